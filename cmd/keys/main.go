@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/bootstrap"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: keys <list|rotate>")
+		fmt.Fprintln(flag.CommandLine.Output(), "  list    show the signing keys and their state")
+		fmt.Fprintln(flag.CommandLine.Output(), "  rotate  add a key that starts signing after the refresh interval")
+	}
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	appLogger, err := logger.New(&logger.Config{
+		Level:       cfg.Server.LogLevel,
+		Environment: cfg.Server.Environment,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := bootstrap.NewDatabase(ctx, cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	store, err := bootstrap.NewKeyStore(cfg.SigningKeys, db, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create key store: %v", err)
+	}
+
+	switch flag.Arg(0) {
+	case "list":
+		if err := store.Reload(ctx); err != nil {
+			log.Fatalf("Failed to load signing keys: %v", err)
+		}
+
+	case "rotate":
+		key, err := store.Rotate(ctx)
+		if err != nil {
+			log.Fatalf("Rotation failed: %v", err)
+		}
+		log.Printf("Created key %s (%s), signing from %s", key.ID, key.Algorithm, key.ActivatesAt.Format(time.RFC3339))
+		if err := store.Reload(ctx); err != nil {
+			log.Fatalf("Failed to load signing keys: %v", err)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	printKeys(store.Keys())
+}
+
+func printKeys(keys []keystore.KeyInfo) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALGORITHM\tSIGNS FROM\tSTATUS")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.ID, key.Algorithm, key.ActivatesAt.Format(time.RFC3339), key.Status)
+	}
+	w.Flush()
+}
@@ -0,0 +1,16 @@
+package event
+
+import "time"
+
+const OIDCBackchannelLogout = "oidc.backchannel_logout"
+
+// BackchannelLogoutEvent is the outbox payload asking for a client to be
+// told, at LogoutURI, that the user's session there has ended. The logout
+// token is signed at delivery, so a retry never sends an expired one.
+type BackchannelLogoutEvent struct {
+	ClientID   string    `json:"client_id"`
+	UserID     string    `json:"user_id"`
+	SessionID  string    `json:"session_id"`
+	LogoutURI  string    `json:"logout_uri"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,19 @@
+package event
+
+import "time"
+
+const UserPasswordlessRequested = "user.passwordless_requested"
+
+// PasswordlessRequestedEvent is the outbox payload asking for a sign-in
+// link or code to be mailed. It carries the hash of the requesting
+// device's token, never the token itself, and the challenge is created at
+// delivery so the request takes as long for an unknown email.
+type PasswordlessRequestedEvent struct {
+	UserID        string    `json:"user_id"`
+	Method        string    `json:"method"`
+	DeviceHash    string    `json:"device_hash"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	RequestedAt   time.Time `json:"requested_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
@@ -0,0 +1,54 @@
+package event
+
+import "time"
+
+const (
+	UserRegistered     = "user.registered"
+	UserEmailVerified  = "user.email_verified"
+	UserPasswordReset  = "user.password_reset"
+	UserMFAEnabled     = "user.mfa_enabled"
+	UserMFADisabled    = "user.mfa_disabled"
+	UserPasskeyAdded   = "user.passkey_added"
+	UserPasskeyRemoved = "user.passkey_removed"
+
+	// UserPasswordResetRequested asks for a reset link to be mailed. It
+	// changes nothing about the account, so it is not among UserEvents.
+	UserPasswordResetRequested = "user.password_reset_requested"
+)
+
+// UserEvents lists every user event type, e.g. for subscribers that
+// forward all of them.
+var UserEvents = []string{
+	UserRegistered,
+	UserEmailVerified,
+	UserPasswordReset,
+	UserMFAEnabled,
+	UserMFADisabled,
+	UserPasskeyAdded,
+	UserPasskeyRemoved,
+}
+
+// UserEvent is the outbox payload for changes to a user account.
+type UserEvent struct {
+	UserID        string                 `json:"user_id"`
+	IPAddress     string                 `json:"ip_address,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+}
+
+func NewUserEvent(userID, ipAddress, correlationID string, details map[string]interface{}) UserEvent {
+	return UserEvent{
+		UserID:        userID,
+		IPAddress:     ipAddress,
+		CorrelationID: correlationID,
+		OccurredAt:    time.Now().UTC(),
+		Details:       details,
+	}
+}
+
+// DedupKey builds the outbox deduplication key for an event about a
+// specific subject, e.g. the user or token the event was raised for.
+func DedupKey(eventType, subjectID string) string {
+	return eventType + ":" + subjectID
+}
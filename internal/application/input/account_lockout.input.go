@@ -0,0 +1,6 @@
+package input
+
+type UnlockAccountInput struct {
+	UserID    string
+	IPAddress string
+}
@@ -0,0 +1,11 @@
+package input
+
+type VerifyEmailInput struct {
+	Token     string
+	IPAddress string
+}
+
+type ResendVerificationInput struct {
+	Email     string
+	IPAddress string
+}
@@ -0,0 +1,17 @@
+package input
+
+type StartFederatedLoginInput struct {
+	Provider  string
+	IPAddress string
+}
+
+// CompleteFederatedLoginInput carries what the provider sent the browser
+// back with, either a code or an error, and the device token returned
+// when the sign-in was started.
+type CompleteFederatedLoginInput struct {
+	DeviceToken string
+	State       string
+	Code        string
+	Error       string
+	IPAddress   string
+}
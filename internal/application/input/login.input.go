@@ -0,0 +1,11 @@
+package input
+
+type LoginInput struct {
+	Identifier string
+	Password   string
+	IPAddress  string
+	// VerifyOnly checks the credentials and starts any MFA challenge
+	// without issuing a session, for a sign-in that only authorizes an
+	// OAuth client.
+	VerifyOnly bool
+}
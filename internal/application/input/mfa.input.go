@@ -0,0 +1,31 @@
+package input
+
+type SetupTOTPInput struct {
+	UserID    string
+	IPAddress string
+}
+
+type ConfirmTOTPInput struct {
+	UserID    string
+	Code      string
+	IPAddress string
+}
+
+type DisableTOTPInput struct {
+	UserID    string
+	Password  string
+	Code      string
+	IPAddress string
+}
+
+// VerifyMFAChallengeInput completes a login with either Passkey or Code,
+// which is a six digit authenticator code or a recovery code.
+type VerifyMFAChallengeInput struct {
+	MFAToken  string
+	Code      string
+	Passkey   *PasskeyAssertion
+	IPAddress string
+	// VerifyOnly checks the second factor without issuing a session, as
+	// for LoginInput.
+	VerifyOnly bool
+}
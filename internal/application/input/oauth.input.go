@@ -0,0 +1,192 @@
+package input
+
+import "time"
+
+// AuthorizationRequest holds the parameters of an OAuth authorization
+// request exactly as the client sent them.
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Prompt              string
+	// RequestObject and RequestURI are the OpenID Connect request and
+	// request_uri parameters, which are not supported.
+	RequestObject string
+	RequestURI    string
+}
+
+// AuthorizeInput issues an authorization code to UserID, who has just
+// signed in to approve Request.
+type AuthorizeInput struct {
+	Request   AuthorizationRequest
+	UserID    string
+	IPAddress string
+}
+
+// ClientCredentials are what a client presented at an OAuth endpoint to
+// authenticate: its ID and secret, or a signed assertion, which may name
+// the client in place of ClientID.
+type ClientCredentials struct {
+	ClientID      string
+	ClientSecret  string
+	AssertionType string
+	Assertion     string
+}
+
+// OAuthTokenInput is a token endpoint request. ClientSecret is empty for
+// public clients and for clients that authenticate with a ClientAssertion,
+// which may also leave ClientID empty. The Subject, Actor, RequestedTokenType
+// and Audiences fields belong to token exchange, where Audiences holds both
+// the audience and the resource parameters.
+type OAuthTokenInput struct {
+	GrantType           string
+	ClientID            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+	Code                string
+	RedirectURI         string
+	CodeVerifier        string
+	RefreshToken        string
+	DeviceCode          string
+	SubjectToken        string
+	SubjectTokenType    string
+	ActorToken          string
+	ActorTokenType      string
+	RequestedTokenType  string
+	Audiences           []string
+	Scope               string
+	IPAddress           string
+}
+
+// UserInfoInput identifies the user and the scopes granted by the access
+// token presented at the userinfo endpoint.
+type UserInfoInput struct {
+	UserID string
+	Scopes []string
+}
+
+// CreateOAuthClientInput registers a client. A client with a JWKS is
+// confidential and authenticates with private_key_jwt; a secret is
+// generated for other confidential clients. AuthMethod, when set, is the
+// token endpoint auth method the client asked for and must agree with
+// Confidential and JWKS. A zero AccessTokenTTL keeps the default lifetime.
+// ExchangeAudiences are only allowed, and required, with the token
+// exchange grant. Only admins register FirstParty clients, which skip the
+// consent page. The logout URIs need a grant that signs users in.
+type CreateOAuthClientInput struct {
+	Name              string
+	RedirectURIs      []string
+	Scopes            []string
+	GrantTypes        []string
+	ExchangeAudiences []string
+	AuthMethod        string
+	Confidential      bool
+	JWKS              string
+	AccessTokenTTL    time.Duration
+	FirstParty        bool
+	IPAddress         string
+
+	PostLogoutRedirectURIs []string
+	BackchannelLogoutURI   string
+	FrontchannelLogoutURI  string
+}
+
+// ClientRegistrationInput names a client managing its own registration
+// with the registration access token it was given (RFC 7592).
+type ClientRegistrationInput struct {
+	ClientID          string
+	RegistrationToken string
+	IPAddress         string
+}
+
+// UpdateClientRegistrationInput replaces all of a client's metadata with
+// Client, as RFC 7592 section 2.2 asks; fields left out are reset. The
+// token endpoint auth method cannot change.
+type UpdateClientRegistrationInput struct {
+	ClientID          string
+	RegistrationToken string
+	Client            CreateOAuthClientInput
+}
+
+// IntrospectTokenInput asks, on behalf of an authenticated client, whether
+// Token is active. TokenTypeHint, when set, names the kind of token to
+// look for first.
+type IntrospectTokenInput struct {
+	Client        ClientCredentials
+	Token         string
+	TokenTypeHint string
+}
+
+// RevokeTokenInput revokes one of the client's own tokens.
+type RevokeTokenInput struct {
+	Client        ClientCredentials
+	Token         string
+	TokenTypeHint string
+	IPAddress     string
+}
+
+// StartDeviceAuthorizationInput begins a device authorization grant for a
+// client that cannot take the user through a browser itself.
+type StartDeviceAuthorizationInput struct {
+	Client    ClientCredentials
+	Scope     string
+	IPAddress string
+}
+
+// LookupDeviceAuthorizationInput is the user code a signed-in user typed
+// on the verification page.
+type LookupDeviceAuthorizationInput struct {
+	UserCode string
+}
+
+// DecideDeviceAuthorizationInput approves or denies the device request
+// with UserCode on behalf of UserID.
+type DecideDeviceAuthorizationInput struct {
+	UserID    string
+	UserCode  string
+	Approve   bool
+	IPAddress string
+}
+
+// LookupConsentInput is the consent token the consent page was opened
+// with.
+type LookupConsentInput struct {
+	ConsentToken string
+}
+
+// DecideConsentInput approves or denies the authorization request waiting
+// on the consent page.
+type DecideConsentInput struct {
+	ConsentToken string
+	Approve      bool
+	IPAddress    string
+}
+
+type ListConsentsInput struct {
+	UserID string
+}
+
+// RevokeConsentInput withdraws what UserID granted ClientID, along with
+// the refresh tokens the client holds for them.
+type RevokeConsentInput struct {
+	UserID    string
+	ClientID  string
+	IPAddress string
+}
+
+// EndSessionInput is an RP-initiated logout request (OpenID Connect
+// RP-Initiated Logout 1.0 section 2). IDTokenHint names the user and
+// client; ClientID, when sent, must agree with it.
+type EndSessionInput struct {
+	IDTokenHint           string
+	ClientID              string
+	PostLogoutRedirectURI string
+	State                 string
+	IPAddress             string
+}
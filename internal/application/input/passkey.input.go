@@ -0,0 +1,51 @@
+package input
+
+// PasskeyAssertion is the browser's answer to an assertion ceremony.
+type PasskeyAssertion struct {
+	SessionID         string
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+type BeginPasskeyRegistrationInput struct {
+	UserID string
+}
+
+type FinishPasskeyRegistrationInput struct {
+	UserID            string
+	SessionID         string
+	Name              string
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+	IPAddress         string
+}
+
+type ListPasskeysInput struct {
+	UserID string
+}
+
+type DeletePasskeyInput struct {
+	UserID    string
+	PasskeyID string
+	Password  string
+	IPAddress string
+}
+
+// BeginPasskeyLoginInput starts a passwordless login. Without an
+// identifier any discoverable passkey may answer.
+type BeginPasskeyLoginInput struct {
+	Identifier string
+}
+
+type FinishPasskeyLoginInput struct {
+	Assertion PasskeyAssertion
+	IPAddress string
+}
+
+type BeginMFAPasskeyInput struct {
+	MFAToken string
+}
@@ -0,0 +1,12 @@
+package input
+
+type ForgotPasswordInput struct {
+	Email     string
+	IPAddress string
+}
+
+type ResetPasswordInput struct {
+	Token     string
+	Password  string
+	IPAddress string
+}
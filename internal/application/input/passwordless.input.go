@@ -0,0 +1,16 @@
+package input
+
+type StartPasswordlessInput struct {
+	Email     string
+	Method    string
+	IPAddress string
+}
+
+// VerifyPasswordlessInput carries the device token returned when the
+// sign-in was started and either the link token or the code.
+type VerifyPasswordlessInput struct {
+	DeviceToken string
+	Token       string
+	Code        string
+	IPAddress   string
+}
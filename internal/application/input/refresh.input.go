@@ -0,0 +1,16 @@
+package input
+
+import "time"
+
+// RefreshInput redeems a refresh token. ClientID is the authenticated OAuth
+// client redeeming it, or empty for a first-party session; a token only
+// refreshes for the client it was issued to. Scopes, when set, must be
+// within the original grant and narrow the new access token. AccessTokenTTL is the client's own access
+// token lifetime, if it has one.
+type RefreshInput struct {
+	RefreshToken   string
+	ClientID       string
+	Scopes         []string
+	AccessTokenTTL time.Duration
+	IPAddress      string
+}
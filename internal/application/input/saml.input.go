@@ -0,0 +1,23 @@
+package input
+
+type StartSAMLLoginInput struct {
+	IdP       string
+	IPAddress string
+}
+
+// ConsumeSAMLResponseInput is what the identity provider posted to the
+// assertion consumer service.
+type ConsumeSAMLResponseInput struct {
+	IdP          string
+	SAMLResponse string
+	IPAddress    string
+}
+
+// RedeemSAMLLoginInput carries the code the assertion consumer service
+// sent the browser on with, and the device token returned when the
+// sign-in was started, if it was.
+type RedeemSAMLLoginInput struct {
+	Code        string
+	DeviceToken string
+	IPAddress   string
+}
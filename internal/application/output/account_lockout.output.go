@@ -0,0 +1,7 @@
+package output
+
+type UnlockAccountOutput struct {
+	UserID    string
+	WasLocked bool
+	Message   string
+}
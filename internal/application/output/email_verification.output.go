@@ -0,0 +1,10 @@
+package output
+
+type VerifyEmailOutput struct {
+	UserID  string
+	Message string
+}
+
+type ResendVerificationOutput struct {
+	Message string
+}
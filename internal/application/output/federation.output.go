@@ -0,0 +1,17 @@
+package output
+
+import "time"
+
+type IdentityProviderOutput struct {
+	ID   string
+	Name string
+}
+
+// StartFederatedLoginOutput sends the browser to the provider. DeviceToken
+// must be presented again with the callback, which ties the sign-in to
+// the client that started it.
+type StartFederatedLoginOutput struct {
+	AuthorizationURL string
+	DeviceToken      string
+	ExpiresAt        time.Time
+}
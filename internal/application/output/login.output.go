@@ -0,0 +1,18 @@
+package output
+
+import "time"
+
+// LoginOutput carries either a session or, when MFARequired is set, the
+// challenge to complete with a second factor.
+type LoginOutput struct {
+	UserID       string
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresAt    time.Time
+
+	MFARequired  bool
+	MFAToken     string
+	MFAMethods   []string
+	MFAExpiresAt time.Time
+}
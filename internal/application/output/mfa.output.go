@@ -0,0 +1,16 @@
+package output
+
+type SetupTOTPOutput struct {
+	Secret          string
+	ProvisioningURI string
+	QRCodePNG       []byte
+}
+
+type ConfirmTOTPOutput struct {
+	RecoveryCodes []string
+	Message       string
+}
+
+type DisableTOTPOutput struct {
+	Message string
+}
@@ -0,0 +1,150 @@
+package output
+
+import (
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type AuthorizationRequestOutput struct {
+	ClientID    string
+	ClientName  string
+	RedirectURI string
+	Scopes      []string
+	State       string
+}
+
+// AuthorizeOutput is what to send back to the client's RedirectURI. When
+// the user has yet to consent, ConsentToken is set instead of Code and
+// opens the consent page. Denied is set when the user refused there.
+type AuthorizeOutput struct {
+	RedirectURI  string
+	Code         string
+	State        string
+	ConsentToken string
+	Denied       bool
+}
+
+// OAuthTokenOutput has no RefreshToken unless the client may refresh,
+// and no IDToken unless a user granted the openid scope. IssuedTokenType
+// is only set by token exchange.
+type OAuthTokenOutput struct {
+	AccessToken     string
+	TokenType       string
+	IssuedTokenType string
+	ExpiresAt       time.Time
+	RefreshToken    string
+	IDToken         string
+	Scopes          []string
+}
+
+// UserInfoOutput holds the claims about the user released to the client.
+type UserInfoOutput struct {
+	Claims map[string]interface{}
+}
+
+// OAuthClientOutput is a client's registered metadata, without any of its
+// credentials.
+type OAuthClientOutput struct {
+	ClientID          string
+	Name              string
+	RedirectURIs      []string
+	Scopes            []string
+	GrantTypes        []string
+	ExchangeAudiences []string
+	AuthMethod        string
+	AccessTokenTTL    time.Duration
+	FirstParty        bool
+	CreatedAt         time.Time
+
+	PostLogoutRedirectURIs []string
+	BackchannelLogoutURI   string
+	FrontchannelLogoutURI  string
+}
+
+// CreateOAuthClientOutput carries the client secret and registration
+// access token, which are only ever shown here.
+type CreateOAuthClientOutput struct {
+	OAuthClientOutput
+	ClientSecret      string
+	RegistrationToken string
+}
+
+// IntrospectTokenOutput describes a token as RFC 7662 does. Only Active is
+// set for a token that is unknown, expired, revoked or not the client's
+// to inspect. TokenType is Bearer for access tokens and empty for refresh
+// tokens. Actor is the delegation chain of an exchanged token.
+type IntrospectTokenOutput struct {
+	Active    bool
+	TokenType string
+	ClientID  string
+	UserID    string
+	Username  string
+	Scopes    []string
+	Audience  []string
+	Actor     *entity.TokenActor
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// StartDeviceAuthorizationOutput is shown to the device: it displays
+// UserCode and VerificationURI to the user and polls with DeviceCode,
+// waiting Interval between requests.
+type StartDeviceAuthorizationOutput struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresAt               time.Time
+	Interval                time.Duration
+}
+
+// DeviceAuthorizationOutput describes a pending device request to the
+// user asked to approve it.
+type DeviceAuthorizationOutput struct {
+	ClientID   string
+	ClientName string
+	Scopes     []string
+	ExpiresAt  time.Time
+}
+
+// ConsentRequestOutput is what the consent page asks the user to approve.
+// GrantedScopes are those of Scopes the user already granted the client.
+type ConsentRequestOutput struct {
+	ClientID      string
+	ClientName    string
+	Scopes        []string
+	GrantedScopes []string
+	ExpiresAt     time.Time
+}
+
+// ConsentOutput is a client the user has granted access to.
+type ConsentOutput struct {
+	ClientID   string
+	ClientName string
+	Scopes     []string
+	GrantedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ListConsentsOutput struct {
+	Consents []ConsentOutput
+}
+
+// EndSessionOutput is where the logout page sends the browser. Each of
+// FrontchannelLogouts is loaded in a frame first, so the clients it names
+// can clear their own sessions. PostLogoutRedirectURI is empty when the
+// client did not ask to get the browser back.
+type EndSessionOutput struct {
+	PostLogoutRedirectURI string
+	State                 string
+	FrontchannelLogouts   []FrontchannelLogout
+}
+
+// FrontchannelLogout is a client's front-channel logout URI and the
+// session it is told about.
+type FrontchannelLogout struct {
+	URI       string
+	SessionID string
+}
@@ -0,0 +1,43 @@
+package output
+
+import "time"
+
+// PasskeyOptionsOutput describes a started WebAuthn ceremony. The user
+// fields are only set for registrations.
+type PasskeyOptionsOutput struct {
+	SessionID     string
+	Challenge     []byte
+	RPID          string
+	RPName        string
+	Algorithms    []int
+	CredentialIDs [][]byte
+	ExpiresAt     time.Time
+
+	UserHandle      []byte
+	UserName        string
+	UserDisplayName string
+}
+
+type PasskeyOutput struct {
+	ID             string
+	Name           string
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+	BackupEligible bool
+	BackupState    bool
+	Transports     []string
+}
+
+type FinishPasskeyRegistrationOutput struct {
+	Passkey       PasskeyOutput
+	RecoveryCodes []string
+	Message       string
+}
+
+type ListPasskeysOutput struct {
+	Passkeys []PasskeyOutput
+}
+
+type DeletePasskeyOutput struct {
+	Message string
+}
@@ -0,0 +1,9 @@
+package output
+
+type ForgotPasswordOutput struct {
+	Message string
+}
+
+type ResetPasswordOutput struct {
+	Message string
+}
@@ -0,0 +1,13 @@
+package output
+
+import "time"
+
+// StartPasswordlessOutput is returned whether or not the email belongs to
+// an account. DeviceToken must be presented again to verify, which ties
+// the sign-in to the client that started it.
+type StartPasswordlessOutput struct {
+	DeviceToken string
+	Method      string
+	ExpiresAt   time.Time
+	Message     string
+}
@@ -0,0 +1,12 @@
+package output
+
+import "time"
+
+type RefreshOutput struct {
+	UserID       string
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresAt    time.Time
+	Scopes       []string
+}
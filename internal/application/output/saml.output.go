@@ -0,0 +1,20 @@
+package output
+
+import "time"
+
+// StartSAMLLoginOutput is the authentication request the browser posts to
+// the identity provider. DeviceToken must be presented again when the
+// sign-in is redeemed, which ties it to the client that started it.
+type StartSAMLLoginOutput struct {
+	SSOURL      string
+	SAMLRequest string
+	DeviceToken string
+	ExpiresAt   time.Time
+}
+
+// ConsumeSAMLResponseOutput carries the code the client redeems for
+// tokens.
+type ConsumeSAMLResponseOutput struct {
+	Code      string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,47 @@
+package port
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// DirectoryAccount is an account whose password a directory has
+// verified, as the directory describes it.
+type DirectoryAccount struct {
+	// Subject is the directory's stable identifier for the account, which
+	// survives renames.
+	Subject  string
+	Username string
+	// Email is empty when the directory has none for the account.
+	Email string
+	// Roles are mapped from the account's group memberships.
+	Roles []string
+}
+
+// CredentialVerifier checks usernames and passwords against an external
+// directory such as LDAP or Active Directory.
+type CredentialVerifier interface {
+	// ID names the directory as the provider of the identities it
+	// verifies.
+	ID() string
+	// LinksByEmail reports whether a directory account signs in to the
+	// existing local account with the same email.
+	LinksByEmail() bool
+	// Verify returns the account the credentials belong to, or nil when
+	// the directory rejects them. An error means the directory could not
+	// give an answer.
+	Verify(ctx context.Context, username, password string) (*DirectoryAccount, error)
+}
+
+// DirectoryAccounts keeps local shadow users in step with the directory
+// accounts they stand for.
+type DirectoryAccounts interface {
+	// IsLinked reports whether the user is the shadow of an account in the
+	// directory, which then owns their password.
+	IsLinked(ctx context.Context, provider string, user *entity.User) (bool, error)
+	// Synchronize resolves a verified account to its shadow user as
+	// ExternalAccounts does, then updates the user's username, email and
+	// roles from it.
+	Synchronize(ctx context.Context, provider ExternalProvider, account *DirectoryAccount, ipAddress string) (*entity.User, error)
+}
@@ -0,0 +1,54 @@
+package port
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// ExternalIdentity is who an upstream identity provider says signed in,
+// read from an ID token or SAML assertion whose signature and conditions
+// have been checked.
+type ExternalIdentity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// IdentityProvider is an upstream OpenID provider users sign in with, as
+// the relying party of the authorization code flow with PKCE.
+type IdentityProvider interface {
+	ID() string
+	Name() string
+	// LinksByEmail reports whether a verified email from this provider
+	// signs in to the existing account with that email. Only providers
+	// trusted to vouch for the addresses they issue should.
+	LinksByEmail() bool
+	// AuthorizationURL returns where to send the browser to sign in.
+	AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the code the provider sent the browser back with
+	// and returns the identity in its ID token, once the token's
+	// signature, issuer, audience, expiry and nonce check out.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+// IdentityProviders is the registry of configured upstream providers.
+type IdentityProviders interface {
+	// Find returns the provider with the given ID, or nil.
+	Find(id string) IdentityProvider
+	List() []IdentityProvider
+}
+
+// ExternalProvider names the upstream provider an identity comes from.
+type ExternalProvider struct {
+	ID          string
+	LinkByEmail bool
+}
+
+// ExternalAccounts maps identities from upstream providers to accounts.
+type ExternalAccounts interface {
+	// Resolve returns the account the identity signs in to, linking or
+	// provisioning one the first time the identity is seen.
+	Resolve(ctx context.Context, provider ExternalProvider, external *ExternalIdentity, ipAddress string) (*entity.User, error)
+}
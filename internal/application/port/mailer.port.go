@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+type MailMessage struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
+
+type SentMail struct {
+	ID     string
+	SentAt time.Time
+	MailMessage
+}
+
+// Mailbox exposes mail captured by a non-delivering Mailer for inspection.
+type Mailbox interface {
+	List() []SentMail
+	Clear()
+}
@@ -0,0 +1,18 @@
+package port
+
+const (
+	LoginStatusSuccess            = "success"
+	LoginStatusInvalidCredentials = "invalid_credentials"
+	LoginStatusInactive           = "inactive"
+	LoginStatusEmailUnverified    = "email_unverified"
+	LoginStatusLocked             = "locked"
+	LoginStatusMFARequired        = "mfa_required"
+	LoginStatusMFAFailed          = "mfa_failed"
+	LoginStatusError              = "error"
+)
+
+type AuthMetrics interface {
+	RecordLoginAttempt(status string)
+	RecordPasswordReset()
+	RecordAccountLockout()
+}
@@ -0,0 +1,69 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+const (
+	MFAMethodTOTP         = "totp"
+	MFAMethodRecoveryCode = "recovery_code"
+)
+
+// TOTP generates and checks RFC 6238 time-based one-time passwords.
+type TOTP interface {
+	GenerateSecret() (string, error)
+	// ProvisioningURI returns the otpauth:// URI authenticator apps
+	// import, usually from a QR code.
+	ProvisioningURI(secret, accountName string) string
+	// Validate reports whether code is valid around at, and the time step
+	// it was generated for.
+	Validate(secret, code string, at time.Time) (step int64, ok bool)
+}
+
+type RecoveryCodeGenerator interface {
+	Generate(count int) ([]string, error)
+}
+
+// SecretBox encrypts secrets that must be stored recoverably.
+type SecretBox interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+type QRCodeEncoder interface {
+	EncodePNG(content string) ([]byte, error)
+}
+
+type MFAChallengeTicket struct {
+	Token     string
+	ExpiresAt time.Time
+	Methods   []string
+}
+
+// MFAChallenger starts the second login step for users with a confirmed
+// second factor.
+type MFAChallenger interface {
+	// BeginChallenge returns nil when the user has no second factor.
+	BeginChallenge(ctx context.Context, user *entity.User) (*MFAChallengeTicket, error)
+}
+
+// MFAVerifier checks a code from the user's authenticator app or one of
+// their recovery codes. Accepted codes are consumed.
+type MFAVerifier interface {
+	VerifyCode(ctx context.Context, userID, code string) (method string, err error)
+}
+
+type MFARecoveryCodeIssuer interface {
+	// IssueRecoveryCodes replaces the user's recovery codes and returns
+	// the new ones. Only their hashes are kept.
+	IssueRecoveryCodes(ctx context.Context, userID string) ([]string, error)
+}
+
+type MFAEnrollment interface {
+	// HasSecondFactor reports whether the user has a confirmed
+	// authenticator app or a passkey.
+	HasSecondFactor(ctx context.Context, userID string) (bool, error)
+}
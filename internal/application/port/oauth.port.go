@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// ClientAssertion holds the claims of a verified private_key_jwt client
+// assertion (RFC 7523). The caller still checks who issued it, for whom
+// and that its ID has not been used before.
+type ClientAssertion struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ID        string
+	ExpiresAt time.Time
+}
+
+// ClientAssertionVerifier checks JWTs a client signs with a key from the
+// JWK Set it registered.
+type ClientAssertionVerifier interface {
+	// ValidateKeySet reports why jwks cannot verify assertions, if it
+	// cannot.
+	ValidateKeySet(jwks string) error
+	// Subject returns the unverified sub claim, which names the client
+	// whose keys the assertion must be verified with.
+	Subject(assertion string) (string, error)
+	Verify(assertion, jwks string) (*ClientAssertion, error)
+}
+
+type ClientAuthenticator interface {
+	// Authenticate returns the client the credentials identify, or an
+	// OAuthError with invalid_client when they identify none.
+	Authenticate(ctx context.Context, credentials input.ClientCredentials) (*entity.OAuthClient, error)
+}
@@ -0,0 +1,68 @@
+package port
+
+import "time"
+
+// IDTokenClaims are the claims of an ID token apart from those the signer
+// sets itself: iss, iat and exp. AccessToken, when set, is bound to the ID
+// token through at_hash.
+type IDTokenClaims struct {
+	Subject     string
+	Audience    string
+	Nonce       string
+	AuthTime    time.Time
+	AccessToken string
+	// SessionID is the sid claim, naming the OAuthSession the token
+	// belongs to.
+	SessionID  string
+	UserClaims map[string]interface{}
+}
+
+type IDTokenSigner interface {
+	SignIDToken(claims IDTokenClaims) (string, error)
+}
+
+// IDTokenHint is what an ID token this service issued says about the user
+// and session it was issued for.
+type IDTokenHint struct {
+	Subject   string
+	Audience  string
+	SessionID string
+}
+
+// IDTokenVerifier checks an ID token a client sends back as id_token_hint.
+// Expired tokens are accepted, since clients usually log a user out long
+// after the ID token they hold expired.
+type IDTokenVerifier interface {
+	VerifyIDTokenHint(token string) (*IDTokenHint, error)
+}
+
+// LogoutTokenClaims describe the session a back-channel logout ends.
+type LogoutTokenClaims struct {
+	Subject   string
+	Audience  string
+	SessionID string
+}
+
+// LogoutTokenSigner signs the logout tokens of OpenID Connect Back-Channel
+// Logout.
+type LogoutTokenSigner interface {
+	SignLogoutToken(claims LogoutTokenClaims) (string, error)
+}
+
+// JSONWebKey is a public key as published in the JWKS.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// KeySet lists the public keys relying parties verify ID tokens with.
+type KeySet interface {
+	PublicKeys() []JSONWebKey
+}
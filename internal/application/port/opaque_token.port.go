@@ -0,0 +1,8 @@
+package port
+
+// OpaqueTokenGenerator creates random bearer secrets that are persisted
+// only as hashes.
+type OpaqueTokenGenerator interface {
+	Generate() (string, error)
+	Hash(token string) string
+}
@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxMessage struct {
+	EventType string
+	// DedupKey identifies the logical event. Publishing the same key twice
+	// is a no-op, and subscribers receive it to deduplicate on their side.
+	DedupKey string
+	Payload  any
+}
+
+// Outbox records messages for asynchronous delivery. Publish must be called
+// with the transactional context of the write it belongs to.
+type Outbox interface {
+	Publish(ctx context.Context, msg OutboxMessage) error
+}
+
+type OutboxDelivery struct {
+	ID        string
+	EventType string
+	DedupKey  string
+	Payload   json.RawMessage
+	Attempt   int
+}
+
+// OutboxSubscriber handles delivered messages. Delivery is at-least-once,
+// so implementations must be idempotent.
+type OutboxSubscriber interface {
+	Name() string
+	Handle(ctx context.Context, delivery OutboxDelivery) error
+}
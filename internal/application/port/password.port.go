@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+)
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encodedHash string) (bool, error)
+}
+
+// PasswordValidator applies the password policy to a new password.
+// personalInfo holds the account's username and email address.
+type PasswordValidator interface {
+	Validate(ctx context.Context, password string, personalInfo ...string) (vo.Password, error)
+}
+
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+type PasswordResetSender interface {
+	// Send issues a fresh password reset token for the user, invalidating
+	// any earlier ones, and mails the reset link.
+	Send(ctx context.Context, user *entity.User) error
+}
+
+// LoginFailureCounter counts recent failed logins per identifier, whether
+// or not an account has it, so slowing down repeated failures does not
+// reveal which accounts exist.
+type LoginFailureCounter interface {
+	// Add counts a failure and returns the number of recent failures,
+	// including this one.
+	Add(identifier string, now time.Time) int
+	Reset(identifier string)
+}
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// LinkSigner authenticates values embedded in links sent by email, so a
+// link cannot be forged or altered.
+type LinkSigner interface {
+	Sign(payload string) string
+	// Verify returns the payload of a token created by Sign.
+	Verify(token string) (string, error)
+}
+
+// OneTimeCodeGenerator creates short numeric codes users type in.
+type OneTimeCodeGenerator interface {
+	Generate() (string, error)
+}
+
+// PasswordlessRequest describes a sign-in email asked for by the device
+// whose token hashes to DeviceHash.
+type PasswordlessRequest struct {
+	Method      entity.PasswordlessMethod
+	DeviceHash  string
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+}
+
+type PasswordlessSender interface {
+	// Send creates the sign-in challenge for the request, invalidating
+	// earlier ones, and mails the link or code. It does nothing for a
+	// request that has expired, was already handled, or came within the
+	// resend interval of the previous email.
+	Send(ctx context.Context, user *entity.User, request PasswordlessRequest) error
+}
@@ -0,0 +1,51 @@
+package port
+
+import "time"
+
+// SAMLAuthnRequest is an authentication request for the browser to post
+// to an identity provider with the HTTP-POST binding.
+type SAMLAuthnRequest struct {
+	// URL is the identity provider's single sign-on service.
+	URL string
+	// SAMLRequest is the base64 encoded request, posted as the form field
+	// of the same name.
+	SAMLRequest string
+}
+
+// SAMLAssertion is a bearer assertion from a response whose signature,
+// issuer, audience, recipient and validity window have been checked.
+type SAMLAssertion struct {
+	ID string
+	// InResponseTo is the ID of the request the assertion answers, empty
+	// for an IdP-initiated sign-in.
+	InResponseTo string
+	// ExpiresAt is when the assertion stops being accepted, so until when
+	// its ID must be remembered to refuse replays.
+	ExpiresAt time.Time
+	Identity  ExternalIdentity
+}
+
+// SAMLIdentityProvider is an upstream SAML 2.0 identity provider, with
+// this service as its service provider.
+type SAMLIdentityProvider interface {
+	ID() string
+	// LinksByEmail reports whether an email asserted by this provider
+	// signs in to the existing account with that email.
+	LinksByEmail() bool
+	// AllowsIdPInitiated reports whether responses that answer no request
+	// of ours are accepted.
+	AllowsIdPInitiated() bool
+	AuthnRequest(id string, issuedAt time.Time) (*SAMLAuthnRequest, error)
+	// ParseResponse decodes a response posted to the assertion consumer
+	// service and returns its assertion once it checks out at now.
+	ParseResponse(samlResponse string, now time.Time) (*SAMLAssertion, error)
+	// Metadata returns the service provider metadata to register with the
+	// identity provider.
+	Metadata() []byte
+}
+
+// SAMLIdentityProviders is the registry of configured SAML providers.
+type SAMLIdentityProviders interface {
+	// Find returns the provider with the given ID, or nil.
+	Find(id string) SAMLIdentityProvider
+}
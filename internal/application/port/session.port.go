@@ -0,0 +1,76 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type Session struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+	FamilyID              string
+}
+
+// ClientGrant is what a user granted an OAuth client. A refresh token is
+// only issued when Refreshable is set; an empty FamilyID starts a new
+// family. A positive AccessTokenTTL is the client's own token lifetime.
+type ClientGrant struct {
+	ClientID string
+	Scopes   []string
+	// AccessTokenScopes, when set, narrows the access token below Scopes;
+	// the refresh token keeps the full grant.
+	AccessTokenScopes []string
+	FamilyID          string
+	Refreshable       bool
+	AccessTokenTTL    time.Duration
+}
+
+type SessionIssuer interface {
+	// Issue creates an access token and a refresh token for the user. An
+	// empty familyID starts a new refresh token family.
+	Issue(ctx context.Context, user *entity.User, familyID string) (*Session, error)
+	// IssueForClient creates tokens bound to an OAuth client and the
+	// scopes it was granted.
+	IssueForClient(ctx context.Context, user *entity.User, grant ClientGrant) (*Session, error)
+}
+
+// LoginAttempt is a sign-in whose credentials checked out. Method and
+// Provider name how the user signed in; Details are added to its audit
+// log. SkipMFA is set when the credential was a second factor itself,
+// such as a passkey. VerifyOnly stops once the account is checked and any
+// MFA challenge started, issuing no session, as when the sign-in only
+// authorizes an OAuth client.
+type LoginAttempt struct {
+	User                 *entity.User
+	Method               string
+	Provider             string
+	Details              map[string]interface{}
+	IPAddress            string
+	SkipMFA              bool
+	RequireVerifiedEmail bool
+	VerifyOnly           bool
+}
+
+// LoginResult holds the session, or the MFA challenge the user must
+// complete first when they have a second factor. Both are nil for a
+// VerifyOnly attempt that needs no challenge.
+type LoginResult struct {
+	Session *Session
+	MFA     *MFAChallengeTicket
+}
+
+// LoginCompleter finishes a sign-in once the credentials are verified.
+type LoginCompleter interface {
+	// Check refuses locked and inactive accounts, and unverified emails
+	// when required, recording the refusal. A locked account is refused
+	// with ErrInvalidCredentials, like a wrong password, so the answer
+	// does not reveal that it exists.
+	Check(ctx context.Context, attempt LoginAttempt) error
+	// Complete checks the account, then starts the MFA challenge or
+	// issues the session and records the sign-in.
+	Complete(ctx context.Context, attempt LoginAttempt) (*LoginResult, error)
+}
@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// AccessTokenClaims identifies the user a token was issued to and the
+// roles they hold. ClientID and Scopes are only set on tokens issued to an
+// OAuth client, and UserID is empty on tokens a client obtained for
+// itself. Audience and Actor are set on tokens obtained by token exchange.
+// When issuing, a positive TTL overrides the default lifetime, a non-zero
+// ExpiresAt caps it and an empty Audience means this service's own; ID and
+// IssuedAt are only set when parsing.
+type AccessTokenClaims struct {
+	UserID    string
+	Username  string
+	Roles     []string
+	ClientID  string
+	Scopes    []string
+	Audience  []string
+	Actor     *entity.TokenActor
+	TTL       time.Duration
+	ID        string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// TokenService issues and verifies access tokens. ParseAccessToken only
+// accepts tokens meant for this service; InspectAccessToken accepts any
+// token it issued, whatever its audience.
+type TokenService interface {
+	GenerateAccessToken(claims AccessTokenClaims) (token string, expiresAt time.Time, err error)
+	ParseAccessToken(token string) (*AccessTokenClaims, error)
+	InspectAccessToken(token string) (*AccessTokenClaims, error)
+}
+
+// AccessTokenRevocations is the list of access tokens revoked before they
+// expire, by jti or all of a user's at once. IsRevoked must be cheap: it
+// runs on every request.
+type AccessTokenRevocations interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUser revokes every access token issued to the user up to now.
+	RevokeUser(ctx context.Context, userID string, now time.Time) error
+	IsRevoked(claims *AccessTokenClaims) bool
+}
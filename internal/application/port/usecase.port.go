@@ -10,3 +10,187 @@ import (
 type RegisterUseCase interface {
 	Execute(ctx context.Context, input input.RegisterInput) (*output.RegisterOutput, error)
 }
+
+type LoginUseCase interface {
+	Execute(ctx context.Context, input input.LoginInput) (*output.LoginOutput, error)
+}
+
+type RefreshUseCase interface {
+	Execute(ctx context.Context, input input.RefreshInput) (*output.RefreshOutput, error)
+}
+
+type ForgotPasswordUseCase interface {
+	Execute(ctx context.Context, input input.ForgotPasswordInput) (*output.ForgotPasswordOutput, error)
+}
+
+type ResetPasswordUseCase interface {
+	Execute(ctx context.Context, input input.ResetPasswordInput) (*output.ResetPasswordOutput, error)
+}
+
+type VerifyEmailUseCase interface {
+	Execute(ctx context.Context, input input.VerifyEmailInput) (*output.VerifyEmailOutput, error)
+}
+
+type ResendVerificationUseCase interface {
+	Execute(ctx context.Context, input input.ResendVerificationInput) (*output.ResendVerificationOutput, error)
+}
+
+type UnlockAccountUseCase interface {
+	Execute(ctx context.Context, input input.UnlockAccountInput) (*output.UnlockAccountOutput, error)
+}
+
+type SetupTOTPUseCase interface {
+	Execute(ctx context.Context, input input.SetupTOTPInput) (*output.SetupTOTPOutput, error)
+}
+
+type ConfirmTOTPUseCase interface {
+	Execute(ctx context.Context, input input.ConfirmTOTPInput) (*output.ConfirmTOTPOutput, error)
+}
+
+type DisableTOTPUseCase interface {
+	Execute(ctx context.Context, input input.DisableTOTPInput) (*output.DisableTOTPOutput, error)
+}
+
+type VerifyMFAChallengeUseCase interface {
+	Execute(ctx context.Context, input input.VerifyMFAChallengeInput) (*output.LoginOutput, error)
+}
+
+type BeginPasskeyRegistrationUseCase interface {
+	Execute(ctx context.Context, input input.BeginPasskeyRegistrationInput) (*output.PasskeyOptionsOutput, error)
+}
+
+type FinishPasskeyRegistrationUseCase interface {
+	Execute(ctx context.Context, input input.FinishPasskeyRegistrationInput) (*output.FinishPasskeyRegistrationOutput, error)
+}
+
+type ListPasskeysUseCase interface {
+	Execute(ctx context.Context, input input.ListPasskeysInput) (*output.ListPasskeysOutput, error)
+}
+
+type DeletePasskeyUseCase interface {
+	Execute(ctx context.Context, input input.DeletePasskeyInput) (*output.DeletePasskeyOutput, error)
+}
+
+type BeginPasskeyLoginUseCase interface {
+	Execute(ctx context.Context, input input.BeginPasskeyLoginInput) (*output.PasskeyOptionsOutput, error)
+}
+
+type FinishPasskeyLoginUseCase interface {
+	Execute(ctx context.Context, input input.FinishPasskeyLoginInput) (*output.LoginOutput, error)
+}
+
+type BeginMFAPasskeyUseCase interface {
+	Execute(ctx context.Context, input input.BeginMFAPasskeyInput) (*output.PasskeyOptionsOutput, error)
+}
+
+type StartPasswordlessUseCase interface {
+	Execute(ctx context.Context, input input.StartPasswordlessInput) (*output.StartPasswordlessOutput, error)
+}
+
+type VerifyPasswordlessUseCase interface {
+	Execute(ctx context.Context, input input.VerifyPasswordlessInput) (*output.LoginOutput, error)
+}
+
+type ListIdentityProvidersUseCase interface {
+	Execute(ctx context.Context) []output.IdentityProviderOutput
+}
+
+type StartFederatedLoginUseCase interface {
+	Execute(ctx context.Context, input input.StartFederatedLoginInput) (*output.StartFederatedLoginOutput, error)
+}
+
+type CompleteFederatedLoginUseCase interface {
+	Execute(ctx context.Context, input input.CompleteFederatedLoginInput) (*output.LoginOutput, error)
+}
+
+type GetSAMLMetadataUseCase interface {
+	Execute(ctx context.Context, idp string) ([]byte, error)
+}
+
+type StartSAMLLoginUseCase interface {
+	Execute(ctx context.Context, input input.StartSAMLLoginInput) (*output.StartSAMLLoginOutput, error)
+}
+
+type ConsumeSAMLResponseUseCase interface {
+	Execute(ctx context.Context, input input.ConsumeSAMLResponseInput) (*output.ConsumeSAMLResponseOutput, error)
+}
+
+type RedeemSAMLLoginUseCase interface {
+	Execute(ctx context.Context, input input.RedeemSAMLLoginInput) (*output.LoginOutput, error)
+}
+
+type ValidateAuthorizationRequestUseCase interface {
+	Execute(ctx context.Context, input input.AuthorizationRequest) (*output.AuthorizationRequestOutput, error)
+}
+
+type AuthorizeUseCase interface {
+	Execute(ctx context.Context, input input.AuthorizeInput) (*output.AuthorizeOutput, error)
+}
+
+type OAuthTokenUseCase interface {
+	Execute(ctx context.Context, input input.OAuthTokenInput) (*output.OAuthTokenOutput, error)
+}
+
+type IntrospectTokenUseCase interface {
+	Execute(ctx context.Context, input input.IntrospectTokenInput) (*output.IntrospectTokenOutput, error)
+}
+
+type RevokeTokenUseCase interface {
+	Execute(ctx context.Context, input input.RevokeTokenInput) error
+}
+
+type StartDeviceAuthorizationUseCase interface {
+	Execute(ctx context.Context, input input.StartDeviceAuthorizationInput) (*output.StartDeviceAuthorizationOutput, error)
+}
+
+type LookupDeviceAuthorizationUseCase interface {
+	Execute(ctx context.Context, input input.LookupDeviceAuthorizationInput) (*output.DeviceAuthorizationOutput, error)
+}
+
+type DecideDeviceAuthorizationUseCase interface {
+	Execute(ctx context.Context, input input.DecideDeviceAuthorizationInput) error
+}
+
+type LookupConsentUseCase interface {
+	Execute(ctx context.Context, input input.LookupConsentInput) (*output.ConsentRequestOutput, error)
+}
+
+type DecideConsentUseCase interface {
+	Execute(ctx context.Context, input input.DecideConsentInput) (*output.AuthorizeOutput, error)
+}
+
+type ListConsentsUseCase interface {
+	Execute(ctx context.Context, input input.ListConsentsInput) (*output.ListConsentsOutput, error)
+}
+
+type RevokeConsentUseCase interface {
+	Execute(ctx context.Context, input input.RevokeConsentInput) error
+}
+
+type EndSessionUseCase interface {
+	Execute(ctx context.Context, input input.EndSessionInput) (*output.EndSessionOutput, error)
+}
+
+type UserInfoUseCase interface {
+	Execute(ctx context.Context, input input.UserInfoInput) (*output.UserInfoOutput, error)
+}
+
+type CreateOAuthClientUseCase interface {
+	Execute(ctx context.Context, input input.CreateOAuthClientInput) (*output.CreateOAuthClientOutput, error)
+}
+
+type ReadClientRegistrationUseCase interface {
+	Execute(ctx context.Context, input input.ClientRegistrationInput) (*output.OAuthClientOutput, error)
+}
+
+type UpdateClientRegistrationUseCase interface {
+	Execute(ctx context.Context, input input.UpdateClientRegistrationInput) (*output.OAuthClientOutput, error)
+}
+
+type DeleteClientRegistrationUseCase interface {
+	Execute(ctx context.Context, input input.ClientRegistrationInput) error
+}
+
+type ListOAuthClientsUseCase interface {
+	Execute(ctx context.Context) ([]output.OAuthClientOutput, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type EmailVerificationSender interface {
+	// Send issues a fresh verification token for the user, invalidating any
+	// earlier ones, and mails the verification link.
+	Send(ctx context.Context, user *entity.User) error
+}
@@ -0,0 +1,89 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+const MFAMethodPasskey = "passkey"
+
+// WebAuthnRegistration is a credential the relying party accepted during
+// a registration ceremony.
+type WebAuthnRegistration struct {
+	CredentialID      []byte
+	PublicKey         []byte
+	Algorithm         int
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	UserVerified      bool
+	BackupEligible    bool
+	BackupState       bool
+}
+
+type WebAuthnAssertion struct {
+	SignCount    uint32
+	UserVerified bool
+	BackupState  bool
+}
+
+// WebAuthnRelyingParty verifies WebAuthn ceremony responses for this
+// service's RP ID and origins.
+type WebAuthnRelyingParty interface {
+	ID() string
+	Name() string
+	Algorithms() []int
+	NewChallenge() ([]byte, error)
+	VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (*WebAuthnRegistration, error)
+	VerifyAssertion(challenge, clientDataJSON, authenticatorData, signature, publicKey []byte, requireUserVerification bool) (*WebAuthnAssertion, error)
+}
+
+// PasskeyCeremony is the server side of a started registration or
+// assertion, handed to the browser as PublicKeyCredential options.
+type PasskeyCeremony struct {
+	SessionID  string
+	Challenge  []byte
+	RPID       string
+	RPName     string
+	Algorithms []int
+	// CredentialIDs are excluded from a registration and allowed for an
+	// assertion.
+	CredentialIDs [][]byte
+	ExpiresAt     time.Time
+}
+
+type PasskeyAttestationResponse struct {
+	SessionID         string
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+}
+
+type PasskeyAssertionResponse struct {
+	SessionID         string
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+// PasskeyRegistrar runs the server side of passkey registration.
+type PasskeyRegistrar interface {
+	BeginRegistration(ctx context.Context, user *entity.User) (*PasskeyCeremony, error)
+	// FinishRegistration consumes the ceremony and stores the new
+	// credential under name.
+	FinishRegistration(ctx context.Context, userID, name string, response PasskeyAttestationResponse) (*entity.WebAuthnCredential, error)
+}
+
+// PasskeyAuthenticator runs the server side of passkey assertions.
+type PasskeyAuthenticator interface {
+	// BeginAssertion starts an assertion limited to the user's passkeys,
+	// or open to any discoverable passkey when userID is empty.
+	BeginAssertion(ctx context.Context, userID string) (*PasskeyCeremony, error)
+	// VerifyAssertion consumes the ceremony and returns the credential
+	// used. When userID is set the credential must belong to that user.
+	VerifyAssertion(ctx context.Context, response PasskeyAssertionResponse, userID string, requireUserVerification bool) (*entity.WebAuthnCredential, error)
+}
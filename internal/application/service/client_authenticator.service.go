@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// ClientAssertionTypeJWTBearer is the client_assertion_type of a
+// private_key_jwt assertion, from RFC 7523 section 2.2.
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// maxClientAssertionLifetime bounds how far ahead a client assertion may
+// expire, and with it how long its ID has to be remembered.
+const maxClientAssertionLifetime = time.Hour
+
+// maxClientAssertionIDLength is the longest jti that is stored.
+const maxClientAssertionIDLength = 255
+
+// ClientAuthenticator authenticates OAuth clients at the token,
+// introspection and revocation endpoints.
+type ClientAuthenticator struct {
+	clientRepo   repository.OAuthClientRepository
+	assertionIDs repository.ClientAssertionRepository
+	opaqueTokens port.OpaqueTokenGenerator
+	assertions   port.ClientAssertionVerifier
+	logger       port.Logger
+
+	// endpoints are the audiences a client assertion may name: the
+	// issuer and the endpoints clients authenticate at.
+	endpoints []string
+}
+
+func NewClientAuthenticator(
+	clientRepo repository.OAuthClientRepository,
+	assertionIDs repository.ClientAssertionRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	assertions port.ClientAssertionVerifier,
+	logger port.Logger,
+	issuer string,
+) *ClientAuthenticator {
+	return &ClientAuthenticator{
+		clientRepo:   clientRepo,
+		assertionIDs: assertionIDs,
+		opaqueTokens: opaqueTokens,
+		assertions:   assertions,
+		logger:       logger,
+		endpoints: []string{
+			issuer,
+			issuer + "/oauth/token",
+			issuer + "/oauth/introspect",
+			issuer + "/oauth/revoke",
+			issuer + "/oauth/device_authorization",
+		},
+	}
+}
+
+// Authenticate accepts a public client by its ID alone and a confidential
+// client only by the method it was registered with: its secret, or an
+// assertion signed with one of its keys.
+func (a *ClientAuthenticator) Authenticate(ctx context.Context, credentials input.ClientCredentials) (*entity.OAuthClient, error) {
+	invalidClient := exception.NewOAuthError(exception.OAuthInvalidClient, "client authentication failed")
+
+	usesAssertion := credentials.AssertionType != "" || credentials.Assertion != ""
+	if usesAssertion && credentials.ClientSecret != "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "client authenticated with more than one method")
+	}
+
+	clientID := credentials.ClientID
+	if usesAssertion && clientID == "" {
+		subject, err := a.assertions.Subject(credentials.Assertion)
+		if err != nil {
+			return nil, invalidClient
+		}
+		clientID = subject
+	}
+	if clientID == "" {
+		return nil, invalidClient
+	}
+
+	client, err := a.clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, invalidClient
+	}
+
+	switch client.AuthMethod() {
+	case entity.OAuthAuthMethodNone:
+		if usesAssertion || credentials.ClientSecret != "" {
+			return nil, invalidClient
+		}
+	case entity.OAuthAuthMethodPrivateKeyJWT:
+		if !usesAssertion {
+			return nil, invalidClient
+		}
+		ok, err := a.verifyAssertion(ctx, client, credentials)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, invalidClient
+		}
+	default:
+		if usesAssertion || credentials.ClientSecret == "" ||
+			subtle.ConstantTimeCompare([]byte(a.opaqueTokens.Hash(credentials.ClientSecret)), []byte(client.SecretHash)) != 1 {
+			return nil, invalidClient
+		}
+	}
+	return client, nil
+}
+
+// verifyAssertion checks a private_key_jwt assertion as RFC 7523 section 3
+// requires. The client names itself as issuer and subject, addresses this
+// server, and may use each assertion only once.
+func (a *ClientAuthenticator) verifyAssertion(ctx context.Context, client *entity.OAuthClient, credentials input.ClientCredentials) (bool, error) {
+	if credentials.AssertionType != ClientAssertionTypeJWTBearer {
+		return false, nil
+	}
+
+	assertion, err := a.assertions.Verify(credentials.Assertion, client.JWKS)
+	if err != nil {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	if assertion.Issuer != client.ID || assertion.Subject != client.ID ||
+		assertion.ID == "" || len(assertion.ID) > maxClientAssertionIDLength ||
+		assertion.ExpiresAt.After(now.Add(maxClientAssertionLifetime)) {
+		return false, nil
+	}
+	if !slices.ContainsFunc(assertion.Audience, func(aud string) bool { return slices.Contains(a.endpoints, aud) }) {
+		return false, nil
+	}
+
+	fresh, err := a.assertionIDs.Use(ctx, client.ID, assertion.ID, assertion.ExpiresAt)
+	if err != nil {
+		return false, err
+	}
+	if !fresh {
+		a.logger.WarnCtx(ctx, "Client assertion replay rejected", "client_id", client.ID)
+	}
+	return fresh, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type EmailVerificationService struct {
+	tokenRepo     repository.EmailVerificationTokenRepository
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	mailer        port.Mailer
+	tokenTTL      time.Duration
+	verifyURL     string
+}
+
+func NewEmailVerificationService(
+	tokenRepo repository.EmailVerificationTokenRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	mailer port.Mailer,
+	tokenTTL time.Duration,
+	verifyURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		tokenRepo:     tokenRepo,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		mailer:        mailer,
+		tokenTTL:      tokenTTL,
+		verifyURL:     verifyURL,
+	}
+}
+
+func (s *EmailVerificationService) Send(ctx context.Context, user *entity.User) error {
+	now := time.Now().UTC()
+
+	if err := s.tokenRepo.InvalidateByUserID(ctx, user.ID.String(), now); err != nil {
+		return err
+	}
+
+	rawToken, err := s.opaqueTokens.Generate()
+	if err != nil {
+		return err
+	}
+
+	token := entity.NewEmailVerificationToken(
+		s.uuidGenerator.Generate(),
+		user.ID.String(),
+		s.opaqueTokens.Hash(rawToken),
+		now.Add(s.tokenTTL),
+	)
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.verifyURL, rawToken)
+
+	return s.mailer.Send(ctx, port.MailMessage{
+		To:      user.Email.String(),
+		Subject: "Verify your email address",
+		TextBody: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address using the link below. It expires in %d hours.\n\n%s\n",
+			user.Username.String(),
+			int(s.tokenTTL.Hours()),
+			link,
+		),
+	})
+}
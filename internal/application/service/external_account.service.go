@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type ExternalAccountService struct {
+	identityRepo  repository.IdentityRepository
+	userRepo      repository.UserRepository
+	txManager     port.TxManager
+	outbox        port.Outbox
+	auditLogger   port.AuditLogger
+	logger        port.Logger
+	uuidGenerator port.UUIDGenerator
+}
+
+func NewExternalAccountService(
+	identityRepo repository.IdentityRepository,
+	userRepo repository.UserRepository,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	uuidGenerator port.UUIDGenerator,
+) *ExternalAccountService {
+	return &ExternalAccountService{
+		identityRepo:  identityRepo,
+		userRepo:      userRepo,
+		txManager:     txManager,
+		outbox:        outbox,
+		auditLogger:   auditLogger,
+		logger:        logger,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+// Resolve finds the account an external identity signs in to and records
+// the sign-in against the identity. An identity seen for the first time
+// gets a new account, registered as RegisterUseCase would register it,
+// unless its email belongs to an existing account: that account is only
+// linked when the provider links by email and the account's owner has
+// verified the address, and the sign-in is refused otherwise.
+func (s *ExternalAccountService) Resolve(ctx context.Context, provider port.ExternalProvider, external *port.ExternalIdentity, ipAddress string) (*entity.User, error) {
+	user, identity, err := s.find(ctx, provider, external, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	reportedEmail := identity.Email
+	if email, err := vo.NewEmail(external.Email); err == nil {
+		reportedEmail = email.String()
+	}
+	if err := s.identityRepo.RecordLogin(ctx, identity.ID, reportedEmail, time.Now().UTC()); err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to record identity login", "error", err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// IsLinked reports whether the user is linked to an identity at the
+// provider.
+func (s *ExternalAccountService) IsLinked(ctx context.Context, provider string, user *entity.User) (bool, error) {
+	linked, err := s.identityRepo.ExistsForUser(ctx, user.ID.String(), provider)
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to find identity", "error", err)
+		return false, err
+	}
+	return linked, nil
+}
+
+// Synchronize resolves a directory account like Resolve, then brings the
+// shadow user's roles, email and username in line with the directory,
+// which owns them. An email or username another account holds is left as
+// it was, to be taken up once it is free.
+func (s *ExternalAccountService) Synchronize(ctx context.Context, provider port.ExternalProvider, account *port.DirectoryAccount, ipAddress string) (*entity.User, error) {
+	external := &port.ExternalIdentity{
+		Subject:           account.Subject,
+		Email:             account.Email,
+		EmailVerified:     account.Email != "",
+		PreferredUsername: account.Username,
+	}
+	user, err := s.Resolve(ctx, provider, external, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := s.synchronize(ctx, user, external, account.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to update user", "error", err)
+			return nil, err
+		}
+		s.logger.InfoCtx(ctx, "User synchronized from directory", "user_id", user.ID.String(), "provider", provider.ID)
+	}
+	return user, nil
+}
+
+func (s *ExternalAccountService) synchronize(ctx context.Context, user *entity.User, external *port.ExternalIdentity, roles []string) (bool, error) {
+	roles = slices.Compact(slices.Sorted(slices.Values(roles)))
+	changed := !slices.Equal(user.Roles, roles)
+	user.Roles = roles
+
+	if email, err := vo.NewEmail(external.Email); err == nil && email.String() != user.Email.String() {
+		taken, err := s.userRepo.ExistsByEmail(ctx, email.String())
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to check email existence", "error", err)
+			return false, err
+		}
+		if !taken {
+			user.Email = email
+			user.IsEmailVerified = true
+			changed = true
+		}
+	}
+
+	if username, err := vo.NewUsername(externalUsername(external)); err == nil && username.String() != user.Username.String() {
+		taken, err := s.userRepo.ExistsByUsername(ctx, username.String())
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to check username existence", "error", err)
+			return false, err
+		}
+		if !taken {
+			user.Username = *username
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+func (s *ExternalAccountService) find(ctx context.Context, provider port.ExternalProvider, external *port.ExternalIdentity, ipAddress string) (*entity.User, *entity.Identity, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, provider.ID, external.Subject)
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to find identity", "error", err)
+		return nil, nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+			return nil, nil, err
+		}
+		if user == nil {
+			return nil, nil, exception.ErrUserNotFound
+		}
+		return user, identity, nil
+	}
+
+	if external.Email == "" || !external.EmailVerified {
+		return nil, nil, exception.ErrFederatedEmailRequired
+	}
+	email, err := vo.NewEmail(external.Email)
+	if err != nil {
+		return nil, nil, exception.ErrFederatedEmailRequired
+	}
+
+	existing, err := s.userRepo.FindByEmail(ctx, email.String())
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to find user by email", "error", err)
+		return nil, nil, err
+	}
+	if existing != nil {
+		// Anyone can register an address they do not own; linking such
+		// an account would hand it to the address's real owner and leave
+		// the squatter's password working.
+		if !provider.LinkByEmail || !existing.IsEmailVerified {
+			return nil, nil, exception.ErrEmailAlreadyExists
+		}
+		identity := entity.NewIdentity(s.uuidGenerator.Generate(), existing.ID.String(), provider.ID, external.Subject, email.String())
+		if err := s.identityRepo.Create(ctx, identity); err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to link identity", "error", err)
+			return nil, nil, err
+		}
+		s.logAudit(ctx, existing, ipAddress, map[string]interface{}{"provider": provider.ID})
+		return existing, identity, nil
+	}
+
+	return s.provision(ctx, provider, external, email, ipAddress)
+}
+
+// provision registers an account for the identity. The provider has
+// verified the email, so no verification mail is sent.
+func (s *ExternalAccountService) provision(ctx context.Context, provider port.ExternalProvider, external *port.ExternalIdentity, email vo.Email, ipAddress string) (*entity.User, *entity.Identity, error) {
+	userID, err := vo.NewUserID(s.uuidGenerator.Generate())
+	if err != nil {
+		return nil, nil, err
+	}
+	username, err := s.availableUsername(ctx, external, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := entity.NewUser(userID, *username, email)
+	if err := user.VerifyEmail(); err != nil {
+		return nil, nil, err
+	}
+	identity := entity.NewIdentity(s.uuidGenerator.Generate(), userID.String(), provider.ID, external.Subject, email.String())
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to create user", "error", err)
+			return err
+		}
+		if err := s.identityRepo.Create(ctx, identity); err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to create identity", "error", err)
+			return err
+		}
+
+		err := s.outbox.Publish(ctx, port.OutboxMessage{
+			EventType: event.UserRegistered,
+			DedupKey:  event.DedupKey(event.UserRegistered, user.ID.String()),
+			Payload: event.NewUserEvent(
+				user.ID.String(),
+				ipAddress,
+				correlationid.FromContext(ctx),
+				map[string]interface{}{
+					"username":     user.Username.String(),
+					"email":        user.Email.String(),
+					"has_password": false,
+					"provider":     provider.ID,
+				},
+			),
+		})
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to publish user registered event", "error", err)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.InfoCtx(ctx, "User provisioned from identity provider", "user_id", user.ID.String(), "provider", provider.ID)
+	return user, identity, nil
+}
+
+// availableUsername derives a username from what the provider calls the
+// user, or their email, and adds part of the user ID when it is taken.
+func (s *ExternalAccountService) availableUsername(ctx context.Context, external *port.ExternalIdentity, userID vo.UserID) (*vo.Username, error) {
+	base := externalUsername(external)
+	id := userID.String()
+	suffixed := base[:min(len(base), vo.UsernameMaxLength-9)] + "-" + id[len(id)-8:]
+
+	for _, candidate := range []string{base, suffixed} {
+		username, err := vo.NewUsername(candidate)
+		if err != nil {
+			return nil, err
+		}
+		exists, err := s.userRepo.ExistsByUsername(ctx, username.String())
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to check username existence", "error", err)
+			return nil, err
+		}
+		if !exists {
+			return username, nil
+		}
+	}
+	return nil, exception.ErrUsernameAlreadyExists
+}
+
+// externalUsername keeps the characters usernames allow from the name the
+// provider gave, or the email's local part, up to their maximum length.
+func externalUsername(external *port.ExternalIdentity) string {
+	name := external.PreferredUsername
+	if name == "" {
+		name = external.Email
+	}
+	name, _, _ = strings.Cut(name, "@")
+
+	var b strings.Builder
+	for _, r := range name {
+		if r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._%+-", r)) {
+			b.WriteRune(r)
+		}
+	}
+	username := b.String()
+	if len(username) < vo.UsernameMinLength {
+		return "user"
+	}
+	return username[:min(len(username), vo.UsernameMaxLength)]
+}
+
+func (s *ExternalAccountService) logAudit(ctx context.Context, user *entity.User, ipAddress string, details map[string]interface{}) {
+	userID := user.ID.String()
+
+	auditLog, err := entity.NewAuditLog(entity.AuditActionIdentityLinked, &userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	s.auditLogger.Log(ctx, auditLog)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type LoginService struct {
+	sessions    port.SessionIssuer
+	mfa         port.MFAChallenger
+	auditLogger port.AuditLogger
+	metrics     port.AuthMetrics
+	logger      port.Logger
+}
+
+func NewLoginService(
+	sessions port.SessionIssuer,
+	mfa port.MFAChallenger,
+	auditLogger port.AuditLogger,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+) *LoginService {
+	return &LoginService{
+		sessions:    sessions,
+		mfa:         mfa,
+		auditLogger: auditLogger,
+		metrics:     metrics,
+		logger:      logger,
+	}
+}
+
+func (s *LoginService) Check(ctx context.Context, attempt port.LoginAttempt) error {
+	user := attempt.User
+
+	if user.IsLocked(time.Now().UTC()) {
+		s.metrics.RecordLoginAttempt(port.LoginStatusLocked)
+		s.logFailure(ctx, attempt, "account_locked")
+		return exception.ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		s.metrics.RecordLoginAttempt(port.LoginStatusInactive)
+		s.logFailure(ctx, attempt, "user_inactive")
+		return exception.ErrUserInactive
+	}
+	if attempt.RequireVerifiedEmail && !user.IsEmailVerified {
+		s.metrics.RecordLoginAttempt(port.LoginStatusEmailUnverified)
+		s.logFailure(ctx, attempt, "email_unverified")
+		return exception.ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (s *LoginService) Complete(ctx context.Context, attempt port.LoginAttempt) (*port.LoginResult, error) {
+	if err := s.Check(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	user := attempt.User
+	if !attempt.SkipMFA {
+		ticket, err := s.mfa.BeginChallenge(ctx, user)
+		if err != nil {
+			s.logger.ErrorCtx(ctx, "Failed to start MFA challenge", "error", err)
+			s.metrics.RecordLoginAttempt(port.LoginStatusError)
+			return nil, err
+		}
+		if ticket != nil {
+			s.metrics.RecordLoginAttempt(port.LoginStatusMFARequired)
+			return &port.LoginResult{MFA: ticket}, nil
+		}
+	}
+
+	if attempt.VerifyOnly {
+		s.metrics.RecordLoginAttempt(port.LoginStatusSuccess)
+		s.logger.InfoCtx(ctx, "User credentials verified", "user_id", user.ID.String(), "method", attempt.Method)
+		return &port.LoginResult{}, nil
+	}
+
+	session, err := s.sessions.Issue(ctx, user, "")
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to issue session", "error", err)
+		s.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	s.metrics.RecordLoginAttempt(port.LoginStatusSuccess)
+	s.logAudit(ctx, entity.AuditActionUserLogin, attempt, s.details(attempt))
+
+	args := []any{"user_id", user.ID.String(), "method", attempt.Method}
+	if attempt.Provider != "" {
+		args = append(args, "provider", attempt.Provider)
+	}
+	s.logger.InfoCtx(ctx, "User logged in", args...)
+
+	return &port.LoginResult{Session: session}, nil
+}
+
+func (s *LoginService) details(attempt port.LoginAttempt) map[string]interface{} {
+	details := map[string]interface{}{"method": attempt.Method}
+	if attempt.Provider != "" {
+		details["provider"] = attempt.Provider
+	}
+	for k, v := range attempt.Details {
+		details[k] = v
+	}
+	return details
+}
+
+func (s *LoginService) logFailure(ctx context.Context, attempt port.LoginAttempt, reason string) {
+	details := s.details(attempt)
+	details["reason"] = reason
+	s.logAudit(ctx, entity.AuditActionUserLoginFailed, attempt, details)
+}
+
+func (s *LoginService) logAudit(ctx context.Context, action entity.AuditAction, attempt port.LoginAttempt, details map[string]interface{}) {
+	userID := attempt.User.ID.String()
+
+	auditLog, err := entity.NewAuditLog(action, &userID, details, attempt.IPAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		s.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	s.auditLogger.Log(ctx, auditLog)
+}
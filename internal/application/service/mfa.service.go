@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type MFAService struct {
+	totpRepo      repository.TOTPCredentialRepository
+	passkeyRepo   repository.WebAuthnCredentialRepository
+	recoveryRepo  repository.MFARecoveryCodeRepository
+	challengeRepo repository.MFAChallengeRepository
+	totp          port.TOTP
+	secrets       port.SecretBox
+	recoveryCodes port.RecoveryCodeGenerator
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	challengeTTL  time.Duration
+	codeCount     int
+}
+
+func NewMFAService(
+	totpRepo repository.TOTPCredentialRepository,
+	passkeyRepo repository.WebAuthnCredentialRepository,
+	recoveryRepo repository.MFARecoveryCodeRepository,
+	challengeRepo repository.MFAChallengeRepository,
+	totp port.TOTP,
+	secrets port.SecretBox,
+	recoveryCodes port.RecoveryCodeGenerator,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	challengeTTL time.Duration,
+	codeCount int,
+) *MFAService {
+	return &MFAService{
+		totpRepo:      totpRepo,
+		passkeyRepo:   passkeyRepo,
+		recoveryRepo:  recoveryRepo,
+		challengeRepo: challengeRepo,
+		totp:          totp,
+		secrets:       secrets,
+		recoveryCodes: recoveryCodes,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		challengeTTL:  challengeTTL,
+		codeCount:     codeCount,
+	}
+}
+
+func (s *MFAService) BeginChallenge(ctx context.Context, user *entity.User) (*port.MFAChallengeTicket, error) {
+	methods, err := s.methods(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	rawToken, err := s.opaqueTokens.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := entity.NewMFAChallenge(
+		s.uuidGenerator.Generate(),
+		user.ID.String(),
+		s.opaqueTokens.Hash(rawToken),
+		time.Now().UTC().Add(s.challengeTTL),
+	)
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &port.MFAChallengeTicket{
+		Token:     rawToken,
+		ExpiresAt: challenge.ExpiresAt,
+		Methods:   append(methods, port.MFAMethodRecoveryCode),
+	}, nil
+}
+
+// methods lists the second factors the user has enrolled, not counting
+// recovery codes, which only back them up.
+func (s *MFAService) methods(ctx context.Context, userID string) ([]string, error) {
+	var methods []string
+
+	credential, err := s.totpRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.IsConfirmed() {
+		methods = append(methods, port.MFAMethodTOTP)
+	}
+
+	passkeys, err := s.passkeyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(passkeys) > 0 {
+		methods = append(methods, port.MFAMethodPasskey)
+	}
+
+	return methods, nil
+}
+
+func (s *MFAService) HasSecondFactor(ctx context.Context, userID string) (bool, error) {
+	methods, err := s.methods(ctx, userID)
+	return len(methods) > 0, err
+}
+
+// VerifyCode treats six digits as an authenticator code and anything else
+// as a recovery code.
+func (s *MFAService) VerifyCode(ctx context.Context, userID, code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return port.MFAMethodTOTP, s.verifyTOTP(ctx, userID, code)
+	}
+	return port.MFAMethodRecoveryCode, s.verifyRecoveryCode(ctx, userID, code)
+}
+
+func (s *MFAService) verifyTOTP(ctx context.Context, userID, code string) error {
+	credential, err := s.totpRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if credential == nil || !credential.IsConfirmed() {
+		return exception.ErrMFANotEnabled
+	}
+
+	secret, err := s.secrets.Open(credential.SecretCiphertext)
+	if err != nil {
+		return err
+	}
+
+	step, ok := s.totp.Validate(string(secret), code, time.Now())
+	if !ok {
+		return exception.ErrInvalidMFACode
+	}
+
+	// Each code is accepted once, even though it stays valid for the
+	// rest of its period.
+	used, err := s.totpRepo.UseStep(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return exception.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) verifyRecoveryCode(ctx context.Context, userID, code string) error {
+	normalized := NormalizeRecoveryCode(code)
+	if normalized == "" {
+		return exception.ErrInvalidMFACode
+	}
+
+	used, err := s.recoveryRepo.MarkUsed(ctx, userID, s.opaqueTokens.Hash(normalized), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return exception.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) IssueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes, err := s.recoveryCodes.Generate(s.codeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]*entity.MFARecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		stored = append(stored, entity.NewMFARecoveryCode(
+			s.uuidGenerator.Generate(),
+			userID,
+			s.opaqueTokens.Hash(NormalizeRecoveryCode(code)),
+		))
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode ignores case, spaces and dashes so codes typed
+// back from paper still match.
+func NormalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
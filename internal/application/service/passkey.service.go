@@ -0,0 +1,217 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// PasskeyService keeps WebAuthn ceremony state and credentials; the
+// relying party does the cryptographic checks.
+type PasskeyService struct {
+	credentialRepo repository.WebAuthnCredentialRepository
+	sessionRepo    repository.WebAuthnSessionRepository
+	rp             port.WebAuthnRelyingParty
+	uuidGenerator  port.UUIDGenerator
+	sessionTTL     time.Duration
+}
+
+func NewPasskeyService(
+	credentialRepo repository.WebAuthnCredentialRepository,
+	sessionRepo repository.WebAuthnSessionRepository,
+	rp port.WebAuthnRelyingParty,
+	uuidGenerator port.UUIDGenerator,
+	sessionTTL time.Duration,
+) *PasskeyService {
+	return &PasskeyService{
+		credentialRepo: credentialRepo,
+		sessionRepo:    sessionRepo,
+		rp:             rp,
+		uuidGenerator:  uuidGenerator,
+		sessionTTL:     sessionTTL,
+	}
+}
+
+func (s *PasskeyService) BeginRegistration(ctx context.Context, user *entity.User) (*port.PasskeyCeremony, error) {
+	return s.begin(ctx, user.ID.String(), entity.WebAuthnCeremonyRegistration)
+}
+
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID, name string, response port.PasskeyAttestationResponse) (*entity.WebAuthnCredential, error) {
+	session, err := s.consume(ctx, response.SessionID, entity.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, exception.ErrInvalidWebAuthnSession
+	}
+
+	registration, err := s.rp.VerifyRegistration(session.Challenge, response.ClientDataJSON, response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", exception.ErrInvalidPasskey, err)
+	}
+
+	existing, err := s.credentialRepo.FindByCredentialID(ctx, registration.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, exception.ErrPasskeyAlreadyRegistered
+	}
+
+	credential := &entity.WebAuthnCredential{
+		ID:                s.uuidGenerator.Generate(),
+		UserID:            userID,
+		Name:              name,
+		CredentialID:      registration.CredentialID,
+		PublicKey:         registration.PublicKey,
+		Algorithm:         registration.Algorithm,
+		SignCount:         registration.SignCount,
+		AAGUID:            registration.AAGUID,
+		Transports:        response.Transports,
+		AttestationFormat: registration.AttestationFormat,
+		BackupEligible:    registration.BackupEligible,
+		BackupState:       registration.BackupState,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err := s.credentialRepo.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (s *PasskeyService) BeginAssertion(ctx context.Context, userID string) (*port.PasskeyCeremony, error) {
+	return s.begin(ctx, userID, entity.WebAuthnCeremonyAuthentication)
+}
+
+func (s *PasskeyService) VerifyAssertion(ctx context.Context, response port.PasskeyAssertionResponse, userID string, requireUserVerification bool) (*entity.WebAuthnCredential, error) {
+	session, err := s.consume(ctx, response.SessionID, entity.WebAuthnCeremonyAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" && session.UserID != userID {
+		return nil, exception.ErrInvalidWebAuthnSession
+	}
+
+	credential, err := s.credentialRepo.FindByCredentialID(ctx, response.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, exception.ErrInvalidPasskey
+	}
+	if session.UserID != "" && credential.UserID != session.UserID {
+		return nil, exception.ErrInvalidPasskey
+	}
+	// A discoverable credential names its user; it has to be the owner
+	// on record.
+	if session.UserID == "" && len(response.UserHandle) == 0 {
+		return nil, exception.ErrInvalidPasskey
+	}
+	if len(response.UserHandle) > 0 && !bytes.Equal(response.UserHandle, []byte(credential.UserID)) {
+		return nil, exception.ErrInvalidPasskey
+	}
+
+	assertion, err := s.rp.VerifyAssertion(
+		session.Challenge,
+		response.ClientDataJSON,
+		response.AuthenticatorData,
+		response.Signature,
+		credential.PublicKey,
+		requireUserVerification,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", exception.ErrInvalidPasskey, err)
+	}
+
+	if !credential.AcceptsSignCount(assertion.SignCount) {
+		return nil, exception.ErrPasskeySignCount
+	}
+
+	now := time.Now().UTC()
+	updated, err := s.credentialRepo.UpdateUsage(ctx, credential.ID, credential.SignCount, assertion.SignCount, assertion.BackupState, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, exception.ErrPasskeySignCount
+	}
+
+	credential.SignCount = assertion.SignCount
+	credential.BackupState = assertion.BackupState
+	credential.LastUsedAt = &now
+	return credential, nil
+}
+
+func (s *PasskeyService) begin(ctx context.Context, userID string, ceremony entity.WebAuthnCeremony) (*port.PasskeyCeremony, error) {
+	challenge, err := s.rp.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	var credentialIDs [][]byte
+	if userID != "" {
+		credentials, err := s.credentialRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, credential := range credentials {
+			credentialIDs = append(credentialIDs, credential.CredentialID)
+		}
+	}
+
+	session := entity.NewWebAuthnSession(
+		s.uuidGenerator.Generate(),
+		userID,
+		ceremony,
+		challenge,
+		time.Now().UTC().Add(s.sessionTTL),
+	)
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &port.PasskeyCeremony{
+		SessionID:     session.ID,
+		Challenge:     challenge,
+		RPID:          s.rp.ID(),
+		RPName:        s.rp.Name(),
+		Algorithms:    s.rp.Algorithms(),
+		CredentialIDs: credentialIDs,
+		ExpiresAt:     session.ExpiresAt,
+	}, nil
+}
+
+// consume marks the ceremony used before its response is checked, so a
+// challenge is never verified twice.
+func (s *PasskeyService) consume(ctx context.Context, sessionID string, ceremony entity.WebAuthnCeremony) (*entity.WebAuthnSession, error) {
+	if sessionID == "" {
+		return nil, exception.ErrInvalidWebAuthnSession
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if session == nil || session.Ceremony != ceremony || session.IsUsed() || session.IsExpired(now) {
+		return nil, exception.ErrInvalidWebAuthnSession
+	}
+
+	marked, err := s.sessionRepo.MarkUsed(ctx, session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, exception.ErrInvalidWebAuthnSession
+	}
+
+	return session, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+)
+
+type PasswordPolicyService struct {
+	policy   vo.PasswordPolicy
+	breaches port.BreachedPasswordChecker
+	logger   port.Logger
+}
+
+// NewPasswordPolicyService returns a validator enforcing policy. breaches
+// may be nil to skip the breached-password check.
+func NewPasswordPolicyService(policy vo.PasswordPolicy, breaches port.BreachedPasswordChecker, logger port.Logger) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		policy:   policy,
+		breaches: breaches,
+		logger:   logger,
+	}
+}
+
+func (s *PasswordPolicyService) Validate(ctx context.Context, password string, personalInfo ...string) (vo.Password, error) {
+	validated, err := vo.NewPassword(password, s.policy, personalInfo...)
+	if err != nil {
+		return vo.Password{}, err
+	}
+
+	if s.breaches == nil {
+		return validated, nil
+	}
+
+	breached, err := s.breaches.IsBreached(ctx, password)
+	if err != nil {
+		// The breach corpus is a defence in depth; an unreadable file
+		// should not stop users from registering or resetting.
+		s.logger.WarnCtx(ctx, "Breached password check failed", "error", err)
+		return validated, nil
+	}
+	if breached {
+		return vo.Password{}, &exception.PasswordPolicyError{
+			Violations: []exception.FieldError{{
+				Field:   "password",
+				Code:    vo.PasswordViolationBreached,
+				Message: "password has appeared in a data breach and cannot be used",
+			}},
+			Suggestions: []string{"Choose a password you have not used on any other site"},
+		}
+	}
+
+	return validated, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type PasswordResetService struct {
+	resetRepo     repository.PasswordResetTokenRepository
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	mailer        port.Mailer
+	tokenTTL      time.Duration
+	resetURL      string
+}
+
+func NewPasswordResetService(
+	resetRepo repository.PasswordResetTokenRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	mailer port.Mailer,
+	tokenTTL time.Duration,
+	resetURL string,
+) *PasswordResetService {
+	return &PasswordResetService{
+		resetRepo:     resetRepo,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		mailer:        mailer,
+		tokenTTL:      tokenTTL,
+		resetURL:      resetURL,
+	}
+}
+
+func (s *PasswordResetService) Send(ctx context.Context, user *entity.User) error {
+	now := time.Now().UTC()
+
+	// Only the most recently issued link stays valid.
+	if err := s.resetRepo.InvalidateByUserID(ctx, user.ID.String(), now); err != nil {
+		return err
+	}
+
+	rawToken, err := s.opaqueTokens.Generate()
+	if err != nil {
+		return err
+	}
+
+	token := entity.NewPasswordResetToken(
+		s.uuidGenerator.Generate(),
+		user.ID.String(),
+		s.opaqueTokens.Hash(rawToken),
+		now.Add(s.tokenTTL),
+	)
+	if err := s.resetRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.resetURL, rawToken)
+
+	return s.mailer.Send(ctx, port.MailMessage{
+		To:      user.Email.String(),
+		Subject: "Reset your password",
+		TextBody: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password. It expires in %d minutes.\n\n%s\n\nIf you did not request a password reset, you can ignore this email.\n",
+			user.Username.String(),
+			int(s.tokenTTL.Minutes()),
+			link,
+		),
+	})
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type PasswordlessService struct {
+	challengeRepo  repository.PasswordlessChallengeRepository
+	opaqueTokens   port.OpaqueTokenGenerator
+	codes          port.OneTimeCodeGenerator
+	signer         port.LinkSigner
+	uuidGenerator  port.UUIDGenerator
+	mailer         port.Mailer
+	linkTTL        time.Duration
+	codeTTL        time.Duration
+	resendInterval time.Duration
+	linkURL        string
+}
+
+func NewPasswordlessService(
+	challengeRepo repository.PasswordlessChallengeRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	codes port.OneTimeCodeGenerator,
+	signer port.LinkSigner,
+	uuidGenerator port.UUIDGenerator,
+	mailer port.Mailer,
+	linkTTL time.Duration,
+	codeTTL time.Duration,
+	resendInterval time.Duration,
+	linkURL string,
+) *PasswordlessService {
+	return &PasswordlessService{
+		challengeRepo:  challengeRepo,
+		opaqueTokens:   opaqueTokens,
+		codes:          codes,
+		signer:         signer,
+		uuidGenerator:  uuidGenerator,
+		mailer:         mailer,
+		linkTTL:        linkTTL,
+		codeTTL:        codeTTL,
+		resendInterval: resendInterval,
+		linkURL:        linkURL,
+	}
+}
+
+func (s *PasswordlessService) Send(ctx context.Context, user *entity.User, request port.PasswordlessRequest) error {
+	now := time.Now().UTC()
+	if !now.Before(request.ExpiresAt) {
+		return nil
+	}
+
+	// A challenge for the device means a redelivery of a request that was
+	// already mailed.
+	existing, err := s.challengeRepo.FindByDeviceHash(ctx, request.DeviceHash)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	// Comparing against the request time rather than now keeps a backlog
+	// of deliveries from mailing every queued request at once.
+	latest, err := s.challengeRepo.FindLatestByUserID(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+	if latest != nil && request.RequestedAt.Sub(latest.CreatedAt) < s.resendInterval {
+		return nil
+	}
+
+	id := s.uuidGenerator.Generate()
+
+	var secretHash string
+	var msg port.MailMessage
+	switch request.Method {
+	case entity.PasswordlessMethodLink:
+		token := s.signer.Sign(id)
+		secretHash = s.opaqueTokens.Hash(token)
+		msg = port.MailMessage{
+			To:      user.Email.String(),
+			Subject: "Your sign-in link",
+			TextBody: fmt.Sprintf(
+				"Hi %s,\n\nUse the link below to sign in. It expires in %d minutes and only works in the browser where you asked for it.\n\n%s?token=%s\n\nIf you did not try to sign in, you can ignore this email.\n",
+				user.Username.String(),
+				int(s.linkTTL.Minutes()),
+				s.linkURL,
+				token,
+			),
+		}
+	case entity.PasswordlessMethodCode:
+		code, err := s.codes.Generate()
+		if err != nil {
+			return err
+		}
+		secretHash = s.opaqueTokens.Hash(s.signer.Sign(entity.PasswordlessCodePayload(request.DeviceHash, code)))
+		msg = port.MailMessage{
+			To:      user.Email.String(),
+			Subject: "Your sign-in code",
+			TextBody: fmt.Sprintf(
+				"Hi %s,\n\nYour sign-in code is %s. It expires in %d minutes.\n\nIf you did not try to sign in, you can ignore this email.\n",
+				user.Username.String(),
+				code,
+				int(s.codeTTL.Minutes()),
+			),
+		}
+	default:
+		return fmt.Errorf("unknown passwordless method %q", request.Method)
+	}
+
+	// The challenge is stored only once the email is out, so a failed
+	// send is retried rather than taken for a redelivery.
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	// Only the most recently requested email stays valid.
+	if err := s.challengeRepo.InvalidateByUserID(ctx, user.ID.String(), now); err != nil {
+		return err
+	}
+
+	return s.challengeRepo.Create(ctx, entity.NewPasswordlessChallenge(
+		id,
+		user.ID.String(),
+		request.Method,
+		request.DeviceHash,
+		secretHash,
+		request.ExpiresAt,
+	))
+}
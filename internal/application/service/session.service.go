@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type SessionService struct {
+	tokenService  port.TokenService
+	refreshRepo   repository.RefreshTokenRepository
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	refreshTTL    time.Duration
+}
+
+func NewSessionService(
+	tokenService port.TokenService,
+	refreshRepo repository.RefreshTokenRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	refreshTTL time.Duration,
+) *SessionService {
+	return &SessionService{
+		tokenService:  tokenService,
+		refreshRepo:   refreshRepo,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		refreshTTL:    refreshTTL,
+	}
+}
+
+func (s *SessionService) Issue(ctx context.Context, user *entity.User, familyID string) (*port.Session, error) {
+	return s.issue(ctx, user, port.ClientGrant{FamilyID: familyID, Refreshable: true})
+}
+
+func (s *SessionService) IssueForClient(ctx context.Context, user *entity.User, grant port.ClientGrant) (*port.Session, error) {
+	return s.issue(ctx, user, grant)
+}
+
+func (s *SessionService) issue(ctx context.Context, user *entity.User, grant port.ClientGrant) (*port.Session, error) {
+	scopes := grant.Scopes
+	if len(grant.AccessTokenScopes) > 0 {
+		scopes = grant.AccessTokenScopes
+	}
+
+	accessToken, accessExpiresAt, err := s.tokenService.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:   user.ID.String(),
+		Username: user.Username.String(),
+		Roles:    user.Roles,
+		ClientID: grant.ClientID,
+		Scopes:   scopes,
+		TTL:      grant.AccessTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session := &port.Session{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessExpiresAt,
+	}
+	if !grant.Refreshable {
+		return session, nil
+	}
+
+	refreshToken, err := s.opaqueTokens.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	familyID := grant.FamilyID
+	if familyID == "" {
+		familyID = s.uuidGenerator.Generate()
+	}
+
+	stored := entity.NewRefreshToken(
+		s.uuidGenerator.Generate(),
+		user.ID.String(),
+		familyID,
+		s.opaqueTokens.Hash(refreshToken),
+		time.Now().UTC().Add(s.refreshTTL),
+	)
+	stored.ClientID = grant.ClientID
+	stored.Scopes = grant.Scopes
+
+	if err := s.refreshRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	session.RefreshToken = refreshToken
+	session.RefreshTokenExpiresAt = stored.ExpiresAt
+	session.FamilyID = familyID
+	return session, nil
+}
@@ -0,0 +1,68 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+var auditActions = map[string]entity.AuditAction{
+	event.UserRegistered:     entity.AuditActionUserRegistered,
+	event.UserEmailVerified:  entity.AuditActionEmailVerified,
+	event.UserPasswordReset:  entity.AuditActionPasswordReset,
+	event.UserMFAEnabled:     entity.AuditActionMFAEnrolled,
+	event.UserMFADisabled:    entity.AuditActionMFADisabled,
+	event.UserPasskeyAdded:   entity.AuditActionPasskeyRegistered,
+	event.UserPasskeyRemoved: entity.AuditActionPasskeyRemoved,
+}
+
+// AuditEvents lists the event types AuditSubscriber records.
+var AuditEvents = []string{
+	event.UserRegistered,
+	event.UserEmailVerified,
+	event.UserPasswordReset,
+	event.UserMFAEnabled,
+	event.UserMFADisabled,
+	event.UserPasskeyAdded,
+	event.UserPasskeyRemoved,
+}
+
+// AuditSubscriber writes user events to the audit log. The audit entry
+// reuses the outbox ID, so a redelivered event is stored only once.
+type AuditSubscriber struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditSubscriber(repo repository.AuditRepository) *AuditSubscriber {
+	return &AuditSubscriber{repo: repo}
+}
+
+func (s *AuditSubscriber) Name() string {
+	return "audit"
+}
+
+func (s *AuditSubscriber) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	action, ok := auditActions[delivery.EventType]
+	if !ok {
+		return fmt.Errorf("no audit action for event %q", delivery.EventType)
+	}
+
+	var payload event.UserEvent
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return err
+	}
+
+	auditLog, err := entity.NewAuditLog(action, &payload.UserID, payload.Details, payload.IPAddress, payload.CorrelationID)
+	if err != nil {
+		return err
+	}
+	auditLog.ID = delivery.ID
+	auditLog.Timestamp = payload.OccurredAt
+
+	return s.repo.Create(ctx, auditLog)
+}
@@ -0,0 +1,57 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// PasswordResetMailEvents lists the event types PasswordResetMailSubscriber
+// handles.
+var PasswordResetMailEvents = []string{
+	event.UserPasswordResetRequested,
+}
+
+// PasswordResetMailSubscriber sends the reset link for a forgotten
+// password. Mailing it here rather than in the request keeps the response
+// time from showing whether the account exists. A redelivery issues a
+// fresh link and invalidates the previous one.
+type PasswordResetMailSubscriber struct {
+	userRepo repository.UserRepository
+	resets   port.PasswordResetSender
+}
+
+func NewPasswordResetMailSubscriber(
+	userRepo repository.UserRepository,
+	resets port.PasswordResetSender,
+) *PasswordResetMailSubscriber {
+	return &PasswordResetMailSubscriber{
+		userRepo: userRepo,
+		resets:   resets,
+	}
+}
+
+func (s *PasswordResetMailSubscriber) Name() string {
+	return "password_reset_mail"
+}
+
+func (s *PasswordResetMailSubscriber) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	var payload event.UserEvent
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	// The account may have been deactivated since the request.
+	if user == nil || !user.IsActive {
+		return nil
+	}
+
+	return s.resets.Send(ctx, user)
+}
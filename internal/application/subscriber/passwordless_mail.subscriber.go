@@ -0,0 +1,62 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// PasswordlessMailEvents lists the event types PasswordlessMailSubscriber
+// handles.
+var PasswordlessMailEvents = []string{
+	event.UserPasswordlessRequested,
+}
+
+// PasswordlessMailSubscriber sends the sign-in link or code for a
+// passwordless sign-in. Mailing it here rather than in the request keeps
+// the response time from showing whether the account exists.
+type PasswordlessMailSubscriber struct {
+	userRepo repository.UserRepository
+	sender   port.PasswordlessSender
+}
+
+func NewPasswordlessMailSubscriber(
+	userRepo repository.UserRepository,
+	sender port.PasswordlessSender,
+) *PasswordlessMailSubscriber {
+	return &PasswordlessMailSubscriber{
+		userRepo: userRepo,
+		sender:   sender,
+	}
+}
+
+func (s *PasswordlessMailSubscriber) Name() string {
+	return "passwordless_mail"
+}
+
+func (s *PasswordlessMailSubscriber) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	var payload event.PasswordlessRequestedEvent
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	// The account may have been deactivated since the request.
+	if user == nil || !user.IsActive {
+		return nil
+	}
+
+	return s.sender.Send(ctx, user, port.PasswordlessRequest{
+		Method:      entity.PasswordlessMethod(payload.Method),
+		DeviceHash:  payload.DeviceHash,
+		RequestedAt: payload.RequestedAt,
+		ExpiresAt:   payload.ExpiresAt,
+	})
+}
@@ -0,0 +1,55 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// VerificationMailEvents lists the event types VerificationMailSubscriber
+// handles.
+var VerificationMailEvents = []string{
+	event.UserRegistered,
+}
+
+// VerificationMailSubscriber sends the verification link to newly
+// registered users. A redelivery issues a fresh link and invalidates the
+// previous one, so at most one link is ever usable.
+type VerificationMailSubscriber struct {
+	userRepo     repository.UserRepository
+	verification port.EmailVerificationSender
+}
+
+func NewVerificationMailSubscriber(
+	userRepo repository.UserRepository,
+	verification port.EmailVerificationSender,
+) *VerificationMailSubscriber {
+	return &VerificationMailSubscriber{
+		userRepo:     userRepo,
+		verification: verification,
+	}
+}
+
+func (s *VerificationMailSubscriber) Name() string {
+	return "verification_mail"
+}
+
+func (s *VerificationMailSubscriber) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	var payload event.UserEvent
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.IsEmailVerified {
+		return nil
+	}
+
+	return s.verification.Send(ctx, user)
+}
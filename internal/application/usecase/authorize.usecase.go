@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type authorizeUseCase struct {
+	clientRepo    repository.OAuthClientRepository
+	userRepo      repository.UserRepository
+	consentRepo   repository.UserConsentRepository
+	challengeRepo repository.ConsentChallengeRepository
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	codes         authorizationCodeIssuer
+
+	scopes     []string
+	consentTTL time.Duration
+}
+
+func NewAuthorizeUsecase(
+	clientRepo repository.OAuthClientRepository,
+	codeRepo repository.AuthorizationCodeRepository,
+	userRepo repository.UserRepository,
+	consentRepo repository.UserConsentRepository,
+	challengeRepo repository.ConsentChallengeRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	scopes []string,
+	codeTTL time.Duration,
+	consentTTL time.Duration,
+) port.AuthorizeUseCase {
+	return &authorizeUseCase{
+		clientRepo:    clientRepo,
+		userRepo:      userRepo,
+		consentRepo:   consentRepo,
+		challengeRepo: challengeRepo,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		codes: authorizationCodeIssuer{
+			codeRepo:      codeRepo,
+			auditLogger:   auditLogger,
+			logger:        logger,
+			opaqueTokens:  opaqueTokens,
+			uuidGenerator: uuidGenerator,
+			codeTTL:       codeTTL,
+		},
+
+		scopes:     scopes,
+		consentTTL: consentTTL,
+	}
+}
+
+// Execute issues an authorization code once the user has signed in. The
+// request is validated again, since it has travelled through the login
+// page since it was first checked. A third-party client the user has not
+// yet granted every requested scope, or that asked with prompt=consent,
+// gets no code yet: the request waits on the consent page instead.
+func (u *authorizeUseCase) Execute(ctx context.Context, input input.AuthorizeInput) (*output.AuthorizeOutput, error) {
+	client, scopes, err := validateAuthorizationRequest(ctx, u.clientRepo, u.logger, u.scopes, input.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := findActiveUser(ctx, u.userRepo, u.logger, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.ID.String()
+
+	if !client.FirstParty {
+		consent, err := u.consentRepo.Find(ctx, userID, client.ID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to find consent", "error", err)
+			return nil, err
+		}
+		forced := slices.Contains(strings.Fields(input.Request.Prompt), oidcPromptConsent)
+		if forced || consent == nil || !consent.Covers(scopes) {
+			return u.askConsent(ctx, client, userID, scopes, input.Request)
+		}
+	}
+
+	return u.codes.issue(ctx, client.ID, userID, scopes, input.Request.RedirectURI, input.Request.State,
+		input.Request.Nonce, input.Request.CodeChallenge, input.IPAddress)
+}
+
+// askConsent stores the request for the consent page to show.
+func (u *authorizeUseCase) askConsent(ctx context.Context, client *entity.OAuthClient, userID string, scopes []string, req input.AuthorizationRequest) (*output.AuthorizeOutput, error) {
+	token, err := u.opaqueTokens.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate consent token", "error", err)
+		return nil, err
+	}
+
+	challenge := entity.NewConsentChallenge(
+		u.uuidGenerator.Generate(),
+		u.opaqueTokens.Hash(token),
+		client.ID,
+		userID,
+		req.RedirectURI,
+		scopes,
+		req.State,
+		req.Nonce,
+		req.CodeChallenge,
+		time.Now().UTC().Add(u.consentTTL),
+	)
+	if err := u.challengeRepo.Create(ctx, challenge); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to store consent challenge", "error", err)
+		return nil, err
+	}
+
+	return &output.AuthorizeOutput{
+		RedirectURI:  req.RedirectURI,
+		State:        req.State,
+		ConsentToken: token,
+	}, nil
+}
+
+// findActiveUser returns the user an authorization code is about to be
+// issued to, who must still exist and be active.
+func findActiveUser(ctx context.Context, userRepo repository.UserRepository, logger port.Logger, userID string) (*entity.User, error) {
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, exception.ErrUserInactive
+	}
+	return user, nil
+}
+
+// authorizationCodeIssuer issues the authorization code that ends an
+// approved authorization request, whether the user approved it by signing
+// in or on the consent page.
+type authorizationCodeIssuer struct {
+	codeRepo      repository.AuthorizationCodeRepository
+	auditLogger   port.AuditLogger
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	codeTTL       time.Duration
+}
+
+func (i authorizationCodeIssuer) issue(
+	ctx context.Context,
+	clientID, userID string,
+	scopes []string,
+	redirectURI, state, nonce, codeChallenge string,
+	ipAddress string,
+) (*output.AuthorizeOutput, error) {
+	code, err := i.opaqueTokens.Generate()
+	if err != nil {
+		i.logger.ErrorCtx(ctx, "Failed to generate authorization code", "error", err)
+		return nil, err
+	}
+
+	stored := entity.NewAuthorizationCode(
+		i.uuidGenerator.Generate(),
+		i.opaqueTokens.Hash(code),
+		clientID,
+		userID,
+		redirectURI,
+		scopes,
+		nonce,
+		codeChallenge,
+		i.uuidGenerator.Generate(),
+		time.Now().UTC().Add(i.codeTTL),
+	)
+	if err := i.codeRepo.Create(ctx, stored); err != nil {
+		i.logger.ErrorCtx(ctx, "Failed to store authorization code", "error", err)
+		return nil, err
+	}
+
+	logOAuthAudit(ctx, i.auditLogger, i.logger, entity.AuditActionOAuthAuthorized, userID, clientID, scopes, ipAddress)
+
+	i.logger.InfoCtx(ctx, "OAuth authorization granted", "user_id", userID, "client_id", clientID)
+
+	return &output.AuthorizeOutput{
+		RedirectURI: redirectURI,
+		Code:        code,
+		State:       state,
+	}, nil
+}
+
+// logOAuthAudit records what a user granted, denied or withdrew from a
+// client.
+func logOAuthAudit(ctx context.Context, auditLogger port.AuditLogger, logger port.Logger, action entity.AuditAction, userID, clientID string, scopes []string, ipAddress string) {
+	details := map[string]interface{}{
+		"client_id": clientID,
+	}
+	if len(scopes) > 0 {
+		details["scope"] = entity.FormatScope(scopes)
+	}
+
+	auditLog, err := entity.NewAuditLog(action, &userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	auditLogger.Log(ctx, auditLog)
+}
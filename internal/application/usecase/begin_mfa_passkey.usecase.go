@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type beginMFAPasskeyUseCase struct {
+	challengeRepo repository.MFAChallengeRepository
+	passkeys      port.PasskeyAuthenticator
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	maxAttempts   int
+}
+
+// NewBeginMFAPasskeyUsecase starts the passkey assertion that answers a
+// pending MFA challenge.
+func NewBeginMFAPasskeyUsecase(
+	challengeRepo repository.MFAChallengeRepository,
+	passkeys port.PasskeyAuthenticator,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	maxAttempts int,
+) port.BeginMFAPasskeyUseCase {
+	return &beginMFAPasskeyUseCase{
+		challengeRepo: challengeRepo,
+		passkeys:      passkeys,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+func (u *beginMFAPasskeyUseCase) Execute(ctx context.Context, input input.BeginMFAPasskeyInput) (*output.PasskeyOptionsOutput, error) {
+	challenge, err := u.challengeRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.MFAToken))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find MFA challenge", "error", err)
+		return nil, err
+	}
+	if challenge == nil || challenge.IsUsed() || challenge.IsExpired(time.Now().UTC()) || challenge.Attempts >= u.maxAttempts {
+		return nil, exception.ErrInvalidMFAChallenge
+	}
+
+	ceremony, err := u.passkeys.BeginAssertion(ctx, challenge.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to start passkey assertion", "error", err)
+		return nil, err
+	}
+	if len(ceremony.CredentialIDs) == 0 {
+		return nil, exception.ErrPasskeyNotFound
+	}
+
+	return passkeyOptions(ceremony), nil
+}
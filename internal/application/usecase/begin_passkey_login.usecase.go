@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type beginPasskeyLoginUseCase struct {
+	userRepo repository.UserRepository
+	passkeys port.PasskeyAuthenticator
+	logger   port.Logger
+}
+
+func NewBeginPasskeyLoginUsecase(
+	userRepo repository.UserRepository,
+	passkeys port.PasskeyAuthenticator,
+	logger port.Logger,
+) port.BeginPasskeyLoginUseCase {
+	return &beginPasskeyLoginUseCase{
+		userRepo: userRepo,
+		passkeys: passkeys,
+		logger:   logger,
+	}
+}
+
+func (u *beginPasskeyLoginUseCase) Execute(ctx context.Context, input input.BeginPasskeyLoginInput) (*output.PasskeyOptionsOutput, error) {
+	var userID string
+	if identifier := strings.TrimSpace(input.Identifier); identifier != "" {
+		user, err := u.findUser(ctx, identifier)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+			return nil, err
+		}
+		// Unknown identifiers fall back to a discoverable ceremony rather
+		// than an error, so the response does not reveal accounts.
+		if user != nil {
+			userID = user.ID.String()
+		}
+	}
+
+	ceremony, err := u.passkeys.BeginAssertion(ctx, userID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to start passkey login", "error", err)
+		return nil, err
+	}
+
+	return passkeyOptions(ceremony), nil
+}
+
+func (u *beginPasskeyLoginUseCase) findUser(ctx context.Context, identifier string) (*entity.User, error) {
+	if strings.Contains(identifier, "@") {
+		return u.userRepo.FindByEmail(ctx, strings.ToLower(identifier))
+	}
+	return u.userRepo.FindByUsername(ctx, identifier)
+}
+
+func passkeyAssertionResponse(assertion input.PasskeyAssertion) port.PasskeyAssertionResponse {
+	return port.PasskeyAssertionResponse{
+		SessionID:         assertion.SessionID,
+		CredentialID:      assertion.CredentialID,
+		ClientDataJSON:    assertion.ClientDataJSON,
+		AuthenticatorData: assertion.AuthenticatorData,
+		Signature:         assertion.Signature,
+		UserHandle:        assertion.UserHandle,
+	}
+}
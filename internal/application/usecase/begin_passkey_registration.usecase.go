@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type beginPasskeyRegistrationUseCase struct {
+	userRepo repository.UserRepository
+	passkeys port.PasskeyRegistrar
+	logger   port.Logger
+}
+
+func NewBeginPasskeyRegistrationUsecase(
+	userRepo repository.UserRepository,
+	passkeys port.PasskeyRegistrar,
+	logger port.Logger,
+) port.BeginPasskeyRegistrationUseCase {
+	return &beginPasskeyRegistrationUseCase{
+		userRepo: userRepo,
+		passkeys: passkeys,
+		logger:   logger,
+	}
+}
+
+func (u *beginPasskeyRegistrationUseCase) Execute(ctx context.Context, input input.BeginPasskeyRegistrationInput) (*output.PasskeyOptionsOutput, error) {
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	ceremony, err := u.passkeys.BeginRegistration(ctx, user)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to start passkey registration", "error", err)
+		return nil, err
+	}
+
+	options := passkeyOptions(ceremony)
+	// The user handle is stored by the authenticator and returned on
+	// passwordless logins, so it must not carry personal data.
+	options.UserHandle = []byte(user.ID.String())
+	options.UserName = user.Username.String()
+	options.UserDisplayName = user.Email.String()
+	return options, nil
+}
+
+func passkeyOptions(ceremony *port.PasskeyCeremony) *output.PasskeyOptionsOutput {
+	return &output.PasskeyOptionsOutput{
+		SessionID:     ceremony.SessionID,
+		Challenge:     ceremony.Challenge,
+		RPID:          ceremony.RPID,
+		RPName:        ceremony.RPName,
+		Algorithms:    ceremony.Algorithms,
+		CredentialIDs: ceremony.CredentialIDs,
+		ExpiresAt:     ceremony.ExpiresAt,
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+const federatedLoginMethod = "federated"
+
+type completeFederatedLoginUseCase struct {
+	providers    port.IdentityProviders
+	loginRepo    repository.FederatedLoginRepository
+	accounts     port.ExternalAccounts
+	logins       port.LoginCompleter
+	metrics      port.AuthMetrics
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewCompleteFederatedLoginUsecase(
+	providers port.IdentityProviders,
+	loginRepo repository.FederatedLoginRepository,
+	accounts port.ExternalAccounts,
+	logins port.LoginCompleter,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.CompleteFederatedLoginUseCase {
+	return &completeFederatedLoginUseCase{
+		providers:    providers,
+		loginRepo:    loginRepo,
+		accounts:     accounts,
+		logins:       logins,
+		metrics:      metrics,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+// Execute finishes a sign-in at an upstream provider. The sign-in is used
+// up before the code is redeemed, so a callback only ever counts once.
+// The identity signs in to the account ExternalAccounts resolves it to.
+func (u *completeFederatedLoginUseCase) Execute(ctx context.Context, input input.CompleteFederatedLoginInput) (*output.LoginOutput, error) {
+	if input.DeviceToken == "" || input.State == "" {
+		return nil, exception.ErrInvalidFederatedLogin
+	}
+
+	login, err := u.loginRepo.FindByStateHash(ctx, u.opaqueTokens.Hash(input.State))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find federated login", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	deviceHash := u.opaqueTokens.Hash(input.DeviceToken)
+	if login == nil || login.IsUsed() || login.IsExpired(now) ||
+		subtle.ConstantTimeCompare([]byte(deviceHash), []byte(login.DeviceHash)) != 1 {
+		return nil, exception.ErrInvalidFederatedLogin
+	}
+	provider := u.providers.Find(login.Provider)
+	if provider == nil {
+		return nil, exception.ErrInvalidFederatedLogin
+	}
+
+	marked, err := u.loginRepo.MarkUsed(ctx, login.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark federated login as used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		return nil, exception.ErrInvalidFederatedLogin
+	}
+
+	if input.Error != "" || input.Code == "" {
+		u.logger.InfoCtx(ctx, "Identity provider did not sign the user in", "provider", provider.ID(), "error", input.Error)
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		return nil, exception.ErrFederatedLoginFailed
+	}
+
+	external, err := provider.Exchange(ctx, input.Code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		u.logger.WarnCtx(ctx, "Failed to redeem identity provider code", "provider", provider.ID(), "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		return nil, exception.ErrFederatedLoginFailed
+	}
+
+	user, err := u.accounts.Resolve(ctx, port.ExternalProvider{ID: provider.ID(), LinkByEmail: provider.LinksByEmail()}, external, input.IPAddress)
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	result, err := u.logins.Complete(ctx, port.LoginAttempt{
+		User:      user,
+		Method:    federatedLoginMethod,
+		Provider:  provider.ID(),
+		IPAddress: input.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loginOutput(user, result), nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type confirmTOTPUseCase struct {
+	totpRepo      repository.TOTPCredentialRepository
+	totp          port.TOTP
+	secrets       port.SecretBox
+	recoveryCodes port.MFARecoveryCodeIssuer
+	txManager     port.TxManager
+	outbox        port.Outbox
+	logger        port.Logger
+}
+
+func NewConfirmTOTPUsecase(
+	totpRepo repository.TOTPCredentialRepository,
+	totp port.TOTP,
+	secrets port.SecretBox,
+	recoveryCodes port.MFARecoveryCodeIssuer,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+) port.ConfirmTOTPUseCase {
+	return &confirmTOTPUseCase{
+		totpRepo:      totpRepo,
+		totp:          totp,
+		secrets:       secrets,
+		recoveryCodes: recoveryCodes,
+		txManager:     txManager,
+		outbox:        outbox,
+		logger:        logger,
+	}
+}
+
+func (u *confirmTOTPUseCase) Execute(ctx context.Context, input input.ConfirmTOTPInput) (*output.ConfirmTOTPOutput, error) {
+	credential, err := u.totpRepo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find TOTP credential", "error", err)
+		return nil, err
+	}
+	if credential == nil {
+		return nil, exception.ErrMFASetupNotStarted
+	}
+	if credential.IsConfirmed() {
+		return nil, exception.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := u.secrets.Open(credential.SecretCiphertext)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to decrypt TOTP secret", "error", err)
+		return nil, err
+	}
+
+	step, ok := u.totp.Validate(string(secret), input.Code, time.Now())
+	if !ok {
+		return nil, exception.ErrInvalidMFACode
+	}
+
+	var codes []string
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		confirmed, err := u.totpRepo.Confirm(ctx, input.UserID, step, time.Now().UTC())
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to confirm TOTP credential", "error", err)
+			return err
+		}
+		if !confirmed {
+			return exception.ErrMFAAlreadyEnabled
+		}
+
+		codes, err = u.recoveryCodes.IssueRecoveryCodes(ctx, input.UserID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to issue recovery codes", "error", err)
+			return err
+		}
+
+		return u.outbox.Publish(ctx, port.OutboxMessage{
+			EventType: event.UserMFAEnabled,
+			DedupKey:  event.DedupKey(event.UserMFAEnabled, credential.ID),
+			Payload: event.NewUserEvent(
+				input.UserID,
+				input.IPAddress,
+				correlationid.FromContext(ctx),
+				map[string]interface{}{"method": port.MFAMethodTOTP},
+			),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "TOTP enabled", "user_id", input.UserID)
+
+	return &output.ConfirmTOTPOutput{
+		RecoveryCodes: codes,
+		Message:       "Two-factor authentication enabled. Store the recovery codes somewhere safe; they are shown only once.",
+	}, nil
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type consumeSAMLResponseUseCase struct {
+	idps          port.SAMLIdentityProviders
+	requestRepo   repository.SAMLRequestRepository
+	assertionRepo repository.SAMLAssertionRepository
+	loginRepo     repository.SAMLLoginRepository
+	accounts      port.ExternalAccounts
+	metrics       port.AuthMetrics
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	loginTTL      time.Duration
+}
+
+func NewConsumeSAMLResponseUsecase(
+	idps port.SAMLIdentityProviders,
+	requestRepo repository.SAMLRequestRepository,
+	assertionRepo repository.SAMLAssertionRepository,
+	loginRepo repository.SAMLLoginRepository,
+	accounts port.ExternalAccounts,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	loginTTL time.Duration,
+) port.ConsumeSAMLResponseUseCase {
+	return &consumeSAMLResponseUseCase{
+		idps:          idps,
+		requestRepo:   requestRepo,
+		assertionRepo: assertionRepo,
+		loginRepo:     loginRepo,
+		accounts:      accounts,
+		metrics:       metrics,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		loginTTL:      loginTTL,
+	}
+}
+
+// Execute accepts a response posted to the assertion consumer service. An
+// assertion that answers a request uses the request up; one that answers
+// none is only accepted from identity providers that allow IdP-initiated
+// sign-ins. Each assertion is accepted once. The identity signs in to the
+// account ExternalAccounts resolves it to, through a short-lived code the
+// client redeems.
+func (u *consumeSAMLResponseUseCase) Execute(ctx context.Context, input input.ConsumeSAMLResponseInput) (*output.ConsumeSAMLResponseOutput, error) {
+	provider := u.idps.Find(input.IdP)
+	if provider == nil {
+		return nil, exception.ErrUnknownIdentityProvider
+	}
+
+	now := time.Now().UTC()
+	assertion, err := provider.ParseResponse(input.SAMLResponse, now)
+	if err != nil {
+		u.logger.WarnCtx(ctx, "Rejected SAML response", "idp", provider.ID(), "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		return nil, exception.ErrInvalidSAMLResponse
+	}
+
+	deviceHash, err := u.consumeRequest(ctx, provider, assertion.InResponseTo, now)
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		return nil, err
+	}
+
+	remembered, err := u.assertionRepo.Remember(ctx, provider.ID(), assertion.ID, assertion.ExpiresAt)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to remember SAML assertion", "error", err)
+		return nil, err
+	}
+	if !remembered {
+		u.logger.WarnCtx(ctx, "Rejected replayed SAML assertion", "idp", provider.ID(), "assertion_id", assertion.ID)
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		return nil, exception.ErrInvalidSAMLResponse
+	}
+
+	user, err := u.accounts.Resolve(ctx, port.ExternalProvider{ID: provider.ID(), LinkByEmail: provider.LinksByEmail()}, &assertion.Identity, input.IPAddress)
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	code, err := u.opaqueTokens.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate SAML login code", "error", err)
+		return nil, err
+	}
+	expiresAt := now.Add(u.loginTTL)
+	login := entity.NewSAMLLogin(u.uuidGenerator.Generate(), provider.ID(), user.ID.String(), u.opaqueTokens.Hash(code), deviceHash, expiresAt)
+	if err := u.loginRepo.Create(ctx, login); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create SAML login", "error", err)
+		return nil, err
+	}
+
+	return &output.ConsumeSAMLResponseOutput{Code: code, ExpiresAt: expiresAt}, nil
+}
+
+// consumeRequest uses up the request the assertion answers and returns
+// the device hash of the client that sent it, empty for an IdP-initiated
+// sign-in.
+func (u *consumeSAMLResponseUseCase) consumeRequest(ctx context.Context, provider port.SAMLIdentityProvider, requestID string, now time.Time) (string, error) {
+	if requestID == "" {
+		if !provider.AllowsIdPInitiated() {
+			u.logger.WarnCtx(ctx, "Rejected unsolicited SAML response", "idp", provider.ID())
+			return "", exception.ErrInvalidSAMLResponse
+		}
+		return "", nil
+	}
+
+	request, err := u.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find SAML request", "error", err)
+		return "", err
+	}
+	if request == nil || request.IdP != provider.ID() || request.IsUsed() || request.IsExpired(now) {
+		return "", exception.ErrInvalidSAMLResponse
+	}
+
+	marked, err := u.requestRepo.MarkUsed(ctx, request.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark SAML request as used", "error", err)
+		return "", err
+	}
+	if !marked {
+		return "", exception.ErrInvalidSAMLResponse
+	}
+	return request.DeviceHash, nil
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+// oauthGrantTypes are the grants a client can be registered for.
+var oauthGrantTypes = []string{
+	entity.OAuthGrantAuthorizationCode,
+	entity.OAuthGrantRefreshToken,
+	entity.OAuthGrantClientCredentials,
+	entity.OAuthGrantDeviceCode,
+	entity.OAuthGrantTokenExchange,
+}
+
+// defaultOAuthGrantTypes are given to a client registered without any.
+var defaultOAuthGrantTypes = []string{entity.OAuthGrantAuthorizationCode, entity.OAuthGrantRefreshToken}
+
+// maxClientJWKSBytes bounds the JWK Set stored for a client.
+const maxClientJWKSBytes = 16 << 10
+
+type createOAuthClientUseCase struct {
+	clientRepo    repository.OAuthClientRepository
+	auditLogger   port.AuditLogger
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	policy        clientMetadataPolicy
+}
+
+func NewCreateOAuthClientUsecase(
+	clientRepo repository.OAuthClientRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	assertions port.ClientAssertionVerifier,
+	scopes []string,
+	maxAccessTokenTTL time.Duration,
+) port.CreateOAuthClientUseCase {
+	return &createOAuthClientUseCase{
+		clientRepo:    clientRepo,
+		auditLogger:   auditLogger,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		policy: clientMetadataPolicy{
+			scopes:            scopes,
+			maxAccessTokenTTL: maxAccessTokenTTL,
+			assertions:        assertions,
+		},
+	}
+}
+
+func (u *createOAuthClientUseCase) Execute(ctx context.Context, input input.CreateOAuthClientInput) (*output.CreateOAuthClientOutput, error) {
+	client, confidential, err := u.policy.check(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if confidential && client.JWKS == "" {
+		secret, err = u.opaqueTokens.Generate()
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to generate client secret", "error", err)
+			return nil, err
+		}
+		client.SecretHash = u.opaqueTokens.Hash(secret)
+	}
+	registrationToken, err := u.opaqueTokens.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate registration access token", "error", err)
+		return nil, err
+	}
+	client.RegistrationTokenHash = u.opaqueTokens.Hash(registrationToken)
+
+	client.ID = u.uuidGenerator.Generate()
+	if err := u.clientRepo.Create(ctx, client); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create OAuth client", "error", err)
+		return nil, err
+	}
+
+	logClientAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthClientCreated, client, input.IPAddress)
+	u.logger.InfoCtx(ctx, "OAuth client created", "client_id", client.ID)
+
+	return &output.CreateOAuthClientOutput{
+		OAuthClientOutput: oauthClientOutput(client),
+		ClientSecret:      secret,
+		RegistrationToken: registrationToken,
+	}, nil
+}
+
+// clientMetadataPolicy checks the metadata a client is registered or
+// updated with, whether an admin or the client itself sends it.
+type clientMetadataPolicy struct {
+	scopes            []string
+	maxAccessTokenTTL time.Duration
+	assertions        port.ClientAssertionVerifier
+}
+
+// check returns the client the metadata describes, without an ID or
+// credentials, and whether it is confidential.
+func (p clientMetadataPolicy) check(input input.CreateOAuthClientInput) (*entity.OAuthClient, bool, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, false, fmt.Errorf("%w: name is required", exception.ErrInvalidClientMetadata)
+	}
+
+	grantTypes := input.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = defaultOAuthGrantTypes
+	}
+	for _, grantType := range grantTypes {
+		if !slices.Contains(oauthGrantTypes, grantType) {
+			return nil, false, fmt.Errorf("%w: grant type %q is not supported", exception.ErrInvalidClientMetadata, grantType)
+		}
+	}
+	usesCode := slices.Contains(grantTypes, entity.OAuthGrantAuthorizationCode)
+	usesClientCredentials := slices.Contains(grantTypes, entity.OAuthGrantClientCredentials)
+	usesDeviceCode := slices.Contains(grantTypes, entity.OAuthGrantDeviceCode)
+	usesTokenExchange := slices.Contains(grantTypes, entity.OAuthGrantTokenExchange)
+	if !usesCode && !usesClientCredentials && !usesDeviceCode && !usesTokenExchange {
+		return nil, false, fmt.Errorf("%w: grant type %q, %q, %q or %q is required", exception.ErrInvalidClientMetadata,
+			entity.OAuthGrantAuthorizationCode, entity.OAuthGrantClientCredentials, entity.OAuthGrantDeviceCode, entity.OAuthGrantTokenExchange)
+	}
+	if !usesCode && !usesDeviceCode && slices.Contains(grantTypes, entity.OAuthGrantRefreshToken) {
+		return nil, false, fmt.Errorf("%w: grant type %q requires %q or %q", exception.ErrInvalidClientMetadata,
+			entity.OAuthGrantRefreshToken, entity.OAuthGrantAuthorizationCode, entity.OAuthGrantDeviceCode)
+	}
+
+	// Redirect URIs only take part in the authorization code grant.
+	if usesCode && len(input.RedirectURIs) == 0 {
+		return nil, false, fmt.Errorf("%w: at least one is required", exception.ErrInvalidClientRedirectURI)
+	}
+	if !usesCode && len(input.RedirectURIs) > 0 {
+		return nil, false, fmt.Errorf("%w: redirect URIs require grant type %q", exception.ErrInvalidClientRedirectURI, entity.OAuthGrantAuthorizationCode)
+	}
+	for _, uri := range input.RedirectURIs {
+		if !validRedirectURI(uri) {
+			return nil, false, fmt.Errorf("%w: %q must be absolute, without a fragment, and use https unless it is a loopback or app URI", exception.ErrInvalidClientRedirectURI, uri)
+		}
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, false, fmt.Errorf("%w: at least one scope is required", exception.ErrInvalidClientMetadata)
+	}
+	for _, scope := range input.Scopes {
+		if !slices.Contains(p.scopes, scope) {
+			return nil, false, fmt.Errorf("%w: scope %q is not supported", exception.ErrInvalidClientMetadata, scope)
+		}
+	}
+
+	if input.JWKS != "" {
+		if len(input.JWKS) > maxClientJWKSBytes {
+			return nil, false, fmt.Errorf("%w: jwks must not exceed %d bytes", exception.ErrInvalidClientMetadata, maxClientJWKSBytes)
+		}
+		if err := p.assertions.ValidateKeySet(input.JWKS); err != nil {
+			return nil, false, fmt.Errorf("%w: %v", exception.ErrInvalidClientMetadata, err)
+		}
+	}
+	confidential := input.Confidential || input.JWKS != ""
+	switch input.AuthMethod {
+	case "":
+	case entity.OAuthAuthMethodNone:
+		if confidential {
+			return nil, false, fmt.Errorf("%w: token endpoint auth method %q cannot be used by a confidential client", exception.ErrInvalidClientMetadata, input.AuthMethod)
+		}
+	case entity.OAuthAuthMethodClientSecretBasic, entity.OAuthAuthMethodClientSecretPost:
+		if input.JWKS != "" {
+			return nil, false, fmt.Errorf("%w: token endpoint auth method %q cannot be used with jwks", exception.ErrInvalidClientMetadata, input.AuthMethod)
+		}
+		confidential = true
+	case entity.OAuthAuthMethodPrivateKeyJWT:
+		if input.JWKS == "" {
+			return nil, false, fmt.Errorf("%w: token endpoint auth method %q requires jwks", exception.ErrInvalidClientMetadata, input.AuthMethod)
+		}
+	default:
+		return nil, false, fmt.Errorf("%w: token endpoint auth method %q is not supported", exception.ErrInvalidClientMetadata, input.AuthMethod)
+	}
+	if usesClientCredentials && !confidential {
+		return nil, false, fmt.Errorf("%w: grant type %q requires a confidential client", exception.ErrInvalidClientMetadata, entity.OAuthGrantClientCredentials)
+	}
+	if usesTokenExchange && !confidential {
+		return nil, false, fmt.Errorf("%w: grant type %q requires a confidential client", exception.ErrInvalidClientMetadata, entity.OAuthGrantTokenExchange)
+	}
+
+	// The audiences a client may exchange tokens for are its whole token
+	// exchange policy, so they must be listed explicitly.
+	if usesTokenExchange && len(input.ExchangeAudiences) == 0 {
+		return nil, false, fmt.Errorf("%w: grant type %q requires at least one exchange audience", exception.ErrInvalidClientMetadata, entity.OAuthGrantTokenExchange)
+	}
+	if !usesTokenExchange && len(input.ExchangeAudiences) > 0 {
+		return nil, false, fmt.Errorf("%w: exchange audiences require grant type %q", exception.ErrInvalidClientMetadata, entity.OAuthGrantTokenExchange)
+	}
+	for _, audience := range input.ExchangeAudiences {
+		if audience == "" || strings.ContainsAny(audience, " \t\r\n") {
+			return nil, false, fmt.Errorf("%w: exchange audience %q must be a non-empty name without spaces", exception.ErrInvalidClientMetadata, audience)
+		}
+	}
+
+	// Logging out only concerns clients that sign users in.
+	hasLogoutURIs := len(input.PostLogoutRedirectURIs) > 0 || input.BackchannelLogoutURI != "" || input.FrontchannelLogoutURI != ""
+	if hasLogoutURIs && !usesCode && !usesDeviceCode {
+		return nil, false, fmt.Errorf("%w: logout URIs require grant type %q or %q", exception.ErrInvalidClientMetadata,
+			entity.OAuthGrantAuthorizationCode, entity.OAuthGrantDeviceCode)
+	}
+	for _, uri := range input.PostLogoutRedirectURIs {
+		if !validRedirectURI(uri) {
+			return nil, false, fmt.Errorf("%w: post-logout redirect URI %q must be absolute, without a fragment, and use https unless it is a loopback or app URI", exception.ErrInvalidClientMetadata, uri)
+		}
+	}
+	for _, uri := range []string{input.BackchannelLogoutURI, input.FrontchannelLogoutURI} {
+		if uri != "" && !validLogoutURI(uri) {
+			return nil, false, fmt.Errorf("%w: logout URI %q must be an absolute https URL without a fragment, or http on a loopback host", exception.ErrInvalidClientMetadata, uri)
+		}
+	}
+
+	if input.AccessTokenTTL < 0 || input.AccessTokenTTL > p.maxAccessTokenTTL {
+		return nil, false, fmt.Errorf("%w: access token lifetime must be between 0 and %s", exception.ErrInvalidClientMetadata, p.maxAccessTokenTTL)
+	}
+
+	client := entity.NewOAuthClient("", name, "", input.RedirectURIs, input.Scopes, grantTypes)
+	client.JWKS = input.JWKS
+	client.ExchangeAudiences = input.ExchangeAudiences
+	client.AccessTokenTTL = input.AccessTokenTTL
+	client.FirstParty = input.FirstParty
+	client.PostLogoutRedirectURIs = input.PostLogoutRedirectURIs
+	client.BackchannelLogoutURI = input.BackchannelLogoutURI
+	client.FrontchannelLogoutURI = input.FrontchannelLogoutURI
+	return client, confidential, nil
+}
+
+// oauthClientOutput describes a client's metadata, without credentials.
+func oauthClientOutput(client *entity.OAuthClient) output.OAuthClientOutput {
+	return output.OAuthClientOutput{
+		ClientID:          client.ID,
+		Name:              client.Name,
+		RedirectURIs:      client.RedirectURIs,
+		Scopes:            client.Scopes,
+		GrantTypes:        client.GrantTypes,
+		ExchangeAudiences: client.ExchangeAudiences,
+		AuthMethod:        client.AuthMethod(),
+		AccessTokenTTL:    client.AccessTokenTTL,
+		FirstParty:        client.FirstParty,
+		CreatedAt:         client.CreatedAt,
+
+		PostLogoutRedirectURIs: client.PostLogoutRedirectURIs,
+		BackchannelLogoutURI:   client.BackchannelLogoutURI,
+		FrontchannelLogoutURI:  client.FrontchannelLogoutURI,
+	}
+}
+
+// logClientAudit records a change to a client's registration.
+func logClientAudit(ctx context.Context, auditLogger port.AuditLogger, logger port.Logger, action entity.AuditAction, client *entity.OAuthClient, ipAddress string) {
+	details := map[string]interface{}{
+		"client_id":    client.ID,
+		"name":         client.Name,
+		"confidential": client.IsConfidential(),
+		"auth_method":  client.AuthMethod(),
+		"grant_types":  client.GrantTypes,
+	}
+	if len(client.ExchangeAudiences) > 0 {
+		details["exchange_audiences"] = client.ExchangeAudiences
+	}
+	if client.FirstParty {
+		details["first_party"] = true
+	}
+	auditLog, err := entity.NewAuditLog(action, nil, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+	auditLogger.Log(ctx, auditLog)
+}
+
+// validRedirectURI accepts absolute URIs without a fragment. Plain http is
+// only allowed for loopback hosts, where native apps listen; any other
+// scheme apart from script and data URLs is taken as a native app's
+// private-use scheme.
+func validRedirectURI(raw string) bool {
+	if strings.Contains(raw, "#") {
+		return false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	case "javascript", "data", "vbscript", "file":
+		return false
+	default:
+		return true
+	}
+}
+
+// validLogoutURI accepts the URIs this service calls or frames on logout:
+// web URLs only, as apps cannot receive either.
+func validLogoutURI(raw string) bool {
+	if strings.Contains(raw, "#") {
+		return false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return false
+	}
+}
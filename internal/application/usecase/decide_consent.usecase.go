@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type decideConsentUseCase struct {
+	clientRepo    repository.OAuthClientRepository
+	userRepo      repository.UserRepository
+	consentRepo   repository.UserConsentRepository
+	challengeRepo repository.ConsentChallengeRepository
+	auditLogger   port.AuditLogger
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	codes         authorizationCodeIssuer
+
+	scopes []string
+}
+
+func NewDecideConsentUsecase(
+	clientRepo repository.OAuthClientRepository,
+	codeRepo repository.AuthorizationCodeRepository,
+	userRepo repository.UserRepository,
+	consentRepo repository.UserConsentRepository,
+	challengeRepo repository.ConsentChallengeRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	scopes []string,
+	codeTTL time.Duration,
+) port.DecideConsentUseCase {
+	return &decideConsentUseCase{
+		clientRepo:    clientRepo,
+		userRepo:      userRepo,
+		consentRepo:   consentRepo,
+		challengeRepo: challengeRepo,
+		auditLogger:   auditLogger,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		codes: authorizationCodeIssuer{
+			codeRepo:      codeRepo,
+			auditLogger:   auditLogger,
+			logger:        logger,
+			opaqueTokens:  opaqueTokens,
+			uuidGenerator: uuidGenerator,
+			codeTTL:       codeTTL,
+		},
+
+		scopes: scopes,
+	}
+}
+
+// Execute records the user's decision on the consent page. Approval adds
+// the requested scopes to what the user has granted the client and issues
+// the authorization code; either way the challenge is used up.
+func (u *decideConsentUseCase) Execute(ctx context.Context, req input.DecideConsentInput) (*output.AuthorizeOutput, error) {
+	challenge, err := findConsentChallenge(ctx, u.challengeRepo, u.logger, u.opaqueTokens, req.ConsentToken)
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := u.challengeRepo.MarkUsed(ctx, challenge.ID, time.Now().UTC())
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark consent challenge used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		return nil, exception.ErrInvalidConsentToken
+	}
+
+	// The client may have changed while the page was open, so the request
+	// is checked once more before anything is sent to its redirect URI.
+	client, scopes, err := validateAuthorizationRequest(ctx, u.clientRepo, u.logger, u.scopes, input.AuthorizationRequest{
+		ResponseType:        oauthResponseTypeCode,
+		ClientID:            challenge.ClientID,
+		RedirectURI:         challenge.RedirectURI,
+		Scope:               entity.FormatScope(challenge.Scopes),
+		State:               challenge.State,
+		Nonce:               challenge.Nonce,
+		CodeChallenge:       challenge.CodeChallenge,
+		CodeChallengeMethod: entity.PKCEMethodS256,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Approve {
+		logOAuthAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthConsentDenied, challenge.UserID, client.ID, scopes, req.IPAddress)
+		return &output.AuthorizeOutput{
+			RedirectURI: challenge.RedirectURI,
+			State:       challenge.State,
+			Denied:      true,
+		}, nil
+	}
+
+	user, err := findActiveUser(ctx, u.userRepo, u.logger, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.ID.String()
+
+	consent, err := u.consentRepo.Find(ctx, userID, client.ID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find consent", "error", err)
+		return nil, err
+	}
+	if consent == nil {
+		consent = entity.NewUserConsent(userID, client.ID, scopes)
+	} else {
+		consent.Grant(scopes, time.Now().UTC())
+	}
+	if err := u.consentRepo.Save(ctx, consent); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to save consent", "error", err)
+		return nil, err
+	}
+	logOAuthAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthConsentGranted, userID, client.ID, scopes, req.IPAddress)
+
+	return u.codes.issue(ctx, client.ID, userID, scopes, challenge.RedirectURI, challenge.State,
+		challenge.Nonce, challenge.CodeChallenge, req.IPAddress)
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type decideDeviceAuthorizationUseCase struct {
+	deviceRepo   repository.DeviceAuthorizationRepository
+	clientRepo   repository.OAuthClientRepository
+	userRepo     repository.UserRepository
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewDecideDeviceAuthorizationUsecase(
+	deviceRepo repository.DeviceAuthorizationRepository,
+	clientRepo repository.OAuthClientRepository,
+	userRepo repository.UserRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.DecideDeviceAuthorizationUseCase {
+	return &decideDeviceAuthorizationUseCase{
+		deviceRepo:   deviceRepo,
+		clientRepo:   clientRepo,
+		userRepo:     userRepo,
+		auditLogger:  auditLogger,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+// Execute records the signed-in user's answer to a device request. The
+// device learns of it on its next poll, and only an approval lets it
+// obtain tokens, once, for that user.
+func (u *decideDeviceAuthorizationUseCase) Execute(ctx context.Context, req input.DecideDeviceAuthorizationInput) error {
+	authorization, client, err := findPendingDeviceAuthorization(ctx, u.deviceRepo, u.clientRepo, u.logger, u.opaqueTokens, req.UserCode)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return err
+	}
+	if user == nil {
+		return exception.ErrUserNotFound
+	}
+	if !user.IsActive {
+		return exception.ErrUserInactive
+	}
+
+	status, action := entity.DeviceAuthorizationDenied, entity.AuditActionOAuthDeviceDenied
+	if req.Approve {
+		status, action = entity.DeviceAuthorizationApproved, entity.AuditActionOAuthAuthorized
+	}
+
+	decided, err := u.deviceRepo.Decide(ctx, authorization.ID, req.UserID, status, time.Now().UTC())
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to record device authorization decision", "error", err)
+		return err
+	}
+	if !decided {
+		return exception.ErrInvalidUserCode
+	}
+
+	details := map[string]interface{}{
+		"client_id":  client.ID,
+		"grant_type": entity.OAuthGrantDeviceCode,
+		"scope":      entity.FormatScope(authorization.Scopes),
+	}
+	auditLog, err := entity.NewAuditLog(action, &req.UserID, details, req.IPAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+	} else {
+		u.auditLogger.Log(ctx, auditLog)
+	}
+
+	u.logger.InfoCtx(ctx, "Device authorization decided", "user_id", req.UserID, "client_id", client.ID, "status", string(status))
+	return nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type deleteClientRegistrationUseCase struct {
+	clientRepo   repository.OAuthClientRepository
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewDeleteClientRegistrationUsecase(
+	clientRepo repository.OAuthClientRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.DeleteClientRegistrationUseCase {
+	return &deleteClientRegistrationUseCase{
+		clientRepo:   clientRepo,
+		auditLogger:  auditLogger,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+// Execute deletes the client along with its outstanding codes and refresh
+// tokens. Access tokens already issued to it stay valid until they expire.
+func (u *deleteClientRegistrationUseCase) Execute(ctx context.Context, req input.ClientRegistrationInput) error {
+	client, err := findRegisteredClient(ctx, u.clientRepo, u.logger, u.opaqueTokens, req.ClientID, req.RegistrationToken)
+	if err != nil {
+		return err
+	}
+
+	if err := u.clientRepo.Delete(ctx, client.ID); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to delete OAuth client", "error", err)
+		return err
+	}
+
+	logClientAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthClientDeleted, client, req.IPAddress)
+	u.logger.InfoCtx(ctx, "OAuth client deleted", "client_id", client.ID)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type deletePasskeyUseCase struct {
+	userRepo       repository.UserRepository
+	credentialRepo repository.WebAuthnCredentialRepository
+	recoveryRepo   repository.MFARecoveryCodeRepository
+	hasher         port.PasswordHasher
+	enrollment     port.MFAEnrollment
+	txManager      port.TxManager
+	outbox         port.Outbox
+	logger         port.Logger
+}
+
+func NewDeletePasskeyUsecase(
+	userRepo repository.UserRepository,
+	credentialRepo repository.WebAuthnCredentialRepository,
+	recoveryRepo repository.MFARecoveryCodeRepository,
+	hasher port.PasswordHasher,
+	enrollment port.MFAEnrollment,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+) port.DeletePasskeyUseCase {
+	return &deletePasskeyUseCase{
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		recoveryRepo:   recoveryRepo,
+		hasher:         hasher,
+		enrollment:     enrollment,
+		txManager:      txManager,
+		outbox:         outbox,
+		logger:         logger,
+	}
+}
+
+func (u *deletePasskeyUseCase) Execute(ctx context.Context, input input.DeletePasskeyInput) (*output.DeletePasskeyOutput, error) {
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	// As with the authenticator app, removing a factor needs more than
+	// an access token.
+	ok, err := u.hasher.Verify(input.Password, user.PasswordHash)
+	if err != nil {
+		u.logger.WarnCtx(ctx, "Failed to verify password", "user_id", input.UserID, "error", err)
+	}
+	if !ok {
+		return nil, exception.ErrInvalidCredentials
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := u.credentialRepo.Delete(ctx, input.UserID, input.PasskeyID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to delete passkey", "error", err)
+			return err
+		}
+		if !deleted {
+			return exception.ErrPasskeyNotFound
+		}
+
+		enrolled, err := u.enrollment.HasSecondFactor(ctx, input.UserID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to check remaining second factors", "error", err)
+			return err
+		}
+		if !enrolled {
+			if err := u.recoveryRepo.DeleteByUserID(ctx, input.UserID); err != nil {
+				u.logger.ErrorCtx(ctx, "Failed to delete recovery codes", "error", err)
+				return err
+			}
+		}
+
+		return u.outbox.Publish(ctx, port.OutboxMessage{
+			EventType: event.UserPasskeyRemoved,
+			DedupKey:  event.DedupKey(event.UserPasskeyRemoved, input.PasskeyID),
+			Payload: event.NewUserEvent(
+				input.UserID,
+				input.IPAddress,
+				correlationid.FromContext(ctx),
+				map[string]interface{}{"passkey_id": input.PasskeyID},
+			),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Passkey removed", "user_id", input.UserID, "passkey_id", input.PasskeyID)
+
+	return &output.DeletePasskeyOutput{
+		Message: "Passkey removed",
+	}, nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type disableTOTPUseCase struct {
+	userRepo     repository.UserRepository
+	totpRepo     repository.TOTPCredentialRepository
+	recoveryRepo repository.MFARecoveryCodeRepository
+	hasher       port.PasswordHasher
+	verifier     port.MFAVerifier
+	enrollment   port.MFAEnrollment
+	txManager    port.TxManager
+	outbox       port.Outbox
+	logger       port.Logger
+}
+
+func NewDisableTOTPUsecase(
+	userRepo repository.UserRepository,
+	totpRepo repository.TOTPCredentialRepository,
+	recoveryRepo repository.MFARecoveryCodeRepository,
+	hasher port.PasswordHasher,
+	verifier port.MFAVerifier,
+	enrollment port.MFAEnrollment,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+) port.DisableTOTPUseCase {
+	return &disableTOTPUseCase{
+		userRepo:     userRepo,
+		totpRepo:     totpRepo,
+		recoveryRepo: recoveryRepo,
+		hasher:       hasher,
+		verifier:     verifier,
+		enrollment:   enrollment,
+		txManager:    txManager,
+		outbox:       outbox,
+		logger:       logger,
+	}
+}
+
+func (u *disableTOTPUseCase) Execute(ctx context.Context, input input.DisableTOTPInput) (*output.DisableTOTPOutput, error) {
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	// A stolen access token alone must not be enough to remove the
+	// second factor.
+	ok, err := u.hasher.Verify(input.Password, user.PasswordHash)
+	if err != nil {
+		u.logger.WarnCtx(ctx, "Failed to verify password", "user_id", input.UserID, "error", err)
+	}
+	if !ok {
+		return nil, exception.ErrInvalidCredentials
+	}
+
+	credential, err := u.totpRepo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find TOTP credential", "error", err)
+		return nil, err
+	}
+	if credential == nil || !credential.IsConfirmed() {
+		return nil, exception.ErrMFANotEnabled
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		method, err := u.verifier.VerifyCode(ctx, input.UserID, input.Code)
+		if err != nil {
+			return err
+		}
+
+		if err := u.totpRepo.Delete(ctx, input.UserID); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to delete TOTP credential", "error", err)
+			return err
+		}
+
+		// Recovery codes stay while a passkey still needs backing up.
+		enrolled, err := u.enrollment.HasSecondFactor(ctx, input.UserID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to check remaining second factors", "error", err)
+			return err
+		}
+		if !enrolled {
+			if err := u.recoveryRepo.DeleteByUserID(ctx, input.UserID); err != nil {
+				u.logger.ErrorCtx(ctx, "Failed to delete recovery codes", "error", err)
+				return err
+			}
+		}
+
+		return u.outbox.Publish(ctx, port.OutboxMessage{
+			EventType: event.UserMFADisabled,
+			DedupKey:  event.DedupKey(event.UserMFADisabled, credential.ID),
+			Payload: event.NewUserEvent(
+				input.UserID,
+				input.IPAddress,
+				correlationid.FromContext(ctx),
+				map[string]interface{}{"method": port.MFAMethodTOTP, "verified_with": method},
+			),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "TOTP disabled", "user_id", input.UserID)
+
+	return &output.DisableTOTPOutput{
+		Message: "Two-factor authentication disabled",
+	}, nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type endSessionUseCase struct {
+	clientRepo  repository.OAuthClientRepository
+	sessionRepo repository.OAuthSessionRepository
+	refreshRepo repository.RefreshTokenRepository
+	idTokens    port.IDTokenVerifier
+	txManager   port.TxManager
+	outbox      port.Outbox
+	auditLogger port.AuditLogger
+	logger      port.Logger
+}
+
+func NewEndSessionUsecase(
+	clientRepo repository.OAuthClientRepository,
+	sessionRepo repository.OAuthSessionRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	idTokens port.IDTokenVerifier,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+) port.EndSessionUseCase {
+	return &endSessionUseCase{
+		clientRepo:  clientRepo,
+		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
+		idTokens:    idTokens,
+		txManager:   txManager,
+		outbox:      outbox,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// Execute logs the user out of every client, not just the one asking:
+// all their sessions end and all their refresh tokens are revoked. Clients
+// with a back-channel logout URI are sent a logout token through the
+// outbox, which retries until they accept it; those with a front-channel
+// logout URI are returned for the logout page to load. Access tokens
+// already issued run out on their own.
+//
+// The service keeps no browser session of its own, so the user can only
+// be told apart by the ID token the client sends as id_token_hint.
+func (u *endSessionUseCase) Execute(ctx context.Context, req input.EndSessionInput) (*output.EndSessionOutput, error) {
+	if req.IDTokenHint == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "id_token_hint is required")
+	}
+	hint, err := u.idTokens.VerifyIDTokenHint(req.IDTokenHint)
+	if err != nil {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "id_token_hint is invalid")
+	}
+	if req.ClientID != "" && req.ClientID != hint.Audience {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "client_id does not match id_token_hint")
+	}
+
+	client, err := u.clientRepo.FindByID(ctx, hint.Audience)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+		return nil, err
+	}
+	if client == nil {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "id_token_hint was issued to an unknown client")
+	}
+	if req.PostLogoutRedirectURI != "" && !client.HasPostLogoutRedirectURI(req.PostLogoutRedirectURI) {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "post_logout_redirect_uri is not registered for the client")
+	}
+
+	var ended []*entity.OAuthSession
+	var frontchannel []output.FrontchannelLogout
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		sessions, err := u.sessionRepo.ListActiveByUserID(ctx, hint.Subject)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to list OAuth sessions", "error", err)
+			return err
+		}
+
+		now := time.Now().UTC()
+		if err := u.sessionRepo.EndByUserID(ctx, hint.Subject, now); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to end OAuth sessions", "error", err)
+			return err
+		}
+		if err := u.refreshRepo.RevokeByUserID(ctx, hint.Subject, now); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to revoke refresh tokens", "error", err)
+			return err
+		}
+
+		ended, frontchannel = sessions, nil
+		for _, session := range sessions {
+			sessionClient, err := u.clientRepo.FindByID(ctx, session.ClientID)
+			if err != nil {
+				u.logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+				return err
+			}
+			if sessionClient == nil {
+				continue
+			}
+
+			if sessionClient.BackchannelLogoutURI != "" {
+				err := u.outbox.Publish(ctx, port.OutboxMessage{
+					EventType: event.OIDCBackchannelLogout,
+					DedupKey:  event.DedupKey(event.OIDCBackchannelLogout, session.ID),
+					Payload: event.BackchannelLogoutEvent{
+						ClientID:   sessionClient.ID,
+						UserID:     session.UserID,
+						SessionID:  session.ID,
+						LogoutURI:  sessionClient.BackchannelLogoutURI,
+						OccurredAt: now,
+					},
+				})
+				if err != nil {
+					u.logger.ErrorCtx(ctx, "Failed to publish back-channel logout", "error", err)
+					return err
+				}
+			}
+			if sessionClient.FrontchannelLogoutURI != "" {
+				frontchannel = append(frontchannel, output.FrontchannelLogout{
+					URI:       sessionClient.FrontchannelLogoutURI,
+					SessionID: session.ID,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logAudit(ctx, hint.Subject, client.ID, len(ended), req.IPAddress)
+	u.logger.InfoCtx(ctx, "User logged out", "user_id", hint.Subject, "client_id", client.ID, "sessions", len(ended))
+
+	return &output.EndSessionOutput{
+		PostLogoutRedirectURI: req.PostLogoutRedirectURI,
+		State:                 req.State,
+		FrontchannelLogouts:   frontchannel,
+	}, nil
+}
+
+func (u *endSessionUseCase) logAudit(ctx context.Context, userID, clientID string, sessions int, ipAddress string) {
+	details := map[string]interface{}{
+		"client_id": clientID,
+		"sessions":  sessions,
+	}
+	auditLog, err := entity.NewAuditLog(entity.AuditActionOIDCLogout, &userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+	u.auditLogger.Log(ctx, auditLog)
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type finishPasskeyLoginUseCase struct {
+	userRepo    repository.UserRepository
+	passkeys    port.PasskeyAuthenticator
+	logins      port.LoginCompleter
+	auditLogger port.AuditLogger
+	metrics     port.AuthMetrics
+	logger      port.Logger
+
+	requireVerifiedEmail bool
+}
+
+func NewFinishPasskeyLoginUsecase(
+	userRepo repository.UserRepository,
+	passkeys port.PasskeyAuthenticator,
+	logins port.LoginCompleter,
+	auditLogger port.AuditLogger,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	requireVerifiedEmail bool,
+) port.FinishPasskeyLoginUseCase {
+	return &finishPasskeyLoginUseCase{
+		userRepo:    userRepo,
+		passkeys:    passkeys,
+		logins:      logins,
+		auditLogger: auditLogger,
+		metrics:     metrics,
+		logger:      logger,
+
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
+}
+
+func (u *finishPasskeyLoginUseCase) Execute(ctx context.Context, input input.FinishPasskeyLoginInput) (*output.LoginOutput, error) {
+	// A passkey replaces both the password and the second factor, so the
+	// authenticator must have verified the user, not just their presence.
+	credential, err := u.passkeys.VerifyAssertion(ctx, passkeyAssertionResponse(input.Assertion), "", true)
+	if err != nil {
+		if errors.Is(err, exception.ErrPasskeySignCount) {
+			u.logger.WarnCtx(ctx, "Passkey signature counter did not increase; the credential may be cloned", "error", err)
+		}
+		if isPasskeyRejection(err) {
+			u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+			u.logAudit(ctx, entity.AuditActionUserLoginFailed, nil, input.IPAddress, map[string]interface{}{
+				"method": port.MFAMethodPasskey,
+				"reason": "invalid_passkey",
+			})
+			return nil, exception.ErrInvalidPasskey
+		}
+		u.logger.ErrorCtx(ctx, "Failed to verify passkey", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindByID(ctx, credential.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrInvalidPasskey
+	}
+
+	result, err := u.logins.Complete(ctx, port.LoginAttempt{
+		User:                 user,
+		Method:               port.MFAMethodPasskey,
+		Details:              map[string]interface{}{"passkey_id": credential.ID},
+		IPAddress:            input.IPAddress,
+		SkipMFA:              true,
+		RequireVerifiedEmail: u.requireVerifiedEmail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loginOutput(user, result), nil
+}
+
+func (u *finishPasskeyLoginUseCase) logAudit(ctx context.Context, action entity.AuditAction, user *entity.User, ipAddress string, details map[string]interface{}) {
+	var userID *string
+	if user != nil {
+		id := user.ID.String()
+		userID = &id
+	}
+
+	auditLog, err := entity.NewAuditLog(action, userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
+
+// isPasskeyRejection reports whether err means the passkey response was
+// refused, as opposed to a failure on our side.
+func isPasskeyRejection(err error) bool {
+	return errors.Is(err, exception.ErrInvalidPasskey) ||
+		errors.Is(err, exception.ErrInvalidWebAuthnSession) ||
+		errors.Is(err, exception.ErrPasskeySignCount)
+}
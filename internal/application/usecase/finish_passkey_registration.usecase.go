@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+const defaultPasskeyName = "Passkey"
+
+type finishPasskeyRegistrationUseCase struct {
+	passkeys      port.PasskeyRegistrar
+	enrollment    port.MFAEnrollment
+	recoveryCodes port.MFARecoveryCodeIssuer
+	txManager     port.TxManager
+	outbox        port.Outbox
+	logger        port.Logger
+}
+
+func NewFinishPasskeyRegistrationUsecase(
+	passkeys port.PasskeyRegistrar,
+	enrollment port.MFAEnrollment,
+	recoveryCodes port.MFARecoveryCodeIssuer,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+) port.FinishPasskeyRegistrationUseCase {
+	return &finishPasskeyRegistrationUseCase{
+		passkeys:      passkeys,
+		enrollment:    enrollment,
+		recoveryCodes: recoveryCodes,
+		txManager:     txManager,
+		outbox:        outbox,
+		logger:        logger,
+	}
+}
+
+func (u *finishPasskeyRegistrationUseCase) Execute(ctx context.Context, input input.FinishPasskeyRegistrationInput) (*output.FinishPasskeyRegistrationOutput, error) {
+	name := input.Name
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	var credential *entity.WebAuthnCredential
+	var codes []string
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		enrolled, err := u.enrollment.HasSecondFactor(ctx, input.UserID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to check second factors", "error", err)
+			return err
+		}
+
+		credential, err = u.passkeys.FinishRegistration(ctx, input.UserID, name, port.PasskeyAttestationResponse{
+			SessionID:         input.SessionID,
+			ClientDataJSON:    input.ClientDataJSON,
+			AttestationObject: input.AttestationObject,
+			Transports:        input.Transports,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The first second factor comes with recovery codes, exactly as
+		// when an authenticator app is confirmed.
+		if !enrolled {
+			codes, err = u.recoveryCodes.IssueRecoveryCodes(ctx, input.UserID)
+			if err != nil {
+				u.logger.ErrorCtx(ctx, "Failed to issue recovery codes", "error", err)
+				return err
+			}
+		}
+
+		return u.outbox.Publish(ctx, port.OutboxMessage{
+			EventType: event.UserPasskeyAdded,
+			DedupKey:  event.DedupKey(event.UserPasskeyAdded, credential.ID),
+			Payload: event.NewUserEvent(
+				input.UserID,
+				input.IPAddress,
+				correlationid.FromContext(ctx),
+				map[string]interface{}{
+					"passkey_id":         credential.ID,
+					"attestation_format": credential.AttestationFormat,
+				},
+			),
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, exception.ErrInvalidPasskey) &&
+			!errors.Is(err, exception.ErrInvalidWebAuthnSession) &&
+			!errors.Is(err, exception.ErrPasskeyAlreadyRegistered) {
+			u.logger.ErrorCtx(ctx, "Failed to register passkey", "error", err)
+		}
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Passkey registered", "user_id", input.UserID, "passkey_id", credential.ID)
+
+	result := &output.FinishPasskeyRegistrationOutput{
+		Passkey:       toPasskeyOutput(credential),
+		RecoveryCodes: codes,
+		Message:       "Passkey registered",
+	}
+	if len(codes) > 0 {
+		result.Message = "Passkey registered. Store the recovery codes somewhere safe; they are shown only once."
+	}
+	return result, nil
+}
+
+func toPasskeyOutput(credential *entity.WebAuthnCredential) output.PasskeyOutput {
+	return output.PasskeyOutput{
+		ID:             credential.ID,
+		Name:           credential.Name,
+		CreatedAt:      credential.CreatedAt,
+		LastUsedAt:     credential.LastUsedAt,
+		BackupEligible: credential.BackupEligible,
+		BackupState:    credential.BackupState,
+		Transports:     credential.Transports,
+	}
+}
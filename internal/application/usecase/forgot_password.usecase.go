@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+const forgotPasswordMessage = "If an account with that email exists, a password reset link has been sent"
+
+type forgotPasswordUseCase struct {
+	userRepo      repository.UserRepository
+	outbox        port.Outbox
+	logger        port.Logger
+	uuidGenerator port.UUIDGenerator
+	metrics       port.AuthMetrics
+}
+
+func NewForgotPasswordUsecase(
+	userRepo repository.UserRepository,
+	outbox port.Outbox,
+	logger port.Logger,
+	uuidGenerator port.UUIDGenerator,
+	metrics port.AuthMetrics,
+) port.ForgotPasswordUseCase {
+	return &forgotPasswordUseCase{
+		userRepo:      userRepo,
+		outbox:        outbox,
+		logger:        logger,
+		uuidGenerator: uuidGenerator,
+		metrics:       metrics,
+	}
+}
+
+// Execute returns the same output whether or not the email belongs to an
+// account, so the endpoint cannot be used to enumerate users. The link is
+// mailed through the outbox, so the response does not take longer when it
+// is sent.
+func (u *forgotPasswordUseCase) Execute(ctx context.Context, input input.ForgotPasswordInput) (*output.ForgotPasswordOutput, error) {
+	u.metrics.RecordPasswordReset()
+
+	user, err := u.userRepo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+
+	if user == nil || !user.IsActive {
+		u.logger.InfoCtx(ctx, "Password reset requested for unknown or inactive account")
+		return &output.ForgotPasswordOutput{Message: forgotPasswordMessage}, nil
+	}
+
+	err = u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserPasswordResetRequested,
+		DedupKey:  event.DedupKey(event.UserPasswordResetRequested, u.uuidGenerator.Generate()),
+		Payload:   event.NewUserEvent(user.ID.String(), input.IPAddress, correlationid.FromContext(ctx), nil),
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to publish password reset request", "user_id", user.ID.String(), "error", err)
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Password reset requested", "user_id", user.ID.String())
+
+	return &output.ForgotPasswordOutput{Message: forgotPasswordMessage}, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+type getSAMLMetadataUseCase struct {
+	idps port.SAMLIdentityProviders
+}
+
+func NewGetSAMLMetadataUsecase(idps port.SAMLIdentityProviders) port.GetSAMLMetadataUseCase {
+	return &getSAMLMetadataUseCase{idps: idps}
+}
+
+// Execute returns the service provider metadata to register with the
+// identity provider.
+func (u *getSAMLMetadataUseCase) Execute(ctx context.Context, idp string) ([]byte, error) {
+	provider := u.idps.Find(idp)
+	if provider == nil {
+		return nil, exception.ErrUnknownIdentityProvider
+	}
+	return provider.Metadata(), nil
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// token_type_hint values from RFC 7009 section 2.1.
+const (
+	tokenTypeHintAccessToken  = "access_token"
+	tokenTypeHintRefreshToken = "refresh_token"
+)
+
+type introspectTokenUseCase struct {
+	clients      port.ClientAuthenticator
+	refreshRepo  repository.RefreshTokenRepository
+	tokens       port.TokenService
+	revocations  port.AccessTokenRevocations
+	opaqueTokens port.OpaqueTokenGenerator
+	logger       port.Logger
+}
+
+func NewIntrospectTokenUsecase(
+	clients port.ClientAuthenticator,
+	refreshRepo repository.RefreshTokenRepository,
+	tokens port.TokenService,
+	revocations port.AccessTokenRevocations,
+	opaqueTokens port.OpaqueTokenGenerator,
+	logger port.Logger,
+) port.IntrospectTokenUseCase {
+	return &introspectTokenUseCase{
+		clients:      clients,
+		refreshRepo:  refreshRepo,
+		tokens:       tokens,
+		revocations:  revocations,
+		opaqueTokens: opaqueTokens,
+		logger:       logger,
+	}
+}
+
+// Execute lets confidential clients, typically resource servers, check any
+// access token issued to a client, including those exchanged for another
+// audience. Refresh tokens are only described to the client holding them.
+// An access token is checked without a database query: its signature
+// locally and its revocation in memory.
+func (u *introspectTokenUseCase) Execute(ctx context.Context, req input.IntrospectTokenInput) (*output.IntrospectTokenOutput, error) {
+	client, err := authenticateClient(ctx, u.clients, u.logger, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	if !client.IsConfidential() {
+		return nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "public clients may not introspect tokens")
+	}
+	if req.Token == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "token is required")
+	}
+
+	if req.TokenTypeHint != tokenTypeHintRefreshToken {
+		if out := u.accessToken(req.Token); out != nil {
+			return out, nil
+		}
+	}
+
+	out, err := u.refreshToken(ctx, client, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil {
+		return out, nil
+	}
+
+	if req.TokenTypeHint == tokenTypeHintRefreshToken {
+		if out := u.accessToken(req.Token); out != nil {
+			return out, nil
+		}
+	}
+	return &output.IntrospectTokenOutput{Active: false}, nil
+}
+
+func (u *introspectTokenUseCase) accessToken(token string) *output.IntrospectTokenOutput {
+	claims, err := u.tokens.InspectAccessToken(token)
+	if err != nil || u.revocations.IsRevoked(claims) {
+		return nil
+	}
+
+	return &output.IntrospectTokenOutput{
+		Active:    true,
+		TokenType: tokenTypeBearer,
+		ClientID:  claims.ClientID,
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Scopes:    claims.Scopes,
+		Audience:  claims.Audience,
+		Actor:     claims.Actor,
+		TokenID:   claims.ID,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+	}
+}
+
+func (u *introspectTokenUseCase) refreshToken(ctx context.Context, client *entity.OAuthClient, token string) (*output.IntrospectTokenOutput, error) {
+	stored, err := u.refreshRepo.FindByHash(ctx, u.opaqueTokens.Hash(token))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find refresh token", "error", err)
+		return nil, err
+	}
+	if stored == nil || stored.ClientID != client.ID ||
+		stored.IsUsed() || stored.IsRevoked() || stored.IsExpired(time.Now().UTC()) {
+		return nil, nil
+	}
+
+	return &output.IntrospectTokenOutput{
+		Active:    true,
+		ClientID:  stored.ClientID,
+		UserID:    stored.UserID,
+		Scopes:    stored.Scopes,
+		IssuedAt:  stored.CreatedAt,
+		ExpiresAt: stored.ExpiresAt,
+	}, nil
+}
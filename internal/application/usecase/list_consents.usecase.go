@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type listConsentsUseCase struct {
+	consentRepo repository.UserConsentRepository
+	clientRepo  repository.OAuthClientRepository
+	logger      port.Logger
+}
+
+func NewListConsentsUsecase(
+	consentRepo repository.UserConsentRepository,
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+) port.ListConsentsUseCase {
+	return &listConsentsUseCase{
+		consentRepo: consentRepo,
+		clientRepo:  clientRepo,
+		logger:      logger,
+	}
+}
+
+func (u *listConsentsUseCase) Execute(ctx context.Context, req input.ListConsentsInput) (*output.ListConsentsOutput, error) {
+	consents, err := u.consentRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to list consents", "error", err)
+		return nil, err
+	}
+
+	out := make([]output.ConsentOutput, 0, len(consents))
+	for _, consent := range consents {
+		client, err := u.clientRepo.FindByID(ctx, consent.ClientID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+			return nil, err
+		}
+		if client == nil {
+			continue
+		}
+		out = append(out, output.ConsentOutput{
+			ClientID:   client.ID,
+			ClientName: client.Name,
+			Scopes:     consent.Scopes,
+			GrantedAt:  consent.CreatedAt,
+			UpdatedAt:  consent.UpdatedAt,
+		})
+	}
+
+	return &output.ListConsentsOutput{Consents: out}, nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+type listIdentityProvidersUseCase struct {
+	providers port.IdentityProviders
+}
+
+func NewListIdentityProvidersUsecase(providers port.IdentityProviders) port.ListIdentityProvidersUseCase {
+	return &listIdentityProvidersUseCase{providers: providers}
+}
+
+// Execute lists the providers the login page offers, in the order they
+// are configured.
+func (u *listIdentityProvidersUseCase) Execute(ctx context.Context) []output.IdentityProviderOutput {
+	providers := u.providers.List()
+	out := make([]output.IdentityProviderOutput, 0, len(providers))
+	for _, provider := range providers {
+		out = append(out, output.IdentityProviderOutput{ID: provider.ID(), Name: provider.Name()})
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type listOAuthClientsUseCase struct {
+	clientRepo repository.OAuthClientRepository
+	logger     port.Logger
+}
+
+func NewListOAuthClientsUsecase(clientRepo repository.OAuthClientRepository, logger port.Logger) port.ListOAuthClientsUseCase {
+	return &listOAuthClientsUseCase{
+		clientRepo: clientRepo,
+		logger:     logger,
+	}
+}
+
+func (u *listOAuthClientsUseCase) Execute(ctx context.Context) ([]output.OAuthClientOutput, error) {
+	clients, err := u.clientRepo.List(ctx)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to list OAuth clients", "error", err)
+		return nil, err
+	}
+
+	out := make([]output.OAuthClientOutput, 0, len(clients))
+	for _, client := range clients {
+		out = append(out, oauthClientOutput(client))
+	}
+	return out, nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type listPasskeysUseCase struct {
+	credentialRepo repository.WebAuthnCredentialRepository
+	logger         port.Logger
+}
+
+func NewListPasskeysUsecase(credentialRepo repository.WebAuthnCredentialRepository, logger port.Logger) port.ListPasskeysUseCase {
+	return &listPasskeysUseCase{
+		credentialRepo: credentialRepo,
+		logger:         logger,
+	}
+}
+
+func (u *listPasskeysUseCase) Execute(ctx context.Context, input input.ListPasskeysInput) (*output.ListPasskeysOutput, error) {
+	credentials, err := u.credentialRepo.ListByUserID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to list passkeys", "error", err)
+		return nil, err
+	}
+
+	passkeys := make([]output.PasskeyOutput, 0, len(credentials))
+	for _, credential := range credentials {
+		passkeys = append(passkeys, toPasskeyOutput(credential))
+	}
+
+	return &output.ListPasskeysOutput{Passkeys: passkeys}, nil
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+const (
+	tokenTypeBearer     = "Bearer"
+	passwordLoginMethod = "password"
+)
+
+type loginUseCase struct {
+	userRepo    repository.UserRepository
+	auditLogger port.AuditLogger
+	logger      port.Logger
+	hasher      port.PasswordHasher
+	// directory is nil unless passwords may also be checked against a
+	// directory, whose accounts sign in as shadow users kept by
+	// directoryAccounts.
+	directory         port.CredentialVerifier
+	directoryAccounts port.DirectoryAccounts
+	logins            port.LoginCompleter
+	metrics           port.AuthMetrics
+	failures          port.LoginFailureCounter
+
+	requireVerifiedEmail bool
+	lockout              entity.LockoutPolicy
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewLoginUsecase(
+	userRepo repository.UserRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	hasher port.PasswordHasher,
+	directory port.CredentialVerifier,
+	directoryAccounts port.DirectoryAccounts,
+	logins port.LoginCompleter,
+	metrics port.AuthMetrics,
+	failures port.LoginFailureCounter,
+	requireVerifiedEmail bool,
+	lockout entity.LockoutPolicy,
+) port.LoginUseCase {
+	return &loginUseCase{
+		userRepo:          userRepo,
+		auditLogger:       auditLogger,
+		logger:            logger,
+		hasher:            hasher,
+		directory:         directory,
+		directoryAccounts: directoryAccounts,
+		logins:            logins,
+		metrics:           metrics,
+		failures:          failures,
+
+		requireVerifiedEmail: requireVerifiedEmail,
+		lockout:              lockout,
+	}
+}
+
+func (u *loginUseCase) Execute(ctx context.Context, input input.LoginInput) (*output.LoginOutput, error) {
+	identifier := strings.TrimSpace(input.Identifier)
+
+	user, err := u.findUser(ctx, identifier)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	useDirectory, err := u.usesDirectory(ctx, user)
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+	if useDirectory {
+		user, err = u.verifyWithDirectory(ctx, user, identifier, input.Password, input.IPAddress, now)
+	} else {
+		err = u.verifyLocally(ctx, user, identifier, input.Password, input.IPAddress, now)
+	}
+	if errors.Is(err, exception.ErrInvalidCredentials) {
+		u.slowDown(ctx, identifier, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.failures.Reset(identifier)
+
+	attempt := u.attempt(user, identifier, input.IPAddress, useDirectory)
+	attempt.VerifyOnly = input.VerifyOnly
+	result, err := u.logins.Complete(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	return loginOutput(user, result), nil
+}
+
+// attempt describes a password sign-in to the LoginCompleter, naming the
+// directory when it checked the password.
+func (u *loginUseCase) attempt(user *entity.User, identifier, ipAddress string, useDirectory bool) port.LoginAttempt {
+	attempt := port.LoginAttempt{
+		User:                 user,
+		Method:               passwordLoginMethod,
+		Details:              map[string]interface{}{"identifier": identifier},
+		IPAddress:            ipAddress,
+		RequireVerifiedEmail: u.requireVerifiedEmail,
+	}
+	if useDirectory {
+		attempt.Provider = u.directory.ID()
+	}
+	return attempt
+}
+
+// verifyLocally checks the password against the user's own hash, counting
+// failures towards a lockout. A locked account is refused by the
+// LoginCompleter's check, like on every other way of signing in.
+func (u *loginUseCase) verifyLocally(ctx context.Context, user *entity.User, identifier, password, ipAddress string, now time.Time) error {
+	if user == nil {
+		// Burn the same amount of work as a real verification so response
+		// timing does not reveal whether the account exists.
+		_, _ = u.hasher.Verify(password, u.getDummyHash())
+		u.loginFailed(ctx, nil, identifier, "user_not_found", ipAddress)
+		return exception.ErrInvalidCredentials
+	}
+
+	if user.IsLocked(now) {
+		// The password is still checked so a locked account answers as
+		// slowly as any other, but even a correct one is refused.
+		_, _ = u.verifyPassword(password, user)
+		return u.logins.Check(ctx, u.attempt(user, identifier, ipAddress, false))
+	}
+
+	ok, err := u.verifyPassword(password, user)
+	if err != nil {
+		u.logger.WarnCtx(ctx, "Failed to verify password", "user_id", user.ID.String(), "error", err)
+	}
+	if !ok {
+		u.loginFailed(ctx, user, identifier, "invalid_password", ipAddress)
+		u.recordFailure(ctx, user, identifier, ipAddress, now)
+		return exception.ErrInvalidCredentials
+	}
+
+	if user.HasLockoutState() {
+		if err := u.userRepo.ResetFailedLogins(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to reset failed logins", "user_id", user.ID.String(), "error", err)
+		}
+	}
+	return nil
+}
+
+// usesDirectory reports whether the password is checked against the
+// directory: always for the users it shadows, and for identifiers with no
+// local account that has a password of its own.
+func (u *loginUseCase) usesDirectory(ctx context.Context, user *entity.User) (bool, error) {
+	if u.directory == nil {
+		return false, nil
+	}
+	if user == nil || !user.HasPassword() {
+		return true, nil
+	}
+	return u.directoryAccounts.IsLinked(ctx, u.directory.ID(), user)
+}
+
+// verifyWithDirectory checks the credentials against the directory and
+// returns the shadow user of the account they belong to, brought up to
+// date. Failures are not counted locally: the directory enforces its own
+// lockout policy, but a shadow user locked here stays locked.
+func (u *loginUseCase) verifyWithDirectory(ctx context.Context, user *entity.User, identifier, password, ipAddress string, now time.Time) (*entity.User, error) {
+	account, err := u.directory.Verify(ctx, identifier, password)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to verify credentials with directory", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, exception.ErrIdentityProviderUnavailable
+	}
+	if account == nil {
+		u.loginFailed(ctx, user, identifier, "invalid_password", ipAddress)
+		return nil, exception.ErrInvalidCredentials
+	}
+
+	provider := port.ExternalProvider{ID: u.directory.ID(), LinkByEmail: u.directory.LinksByEmail()}
+	shadow, err := u.directoryAccounts.Synchronize(ctx, provider, account, ipAddress)
+	if errors.Is(err, exception.ErrEmailAlreadyExists) || errors.Is(err, exception.ErrUsernameAlreadyExists) {
+		// The directory account collides with a local account it may not
+		// take over. Only the server is told, since the answer would
+		// reveal that the local account exists.
+		u.logger.WarnCtx(ctx, "Directory account conflicts with a local account",
+			"directory", u.directory.ID(),
+			"subject", account.Subject,
+			"error", err,
+		)
+		u.loginFailed(ctx, user, identifier, "account_conflict", ipAddress)
+		return nil, exception.ErrInvalidCredentials
+	}
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	if shadow.IsLocked(now) {
+		return nil, u.logins.Check(ctx, u.attempt(shadow, identifier, ipAddress, true))
+	}
+	return shadow, nil
+}
+
+func (u *loginUseCase) findUser(ctx context.Context, identifier string) (*entity.User, error) {
+	if strings.Contains(identifier, "@") {
+		return u.userRepo.FindByEmail(ctx, strings.ToLower(identifier))
+	}
+	return u.userRepo.FindByUsername(ctx, identifier)
+}
+
+// verifyPassword never accepts a password for an account registered
+// without one, but takes as long as a real check.
+func (u *loginUseCase) verifyPassword(password string, user *entity.User) (bool, error) {
+	if !user.HasPassword() {
+		_, _ = u.hasher.Verify(password, u.getDummyHash())
+		return false, nil
+	}
+	return u.hasher.Verify(password, user.PasswordHash)
+}
+
+func (u *loginUseCase) getDummyHash() string {
+	u.dummyHashOnce.Do(func() {
+		hash, err := u.hasher.Hash("dummy-password-for-timing")
+		if err != nil {
+			u.logger.ErrorCtx(context.Background(), "Failed to generate dummy hash", "error", err)
+			return
+		}
+		u.dummyHash = hash
+	})
+	return u.dummyHash
+}
+
+func (u *loginUseCase) loginFailed(ctx context.Context, user *entity.User, identifier, reason, ipAddress string) {
+	u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+	u.logAudit(ctx, entity.AuditActionUserLoginFailed, user, identifier, reason, ipAddress)
+}
+
+// recordFailure counts a wrong password against the account, locking it
+// once the policy's threshold is reached. The lock is only recorded; the
+// caller still answers as for any wrong password.
+func (u *loginUseCase) recordFailure(ctx context.Context, user *entity.User, identifier, ipAddress string, now time.Time) {
+	if err := u.userRepo.RecordFailedLogin(ctx, user, now, now.Add(-u.lockout.AttemptWindow)); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to record failed login", "user_id", user.ID.String(), "error", err)
+		return
+	}
+
+	if !u.lockout.Enabled() || user.FailedLoginAttempts < u.lockout.MaxAttempts {
+		return
+	}
+
+	until := now.Add(u.lockout.LockDuration(user.LockoutCount + 1))
+	locked, err := u.userRepo.Lock(ctx, user, until, u.lockout.MaxAttempts)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to lock account", "user_id", user.ID.String(), "error", err)
+	}
+	if locked {
+		u.metrics.RecordAccountLockout()
+		u.logAudit(ctx, entity.AuditActionAccountLocked, user, identifier, "too_many_failed_logins", ipAddress)
+		u.logger.WarnCtx(ctx, "Account locked", "user_id", user.ID.String(), "locked_until", until, "lockout_count", user.LockoutCount)
+	}
+}
+
+// slowDown delays the response to a failed login progressively. Failures
+// are counted per identifier rather than per account, so an identifier
+// without an account is slowed down just the same.
+func (u *loginUseCase) slowDown(ctx context.Context, identifier string, now time.Time) {
+	delay := u.lockout.Delay(u.failures.Add(identifier, now))
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+func (u *loginUseCase) logAudit(ctx context.Context, action entity.AuditAction, user *entity.User, identifier, reason, ipAddress string) {
+	corrID := correlationid.FromContext(ctx)
+
+	var userID *string
+	if user != nil {
+		id := user.ID.String()
+		userID = &id
+	}
+
+	details := map[string]interface{}{
+		"identifier": identifier,
+	}
+	if reason != "" {
+		details["reason"] = reason
+	}
+
+	auditLog, err := entity.NewAuditLog(action, userID, details, ipAddress, corrID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
+
+// loginOutput describes what a LoginCompleter returned: the session, the
+// MFA challenge standing in its way, or just the user when the sign-in
+// only verified the credentials.
+func loginOutput(user *entity.User, result *port.LoginResult) *output.LoginOutput {
+	if result.Session == nil && result.MFA == nil {
+		return &output.LoginOutput{UserID: user.ID.String()}
+	}
+	if result.MFA != nil {
+		return &output.LoginOutput{
+			UserID:       user.ID.String(),
+			MFARequired:  true,
+			MFAToken:     result.MFA.Token,
+			MFAMethods:   result.MFA.Methods,
+			MFAExpiresAt: result.MFA.ExpiresAt,
+		}
+	}
+	return &output.LoginOutput{
+		UserID:       user.ID.String(),
+		AccessToken:  result.Session.AccessToken,
+		RefreshToken: result.Session.RefreshToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresAt:    result.Session.AccessTokenExpiresAt,
+	}
+}
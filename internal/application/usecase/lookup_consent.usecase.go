@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type lookupConsentUseCase struct {
+	clientRepo    repository.OAuthClientRepository
+	consentRepo   repository.UserConsentRepository
+	challengeRepo repository.ConsentChallengeRepository
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+}
+
+func NewLookupConsentUsecase(
+	clientRepo repository.OAuthClientRepository,
+	consentRepo repository.UserConsentRepository,
+	challengeRepo repository.ConsentChallengeRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.LookupConsentUseCase {
+	return &lookupConsentUseCase{
+		clientRepo:    clientRepo,
+		consentRepo:   consentRepo,
+		challengeRepo: challengeRepo,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+	}
+}
+
+func (u *lookupConsentUseCase) Execute(ctx context.Context, req input.LookupConsentInput) (*output.ConsentRequestOutput, error) {
+	challenge, err := findConsentChallenge(ctx, u.challengeRepo, u.logger, u.opaqueTokens, req.ConsentToken)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := u.clientRepo.FindByID(ctx, challenge.ClientID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+		return nil, err
+	}
+	if client == nil {
+		return nil, exception.ErrInvalidConsentToken
+	}
+
+	consent, err := u.consentRepo.Find(ctx, challenge.UserID, challenge.ClientID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find consent", "error", err)
+		return nil, err
+	}
+	var granted []string
+	if consent != nil {
+		for _, scope := range challenge.Scopes {
+			if slices.Contains(consent.Scopes, scope) {
+				granted = append(granted, scope)
+			}
+		}
+	}
+
+	return &output.ConsentRequestOutput{
+		ClientID:      client.ID,
+		ClientName:    client.Name,
+		Scopes:        challenge.Scopes,
+		GrantedScopes: granted,
+		ExpiresAt:     challenge.ExpiresAt,
+	}, nil
+}
+
+// findConsentChallenge returns the pending challenge a consent token
+// names. Unknown, expired and already decided challenges all fail with
+// ErrInvalidConsentToken.
+func findConsentChallenge(
+	ctx context.Context,
+	challengeRepo repository.ConsentChallengeRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	token string,
+) (*entity.ConsentChallenge, error) {
+	if token == "" {
+		return nil, exception.ErrInvalidConsentToken
+	}
+
+	challenge, err := challengeRepo.FindByTokenHash(ctx, opaqueTokens.Hash(token))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find consent challenge", "error", err)
+		return nil, err
+	}
+	if challenge == nil || challenge.IsUsed() || challenge.IsExpired(time.Now().UTC()) {
+		return nil, exception.ErrInvalidConsentToken
+	}
+	return challenge, nil
+}
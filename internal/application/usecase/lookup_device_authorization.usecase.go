@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type lookupDeviceAuthorizationUseCase struct {
+	deviceRepo   repository.DeviceAuthorizationRepository
+	clientRepo   repository.OAuthClientRepository
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewLookupDeviceAuthorizationUsecase(
+	deviceRepo repository.DeviceAuthorizationRepository,
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.LookupDeviceAuthorizationUseCase {
+	return &lookupDeviceAuthorizationUseCase{
+		deviceRepo:   deviceRepo,
+		clientRepo:   clientRepo,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+// Execute tells the user which client is asking and for what, so they
+// can recognise a request they did not start before approving it.
+func (u *lookupDeviceAuthorizationUseCase) Execute(ctx context.Context, req input.LookupDeviceAuthorizationInput) (*output.DeviceAuthorizationOutput, error) {
+	authorization, client, err := findPendingDeviceAuthorization(ctx, u.deviceRepo, u.clientRepo, u.logger, u.opaqueTokens, req.UserCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.DeviceAuthorizationOutput{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     authorization.Scopes,
+		ExpiresAt:  authorization.ExpiresAt,
+	}, nil
+}
+
+// findPendingDeviceAuthorization returns the undecided, unexpired request
+// with userCode and its client, or ErrInvalidUserCode.
+func findPendingDeviceAuthorization(
+	ctx context.Context,
+	deviceRepo repository.DeviceAuthorizationRepository,
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	userCode string,
+) (*entity.DeviceAuthorization, *entity.OAuthClient, error) {
+	normalized := entity.NormalizeUserCode(userCode)
+	if normalized == "" {
+		return nil, nil, exception.ErrInvalidUserCode
+	}
+
+	authorization, err := deviceRepo.FindByUserCodeHash(ctx, opaqueTokens.Hash(normalized))
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find device authorization", "error", err)
+		return nil, nil, err
+	}
+	if authorization == nil || !authorization.IsPending() || authorization.IsExpired(time.Now().UTC()) {
+		return nil, nil, exception.ErrInvalidUserCode
+	}
+
+	client, err := clientRepo.FindByID(ctx, authorization.ClientID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+		return nil, nil, err
+	}
+	if client == nil {
+		return nil, nil, exception.ErrInvalidUserCode
+	}
+
+	return authorization, client, nil
+}
@@ -0,0 +1,623 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+// Token type identifiers from RFC 8693 section 3.
+const (
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	tokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// maxTokenActorDepth bounds the act claim chain, and so the size of
+// tokens exchanged again and again down a chain of services.
+const maxTokenActorDepth = 5
+
+type oauthTokenUseCase struct {
+	clients      port.ClientAuthenticator
+	codeRepo     repository.AuthorizationCodeRepository
+	deviceRepo   repository.DeviceAuthorizationRepository
+	userRepo     repository.UserRepository
+	refreshRepo  repository.RefreshTokenRepository
+	sessionRepo  repository.OAuthSessionRepository
+	sessions     port.SessionIssuer
+	refresh      port.RefreshUseCase
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+	idTokens     port.IDTokenSigner
+	tokens       port.TokenService
+	revocations  port.AccessTokenRevocations
+	// deviceGrant reports whether the device authorization grant is on.
+	deviceGrant bool
+}
+
+func NewOAuthTokenUsecase(
+	clients port.ClientAuthenticator,
+	codeRepo repository.AuthorizationCodeRepository,
+	deviceRepo repository.DeviceAuthorizationRepository,
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	sessionRepo repository.OAuthSessionRepository,
+	sessions port.SessionIssuer,
+	refresh port.RefreshUseCase,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	idTokens port.IDTokenSigner,
+	tokens port.TokenService,
+	revocations port.AccessTokenRevocations,
+	deviceGrant bool,
+) port.OAuthTokenUseCase {
+	return &oauthTokenUseCase{
+		clients:      clients,
+		codeRepo:     codeRepo,
+		deviceRepo:   deviceRepo,
+		userRepo:     userRepo,
+		refreshRepo:  refreshRepo,
+		sessionRepo:  sessionRepo,
+		sessions:     sessions,
+		refresh:      refresh,
+		auditLogger:  auditLogger,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+		idTokens:     idTokens,
+		tokens:       tokens,
+		revocations:  revocations,
+		deviceGrant:  deviceGrant,
+	}
+}
+
+// Execute authenticates the client and redeems the grant. Protocol
+// failures are returned as an OAuthError.
+func (u *oauthTokenUseCase) Execute(ctx context.Context, req input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	client, err := authenticateClient(ctx, u.clients, u.logger, input.ClientCredentials{
+		ClientID:      req.ClientID,
+		ClientSecret:  req.ClientSecret,
+		AssertionType: req.ClientAssertionType,
+		Assertion:     req.ClientAssertion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case entity.OAuthGrantAuthorizationCode, entity.OAuthGrantRefreshToken, entity.OAuthGrantClientCredentials,
+		entity.OAuthGrantTokenExchange:
+	case entity.OAuthGrantDeviceCode:
+		if !u.deviceGrant {
+			return nil, exception.NewOAuthError(exception.OAuthUnsupportedGrantType, "grant_type "+req.GrantType+" is not supported")
+		}
+	case "":
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "grant_type is required")
+	default:
+		return nil, exception.NewOAuthError(exception.OAuthUnsupportedGrantType, "grant_type "+req.GrantType+" is not supported")
+	}
+
+	if !client.AllowsGrantType(req.GrantType) {
+		return nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "client may not use the "+req.GrantType+" grant")
+	}
+
+	switch req.GrantType {
+	case entity.OAuthGrantRefreshToken:
+		return u.refreshToken(ctx, client, req)
+	case entity.OAuthGrantClientCredentials:
+		return u.clientCredentials(ctx, client, req)
+	case entity.OAuthGrantDeviceCode:
+		return u.deviceCode(ctx, client, req)
+	case entity.OAuthGrantTokenExchange:
+		return u.exchangeToken(ctx, client, req)
+	default:
+		return u.exchangeCode(ctx, client, req)
+	}
+}
+
+// authenticateClient logs failures other than the client's own, which
+// are returned as an OAuthError.
+func authenticateClient(ctx context.Context, clients port.ClientAuthenticator, logger port.Logger, credentials input.ClientCredentials) (*entity.OAuthClient, error) {
+	client, err := clients.Authenticate(ctx, credentials)
+	if err != nil {
+		var oauthErr *exception.OAuthError
+		if !errors.As(err, &oauthErr) {
+			logger.ErrorCtx(ctx, "Failed to authenticate OAuth client", "error", err)
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// clientCredentials issues a token to the client itself. It gets every
+// scope it is registered for unless it asks for fewer, but never openid,
+// which only has meaning for a user.
+func (u *oauthTokenUseCase) clientCredentials(ctx context.Context, client *entity.OAuthClient, req input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	if !client.IsConfidential() {
+		return nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "public clients may not use the client_credentials grant")
+	}
+
+	scopes := entity.ParseScope(req.Scope)
+	if len(scopes) == 0 {
+		for _, scope := range client.Scopes {
+			if scope != entity.ScopeOpenID {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	for _, scope := range scopes {
+		if scope == entity.ScopeOpenID || !slices.Contains(client.Scopes, scope) {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope "+scope+" is not available to the client")
+		}
+	}
+
+	accessToken, expiresAt, err := u.tokens.GenerateAccessToken(port.AccessTokenClaims{
+		ClientID: client.ID,
+		Scopes:   scopes,
+		TTL:      client.AccessTokenTTL,
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate access token", "error", err)
+		return nil, err
+	}
+
+	u.logAudit(ctx, entity.AuditActionOAuthTokenIssued, nil, map[string]interface{}{
+		"client_id":  client.ID,
+		"grant_type": entity.OAuthGrantClientCredentials,
+		"scope":      entity.FormatScope(scopes),
+	}, req.IPAddress)
+
+	return &output.OAuthTokenOutput{
+		AccessToken: accessToken,
+		TokenType:   tokenTypeBearer,
+		ExpiresAt:   expiresAt,
+		Scopes:      scopes,
+	}, nil
+}
+
+func (u *oauthTokenUseCase) exchangeCode(ctx context.Context, client *entity.OAuthClient, input input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	if input.Code == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "code is required")
+	}
+	if input.CodeVerifier == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "code_verifier is required")
+	}
+
+	invalidCode := exception.NewOAuthError(exception.OAuthInvalidGrant, "authorization code is invalid or expired")
+
+	code, err := u.codeRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.Code))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find authorization code", "error", err)
+		return nil, err
+	}
+	// A code presented by another client is treated as unknown rather than
+	// replayed, so that client cannot revoke the tokens it produced.
+	if code == nil || code.ClientID != client.ID {
+		return nil, invalidCode
+	}
+
+	if code.IsUsed() {
+		return nil, u.handleReuse(ctx, code, input.IPAddress)
+	}
+
+	now := time.Now().UTC()
+	if code.IsExpired(now) {
+		return nil, invalidCode
+	}
+	if input.RedirectURI != code.RedirectURI {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "redirect_uri does not match the authorization request")
+	}
+	if !code.VerifyCodeVerifier(input.CodeVerifier) {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "code_verifier does not match the code challenge")
+	}
+
+	marked, err := u.codeRepo.MarkUsed(ctx, code.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark authorization code as used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		return nil, u.handleReuse(ctx, code, input.IPAddress)
+	}
+
+	user, err := u.userRepo.FindByID(ctx, code.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, invalidCode
+	}
+
+	session, err := u.sessions.IssueForClient(ctx, user, port.ClientGrant{
+		ClientID:       client.ID,
+		Scopes:         code.Scopes,
+		FamilyID:       code.FamilyID,
+		Refreshable:    client.AllowsGrantType(entity.OAuthGrantRefreshToken),
+		AccessTokenTTL: client.AccessTokenTTL,
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to issue session", "error", err)
+		return nil, err
+	}
+	if err := u.startSession(ctx, code.FamilyID, user, client); err != nil {
+		return nil, err
+	}
+
+	// The user signed in just before the code was issued, so its creation
+	// time stands in for auth_time.
+	var idToken string
+	if slices.Contains(code.Scopes, entity.ScopeOpenID) {
+		idToken, err = u.idTokens.SignIDToken(port.IDTokenClaims{
+			Subject:     user.ID.String(),
+			Audience:    client.ID,
+			Nonce:       code.Nonce,
+			AuthTime:    code.CreatedAt,
+			AccessToken: session.AccessToken,
+			SessionID:   code.FamilyID,
+			UserClaims:  oidcUserClaims(user, code.Scopes),
+		})
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to sign ID token", "error", err)
+			return nil, err
+		}
+	}
+
+	u.logAudit(ctx, entity.AuditActionOAuthTokenIssued, &code.UserID, map[string]interface{}{
+		"client_id":  client.ID,
+		"grant_type": entity.OAuthGrantAuthorizationCode,
+		"scope":      entity.FormatScope(code.Scopes),
+	}, input.IPAddress)
+
+	return &output.OAuthTokenOutput{
+		AccessToken:  session.AccessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresAt:    session.AccessTokenExpiresAt,
+		RefreshToken: session.RefreshToken,
+		IDToken:      idToken,
+		Scopes:       code.Scopes,
+	}, nil
+}
+
+// deviceCode answers a device polling for the outcome of its
+// authorization request, as RFC 8628 section 3.5 describes. A device
+// polling faster than its interval is told to slow down, and the
+// interval grows for every later poll.
+func (u *oauthTokenUseCase) deviceCode(ctx context.Context, client *entity.OAuthClient, req input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	if req.DeviceCode == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "device_code is required")
+	}
+
+	invalidCode := exception.NewOAuthError(exception.OAuthInvalidGrant, "device code is invalid")
+
+	authorization, err := u.deviceRepo.FindByDeviceCodeHash(ctx, u.opaqueTokens.Hash(req.DeviceCode))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find device authorization", "error", err)
+		return nil, err
+	}
+	if authorization == nil || authorization.ClientID != client.ID || authorization.IsUsed() {
+		return nil, invalidCode
+	}
+
+	now := time.Now().UTC()
+	if authorization.IsExpired(now) {
+		return nil, exception.NewOAuthError(exception.OAuthExpiredToken, "device code has expired")
+	}
+
+	interval := authorization.Interval
+	tooSoon := authorization.PolledTooSoon(now)
+	if tooSoon {
+		interval += entity.DeviceSlowDownStep
+	}
+	if err := u.deviceRepo.RecordPoll(ctx, authorization.ID, now, interval); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to record device poll", "error", err)
+		return nil, err
+	}
+	if tooSoon {
+		return nil, exception.NewOAuthError(exception.OAuthSlowDown, "polling too frequently")
+	}
+
+	switch authorization.Status {
+	case entity.DeviceAuthorizationPending:
+		return nil, exception.NewOAuthError(exception.OAuthAuthorizationPending, "the user has not yet approved the request")
+	case entity.DeviceAuthorizationDenied:
+		return nil, exception.NewOAuthError(exception.OAuthAccessDenied, "the user denied the request")
+	}
+
+	marked, err := u.deviceRepo.MarkUsed(ctx, authorization.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark device authorization as used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		return nil, invalidCode
+	}
+
+	user, err := u.userRepo.FindByID(ctx, authorization.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, invalidCode
+	}
+
+	session, err := u.sessions.IssueForClient(ctx, user, port.ClientGrant{
+		ClientID:       client.ID,
+		Scopes:         authorization.Scopes,
+		FamilyID:       authorization.FamilyID,
+		Refreshable:    client.AllowsGrantType(entity.OAuthGrantRefreshToken),
+		AccessTokenTTL: client.AccessTokenTTL,
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to issue session", "error", err)
+		return nil, err
+	}
+	if err := u.startSession(ctx, authorization.FamilyID, user, client); err != nil {
+		return nil, err
+	}
+
+	// The user was signed in when they approved the request.
+	var idToken string
+	if slices.Contains(authorization.Scopes, entity.ScopeOpenID) {
+		idToken, err = u.idTokens.SignIDToken(port.IDTokenClaims{
+			Subject:     user.ID.String(),
+			Audience:    client.ID,
+			AuthTime:    *authorization.DecidedAt,
+			AccessToken: session.AccessToken,
+			SessionID:   authorization.FamilyID,
+			UserClaims:  oidcUserClaims(user, authorization.Scopes),
+		})
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to sign ID token", "error", err)
+			return nil, err
+		}
+	}
+
+	u.logAudit(ctx, entity.AuditActionOAuthTokenIssued, &authorization.UserID, map[string]interface{}{
+		"client_id":  client.ID,
+		"grant_type": entity.OAuthGrantDeviceCode,
+		"scope":      entity.FormatScope(authorization.Scopes),
+	}, req.IPAddress)
+
+	return &output.OAuthTokenOutput{
+		AccessToken:  session.AccessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresAt:    session.AccessTokenExpiresAt,
+		RefreshToken: session.RefreshToken,
+		IDToken:      idToken,
+		Scopes:       authorization.Scopes,
+	}, nil
+}
+
+// exchangeToken swaps a user's access token for one restricted to other
+// audiences, as RFC 8693 describes. Without an actor token the client
+// impersonates the user; with one, the new token records the client's
+// actor in its act claim ahead of any earlier actors. Each audience must
+// be one the client is registered to exchange for, the scopes can only
+// narrow, and the new token never outlives the subject token.
+func (u *oauthTokenUseCase) exchangeToken(ctx context.Context, client *entity.OAuthClient, req input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	if !client.IsConfidential() {
+		return nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "public clients may not exchange tokens")
+	}
+	if req.SubjectToken == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "subject_token is required")
+	}
+	if !isAccessTokenType(req.SubjectTokenType) {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "subject_token_type must be "+tokenTypeAccessToken)
+	}
+	if req.ActorToken == "" && req.ActorTokenType != "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "actor_token_type requires an actor_token")
+	}
+	if req.ActorToken != "" && !isAccessTokenType(req.ActorTokenType) {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "actor_token_type must be "+tokenTypeAccessToken)
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != tokenTypeAccessToken {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "only "+tokenTypeAccessToken+" can be requested")
+	}
+
+	var audiences []string
+	for _, audience := range req.Audiences {
+		if !slices.Contains(audiences, audience) {
+			audiences = append(audiences, audience)
+		}
+	}
+	if len(audiences) == 0 {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidTarget, "audience or resource is required")
+	}
+	for _, audience := range audiences {
+		if !client.MayExchangeFor(audience) {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidTarget, "client may not exchange tokens for audience "+audience)
+		}
+	}
+
+	subject := u.verifyExchangedToken(req.SubjectToken)
+	if subject == nil {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "subject_token is invalid")
+	}
+	if subject.UserID == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "subject_token must identify a user")
+	}
+
+	actor := subject.Actor
+	if req.ActorToken != "" {
+		claims := u.verifyExchangedToken(req.ActorToken)
+		if claims == nil {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "actor_token is invalid")
+		}
+		// A client may only name itself, or a user it holds a token for,
+		// as the actor.
+		if claims.ClientID != client.ID {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "actor_token was not issued to the client")
+		}
+		actorSubject := claims.UserID
+		if actorSubject == "" {
+			actorSubject = claims.ClientID
+		}
+		actor = &entity.TokenActor{Subject: actorSubject, ClientID: claims.ClientID, Actor: subject.Actor}
+	}
+	if actor.Depth() > maxTokenActorDepth {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "delegation chain is too long")
+	}
+
+	scopes := entity.ParseScope(req.Scope)
+	available := make([]string, 0, len(subject.Scopes))
+	for _, scope := range subject.Scopes {
+		if slices.Contains(client.Scopes, scope) {
+			available = append(available, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = available
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(available, scope) {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope "+scope+" is not granted by the subject token")
+		}
+	}
+
+	user, err := u.userRepo.FindByID(ctx, subject.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, "subject_token is invalid")
+	}
+
+	accessToken, expiresAt, err := u.tokens.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:    subject.UserID,
+		Username:  subject.Username,
+		ClientID:  client.ID,
+		Scopes:    scopes,
+		Audience:  audiences,
+		Actor:     actor,
+		TTL:       client.AccessTokenTTL,
+		ExpiresAt: subject.ExpiresAt,
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate access token", "error", err)
+		return nil, err
+	}
+
+	details := map[string]interface{}{
+		"client_id":         client.ID,
+		"grant_type":        entity.OAuthGrantTokenExchange,
+		"audience":          audiences,
+		"scope":             entity.FormatScope(scopes),
+		"subject_client_id": subject.ClientID,
+		"subject_jti":       subject.ID,
+	}
+	if actor != nil {
+		details["act"] = actor.Subjects()
+	}
+	u.logAudit(ctx, entity.AuditActionOAuthTokenExchanged, &subject.UserID, details, req.IPAddress)
+
+	return &output.OAuthTokenOutput{
+		AccessToken:     accessToken,
+		TokenType:       tokenTypeBearer,
+		IssuedTokenType: tokenTypeAccessToken,
+		ExpiresAt:       expiresAt,
+		Scopes:          scopes,
+	}, nil
+}
+
+// verifyExchangedToken returns the claims of an access token this service
+// issued, for any audience, unless it has been revoked.
+func (u *oauthTokenUseCase) verifyExchangedToken(token string) *port.AccessTokenClaims {
+	claims, err := u.tokens.InspectAccessToken(token)
+	if err != nil || u.revocations.IsRevoked(claims) {
+		return nil
+	}
+	return claims
+}
+
+// isAccessTokenType accepts the token types our access tokens can be
+// presented as: an access token, or the JWT it is.
+func isAccessTokenType(tokenType string) bool {
+	return tokenType == tokenTypeAccessToken || tokenType == tokenTypeJWT
+}
+
+// handleReuse revokes the refresh tokens issued from a code presented a
+// second time, as the code has evidently leaked.
+func (u *oauthTokenUseCase) handleReuse(ctx context.Context, code *entity.AuthorizationCode, ipAddress string) error {
+	u.logger.WarnCtx(ctx, "Authorization code reuse detected, revoking issued tokens",
+		"user_id", code.UserID,
+		"client_id", code.ClientID,
+	)
+
+	if err := u.refreshRepo.RevokeFamily(ctx, code.FamilyID, time.Now().UTC()); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to revoke refresh token family", "family_id", code.FamilyID, "error", err)
+	}
+
+	u.logAudit(ctx, entity.AuditActionOAuthCodeReused, &code.UserID, map[string]interface{}{
+		"client_id": code.ClientID,
+		"code_id":   code.ID,
+	}, ipAddress)
+
+	return exception.NewOAuthError(exception.OAuthInvalidGrant, "authorization code is invalid or expired")
+}
+
+func (u *oauthTokenUseCase) refreshToken(ctx context.Context, client *entity.OAuthClient, req input.OAuthTokenInput) (*output.OAuthTokenOutput, error) {
+	if req.RefreshToken == "" {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "refresh_token is required")
+	}
+
+	result, err := u.refresh.Execute(ctx, input.RefreshInput{
+		RefreshToken:   req.RefreshToken,
+		ClientID:       client.ID,
+		Scopes:         entity.ParseScope(req.Scope),
+		AccessTokenTTL: client.AccessTokenTTL,
+		IPAddress:      req.IPAddress,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInvalidRefreshToken),
+			errors.Is(err, exception.ErrRefreshTokenReused):
+			return nil, exception.NewOAuthError(exception.OAuthInvalidGrant, err.Error())
+		case errors.Is(err, exception.ErrInvalidScope):
+			return nil, exception.NewOAuthError(exception.OAuthInvalidScope, err.Error())
+		default:
+			return nil, err
+		}
+	}
+
+	return &output.OAuthTokenOutput{
+		AccessToken:  result.AccessToken,
+		TokenType:    result.TokenType,
+		ExpiresAt:    result.ExpiresAt,
+		RefreshToken: result.RefreshToken,
+		Scopes:       result.Scopes,
+	}, nil
+}
+
+// startSession records the user's session at the client, named after the
+// grant's refresh token family, so logging out can end it.
+func (u *oauthTokenUseCase) startSession(ctx context.Context, familyID string, user *entity.User, client *entity.OAuthClient) error {
+	if err := u.sessionRepo.Create(ctx, entity.NewOAuthSession(familyID, user.ID.String(), client.ID)); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to record OAuth session", "error", err)
+		return err
+	}
+	return nil
+}
+
+// logAudit records userID, or no user for tokens a client obtained for
+// itself; details always name the client.
+func (u *oauthTokenUseCase) logAudit(ctx context.Context, action entity.AuditAction, userID *string, details map[string]interface{}, ipAddress string) {
+	auditLog, err := entity.NewAuditLog(action, userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
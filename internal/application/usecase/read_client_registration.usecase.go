@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type readClientRegistrationUseCase struct {
+	clientRepo   repository.OAuthClientRepository
+	opaqueTokens port.OpaqueTokenGenerator
+	logger       port.Logger
+}
+
+func NewReadClientRegistrationUsecase(
+	clientRepo repository.OAuthClientRepository,
+	opaqueTokens port.OpaqueTokenGenerator,
+	logger port.Logger,
+) port.ReadClientRegistrationUseCase {
+	return &readClientRegistrationUseCase{
+		clientRepo:   clientRepo,
+		opaqueTokens: opaqueTokens,
+		logger:       logger,
+	}
+}
+
+func (u *readClientRegistrationUseCase) Execute(ctx context.Context, req input.ClientRegistrationInput) (*output.OAuthClientOutput, error) {
+	client, err := findRegisteredClient(ctx, u.clientRepo, u.logger, u.opaqueTokens, req.ClientID, req.RegistrationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	out := oauthClientOutput(client)
+	return &out, nil
+}
+
+// findRegisteredClient returns the client the registration access token
+// was issued to. An unknown client and a wrong token fail the same way,
+// as RFC 7592 section 3 asks, so client IDs cannot be probed.
+func findRegisteredClient(
+	ctx context.Context,
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	clientID, registrationToken string,
+) (*entity.OAuthClient, error) {
+	if clientID == "" || registrationToken == "" {
+		return nil, exception.ErrInvalidRegistrationToken
+	}
+
+	client, err := clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+		return nil, err
+	}
+	// Clients created before registration tokens existed have none and
+	// can only be managed by an admin.
+	if client == nil || client.RegistrationTokenHash == "" {
+		return nil, exception.ErrInvalidRegistrationToken
+	}
+	if subtle.ConstantTimeCompare([]byte(opaqueTokens.Hash(registrationToken)), []byte(client.RegistrationTokenHash)) != 1 {
+		return nil, exception.ErrInvalidRegistrationToken
+	}
+	return client, nil
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+const samlLoginMethod = "saml"
+
+type redeemSAMLLoginUseCase struct {
+	loginRepo    repository.SAMLLoginRepository
+	userRepo     repository.UserRepository
+	logins       port.LoginCompleter
+	metrics      port.AuthMetrics
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewRedeemSAMLLoginUsecase(
+	loginRepo repository.SAMLLoginRepository,
+	userRepo repository.UserRepository,
+	logins port.LoginCompleter,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.RedeemSAMLLoginUseCase {
+	return &redeemSAMLLoginUseCase{
+		loginRepo:    loginRepo,
+		userRepo:     userRepo,
+		logins:       logins,
+		metrics:      metrics,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+// Execute exchanges the code from the assertion consumer service for
+// tokens, or for an MFA challenge when the user has a second factor. A
+// sign-in this service requested can only be redeemed with the device
+// token of the client that started it.
+func (u *redeemSAMLLoginUseCase) Execute(ctx context.Context, input input.RedeemSAMLLoginInput) (*output.LoginOutput, error) {
+	if input.Code == "" {
+		return nil, exception.ErrInvalidSAMLLogin
+	}
+
+	login, err := u.loginRepo.FindByCodeHash(ctx, u.opaqueTokens.Hash(input.Code))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find SAML login", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if login == nil || login.IsUsed() || login.IsExpired(now) {
+		return nil, exception.ErrInvalidSAMLLogin
+	}
+	if login.DeviceHash != "" {
+		if input.DeviceToken == "" ||
+			subtle.ConstantTimeCompare([]byte(u.opaqueTokens.Hash(input.DeviceToken)), []byte(login.DeviceHash)) != 1 {
+			return nil, exception.ErrInvalidSAMLLogin
+		}
+	}
+
+	marked, err := u.loginRepo.MarkUsed(ctx, login.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark SAML login as used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		return nil, exception.ErrInvalidSAMLLogin
+	}
+
+	user, err := u.userRepo.FindByID(ctx, login.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrInvalidSAMLLogin
+	}
+
+	result, err := u.logins.Complete(ctx, port.LoginAttempt{
+		User:      user,
+		Method:    samlLoginMethod,
+		Provider:  login.IdP,
+		IPAddress: input.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loginOutput(user, result), nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type refreshUseCase struct {
+	userRepo     repository.UserRepository
+	refreshRepo  repository.RefreshTokenRepository
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+	sessions     port.SessionIssuer
+}
+
+func NewRefreshUsecase(
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	sessions port.SessionIssuer,
+) port.RefreshUseCase {
+	return &refreshUseCase{
+		userRepo:     userRepo,
+		refreshRepo:  refreshRepo,
+		auditLogger:  auditLogger,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+		sessions:     sessions,
+	}
+}
+
+func (u *refreshUseCase) Execute(ctx context.Context, input input.RefreshInput) (*output.RefreshOutput, error) {
+	token, err := u.refreshRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.RefreshToken))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find refresh token", "error", err)
+		return nil, err
+	}
+	if token == nil || token.IsRevoked() || token.ClientID != input.ClientID {
+		return nil, exception.ErrInvalidRefreshToken
+	}
+	if !entity.ScopeSubset(input.Scopes, token.Scopes) {
+		return nil, exception.ErrInvalidScope
+	}
+
+	now := time.Now().UTC()
+
+	if token.IsUsed() {
+		return nil, u.handleReuse(ctx, token, input.IPAddress)
+	}
+
+	if token.IsExpired(now) {
+		return nil, exception.ErrInvalidRefreshToken
+	}
+
+	user, err := u.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		u.revokeFamily(ctx, token.FamilyID, now)
+		return nil, exception.ErrInvalidRefreshToken
+	}
+	// A lock is temporary, so the token is left unused for the client to
+	// retry once it is lifted.
+	if user.IsLocked(now) {
+		return nil, exception.ErrInvalidRefreshToken
+	}
+
+	marked, err := u.refreshRepo.MarkUsed(ctx, token.ID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to mark refresh token as used", "error", err)
+		return nil, err
+	}
+	if !marked {
+		// A concurrent request consumed the token between the lookup and
+		// the update, which is indistinguishable from a replay.
+		return nil, u.handleReuse(ctx, token, input.IPAddress)
+	}
+
+	session, err := u.issue(ctx, user, token, input.Scopes, input.AccessTokenTTL)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to issue session", "error", err)
+		return nil, err
+	}
+
+	scopes := token.Scopes
+	if len(input.Scopes) > 0 {
+		scopes = input.Scopes
+	}
+
+	return &output.RefreshOutput{
+		UserID:       user.ID.String(),
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresAt:    session.AccessTokenExpiresAt,
+		Scopes:       scopes,
+	}, nil
+}
+
+// issue continues the token's family, keeping an OAuth client's tokens
+// bound to the client. The access token gets the requested scopes, if
+// any; the refresh token keeps the original grant so a later refresh can
+// ask for more again.
+func (u *refreshUseCase) issue(ctx context.Context, user *entity.User, token *entity.RefreshToken, scopes []string, accessTokenTTL time.Duration) (*port.Session, error) {
+	if token.ClientID == "" {
+		return u.sessions.Issue(ctx, user, token.FamilyID)
+	}
+	return u.sessions.IssueForClient(ctx, user, port.ClientGrant{
+		ClientID:          token.ClientID,
+		Scopes:            token.Scopes,
+		AccessTokenScopes: scopes,
+		FamilyID:          token.FamilyID,
+		Refreshable:       true,
+		AccessTokenTTL:    accessTokenTTL,
+	})
+}
+
+func (u *refreshUseCase) handleReuse(ctx context.Context, token *entity.RefreshToken, ipAddress string) error {
+	u.logger.WarnCtx(ctx, "Refresh token reuse detected, revoking family",
+		"user_id", token.UserID,
+		"family_id", token.FamilyID,
+	)
+
+	u.revokeFamily(ctx, token.FamilyID, time.Now().UTC())
+	u.logAudit(ctx, token, ipAddress)
+
+	return exception.ErrRefreshTokenReused
+}
+
+func (u *refreshUseCase) revokeFamily(ctx context.Context, familyID string, now time.Time) {
+	if err := u.refreshRepo.RevokeFamily(ctx, familyID, now); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to revoke refresh token family", "family_id", familyID, "error", err)
+	}
+}
+
+func (u *refreshUseCase) logAudit(ctx context.Context, token *entity.RefreshToken, ipAddress string) {
+	corrID := correlationid.FromContext(ctx)
+	userID := token.UserID
+
+	auditLog, err := entity.NewAuditLog(
+		entity.AuditActionRefreshTokenReused,
+		&userID,
+		map[string]interface{}{
+			"family_id": token.FamilyID,
+			"token_id":  token.ID,
+		},
+		ipAddress,
+		corrID,
+	)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
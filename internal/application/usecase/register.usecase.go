@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
 	"github.com/thanhnamdk2710/auth-service/internal/application/input"
 	"github.com/thanhnamdk2710/auth-service/internal/application/output"
 	"github.com/thanhnamdk2710/auth-service/internal/application/port"
@@ -15,22 +16,31 @@ import (
 
 type registerUseCase struct {
 	userRepo      repository.UserRepository
-	auditLogger   port.AuditLogger
+	txManager     port.TxManager
+	outbox        port.Outbox
 	logger        port.Logger
 	uuidGenerator port.UUIDGenerator
+	hasher        port.PasswordHasher
+	passwords     port.PasswordValidator
 }
 
 func NewRegisterUsecase(
 	userRepo repository.UserRepository,
-	auditLogger port.AuditLogger,
+	txManager port.TxManager,
+	outbox port.Outbox,
 	logger port.Logger,
 	uuidGenerator port.UUIDGenerator,
+	hasher port.PasswordHasher,
+	passwords port.PasswordValidator,
 ) port.RegisterUseCase {
 	return &registerUseCase{
 		userRepo:      userRepo,
-		auditLogger:   auditLogger,
+		txManager:     txManager,
+		outbox:        outbox,
 		logger:        logger,
 		uuidGenerator: uuidGenerator,
+		hasher:        hasher,
+		passwords:     passwords,
 	}
 }
 
@@ -73,15 +83,40 @@ func (u *registerUseCase) Execute(ctx context.Context, input input.RegisterInput
 		return nil, err
 	}
 
+	// Without a password the account signs in by email link, code or
+	// passkey only.
 	user := entity.NewUser(userID, *username, email)
+	if input.Password != "" {
+		password, err := u.passwords.Validate(ctx, input.Password, username.String(), email.String())
+		if err != nil {
+			return nil, err
+		}
+
+		passwordHash, err := u.hasher.Hash(password.Plaintext())
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to hash password", "error", err)
+			return nil, err
+		}
+
+		if err := user.SetPasswordHash(passwordHash); err != nil {
+			return nil, err
+		}
+	}
+
+	// The audit entry and verification mail are delivered from the outbox,
+	// so they are recorded if and only if the user is.
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.userRepo.Create(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to create user", "error", err)
+			return err
+		}
 
-	if err := u.userRepo.Create(ctx, user); err != nil {
-		u.logger.ErrorCtx(ctx, "Failed to create user", "error", err)
+		return u.publishRegistered(ctx, user, input.IPAddress)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	u.logAudit(ctx, user, input.IPAddress)
-
 	u.logger.InfoCtx(ctx, "User registration completed",
 		"user_id", user.ID.String(),
 	)
@@ -92,24 +127,23 @@ func (u *registerUseCase) Execute(ctx context.Context, input input.RegisterInput
 	}, nil
 }
 
-func (u *registerUseCase) logAudit(ctx context.Context, user *entity.User, ipAddress string) {
-	corrID := correlationid.FromContext(ctx)
-	userIDStr := user.ID.String()
-
-	auditLog, err := entity.NewAuditLog(
-		entity.AuditActionUserRegistered,
-		&userIDStr,
-		map[string]interface{}{
-			"username": user.Username.String(),
-			"email":    user.Email.String(),
-		},
-		ipAddress,
-		corrID,
-	)
+func (u *registerUseCase) publishRegistered(ctx context.Context, user *entity.User, ipAddress string) error {
+	err := u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserRegistered,
+		DedupKey:  event.DedupKey(event.UserRegistered, user.ID.String()),
+		Payload: event.NewUserEvent(
+			user.ID.String(),
+			ipAddress,
+			correlationid.FromContext(ctx),
+			map[string]interface{}{
+				"username":     user.Username.String(),
+				"email":        user.Email.String(),
+				"has_password": user.HasPassword(),
+			},
+		),
+	})
 	if err != nil {
-		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
-		return
+		u.logger.ErrorCtx(ctx, "Failed to publish user registered event", "error", err)
 	}
-
-	u.auditLogger.Log(ctx, auditLog)
+	return err
 }
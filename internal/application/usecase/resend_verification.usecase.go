@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+const resendVerificationMessage = "If an unverified account with that email exists, a verification link has been sent"
+
+type resendVerificationUseCase struct {
+	userRepo       repository.UserRepository
+	tokenRepo      repository.EmailVerificationTokenRepository
+	logger         port.Logger
+	verification   port.EmailVerificationSender
+	resendInterval time.Duration
+}
+
+func NewResendVerificationUsecase(
+	userRepo repository.UserRepository,
+	tokenRepo repository.EmailVerificationTokenRepository,
+	logger port.Logger,
+	verification port.EmailVerificationSender,
+	resendInterval time.Duration,
+) port.ResendVerificationUseCase {
+	return &resendVerificationUseCase{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		logger:         logger,
+		verification:   verification,
+		resendInterval: resendInterval,
+	}
+}
+
+// Execute answers identically for unknown, verified and throttled accounts
+// so the endpoint does not reveal which emails are registered.
+func (u *resendVerificationUseCase) Execute(ctx context.Context, input input.ResendVerificationInput) (*output.ResendVerificationOutput, error) {
+	result := &output.ResendVerificationOutput{Message: resendVerificationMessage}
+
+	user, err := u.userRepo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+
+	if user == nil || !user.IsActive || user.IsEmailVerified {
+		return result, nil
+	}
+
+	latest, err := u.tokenRepo.FindLatestByUserID(ctx, user.ID.String())
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find latest verification token", "error", err)
+		return nil, err
+	}
+
+	if latest != nil && time.Since(latest.CreatedAt) < u.resendInterval {
+		u.logger.InfoCtx(ctx, "Verification resend throttled", "user_id", user.ID.String())
+		return result, nil
+	}
+
+	if err := u.verification.Send(ctx, user); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to send verification email", "user_id", user.ID.String(), "error", err)
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Verification email resent", "user_id", user.ID.String())
+
+	return result, nil
+}
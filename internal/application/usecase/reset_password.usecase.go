@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type resetPasswordUseCase struct {
+	userRepo     repository.UserRepository
+	resetRepo    repository.PasswordResetTokenRepository
+	refreshRepo  repository.RefreshTokenRepository
+	revocations  port.AccessTokenRevocations
+	txManager    port.TxManager
+	outbox       port.Outbox
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+	hasher       port.PasswordHasher
+	passwords    port.PasswordValidator
+}
+
+func NewResetPasswordUsecase(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetTokenRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	revocations port.AccessTokenRevocations,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	hasher port.PasswordHasher,
+	passwords port.PasswordValidator,
+) port.ResetPasswordUseCase {
+	return &resetPasswordUseCase{
+		userRepo:     userRepo,
+		resetRepo:    resetRepo,
+		refreshRepo:  refreshRepo,
+		revocations:  revocations,
+		txManager:    txManager,
+		outbox:       outbox,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+		hasher:       hasher,
+		passwords:    passwords,
+	}
+}
+
+func (u *resetPasswordUseCase) Execute(ctx context.Context, input input.ResetPasswordInput) (*output.ResetPasswordOutput, error) {
+	if input.Password == "" {
+		return nil, exception.ErrPasswordRequired
+	}
+
+	token, err := u.resetRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.Token))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find password reset token", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if token == nil || token.IsUsed() || token.IsExpired(now) {
+		return nil, exception.ErrInvalidResetToken
+	}
+
+	user, err := u.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, exception.ErrInvalidResetToken
+	}
+
+	password, err := u.passwords.Validate(ctx, input.Password, user.Username.String(), user.Email.String())
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := u.hasher.Hash(password.Plaintext())
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to hash password", "error", err)
+		return nil, err
+	}
+
+	if err := user.SetPasswordHash(passwordHash); err != nil {
+		return nil, err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		marked, err := u.resetRepo.MarkUsed(ctx, token.ID, now)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to mark password reset token as used", "error", err)
+			return err
+		}
+		if !marked {
+			return exception.ErrInvalidResetToken
+		}
+
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to update user", "error", err)
+			return err
+		}
+
+		// A reset implies the old credentials may be compromised, so end
+		// every existing session and refuse the access tokens already
+		// issued.
+		if err := u.refreshRepo.RevokeByUserID(ctx, user.ID.String(), now); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to revoke sessions after password reset", "error", err)
+			return err
+		}
+		if err := u.revocations.RevokeUser(ctx, user.ID.String(), now); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to revoke access tokens after password reset", "error", err)
+			return err
+		}
+
+		if err := u.resetRepo.InvalidateByUserID(ctx, user.ID.String(), now); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to invalidate outstanding reset tokens", "error", err)
+			return err
+		}
+
+		return u.publishReset(ctx, user, token.ID, input.IPAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Password reset completed", "user_id", user.ID.String())
+
+	return &output.ResetPasswordOutput{
+		Message: "Password has been reset successfully",
+	}, nil
+}
+
+func (u *resetPasswordUseCase) publishReset(ctx context.Context, user *entity.User, tokenID, ipAddress string) error {
+	err := u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserPasswordReset,
+		DedupKey:  event.DedupKey(event.UserPasswordReset, tokenID),
+		Payload: event.NewUserEvent(
+			user.ID.String(),
+			ipAddress,
+			correlationid.FromContext(ctx),
+			map[string]interface{}{
+				"sessions_revoked": true,
+			},
+		),
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to publish password reset event", "error", err)
+	}
+	return err
+}
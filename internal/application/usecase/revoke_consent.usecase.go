@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type revokeConsentUseCase struct {
+	consentRepo repository.UserConsentRepository
+	refreshRepo repository.RefreshTokenRepository
+	txManager   port.TxManager
+	auditLogger port.AuditLogger
+	logger      port.Logger
+}
+
+func NewRevokeConsentUsecase(
+	consentRepo repository.UserConsentRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	txManager port.TxManager,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+) port.RevokeConsentUseCase {
+	return &revokeConsentUseCase{
+		consentRepo: consentRepo,
+		refreshRepo: refreshRepo,
+		txManager:   txManager,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// Execute withdraws the user's consent and revokes every refresh token
+// the client holds for them, so it cannot keep acting for the user. Its
+// access tokens run out on their own.
+func (u *revokeConsentUseCase) Execute(ctx context.Context, req input.RevokeConsentInput) error {
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := u.consentRepo.Delete(ctx, req.UserID, req.ClientID)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to delete consent", "error", err)
+			return err
+		}
+		if !deleted {
+			return exception.ErrConsentNotFound
+		}
+
+		if err := u.refreshRepo.RevokeByUserAndClient(ctx, req.UserID, req.ClientID, time.Now().UTC()); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to revoke refresh tokens", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logOAuthAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthConsentRevoked, req.UserID, req.ClientID, nil, req.IPAddress)
+	u.logger.InfoCtx(ctx, "OAuth consent revoked", "user_id", req.UserID, "client_id", req.ClientID)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type revokeTokenUseCase struct {
+	clients      port.ClientAuthenticator
+	refreshRepo  repository.RefreshTokenRepository
+	tokens       port.TokenService
+	revocations  port.AccessTokenRevocations
+	opaqueTokens port.OpaqueTokenGenerator
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+}
+
+func NewRevokeTokenUsecase(
+	clients port.ClientAuthenticator,
+	refreshRepo repository.RefreshTokenRepository,
+	tokens port.TokenService,
+	revocations port.AccessTokenRevocations,
+	opaqueTokens port.OpaqueTokenGenerator,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+) port.RevokeTokenUseCase {
+	return &revokeTokenUseCase{
+		clients:      clients,
+		refreshRepo:  refreshRepo,
+		tokens:       tokens,
+		revocations:  revocations,
+		opaqueTokens: opaqueTokens,
+		auditLogger:  auditLogger,
+		logger:       logger,
+	}
+}
+
+// Execute revokes a token issued to the client. A refresh token takes its
+// whole family with it; an access token is refused from then on until it
+// expires. As RFC 7009 section 2.2 asks, a token that is unknown, already
+// invalid or another client's is not an error, and is left alone.
+func (u *revokeTokenUseCase) Execute(ctx context.Context, req input.RevokeTokenInput) error {
+	client, err := authenticateClient(ctx, u.clients, u.logger, req.Client)
+	if err != nil {
+		return err
+	}
+	if req.Token == "" {
+		return exception.NewOAuthError(exception.OAuthInvalidRequest, "token is required")
+	}
+
+	if req.TokenTypeHint != tokenTypeHintRefreshToken {
+		if claims, err := u.tokens.InspectAccessToken(req.Token); err == nil {
+			return u.revokeAccessToken(ctx, client, claims, req.IPAddress)
+		}
+	}
+
+	stored, err := u.refreshRepo.FindByHash(ctx, u.opaqueTokens.Hash(req.Token))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find refresh token", "error", err)
+		return err
+	}
+	if stored != nil {
+		return u.revokeRefreshToken(ctx, client, stored, req.IPAddress)
+	}
+
+	if req.TokenTypeHint == tokenTypeHintRefreshToken {
+		if claims, err := u.tokens.InspectAccessToken(req.Token); err == nil {
+			return u.revokeAccessToken(ctx, client, claims, req.IPAddress)
+		}
+	}
+	return nil
+}
+
+func (u *revokeTokenUseCase) revokeAccessToken(ctx context.Context, client *entity.OAuthClient, claims *port.AccessTokenClaims, ipAddress string) error {
+	if claims.ClientID != client.ID || claims.ID == "" || u.revocations.IsRevoked(claims) {
+		return nil
+	}
+
+	if err := u.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to revoke access token", "error", err)
+		return err
+	}
+
+	var userID *string
+	if claims.UserID != "" {
+		userID = &claims.UserID
+	}
+	u.logAudit(ctx, userID, map[string]interface{}{
+		"client_id":  client.ID,
+		"token_type": tokenTypeHintAccessToken,
+		"jti":        claims.ID,
+	}, ipAddress)
+	return nil
+}
+
+func (u *revokeTokenUseCase) revokeRefreshToken(ctx context.Context, client *entity.OAuthClient, token *entity.RefreshToken, ipAddress string) error {
+	if token.ClientID != client.ID || token.IsRevoked() {
+		return nil
+	}
+
+	if err := u.refreshRepo.RevokeFamily(ctx, token.FamilyID, time.Now().UTC()); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to revoke refresh token family", "family_id", token.FamilyID, "error", err)
+		return err
+	}
+
+	u.logAudit(ctx, &token.UserID, map[string]interface{}{
+		"client_id":  client.ID,
+		"token_type": tokenTypeHintRefreshToken,
+		"family_id":  token.FamilyID,
+	}, ipAddress)
+	return nil
+}
+
+func (u *revokeTokenUseCase) logAudit(ctx context.Context, userID *string, details map[string]interface{}, ipAddress string) {
+	auditLog, err := entity.NewAuditLog(entity.AuditActionOAuthTokenRevoked, userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
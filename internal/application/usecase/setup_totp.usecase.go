@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type setupTOTPUseCase struct {
+	userRepo      repository.UserRepository
+	totpRepo      repository.TOTPCredentialRepository
+	totp          port.TOTP
+	secrets       port.SecretBox
+	qrCodes       port.QRCodeEncoder
+	uuidGenerator port.UUIDGenerator
+	logger        port.Logger
+}
+
+func NewSetupTOTPUsecase(
+	userRepo repository.UserRepository,
+	totpRepo repository.TOTPCredentialRepository,
+	totp port.TOTP,
+	secrets port.SecretBox,
+	qrCodes port.QRCodeEncoder,
+	uuidGenerator port.UUIDGenerator,
+	logger port.Logger,
+) port.SetupTOTPUseCase {
+	return &setupTOTPUseCase{
+		userRepo:      userRepo,
+		totpRepo:      totpRepo,
+		totp:          totp,
+		secrets:       secrets,
+		qrCodes:       qrCodes,
+		uuidGenerator: uuidGenerator,
+		logger:        logger,
+	}
+}
+
+func (u *setupTOTPUseCase) Execute(ctx context.Context, input input.SetupTOTPInput) (*output.SetupTOTPOutput, error) {
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	existing, err := u.totpRepo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find TOTP credential", "error", err)
+		return nil, err
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, exception.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := u.totp.GenerateSecret()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate TOTP secret", "error", err)
+		return nil, err
+	}
+
+	ciphertext, err := u.secrets.Seal([]byte(secret))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to encrypt TOTP secret", "error", err)
+		return nil, err
+	}
+
+	// Starting setup again replaces a pending secret, so only the most
+	// recently scanned QR code can be confirmed.
+	credential := entity.NewTOTPCredential(u.uuidGenerator.Generate(), user.ID.String(), ciphertext)
+	if err := u.totpRepo.Save(ctx, credential); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to save TOTP credential", "error", err)
+		return nil, err
+	}
+
+	uri := u.totp.ProvisioningURI(secret, user.Email.String())
+	png, err := u.qrCodes.EncodePNG(uri)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to encode QR code", "error", err)
+		return nil, err
+	}
+
+	return &output.SetupTOTPOutput{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRCodePNG:       png,
+	}, nil
+}
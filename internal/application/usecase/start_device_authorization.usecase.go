@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type startDeviceAuthorizationUseCase struct {
+	clients       port.ClientAuthenticator
+	deviceRepo    repository.DeviceAuthorizationRepository
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	userCodes     port.OneTimeCodeGenerator
+	uuidGenerator port.UUIDGenerator
+
+	verificationURL string
+	codeTTL         time.Duration
+	pollInterval    time.Duration
+}
+
+func NewStartDeviceAuthorizationUsecase(
+	clients port.ClientAuthenticator,
+	deviceRepo repository.DeviceAuthorizationRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	userCodes port.OneTimeCodeGenerator,
+	uuidGenerator port.UUIDGenerator,
+	verificationURL string,
+	codeTTL time.Duration,
+	pollInterval time.Duration,
+) port.StartDeviceAuthorizationUseCase {
+	return &startDeviceAuthorizationUseCase{
+		clients:       clients,
+		deviceRepo:    deviceRepo,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		userCodes:     userCodes,
+		uuidGenerator: uuidGenerator,
+
+		verificationURL: verificationURL,
+		codeTTL:         codeTTL,
+		pollInterval:    pollInterval,
+	}
+}
+
+// Execute issues a device code for the client to poll with and a user
+// code for the user to enter on the verification page (RFC 8628 section
+// 3.2). Public clients are accepted, as most devices cannot keep a
+// secret.
+func (u *startDeviceAuthorizationUseCase) Execute(ctx context.Context, req input.StartDeviceAuthorizationInput) (*output.StartDeviceAuthorizationOutput, error) {
+	client, err := authenticateClient(ctx, u.clients, u.logger, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	if !client.AllowsGrantType(entity.OAuthGrantDeviceCode) {
+		return nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "client may not use the device authorization grant")
+	}
+
+	scopes := entity.ParseScope(req.Scope)
+	if len(scopes) == 0 {
+		return nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope "+scope+" is not allowed")
+		}
+	}
+
+	deviceCode, err := u.opaqueTokens.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate device code", "error", err)
+		return nil, err
+	}
+	userCode, err := u.userCodes.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate user code", "error", err)
+		return nil, err
+	}
+
+	authorization := entity.NewDeviceAuthorization(
+		u.uuidGenerator.Generate(),
+		client.ID,
+		u.opaqueTokens.Hash(deviceCode),
+		u.opaqueTokens.Hash(entity.NormalizeUserCode(userCode)),
+		scopes,
+		u.uuidGenerator.Generate(),
+		u.pollInterval,
+		time.Now().UTC().Add(u.codeTTL),
+	)
+	if err := u.deviceRepo.Create(ctx, authorization); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to store device authorization", "error", err)
+		return nil, err
+	}
+
+	return &output.StartDeviceAuthorizationOutput{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         u.verificationURL,
+		VerificationURIComplete: u.verificationURL + "?" + url.Values{"user_code": {userCode}}.Encode(),
+		ExpiresAt:               authorization.ExpiresAt,
+		Interval:                authorization.Interval,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type startFederatedLoginUseCase struct {
+	providers     port.IdentityProviders
+	loginRepo     repository.FederatedLoginRepository
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+	loginTTL      time.Duration
+}
+
+func NewStartFederatedLoginUsecase(
+	providers port.IdentityProviders,
+	loginRepo repository.FederatedLoginRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	loginTTL time.Duration,
+) port.StartFederatedLoginUseCase {
+	return &startFederatedLoginUseCase{
+		providers:     providers,
+		loginRepo:     loginRepo,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+		loginTTL:      loginTTL,
+	}
+}
+
+// Execute records a pending sign-in with a fresh state, nonce and PKCE
+// code verifier, and returns the provider's authorization URL for it.
+func (u *startFederatedLoginUseCase) Execute(ctx context.Context, input input.StartFederatedLoginInput) (*output.StartFederatedLoginOutput, error) {
+	provider := u.providers.Find(input.Provider)
+	if provider == nil {
+		return nil, exception.ErrUnknownIdentityProvider
+	}
+
+	var secrets [4]string
+	for i := range secrets {
+		secret, err := u.opaqueTokens.Generate()
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to generate federated login secret", "error", err)
+			return nil, err
+		}
+		secrets[i] = secret
+	}
+	state, deviceToken, nonce, codeVerifier := secrets[0], secrets[1], secrets[2], secrets[3]
+
+	expiresAt := time.Now().UTC().Add(u.loginTTL)
+	login := entity.NewFederatedLogin(
+		u.uuidGenerator.Generate(),
+		provider.ID(),
+		u.opaqueTokens.Hash(state),
+		u.opaqueTokens.Hash(deviceToken),
+		nonce,
+		codeVerifier,
+		expiresAt,
+	)
+
+	authorizationURL, err := provider.AuthorizationURL(ctx, state, nonce, login.CodeChallenge())
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to reach identity provider", "provider", provider.ID(), "error", err)
+		return nil, exception.ErrIdentityProviderUnavailable
+	}
+
+	if err := u.loginRepo.Create(ctx, login); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create federated login", "error", err)
+		return nil, err
+	}
+
+	return &output.StartFederatedLoginOutput{
+		AuthorizationURL: authorizationURL,
+		DeviceToken:      deviceToken,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
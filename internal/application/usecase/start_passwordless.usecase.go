@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+const startPasswordlessMessage = "If an account with that email exists, a sign-in email has been sent"
+
+type startPasswordlessUseCase struct {
+	userRepo      repository.UserRepository
+	outbox        port.Outbox
+	auditLogger   port.AuditLogger
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	uuidGenerator port.UUIDGenerator
+
+	linkTTL time.Duration
+	codeTTL time.Duration
+}
+
+func NewStartPasswordlessUsecase(
+	userRepo repository.UserRepository,
+	outbox port.Outbox,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	uuidGenerator port.UUIDGenerator,
+	linkTTL time.Duration,
+	codeTTL time.Duration,
+) port.StartPasswordlessUseCase {
+	return &startPasswordlessUseCase{
+		userRepo:      userRepo,
+		outbox:        outbox,
+		auditLogger:   auditLogger,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		uuidGenerator: uuidGenerator,
+
+		linkTTL: linkTTL,
+		codeTTL: codeTTL,
+	}
+}
+
+// Execute answers identically for unknown, inactive and throttled
+// accounts, including a device token, so the endpoint does not reveal
+// which emails are registered. The email is sent from the outbox, which
+// also applies the resend interval.
+func (u *startPasswordlessUseCase) Execute(ctx context.Context, input input.StartPasswordlessInput) (*output.StartPasswordlessOutput, error) {
+	method := entity.PasswordlessMethod(input.Method)
+	if !method.IsValid() {
+		return nil, exception.ErrInvalidPasswordlessMethod
+	}
+
+	deviceToken, err := u.opaqueTokens.Generate()
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to generate device token", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := &output.StartPasswordlessOutput{
+		DeviceToken: deviceToken,
+		Method:      string(method),
+		ExpiresAt:   now.Add(u.ttl(method)),
+		Message:     startPasswordlessMessage,
+	}
+
+	user, err := u.userRepo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+
+	if user == nil || !user.IsActive {
+		u.logger.InfoCtx(ctx, "Passwordless sign-in requested for unknown or inactive account")
+		return result, nil
+	}
+
+	err = u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserPasswordlessRequested,
+		DedupKey:  event.DedupKey(event.UserPasswordlessRequested, u.uuidGenerator.Generate()),
+		Payload: event.PasswordlessRequestedEvent{
+			UserID:        user.ID.String(),
+			Method:        string(method),
+			DeviceHash:    u.opaqueTokens.Hash(deviceToken),
+			IPAddress:     input.IPAddress,
+			CorrelationID: correlationid.FromContext(ctx),
+			RequestedAt:   now,
+			ExpiresAt:     result.ExpiresAt,
+		},
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to publish passwordless sign-in request", "user_id", user.ID.String(), "error", err)
+		return nil, err
+	}
+
+	u.logAudit(ctx, user, method, input.IPAddress)
+	u.logger.InfoCtx(ctx, "Passwordless sign-in requested", "user_id", user.ID.String(), "method", string(method))
+
+	return result, nil
+}
+
+func (u *startPasswordlessUseCase) ttl(method entity.PasswordlessMethod) time.Duration {
+	if method == entity.PasswordlessMethodLink {
+		return u.linkTTL
+	}
+	return u.codeTTL
+}
+
+func (u *startPasswordlessUseCase) logAudit(ctx context.Context, user *entity.User, method entity.PasswordlessMethod, ipAddress string) {
+	userID := user.ID.String()
+
+	auditLog, err := entity.NewAuditLog(entity.AuditActionPasswordlessRequested, &userID, map[string]interface{}{
+		"method": string(method),
+	}, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
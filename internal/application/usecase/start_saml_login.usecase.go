@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type startSAMLLoginUseCase struct {
+	idps         port.SAMLIdentityProviders
+	requestRepo  repository.SAMLRequestRepository
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+	requestTTL   time.Duration
+}
+
+func NewStartSAMLLoginUsecase(
+	idps port.SAMLIdentityProviders,
+	requestRepo repository.SAMLRequestRepository,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	requestTTL time.Duration,
+) port.StartSAMLLoginUseCase {
+	return &startSAMLLoginUseCase{
+		idps:         idps,
+		requestRepo:  requestRepo,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+		requestTTL:   requestTTL,
+	}
+}
+
+// Execute records an authentication request under a fresh ID and returns
+// it for the browser to post to the identity provider.
+func (u *startSAMLLoginUseCase) Execute(ctx context.Context, input input.StartSAMLLoginInput) (*output.StartSAMLLoginOutput, error) {
+	provider := u.idps.Find(input.IdP)
+	if provider == nil {
+		return nil, exception.ErrUnknownIdentityProvider
+	}
+
+	var secrets [2]string
+	for i := range secrets {
+		secret, err := u.opaqueTokens.Generate()
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to generate SAML request secret", "error", err)
+			return nil, err
+		}
+		secrets[i] = secret
+	}
+	// Request IDs are XML IDs, which cannot start with a digit.
+	requestID, deviceToken := "_"+secrets[0], secrets[1]
+
+	now := time.Now().UTC()
+	authnRequest, err := provider.AuthnRequest(requestID, now)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to build SAML authentication request", "idp", provider.ID(), "error", err)
+		return nil, err
+	}
+
+	expiresAt := now.Add(u.requestTTL)
+	request := entity.NewSAMLRequest(requestID, provider.ID(), u.opaqueTokens.Hash(deviceToken), expiresAt)
+	if err := u.requestRepo.Create(ctx, request); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create SAML request", "error", err)
+		return nil, err
+	}
+
+	return &output.StartSAMLLoginOutput{
+		SSOURL:      authnRequest.URL,
+		SAMLRequest: authnRequest.SAMLRequest,
+		DeviceToken: deviceToken,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type unlockAccountUseCase struct {
+	userRepo    repository.UserRepository
+	auditLogger port.AuditLogger
+	logger      port.Logger
+}
+
+func NewUnlockAccountUsecase(
+	userRepo repository.UserRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+) port.UnlockAccountUseCase {
+	return &unlockAccountUseCase{
+		userRepo:    userRepo,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+func (u *unlockAccountUseCase) Execute(ctx context.Context, input input.UnlockAccountInput) (*output.UnlockAccountOutput, error) {
+	if _, err := vo.NewUserID(input.UserID); err != nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	wasLocked := user.IsLocked(time.Now().UTC())
+
+	// Unlocking also forgets previous lockouts, so the next one starts
+	// again from the base duration.
+	if user.HasLockoutState() {
+		if err := u.userRepo.ResetFailedLogins(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to unlock account", "user_id", user.ID.String(), "error", err)
+			return nil, err
+		}
+	}
+
+	userID := user.ID.String()
+	details := map[string]interface{}{
+		"was_locked": wasLocked,
+	}
+	auditLog, err := entity.NewAuditLog(entity.AuditActionAccountUnlocked, &userID, details, input.IPAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+	} else {
+		u.auditLogger.Log(ctx, auditLog)
+	}
+
+	u.logger.InfoCtx(ctx, "Account unlocked", "user_id", userID, "was_locked", wasLocked)
+
+	return &output.UnlockAccountOutput{
+		UserID:    userID,
+		WasLocked: wasLocked,
+		Message:   "Account unlocked",
+	}, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type updateClientRegistrationUseCase struct {
+	clientRepo   repository.OAuthClientRepository
+	auditLogger  port.AuditLogger
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+	policy       clientMetadataPolicy
+}
+
+func NewUpdateClientRegistrationUsecase(
+	clientRepo repository.OAuthClientRepository,
+	auditLogger port.AuditLogger,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	assertions port.ClientAssertionVerifier,
+	scopes []string,
+	maxAccessTokenTTL time.Duration,
+) port.UpdateClientRegistrationUseCase {
+	return &updateClientRegistrationUseCase{
+		clientRepo:   clientRepo,
+		auditLogger:  auditLogger,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+		policy: clientMetadataPolicy{
+			scopes:            scopes,
+			maxAccessTokenTTL: maxAccessTokenTTL,
+			assertions:        assertions,
+		},
+	}
+}
+
+func (u *updateClientRegistrationUseCase) Execute(ctx context.Context, req input.UpdateClientRegistrationInput) (*output.OAuthClientOutput, error) {
+	client, err := findRegisteredClient(ctx, u.clientRepo, u.logger, u.opaqueTokens, req.ClientID, req.RegistrationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, confidential, err := u.policy.check(req.Client)
+	if err != nil {
+		return nil, err
+	}
+	// Switching between a secret, a key set and no credentials at all
+	// would need new credentials, which only registration hands out.
+	if confidential != client.IsConfidential() || (updated.JWKS != "") != (client.JWKS != "") {
+		return nil, fmt.Errorf("%w: token endpoint auth method cannot change from %q", exception.ErrInvalidClientMetadata, client.AuthMethod())
+	}
+
+	updated.ID = client.ID
+	updated.SecretHash = client.SecretHash
+	updated.RegistrationTokenHash = client.RegistrationTokenHash
+	updated.FirstParty = client.FirstParty
+	updated.CreatedAt = client.CreatedAt
+	if err := u.clientRepo.Update(ctx, updated); err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to update OAuth client", "error", err)
+		return nil, err
+	}
+
+	logClientAudit(ctx, u.auditLogger, u.logger, entity.AuditActionOAuthClientUpdated, updated, req.Client.IPAddress)
+	u.logger.InfoCtx(ctx, "OAuth client updated", "client_id", updated.ID)
+
+	out := oauthClientOutput(updated)
+	return &out, nil
+}
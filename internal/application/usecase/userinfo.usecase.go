@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type userInfoUseCase struct {
+	userRepo repository.UserRepository
+	logger   port.Logger
+}
+
+func NewUserInfoUsecase(
+	userRepo repository.UserRepository,
+	logger port.Logger,
+) port.UserInfoUseCase {
+	return &userInfoUseCase{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+func (u *userInfoUseCase) Execute(ctx context.Context, input input.UserInfoInput) (*output.UserInfoOutput, error) {
+	if !slices.Contains(input.Scopes, entity.ScopeOpenID) {
+		return nil, exception.ErrInsufficientScope
+	}
+
+	user, err := u.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, exception.ErrUserNotFound
+	}
+
+	claims := oidcUserClaims(user, input.Scopes)
+	claims["sub"] = user.ID.String()
+
+	return &output.UserInfoOutput{Claims: claims}, nil
+}
+
+// oidcUserClaims returns the standard claims about user that the granted
+// scopes release, shared by the ID token and the userinfo response.
+func oidcUserClaims(user *entity.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{}
+	if slices.Contains(scopes, entity.ScopeProfile) {
+		claims["preferred_username"] = user.Username.String()
+	}
+	if slices.Contains(scopes, entity.ScopeEmail) {
+		claims["email"] = user.Email.String()
+		claims["email_verified"] = user.IsEmailVerified
+	}
+	return claims
+}
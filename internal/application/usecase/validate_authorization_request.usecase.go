@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+const (
+	oauthResponseTypeCode = "code"
+	maxOAuthNonceLength   = 255
+	oidcPromptNone        = "none"
+	oidcPromptConsent     = "consent"
+)
+
+type validateAuthorizationRequestUseCase struct {
+	clientRepo repository.OAuthClientRepository
+	logger     port.Logger
+	scopes     []string
+}
+
+func NewValidateAuthorizationRequestUsecase(
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+	scopes []string,
+) port.ValidateAuthorizationRequestUseCase {
+	return &validateAuthorizationRequestUseCase{
+		clientRepo: clientRepo,
+		logger:     logger,
+		scopes:     scopes,
+	}
+}
+
+func (u *validateAuthorizationRequestUseCase) Execute(ctx context.Context, input input.AuthorizationRequest) (*output.AuthorizationRequestOutput, error) {
+	client, scopes, err := validateAuthorizationRequest(ctx, u.clientRepo, u.logger, u.scopes, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.AuthorizationRequestOutput{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		RedirectURI: input.RedirectURI,
+		Scopes:      scopes,
+		State:       input.State,
+	}, nil
+}
+
+// validateAuthorizationRequest checks an authorization request and returns
+// the client and the requested scopes. An unknown client or unregistered
+// redirect URI is reported as ErrInvalidOAuthClient or
+// ErrInvalidRedirectURI and must not be redirected to; every other
+// rejection is an OAuthError for the client's redirect URI.
+func validateAuthorizationRequest(
+	ctx context.Context,
+	clientRepo repository.OAuthClientRepository,
+	logger port.Logger,
+	supportedScopes []string,
+	req input.AuthorizationRequest,
+) (*entity.OAuthClient, []string, error) {
+	if req.ClientID == "" {
+		return nil, nil, exception.ErrInvalidOAuthClient
+	}
+
+	client, err := clientRepo.FindByID(ctx, req.ClientID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to find OAuth client", "error", err)
+		return nil, nil, err
+	}
+	if client == nil {
+		return nil, nil, exception.ErrInvalidOAuthClient
+	}
+
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, nil, exception.ErrInvalidRedirectURI
+	}
+
+	if !client.AllowsGrantType(entity.OAuthGrantAuthorizationCode) {
+		return nil, nil, exception.NewOAuthError(exception.OAuthUnauthorizedClient, "client may not use the authorization code grant")
+	}
+	if req.ResponseType != oauthResponseTypeCode {
+		return nil, nil, exception.NewOAuthError(exception.OAuthUnsupportedResponseType, "response_type must be code")
+	}
+
+	if req.CodeChallenge == "" {
+		return nil, nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "code_challenge is required")
+	}
+	if req.CodeChallengeMethod != entity.PKCEMethodS256 {
+		return nil, nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "code_challenge_method must be S256")
+	}
+	if !entity.IsValidCodeChallenge(req.CodeChallenge) {
+		return nil, nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "code_challenge is malformed")
+	}
+	if len(req.Nonce) > maxOAuthNonceLength {
+		return nil, nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "nonce is too long")
+	}
+	if req.RequestObject != "" {
+		return nil, nil, exception.NewOAuthError(exception.OIDCRequestNotSupported, "request objects are not supported")
+	}
+	if req.RequestURI != "" {
+		return nil, nil, exception.NewOAuthError(exception.OIDCRequestURINotSupported, "request_uri is not supported")
+	}
+
+	// There is no single sign-on session to reuse, so the user always has
+	// to sign in and prompt=none can never succeed.
+	if prompt := strings.Fields(req.Prompt); slices.Contains(prompt, oidcPromptNone) {
+		if len(prompt) > 1 {
+			return nil, nil, exception.NewOAuthError(exception.OAuthInvalidRequest, "prompt=none cannot be combined with other values")
+		}
+		return nil, nil, exception.NewOAuthError(exception.OIDCLoginRequired, "the user must sign in")
+	}
+
+	scopes := entity.ParseScope(req.Scope)
+	if len(scopes) == 0 {
+		return nil, nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(supportedScopes, scope) || !slices.Contains(client.Scopes, scope) {
+			return nil, nil, exception.NewOAuthError(exception.OAuthInvalidScope, "scope "+scope+" is not allowed")
+		}
+	}
+
+	return client, scopes, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type verifyEmailUseCase struct {
+	userRepo     repository.UserRepository
+	tokenRepo    repository.EmailVerificationTokenRepository
+	txManager    port.TxManager
+	outbox       port.Outbox
+	logger       port.Logger
+	opaqueTokens port.OpaqueTokenGenerator
+}
+
+func NewVerifyEmailUsecase(
+	userRepo repository.UserRepository,
+	tokenRepo repository.EmailVerificationTokenRepository,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+) port.VerifyEmailUseCase {
+	return &verifyEmailUseCase{
+		userRepo:     userRepo,
+		tokenRepo:    tokenRepo,
+		txManager:    txManager,
+		outbox:       outbox,
+		logger:       logger,
+		opaqueTokens: opaqueTokens,
+	}
+}
+
+func (u *verifyEmailUseCase) Execute(ctx context.Context, input input.VerifyEmailInput) (*output.VerifyEmailOutput, error) {
+	token, err := u.tokenRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.Token))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find email verification token", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if token == nil || token.IsUsed() || token.IsExpired(now) {
+		return nil, exception.ErrInvalidVerificationToken
+	}
+
+	user, err := u.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrInvalidVerificationToken
+	}
+
+	if err := user.VerifyEmail(); err != nil {
+		return nil, err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		marked, err := u.tokenRepo.MarkUsed(ctx, token.ID, now)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to mark email verification token as used", "error", err)
+			return err
+		}
+		if !marked {
+			return exception.ErrInvalidVerificationToken
+		}
+
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to update user", "error", err)
+			return err
+		}
+
+		return u.publishVerified(ctx, user, token.ID, input.IPAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoCtx(ctx, "Email verified", "user_id", user.ID.String())
+
+	return &output.VerifyEmailOutput{
+		UserID:  user.ID.String(),
+		Message: "Email verified successfully",
+	}, nil
+}
+
+func (u *verifyEmailUseCase) publishVerified(ctx context.Context, user *entity.User, tokenID, ipAddress string) error {
+	err := u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserEmailVerified,
+		DedupKey:  event.DedupKey(event.UserEmailVerified, tokenID),
+		Payload: event.NewUserEvent(
+			user.ID.String(),
+			ipAddress,
+			correlationid.FromContext(ctx),
+			map[string]interface{}{
+				"email": user.Email.String(),
+			},
+		),
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to publish email verified event", "error", err)
+	}
+	return err
+}
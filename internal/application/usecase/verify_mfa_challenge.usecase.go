@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type verifyMFAChallengeUseCase struct {
+	challengeRepo repository.MFAChallengeRepository
+	userRepo      repository.UserRepository
+	verifier      port.MFAVerifier
+	passkeys      port.PasskeyAuthenticator
+	sessions      port.SessionIssuer
+	txManager     port.TxManager
+	auditLogger   port.AuditLogger
+	metrics       port.AuthMetrics
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	maxAttempts   int
+}
+
+func NewVerifyMFAChallengeUsecase(
+	challengeRepo repository.MFAChallengeRepository,
+	userRepo repository.UserRepository,
+	verifier port.MFAVerifier,
+	passkeys port.PasskeyAuthenticator,
+	sessions port.SessionIssuer,
+	txManager port.TxManager,
+	auditLogger port.AuditLogger,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	maxAttempts int,
+) port.VerifyMFAChallengeUseCase {
+	return &verifyMFAChallengeUseCase{
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		verifier:      verifier,
+		passkeys:      passkeys,
+		sessions:      sessions,
+		txManager:     txManager,
+		auditLogger:   auditLogger,
+		metrics:       metrics,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+func (u *verifyMFAChallengeUseCase) Execute(ctx context.Context, input input.VerifyMFAChallengeInput) (*output.LoginOutput, error) {
+	challenge, err := u.challengeRepo.FindByHash(ctx, u.opaqueTokens.Hash(input.MFAToken))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find MFA challenge", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if challenge == nil || challenge.IsUsed() || challenge.IsExpired(now) || challenge.Attempts >= u.maxAttempts {
+		return nil, exception.ErrInvalidMFAChallenge
+	}
+
+	user, err := u.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, exception.ErrInvalidMFAChallenge
+	}
+
+	var method string
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		method, err = u.verify(ctx, user.ID.String(), input)
+		if err != nil {
+			return err
+		}
+
+		marked, err := u.challengeRepo.MarkUsed(ctx, challenge.ID, now)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to mark MFA challenge as used", "error", err)
+			return err
+		}
+		if !marked {
+			return exception.ErrInvalidMFAChallenge
+		}
+		return nil
+	})
+	if isMFARejection(err) {
+		attempts, incErr := u.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+		if incErr != nil {
+			u.logger.ErrorCtx(ctx, "Failed to count MFA attempt", "error", incErr)
+		}
+		u.metrics.RecordLoginAttempt(port.LoginStatusMFAFailed)
+		u.logAudit(ctx, entity.AuditActionMFAChallengeFailed, user, input.IPAddress, map[string]interface{}{
+			"method":   method,
+			"attempts": attempts,
+		})
+		return nil, err
+	}
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to verify MFA challenge", "error", err)
+		return nil, err
+	}
+
+	if input.VerifyOnly {
+		u.metrics.RecordLoginAttempt(port.LoginStatusSuccess)
+		u.logAudit(ctx, entity.AuditActionMFAChallengeSucceeded, user, input.IPAddress, map[string]interface{}{"method": method})
+		u.logger.InfoCtx(ctx, "User credentials verified", "user_id", user.ID.String(), "mfa_method", method)
+		return &output.LoginOutput{UserID: user.ID.String()}, nil
+	}
+
+	session, err := u.sessions.Issue(ctx, user, "")
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to issue session", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	u.metrics.RecordLoginAttempt(port.LoginStatusSuccess)
+	u.logAudit(ctx, entity.AuditActionMFAChallengeSucceeded, user, input.IPAddress, map[string]interface{}{"method": method})
+	u.logAudit(ctx, entity.AuditActionUserLogin, user, input.IPAddress, map[string]interface{}{"mfa_method": method})
+
+	u.logger.InfoCtx(ctx, "User logged in", "user_id", user.ID.String(), "mfa_method", method)
+
+	return &output.LoginOutput{
+		UserID:       user.ID.String(),
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresAt:    session.AccessTokenExpiresAt,
+	}, nil
+}
+
+// verify checks the passkey assertion when one is given and the code
+// otherwise. The password was already checked, so user presence is
+// enough from the passkey.
+func (u *verifyMFAChallengeUseCase) verify(ctx context.Context, userID string, input input.VerifyMFAChallengeInput) (string, error) {
+	if input.Passkey == nil {
+		return u.verifier.VerifyCode(ctx, userID, input.Code)
+	}
+
+	_, err := u.passkeys.VerifyAssertion(ctx, passkeyAssertionResponse(*input.Passkey), userID, false)
+	if errors.Is(err, exception.ErrPasskeySignCount) {
+		u.logger.WarnCtx(ctx, "Passkey signature counter did not increase; the credential may be cloned", "user_id", userID)
+	}
+	return port.MFAMethodPasskey, err
+}
+
+func isMFARejection(err error) bool {
+	return errors.Is(err, exception.ErrInvalidMFACode) || isPasskeyRejection(err)
+}
+
+func (u *verifyMFAChallengeUseCase) logAudit(ctx context.Context, action entity.AuditAction, user *entity.User, ipAddress string, details map[string]interface{}) {
+	userID := user.ID.String()
+
+	auditLog, err := entity.NewAuditLog(action, &userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/correlationid"
+)
+
+type verifyPasswordlessUseCase struct {
+	challengeRepo repository.PasswordlessChallengeRepository
+	userRepo      repository.UserRepository
+	logins        port.LoginCompleter
+	txManager     port.TxManager
+	outbox        port.Outbox
+	auditLogger   port.AuditLogger
+	metrics       port.AuthMetrics
+	logger        port.Logger
+	opaqueTokens  port.OpaqueTokenGenerator
+	signer        port.LinkSigner
+	maxAttempts   int
+}
+
+func NewVerifyPasswordlessUsecase(
+	challengeRepo repository.PasswordlessChallengeRepository,
+	userRepo repository.UserRepository,
+	logins port.LoginCompleter,
+	txManager port.TxManager,
+	outbox port.Outbox,
+	auditLogger port.AuditLogger,
+	metrics port.AuthMetrics,
+	logger port.Logger,
+	opaqueTokens port.OpaqueTokenGenerator,
+	signer port.LinkSigner,
+	maxAttempts int,
+) port.VerifyPasswordlessUseCase {
+	return &verifyPasswordlessUseCase{
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		logins:        logins,
+		txManager:     txManager,
+		outbox:        outbox,
+		auditLogger:   auditLogger,
+		metrics:       metrics,
+		logger:        logger,
+		opaqueTokens:  opaqueTokens,
+		signer:        signer,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+func (u *verifyPasswordlessUseCase) Execute(ctx context.Context, input input.VerifyPasswordlessInput) (*output.LoginOutput, error) {
+	if input.DeviceToken == "" {
+		return nil, exception.ErrInvalidPasswordlessChallenge
+	}
+
+	challenge, err := u.challengeRepo.FindByDeviceHash(ctx, u.opaqueTokens.Hash(input.DeviceToken))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find passwordless challenge", "error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if challenge == nil || challenge.IsUsed() || challenge.IsExpired(now) || challenge.Attempts >= u.maxAttempts {
+		return nil, exception.ErrInvalidPasswordlessChallenge
+	}
+
+	user, err := u.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to find user", "error", err)
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+	if user == nil {
+		return nil, exception.ErrInvalidPasswordlessChallenge
+	}
+
+	method := passwordlessLoginMethod(challenge.Method)
+
+	if !u.matches(challenge, input) {
+		attempts, incErr := u.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+		if incErr != nil {
+			u.logger.ErrorCtx(ctx, "Failed to count passwordless attempt", "error", incErr)
+		}
+		u.metrics.RecordLoginAttempt(port.LoginStatusInvalidCredentials)
+		u.logAudit(ctx, entity.AuditActionUserLoginFailed, user, input.IPAddress, map[string]interface{}{
+			"method":   method,
+			"reason":   "invalid_code",
+			"attempts": attempts,
+		})
+		return nil, exception.ErrInvalidPasswordlessCode
+	}
+
+	// Refused before the challenge is used up, so a locked account can
+	// still sign in with it once unlocked.
+	attempt := port.LoginAttempt{User: user, Method: method, IPAddress: input.IPAddress}
+	if err := u.logins.Check(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		marked, err := u.challengeRepo.MarkUsed(ctx, challenge.ID, now)
+		if err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to mark passwordless challenge as used", "error", err)
+			return err
+		}
+		if !marked {
+			return exception.ErrInvalidPasswordlessChallenge
+		}
+
+		// Receiving the email proves the address, which is all a
+		// verification link would.
+		if user.IsEmailVerified {
+			return nil
+		}
+		if err := user.VerifyEmail(); err != nil {
+			return err
+		}
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			u.logger.ErrorCtx(ctx, "Failed to update user", "error", err)
+			return err
+		}
+		return u.publishVerified(ctx, user, challenge.ID, input.IPAddress)
+	})
+	if err != nil {
+		u.metrics.RecordLoginAttempt(port.LoginStatusError)
+		return nil, err
+	}
+
+	result, err := u.logins.Complete(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+	return loginOutput(user, result), nil
+}
+
+// matches checks the link token or code against the challenge. Only the
+// device that started the sign-in knows the token the challenge was
+// found by, so a forwarded link or code is useless elsewhere.
+func (u *verifyPasswordlessUseCase) matches(challenge *entity.PasswordlessChallenge, input input.VerifyPasswordlessInput) bool {
+	var hash string
+	switch challenge.Method {
+	case entity.PasswordlessMethodLink:
+		id, err := u.signer.Verify(input.Token)
+		if err != nil || id != challenge.ID {
+			return false
+		}
+		hash = u.opaqueTokens.Hash(input.Token)
+	case entity.PasswordlessMethodCode:
+		code := strings.TrimSpace(input.Code)
+		if code == "" {
+			return false
+		}
+		hash = u.opaqueTokens.Hash(u.signer.Sign(entity.PasswordlessCodePayload(challenge.DeviceHash, code)))
+	default:
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(challenge.SecretHash)) == 1
+}
+
+func (u *verifyPasswordlessUseCase) publishVerified(ctx context.Context, user *entity.User, challengeID, ipAddress string) error {
+	err := u.outbox.Publish(ctx, port.OutboxMessage{
+		EventType: event.UserEmailVerified,
+		DedupKey:  event.DedupKey(event.UserEmailVerified, challengeID),
+		Payload: event.NewUserEvent(
+			user.ID.String(),
+			ipAddress,
+			correlationid.FromContext(ctx),
+			map[string]interface{}{
+				"email": user.Email.String(),
+			},
+		),
+	})
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to publish email verified event", "error", err)
+	}
+	return err
+}
+
+func (u *verifyPasswordlessUseCase) logAudit(ctx context.Context, action entity.AuditAction, user *entity.User, ipAddress string, details map[string]interface{}) {
+	userID := user.ID.String()
+
+	auditLog, err := entity.NewAuditLog(action, &userID, details, ipAddress, correlationid.FromContext(ctx))
+	if err != nil {
+		u.logger.ErrorCtx(ctx, "Failed to create audit log", "error", err)
+		return
+	}
+
+	u.auditLogger.Log(ctx, auditLog)
+}
+
+func passwordlessLoginMethod(method entity.PasswordlessMethod) string {
+	if method == entity.PasswordlessMethodLink {
+		return "magic_link"
+	}
+	return "email_code"
+}
@@ -54,7 +54,9 @@ func (a *App) Run() error {
 	}
 
 	a.initMetrics()
-	a.initServices()
+	if err := a.initServices(); err != nil {
+		return err
+	}
 	a.initHandlers()
 	a.initServer()
 
@@ -92,21 +94,32 @@ func (a *App) initMetrics() {
 	a.metrics.RegisterDBStats(prometheus.DefaultRegisterer, a.db.SQL())
 }
 
-func (a *App) initServices() {
-	a.services = NewServices(a.db, a.logger)
+func (a *App) initServices() error {
+	services, err := NewServices(a.cfg, a.db, a.logger)
+	if err != nil {
+		a.logger.Error("Failed to initialize services", zap.Error(err))
+		return err
+	}
+	a.services = services
+	a.metrics.RegisterSigningKeyStats(prometheus.DefaultRegisterer, services.SigningKeys())
 	a.services.Start()
+	return nil
 }
 
 func (a *App) initHandlers() {
-	a.handlers = NewHandlers(a.db, a.services.Audit(), a.logger)
+	a.handlers = NewHandlers(a.cfg, a.db, a.services, a.logger, a.metrics)
 }
 
 func (a *App) initServer() {
 	a.server = NewServer(ServerOptions{
-		Config:   a.cfg.Server,
-		Logger:   a.logger,
-		Metrics:  a.metrics,
-		Handlers: a.handlers,
+		Config:      a.cfg.Server,
+		Logger:      a.logger,
+		Metrics:     a.metrics,
+		Handlers:    a.handlers,
+		Admin:       a.cfg.Admin,
+		OAuth:       a.cfg.OAuth,
+		Tokens:      a.services.Tokens(),
+		Revocations: a.services.Revocations(),
 	})
 }
 
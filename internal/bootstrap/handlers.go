@@ -1,32 +1,546 @@
 package bootstrap
 
 import (
+	"crypto/x509"
+	"net/http"
+	"strings"
+
 	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
 	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/federation"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/ldap"
 	infralogger "github.com/thanhnamdk2710/auth-service/internal/infrastructure/logger"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/password"
 	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/persistence/postgres"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/qrcode"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/saml"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
 	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/uuid"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webauthn"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/metrics"
 	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/handler"
 )
 
 type Handlers struct {
-	Auth *handler.AuthHandler
+	Auth         *handler.AuthHandler
+	Debug        *handler.DebugHandler
+	Admin        *handler.AdminHandler
+	MFA          *handler.MFAHandler
+	Passkey      *handler.PasskeyHandler
+	Passwordless *handler.PasswordlessHandler
+	Federation   *handler.FederationHandler
+	SAML         *handler.SAMLHandler
+	OAuth        *handler.OAuthHandler
+	Device       *handler.DeviceHandler
+	Consent      *handler.ConsentHandler
+	Logout       *handler.LogoutHandler
+	OIDC         *handler.OIDCHandler
+	Registration *handler.RegistrationHandler
 }
 
-func NewHandlers(db *Database, auditLogger port.AuditLogger, log *logger.Logger) *Handlers {
+func NewHandlers(cfg *config.Config, db *Database, services *Services, log *logger.Logger, m *metrics.Metrics) *Handlers {
+	auditLogger := services.Audit()
+	txManager := services.TxManager()
+	outbox := services.Outbox()
+	verificationService := services.Verification()
+	passwordValidator := services.PasswordValidator()
+
 	// Infrastructure layer
 	userRepo := postgres.NewPostgreUserRepo(db.Conn())
+	refreshTokenRepo := postgres.NewRefreshTokenRepo(db.Conn())
+	resetTokenRepo := postgres.NewPasswordResetTokenRepo(db.Conn())
+	verificationTokenRepo := postgres.NewEmailVerificationTokenRepo(db.Conn())
 	uuidGenerator := uuid.NewGenerator()
 	logAdapter := infralogger.NewAdapter(log)
+	passwordHasher := password.NewHasher(cfg.Password)
+	tokenService := services.Tokens()
+	opaqueTokens := token.NewOpaqueGenerator()
+	totpRepo := postgres.NewTOTPCredentialRepo(db.Conn())
+	recoveryCodeRepo := postgres.NewMFARecoveryCodeRepo(db.Conn())
+	mfaChallengeRepo := postgres.NewMFAChallengeRepo(db.Conn())
+	totp := otp.NewTOTP(cfg.MFA.Issuer, cfg.MFA.TOTPSkew)
+	passkeyRepo := postgres.NewWebAuthnCredentialRepo(db.Conn())
+	relyingParty := webauthn.NewRelyingParty(cfg.WebAuthn.RPID, cfg.WebAuthn.RPName, cfg.WebAuthn.Origins)
 
 	// Application layer
-	registerUC := usecase.NewRegisterUsecase(userRepo, auditLogger, logAdapter, uuidGenerator)
+	sessionService := service.NewSessionService(tokenService, refreshTokenRepo, opaqueTokens, uuidGenerator, cfg.JWT.RefreshTokenTTL)
+	passkeyService := service.NewPasskeyService(
+		passkeyRepo,
+		postgres.NewWebAuthnSessionRepo(db.Conn()),
+		relyingParty,
+		uuidGenerator,
+		cfg.WebAuthn.SessionTTL,
+	)
+	mfaService := service.NewMFAService(
+		totpRepo,
+		passkeyRepo,
+		recoveryCodeRepo,
+		mfaChallengeRepo,
+		totp,
+		services.Secrets(),
+		otp.NewRecoveryCodeGenerator(),
+		opaqueTokens,
+		uuidGenerator,
+		cfg.MFA.ChallengeTTL,
+		cfg.MFA.RecoveryCodeCount,
+	)
+	loginService := service.NewLoginService(sessionService, mfaService, auditLogger, m, logAdapter)
+	registerUC := usecase.NewRegisterUsecase(userRepo, txManager, outbox, logAdapter, uuidGenerator, passwordHasher, passwordValidator)
+	externalAccounts := service.NewExternalAccountService(
+		postgres.NewIdentityRepo(db.Conn()),
+		userRepo,
+		txManager,
+		outbox,
+		auditLogger,
+		logAdapter,
+		uuidGenerator,
+	)
+	loginUC := usecase.NewLoginUsecase(
+		userRepo,
+		auditLogger,
+		logAdapter,
+		passwordHasher,
+		newDirectory(cfg.LDAP),
+		externalAccounts,
+		loginService,
+		m,
+		cache.NewLoginFailures(cfg.Lockout.AttemptWindow),
+		cfg.Verification.RequireVerifiedEmail,
+		lockoutPolicy(cfg.Lockout),
+	)
+	refreshUC := usecase.NewRefreshUsecase(userRepo, refreshTokenRepo, auditLogger, logAdapter, opaqueTokens, sessionService)
+	forgotPasswordUC := usecase.NewForgotPasswordUsecase(userRepo, outbox, logAdapter, uuidGenerator, m)
+	resetPasswordUC := usecase.NewResetPasswordUsecase(userRepo, resetTokenRepo, refreshTokenRepo, services.Revocations(), txManager, outbox, logAdapter, opaqueTokens, passwordHasher, passwordValidator)
+	verifyEmailUC := usecase.NewVerifyEmailUsecase(userRepo, verificationTokenRepo, txManager, outbox, logAdapter, opaqueTokens)
+	resendVerificationUC := usecase.NewResendVerificationUsecase(userRepo, verificationTokenRepo, logAdapter, verificationService, cfg.Verification.ResendInterval)
+
+	setupTOTPUC := usecase.NewSetupTOTPUsecase(userRepo, totpRepo, totp, services.Secrets(), qrcode.NewEncoder(), uuidGenerator, logAdapter)
+	confirmTOTPUC := usecase.NewConfirmTOTPUsecase(totpRepo, totp, services.Secrets(), mfaService, txManager, outbox, logAdapter)
+	disableTOTPUC := usecase.NewDisableTOTPUsecase(userRepo, totpRepo, recoveryCodeRepo, passwordHasher, mfaService, mfaService, txManager, outbox, logAdapter)
+	verifyMFAChallengeUC := usecase.NewVerifyMFAChallengeUsecase(
+		mfaChallengeRepo,
+		userRepo,
+		mfaService,
+		passkeyService,
+		sessionService,
+		txManager,
+		auditLogger,
+		m,
+		logAdapter,
+		opaqueTokens,
+		cfg.MFA.ChallengeMaxAttempts,
+	)
+
+	beginPasskeyRegistrationUC := usecase.NewBeginPasskeyRegistrationUsecase(userRepo, passkeyService, logAdapter)
+	finishPasskeyRegistrationUC := usecase.NewFinishPasskeyRegistrationUsecase(passkeyService, mfaService, mfaService, txManager, outbox, logAdapter)
+	listPasskeysUC := usecase.NewListPasskeysUsecase(passkeyRepo, logAdapter)
+	deletePasskeyUC := usecase.NewDeletePasskeyUsecase(userRepo, passkeyRepo, recoveryCodeRepo, passwordHasher, mfaService, txManager, outbox, logAdapter)
+	beginPasskeyLoginUC := usecase.NewBeginPasskeyLoginUsecase(userRepo, passkeyService, logAdapter)
+	finishPasskeyLoginUC := usecase.NewFinishPasskeyLoginUsecase(userRepo, passkeyService, loginService, auditLogger, m, logAdapter, cfg.Verification.RequireVerifiedEmail)
+	beginMFAPasskeyUC := usecase.NewBeginMFAPasskeyUsecase(mfaChallengeRepo, passkeyService, logAdapter, opaqueTokens, cfg.MFA.ChallengeMaxAttempts)
+
+	passwordlessRepo := postgres.NewPasswordlessChallengeRepo(db.Conn())
+	linkSigner := token.NewHMACSigner([]byte(cfg.Passwordless.LinkSecret))
+	startPasswordlessUC := usecase.NewStartPasswordlessUsecase(
+		userRepo,
+		outbox,
+		auditLogger,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		cfg.Passwordless.LinkTTL,
+		cfg.Passwordless.CodeTTL,
+	)
+	verifyPasswordlessUC := usecase.NewVerifyPasswordlessUsecase(
+		passwordlessRepo,
+		userRepo,
+		loginService,
+		txManager,
+		outbox,
+		auditLogger,
+		m,
+		logAdapter,
+		opaqueTokens,
+		linkSigner,
+		cfg.Passwordless.MaxAttempts,
+	)
+
+	identityProviders := newIdentityProviders(cfg.Federation)
+	federatedLoginRepo := postgres.NewFederatedLoginRepo(db.Conn())
+	listIdentityProvidersUC := usecase.NewListIdentityProvidersUsecase(identityProviders)
+	startFederatedLoginUC := usecase.NewStartFederatedLoginUsecase(
+		identityProviders,
+		federatedLoginRepo,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		cfg.Federation.LoginTTL,
+	)
+	completeFederatedLoginUC := usecase.NewCompleteFederatedLoginUsecase(
+		identityProviders,
+		federatedLoginRepo,
+		externalAccounts,
+		loginService,
+		m,
+		logAdapter,
+		opaqueTokens,
+	)
+
+	samlIdPs := newSAMLIdentityProviders(cfg.SAML, cfg.OIDC.Issuer)
+	samlRequestRepo := postgres.NewSAMLRequestRepo(db.Conn())
+	samlLoginRepo := postgres.NewSAMLLoginRepo(db.Conn())
+	getSAMLMetadataUC := usecase.NewGetSAMLMetadataUsecase(samlIdPs)
+	startSAMLLoginUC := usecase.NewStartSAMLLoginUsecase(samlIdPs, samlRequestRepo, logAdapter, opaqueTokens, cfg.SAML.RequestTTL)
+	consumeSAMLResponseUC := usecase.NewConsumeSAMLResponseUsecase(
+		samlIdPs,
+		samlRequestRepo,
+		postgres.NewSAMLAssertionRepo(db.Conn()),
+		samlLoginRepo,
+		externalAccounts,
+		m,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		cfg.SAML.LoginTTL,
+	)
+	redeemSAMLLoginUC := usecase.NewRedeemSAMLLoginUsecase(
+		samlLoginRepo,
+		userRepo,
+		loginService,
+		m,
+		logAdapter,
+		opaqueTokens,
+	)
+
+	// Clients are looked up on every token, introspection and revocation
+	// request, so they are cached briefly.
+	oauthClientRepo := cache.NewOAuthClientRepo(postgres.NewOAuthClientRepo(db.Conn()), cfg.OAuth.ClientCacheTTL)
+	authorizationCodeRepo := postgres.NewAuthorizationCodeRepo(db.Conn())
+	deviceAuthorizationRepo := postgres.NewDeviceAuthorizationRepo(db.Conn())
+	userConsentRepo := postgres.NewUserConsentRepo(db.Conn())
+	consentChallengeRepo := postgres.NewConsentChallengeRepo(db.Conn())
+	oauthSessionRepo := postgres.NewOAuthSessionRepo(db.Conn())
+	clientAssertions := token.NewClientAssertionVerifier()
+	clientAuthenticator := service.NewClientAuthenticator(
+		oauthClientRepo,
+		postgres.NewClientAssertionRepo(db.Conn()),
+		opaqueTokens,
+		clientAssertions,
+		logAdapter,
+		cfg.OIDC.Issuer,
+	)
+	validateAuthorizationUC := usecase.NewValidateAuthorizationRequestUsecase(oauthClientRepo, logAdapter, cfg.OAuth.Scopes)
+	authorizeUC := usecase.NewAuthorizeUsecase(
+		oauthClientRepo,
+		authorizationCodeRepo,
+		userRepo,
+		userConsentRepo,
+		consentChallengeRepo,
+		auditLogger,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		cfg.OAuth.Scopes,
+		cfg.OAuth.CodeTTL,
+		cfg.OAuth.ConsentTTL,
+	)
+	oauthTokenUC := usecase.NewOAuthTokenUsecase(
+		clientAuthenticator,
+		authorizationCodeRepo,
+		deviceAuthorizationRepo,
+		userRepo,
+		refreshTokenRepo,
+		oauthSessionRepo,
+		sessionService,
+		refreshUC,
+		auditLogger,
+		logAdapter,
+		opaqueTokens,
+		services.IDTokens(),
+		services.Tokens(),
+		services.Revocations(),
+		cfg.OAuth.DeviceGrant,
+	)
+	introspectTokenUC := usecase.NewIntrospectTokenUsecase(
+		clientAuthenticator,
+		refreshTokenRepo,
+		tokenService,
+		services.Revocations(),
+		opaqueTokens,
+		logAdapter,
+	)
+	revokeTokenUC := usecase.NewRevokeTokenUsecase(
+		clientAuthenticator,
+		refreshTokenRepo,
+		tokenService,
+		services.Revocations(),
+		opaqueTokens,
+		auditLogger,
+		logAdapter,
+	)
+	startDeviceAuthorizationUC := usecase.NewStartDeviceAuthorizationUsecase(
+		clientAuthenticator,
+		deviceAuthorizationRepo,
+		logAdapter,
+		opaqueTokens,
+		otp.NewUserCodeGenerator(),
+		uuidGenerator,
+		cfg.OAuth.DeviceVerificationURL,
+		cfg.OAuth.DeviceCodeTTL,
+		cfg.OAuth.DevicePollInterval,
+	)
+	lookupDeviceAuthorizationUC := usecase.NewLookupDeviceAuthorizationUsecase(deviceAuthorizationRepo, oauthClientRepo, logAdapter, opaqueTokens)
+	decideDeviceAuthorizationUC := usecase.NewDecideDeviceAuthorizationUsecase(deviceAuthorizationRepo, oauthClientRepo, userRepo, auditLogger, logAdapter, opaqueTokens)
+	lookupConsentUC := usecase.NewLookupConsentUsecase(oauthClientRepo, userConsentRepo, consentChallengeRepo, logAdapter, opaqueTokens)
+	decideConsentUC := usecase.NewDecideConsentUsecase(
+		oauthClientRepo,
+		authorizationCodeRepo,
+		userRepo,
+		userConsentRepo,
+		consentChallengeRepo,
+		auditLogger,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		cfg.OAuth.Scopes,
+		cfg.OAuth.CodeTTL,
+	)
+	listConsentsUC := usecase.NewListConsentsUsecase(userConsentRepo, oauthClientRepo, logAdapter)
+	revokeConsentUC := usecase.NewRevokeConsentUsecase(userConsentRepo, refreshTokenRepo, txManager, auditLogger, logAdapter)
+	endSessionUC := usecase.NewEndSessionUsecase(
+		oauthClientRepo,
+		oauthSessionRepo,
+		refreshTokenRepo,
+		services.IDTokenHints(),
+		txManager,
+		outbox,
+		auditLogger,
+		logAdapter,
+	)
+	userInfoUC := usecase.NewUserInfoUsecase(userRepo, logAdapter)
 
 	// Presentation layer
-	authHandler := handler.NewAuthHandler(registerUC, logAdapter)
+	authHandler := handler.NewAuthHandler(
+		registerUC,
+		loginUC,
+		refreshUC,
+		forgotPasswordUC,
+		resetPasswordUC,
+		verifyEmailUC,
+		resendVerificationUC,
+		logAdapter,
+	)
+
+	mfaHandler := handler.NewMFAHandler(setupTOTPUC, confirmTOTPUC, disableTOTPUC, verifyMFAChallengeUC)
+
+	passkeyHandler := handler.NewPasskeyHandler(
+		beginPasskeyRegistrationUC,
+		finishPasskeyRegistrationUC,
+		listPasskeysUC,
+		deletePasskeyUC,
+		beginPasskeyLoginUC,
+		finishPasskeyLoginUC,
+		beginMFAPasskeyUC,
+	)
+
+	passwordlessHandler := handler.NewPasswordlessHandler(
+		startPasswordlessUC,
+		verifyPasswordlessUC,
+		strings.HasPrefix(cfg.Mail.AppBaseURL, "https://"),
+	)
+
+	federationHandler := handler.NewFederationHandler(
+		listIdentityProvidersUC,
+		startFederatedLoginUC,
+		completeFederatedLoginUC,
+		strings.HasPrefix(cfg.Mail.AppBaseURL, "https://"),
+	)
+
+	samlHandler := handler.NewSAMLHandler(
+		getSAMLMetadataUC,
+		startSAMLLoginUC,
+		consumeSAMLResponseUC,
+		redeemSAMLLoginUC,
+		cfg.SAML.RedirectURL,
+		strings.HasPrefix(cfg.Mail.AppBaseURL, "https://"),
+	)
+
+	oauthHandler := handler.NewOAuthHandler(
+		validateAuthorizationUC,
+		authorizeUC,
+		oauthTokenUC,
+		introspectTokenUC,
+		revokeTokenUC,
+		startDeviceAuthorizationUC,
+		loginUC,
+		verifyMFAChallengeUC,
+		cfg.OAuth.LoginURL,
+		cfg.OIDC.Issuer+"/oauth/consent",
+	)
+
+	deviceHandler := handler.NewDeviceHandler(lookupDeviceAuthorizationUC, decideDeviceAuthorizationUC, loginUC, verifyMFAChallengeUC)
+
+	consentHandler := handler.NewConsentHandler(lookupConsentUC, decideConsentUC, listConsentsUC, revokeConsentUC)
+
+	logoutHandler := handler.NewLogoutHandler(endSessionUC, cfg.OIDC.Issuer)
+
+	oidcHandler := handler.NewOIDCHandler(userInfoUC, services.KeySet(), cfg.OIDC.Issuer, cfg.OAuth.Scopes, cfg.OAuth.RegistrationToken != "", cfg.OAuth.DeviceGrant)
+
+	createOAuthClientUC := usecase.NewCreateOAuthClientUsecase(
+		oauthClientRepo,
+		auditLogger,
+		logAdapter,
+		opaqueTokens,
+		uuidGenerator,
+		clientAssertions,
+		cfg.OAuth.Scopes,
+		cfg.OAuth.MaxAccessTokenTTL,
+	)
+	registrationHandler := handler.NewRegistrationHandler(
+		createOAuthClientUC,
+		usecase.NewReadClientRegistrationUsecase(oauthClientRepo, opaqueTokens, logAdapter),
+		usecase.NewUpdateClientRegistrationUsecase(
+			oauthClientRepo,
+			auditLogger,
+			logAdapter,
+			opaqueTokens,
+			clientAssertions,
+			cfg.OAuth.Scopes,
+			cfg.OAuth.MaxAccessTokenTTL,
+		),
+		usecase.NewDeleteClientRegistrationUsecase(oauthClientRepo, auditLogger, logAdapter, opaqueTokens),
+		cfg.OIDC.Issuer,
+	)
+
+	// The mailbox exposes reset and verification links, so it is only
+	// served in development.
+	var debugHandler *handler.DebugHandler
+	if cfg.Server.Environment == "development" && services.Mailbox() != nil {
+		debugHandler = handler.NewDebugHandler(services.Mailbox())
+	}
+
+	// Admin endpoints have no other authentication, so they are only
+	// mounted when an API key is configured.
+	var adminHandler *handler.AdminHandler
+	if cfg.Admin.APIKey != "" {
+		unlockAccountUC := usecase.NewUnlockAccountUsecase(userRepo, auditLogger, logAdapter)
+		listOAuthClientsUC := usecase.NewListOAuthClientsUsecase(oauthClientRepo, logAdapter)
+		adminHandler = handler.NewAdminHandler(unlockAccountUC, createOAuthClientUC, listOAuthClientsUC)
+	}
 
 	return &Handlers{
-		Auth: authHandler,
+		Auth:         authHandler,
+		Debug:        debugHandler,
+		Admin:        adminHandler,
+		MFA:          mfaHandler,
+		Passkey:      passkeyHandler,
+		Passwordless: passwordlessHandler,
+		Federation:   federationHandler,
+		SAML:         samlHandler,
+		OAuth:        oauthHandler,
+		Device:       deviceHandler,
+		Consent:      consentHandler,
+		Logout:       logoutHandler,
+		OIDC:         oidcHandler,
+		Registration: registrationHandler,
+	}
+}
+
+// newIdentityProviders sets up the configured upstream identity providers.
+// They are contacted lazily, so one that is down does not keep the
+// service from starting.
+func newIdentityProviders(cfg *config.FederationConfig) *federation.Registry {
+	client := &http.Client{Timeout: cfg.HTTPTimeout}
+
+	providers := make([]*federation.OIDCProvider, 0, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		providers = append(providers, federation.NewOIDCProvider(federation.ProviderConfig{
+			ID:           provider.ID,
+			Name:         provider.Name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			Scopes:       provider.Scopes,
+			LinkByEmail:  provider.LinkByEmail,
+			RedirectURL:  cfg.RedirectURL,
+			CacheTTL:     cfg.CacheTTL,
+		}, client))
+	}
+	return federation.NewRegistry(providers...)
+}
+
+// newSAMLIdentityProviders sets up the configured SAML identity providers.
+// Each gets its own service provider entity ID and assertion consumer
+// service under the issuer, named after the identity provider.
+func newSAMLIdentityProviders(cfg *config.SAMLConfig, issuer string) *saml.Registry {
+	idps := make([]*saml.IdentityProvider, 0, len(cfg.IdPs))
+	for _, idp := range cfg.IdPs {
+		base := issuer + "/saml/" + idp.ID
+		idps = append(idps, saml.NewIdentityProvider(saml.IdentityProviderConfig{
+			ID:                 idp.ID,
+			Name:               idp.Name,
+			EntityID:           idp.EntityID,
+			SSOURL:             idp.SSOURL,
+			Certificates:       idp.Certificates,
+			UsernameAttributes: idp.UsernameAttributes,
+			EmailAttributes:    idp.EmailAttributes,
+			AllowIdPInitiated:  idp.AllowIdPInitiated,
+			LinkByEmail:        idp.LinkByEmail,
+			SPEntityID:         base + "/metadata",
+			ACSURL:             base + "/acs",
+			ClockSkew:          cfg.ClockSkew,
+		}))
+	}
+	return saml.NewRegistry(idps...)
+}
+
+// newDirectory sets up the LDAP directory login verifies passwords
+// against, or returns nil when none is configured.
+func newDirectory(cfg *config.LDAPConfig) port.CredentialVerifier {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	var rootCAs *x509.CertPool
+	if len(cfg.CACertificates) > 0 {
+		rootCAs = x509.NewCertPool()
+		for _, cert := range cfg.CACertificates {
+			rootCAs.AddCert(cert)
+		}
+	}
+	return ldap.NewDirectory(ldap.Config{
+		URL:               cfg.URL,
+		StartTLS:          cfg.StartTLS,
+		RootCAs:           rootCAs,
+		BindDN:            cfg.BindDN,
+		BindPassword:      cfg.BindPassword,
+		UserDNTemplate:    cfg.UserDNTemplate,
+		BaseDN:            cfg.BaseDN,
+		UserFilter:        cfg.UserFilter,
+		IDAttribute:       cfg.IDAttribute,
+		UsernameAttribute: cfg.UsernameAttribute,
+		EmailAttribute:    cfg.EmailAttribute,
+		GroupAttribute:    cfg.GroupAttribute,
+		GroupRoles:        cfg.GroupRoles,
+		LinkByEmail:       cfg.LinkByEmail,
+		Timeout:           cfg.Timeout,
+	})
+}
+
+func lockoutPolicy(cfg *config.LockoutConfig) entity.LockoutPolicy {
+	return entity.LockoutPolicy{
+		MaxAttempts:   cfg.MaxAttempts,
+		AttemptWindow: cfg.AttemptWindow,
+		BaseDuration:  cfg.BaseDuration,
+		MaxDuration:   cfg.MaxDuration,
+		DelayAfter:    cfg.DelayAfter,
+		DelayBase:     cfg.DelayBase,
+		DelayMax:      cfg.DelayMax,
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
 	"github.com/thanhnamdk2710/auth-service/internal/config"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/metrics"
@@ -14,10 +15,14 @@ import (
 )
 
 type ServerOptions struct {
-	Config   *config.ServerConfig
-	Logger   *logger.Logger
-	Metrics  *metrics.Metrics
-	Handlers *Handlers
+	Config      *config.ServerConfig
+	Logger      *logger.Logger
+	Metrics     *metrics.Metrics
+	Handlers    *Handlers
+	Admin       *config.AdminConfig
+	OAuth       *config.OAuthConfig
+	Tokens      port.TokenService
+	Revocations port.AccessTokenRevocations
 }
 
 type Server struct {
@@ -27,9 +32,27 @@ type Server struct {
 
 func NewServer(opts ServerOptions) *Server {
 	routerDeps := router.RouterDeps{
-		Logger:      opts.Logger,
-		Metrics:     opts.Metrics,
-		AuthHandler: opts.Handlers.Auth,
+		Logger:              opts.Logger,
+		Metrics:             opts.Metrics,
+		AuthHandler:         opts.Handlers.Auth,
+		DebugHandler:        opts.Handlers.Debug,
+		AdminHandler:        opts.Handlers.Admin,
+		AdminAPIKey:         opts.Admin.APIKey,
+		MFAHandler:          opts.Handlers.MFA,
+		PasskeyHandler:      opts.Handlers.Passkey,
+		PasswordlessHandler: opts.Handlers.Passwordless,
+		FederationHandler:   opts.Handlers.Federation,
+		SAMLHandler:         opts.Handlers.SAML,
+		OAuthHandler:        opts.Handlers.OAuth,
+		DeviceHandler:       opts.Handlers.Device,
+		ConsentHandler:      opts.Handlers.Consent,
+		LogoutHandler:       opts.Handlers.Logout,
+		OIDCHandler:         opts.Handlers.OIDC,
+		RegistrationHandler: opts.Handlers.Registration,
+		RegistrationToken:   opts.OAuth.RegistrationToken,
+		DeviceAuthorization: opts.OAuth.DeviceGrant,
+		TokenService:        opts.Tokens,
+		Revocations:         opts.Revocations,
 	}
 
 	return &Server{
@@ -1,17 +1,51 @@
 package bootstrap
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
 	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/subscriber"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
 	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/audit"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/encryption"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+	infralogger "github.com/thanhnamdk2710/auth-service/internal/infrastructure/logger"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/mailer"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/outbox"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/password"
 	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/persistence/postgres"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/revocation"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/uuid"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webhook"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
 )
 
 type Services struct {
-	audit port.AuditLogger
+	audit        port.AuditLogger
+	mailer       port.Mailer
+	mailbox      port.Mailbox
+	txManager    port.TxManager
+	verification port.EmailVerificationSender
+	resets       port.PasswordResetSender
+	passwordless port.PasswordlessSender
+	outbox       port.Outbox
+	dispatcher   *outbox.Dispatcher
+	passwords    port.PasswordValidator
+	tokens       port.TokenService
+	secrets      port.SecretBox
+	idTokens     *token.IDTokenSigner
+	keys         *keystore.Store
+	revocations  *revocation.List
 }
 
-func NewServices(db *Database, log *logger.Logger) *Services {
+func NewServices(cfg *config.Config, db *Database, log *logger.Logger) (*Services, error) {
 	auditRepo := postgres.NewAuditRepo(db.Conn())
 	auditLogger := audit.NewAsyncLogger(
 		auditRepo,
@@ -19,19 +53,274 @@ func NewServices(db *Database, log *logger.Logger) *Services {
 		audit.DefaultConfig(),
 	)
 
-	return &Services{
-		audit: auditLogger,
+	s := &Services{
+		audit:     auditLogger,
+		txManager: postgres.NewTxManager(db.Conn()),
+	}
+
+	if err := s.initSigningKeys(cfg.SigningKeys, db, log); err != nil {
+		return nil, err
 	}
+	s.tokens = token.NewJWTService(cfg.JWT, s.keys)
+	s.idTokens = token.NewIDTokenSigner(s.keys, cfg.OIDC.Issuer, cfg.OIDC.IDTokenTTL)
+
+	s.revocations = revocation.NewList(postgres.NewRevokedAccessTokenRepo(db.Conn()), log, cfg.Revocation)
+	if err := s.revocations.Reload(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load access token revocations: %w", err)
+	}
+
+	if err := s.initMailer(cfg.Mail, log); err != nil {
+		return nil, err
+	}
+
+	s.verification = service.NewEmailVerificationService(
+		postgres.NewEmailVerificationTokenRepo(db.Conn()),
+		token.NewOpaqueGenerator(),
+		uuid.NewGenerator(),
+		s.mailer,
+		cfg.Verification.TokenTTL,
+		cfg.Mail.AppBaseURL+"/verify-email",
+	)
+
+	s.resets = service.NewPasswordResetService(
+		postgres.NewPasswordResetTokenRepo(db.Conn()),
+		token.NewOpaqueGenerator(),
+		uuid.NewGenerator(),
+		s.mailer,
+		cfg.Password.ResetTokenTTL,
+		cfg.Mail.AppBaseURL+"/reset-password",
+	)
+
+	s.passwordless = service.NewPasswordlessService(
+		postgres.NewPasswordlessChallengeRepo(db.Conn()),
+		token.NewOpaqueGenerator(),
+		otp.NewEmailCodeGenerator(),
+		token.NewHMACSigner([]byte(cfg.Passwordless.LinkSecret)),
+		uuid.NewGenerator(),
+		s.mailer,
+		cfg.Passwordless.LinkTTL,
+		cfg.Passwordless.CodeTTL,
+		cfg.Passwordless.ResendInterval,
+		cfg.Mail.AppBaseURL+"/passwordless/verify",
+	)
+
+	if err := s.initOutbox(cfg.Outbox, db, auditRepo, log); err != nil {
+		return nil, err
+	}
+
+	if err := s.initPasswordPolicy(cfg.Password, log); err != nil {
+		return nil, err
+	}
+
+	secrets, err := encryption.NewAESGCM(cfg.MFA.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize secret encryption: %w", err)
+	}
+	s.secrets = secrets
+
+	return s, nil
+}
+
+// initSigningKeys loads the token signing keys, creating the first one on
+// a fresh database.
+func (s *Services) initSigningKeys(cfg *config.SigningKeyConfig, db *Database, log *logger.Logger) error {
+	keys, err := NewKeyStore(cfg, db, log)
+	if err != nil {
+		return err
+	}
+	if err := keys.Init(context.Background()); err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	s.keys = keys
+	return nil
+}
+
+// NewKeyStore builds the signing key store; the keys command uses it
+// without the rest of the services.
+func NewKeyStore(cfg *config.SigningKeyConfig, db *Database, log *logger.Logger) (*keystore.Store, error) {
+	kek, err := encryption.NewAESGCM(cfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize signing key encryption: %w", err)
+	}
+
+	return keystore.NewStore(postgres.NewSigningKeyRepo(db.Conn()), postgres.NewTxManager(db.Conn()), kek, log, cfg), nil
+}
+
+func (s *Services) initOutbox(cfg *config.OutboxConfig, db *Database, auditRepo repository.AuditRepository, log *logger.Logger) error {
+	router := outbox.NewRouter()
+
+	err := router.Subscribe(subscriber.NewAuditSubscriber(auditRepo), subscriber.AuditEvents...)
+	if err != nil {
+		return err
+	}
+
+	err = router.Subscribe(
+		subscriber.NewVerificationMailSubscriber(postgres.NewPostgreUserRepo(db.Conn()), s.verification),
+		subscriber.VerificationMailEvents...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = router.Subscribe(
+		subscriber.NewPasswordResetMailSubscriber(postgres.NewPostgreUserRepo(db.Conn()), s.resets),
+		subscriber.PasswordResetMailEvents...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = router.Subscribe(
+		subscriber.NewPasswordlessMailSubscriber(postgres.NewPostgreUserRepo(db.Conn()), s.passwordless),
+		subscriber.PasswordlessMailEvents...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = router.Subscribe(webhook.NewBackchannelLogoutNotifier(s.idTokens, cfg.WebhookTimeout), event.OIDCBackchannelLogout)
+	if err != nil {
+		return err
+	}
+
+	for _, url := range cfg.WebhookURLs {
+		notifier := webhook.NewNotifier(url, cfg.WebhookSecret, cfg.WebhookTimeout)
+		err := router.Subscribe(notifier, event.UserEvents...)
+		if err != nil {
+			return err
+		}
+	}
+
+	outboxRepo := postgres.NewOutboxRepo(db.Conn())
+	s.outbox = outbox.NewPublisher(outboxRepo, router, uuid.NewGenerator())
+	s.dispatcher = outbox.NewDispatcher(outboxRepo, router, log, cfg)
+	return nil
+}
+
+func (s *Services) initMailer(cfg *config.MailConfig, log *logger.Logger) error {
+	switch cfg.Driver {
+	case config.MailDriverSMTP:
+		s.mailer = mailer.NewSMTPMailer(cfg)
+	case config.MailDriverFile:
+		fileMailer, err := mailer.NewFileMailer(cfg.FileDir, cfg.From)
+		if err != nil {
+			return err
+		}
+		s.mailer = fileMailer
+	case config.MailDriverMemory:
+		memoryMailer := mailer.NewMemoryMailer(cfg.MemoryCapacity)
+		s.mailer = memoryMailer
+		s.mailbox = memoryMailer
+	default:
+		s.mailer = mailer.NewLogMailer(log)
+	}
+	return nil
+}
+
+func (s *Services) initPasswordPolicy(cfg *config.PasswordConfig, log *logger.Logger) error {
+	policy := vo.PasswordPolicy{
+		MinLength:          cfg.MinLength,
+		MaxLength:          cfg.MaxLength,
+		RequireUppercase:   cfg.RequireUppercase,
+		RequireLowercase:   cfg.RequireLowercase,
+		RequireDigit:       cfg.RequireDigit,
+		RequireSymbol:      cfg.RequireSymbol,
+		MinStrengthScore:   cfg.MinStrengthScore,
+		RejectPersonalInfo: cfg.RejectPersonalInfo,
+	}
+
+	var breaches port.BreachedPasswordChecker
+	if cfg.BreachFile != "" {
+		checker, err := password.NewBreachChecker(cfg.BreachFile, cfg.BreachMinCount)
+		if err != nil {
+			return fmt.Errorf("failed to open breached password corpus: %w", err)
+		}
+		breaches = checker
+	}
+
+	s.passwords = service.NewPasswordPolicyService(policy, breaches, infralogger.NewAdapter(log))
+	return nil
 }
 
 func (s *Services) Audit() port.AuditLogger {
 	return s.audit
 }
 
+func (s *Services) Mailer() port.Mailer {
+	return s.mailer
+}
+
+// Mailbox returns the captured mail when the in-memory driver is used,
+// otherwise nil.
+func (s *Services) Mailbox() port.Mailbox {
+	return s.mailbox
+}
+
+func (s *Services) TxManager() port.TxManager {
+	return s.txManager
+}
+
+// Outbox records side effects in the caller's transaction; the dispatcher
+// started with the services delivers them.
+func (s *Services) Outbox() port.Outbox {
+	return s.outbox
+}
+
+func (s *Services) Verification() port.EmailVerificationSender {
+	return s.verification
+}
+
+func (s *Services) PasswordValidator() port.PasswordValidator {
+	return s.passwords
+}
+
+// Tokens issues and verifies access tokens; the HTTP layer uses it to
+// authenticate requests.
+func (s *Services) Tokens() port.TokenService {
+	return s.tokens
+}
+
+// Secrets encrypts secrets stored at rest, such as TOTP keys.
+func (s *Services) Secrets() port.SecretBox {
+	return s.secrets
+}
+
+// IDTokens signs OpenID Connect ID tokens.
+func (s *Services) IDTokens() port.IDTokenSigner {
+	return s.idTokens
+}
+
+// IDTokenHints verifies the ID tokens clients send back when logging a
+// user out.
+func (s *Services) IDTokenHints() port.IDTokenVerifier {
+	return s.idTokens
+}
+
+// KeySet publishes the public keys tokens are verified with.
+func (s *Services) KeySet() port.KeySet {
+	return s.keys
+}
+
+// SigningKeys is the signing key store, exposed for its metrics.
+func (s *Services) SigningKeys() *keystore.Store {
+	return s.keys
+}
+
+// Revocations lists the revoked access tokens that have not expired.
+func (s *Services) Revocations() port.AccessTokenRevocations {
+	return s.revocations
+}
+
 func (s *Services) Start() {
 	s.audit.Start()
+	s.dispatcher.Start()
+	s.keys.Start()
+	s.revocations.Start()
 }
 
 func (s *Services) Stop() {
+	s.revocations.Stop()
+	s.keys.Stop()
+	s.dispatcher.Stop()
 	s.audit.Stop()
 }
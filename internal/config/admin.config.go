@@ -0,0 +1,22 @@
+package config
+
+import "fmt"
+
+type AdminConfig struct {
+	// APIKey guards the /admin endpoints. They are not mounted when empty.
+	APIKey string
+}
+
+const MinAdminAPIKeyLength = 32
+
+func NewAdminConfig() (*AdminConfig, error) {
+	cfg := &AdminConfig{
+		APIKey: getEnv("ADMIN_API_KEY", ""),
+	}
+
+	if cfg.APIKey != "" && len(cfg.APIKey) < MinAdminAPIKeyLength {
+		return nil, fmt.Errorf("ADMIN_API_KEY must be at least %d characters", MinAdminAPIKeyLength)
+	}
+
+	return cfg, nil
+}
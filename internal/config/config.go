@@ -1,15 +1,34 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strconv"
 )
 
 type Config struct {
-	Server *ServerConfig
-	DB     *DBConfig
-	Redis  *RedisConfig
+	Server       *ServerConfig
+	DB           *DBConfig
+	Redis        *RedisConfig
+	Password     *PasswordConfig
+	JWT          *JWTConfig
+	Mail         *MailConfig
+	Verification *VerificationConfig
+	Outbox       *OutboxConfig
+	Lockout      *LockoutConfig
+	Admin        *AdminConfig
+	MFA          *MFAConfig
+	WebAuthn     *WebAuthnConfig
+	Passwordless *PasswordlessConfig
+	OAuth        *OAuthConfig
+	OIDC         *OIDCConfig
+	SigningKeys  *SigningKeyConfig
+	Revocation   *RevocationConfig
+	Federation   *FederationConfig
+	SAML         *SAMLConfig
+	LDAP         *LDAPConfig
 }
 
 func NewConfig() (*Config, error) {
@@ -28,10 +47,138 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to load redis config: %w", err)
 	}
 
+	passwordConfig, err := NewPasswordConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load password config: %w", err)
+	}
+
+	jwtConfig, err := NewJWTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwt config: %w", err)
+	}
+
+	mailConfig, err := NewMailConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mail config: %w", err)
+	}
+
+	verificationConfig, err := NewVerificationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load verification config: %w", err)
+	}
+
+	outboxConfig, err := NewOutboxConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outbox config: %w", err)
+	}
+
+	lockoutConfig, err := NewLockoutConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lockout config: %w", err)
+	}
+
+	adminConfig, err := NewAdminConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin config: %w", err)
+	}
+
+	mfaConfig, err := NewMFAConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mfa config: %w", err)
+	}
+
+	webAuthnConfig, err := NewWebAuthnConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webauthn config: %w", err)
+	}
+
+	passwordlessConfig, err := NewPasswordlessConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load passwordless config: %w", err)
+	}
+
+	oauthConfig, err := NewOAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load oauth config: %w", err)
+	}
+
+	oidcConfig, err := NewOIDCConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load oidc config: %w", err)
+	}
+	if jwtConfig.Issuer == "" {
+		jwtConfig.Issuer = oidcConfig.Issuer
+	} else if jwtConfig.Issuer != oidcConfig.Issuer {
+		return nil, fmt.Errorf("JWT_ISSUER %q must match OIDC_ISSUER %q or be left unset", jwtConfig.Issuer, oidcConfig.Issuer)
+	}
+
+	signingKeyConfig, err := NewSigningKeyConfig(serverConfig.Environment, max(jwtConfig.AccessTokenTTL, oidcConfig.IDTokenTTL, oauthConfig.MaxAccessTokenTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing key config: %w", err)
+	}
+
+	revocationConfig, err := NewRevocationConfig(max(jwtConfig.AccessTokenTTL, oauthConfig.MaxAccessTokenTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load revocation config: %w", err)
+	}
+
+	federationConfig, err := NewFederationConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load federation config: %w", err)
+	}
+
+	samlConfig, err := NewSAMLConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saml config: %w", err)
+	}
+	// SAML identity providers and federation providers share the
+	// namespace identities are recorded under.
+	for _, idp := range samlConfig.IdPs {
+		for _, provider := range federationConfig.Providers {
+			if idp.ID == provider.ID {
+				return nil, fmt.Errorf("SAML_IDPS: %q is also listed in FEDERATION_PROVIDERS", idp.ID)
+			}
+		}
+	}
+
+	ldapConfig, err := NewLDAPConfig(serverConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ldap config: %w", err)
+	}
+	if ldapConfig.Enabled {
+		for _, provider := range federationConfig.Providers {
+			if provider.ID == ldapProviderID {
+				return nil, fmt.Errorf("FEDERATION_PROVIDERS: %q is reserved for the LDAP directory", ldapProviderID)
+			}
+		}
+		for _, idp := range samlConfig.IdPs {
+			if idp.ID == ldapProviderID {
+				return nil, fmt.Errorf("SAML_IDPS: %q is reserved for the LDAP directory", ldapProviderID)
+			}
+		}
+	}
+
 	return &Config{
-		DB:     dbConfig,
-		Redis:  redisConfig,
-		Server: serverConfig,
+		DB:           dbConfig,
+		Redis:        redisConfig,
+		Server:       serverConfig,
+		Password:     passwordConfig,
+		JWT:          jwtConfig,
+		Mail:         mailConfig,
+		Verification: verificationConfig,
+		Outbox:       outboxConfig,
+		Lockout:      lockoutConfig,
+		Admin:        adminConfig,
+		MFA:          mfaConfig,
+		WebAuthn:     webAuthnConfig,
+		Passwordless: passwordlessConfig,
+		OAuth:        oauthConfig,
+		OIDC:         oidcConfig,
+		SigningKeys:  signingKeyConfig,
+		Revocation:   revocationConfig,
+		Federation:   federationConfig,
+		SAML:         samlConfig,
+		LDAP:         ldapConfig,
 	}, nil
 }
 
@@ -55,3 +202,35 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return value
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// encryptionKey reads a base64-encoded 32-byte key from name. Outside
+// production an unset key is derived from developmentSeed.
+func encryptionKey(name, developmentSeed, environment string) ([]byte, error) {
+	encoded := getEnv(name, "")
+	if encoded == "" {
+		if environment == "production" {
+			return nil, fmt.Errorf("%s is required in production", name)
+		}
+		sum := sha256.Sum256([]byte(developmentSeed))
+		return sum[:], nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 32 bytes, base64 encoded", name)
+	}
+	return key, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type FederationConfig struct {
+	// RedirectURL is the frontend page identity providers send users back
+	// to. It posts the state and code it receives to the callback
+	// endpoint.
+	RedirectURL string
+	LoginTTL    time.Duration
+	// CacheTTL is how long provider discovery documents and keys are
+	// cached.
+	CacheTTL    time.Duration
+	HTTPTimeout time.Duration
+	Providers   []FederationProviderConfig
+}
+
+type FederationProviderConfig struct {
+	ID           string
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	LinkByEmail  bool
+}
+
+const (
+	DefaultFederationLoginTTLSec    = 600
+	DefaultFederationCacheTTLSec    = 3600
+	DefaultFederationHTTPTimeoutSec = 10
+	defaultFederationScopes         = "openid,email,profile"
+	maxFederationProviderIDLength   = 50
+)
+
+var federationProviderIDRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// NewFederationConfig reads the providers named in FEDERATION_PROVIDERS.
+// Each is configured by variables prefixed with its ID, upper-cased with
+// dashes turned into underscores: provider "gitlab-corp" reads
+// FEDERATION_GITLAB_CORP_ISSUER and so on.
+func NewFederationConfig(environment string) (*FederationConfig, error) {
+	appBaseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8000"), "/")
+
+	cfg := &FederationConfig{
+		RedirectURL: getEnv("FEDERATION_REDIRECT_URL", appBaseURL+"/login/callback"),
+		LoginTTL:    time.Duration(getEnvAsInt("FEDERATION_LOGIN_TTL_SEC", DefaultFederationLoginTTLSec)) * time.Second,
+		CacheTTL:    time.Duration(getEnvAsInt("FEDERATION_CACHE_TTL_SEC", DefaultFederationCacheTTLSec)) * time.Second,
+		HTTPTimeout: time.Duration(getEnvAsInt("FEDERATION_HTTP_TIMEOUT_SEC", DefaultFederationHTTPTimeoutSec)) * time.Second,
+	}
+
+	if u, err := url.Parse(cfg.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+		return nil, fmt.Errorf("FEDERATION_REDIRECT_URL must be an absolute URL without a fragment: %q", cfg.RedirectURL)
+	}
+	if cfg.LoginTTL <= 0 || cfg.CacheTTL <= 0 || cfg.HTTPTimeout <= 0 {
+		return nil, errors.New("FEDERATION_LOGIN_TTL_SEC, FEDERATION_CACHE_TTL_SEC and FEDERATION_HTTP_TIMEOUT_SEC must be positive")
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range splitList(getEnv("FEDERATION_PROVIDERS", "")) {
+		if !federationProviderIDRegex.MatchString(id) || len(id) > maxFederationProviderIDLength {
+			return nil, fmt.Errorf("FEDERATION_PROVIDERS: %q must be lowercase letters, digits and dashes, at most %d characters", id, maxFederationProviderIDLength)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("FEDERATION_PROVIDERS: %q is listed twice", id)
+		}
+		seen[id] = true
+
+		provider, err := newFederationProviderConfig(id, environment)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Providers = append(cfg.Providers, *provider)
+	}
+
+	return cfg, nil
+}
+
+func newFederationProviderConfig(id, environment string) (*FederationProviderConfig, error) {
+	prefix := "FEDERATION_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+
+	provider := &FederationProviderConfig{
+		ID:           id,
+		Name:         getEnv(prefix+"NAME", id),
+		Issuer:       getEnv(prefix+"ISSUER", ""),
+		ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+		ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+		Scopes:       splitList(getEnv(prefix+"SCOPES", defaultFederationScopes)),
+		LinkByEmail:  getEnvAsBool(prefix+"LINK_BY_EMAIL", false),
+	}
+
+	u, err := url.Parse(provider.Issuer)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("%sISSUER must be an absolute URL without a query or fragment: %q", prefix, provider.Issuer)
+	}
+	if environment == "production" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%sISSUER must use https in production", prefix)
+	}
+	if provider.ClientID == "" {
+		return nil, fmt.Errorf("%sCLIENT_ID is required", prefix)
+	}
+	hasOpenID := false
+	for _, scope := range provider.Scopes {
+		hasOpenID = hasOpenID || scope == "openid"
+	}
+	if !hasOpenID {
+		return nil, fmt.Errorf("%sSCOPES must include openid", prefix)
+	}
+
+	return provider, nil
+}
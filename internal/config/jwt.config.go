@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type JWTConfig struct {
+	// Issuer is the iss claim of access tokens. It is OIDC_ISSUER, as ID
+	// tokens and discovery name the same issuer; JWT_ISSUER may only
+	// repeat it.
+	Issuer          string
+	Audience        string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+const (
+	DefaultJWTAudience          = "auth-service"
+	DefaultAccessTokenTTLMin    = 15
+	DefaultRefreshTokenTTLHours = 720
+)
+
+func NewJWTConfig() (*JWTConfig, error) {
+	cfg := &JWTConfig{
+		Issuer:          strings.TrimRight(getEnv("JWT_ISSUER", ""), "/"),
+		Audience:        getEnv("JWT_AUDIENCE", DefaultJWTAudience),
+		AccessTokenTTL:  time.Duration(getEnvAsInt("JWT_ACCESS_TOKEN_TTL_MIN", DefaultAccessTokenTTLMin)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvAsInt("JWT_REFRESH_TOKEN_TTL_HOURS", DefaultRefreshTokenTTLHours)) * time.Hour,
+	}
+
+	if cfg.AccessTokenTTL <= 0 {
+		return nil, errors.New("JWT_ACCESS_TOKEN_TTL_MIN must be positive")
+	}
+
+	return cfg, nil
+}
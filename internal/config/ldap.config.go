@@ -0,0 +1,127 @@
+package config
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type LDAPConfig struct {
+	// Enabled is set when LDAP_URL is, making login check passwords of
+	// accounts without a local one against the directory.
+	Enabled        bool
+	URL            string
+	StartTLS       bool
+	CACertificates []*x509.Certificate
+
+	// BindDN is the service account that searches for users before
+	// binding as them. Without one, users bind with the DN made from
+	// UserDNTemplate.
+	BindDN         string
+	BindPassword   string
+	UserDNTemplate string
+
+	BaseDN            string
+	UserFilter        string
+	IDAttribute       string
+	UsernameAttribute string
+	EmailAttribute    string
+	GroupAttribute    string
+	// GroupRoles maps group DNs to the roles their members get.
+	GroupRoles map[string][]string
+
+	LinkByEmail bool
+	Timeout     time.Duration
+}
+
+const (
+	DefaultLDAPTimeoutSec   = 10
+	ldapUsernamePlaceholder = "{username}"
+	// ldapProviderID is the provider directory identities are recorded
+	// under.
+	ldapProviderID    = "ldap"
+	maxRoleNameLength = 64
+)
+
+var roleNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// NewLDAPConfig reads the directory login also checks passwords against.
+// LDAP_GROUP_ROLES maps groups to roles as semicolon-separated
+// "role:group DN" pairs, since DNs contain commas.
+func NewLDAPConfig(environment string) (*LDAPConfig, error) {
+	cfg := &LDAPConfig{
+		URL:               getEnv("LDAP_URL", ""),
+		StartTLS:          getEnvAsBool("LDAP_START_TLS", false),
+		BindDN:            getEnv("LDAP_BIND_DN", ""),
+		BindPassword:      getEnv("LDAP_BIND_PASSWORD", ""),
+		UserDNTemplate:    getEnv("LDAP_USER_DN_TEMPLATE", ""),
+		BaseDN:            getEnv("LDAP_BASE_DN", ""),
+		UserFilter:        getEnv("LDAP_USER_FILTER", "(uid={username})"),
+		IDAttribute:       getEnv("LDAP_ID_ATTRIBUTE", "entryUUID"),
+		UsernameAttribute: getEnv("LDAP_USERNAME_ATTRIBUTE", "uid"),
+		EmailAttribute:    getEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+		GroupAttribute:    getEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		GroupRoles:        make(map[string][]string),
+		LinkByEmail:       getEnvAsBool("LDAP_LINK_BY_EMAIL", false),
+		Timeout:           time.Duration(getEnvAsInt("LDAP_TIMEOUT_SEC", DefaultLDAPTimeoutSec)) * time.Second,
+	}
+	if cfg.URL == "" {
+		return cfg, nil
+	}
+	cfg.Enabled = true
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+		return nil, fmt.Errorf("LDAP_URL must be an ldap:// or ldaps:// URL of a server: %q", cfg.URL)
+	}
+	if cfg.StartTLS && u.Scheme == "ldaps" {
+		return nil, errors.New("LDAP_START_TLS applies to ldap:// URLs; ldaps:// connections use TLS from the start")
+	}
+	if environment == "production" && u.Scheme != "ldaps" && !cfg.StartTLS {
+		return nil, errors.New("LDAP_URL must use ldaps:// or LDAP_START_TLS in production")
+	}
+
+	if ca := getEnv("LDAP_CA_CERTIFICATE", ""); ca != "" {
+		cfg.CACertificates, err = parseCertificates(ca)
+		if err != nil {
+			return nil, fmt.Errorf("LDAP_CA_CERTIFICATE: %w", err)
+		}
+	}
+
+	if cfg.BaseDN == "" {
+		return nil, errors.New("LDAP_BASE_DN is required")
+	}
+	if !strings.Contains(cfg.UserFilter, ldapUsernamePlaceholder) {
+		return nil, fmt.Errorf("LDAP_USER_FILTER must contain %s", ldapUsernamePlaceholder)
+	}
+	switch {
+	case cfg.BindDN != "" && cfg.BindPassword == "":
+		return nil, errors.New("LDAP_BIND_PASSWORD is required with LDAP_BIND_DN")
+	case cfg.BindDN == "" && !strings.Contains(cfg.UserDNTemplate, ldapUsernamePlaceholder):
+		return nil, fmt.Errorf("LDAP_BIND_DN or an LDAP_USER_DN_TEMPLATE containing %s is required", ldapUsernamePlaceholder)
+	}
+	if cfg.IDAttribute == "" || cfg.UsernameAttribute == "" || cfg.EmailAttribute == "" || cfg.GroupAttribute == "" {
+		return nil, errors.New("LDAP_ID_ATTRIBUTE, LDAP_USERNAME_ATTRIBUTE, LDAP_EMAIL_ATTRIBUTE and LDAP_GROUP_ATTRIBUTE must not be empty")
+	}
+	if cfg.Timeout <= 0 {
+		return nil, errors.New("LDAP_TIMEOUT_SEC must be positive")
+	}
+
+	for _, pair := range strings.Split(getEnv("LDAP_GROUP_ROLES", ""), ";") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		role, group, ok := strings.Cut(pair, ":")
+		role, group = strings.TrimSpace(role), strings.TrimSpace(group)
+		if !ok || group == "" || !roleNameRegex.MatchString(role) || len(role) > maxRoleNameLength {
+			return nil, fmt.Errorf("LDAP_GROUP_ROLES: %q must be a role of lowercase letters, digits, dots, dashes and underscores, a colon and a group DN", pair)
+		}
+		cfg.GroupRoles[group] = append(cfg.GroupRoles[group], role)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type LockoutConfig struct {
+	MaxAttempts   int
+	AttemptWindow time.Duration
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+
+	DelayAfter int
+	DelayBase  time.Duration
+	DelayMax   time.Duration
+}
+
+const (
+	DefaultLockoutMaxAttempts = 5
+	DefaultLockoutWindowMin   = 15
+	DefaultLockoutBaseMin     = 5
+	DefaultLockoutMaxMin      = 1440
+	DefaultLoginDelayAfter    = 3
+	DefaultLoginDelayBaseMs   = 500
+	DefaultLoginDelayMaxMs    = 4000
+)
+
+func NewLockoutConfig() (*LockoutConfig, error) {
+	cfg := &LockoutConfig{
+		MaxAttempts:   getEnvAsInt("LOGIN_LOCKOUT_MAX_ATTEMPTS", DefaultLockoutMaxAttempts),
+		AttemptWindow: time.Duration(getEnvAsInt("LOGIN_LOCKOUT_WINDOW_MIN", DefaultLockoutWindowMin)) * time.Minute,
+		BaseDuration:  time.Duration(getEnvAsInt("LOGIN_LOCKOUT_BASE_MIN", DefaultLockoutBaseMin)) * time.Minute,
+		MaxDuration:   time.Duration(getEnvAsInt("LOGIN_LOCKOUT_MAX_MIN", DefaultLockoutMaxMin)) * time.Minute,
+		DelayAfter:    getEnvAsInt("LOGIN_DELAY_AFTER", DefaultLoginDelayAfter),
+		DelayBase:     time.Duration(getEnvAsInt("LOGIN_DELAY_BASE_MS", DefaultLoginDelayBaseMs)) * time.Millisecond,
+		DelayMax:      time.Duration(getEnvAsInt("LOGIN_DELAY_MAX_MS", DefaultLoginDelayMaxMs)) * time.Millisecond,
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_MAX_ATTEMPTS must not be negative")
+	}
+	if cfg.DelayAfter > 0 && cfg.AttemptWindow <= 0 {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_WINDOW_MIN must be positive when LOGIN_DELAY_AFTER is set")
+	}
+	if cfg.MaxAttempts > 0 {
+		if cfg.AttemptWindow <= 0 || cfg.BaseDuration <= 0 {
+			return nil, fmt.Errorf("LOGIN_LOCKOUT_WINDOW_MIN and LOGIN_LOCKOUT_BASE_MIN must be positive")
+		}
+		if cfg.MaxDuration < cfg.BaseDuration {
+			return nil, fmt.Errorf("LOGIN_LOCKOUT_MAX_MIN must be at least LOGIN_LOCKOUT_BASE_MIN")
+		}
+	}
+	if cfg.DelayMax < cfg.DelayBase {
+		return nil, fmt.Errorf("LOGIN_DELAY_MAX_MS must be at least LOGIN_DELAY_BASE_MS")
+	}
+
+	return cfg, nil
+}
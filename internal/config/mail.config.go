@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MailDriverLog    = "log"
+	MailDriverSMTP   = "smtp"
+	MailDriverFile   = "file"
+	MailDriverMemory = "memory"
+)
+
+type MailConfig struct {
+	Driver     string
+	From       string
+	AppBaseURL string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPStartTLS bool
+	SMTPTimeout  time.Duration
+
+	FileDir        string
+	MemoryCapacity int
+}
+
+const (
+	DefaultSMTPPort           = 587
+	DefaultSMTPTimeoutSec     = 10
+	DefaultMailFileDir        = "tmp/mail"
+	DefaultMailMemoryCapacity = 100
+)
+
+func NewMailConfig() (*MailConfig, error) {
+	cfg := &MailConfig{
+		Driver:         getEnv("MAIL_DRIVER", MailDriverLog),
+		From:           getEnv("MAIL_FROM", "no-reply@localhost"),
+		AppBaseURL:     strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8000"), "/"),
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       getEnvAsInt("SMTP_PORT", DefaultSMTPPort),
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		SMTPStartTLS:   getEnvAsBool("SMTP_STARTTLS", true),
+		SMTPTimeout:    time.Duration(getEnvAsInt("SMTP_TIMEOUT_SEC", DefaultSMTPTimeoutSec)) * time.Second,
+		FileDir:        getEnv("MAIL_FILE_DIR", DefaultMailFileDir),
+		MemoryCapacity: getEnvAsInt("MAIL_MEMORY_CAPACITY", DefaultMailMemoryCapacity),
+	}
+
+	switch cfg.Driver {
+	case MailDriverLog, MailDriverFile, MailDriverMemory:
+	case MailDriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER=%s", MailDriverSMTP)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Driver)
+	}
+
+	return cfg, nil
+}
+
+func (c *MailConfig) SMTPAddr() string {
+	return fmt.Sprintf("%s:%d", c.SMTPHost, c.SMTPPort)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type MFAConfig struct {
+	Issuer string
+	// EncryptionKey is the 32-byte AES key TOTP secrets are stored under.
+	EncryptionKey        []byte
+	TOTPSkew             int
+	ChallengeTTL         time.Duration
+	ChallengeMaxAttempts int
+	RecoveryCodeCount    int
+}
+
+const (
+	DefaultMFAIssuer               = "auth-service"
+	DefaultMFATOTPSkew             = 1
+	DefaultMFAChallengeTTLMin      = 5
+	DefaultMFAChallengeMaxAttempts = 5
+	DefaultMFARecoveryCodeCount    = 10
+	developmentMFAEncryptionSeed   = "development-mfa-key-do-not-use-in-production"
+)
+
+func NewMFAConfig(environment string) (*MFAConfig, error) {
+	key, err := encryptionKey("MFA_ENCRYPTION_KEY", developmentMFAEncryptionSeed, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &MFAConfig{
+		Issuer:               getEnv("MFA_ISSUER", DefaultMFAIssuer),
+		EncryptionKey:        key,
+		TOTPSkew:             getEnvAsInt("MFA_TOTP_SKEW", DefaultMFATOTPSkew),
+		ChallengeTTL:         time.Duration(getEnvAsInt("MFA_CHALLENGE_TTL_MIN", DefaultMFAChallengeTTLMin)) * time.Minute,
+		ChallengeMaxAttempts: getEnvAsInt("MFA_CHALLENGE_MAX_ATTEMPTS", DefaultMFAChallengeMaxAttempts),
+		RecoveryCodeCount:    getEnvAsInt("MFA_RECOVERY_CODE_COUNT", DefaultMFARecoveryCodeCount),
+	}
+
+	if cfg.TOTPSkew < 0 || cfg.TOTPSkew > 2 {
+		return nil, errors.New("MFA_TOTP_SKEW must be between 0 and 2")
+	}
+	if cfg.ChallengeMaxAttempts <= 0 {
+		return nil, errors.New("MFA_CHALLENGE_MAX_ATTEMPTS must be positive")
+	}
+	if cfg.RecoveryCodeCount <= 0 {
+		return nil, errors.New("MFA_RECOVERY_CODE_COUNT must be positive")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type OAuthConfig struct {
+	// LoginURL is the page that signs the user in for an authorization
+	// request. It receives the request's parameters in its query string.
+	LoginURL string
+	CodeTTL  time.Duration
+	// ConsentTTL is how long the consent page waits for the user to
+	// approve or deny a third-party client.
+	ConsentTTL time.Duration
+	// Scopes lists every scope a client can be registered for.
+	Scopes []string
+	// MaxAccessTokenTTL caps the access token lifetime a client can be
+	// registered with.
+	MaxAccessTokenTTL time.Duration
+	// ClientCacheTTL is how long each instance reuses a client it looked
+	// up; zero disables the cache.
+	ClientCacheTTL time.Duration
+	// DeviceGrant turns on the device authorization grant.
+	DeviceGrant bool
+	// DeviceVerificationURL is the page where a user signs in and enters
+	// a device's user code.
+	DeviceVerificationURL string
+	DeviceCodeTTL         time.Duration
+	// DevicePollInterval is how long a device waits between polls of the
+	// token endpoint.
+	DevicePollInterval time.Duration
+	// RegistrationToken is the initial access token that dynamic client
+	// registration requires. Registration is not mounted when empty.
+	RegistrationToken string
+}
+
+const (
+	DefaultOAuthCodeTTLSec            = 60
+	DefaultOAuthConsentTTLSec         = 600
+	DefaultOAuthScopes                = "openid,profile,email"
+	DefaultOAuthMaxAccessTokenTTLMin  = 60
+	DefaultOAuthClientCacheTTLSec     = 30
+	DefaultOAuthDeviceCodeTTLSec      = 600
+	DefaultOAuthDevicePollIntervalSec = 5
+	MinOAuthRegistrationTokenLength   = 32
+)
+
+func NewOAuthConfig() (*OAuthConfig, error) {
+	appBaseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8000"), "/")
+	issuer := strings.TrimRight(getEnv("OIDC_ISSUER", "http://localhost:8000"), "/")
+	cfg := &OAuthConfig{
+		LoginURL: getEnv("OAUTH_LOGIN_URL", appBaseURL+"/oauth/login"),
+		CodeTTL:  time.Duration(getEnvAsInt("OAUTH_CODE_TTL_SEC", DefaultOAuthCodeTTLSec)) * time.Second,
+		Scopes:   splitList(getEnv("OAUTH_SCOPES", DefaultOAuthScopes)),
+
+		ConsentTTL: time.Duration(getEnvAsInt("OAUTH_CONSENT_TTL_SEC", DefaultOAuthConsentTTLSec)) * time.Second,
+
+		MaxAccessTokenTTL: time.Duration(getEnvAsInt("OAUTH_MAX_ACCESS_TOKEN_TTL_MIN", DefaultOAuthMaxAccessTokenTTLMin)) * time.Minute,
+		ClientCacheTTL:    time.Duration(getEnvAsInt("OAUTH_CLIENT_CACHE_TTL_SEC", DefaultOAuthClientCacheTTLSec)) * time.Second,
+
+		DeviceGrant:           getEnvAsBool("OAUTH_DEVICE_GRANT_ENABLED", true),
+		DeviceVerificationURL: getEnv("OAUTH_DEVICE_VERIFICATION_URL", issuer+"/oauth/device"),
+		DeviceCodeTTL:         time.Duration(getEnvAsInt("OAUTH_DEVICE_CODE_TTL_SEC", DefaultOAuthDeviceCodeTTLSec)) * time.Second,
+		DevicePollInterval:    time.Duration(getEnvAsInt("OAUTH_DEVICE_POLL_INTERVAL_SEC", DefaultOAuthDevicePollIntervalSec)) * time.Second,
+
+		RegistrationToken: getEnv("OAUTH_REGISTRATION_TOKEN", ""),
+	}
+
+	u, err := url.Parse(cfg.LoginURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" {
+		return nil, fmt.Errorf("OAUTH_LOGIN_URL must be an absolute URL without a query: %q", cfg.LoginURL)
+	}
+	if cfg.CodeTTL <= 0 {
+		return nil, errors.New("OAUTH_CODE_TTL_SEC must be positive")
+	}
+	if cfg.ConsentTTL <= 0 {
+		return nil, errors.New("OAUTH_CONSENT_TTL_SEC must be positive")
+	}
+	if cfg.MaxAccessTokenTTL <= 0 {
+		return nil, errors.New("OAUTH_MAX_ACCESS_TOKEN_TTL_MIN must be positive")
+	}
+	if cfg.ClientCacheTTL < 0 {
+		return nil, errors.New("OAUTH_CLIENT_CACHE_TTL_SEC must not be negative")
+	}
+	u, err = url.Parse(cfg.DeviceVerificationURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" {
+		return nil, fmt.Errorf("OAUTH_DEVICE_VERIFICATION_URL must be an absolute URL without a query: %q", cfg.DeviceVerificationURL)
+	}
+	if cfg.DeviceCodeTTL <= 0 {
+		return nil, errors.New("OAUTH_DEVICE_CODE_TTL_SEC must be positive")
+	}
+	if cfg.DevicePollInterval <= 0 {
+		return nil, errors.New("OAUTH_DEVICE_POLL_INTERVAL_SEC must be positive")
+	}
+	if cfg.RegistrationToken != "" && len(cfg.RegistrationToken) < MinOAuthRegistrationTokenLength {
+		return nil, fmt.Errorf("OAUTH_REGISTRATION_TOKEN must be at least %d characters", MinOAuthRegistrationTokenLength)
+	}
+	for _, scope := range cfg.Scopes {
+		if strings.ContainsAny(scope, " \"\\") {
+			return nil, fmt.Errorf("OAUTH_SCOPES contains an invalid scope: %q", scope)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type OIDCConfig struct {
+	// Issuer is the public base URL of this service. Discovery, the JWKS
+	// and the OAuth endpoints are advertised under it.
+	Issuer     string
+	IDTokenTTL time.Duration
+}
+
+const DefaultOIDCIDTokenTTLMin = 60
+
+func NewOIDCConfig(environment string) (*OIDCConfig, error) {
+	cfg := &OIDCConfig{
+		Issuer:     strings.TrimRight(getEnv("OIDC_ISSUER", "http://localhost:8000"), "/"),
+		IDTokenTTL: time.Duration(getEnvAsInt("OIDC_ID_TOKEN_TTL_MIN", DefaultOIDCIDTokenTTLMin)) * time.Minute,
+	}
+
+	u, err := url.Parse(cfg.Issuer)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("OIDC_ISSUER must be an absolute URL without a query or fragment: %q", cfg.Issuer)
+	}
+
+	if environment == "production" && u.Scheme != "https" {
+		return nil, errors.New("OIDC_ISSUER must use https in production")
+	}
+
+	if cfg.IDTokenTTL <= 0 {
+		return nil, errors.New("OIDC_ID_TOKEN_TTL_MIN must be positive")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OutboxConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	BackoffBase    time.Duration
+	BackoffMax     time.Duration
+	Lease          time.Duration
+	HandlerTimeout time.Duration
+
+	WebhookURLs    []string
+	WebhookSecret  string
+	WebhookTimeout time.Duration
+}
+
+const (
+	DefaultOutboxPollIntervalMs    = 1000
+	DefaultOutboxBatchSize         = 50
+	DefaultOutboxMaxAttempts       = 10
+	DefaultOutboxBackoffBaseSec    = 5
+	DefaultOutboxBackoffMaxSec     = 900
+	DefaultOutboxLeaseSec          = 60
+	DefaultOutboxHandlerTimeoutSec = 15
+	DefaultWebhookTimeoutSec       = 10
+)
+
+func NewOutboxConfig() (*OutboxConfig, error) {
+	cfg := &OutboxConfig{
+		PollInterval:   time.Duration(getEnvAsInt("OUTBOX_POLL_INTERVAL_MS", DefaultOutboxPollIntervalMs)) * time.Millisecond,
+		BatchSize:      getEnvAsInt("OUTBOX_BATCH_SIZE", DefaultOutboxBatchSize),
+		MaxAttempts:    getEnvAsInt("OUTBOX_MAX_ATTEMPTS", DefaultOutboxMaxAttempts),
+		BackoffBase:    time.Duration(getEnvAsInt("OUTBOX_BACKOFF_BASE_SEC", DefaultOutboxBackoffBaseSec)) * time.Second,
+		BackoffMax:     time.Duration(getEnvAsInt("OUTBOX_BACKOFF_MAX_SEC", DefaultOutboxBackoffMaxSec)) * time.Second,
+		Lease:          time.Duration(getEnvAsInt("OUTBOX_LEASE_SEC", DefaultOutboxLeaseSec)) * time.Second,
+		HandlerTimeout: time.Duration(getEnvAsInt("OUTBOX_HANDLER_TIMEOUT_SEC", DefaultOutboxHandlerTimeoutSec)) * time.Second,
+		WebhookURLs:    splitList(getEnv("WEBHOOK_URLS", "")),
+		WebhookSecret:  getEnv("WEBHOOK_SECRET", ""),
+		WebhookTimeout: time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SEC", DefaultWebhookTimeoutSec)) * time.Second,
+	}
+
+	if cfg.BatchSize <= 0 {
+		return nil, fmt.Errorf("OUTBOX_BATCH_SIZE must be positive")
+	}
+	if cfg.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+	// A lease shorter than a handler run would let a second worker claim
+	// the event while the first is still delivering it.
+	if cfg.Lease <= cfg.HandlerTimeout {
+		return nil, fmt.Errorf("OUTBOX_LEASE_SEC must be greater than OUTBOX_HANDLER_TIMEOUT_SEC")
+	}
+	if len(cfg.WebhookURLs) > 0 && cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("WEBHOOK_SECRET is required when WEBHOOK_URLS is set")
+	}
+
+	return cfg, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+type PasswordConfig struct {
+	Algorithm         string
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	Argon2SaltLength  uint32
+	Argon2KeyLength   uint32
+	BcryptCost        int
+	ResetTokenTTL     time.Duration
+
+	MinLength          int
+	MaxLength          int
+	RequireUppercase   bool
+	RequireLowercase   bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	MinStrengthScore   int
+	RejectPersonalInfo bool
+	BreachFile         string
+	BreachMinCount     int
+}
+
+const (
+	DefaultArgon2MemoryKB    = 64 * 1024
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+	DefaultArgon2SaltLength  = 16
+	DefaultArgon2KeyLength   = 32
+	DefaultBcryptCost        = 12
+	DefaultResetTokenTTLMin  = 30
+
+	DefaultPasswordMinLength        = 8
+	DefaultPasswordMaxLength        = 64
+	DefaultPasswordMinStrengthScore = 2
+	DefaultPasswordBreachMinCount   = 1
+
+	// bcrypt ignores input beyond 72 bytes.
+	bcryptMaxPasswordLength = 72
+)
+
+func NewPasswordConfig() (*PasswordConfig, error) {
+	cfg := &PasswordConfig{
+		Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", PasswordAlgorithmArgon2id),
+		Argon2Memory:      uint32(getEnvAsInt("ARGON2_MEMORY_KB", DefaultArgon2MemoryKB)),
+		Argon2Iterations:  uint32(getEnvAsInt("ARGON2_ITERATIONS", DefaultArgon2Iterations)),
+		Argon2Parallelism: uint8(getEnvAsInt("ARGON2_PARALLELISM", DefaultArgon2Parallelism)),
+		Argon2SaltLength:  uint32(getEnvAsInt("ARGON2_SALT_LENGTH", DefaultArgon2SaltLength)),
+		Argon2KeyLength:   uint32(getEnvAsInt("ARGON2_KEY_LENGTH", DefaultArgon2KeyLength)),
+		BcryptCost:        getEnvAsInt("BCRYPT_COST", DefaultBcryptCost),
+		ResetTokenTTL:     time.Duration(getEnvAsInt("PASSWORD_RESET_TOKEN_TTL_MIN", DefaultResetTokenTTLMin)) * time.Minute,
+
+		MinLength:          getEnvAsInt("PASSWORD_MIN_LENGTH", DefaultPasswordMinLength),
+		MaxLength:          getEnvAsInt("PASSWORD_MAX_LENGTH", DefaultPasswordMaxLength),
+		RequireUppercase:   getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false),
+		RequireLowercase:   getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", false),
+		RequireDigit:       getEnvAsBool("PASSWORD_REQUIRE_DIGIT", false),
+		RequireSymbol:      getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+		MinStrengthScore:   getEnvAsInt("PASSWORD_MIN_STRENGTH_SCORE", DefaultPasswordMinStrengthScore),
+		RejectPersonalInfo: getEnvAsBool("PASSWORD_REJECT_PERSONAL_INFO", true),
+		BreachFile:         getEnv("PASSWORD_BREACH_FILE", ""),
+		BreachMinCount:     getEnvAsInt("PASSWORD_BREACH_MIN_COUNT", DefaultPasswordBreachMinCount),
+	}
+
+	switch cfg.Algorithm {
+	case PasswordAlgorithmArgon2id, PasswordAlgorithmBcrypt:
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", cfg.Algorithm)
+	}
+
+	if cfg.MinLength < 1 || cfg.MaxLength < cfg.MinLength {
+		return nil, fmt.Errorf("PASSWORD_MAX_LENGTH must be at least PASSWORD_MIN_LENGTH, and both positive")
+	}
+	if cfg.Algorithm == PasswordAlgorithmBcrypt && cfg.MaxLength > bcryptMaxPasswordLength {
+		return nil, fmt.Errorf("PASSWORD_MAX_LENGTH must not exceed %d with bcrypt", bcryptMaxPasswordLength)
+	}
+	if cfg.MinStrengthScore < 0 || cfg.MinStrengthScore > 4 {
+		return nil, fmt.Errorf("PASSWORD_MIN_STRENGTH_SCORE must be between 0 and 4")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type PasswordlessConfig struct {
+	// LinkSecret signs magic links.
+	LinkSecret     string
+	LinkTTL        time.Duration
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendInterval time.Duration
+}
+
+const (
+	DefaultPasswordlessLinkTTLMin        = 15
+	DefaultPasswordlessCodeTTLMin        = 10
+	DefaultPasswordlessMaxAttempts       = 5
+	DefaultPasswordlessResendIntervalSec = 60
+	developmentPasswordlessLinkSecret    = "development-link-secret-do-not-use-in-production"
+	minPasswordlessLinkSecretLength      = 32
+)
+
+func NewPasswordlessConfig(environment string) (*PasswordlessConfig, error) {
+	secret := getEnv("PASSWORDLESS_LINK_SECRET", "")
+	if secret == "" {
+		if environment == "production" {
+			return nil, errors.New("PASSWORDLESS_LINK_SECRET is required in production")
+		}
+		secret = developmentPasswordlessLinkSecret
+	}
+
+	if len(secret) < minPasswordlessLinkSecretLength {
+		return nil, errors.New("PASSWORDLESS_LINK_SECRET must be at least 32 characters")
+	}
+
+	cfg := &PasswordlessConfig{
+		LinkSecret:     secret,
+		LinkTTL:        time.Duration(getEnvAsInt("PASSWORDLESS_LINK_TTL_MIN", DefaultPasswordlessLinkTTLMin)) * time.Minute,
+		CodeTTL:        time.Duration(getEnvAsInt("PASSWORDLESS_CODE_TTL_MIN", DefaultPasswordlessCodeTTLMin)) * time.Minute,
+		MaxAttempts:    getEnvAsInt("PASSWORDLESS_MAX_ATTEMPTS", DefaultPasswordlessMaxAttempts),
+		ResendInterval: time.Duration(getEnvAsInt("PASSWORDLESS_RESEND_INTERVAL_SEC", DefaultPasswordlessResendIntervalSec)) * time.Second,
+	}
+
+	if cfg.LinkTTL <= 0 || cfg.CodeTTL <= 0 {
+		return nil, errors.New("PASSWORDLESS_LINK_TTL_MIN and PASSWORDLESS_CODE_TTL_MIN must be positive")
+	}
+	if cfg.MaxAttempts <= 0 {
+		return nil, errors.New("PASSWORDLESS_MAX_ATTEMPTS must be positive")
+	}
+
+	return cfg, nil
+}
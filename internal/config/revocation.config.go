@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type RevocationConfig struct {
+	// RefreshInterval is how often each instance reloads the revoked
+	// access tokens, and so how long a token revoked on another instance
+	// may still be accepted.
+	RefreshInterval time.Duration
+	// CleanupInterval is how often revocations of expired tokens are
+	// deleted.
+	CleanupInterval time.Duration
+	// MaxAccessTokenTTL is the longest any access token lives, and so how
+	// long revoking all of a user's tokens needs to be remembered.
+	MaxAccessTokenTTL time.Duration
+}
+
+const (
+	DefaultRevocationRefreshIntervalSec = 5
+	DefaultRevocationCleanupIntervalSec = 300
+)
+
+func NewRevocationConfig(maxAccessTokenTTL time.Duration) (*RevocationConfig, error) {
+	cfg := &RevocationConfig{
+		RefreshInterval:   time.Duration(getEnvAsInt("TOKEN_REVOCATION_REFRESH_INTERVAL_SEC", DefaultRevocationRefreshIntervalSec)) * time.Second,
+		CleanupInterval:   time.Duration(getEnvAsInt("TOKEN_REVOCATION_CLEANUP_INTERVAL_SEC", DefaultRevocationCleanupIntervalSec)) * time.Second,
+		MaxAccessTokenTTL: maxAccessTokenTTL,
+	}
+
+	if cfg.RefreshInterval <= 0 {
+		return nil, errors.New("TOKEN_REVOCATION_REFRESH_INTERVAL_SEC must be positive")
+	}
+	if cfg.CleanupInterval <= 0 {
+		return nil, errors.New("TOKEN_REVOCATION_CLEANUP_INTERVAL_SEC must be positive")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type SAMLConfig struct {
+	// RedirectURL is the frontend page the assertion consumer service
+	// sends the browser on to, with a code to redeem or an error.
+	RedirectURL string
+	RequestTTL  time.Duration
+	LoginTTL    time.Duration
+	// ClockSkew is how far identity provider clocks may be off when
+	// assertion validity windows are checked.
+	ClockSkew time.Duration
+	IdPs      []SAMLIdPConfig
+}
+
+type SAMLIdPConfig struct {
+	ID                 string
+	Name               string
+	EntityID           string
+	SSOURL             string
+	Certificates       []*x509.Certificate
+	UsernameAttributes []string
+	EmailAttributes    []string
+	AllowIdPInitiated  bool
+	LinkByEmail        bool
+}
+
+const (
+	DefaultSAMLRequestTTLSec      = 600
+	DefaultSAMLLoginTTLSec        = 60
+	DefaultSAMLClockSkewSec       = 120
+	defaultSAMLUsernameAttributes = "username,uid,urn:oid:0.9.2342.19200300.100.1.1,http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name"
+	defaultSAMLEmailAttributes    = "email,mail,urn:oid:0.9.2342.19200300.100.1.3,http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"
+)
+
+// NewSAMLConfig reads the identity providers named in SAML_IDPS, each
+// configured by variables prefixed with its ID as for federation
+// providers: "okta-acme" reads SAML_OKTA_ACME_ENTITY_ID and so on.
+func NewSAMLConfig(environment string) (*SAMLConfig, error) {
+	appBaseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8000"), "/")
+
+	cfg := &SAMLConfig{
+		RedirectURL: getEnv("SAML_REDIRECT_URL", appBaseURL+"/login/saml"),
+		RequestTTL:  time.Duration(getEnvAsInt("SAML_REQUEST_TTL_SEC", DefaultSAMLRequestTTLSec)) * time.Second,
+		LoginTTL:    time.Duration(getEnvAsInt("SAML_LOGIN_TTL_SEC", DefaultSAMLLoginTTLSec)) * time.Second,
+		ClockSkew:   time.Duration(getEnvAsInt("SAML_CLOCK_SKEW_SEC", DefaultSAMLClockSkewSec)) * time.Second,
+	}
+
+	if u, err := url.Parse(cfg.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("SAML_REDIRECT_URL must be an absolute URL without a query or fragment: %q", cfg.RedirectURL)
+	}
+	if cfg.RequestTTL <= 0 || cfg.LoginTTL <= 0 {
+		return nil, errors.New("SAML_REQUEST_TTL_SEC and SAML_LOGIN_TTL_SEC must be positive")
+	}
+	if cfg.ClockSkew < 0 || cfg.ClockSkew > 10*time.Minute {
+		return nil, errors.New("SAML_CLOCK_SKEW_SEC must be between 0 and 600")
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range splitList(getEnv("SAML_IDPS", "")) {
+		if !federationProviderIDRegex.MatchString(id) || len(id) > maxFederationProviderIDLength {
+			return nil, fmt.Errorf("SAML_IDPS: %q must be lowercase letters, digits and dashes, at most %d characters", id, maxFederationProviderIDLength)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("SAML_IDPS: %q is listed twice", id)
+		}
+		seen[id] = true
+
+		idp, err := newSAMLIdPConfig(id, environment)
+		if err != nil {
+			return nil, err
+		}
+		cfg.IdPs = append(cfg.IdPs, *idp)
+	}
+
+	return cfg, nil
+}
+
+func newSAMLIdPConfig(id, environment string) (*SAMLIdPConfig, error) {
+	prefix := "SAML_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+
+	idp := &SAMLIdPConfig{
+		ID:                 id,
+		Name:               getEnv(prefix+"NAME", id),
+		EntityID:           getEnv(prefix+"ENTITY_ID", ""),
+		SSOURL:             getEnv(prefix+"SSO_URL", ""),
+		UsernameAttributes: splitList(getEnv(prefix+"USERNAME_ATTRIBUTES", defaultSAMLUsernameAttributes)),
+		EmailAttributes:    splitList(getEnv(prefix+"EMAIL_ATTRIBUTES", defaultSAMLEmailAttributes)),
+		AllowIdPInitiated:  getEnvAsBool(prefix+"ALLOW_IDP_INITIATED", false),
+		LinkByEmail:        getEnvAsBool(prefix+"LINK_BY_EMAIL", false),
+	}
+
+	if idp.EntityID == "" {
+		return nil, fmt.Errorf("%sENTITY_ID is required", prefix)
+	}
+	u, err := url.Parse(idp.SSOURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+		return nil, fmt.Errorf("%sSSO_URL must be an absolute URL without a fragment: %q", prefix, idp.SSOURL)
+	}
+	if environment == "production" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%sSSO_URL must use https in production", prefix)
+	}
+
+	idp.Certificates, err = parseCertificates(getEnv(prefix+"CERTIFICATE", ""))
+	if err != nil {
+		return nil, fmt.Errorf("%sCERTIFICATE: %w", prefix, err)
+	}
+
+	return idp, nil
+}
+
+// parseCertificates reads one or more certificates, as PEM blocks or as
+// the comma-separated base64 DER found in identity provider metadata.
+// Literal "\n" sequences are read as newlines, for env files that cannot
+// hold multi-line values.
+func parseCertificates(value string) ([]*x509.Certificate, error) {
+	value = strings.ReplaceAll(value, `\n`, "\n")
+
+	var ders [][]byte
+	if strings.Contains(value, "-----BEGIN") {
+		rest := []byte(value)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type == "CERTIFICATE" {
+				ders = append(ders, block.Bytes)
+			}
+		}
+	} else {
+		for _, encoded := range splitList(value) {
+			der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+			if err != nil {
+				return nil, errors.New("must be PEM or base64 encoded")
+			}
+			ders = append(ders, der)
+		}
+	}
+	if len(ders) == 0 {
+		return nil, errors.New("at least one certificate is required")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(ders))
+	for _, der := range ders {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type SigningKeyConfig struct {
+	// Algorithm is the JWS algorithm new keys are generated for.
+	Algorithm string
+	// EncryptionKey is the 32-byte key encryption key private signing keys
+	// are stored under.
+	EncryptionKey []byte
+	// RotationInterval is how long a key signs before it is replaced;
+	// zero leaves rotation to the keys command.
+	RotationInterval time.Duration
+	// RefreshInterval is how often each instance reloads the keys. A new
+	// key is published this long before it starts signing, so every
+	// instance can verify its tokens.
+	RefreshInterval time.Duration
+	// Overlap is how long a replaced key stays published: the lifetime of
+	// the longest-lived token it can have signed.
+	Overlap time.Duration
+}
+
+const (
+	DefaultSigningKeyAlgorithm          = "RS256"
+	DefaultSigningKeyRotationDays       = 30
+	DefaultSigningKeyRefreshIntervalSec = 60
+	developmentSigningKeyEncryptionSeed = "development-signing-key-kek-do-not-use-in-production"
+)
+
+func NewSigningKeyConfig(environment string, maxTokenTTL time.Duration) (*SigningKeyConfig, error) {
+	key, err := encryptionKey("SIGNING_KEY_ENCRYPTION_KEY", developmentSigningKeyEncryptionSeed, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &SigningKeyConfig{
+		Algorithm:        getEnv("SIGNING_KEY_ALGORITHM", DefaultSigningKeyAlgorithm),
+		EncryptionKey:    key,
+		RotationInterval: time.Duration(getEnvAsInt("SIGNING_KEY_ROTATION_DAYS", DefaultSigningKeyRotationDays)) * 24 * time.Hour,
+		RefreshInterval:  time.Duration(getEnvAsInt("SIGNING_KEY_REFRESH_INTERVAL_SEC", DefaultSigningKeyRefreshIntervalSec)) * time.Second,
+		Overlap:          maxTokenTTL,
+	}
+
+	switch cfg.Algorithm {
+	case "RS256", "ES256", "EdDSA":
+	default:
+		return nil, errors.New("SIGNING_KEY_ALGORITHM must be RS256, ES256 or EdDSA")
+	}
+	if cfg.RotationInterval < 0 {
+		return nil, errors.New("SIGNING_KEY_ROTATION_DAYS must not be negative")
+	}
+	if cfg.RefreshInterval <= 0 {
+		return nil, errors.New("SIGNING_KEY_REFRESH_INTERVAL_SEC must be positive")
+	}
+
+	return cfg, nil
+}
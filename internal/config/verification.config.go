@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+type VerificationConfig struct {
+	TokenTTL             time.Duration
+	ResendInterval       time.Duration
+	RequireVerifiedEmail bool
+}
+
+const (
+	DefaultVerificationTokenTTLHours     = 24
+	DefaultVerificationResendIntervalSec = 60
+)
+
+func NewVerificationConfig() (*VerificationConfig, error) {
+	return &VerificationConfig{
+		TokenTTL:             time.Duration(getEnvAsInt("EMAIL_VERIFICATION_TOKEN_TTL_HOURS", DefaultVerificationTokenTTLHours)) * time.Hour,
+		ResendInterval:       time.Duration(getEnvAsInt("EMAIL_VERIFICATION_RESEND_INTERVAL_SEC", DefaultVerificationResendIntervalSec)) * time.Second,
+		RequireVerifiedEmail: getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", false),
+	}, nil
+}
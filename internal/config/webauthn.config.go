@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type WebAuthnConfig struct {
+	// RPID is the domain passkeys are scoped to. It must equal, or be a
+	// registrable suffix of, the host of every origin.
+	RPID   string
+	RPName string
+	// Origins are the exact browser origins allowed to run ceremonies.
+	Origins    []string
+	SessionTTL time.Duration
+}
+
+const (
+	DefaultWebAuthnRPName        = "auth-service"
+	DefaultWebAuthnSessionTTLSec = 300
+)
+
+func NewWebAuthnConfig() (*WebAuthnConfig, error) {
+	origins := splitList(getEnv("WEBAUTHN_ORIGINS", ""))
+	if len(origins) == 0 {
+		origins = []string{strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8000"), "/")}
+	}
+
+	cfg := &WebAuthnConfig{
+		RPID:       getEnv("WEBAUTHN_RP_ID", ""),
+		RPName:     getEnv("WEBAUTHN_RP_NAME", DefaultWebAuthnRPName),
+		Origins:    origins,
+		SessionTTL: time.Duration(getEnvAsInt("WEBAUTHN_SESSION_TTL_SEC", DefaultWebAuthnSessionTTLSec)) * time.Second,
+	}
+
+	for _, origin := range cfg.Origins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return nil, fmt.Errorf("WEBAUTHN_ORIGINS contains an invalid origin: %q", origin)
+		}
+		if cfg.RPID == "" {
+			cfg.RPID = u.Hostname()
+		}
+		host := u.Hostname()
+		if host != cfg.RPID && !strings.HasSuffix(host, "."+cfg.RPID) {
+			return nil, fmt.Errorf("origin %q is not within WEBAUTHN_RP_ID %q", origin, cfg.RPID)
+		}
+	}
+
+	if cfg.SessionTTL <= 0 {
+		return nil, errors.New("WEBAUTHN_SESSION_TTL_SEC must be positive")
+	}
+
+	return cfg, nil
+}
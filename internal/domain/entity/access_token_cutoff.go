@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// AccessTokenCutoff revokes every access token issued to a user up to
+// IssuedBefore. It only needs to be kept until the last of those tokens
+// would have expired.
+type AccessTokenCutoff struct {
+	UserID       string
+	IssuedBefore time.Time
+	ExpiresAt    time.Time
+}
+
+func NewAccessTokenCutoff(userID string, issuedBefore time.Time, maxTokenTTL time.Duration) *AccessTokenCutoff {
+	return &AccessTokenCutoff{
+		UserID:       userID,
+		IssuedBefore: issuedBefore,
+		ExpiresAt:    issuedBefore.Add(maxTokenTTL),
+	}
+}
+
+// Revokes reports whether a token issued at issuedAt falls under the
+// cutoff. Token timestamps only have whole seconds, so a token from the
+// cutoff's second is revoked too.
+func (c *AccessTokenCutoff) Revokes(issuedAt time.Time) bool {
+	return !issuedAt.After(c.IssuedBefore.Truncate(time.Second))
+}
+
+func (c *AccessTokenCutoff) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
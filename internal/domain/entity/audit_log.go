@@ -16,6 +16,37 @@ const (
 	AuditActionPasswordChanged AuditAction = "PASSWORD_CHANGED"
 	AuditActionPasswordReset   AuditAction = "PASSWORD_RESET"
 	AuditActionEmailVerified   AuditAction = "EMAIL_VERIFIED"
+
+	AuditActionRefreshTokenReused AuditAction = "REFRESH_TOKEN_REUSED"
+
+	AuditActionAccountLocked   AuditAction = "ACCOUNT_LOCKED"
+	AuditActionAccountUnlocked AuditAction = "ACCOUNT_UNLOCKED"
+
+	AuditActionMFAEnrolled           AuditAction = "MFA_ENROLLED"
+	AuditActionMFADisabled           AuditAction = "MFA_DISABLED"
+	AuditActionMFAChallengeSucceeded AuditAction = "MFA_CHALLENGE_SUCCEEDED"
+	AuditActionMFAChallengeFailed    AuditAction = "MFA_CHALLENGE_FAILED"
+
+	AuditActionPasskeyRegistered AuditAction = "PASSKEY_REGISTERED"
+	AuditActionPasskeyRemoved    AuditAction = "PASSKEY_REMOVED"
+
+	AuditActionPasswordlessRequested AuditAction = "PASSWORDLESS_LOGIN_REQUESTED"
+
+	AuditActionIdentityLinked AuditAction = "IDENTITY_LINKED"
+
+	AuditActionOAuthClientCreated  AuditAction = "OAUTH_CLIENT_CREATED"
+	AuditActionOAuthClientUpdated  AuditAction = "OAUTH_CLIENT_UPDATED"
+	AuditActionOAuthClientDeleted  AuditAction = "OAUTH_CLIENT_DELETED"
+	AuditActionOAuthAuthorized     AuditAction = "OAUTH_AUTHORIZED"
+	AuditActionOAuthTokenIssued    AuditAction = "OAUTH_TOKEN_ISSUED"
+	AuditActionOAuthCodeReused     AuditAction = "OAUTH_CODE_REUSED"
+	AuditActionOAuthTokenRevoked   AuditAction = "OAUTH_TOKEN_REVOKED"
+	AuditActionOAuthDeviceDenied   AuditAction = "OAUTH_DEVICE_DENIED"
+	AuditActionOAuthTokenExchanged AuditAction = "OAUTH_TOKEN_EXCHANGED"
+	AuditActionOAuthConsentGranted AuditAction = "OAUTH_CONSENT_GRANTED"
+	AuditActionOAuthConsentDenied  AuditAction = "OAUTH_CONSENT_DENIED"
+	AuditActionOAuthConsentRevoked AuditAction = "OAUTH_CONSENT_REVOKED"
+	AuditActionOIDCLogout          AuditAction = "OIDC_LOGOUT"
 )
 
 type AuditLog struct {
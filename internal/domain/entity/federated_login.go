@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+// FederatedLogin is a sign-in waiting for the user to come back from an
+// upstream identity provider. StateHash is the hash of the state sent
+// through the provider and DeviceHash that of a token only the client
+// that started the sign-in holds, so a callback cannot be replayed in
+// another browser. The nonce and PKCE code verifier are kept as they are,
+// since they are sent on when the code is redeemed.
+type FederatedLogin struct {
+	ID           string
+	Provider     string
+	StateHash    string
+	DeviceHash   string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+func NewFederatedLogin(id, provider, stateHash, deviceHash, nonce, codeVerifier string, expiresAt time.Time) *FederatedLogin {
+	return &FederatedLogin{
+		ID:           id,
+		Provider:     provider,
+		StateHash:    stateHash,
+		DeviceHash:   deviceHash,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+// CodeChallenge is the S256 PKCE challenge for the code verifier.
+func (l *FederatedLogin) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(l.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (l *FederatedLogin) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+func (l *FederatedLogin) IsUsed() bool {
+	return l.UsedAt != nil
+}
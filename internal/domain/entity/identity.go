@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Identity links a user to their account at an upstream identity
+// provider. Subject is the provider's sub claim, which stays the same when
+// the user changes their email there; Email is the one it last reported.
+type Identity struct {
+	ID          string
+	UserID      string
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+func NewIdentity(id, userID, provider, subject, email string) *Identity {
+	return &Identity{
+		ID:        id,
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// LockoutPolicy limits password guessing against a single account.
+// Failures slow down each further attempt once DelayAfter is reached, and
+// MaxAttempts failures within AttemptWindow lock the account. Every
+// consecutive lockout doubles the lock duration up to MaxDuration.
+type LockoutPolicy struct {
+	MaxAttempts   int
+	AttemptWindow time.Duration
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+
+	DelayAfter int
+	DelayBase  time.Duration
+	DelayMax   time.Duration
+}
+
+// Enabled reports whether accounts are ever locked.
+func (p LockoutPolicy) Enabled() bool {
+	return p.MaxAttempts > 0
+}
+
+// LockDuration returns how long the nth consecutive lockout lasts.
+func (p LockoutPolicy) LockDuration(lockout int) time.Duration {
+	return doubled(p.BaseDuration, lockout-1, p.MaxDuration)
+}
+
+// Delay returns how long to hold the response to a failed login after
+// the given number of recent failures.
+func (p LockoutPolicy) Delay(failedAttempts int) time.Duration {
+	if p.DelayAfter <= 0 || failedAttempts < p.DelayAfter {
+		return 0
+	}
+	return doubled(p.DelayBase, failedAttempts-p.DelayAfter, p.DelayMax)
+}
+
+func doubled(base time.Duration, times int, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < times && d < max; i++ {
+		d *= 2
+	}
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
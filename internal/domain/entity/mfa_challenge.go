@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// MFAChallenge is the pending second step of a login whose password step
+// succeeded.
+type MFAChallenge struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewMFAChallenge(id, userID, tokenHash string, expiresAt time.Time) *MFAChallenge {
+	return &MFAChallenge{
+		ID:        id,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *MFAChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
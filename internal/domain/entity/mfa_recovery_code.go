@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type MFARecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewMFARecoveryCode(id, userID, codeHash string) *MFARecoveryCode {
+	return &MFARecoveryCode{
+		ID:        id,
+		UserID:    userID,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (c *MFARecoveryCode) IsUsed() bool {
+	return c.UsedAt != nil
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	OAuthGrantAuthorizationCode = "authorization_code"
+	OAuthGrantRefreshToken      = "refresh_token"
+	OAuthGrantClientCredentials = "client_credentials"
+	OAuthGrantDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	OAuthGrantTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// Token endpoint authentication methods, as named in RFC 7591.
+const (
+	OAuthAuthMethodNone              = "none"
+	OAuthAuthMethodClientSecretBasic = "client_secret_basic"
+	OAuthAuthMethodClientSecretPost  = "client_secret_post"
+	OAuthAuthMethodPrivateKeyJWT     = "private_key_jwt"
+)
+
+// OAuthClient is an application that signs its users in through this
+// service. Public clients, such as mobile and single-page apps, cannot keep
+// a secret and have an empty SecretHash; PKCE protects their codes instead.
+// A confidential client authenticates either with its secret or, when JWKS
+// is set, with a JWT signed by one of the keys in that JWK Set.
+//
+// AccessTokenTTL overrides the default access token lifetime when positive.
+// ExchangeAudiences are the audiences the client may obtain tokens for
+// with the token exchange grant. RegistrationTokenHash is the hash of the
+// registration access token the client manages its own registration with
+// (RFC 7592). A FirstParty client is one of our own apps, which users are
+// not asked to consent to.
+//
+// PostLogoutRedirectURIs are where the client may send the browser after
+// logging the user out. BackchannelLogoutURI receives a logout token, and
+// FrontchannelLogoutURI is loaded in the logout page, whenever the user
+// logs out.
+type OAuthClient struct {
+	ID                     string
+	Name                   string
+	SecretHash             string
+	JWKS                   string
+	RedirectURIs           []string
+	Scopes                 []string
+	GrantTypes             []string
+	ExchangeAudiences      []string
+	AccessTokenTTL         time.Duration
+	RegistrationTokenHash  string
+	FirstParty             bool
+	PostLogoutRedirectURIs []string
+	BackchannelLogoutURI   string
+	FrontchannelLogoutURI  string
+	CreatedAt              time.Time
+}
+
+func NewOAuthClient(id, name, secretHash string, redirectURIs, scopes, grantTypes []string) *OAuthClient {
+	return &OAuthClient{
+		ID:           id,
+		Name:         name,
+		SecretHash:   secretHash,
+		RedirectURIs: redirectURIs,
+		Scopes:       scopes,
+		GrantTypes:   grantTypes,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+func (c *OAuthClient) IsConfidential() bool {
+	return c.SecretHash != "" || c.JWKS != ""
+}
+
+// AuthMethod names how the client authenticates at the token endpoint.
+func (c *OAuthClient) AuthMethod() string {
+	switch {
+	case c.JWKS != "":
+		return OAuthAuthMethodPrivateKeyJWT
+	case c.SecretHash != "":
+		return OAuthAuthMethodClientSecretBasic
+	default:
+		return OAuthAuthMethodNone
+	}
+}
+
+// HasRedirectURI reports whether uri is registered exactly as given. There
+// is deliberately no normalisation, prefix or wildcard matching.
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// HasPostLogoutRedirectURI matches uri exactly, as HasRedirectURI does.
+func (c *OAuthClient) HasPostLogoutRedirectURI(uri string) bool {
+	return slices.Contains(c.PostLogoutRedirectURIs, uri)
+}
+
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return slices.Contains(c.GrantTypes, grantType)
+}
+
+func (c *OAuthClient) MayExchangeFor(audience string) bool {
+	return slices.Contains(c.ExchangeAudiences, audience)
+}
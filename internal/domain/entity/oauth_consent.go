@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// UserConsent records the scopes a user has granted a client, so the
+// consent page is only shown again when the client asks for more.
+type UserConsent struct {
+	UserID    string
+	ClientID  string
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewUserConsent(userID, clientID string, scopes []string) *UserConsent {
+	now := time.Now().UTC()
+	return &UserConsent{
+		UserID:    userID,
+		ClientID:  clientID,
+		Scopes:    scopes,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Covers reports whether every one of scopes has been granted.
+func (c *UserConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grant adds scopes to those already granted.
+func (c *UserConsent) Grant(scopes []string, now time.Time) {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			c.Scopes = append(c.Scopes, scope)
+		}
+	}
+	c.UpdatedAt = now
+}
+
+// ConsentChallenge is an authorization request from a signed-in user that
+// waits for them to approve or deny it on the consent page. It holds what
+// the authorization code will be issued for; the token that names it is
+// stored hashed.
+type ConsentChallenge struct {
+	ID            string
+	TokenHash     string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        []string
+	State         string
+	Nonce         string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+func NewConsentChallenge(id, tokenHash, clientID, userID, redirectURI string, scopes []string, state, nonce, codeChallenge string, expiresAt time.Time) *ConsentChallenge {
+	return &ConsentChallenge{
+		ID:            id,
+		TokenHash:     tokenHash,
+		ClientID:      clientID,
+		UserID:        userID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: codeChallenge,
+		ExpiresAt:     expiresAt,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+func (c *ConsentChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *ConsentChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type DeviceAuthorizationStatus string
+
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "pending"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceSlowDownStep is added to a device's polling interval each time it
+// polls too fast, as RFC 8628 section 3.5 requires.
+const DeviceSlowDownStep = 5 * time.Second
+
+// DeviceAuthorization is a device authorization grant in progress. The
+// device polls with its device code while the user enters the user code
+// on another device and approves or denies the request. Both codes are
+// stored hashed. FamilyID names the refresh token family the grant
+// starts.
+type DeviceAuthorization struct {
+	ID             string
+	ClientID       string
+	DeviceCodeHash string
+	UserCodeHash   string
+	Scopes         []string
+	Status         DeviceAuthorizationStatus
+	UserID         string
+	FamilyID       string
+	Interval       time.Duration
+	LastPolledAt   *time.Time
+	ExpiresAt      time.Time
+	DecidedAt      *time.Time
+	UsedAt         *time.Time
+	CreatedAt      time.Time
+}
+
+func NewDeviceAuthorization(id, clientID, deviceCodeHash, userCodeHash string, scopes []string, familyID string, interval time.Duration, expiresAt time.Time) *DeviceAuthorization {
+	return &DeviceAuthorization{
+		ID:             id,
+		ClientID:       clientID,
+		DeviceCodeHash: deviceCodeHash,
+		UserCodeHash:   userCodeHash,
+		Scopes:         scopes,
+		Status:         DeviceAuthorizationPending,
+		FamilyID:       familyID,
+		Interval:       interval,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      time.Now().UTC(),
+	}
+}
+
+func (a *DeviceAuthorization) IsExpired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
+
+func (a *DeviceAuthorization) IsUsed() bool {
+	return a.UsedAt != nil
+}
+
+func (a *DeviceAuthorization) IsPending() bool {
+	return a.Status == DeviceAuthorizationPending
+}
+
+// PolledTooSoon reports whether the device polled again before its
+// interval had passed.
+func (a *DeviceAuthorization) PolledTooSoon(now time.Time) bool {
+	return a.LastPolledAt != nil && now.Sub(*a.LastPolledAt) < a.Interval
+}
+
+// NormalizeUserCode folds a user code as typed, in either case and with
+// or without separators, to the form it is hashed in.
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ' || r == '\t':
+			return -1
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return r
+		}
+	}, code)
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+)
+
+// Scopes with a meaning defined by OpenID Connect Core section 5.4.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// ParseScope splits a space-delimited scope parameter, dropping
+// duplicates but keeping the order the client asked for.
+func ParseScope(scope string) []string {
+	var scopes []string
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// ScopeSubset reports whether every scope in requested is also in
+// granted.
+func ScopeSubset(requested, granted []string) bool {
+	for _, scope := range requested {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
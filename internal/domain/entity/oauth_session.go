@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// OAuthSession is a user's sign-in at a client, started when tokens are
+// issued for an authorization. Its ID is the refresh token family of that
+// authorization and is the sid claim of the client's ID tokens, so the
+// client can tell which session a logout ends.
+type OAuthSession struct {
+	ID        string
+	UserID    string
+	ClientID  string
+	CreatedAt time.Time
+	EndedAt   *time.Time
+}
+
+func NewOAuthSession(id, userID, clientID string) *OAuthSession {
+	return &OAuthSession{
+		ID:        id,
+		UserID:    userID,
+		ClientID:  clientID,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending    OutboxStatus = "pending"
+	OutboxStatusProcessing OutboxStatus = "processing"
+	OutboxStatusDelivered  OutboxStatus = "delivered"
+	OutboxStatusDead       OutboxStatus = "dead"
+)
+
+// OutboxEvent is a side effect recorded in the same transaction as the
+// write that caused it. Each subscriber gets its own row so deliveries are
+// retried independently.
+type OutboxEvent struct {
+	ID            string
+	EventType     string
+	Subscriber    string
+	DedupKey      string
+	Payload       json.RawMessage
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LockedUntil   *time.Time
+	LastError     string
+	CreatedAt     time.Time
+	ProcessedAt   *time.Time
+}
+
+func NewOutboxEvent(id, eventType, subscriber, dedupKey string, payload json.RawMessage) *OutboxEvent {
+	now := time.Now().UTC()
+	return &OutboxEvent{
+		ID:            id,
+		EventType:     eventType,
+		Subscriber:    subscriber,
+		DedupKey:      dedupKey,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+type PasswordlessMethod string
+
+const (
+	PasswordlessMethodLink PasswordlessMethod = "link"
+	PasswordlessMethodCode PasswordlessMethod = "code"
+)
+
+func (m PasswordlessMethod) IsValid() bool {
+	return m == PasswordlessMethodLink || m == PasswordlessMethodCode
+}
+
+// PasswordlessChallenge is a pending email sign-in. It is bound to the
+// device that started it: DeviceHash is the hash of a token only that
+// client holds, and SecretHash the hash of the emailed link token or of
+// the signed PasswordlessCodePayload.
+type PasswordlessChallenge struct {
+	ID         string
+	UserID     string
+	Method     PasswordlessMethod
+	DeviceHash string
+	SecretHash string
+	Attempts   int
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+func NewPasswordlessChallenge(id, userID string, method PasswordlessMethod, deviceHash, secretHash string, expiresAt time.Time) *PasswordlessChallenge {
+	return &PasswordlessChallenge{
+		ID:         id,
+		UserID:     userID,
+		Method:     method,
+		DeviceHash: deviceHash,
+		SecretHash: secretHash,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func (c *PasswordlessChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *PasswordlessChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+// PasswordlessCodePayload binds a sign-in code to the device it was sent
+// for. The payload is signed before hashing, so a six-digit code cannot be
+// recovered from the stored hash without the signing key.
+func PasswordlessCodePayload(deviceHash, code string) string {
+	return deviceHash + ":" + code
+}
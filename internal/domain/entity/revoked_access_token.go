@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// RevokedAccessToken marks an access token, by its jti, as no longer
+// valid. It only needs to be kept until the token would have expired.
+type RevokedAccessToken struct {
+	ID        string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func NewRevokedAccessToken(id string, expiresAt time.Time) *RevokedAccessToken {
+	return &RevokedAccessToken{
+		ID:        id,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now().UTC(),
+	}
+}
+
+func (t *RevokedAccessToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
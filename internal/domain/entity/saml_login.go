@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// SAMLLogin hands a sign-in accepted at the assertion consumer service
+// over to the client, which redeems the code whose hash is CodeHash for
+// tokens. DeviceHash is carried over from the request the sign-in
+// answers; it is empty for IdP-initiated sign-ins, which no client
+// started.
+type SAMLLogin struct {
+	ID         string
+	IdP        string
+	UserID     string
+	CodeHash   string
+	DeviceHash string
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+func NewSAMLLogin(id, idp, userID, codeHash, deviceHash string, expiresAt time.Time) *SAMLLogin {
+	return &SAMLLogin{
+		ID:         id,
+		IdP:        idp,
+		UserID:     userID,
+		CodeHash:   codeHash,
+		DeviceHash: deviceHash,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func (l *SAMLLogin) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+func (l *SAMLLogin) IsUsed() bool {
+	return l.UsedAt != nil
+}
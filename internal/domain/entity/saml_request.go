@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// SAMLRequest is an authentication request sent to a SAML identity
+// provider, waiting for the response that answers it. ID is the request's
+// own ID, which the response names in InResponseTo. DeviceHash is the
+// hash of a token only the client that started the sign-in holds, which
+// the sign-in carries through to its redemption.
+type SAMLRequest struct {
+	ID         string
+	IdP        string
+	DeviceHash string
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+func NewSAMLRequest(id, idp, deviceHash string, expiresAt time.Time) *SAMLRequest {
+	return &SAMLRequest{
+		ID:         id,
+		IdP:        idp,
+		DeviceHash: deviceHash,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func (r *SAMLRequest) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+func (r *SAMLRequest) IsUsed() bool {
+	return r.UsedAt != nil
+}
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Algorithms a signing key can be generated for, as JWS "alg" values.
+const (
+	SigningAlgRS256 = "RS256"
+	SigningAlgES256 = "ES256"
+	SigningAlgEdDSA = "EdDSA"
+)
+
+type SigningKeyStatus string
+
+const (
+	// SigningKeyPending keys are published but do not sign yet, so relying
+	// parties can fetch them before the first token they sign appears.
+	SigningKeyPending SigningKeyStatus = "pending"
+	SigningKeyActive  SigningKeyStatus = "active"
+	// SigningKeyRetired keys no longer sign but stay published until the
+	// tokens they signed have expired.
+	SigningKeyRetired SigningKeyStatus = "retired"
+	SigningKeyExpired SigningKeyStatus = "expired"
+)
+
+// SigningKey is a token signing key pair. ID is the kid tokens name it by.
+// PrivateKey is PKCS #8 sealed with the key encryption key and PublicKey
+// is PKIX.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  []byte
+	PublicKey   []byte
+	ActivatesAt time.Time
+	CreatedAt   time.Time
+}
+
+func NewSigningKey(id, algorithm string, privateKey, publicKey []byte, activatesAt time.Time) *SigningKey {
+	return &SigningKey{
+		ID:          id,
+		Algorithm:   algorithm,
+		PrivateKey:  privateKey,
+		PublicKey:   publicKey,
+		ActivatesAt: activatesAt,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// SigningKeyRing holds every stored key, ordered by activation. The most
+// recently activated key signs; each key it supersedes remains published
+// for overlap, the longest lifetime of a token signed with it.
+type SigningKeyRing []*SigningKey
+
+func NewSigningKeyRing(keys []*SigningKey) SigningKeyRing {
+	ring := slices.Clone(keys)
+	slices.SortStableFunc(ring, func(a, b *SigningKey) int {
+		return a.ActivatesAt.Compare(b.ActivatesAt)
+	})
+	return ring
+}
+
+// Active returns the key that signs at now, or nil if none has activated.
+func (r SigningKeyRing) Active(now time.Time) *SigningKey {
+	for i := len(r) - 1; i >= 0; i-- {
+		if !r[i].ActivatesAt.After(now) {
+			return r[i]
+		}
+	}
+	return nil
+}
+
+// Latest returns the most recently created key by activation time,
+// including one that is still pending.
+func (r SigningKeyRing) Latest() *SigningKey {
+	if len(r) == 0 {
+		return nil
+	}
+	return r[len(r)-1]
+}
+
+func (r SigningKeyRing) Status(key *SigningKey, now time.Time, overlap time.Duration) SigningKeyStatus {
+	if key.ActivatesAt.After(now) {
+		return SigningKeyPending
+	}
+
+	i := slices.Index(r, key)
+	if i < 0 || i == len(r)-1 || r[i+1].ActivatesAt.After(now) {
+		return SigningKeyActive
+	}
+
+	// The key stopped signing when its successor activated.
+	if now.Before(r[i+1].ActivatesAt.Add(overlap)) {
+		return SigningKeyRetired
+	}
+	return SigningKeyExpired
+}
+
+// Published returns the keys tokens may still be verified with.
+func (r SigningKeyRing) Published(now time.Time, overlap time.Duration) []*SigningKey {
+	var published []*SigningKey
+	for _, key := range r {
+		if r.Status(key, now, overlap) != SigningKeyExpired {
+			published = append(published, key)
+		}
+	}
+	return published
+}
+
+// Expired returns the keys no unexpired token was signed with.
+func (r SigningKeyRing) Expired(now time.Time, overlap time.Duration) []*SigningKey {
+	var expired []*SigningKey
+	for _, key := range r {
+		if r.Status(key, now, overlap) == SigningKeyExpired {
+			expired = append(expired, key)
+		}
+	}
+	return expired
+}
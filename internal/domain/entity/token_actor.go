@@ -0,0 +1,29 @@
+package entity
+
+// TokenActor is a party acting on behalf of a token's subject, the act
+// claim of RFC 8693 section 4.1. Actor is the party it acted for in turn,
+// so the chain starts with the most recent actor.
+type TokenActor struct {
+	Subject  string
+	ClientID string
+	Actor    *TokenActor
+}
+
+// Depth counts the actors in the chain starting at a.
+func (a *TokenActor) Depth() int {
+	depth := 0
+	for ; a != nil; a = a.Actor {
+		depth++
+	}
+	return depth
+}
+
+// Subjects lists the subject of each actor in the chain, most recent
+// first.
+func (a *TokenActor) Subjects() []string {
+	var subjects []string
+	for ; a != nil; a = a.Actor {
+		subjects = append(subjects, a.Subject)
+	}
+	return subjects
+}
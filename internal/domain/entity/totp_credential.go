@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// TOTPCredential is a user's authenticator app secret. It only counts as
+// a second factor once confirmed with a valid code.
+type TOTPCredential struct {
+	ID               string
+	UserID           string
+	SecretCiphertext []byte
+	ConfirmedAt      *time.Time
+	// LastUsedStep is the time step of the last accepted code, so a code
+	// cannot be replayed within its validity window.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+func NewTOTPCredential(id, userID string, secretCiphertext []byte) *TOTPCredential {
+	return &TOTPCredential{
+		ID:               id,
+		UserID:           userID,
+		SecretCiphertext: secretCiphertext,
+		CreatedAt:        time.Now().UTC(),
+	}
+}
+
+func (c *TOTPCredential) IsConfirmed() bool {
+	return c.ConfirmedAt != nil
+}
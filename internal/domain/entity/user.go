@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
 	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
 )
@@ -9,8 +11,18 @@ type User struct {
 	ID              vo.UserID
 	Username        vo.Username
 	Email           vo.Email
+	PasswordHash    string
 	IsActive        bool
 	IsEmailVerified bool
+	// Roles are the sorted roles granted to the user. Accounts from a
+	// directory get theirs from its group memberships on every sign-in.
+	Roles []string
+
+	// Lockout state is written through the repository's dedicated
+	// methods so concurrent failed logins are counted atomically.
+	FailedLoginAttempts int
+	LockoutCount        int
+	LockedUntil         *time.Time
 }
 
 func NewUser(id vo.UserID, username vo.Username, email vo.Email) *User {
@@ -23,6 +35,20 @@ func NewUser(id vo.UserID, username vo.Username, email vo.Email) *User {
 	}
 }
 
+func (u *User) SetPasswordHash(hash string) error {
+	if hash == "" {
+		return exception.ErrPasswordHashRequired
+	}
+	u.PasswordHash = hash
+	return nil
+}
+
+// HasPassword reports whether the user can sign in with a password.
+// Accounts registered without one sign in by email or passkey.
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
 func (u *User) Activate() error {
 	if u.IsActive {
 		return exception.ErrUserAlreadyActive
@@ -50,3 +76,13 @@ func (u *User) VerifyEmail() error {
 	u.IsEmailVerified = true
 	return nil
 }
+
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// HasLockoutState reports whether there is anything for a successful
+// login or an unlock to clear.
+func (u *User) HasLockoutState() bool {
+	return u.FailedLoginAttempts > 0 || u.LockoutCount > 0 || u.LockedUntil != nil
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// WebAuthnCredential is a passkey or security key registered to a user.
+type WebAuthnCredential struct {
+	ID           string
+	UserID       string
+	Name         string
+	CredentialID []byte
+	// PublicKey is the COSE-encoded credential public key.
+	PublicKey         []byte
+	Algorithm         int
+	SignCount         uint32
+	AAGUID            []byte
+	Transports        []string
+	AttestationFormat string
+	BackupEligible    bool
+	BackupState       bool
+	CreatedAt         time.Time
+	LastUsedAt        *time.Time
+}
+
+// AcceptsSignCount reports whether next is a plausible signature counter
+// after the stored one. Authenticators that do not count always report
+// zero; otherwise the counter must grow, or the credential may have been
+// cloned.
+func (c *WebAuthnCredential) AcceptsSignCount(next uint32) bool {
+	if next == 0 && c.SignCount == 0 {
+		return true
+	}
+	return next > c.SignCount
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type WebAuthnCeremony string
+
+const (
+	WebAuthnCeremonyRegistration   WebAuthnCeremony = "registration"
+	WebAuthnCeremonyAuthentication WebAuthnCeremony = "authentication"
+)
+
+// WebAuthnSession holds the challenge of a started WebAuthn ceremony
+// until the browser's response comes back. UserID is empty for a
+// passwordless login with a discoverable credential.
+type WebAuthnSession struct {
+	ID        string
+	UserID    string
+	Ceremony  WebAuthnCeremony
+	Challenge []byte
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewWebAuthnSession(id, userID string, ceremony WebAuthnCeremony, challenge []byte, expiresAt time.Time) *WebAuthnSession {
+	return &WebAuthnSession{
+		ID:        id,
+		UserID:    userID,
+		Ceremony:  ceremony,
+		Challenge: challenge,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (s *WebAuthnSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+func (s *WebAuthnSession) IsUsed() bool {
+	return s.UsedAt != nil
+}
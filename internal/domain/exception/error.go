@@ -1,6 +1,9 @@
 package exception
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrUserAlreadyActive     = errors.New("User already active")
@@ -20,4 +23,58 @@ var (
 
 	ErrUserIDRequired = errors.New("UserID is required")
 	ErrUserIDInvalid  = errors.New("UserID format is invalid")
+
+	ErrUserNotFound = errors.New("User not found")
+
+	ErrInvalidCredentials = errors.New("Invalid credentials")
+
+	ErrInvalidRefreshToken = errors.New("Refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("Refresh token has already been used")
+
+	ErrInvalidResetToken = errors.New("Password reset token is invalid or expired")
+
+	ErrInvalidVerificationToken = errors.New("Email verification token is invalid or expired")
+	ErrEmailNotVerified         = errors.New("Email is not verified")
+
+	ErrMFAAlreadyEnabled   = errors.New("Two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("Two-factor authentication is not enabled")
+	ErrMFASetupNotStarted  = errors.New("Two-factor authentication setup has not been started")
+	ErrInvalidMFACode      = errors.New("Authentication code is invalid")
+	ErrInvalidMFAChallenge = errors.New("MFA challenge is invalid or expired")
+
+	ErrInvalidWebAuthnSession   = errors.New("Passkey ceremony is invalid or expired")
+	ErrInvalidPasskey           = errors.New("Passkey response is invalid")
+	ErrPasskeyAlreadyRegistered = errors.New("Passkey is already registered")
+	ErrPasskeyNotFound          = errors.New("Passkey not found")
+	ErrPasskeySignCount         = errors.New("Passkey signature counter did not increase")
+
+	ErrInvalidPasswordlessMethod    = errors.New("Sign-in method must be link or code")
+	ErrInvalidPasswordlessChallenge = errors.New("Sign-in request is invalid or expired")
+	ErrInvalidPasswordlessCode      = errors.New("Sign-in link or code is invalid")
+
+	ErrUnknownIdentityProvider     = errors.New("Identity provider is not configured")
+	ErrIdentityProviderUnavailable = errors.New("Identity provider is unavailable")
+	ErrInvalidFederatedLogin       = errors.New("Sign-in with the identity provider is invalid or expired")
+	ErrFederatedLoginFailed        = errors.New("Identity provider did not confirm the sign-in")
+	ErrFederatedEmailRequired      = errors.New("Identity provider did not share a verified email")
+	ErrInvalidSAMLResponse         = errors.New("SAML response is invalid")
+	ErrInvalidSAMLLogin            = errors.New("SAML sign-in is invalid or expired")
+
+	ErrInvalidOAuthClient    = errors.New("OAuth client is not registered")
+	ErrInvalidRedirectURI    = errors.New("Redirect URI is not registered for this client")
+	ErrInvalidClientMetadata = errors.New("OAuth client registration is invalid")
+	ErrInvalidScope          = errors.New("Requested scope exceeds the original grant")
+	ErrInsufficientScope     = errors.New("Access token was not granted the openid scope")
+	ErrInvalidUserCode       = errors.New("Device code is invalid or expired")
+	ErrInvalidConsentToken   = errors.New("Consent request is invalid or expired")
+	ErrConsentNotFound       = errors.New("No consent was given to this client")
+
+	// ErrInvalidClientRedirectURI is the ErrInvalidClientMetadata that
+	// dynamic registration reports as invalid_redirect_uri.
+	ErrInvalidClientRedirectURI = fmt.Errorf("%w: redirect URI is invalid", ErrInvalidClientMetadata)
+	ErrInvalidRegistrationToken = errors.New("Registration access token is invalid")
+
+	ErrPasswordRequired        = errors.New("Password is required")
+	ErrPasswordHashRequired    = errors.New("Password hash is required")
+	ErrPasswordPolicyViolation = errors.New("Password does not meet the password policy")
 )
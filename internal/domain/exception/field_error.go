@@ -0,0 +1,30 @@
+package exception
+
+import "strings"
+
+// FieldError describes why a single input field was rejected. Code is a
+// stable identifier clients can switch on; Message is for display.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password failed, so the client
+// can report them all at once. It matches ErrPasswordPolicyViolation.
+type PasswordPolicyError struct {
+	Violations  []FieldError
+	Suggestions []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return ErrPasswordPolicyViolation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrPasswordPolicyViolation
+}
@@ -0,0 +1,48 @@
+package exception
+
+// Error codes an OAuth client receives, from RFC 6749 sections 4.1.2.1
+// and 5.2.
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthAccessDenied            = "access_denied"
+)
+
+// Error codes a device polling the token endpoint receives, from RFC 8628
+// section 3.5.
+const (
+	OAuthAuthorizationPending = "authorization_pending"
+	OAuthSlowDown             = "slow_down"
+	OAuthExpiredToken         = "expired_token"
+)
+
+// OAuthInvalidTarget is returned for a token exchange to an audience the
+// client may not obtain tokens for (RFC 8693 section 2.2.2).
+const OAuthInvalidTarget = "invalid_target"
+
+// Error codes added by OpenID Connect Core section 3.1.2.6.
+const (
+	OIDCLoginRequired          = "login_required"
+	OIDCRequestNotSupported    = "request_not_supported"
+	OIDCRequestURINotSupported = "request_uri_not_supported"
+)
+
+// OAuthError is a protocol error returned to an OAuth client, either in
+// the token response or on its redirect URI.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type UserConsentRepository interface {
+	Find(ctx context.Context, userID, clientID string) (*entity.UserConsent, error)
+	ListByUserID(ctx context.Context, userID string) ([]*entity.UserConsent, error)
+	// Save creates the consent or replaces the scopes of an existing one.
+	Save(ctx context.Context, consent *entity.UserConsent) error
+	// Delete reports false if the user had not granted the client
+	// anything.
+	Delete(ctx context.Context, userID, clientID string) (bool, error)
+}
+
+type ConsentChallengeRepository interface {
+	Create(ctx context.Context, challenge *entity.ConsentChallenge) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ConsentChallenge, error)
+	// MarkUsed consumes the challenge and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type DeviceAuthorizationRepository interface {
+	Create(ctx context.Context, authorization *entity.DeviceAuthorization) error
+	FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*entity.DeviceAuthorization, error)
+	FindByUserCodeHash(ctx context.Context, userCodeHash string) (*entity.DeviceAuthorization, error)
+	// RecordPoll stores when the device last polled and the interval it
+	// must now wait between polls.
+	RecordPoll(ctx context.Context, id string, polledAt time.Time, interval time.Duration) error
+	// Decide records the user's approval or denial and reports false if
+	// the request was no longer pending or had expired.
+	Decide(ctx context.Context, id, userID string, status entity.DeviceAuthorizationStatus, decidedAt time.Time) (bool, error)
+	// MarkUsed consumes an approved request and reports false if it was
+	// already used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type EmailVerificationTokenRepository interface {
+	Create(ctx context.Context, token *entity.EmailVerificationToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error)
+	FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error)
+	// MarkUsed consumes the token and reports false if it was already used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+	InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error
+}
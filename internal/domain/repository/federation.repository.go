@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity *entity.Identity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error)
+	// ExistsForUser reports whether the user is linked to an identity at
+	// the provider.
+	ExistsForUser(ctx context.Context, userID, provider string) (bool, error)
+	// RecordLogin stores the time of a sign-in and the email the provider
+	// reported with it.
+	RecordLogin(ctx context.Context, id, email string, at time.Time) error
+}
+
+type FederatedLoginRepository interface {
+	Create(ctx context.Context, login *entity.FederatedLogin) error
+	FindByStateHash(ctx context.Context, stateHash string) (*entity.FederatedLogin, error)
+	// MarkUsed consumes the sign-in and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
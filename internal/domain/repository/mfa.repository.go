@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type TOTPCredentialRepository interface {
+	// Save stores a new unconfirmed credential, replacing any earlier
+	// unconfirmed one. It never overwrites a confirmed credential.
+	Save(ctx context.Context, credential *entity.TOTPCredential) error
+	FindByUserID(ctx context.Context, userID string) (*entity.TOTPCredential, error)
+	// Confirm activates a pending credential and reports false if it was
+	// already confirmed or no longer exists.
+	Confirm(ctx context.Context, userID string, step int64, confirmedAt time.Time) (bool, error)
+	// UseStep records an accepted code's time step and reports false if
+	// that step or a later one was already used.
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+type MFARecoveryCodeRepository interface {
+	// ReplaceForUser discards the user's existing codes and stores codes.
+	ReplaceForUser(ctx context.Context, userID string, codes []*entity.MFARecoveryCode) error
+	// MarkUsed consumes the matching unused code and reports false if
+	// there is none.
+	MarkUsed(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error)
+	CountUnused(ctx context.Context, userID string) (int, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge *entity.MFAChallenge) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error)
+	// IncrementAttempts counts a failed code and returns the new total.
+	IncrementAttempts(ctx context.Context, id string) (int, error)
+	// MarkUsed consumes the challenge and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *entity.OAuthClient) error
+	FindByID(ctx context.Context, id string) (*entity.OAuthClient, error)
+	List(ctx context.Context) ([]*entity.OAuthClient, error)
+	// Update replaces the metadata of the client, keeping its credentials.
+	Update(ctx context.Context, client *entity.OAuthClient) error
+	Delete(ctx context.Context, id string) error
+}
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *entity.AuthorizationCode) error
+	FindByHash(ctx context.Context, codeHash string) (*entity.AuthorizationCode, error)
+	// MarkUsed consumes the code and reports false if it was already used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
+
+type ClientAssertionRepository interface {
+	// Use records the ID of a client assertion until it expires and
+	// reports false if the client already used it.
+	Use(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type OAuthSessionRepository interface {
+	// Create records the session; recording one that exists is a no-op.
+	Create(ctx context.Context, session *entity.OAuthSession) error
+	ListActiveByUserID(ctx context.Context, userID string) ([]*entity.OAuthSession, error)
+	EndByUserID(ctx context.Context, userID string, endedAt time.Time) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	// Create stores the event unless one with the same dedup key exists.
+	Create(ctx context.Context, event *entity.OutboxEvent) error
+	// Claim locks up to limit due events until now+lease and increments
+	// their attempt counter. Events whose lease expired are claimed again
+	// while they have attempts left, and marked dead once they have none.
+	Claim(ctx context.Context, limit int, now time.Time, lease time.Duration, maxAttempts int) ([]*entity.OutboxEvent, error)
+	// MarkDelivered, MarkRetry and MarkDead only apply while the event is
+	// still held under the lease that ends at lockedUntil, and report
+	// whether it was. A lease that expired and was claimed again belongs
+	// to the newer claim.
+	MarkDelivered(ctx context.Context, id string, lockedUntil, processedAt time.Time) (bool, error)
+	MarkRetry(ctx context.Context, id string, lockedUntil, nextAttemptAt time.Time, lastError string) (bool, error)
+	MarkDead(ctx context.Context, id string, lockedUntil, processedAt time.Time, lastError string) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type PasswordlessChallengeRepository interface {
+	Create(ctx context.Context, challenge *entity.PasswordlessChallenge) error
+	FindByDeviceHash(ctx context.Context, deviceHash string) (*entity.PasswordlessChallenge, error)
+	FindLatestByUserID(ctx context.Context, userID string) (*entity.PasswordlessChallenge, error)
+	// IncrementAttempts counts a failed verification and returns the new
+	// total.
+	IncrementAttempts(ctx context.Context, id string) (int, error)
+	// MarkUsed consumes the challenge and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+	// InvalidateByUserID consumes every outstanding challenge of the user.
+	InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error
+}
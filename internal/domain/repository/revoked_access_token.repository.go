@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type RevokedAccessTokenRepository interface {
+	// Create records the revocation; revoking a token twice is not an
+	// error.
+	Create(ctx context.Context, token *entity.RevokedAccessToken) error
+	// ListActive returns the revocations of tokens that expire after now.
+	ListActive(ctx context.Context, now time.Time) ([]*entity.RevokedAccessToken, error)
+	// CreateCutoff records the cutoff, replacing the user's previous one.
+	CreateCutoff(ctx context.Context, cutoff *entity.AccessTokenCutoff) error
+	// ListActiveCutoffs returns the cutoffs that expire after now.
+	ListActiveCutoffs(ctx context.Context, now time.Time) ([]*entity.AccessTokenCutoff, error)
+	// DeleteExpired deletes expired revocations and cutoffs.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
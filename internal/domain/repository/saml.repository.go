@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type SAMLRequestRepository interface {
+	Create(ctx context.Context, request *entity.SAMLRequest) error
+	FindByID(ctx context.Context, id string) (*entity.SAMLRequest, error)
+	// MarkUsed consumes the request and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
+
+type SAMLLoginRepository interface {
+	Create(ctx context.Context, login *entity.SAMLLogin) error
+	FindByCodeHash(ctx context.Context, codeHash string) (*entity.SAMLLogin, error)
+	// MarkUsed consumes the sign-in and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
+
+// SAMLAssertionRepository remembers the assertions that have been
+// consumed, until they expire, so none is accepted twice.
+type SAMLAssertionRepository interface {
+	// Remember records the assertion and reports false if it was already
+	// recorded.
+	Remember(ctx context.Context, idp, assertionID string, expiresAt time.Time) (bool, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type SigningKeyRepository interface {
+	Create(ctx context.Context, key *entity.SigningKey) error
+	List(ctx context.Context) ([]*entity.SigningKey, error)
+	Delete(ctx context.Context, ids []string) error
+	// Lock serializes key rotation across instances until the surrounding
+	// transaction ends. It must be called inside one.
+	Lock(ctx context.Context) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
 )
@@ -14,4 +15,13 @@ type UserRepository interface {
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	Update(ctx context.Context, user *entity.User) error
+
+	// RecordFailedLogin atomically counts a failed login, forgetting
+	// failures before windowStart, and refreshes the user's lockout fields.
+	RecordFailedLogin(ctx context.Context, user *entity.User, now, windowStart time.Time) error
+	// Lock locks the account until the given time if it still has at least
+	// threshold failures, so concurrent failures lock it only once.
+	Lock(ctx context.Context, user *entity.User, until time.Time, threshold int) (bool, error)
+	// ResetFailedLogins clears failure counters and any lock.
+	ResetFailedLogins(ctx context.Context, user *entity.User) error
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *entity.WebAuthnCredential) error
+	FindByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error)
+	ListByUserID(ctx context.Context, userID string) ([]*entity.WebAuthnCredential, error)
+	// UpdateUsage stores the new signature counter and reports false if
+	// another login already advanced it past the expected value.
+	UpdateUsage(ctx context.Context, id string, expectedSignCount, signCount uint32, backupState bool, usedAt time.Time) (bool, error)
+	// Delete removes the user's credential and reports false if there was
+	// none.
+	Delete(ctx context.Context, userID, id string) (bool, error)
+}
+
+type WebAuthnSessionRepository interface {
+	Create(ctx context.Context, session *entity.WebAuthnSession) error
+	FindByID(ctx context.Context, id string) (*entity.WebAuthnSession, error)
+	// MarkUsed consumes the session and reports false if it was already
+	// used.
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error)
+}
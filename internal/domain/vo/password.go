@@ -0,0 +1,178 @@
+package vo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+const (
+	PasswordViolationTooShort             = "too_short"
+	PasswordViolationTooLong              = "too_long"
+	PasswordViolationMissingUppercase     = "missing_uppercase"
+	PasswordViolationMissingLowercase     = "missing_lowercase"
+	PasswordViolationMissingDigit         = "missing_digit"
+	PasswordViolationMissingSymbol        = "missing_symbol"
+	PasswordViolationContainsPersonalInfo = "contains_personal_info"
+	PasswordViolationTooWeak              = "too_weak"
+	PasswordViolationBreached             = "breached"
+)
+
+const passwordField = "password"
+
+// minPersonalInfoLength keeps very short usernames from rejecting most
+// passwords that happen to contain them.
+const minPersonalInfoLength = 3
+
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// MinStrengthScore is the lowest accepted EstimatePasswordStrength
+	// score, from 0 (accept anything) to 4.
+	MinStrengthScore   int
+	RejectPersonalInfo bool
+}
+
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:          8,
+		MaxLength:          64,
+		MinStrengthScore:   2,
+		RejectPersonalInfo: true,
+	}
+}
+
+type Password struct {
+	value string
+}
+
+// NewPassword checks value against policy. personalInfo holds values the
+// password must not contain, such as the username and email address. All
+// failed rules are returned together in an *exception.PasswordPolicyError.
+func NewPassword(value string, policy PasswordPolicy, personalInfo ...string) (Password, error) {
+	if value == "" {
+		return Password{}, exception.ErrPasswordRequired
+	}
+
+	var violations []exception.FieldError
+	addViolation := func(code, message string) {
+		violations = append(violations, exception.FieldError{
+			Field:   passwordField,
+			Code:    code,
+			Message: message,
+		})
+	}
+
+	length := utf8.RuneCountInString(value)
+	if length < policy.MinLength {
+		addViolation(PasswordViolationTooShort, fmt.Sprintf("password must be at least %d characters", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		addViolation(PasswordViolationTooLong, fmt.Sprintf("password must be at most %d characters", policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range value {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireUppercase && !hasUpper {
+		addViolation(PasswordViolationMissingUppercase, "password must contain an uppercase letter")
+	}
+	if policy.RequireLowercase && !hasLower {
+		addViolation(PasswordViolationMissingLowercase, "password must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		addViolation(PasswordViolationMissingDigit, "password must contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		addViolation(PasswordViolationMissingSymbol, "password must contain a symbol")
+	}
+
+	if policy.RejectPersonalInfo && containsPersonalInfo(value, personalInfo) {
+		addViolation(PasswordViolationContainsPersonalInfo, "password must not contain your username or email address")
+	}
+
+	var suggestions []string
+	// Length and composition failures already explain the problem, so
+	// only score passwords that pass them.
+	if len(violations) == 0 && policy.MinStrengthScore > 0 {
+		strength := EstimatePasswordStrength(value, personalInfo...)
+		if strength.Score < policy.MinStrengthScore {
+			message := "password is too easy to guess"
+			if strength.Warning != "" {
+				message += ": " + strings.ToLower(strength.Warning[:1]) + strength.Warning[1:]
+			}
+			addViolation(PasswordViolationTooWeak, message)
+			suggestions = strength.Suggestions
+		}
+	}
+
+	if len(violations) > 0 {
+		return Password{}, &exception.PasswordPolicyError{
+			Violations:  violations,
+			Suggestions: suggestions,
+		}
+	}
+
+	return Password{value: value}, nil
+}
+
+func containsPersonalInfo(password string, personalInfo []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, info := range personalInfoTokens(personalInfo) {
+		if strings.Contains(lowered, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// personalInfoTokens lowercases the given values and adds the local part
+// of email addresses, dropping anything too short to be meaningful.
+func personalInfoTokens(personalInfo []string) []string {
+	var tokens []string
+
+	for _, info := range personalInfo {
+		info = strings.ToLower(strings.TrimSpace(info))
+		candidates := []string{info}
+		if local, _, ok := strings.Cut(info, "@"); ok {
+			candidates = append(candidates, local)
+		}
+
+		for _, candidate := range candidates {
+			if utf8.RuneCountInString(candidate) >= minPersonalInfoLength {
+				tokens = append(tokens, candidate)
+			}
+		}
+	}
+
+	return tokens
+}
+
+// Plaintext returns the password for hashing. It is deliberately not
+// named String so the value is not printed by accident.
+func (p Password) Plaintext() string {
+	return p.value
+}
+
+func (p Password) String() string {
+	return "********"
+}
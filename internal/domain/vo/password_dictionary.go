@@ -0,0 +1,111 @@
+package vo
+
+// commonPasswords is ordered by frequency in public breach corpora; a
+// word's rank is its position in the list.
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty",
+	"1234567", "111111", "1234567890", "123123", "abc123", "1234",
+	"password1", "iloveyou", "1q2w3e4r", "000000", "qwerty123", "zaq12wsx",
+	"dragon", "sunshine", "princess", "letmein", "654321", "monkey",
+	"1qaz2wsx", "123321", "qwertyuiop", "superman", "asdfghjkl", "123qwe",
+	"football", "baseball", "welcome", "admin", "master", "shadow",
+	"michael", "jennifer", "666666", "121212", "trustno1", "hello",
+	"freedom", "whatever", "qazwsx", "ninja", "mustang", "access",
+	"flower", "555555", "passw0rd", "lovely", "7777777", "888888",
+	"123abc", "charlie", "aa123456", "donald", "batman", "starwars",
+	"login", "solo", "loveme", "hottie", "zxcvbnm", "1q2w3e",
+	"michelle", "jessica", "pepper", "daniel", "andrew", "joshua",
+	"hunter", "thomas", "killer", "secret", "summer", "winter",
+	"spring", "autumn", "computer", "internet", "cheese", "soccer",
+	"hockey", "jordan", "harley", "ranger", "buster", "tigger",
+	"robert", "matrix", "pokemon", "samsung", "google", "chocolate",
+	"purple", "orange", "banana", "cookie", "maggie", "ginger",
+	"silver", "golden", "diamond", "angel", "babygirl", "anthony",
+	"nicole", "ashley", "family", "friends", "forever", "blessed",
+	"qwe123", "passport", "changeme", "default", "guest", "test",
+	"test123", "root", "toor", "administrator", "adminadmin", "password123",
+	"pass", "pass123", "abcd1234", "1password", "letmein123", "welcome1",
+	"welcome123", "iloveyou1", "monkey123", "dragon123", "11111111", "00000000",
+	"12341234", "987654321", "0987654321", "112233", "159753", "147258369",
+	"123654", "asdf", "asdf1234", "qwer1234", "zxcvbn", "q1w2e3r4",
+	"1a2b3c", "love", "money", "mother", "father", "sister",
+	"brother", "baby", "happy", "lucky", "hello123", "secret123",
+	"princess1", "sunshine1", "football1", "baseball1", "superman1", "starwars1",
+	"master123", "shadow123", "qwertyu", "asdfgh", "zxcvb", "qazxsw",
+	"1qazxsw2", "azerty", "abcdef", "abcdefg", "abcdefgh", "letmeinnow",
+	"whatever1", "trustme", "access14", "mypassword", "mypass", "user",
+	"username", "temp", "temp123", "system", "server", "database",
+}
+
+var commonPasswordRanks = rankWords(commonPasswords)
+
+func rankWords(words []string) map[string]int {
+	ranks := make(map[string]int, len(words))
+	for i, word := range words {
+		if _, exists := ranks[word]; !exists {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}
+
+// l33tTable maps common character substitutions back to the letters they
+// stand for.
+var l33tTable = map[rune][]rune{
+	'4': {'a'},
+	'@': {'a'},
+	'8': {'b'},
+	'(': {'c'},
+	'{': {'c'},
+	'[': {'c'},
+	'<': {'c'},
+	'3': {'e'},
+	'6': {'g'},
+	'9': {'g'},
+	'1': {'i', 'l'},
+	'!': {'i'},
+	'|': {'i', 'l'},
+	'0': {'o'},
+	'$': {'s'},
+	'5': {'s'},
+	'7': {'t'},
+	'+': {'t'},
+	'%': {'x'},
+	'2': {'z'},
+}
+
+type keyPosition struct {
+	row     int
+	x       float64
+	shifted bool
+}
+
+// qwertyLayout places each key at its physical offset so that diagonal
+// neighbours on a staggered keyboard are detected.
+var qwertyLayout = buildKeyboardLayout([]struct {
+	keys    string
+	shifted string
+	offset  float64
+}{
+	{keys: "`1234567890-=", shifted: "~!@#$%^&*()_+", offset: 0},
+	{keys: "qwertyuiop[]\\", shifted: "QWERTYUIOP{}|", offset: 1.5},
+	{keys: "asdfghjkl;'", shifted: "ASDFGHJKL:\"", offset: 1.75},
+	{keys: "zxcvbnm,./", shifted: "ZXCVBNM<>?", offset: 2.25},
+})
+
+func buildKeyboardLayout(rows []struct {
+	keys    string
+	shifted string
+	offset  float64
+}) map[rune]keyPosition {
+	layout := make(map[rune]keyPosition)
+	for row, r := range rows {
+		shifted := []rune(r.shifted)
+		for i, key := range []rune(r.keys) {
+			x := r.offset + float64(i)
+			layout[key] = keyPosition{row: row, x: x}
+			layout[shifted[i]] = keyPosition{row: row, x: x, shifted: true}
+		}
+	}
+	return layout
+}
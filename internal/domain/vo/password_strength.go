@@ -0,0 +1,591 @@
+package vo
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PasswordStrength is a zxcvbn-style estimate of how many guesses an
+// attacker needs, based on the cheapest decomposition of the password into
+// known patterns (common passwords, keyboard walks, repeats, sequences,
+// years) and brute-forced characters.
+type PasswordStrength struct {
+	// Score ranges from 0 (too guessable) to 4 (very unguessable).
+	Score int
+	// Log10Guesses is the base-10 logarithm of the estimated guesses.
+	Log10Guesses float64
+	Warning      string
+	Suggestions  []string
+}
+
+// maxStrengthInput bounds the quadratic pattern search; characters beyond
+// it are counted as brute-forced.
+const maxStrengthInput = 100
+
+const (
+	bruteforceLog10PerChar = 1 // 10 guesses per unknown character
+	minSingleCharLog10     = 1
+	minMultiCharLog10      = 1.69897 // log10(50)
+	minYearSpace           = 20
+	keyboardStartingKeys   = 47
+	keyboardAverageDegree  = 4
+	maxSequenceDelta       = 5
+)
+
+type strengthPattern int
+
+const (
+	patternBruteforce strengthPattern = iota
+	patternDictionary
+	patternSpatial
+	patternRepeat
+	patternSequence
+	patternYear
+)
+
+type strengthMatch struct {
+	pattern strengthPattern
+	start   int
+	end     int
+	log10   float64
+
+	rank        int
+	userInput   bool
+	reversed    bool
+	l33t        bool
+	capitalized bool
+	allUpper    bool
+	turns       int
+	baseLength  int
+}
+
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	overflow := 0
+	if len(runes) > maxStrengthInput {
+		overflow = len(runes) - maxStrengthInput
+		runes = runes[:maxStrengthInput]
+	}
+
+	userRanks := rankWords(userInputWords(userInputs))
+	sequence, log10 := mostGuessableSequence(runes, userRanks)
+	log10 += float64(overflow * bruteforceLog10PerChar)
+
+	strength := PasswordStrength{
+		Score:        scoreFromGuesses(log10),
+		Log10Guesses: log10,
+	}
+	strength.Warning, strength.Suggestions = strengthFeedback(strength.Score, sequence)
+	return strength
+}
+
+func scoreFromGuesses(log10 float64) int {
+	switch {
+	case log10 < 3:
+		return 0
+	case log10 < 6:
+		return 1
+	case log10 < 8:
+		return 2
+	case log10 < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// mostGuessableSequence finds the decomposition of runes with the fewest
+// total guesses. Uncovered characters are brute-forced.
+func mostGuessableSequence(runes []rune, userRanks map[string]int) ([]strengthMatch, float64) {
+	n := len(runes)
+	matches := findMatches(runes, userRanks)
+
+	byEnd := make([][]strengthMatch, n+1)
+	for _, m := range matches {
+		byEnd[m.end] = append(byEnd[m.end], m)
+	}
+
+	best := make([]float64, n+1)
+	choice := make([]*strengthMatch, n+1)
+	for k := 1; k <= n; k++ {
+		best[k] = best[k-1] + bruteforceLog10PerChar
+		choice[k] = nil
+		for i := range byEnd[k] {
+			m := &byEnd[k][i]
+			if cost := best[m.start] + m.log10; cost < best[k] {
+				best[k] = cost
+				choice[k] = m
+			}
+		}
+	}
+
+	var sequence []strengthMatch
+	for k := n; k > 0; {
+		if m := choice[k]; m != nil {
+			sequence = append(sequence, *m)
+			k = m.start
+			continue
+		}
+		start := k - 1
+		for start > 0 && choice[start] == nil {
+			start--
+		}
+		sequence = append(sequence, strengthMatch{
+			pattern: patternBruteforce,
+			start:   start,
+			end:     k,
+			log10:   float64((k - start) * bruteforceLog10PerChar),
+		})
+		k = start
+	}
+
+	for i, j := 0, len(sequence)-1; i < j; i, j = i+1, j-1 {
+		sequence[i], sequence[j] = sequence[j], sequence[i]
+	}
+
+	return sequence, best[n]
+}
+
+func findMatches(runes []rune, userRanks map[string]int) []strengthMatch {
+	var matches []strengthMatch
+	matches = append(matches, dictionaryMatches(runes, userRanks)...)
+	matches = append(matches, spatialMatches(runes)...)
+	matches = append(matches, repeatMatches(runes, userRanks)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, yearMatches(runes)...)
+
+	for i := range matches {
+		floor := minMultiCharLog10
+		if matches[i].end-matches[i].start == 1 {
+			floor = minSingleCharLog10
+		}
+		matches[i].log10 = math.Max(matches[i].log10, floor)
+	}
+
+	return matches
+}
+
+func dictionaryMatches(runes []rune, userRanks map[string]int) []strengthMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+	variants := l33tVariants(lower)
+
+	lookup := func(word string) (int, bool, bool) {
+		if rank, ok := userRanks[word]; ok {
+			return rank, true, true
+		}
+		rank, ok := commonPasswordRanks[word]
+		return rank, false, ok
+	}
+
+	var matches []strengthMatch
+	n := len(lower)
+	for i := 0; i < n; i++ {
+		for j := i + 3; j <= n; j++ {
+			word := string(lower[i:j])
+			m := strengthMatch{pattern: patternDictionary, start: i, end: j}
+
+			rank, user, ok := lookup(word)
+			if !ok {
+				if rank, user, ok = lookup(reverseString(word)); ok {
+					m.reversed = true
+				}
+			}
+			if !ok {
+				for _, variant := range variants {
+					candidate := string(variant[i:j])
+					if candidate == word {
+						continue
+					}
+					if rank, user, ok = lookup(candidate); ok {
+						m.l33t = true
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			m.rank = rank
+			m.userInput = user
+			original := runes[i:j]
+			m.capitalized = unicode.IsUpper(original[0])
+			m.allUpper = isAllUpper(original)
+			m.log10 = math.Log10(float64(rank)) + math.Log10(uppercaseVariations(original))
+			if m.l33t {
+				m.log10 += math.Log10(2)
+			}
+			if m.reversed {
+				m.log10 += math.Log10(2)
+			}
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// l33tVariants returns copies of lower with substitutions undone, one
+// using the first and one using the last candidate letter for each
+// substituted character.
+func l33tVariants(lower []rune) [][]rune {
+	first := make([]rune, len(lower))
+	last := make([]rune, len(lower))
+	changed := false
+
+	for i, r := range lower {
+		subs, ok := l33tTable[r]
+		if !ok {
+			first[i], last[i] = r, r
+			continue
+		}
+		first[i], last[i] = subs[0], subs[len(subs)-1]
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return [][]rune{first, last}
+}
+
+func uppercaseVariations(word []rune) float64 {
+	var upper, lower int
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || unicode.IsUpper(word[0]) && upper == 1 || unicode.IsUpper(word[len(word)-1]) && upper == 1 {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+func spatialMatches(runes []rune) []strengthMatch {
+	var matches []strengthMatch
+	n := len(runes)
+
+	for i := 0; i < n-2; i++ {
+		turns := 0
+		shifted := 0
+		var lastDY int
+		var lastDX float64
+
+		if pos, ok := qwertyLayout[runes[i]]; ok && pos.shifted {
+			shifted++
+		}
+
+		for j := i + 1; j < n; j++ {
+			prev, okPrev := qwertyLayout[runes[j-1]]
+			cur, okCur := qwertyLayout[runes[j]]
+			if !okPrev || !okCur {
+				break
+			}
+			dy := cur.row - prev.row
+			dx := cur.x - prev.x
+			if !isAdjacentKey(dy, dx) {
+				break
+			}
+			if j == i+1 || dy != lastDY || dx != lastDX {
+				turns++
+			}
+			lastDY, lastDX = dy, dx
+			if cur.shifted {
+				shifted++
+			}
+
+			if length := j - i + 1; length >= 3 {
+				log10 := math.Log10(keyboardStartingKeys) + math.Log10(float64(length)) +
+					float64(turns)*math.Log10(keyboardAverageDegree)
+				if shifted > 0 {
+					log10 += math.Log10(2)
+				}
+				matches = append(matches, strengthMatch{
+					pattern: patternSpatial,
+					start:   i,
+					end:     j + 1,
+					log10:   log10,
+					turns:   turns,
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+func isAdjacentKey(dy int, dx float64) bool {
+	switch dy {
+	case 0:
+		return dx == 1 || dx == -1
+	case 1, -1:
+		return math.Abs(dx) <= 1
+	default:
+		return false
+	}
+}
+
+func repeatMatches(runes []rune, userRanks map[string]int) []strengthMatch {
+	var matches []strengthMatch
+	n := len(runes)
+
+	for i := 0; i < n; i++ {
+		for size := 1; i+2*size <= n; size++ {
+			chunk := string(runes[i : i+size])
+			count := 1
+			for i+(count+1)*size <= n && string(runes[i+count*size:i+(count+1)*size]) == chunk {
+				count++
+			}
+			if count < 2 || count*size < 3 {
+				continue
+			}
+
+			_, chunkLog10 := mostGuessableSequence(runes[i:i+size], userRanks)
+			matches = append(matches, strengthMatch{
+				pattern:    patternRepeat,
+				start:      i,
+				end:        i + count*size,
+				log10:      chunkLog10 + math.Log10(float64(count)),
+				baseLength: size,
+			})
+		}
+	}
+
+	return matches
+}
+
+func sequenceMatches(runes []rune) []strengthMatch {
+	var matches []strengthMatch
+	n := len(runes)
+
+	flush := func(start, end int, delta rune) {
+		if end-start < 3 {
+			return
+		}
+		log10 := math.Log10(sequenceStartGuesses(runes[start])) +
+			math.Log10(float64(end-start)) +
+			math.Log10(math.Abs(float64(delta)))
+		if delta < 0 {
+			log10 += math.Log10(2)
+		}
+		matches = append(matches, strengthMatch{
+			pattern: patternSequence,
+			start:   start,
+			end:     end,
+			log10:   log10,
+		})
+	}
+
+	for i := 0; i < n-1; {
+		delta := runes[i+1] - runes[i]
+		if delta == 0 || delta < -maxSequenceDelta || delta > maxSequenceDelta {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j+1 < n && runes[j+1]-runes[j] == delta {
+			j++
+		}
+		flush(i, j+1, delta)
+		i = j
+	}
+
+	return matches
+}
+
+func sequenceStartGuesses(r rune) float64 {
+	switch {
+	case strings.ContainsRune("aAzZ019", r):
+		return 4
+	case unicode.IsDigit(r):
+		return 10
+	default:
+		return 26
+	}
+}
+
+func yearMatches(runes []rune) []strengthMatch {
+	var matches []strengthMatch
+	reference := time.Now().Year()
+
+	for i := 0; i+4 <= len(runes); i++ {
+		year := 0
+		valid := true
+		for _, r := range runes[i : i+4] {
+			if r < '0' || r > '9' {
+				valid = false
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if !valid || year < 1900 || year > 2099 {
+			continue
+		}
+
+		space := max(abs(year-reference), minYearSpace)
+		matches = append(matches, strengthMatch{
+			pattern: patternYear,
+			start:   i,
+			end:     i + 4,
+			log10:   math.Log10(float64(space)),
+		})
+	}
+
+	return matches
+}
+
+func strengthFeedback(score int, sequence []strengthMatch) (string, []string) {
+	if score > 2 {
+		return "", nil
+	}
+
+	if len(sequence) == 0 {
+		return "", []string{
+			"Use a few words, avoid common phrases",
+			"No need for symbols, digits, or uppercase letters",
+		}
+	}
+
+	var longest *strengthMatch
+	for i := range sequence {
+		m := &sequence[i]
+		if m.pattern == patternBruteforce {
+			continue
+		}
+		if longest == nil || m.end-m.start > longest.end-longest.start {
+			longest = m
+		}
+	}
+
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	if longest == nil {
+		return "", suggestions
+	}
+
+	switch longest.pattern {
+	case patternDictionary:
+		warning := dictionaryWarning(longest, len(sequence) == 1)
+		if longest.capitalized && !longest.allUpper {
+			suggestions = append(suggestions, "Capitalization doesn't help very much")
+		}
+		if longest.allUpper {
+			suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+		}
+		if longest.reversed {
+			suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+		}
+		if longest.l33t {
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		}
+		return warning, suggestions
+
+	case patternSpatial:
+		warning := "Short keyboard patterns are easy to guess"
+		if longest.turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		return warning, append(suggestions, "Use a longer keyboard pattern with more turns")
+
+	case patternRepeat:
+		warning := `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if longest.baseLength == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		return warning, append(suggestions, "Avoid repeated words and characters")
+
+	case patternSequence:
+		return "Sequences like abc or 6543 are easy to guess", append(suggestions, "Avoid sequences")
+
+	case patternYear:
+		return "Recent years are easy to guess", append(suggestions,
+			"Avoid recent years",
+			"Avoid years that are associated with you",
+		)
+	}
+
+	return "", suggestions
+}
+
+func dictionaryWarning(m *strengthMatch, soleMatch bool) string {
+	switch {
+	case m.userInput:
+		return "Avoid using your username or email address"
+	case soleMatch && !m.l33t && !m.reversed && m.rank <= 10:
+		return "This is a top-10 common password"
+	case soleMatch && !m.l33t && !m.reversed && m.rank <= 100:
+		return "This is a top-100 common password"
+	case soleMatch:
+		return "This is a very common password"
+	default:
+		return "This is similar to a commonly used password"
+	}
+}
+
+// userInputWords splits the user's own details into the words an attacker
+// who knows them would try first.
+func userInputWords(userInputs []string) []string {
+	var words []string
+	for _, token := range personalInfoTokens(userInputs) {
+		words = append(words, token)
+		for _, part := range strings.FieldsFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len(part) >= minPersonalInfoLength {
+				words = append(words, part)
+			}
+		}
+	}
+	return words
+}
+
+func isAllUpper(word []rune) bool {
+	hasLetter := false
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type loginFailure struct {
+	count    int
+	lastSeen time.Time
+}
+
+// LoginFailures counts failed logins per identifier in memory. Failures
+// older than the window no longer count, and identifiers whose last
+// failure has left the window are dropped, so identifiers made up by a
+// caller do not pile up. Each instance keeps its own counts.
+type LoginFailures struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	failures  map[string]*loginFailure
+	lastSweep time.Time
+}
+
+func NewLoginFailures(window time.Duration) *LoginFailures {
+	return &LoginFailures{
+		window:   window,
+		failures: make(map[string]*loginFailure),
+	}
+}
+
+func (c *LoginFailures) Add(identifier string, now time.Time) int {
+	key := strings.ToLower(identifier)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep(now)
+
+	f, ok := c.failures[key]
+	if !ok || now.Sub(f.lastSeen) > c.window {
+		f = &loginFailure{}
+		c.failures[key] = f
+	}
+	f.count++
+	f.lastSeen = now
+	return f.count
+}
+
+func (c *LoginFailures) Reset(identifier string) {
+	c.mu.Lock()
+	delete(c.failures, strings.ToLower(identifier))
+	c.mu.Unlock()
+}
+
+// sweep drops expired identifiers at most once per window.
+func (c *LoginFailures) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.window {
+		return
+	}
+	for key, f := range c.failures {
+		if now.Sub(f.lastSeen) > c.window {
+			delete(c.failures, key)
+		}
+	}
+	c.lastSweep = now
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type cachedClient struct {
+	client    entity.OAuthClient
+	expiresAt time.Time
+}
+
+// OAuthClientRepo keeps clients found by ID for a short TTL, since every
+// token, introspection and revocation request authenticates one. Unknown
+// IDs are not cached, so callers cannot fill the cache with them.
+type OAuthClientRepo struct {
+	repo repository.OAuthClientRepository
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+func NewOAuthClientRepo(repo repository.OAuthClientRepository, ttl time.Duration) repository.OAuthClientRepository {
+	if ttl <= 0 {
+		return repo
+	}
+	return &OAuthClientRepo{
+		repo:    repo,
+		ttl:     ttl,
+		clients: make(map[string]cachedClient),
+	}
+}
+
+func (r *OAuthClientRepo) Create(ctx context.Context, client *entity.OAuthClient) error {
+	return r.repo.Create(ctx, client)
+}
+
+func (r *OAuthClientRepo) List(ctx context.Context) ([]*entity.OAuthClient, error) {
+	return r.repo.List(ctx)
+}
+
+// Update and Delete drop the client from this instance's cache; other
+// instances keep using their copy for up to the TTL.
+func (r *OAuthClientRepo) Update(ctx context.Context, client *entity.OAuthClient) error {
+	err := r.repo.Update(ctx, client)
+	r.forget(client.ID)
+	return err
+}
+
+func (r *OAuthClientRepo) Delete(ctx context.Context, id string) error {
+	err := r.repo.Delete(ctx, id)
+	r.forget(id)
+	return err
+}
+
+func (r *OAuthClientRepo) forget(id string) {
+	r.mu.Lock()
+	delete(r.clients, id)
+	r.mu.Unlock()
+}
+
+// FindByID returns a copy, so callers cannot change the cached client.
+func (r *OAuthClientRepo) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	now := time.Now()
+
+	r.mu.Lock()
+	cached, ok := r.clients[id]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		client := cached.client
+		return &client, nil
+	}
+
+	client, err := r.repo.FindByID(ctx, id)
+	if err != nil || client == nil {
+		return client, err
+	}
+
+	r.mu.Lock()
+	for cachedID, entry := range r.clients {
+		if !now.Before(entry.expiresAt) {
+			delete(r.clients, cachedID)
+		}
+	}
+	r.clients[id] = cachedClient{client: *client, expiresAt: now.Add(r.ttl)}
+	r.mu.Unlock()
+
+	return client, nil
+}
@@ -0,0 +1,64 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+// version prefixes every ciphertext so the format or key can be changed
+// later without guessing how old values were sealed.
+const version byte = 1
+
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// AESGCM seals secrets with AES-256-GCM. Ciphertexts are laid out as
+// version || nonce || sealed data.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if len(key) != 32 {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+func (b *AESGCM) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(nonce)+len(plaintext)+b.aead.Overhead())
+	out = append(out, version)
+	out = append(out, nonce...)
+	return b.aead.Seal(out, nonce, plaintext, []byte{version}), nil
+}
+
+func (b *AESGCM) Open(ciphertext []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize+b.aead.Overhead() || ciphertext[0] != version {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce := ciphertext[1 : 1+nonceSize]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext[1+nonceSize:], []byte{version})
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
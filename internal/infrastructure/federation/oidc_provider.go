@@ -0,0 +1,302 @@
+package federation
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+const (
+	// maxResponseBytes bounds what is read from a provider's endpoints.
+	maxResponseBytes = 1 << 20
+	// idTokenLeeway absorbs clock skew between us and the provider.
+	idTokenLeeway = time.Minute
+)
+
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// ProviderConfig describes an upstream OpenID Connect provider users can
+// sign in with.
+type ProviderConfig struct {
+	ID           string
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// LinkByEmail lets a provider's verified email sign in to an existing
+	// account with that email. Only set it for providers trusted to
+	// verify the addresses they hand out.
+	LinkByEmail bool
+	RedirectURL string
+	// CacheTTL is how long the discovery document and JWKS are kept.
+	CacheTTL time.Duration
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider is the relying party side of the authorization code flow
+// with PKCE against one provider. The discovery document and the
+// provider's keys are fetched on first use and cached; the keys are
+// fetched again early when a token names a key the cache lacks, which is
+// how providers rotate them.
+type OIDCProvider struct {
+	cfg    ProviderConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu               sync.Mutex
+	discovery        *discoveryDocument
+	discoveryExpires time.Time
+	keys             *token.RemoteKeySet
+	keysExpires      time.Time
+}
+
+func NewOIDCProvider(cfg ProviderConfig, client *http.Client) *OIDCProvider {
+	return &OIDCProvider{
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (p *OIDCProvider) ID() string {
+	return p.cfg.ID
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *OIDCProvider) LinksByEmail() bool {
+	return p.cfg.LinkByEmail
+}
+
+func (p *OIDCProvider) AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", entity.PKCEMethodS256)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint and
+// returns who the ID token it answers with says signed in.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*port.ExternalIdentity, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// RFC 6749 section 2.3.1 form-encodes the credentials first.
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint responded with status %d: %s %s", status, tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token endpoint returned no ID token")
+	}
+
+	return p.verifyIDToken(ctx, doc, tokens.IDToken, nonce)
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string      `json:"nonce"`
+	AuthorizedParty   string      `json:"azp"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	PreferredUsername string      `json:"preferred_username"`
+}
+
+// verifyIDToken validates the ID token as OpenID Connect Core section
+// 3.1.3.7 asks of a confidential client.
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, doc *discoveryDocument, raw, nonce string) (*port.ExternalIdentity, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(raw, &jwt.RegisteredClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	kid, _ := unverified.Header["kid"].(string)
+
+	keys, err := p.keySet(ctx, doc, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, keys.Keyfunc(),
+		jwt.WithValidMethods([]string{entity.SigningAlgRS256, entity.SigningAlgES256, entity.SigningAlgEdDSA}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if (claims.AuthorizedParty != "" || len(claims.Audience) > 1) && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: issued to another party", ErrInvalidIDToken)
+	}
+
+	return &port.ExternalIdentity{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified == true || claims.EmailVerified == "true",
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// discover returns the provider's discovery document, which must name
+// the issuer it was configured with.
+func (p *OIDCProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil && p.now().Before(p.discoveryExpires) {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var doc discoveryDocument
+	status, err := p.do(req, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery responded with status %d", status)
+	}
+	if doc.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery names issuer %q, want %q", doc.Issuer, p.cfg.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document lacks an authorization, token or jwks endpoint")
+	}
+
+	p.discovery = &doc
+	p.discoveryExpires = p.now().Add(p.cfg.CacheTTL)
+	return p.discovery, nil
+}
+
+// keySet returns the provider's keys, fetching them when the cache has
+// expired or lacks the key the token was signed with.
+func (p *OIDCProvider) keySet(ctx context.Context, doc *discoveryDocument, kid string) (*token.RemoteKeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && p.now().Before(p.keysExpires) && (kid == "" || p.keys.Has(kid)) {
+		return p.keys, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	status, err := p.do(req, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks endpoint responded with status %d", status)
+	}
+	keys, err := token.ParseRemoteKeySet(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = keys
+	p.keysExpires = p.now().Add(p.cfg.CacheTTL)
+	return p.keys, nil
+}
+
+// do sends the request and decodes its JSON response into v, whatever
+// its status.
+func (p *OIDCProvider) do(req *http.Request, v interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response from %s: %w", req.URL.Redacted(), err)
+	}
+	return resp.StatusCode, nil
+}
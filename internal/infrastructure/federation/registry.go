@@ -0,0 +1,30 @@
+package federation
+
+import "github.com/thanhnamdk2710/auth-service/internal/application/port"
+
+// Registry holds the configured identity providers in the order they
+// were configured, which is the order login pages list them in.
+type Registry struct {
+	providers []port.IdentityProvider
+}
+
+func NewRegistry(providers ...*OIDCProvider) *Registry {
+	registry := &Registry{providers: make([]port.IdentityProvider, 0, len(providers))}
+	for _, provider := range providers {
+		registry.providers = append(registry.providers, provider)
+	}
+	return registry
+}
+
+func (r *Registry) Find(id string) port.IdentityProvider {
+	for _, provider := range r.providers {
+		if provider.ID() == id {
+			return provider
+		}
+	}
+	return nil
+}
+
+func (r *Registry) List() []port.IdentityProvider {
+	return r.providers
+}
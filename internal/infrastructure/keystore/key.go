@@ -0,0 +1,184 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+const rsaKeyBits = 3072
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+// Key is a signing key ready for use. ID is its RFC 7638 JWK thumbprint.
+type Key struct {
+	ID        string
+	Algorithm string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// GenerateKey creates a key for algorithm: RSA 3072 for RS256, P-256 for
+// ES256 and Ed25519 for EdDSA.
+func GenerateKey(algorithm string) (*Key, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case entity.SigningAlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case entity.SigningAlgES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case entity.SigningAlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(algorithm, private)
+}
+
+func newKey(algorithm string, private crypto.Signer) (*Key, error) {
+	key := &Key{
+		Algorithm: algorithm,
+		Method:    jwt.GetSigningMethod(algorithm),
+		Private:   private,
+		Public:    private.Public(),
+	}
+	if key.Method == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	jwk, err := key.jwk()
+	if err != nil {
+		return nil, err
+	}
+	key.ID = thumbprint(jwk)
+	return key, nil
+}
+
+// JWK returns the public half of the key as published in the JWKS.
+func (k *Key) JWK() port.JSONWebKey {
+	jwk, _ := k.jwk()
+	return jwk
+}
+
+func (k *Key) jwk() (port.JSONWebKey, error) {
+	jwk := port.JSONWebKey{
+		Use:       "sig",
+		Algorithm: k.Algorithm,
+		KeyID:     k.ID,
+	}
+
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(public.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return jwk, errors.New("only P-256 keys are supported")
+		}
+		jwk.KeyType = "EC"
+		jwk.Curve = "P-256"
+		jwk.X = encode(public.X.FillBytes(make([]byte, 32)))
+		jwk.Y = encode(public.Y.FillBytes(make([]byte, 32)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(public)
+	default:
+		return jwk, fmt.Errorf("unsupported public key type %T", k.Public)
+	}
+
+	return jwk, nil
+}
+
+// marshal encodes the key pair for storage: PKCS #8 and PKIX.
+func (k *Key) marshal() (private, public []byte, err error) {
+	private, err = x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, nil, err
+	}
+	public, err = x509.MarshalPKIXPublicKey(k.Public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return private, public, nil
+}
+
+// parseKey restores a stored key from its decrypted PKCS #8 private key
+// and checks it still matches the kid it was stored under.
+func parseKey(algorithm, id string, privateDER []byte) (*Key, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(privateDER)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+
+	key, err := newKey(algorithm, private)
+	if err != nil {
+		return nil, err
+	}
+	if key.ID != id {
+		return nil, fmt.Errorf("key %s does not match its thumbprint", id)
+	}
+	return key, nil
+}
+
+// thumbprint is the RFC 7638 JWK thumbprint: the SHA-256 of the required
+// members, in lexicographic order.
+func thumbprint(jwk port.JSONWebKey) string {
+	var members any
+	switch jwk.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Curve, jwk.KeyType, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	encoded, _ := json.Marshal(members)
+	sum := sha256.Sum256(encoded)
+	return encode(sum[:])
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,351 @@
+package keystore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+var ErrNoActiveKey = errors.New("no active signing key")
+
+// Store keeps the token signing keys. Keys live in Postgres with their
+// private half sealed by the key encryption key; every instance holds a
+// decrypted copy that it reloads each refresh interval. While running, the
+// store replaces the signing key once it is older than the rotation
+// interval and deletes keys that are no longer published.
+type Store struct {
+	repo   repository.SigningKeyRepository
+	tx     port.TxManager
+	kek    port.SecretBox
+	log    *logger.Logger
+	config *config.SigningKeyConfig
+
+	mu   sync.RWMutex
+	ring entity.SigningKeyRing
+	keys map[string]*Key
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewStore(
+	repo repository.SigningKeyRepository,
+	tx port.TxManager,
+	kek port.SecretBox,
+	log *logger.Logger,
+	cfg *config.SigningKeyConfig,
+) *Store {
+	return &Store{
+		repo:   repo,
+		tx:     tx,
+		kek:    kek,
+		log:    log,
+		config: cfg,
+		keys:   make(map[string]*Key),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Init loads the keys, creating the first one when none is active yet.
+func (s *Store) Init(ctx context.Context) error {
+	_, err := s.rotate(ctx, func(ring entity.SigningKeyRing, now time.Time) bool {
+		return ring.Active(now) == nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.Reload(ctx)
+}
+
+// Rotate adds a key that starts signing one refresh interval from now,
+// once every instance has loaded it.
+func (s *Store) Rotate(ctx context.Context) (*entity.SigningKey, error) {
+	return s.rotate(ctx, func(entity.SigningKeyRing, time.Time) bool {
+		return true
+	})
+}
+
+// RotateIfDue rotates when the newest key has been signing for the
+// rotation interval, and reports whether it did.
+func (s *Store) RotateIfDue(ctx context.Context) (bool, error) {
+	if s.config.RotationInterval <= 0 {
+		return false, nil
+	}
+
+	key, err := s.rotate(ctx, func(ring entity.SigningKeyRing, now time.Time) bool {
+		latest := ring.Latest()
+		return latest == nil || !latest.ActivatesAt.Add(s.config.RotationInterval).After(now)
+	})
+	return key != nil, err
+}
+
+// rotate creates a key when due says so. The decision is taken under the
+// rotation lock so instances checking at the same moment add one key.
+func (s *Store) rotate(ctx context.Context, due func(entity.SigningKeyRing, time.Time) bool) (*entity.SigningKey, error) {
+	var created *entity.SigningKey
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Lock(ctx); err != nil {
+			return err
+		}
+
+		stored, err := s.repo.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		ring := entity.NewSigningKeyRing(stored)
+		now := time.Now().UTC()
+		if !due(ring, now) {
+			return nil
+		}
+
+		// Without an active key nothing can be signed, and no instance can
+		// hold tokens from the new one, so it activates at once.
+		activatesAt := now.Add(s.config.RefreshInterval)
+		if ring.Active(now) == nil {
+			activatesAt = now
+		}
+
+		created, err = s.newSigningKey(activatesAt)
+		if err != nil {
+			return err
+		}
+		return s.repo.Create(ctx, created)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate signing key: %w", err)
+	}
+
+	if created != nil {
+		s.log.Info("Signing key created",
+			zap.String("kid", created.ID),
+			zap.String("algorithm", created.Algorithm),
+			zap.Time("activates_at", created.ActivatesAt),
+		)
+	}
+	return created, nil
+}
+
+func (s *Store) newSigningKey(activatesAt time.Time) (*entity.SigningKey, error) {
+	key, err := GenerateKey(s.config.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	private, public, err := key.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.kek.Seal(private)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewSigningKey(key.ID, key.Algorithm, sealed, public, activatesAt), nil
+}
+
+// Reload replaces the in-memory keys with the stored ones. Keys already
+// decrypted are reused.
+func (s *Store) Reload(ctx context.Context) error {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	current := s.keys
+	s.mu.RUnlock()
+
+	keys := make(map[string]*Key, len(stored))
+	for _, signingKey := range stored {
+		if key, ok := current[signingKey.ID]; ok {
+			keys[key.ID] = key
+			continue
+		}
+
+		private, err := s.kek.Open(signingKey.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt signing key %s: %w", signingKey.ID, err)
+		}
+		key, err := parseKey(signingKey.Algorithm, signingKey.ID, private)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key %s: %w", signingKey.ID, err)
+		}
+		keys[key.ID] = key
+	}
+
+	s.mu.Lock()
+	s.ring = entity.NewSigningKeyRing(stored)
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Prune deletes the keys no unexpired token can have been signed with.
+func (s *Store) Prune(ctx context.Context) error {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	expired := entity.NewSigningKeyRing(stored).Expired(time.Now().UTC(), s.config.Overlap)
+	if len(expired) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(expired))
+	for _, key := range expired {
+		ids = append(ids, key.ID)
+	}
+	if err := s.repo.Delete(ctx, ids); err != nil {
+		return err
+	}
+
+	s.log.Info("Expired signing keys deleted", zap.Strings("kids", ids))
+	return nil
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (s *Store) SigningKey() (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	active := s.ring.Active(time.Now().UTC())
+	if active == nil {
+		return nil, ErrNoActiveKey
+	}
+	return s.keys[active.ID], nil
+}
+
+// VerificationKey returns the published key with the given kid.
+func (s *Store) VerificationKey(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, published := range s.ring.Published(time.Now().UTC(), s.config.Overlap) {
+		if published.ID == kid {
+			return s.keys[kid], true
+		}
+	}
+	return nil, false
+}
+
+// PublicKeys lists the published keys for the JWKS, pending ones included
+// so relying parties have them before they sign.
+func (s *Store) PublicKeys() []port.JSONWebKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	published := s.ring.Published(time.Now().UTC(), s.config.Overlap)
+	keys := make([]port.JSONWebKey, 0, len(published))
+	for _, key := range published {
+		keys = append(keys, s.keys[key.ID].JWK())
+	}
+	return keys
+}
+
+// KeyInfo describes a stored key for operators.
+type KeyInfo struct {
+	ID          string
+	Algorithm   string
+	ActivatesAt time.Time
+	Status      entity.SigningKeyStatus
+}
+
+// Keys describes every loaded key, oldest first.
+func (s *Store) Keys() []KeyInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	infos := make([]KeyInfo, 0, len(s.ring))
+	for _, key := range s.ring {
+		infos = append(infos, KeyInfo{
+			ID:          key.ID,
+			Algorithm:   key.Algorithm,
+			ActivatesAt: key.ActivatesAt,
+			Status:      s.ring.Status(key, now, s.config.Overlap),
+		})
+	}
+	return infos
+}
+
+// ActiveKeyAge is how long the current signing key has been signing.
+func (s *Store) ActiveKeyAge() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	active := s.ring.Active(now)
+	if active == nil {
+		return 0
+	}
+	return now.Sub(active.ActivatesAt)
+}
+
+func (s *Store) PublishedKeys() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.ring.Published(time.Now().UTC(), s.config.Overlap))
+}
+
+func (s *Store) Start() {
+	s.wg.Add(1)
+	go s.run()
+
+	s.log.Info("Signing key store started",
+		zap.String("algorithm", s.config.Algorithm),
+		zap.Duration("rotation_interval", s.config.RotationInterval),
+		zap.Duration("overlap", s.config.Overlap),
+	)
+}
+
+func (s *Store) Stop() {
+	s.once.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+	s.log.Info("Signing key store stopped")
+}
+
+func (s *Store) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.maintain(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *Store) maintain(ctx context.Context) {
+	if _, err := s.RotateIfDue(ctx); err != nil {
+		s.log.Error("Scheduled signing key rotation failed", zap.Error(err))
+	}
+	if err := s.Prune(ctx); err != nil {
+		s.log.Error("Failed to delete expired signing keys", zap.Error(err))
+	}
+	if err := s.Reload(ctx); err != nil {
+		s.log.Error("Failed to reload signing keys", zap.Error(err))
+	}
+}
@@ -0,0 +1,198 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// The subset of BER (X.690) that LDAP messages use: low tag numbers and
+// definite lengths only, as RFC 4511 section 5.1 requires.
+
+const (
+	classUniversal   = 0x00
+	classApplication = 0x40
+	classContext     = 0x80
+	constructed      = 0x20
+
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x10 | constructed
+	tagSet         = 0x11 | constructed
+)
+
+// maxMessageSize bounds what a server can make the client buffer.
+const maxMessageSize = 1 << 20
+
+var errMalformed = errors.New("ldap: malformed message")
+
+// packet is a decoded BER element. Primitive elements keep their
+// content; constructed ones their children.
+type packet struct {
+	identifier byte
+	content    []byte
+	children   []*packet
+}
+
+func (p *packet) is(identifier byte) bool {
+	return p.identifier == identifier
+}
+
+func (p *packet) str() string {
+	return string(p.content)
+}
+
+func (p *packet) int() (int64, error) {
+	if len(p.content) == 0 || len(p.content) > 8 {
+		return 0, errMalformed
+	}
+	n := int64(int8(p.content[0]))
+	for _, b := range p.content[1:] {
+		n = n<<8 | int64(b)
+	}
+	return n, nil
+}
+
+func encode(identifier byte, content ...[]byte) []byte {
+	size := 0
+	for _, c := range content {
+		size += len(c)
+	}
+
+	out := []byte{identifier}
+	if size < 0x80 {
+		out = append(out, byte(size))
+	} else {
+		var length []byte
+		for n := size; n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		out = append(out, 0x80|byte(len(length)))
+		out = append(out, length...)
+	}
+	for _, c := range content {
+		out = append(out, c...)
+	}
+	return out
+}
+
+func sequence(children ...[]byte) []byte {
+	return encode(tagSequence, children...)
+}
+
+func integer(identifier byte, n int64) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(n)}, content...)
+		// Two's complement in as few bytes as keep the sign.
+		if n >= -0x80 && n < 0x80 {
+			break
+		}
+		n >>= 8
+	}
+	return encode(identifier, content)
+}
+
+func octetString(identifier byte, s string) []byte {
+	return encode(identifier, []byte(s))
+}
+
+func boolean(identifier byte, b bool) []byte {
+	if b {
+		return encode(identifier, []byte{0xff})
+	}
+	return encode(identifier, []byte{0x00})
+}
+
+// readPacket reads one element from r.
+func readPacket(r *bufio.Reader) (*packet, error) {
+	header := make([]byte, 2, 6)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[1]&0x80 != 0 {
+		n := int(header[1] & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errMalformed
+		}
+		header = header[:2+n]
+		if _, err := io.ReadFull(r, header[2:]); err != nil {
+			return nil, err
+		}
+	}
+	length, headerSize, err := decodeLength(header)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxMessageSize {
+		return nil, errors.New("ldap: message too large")
+	}
+
+	data := make([]byte, headerSize+length)
+	copy(data, header)
+	if _, err := io.ReadFull(r, data[headerSize:]); err != nil {
+		return nil, err
+	}
+	p, rest, err := parsePacket(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errMalformed
+	}
+	return p, nil
+}
+
+// parsePacket decodes the element at the start of data and returns what
+// follows it.
+func parsePacket(data []byte) (*packet, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errMalformed
+	}
+	length, headerSize, err := decodeLength(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data)-headerSize < length {
+		return nil, nil, errMalformed
+	}
+
+	p := &packet{identifier: data[0]}
+	content, rest := data[headerSize:headerSize+length], data[headerSize+length:]
+	if p.identifier&constructed == 0 {
+		p.content = content
+		return p, rest, nil
+	}
+	for len(content) > 0 {
+		var child *packet
+		child, content, err = parsePacket(content)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.children = append(p.children, child)
+	}
+	return p, rest, nil
+}
+
+func decodeLength(data []byte) (length, headerSize int, err error) {
+	if data[0]&0x1f == 0x1f {
+		// High tag numbers are not used by LDAP.
+		return 0, 0, errMalformed
+	}
+	if data[1]&0x80 == 0 {
+		return int(data[1]), 2, nil
+	}
+	n := int(data[1] & 0x7f)
+	if n == 0 || n > 4 || len(data) < 2+n {
+		return 0, 0, errMalformed
+	}
+	for _, b := range data[2 : 2+n] {
+		length = length<<8 | int(b)
+	}
+	if length < 0 || length > maxMessageSize {
+		return 0, 0, errMalformed
+	}
+	return length, 2 + n, nil
+}
@@ -0,0 +1,294 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Protocol operations of RFC 4511 section 4.2 onwards.
+const (
+	opBindRequest           = classApplication | constructed | 0
+	opBindResponse          = classApplication | constructed | 1
+	opUnbindRequest         = classApplication | 2
+	opSearchRequest         = classApplication | constructed | 3
+	opSearchResultEntry     = classApplication | constructed | 4
+	opSearchResultDone      = classApplication | constructed | 5
+	opSearchResultReference = classApplication | constructed | 19
+	opExtendedRequest       = classApplication | constructed | 23
+	opExtendedResponse      = classApplication | constructed | 24
+
+	scopeWholeSubtree = 2
+	derefNever        = 0
+
+	oidStartTLS = "1.3.6.1.4.1.1466.20037"
+)
+
+// Result codes of RFC 4511 appendix A.
+const (
+	resultSuccess            = 0
+	resultSizeLimitExceeded  = 4
+	resultInvalidCredentials = 49
+)
+
+// ResultError is a result other than success from the server.
+type ResultError struct {
+	Code    int64
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldap: result code %d", e.Code)
+	}
+	return fmt.Sprintf("ldap: result code %d: %s", e.Code, e.Message)
+}
+
+// entry is a search result. Attribute names are lower-cased.
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+func (e *entry) first(attribute string) string {
+	if values := e.attributes[strings.ToLower(attribute)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// conn is a connection to a directory server. It sends one request at a
+// time.
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	lastID  int64
+}
+
+// dial connects to an ldap:// or ldaps:// URL, upgrading an ldap://
+// connection with StartTLS when startTLS is set.
+func dial(ctx context.Context, rawURL string, startTLS bool, tlsConfig *tls.Config, timeout time.Duration) (*conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		port := "389"
+		if u.Scheme == "ldaps" {
+			port = "636"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		netConn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ldaps":
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("ldap: unsupported URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &conn{netConn: netConn, reader: bufio.NewReader(netConn), timeout: timeout}
+	if startTLS && u.Scheme == "ldap" {
+		if err := c.startTLS(ctx, tlsConfig); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// close tells the server the session is over and closes the connection.
+func (c *conn) close() {
+	_, _ = c.request(context.Background(), encode(opUnbindRequest))
+	c.netConn.Close()
+}
+
+func (c *conn) startTLS(ctx context.Context, tlsConfig *tls.Config) error {
+	response, err := c.roundTrip(ctx, encode(opExtendedRequest, octetString(classContext|0, oidStartTLS)))
+	if err != nil {
+		return err
+	}
+	if err := checkResult(response, opExtendedResponse); err != nil {
+		return fmt.Errorf("ldap: StartTLS: %w", err)
+	}
+
+	tlsConn := tls.Client(c.netConn, tlsConfig)
+	c.setDeadline(ctx)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	c.netConn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// bind authenticates the connection with a simple bind. A wrong password
+// or unknown DN comes back as a ResultError with code 49.
+func (c *conn) bind(ctx context.Context, dn, password string) error {
+	response, err := c.roundTrip(ctx, encode(opBindRequest,
+		integer(tagInteger, 3),
+		octetString(tagOctetString, dn),
+		octetString(classContext|0, password),
+	))
+	if err != nil {
+		return err
+	}
+	return checkResult(response, opBindResponse)
+}
+
+// search returns up to sizeLimit entries matching filter under baseDN, with
+// the given attributes. Referrals are not followed.
+func (c *conn) search(ctx context.Context, baseDN, filter string, attributes []string, sizeLimit int64) ([]*entry, error) {
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	requested := make([][]byte, len(attributes))
+	for i, attribute := range attributes {
+		requested[i] = octetString(tagOctetString, attribute)
+	}
+
+	id, err := c.request(ctx, encode(opSearchRequest,
+		octetString(tagOctetString, baseDN),
+		integer(tagEnumerated, scopeWholeSubtree),
+		integer(tagEnumerated, derefNever),
+		integer(tagInteger, sizeLimit),
+		integer(tagInteger, int64(c.timeout/time.Second)),
+		boolean(tagBoolean, false),
+		compiled,
+		sequence(requested...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*entry
+	for {
+		response, err := c.response(id)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case response.is(opSearchResultEntry):
+			e, err := parseEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case response.is(opSearchResultReference):
+		case response.is(opSearchResultDone):
+			err := checkResult(response, opSearchResultDone)
+			var result *ResultError
+			if errors.As(err, &result) && result.Code == resultSizeLimitExceeded {
+				err = nil
+			}
+			return entries, err
+		default:
+			return nil, errMalformed
+		}
+	}
+}
+
+func (c *conn) roundTrip(ctx context.Context, op []byte) (*packet, error) {
+	id, err := c.request(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	return c.response(id)
+}
+
+func (c *conn) request(ctx context.Context, op []byte) (int64, error) {
+	c.lastID++
+	return c.lastID, c.send(ctx, op)
+}
+
+func (c *conn) send(ctx context.Context, op []byte) error {
+	c.setDeadline(ctx)
+	_, err := c.netConn.Write(sequence(integer(tagInteger, c.lastID), op))
+	return err
+}
+
+// response reads the next message, which must answer request id, and
+// returns its protocol operation.
+func (c *conn) response(id int64) (*packet, error) {
+	message, err := readPacket(c.reader)
+	if err != nil {
+		return nil, err
+	}
+	if !message.is(tagSequence) || len(message.children) < 2 {
+		return nil, errMalformed
+	}
+	messageID, err := message.children[0].int()
+	if err != nil {
+		return nil, err
+	}
+	if messageID == 0 {
+		// An unsolicited notification, which only ever announces that
+		// the server is closing the connection.
+		return nil, errors.New("ldap: server ended the session")
+	}
+	if messageID != id {
+		return nil, errMalformed
+	}
+	return message.children[1], nil
+}
+
+// setDeadline bounds the next exchange by the timeout and ctx.
+func (c *conn) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = c.netConn.SetDeadline(deadline)
+}
+
+// checkResult reads the LDAPResult that starts a response.
+func checkResult(response *packet, op byte) error {
+	if !response.is(op) || len(response.children) < 3 {
+		return errMalformed
+	}
+	code, err := response.children[0].int()
+	if err != nil {
+		return err
+	}
+	if code != resultSuccess {
+		return &ResultError{Code: code, Message: response.children[2].str()}
+	}
+	return nil
+}
+
+func parseEntry(response *packet) (*entry, error) {
+	if len(response.children) != 2 {
+		return nil, errMalformed
+	}
+	e := &entry{dn: response.children[0].str(), attributes: make(map[string][]string)}
+	for _, attribute := range response.children[1].children {
+		if len(attribute.children) != 2 {
+			return nil, errMalformed
+		}
+		name := strings.ToLower(attribute.children[0].str())
+		for _, value := range attribute.children[1].children {
+			e.attributes[name] = append(e.attributes[name], value.str())
+		}
+	}
+	return e, nil
+}
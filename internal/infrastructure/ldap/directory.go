@@ -0,0 +1,217 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+// ProviderID names the directory as the provider of the identities that
+// link shadow users to their directory accounts.
+const ProviderID = "ldap"
+
+// UsernamePlaceholder stands for the escaped username in UserFilter and
+// UserDNTemplate.
+const UsernamePlaceholder = "{username}"
+
+const (
+	maxUsernameLength = 256
+	maxSubjectLength  = 255
+)
+
+// Config describes a directory and how accounts are found in it.
+type Config struct {
+	// URL is an ldap:// or ldaps:// URL; StartTLS upgrades an ldap://
+	// connection before anything is sent. RootCAs are the certificates
+	// the server's is verified against, the system's when nil.
+	URL      string
+	StartTLS bool
+	RootCAs  *x509.CertPool
+
+	// With a BindDN, accounts are found by searching as that service
+	// account and then verified by binding as the entry found. Without
+	// one, the user binds with the DN made from UserDNTemplate (which for
+	// Active Directory may be a user principal name such as
+	// "{username}@corp.example.com") and then reads their own entry.
+	BindDN         string
+	BindPassword   string
+	UserDNTemplate string
+
+	// BaseDN is searched with UserFilter for the user's entry.
+	BaseDN     string
+	UserFilter string
+
+	// IDAttribute holds the entry's stable identifier, such as entryUUID
+	// or Active Directory's objectGUID; binary values are hex encoded.
+	IDAttribute       string
+	UsernameAttribute string
+	EmailAttribute    string
+	// GroupAttribute lists the groups the entry is a member of by DN, and
+	// GroupRoles maps those DNs to roles.
+	GroupAttribute string
+	GroupRoles     map[string][]string
+
+	LinkByEmail bool
+	Timeout     time.Duration
+}
+
+// Directory verifies passwords against an LDAP directory such as Active
+// Directory, connecting afresh for each verification.
+type Directory struct {
+	cfg        Config
+	tlsConfig  *tls.Config
+	groupRoles map[string][]string
+}
+
+func NewDirectory(cfg Config) *Directory {
+	groupRoles := make(map[string][]string, len(cfg.GroupRoles))
+	for dn, roles := range cfg.GroupRoles {
+		groupRoles[normalizeDN(dn)] = append(groupRoles[normalizeDN(dn)], roles...)
+	}
+
+	return &Directory{
+		cfg:        cfg,
+		tlsConfig:  &tls.Config{RootCAs: cfg.RootCAs, MinVersion: tls.VersionTLS12},
+		groupRoles: groupRoles,
+	}
+}
+
+func (d *Directory) ID() string {
+	return ProviderID
+}
+
+func (d *Directory) LinksByEmail() bool {
+	return d.cfg.LinkByEmail
+}
+
+// Verify binds as the account the username names to check the password.
+// An unknown username, a wrong password and an account the filter leaves
+// out are all rejected alike.
+func (d *Directory) Verify(ctx context.Context, username, password string) (*port.DirectoryAccount, error) {
+	// A simple bind with an empty password is an unauthenticated bind,
+	// which servers accept without checking anything (RFC 4513 section
+	// 5.1.2).
+	if password == "" || !validUsername(username) {
+		return nil, nil
+	}
+
+	c, err := dial(ctx, d.cfg.URL, d.cfg.StartTLS, d.tlsConfig, d.cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	var found *entry
+	if d.cfg.BindDN != "" {
+		if err := c.bind(ctx, d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldap: service account bind: %w", err)
+		}
+		found, err = d.findUser(ctx, c, username)
+		if err != nil || found == nil {
+			return nil, err
+		}
+		if err := c.bind(ctx, found.dn, password); err != nil {
+			return nil, rejected(err)
+		}
+	} else {
+		dn := strings.ReplaceAll(d.cfg.UserDNTemplate, UsernamePlaceholder, escapeDNValue(username))
+		if err := c.bind(ctx, dn, password); err != nil {
+			return nil, rejected(err)
+		}
+		found, err = d.findUser(ctx, c, username)
+		if err != nil || found == nil {
+			return nil, err
+		}
+	}
+
+	return d.account(found, username)
+}
+
+// findUser returns the single entry the filter matches for username, or
+// nil when there is none.
+func (d *Directory) findUser(ctx context.Context, c *conn, username string) (*entry, error) {
+	filter := strings.ReplaceAll(d.cfg.UserFilter, UsernamePlaceholder, escapeFilterValue(username))
+	attributes := []string{d.cfg.IDAttribute, d.cfg.UsernameAttribute, d.cfg.EmailAttribute, d.cfg.GroupAttribute}
+
+	entries, err := c.search(ctx, d.cfg.BaseDN, filter, attributes, 2)
+	if err != nil {
+		return nil, err
+	}
+	switch len(entries) {
+	case 0:
+		return nil, nil
+	case 1:
+		return entries[0], nil
+	default:
+		return nil, fmt.Errorf("ldap: more than one entry matches %s", filter)
+	}
+}
+
+func (d *Directory) account(found *entry, username string) (*port.DirectoryAccount, error) {
+	subject := printable(found.first(d.cfg.IDAttribute))
+	if subject == "" || len(subject) > maxSubjectLength {
+		return nil, fmt.Errorf("ldap: %s has no usable %s", found.dn, d.cfg.IDAttribute)
+	}
+
+	account := &port.DirectoryAccount{
+		Subject:  subject,
+		Username: found.first(d.cfg.UsernameAttribute),
+		Email:    found.first(d.cfg.EmailAttribute),
+	}
+	if account.Username == "" {
+		account.Username = username
+	}
+	for _, group := range found.attributes[strings.ToLower(d.cfg.GroupAttribute)] {
+		account.Roles = append(account.Roles, d.groupRoles[normalizeDN(group)]...)
+	}
+	slices.Sort(account.Roles)
+	account.Roles = slices.Compact(account.Roles)
+	return account, nil
+}
+
+// rejected turns a failed bind for wrong credentials into a rejection,
+// and passes on any other error.
+func rejected(err error) error {
+	var result *ResultError
+	if errors.As(err, &result) && result.Code == resultInvalidCredentials {
+		return nil
+	}
+	return err
+}
+
+func validUsername(username string) bool {
+	if username == "" || len(username) > maxUsernameLength || !utf8.ValidString(username) {
+		return false
+	}
+	return !strings.ContainsFunc(username, unicode.IsControl)
+}
+
+// printable returns text identifiers as they are and hex encodes binary
+// ones such as GUIDs.
+func printable(value string) string {
+	if utf8.ValidString(value) && !strings.ContainsFunc(value, unicode.IsControl) {
+		return value
+	}
+	return hex.EncodeToString([]byte(value))
+}
+
+// normalizeDN makes DNs that differ only in case or in spaces around
+// their separators compare equal.
+func normalizeDN(dn string) string {
+	rdns := strings.Split(strings.ToLower(dn), ",")
+	for i, rdn := range rdns {
+		attribute, value, _ := strings.Cut(rdn, "=")
+		rdns[i] = strings.TrimSpace(attribute) + "=" + strings.TrimSpace(value)
+	}
+	return strings.Join(rdns, ",")
+}
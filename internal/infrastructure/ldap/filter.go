@@ -0,0 +1,246 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Filter choices of RFC 4511 section 4.5.1.
+const (
+	filterAnd            = classContext | constructed | 0
+	filterOr             = classContext | constructed | 1
+	filterNot            = classContext | constructed | 2
+	filterEquality       = classContext | constructed | 3
+	filterSubstrings     = classContext | constructed | 4
+	filterGreaterOrEqual = classContext | constructed | 5
+	filterLessOrEqual    = classContext | constructed | 6
+	filterPresent        = classContext | 7
+	filterApprox         = classContext | constructed | 8
+
+	substringInitial = classContext | 0
+	substringAny     = classContext | 1
+	substringFinal   = classContext | 2
+)
+
+// compileFilter encodes a string filter of RFC 4515 for a search request.
+// Extensible matches are not supported.
+func compileFilter(filter string) ([]byte, error) {
+	p := &filterParser{s: filter}
+	encoded, err := p.filter()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return encoded, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+func (p *filterParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("ldap: filter %q at %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) filter() ([]byte, error) {
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return nil, p.errorf("expected (")
+	}
+	p.pos++
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unterminated filter")
+	}
+
+	var encoded []byte
+	var err error
+	switch p.s[p.pos] {
+	case '&', '|':
+		identifier := byte(filterAnd)
+		if p.s[p.pos] == '|' {
+			identifier = filterOr
+		}
+		p.pos++
+		var children [][]byte
+		for p.pos < len(p.s) && p.s[p.pos] == '(' {
+			child, err := p.filter()
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		encoded = encode(identifier, children...)
+	case '!':
+		p.pos++
+		child, err := p.filter()
+		if err != nil {
+			return nil, err
+		}
+		encoded = encode(filterNot, child)
+	default:
+		encoded, err = p.item()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+		return nil, p.errorf("expected )")
+	}
+	p.pos++
+	return encoded, nil
+}
+
+func (p *filterParser) item() ([]byte, error) {
+	start := p.pos
+	for p.pos < len(p.s) && isAttributeChar(p.s[p.pos]) {
+		p.pos++
+	}
+	attribute := p.s[start:p.pos]
+	if attribute == "" {
+		return nil, p.errorf("expected an attribute")
+	}
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unterminated filter")
+	}
+
+	identifier := byte(filterEquality)
+	switch p.s[p.pos] {
+	case '=':
+	case '>':
+		identifier = filterGreaterOrEqual
+	case '<':
+		identifier = filterLessOrEqual
+	case '~':
+		identifier = filterApprox
+	case ':':
+		return nil, p.errorf("extensible matches are not supported")
+	default:
+		return nil, p.errorf("expected a comparison")
+	}
+	if identifier != filterEquality {
+		p.pos++
+		if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+			return nil, p.errorf("expected =")
+		}
+	}
+	p.pos++
+
+	start = p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != ')' {
+		if p.s[p.pos] == '(' {
+			return nil, p.errorf("unescaped (")
+		}
+		p.pos++
+	}
+	raw := p.s[start:p.pos]
+
+	if identifier != filterEquality || !strings.Contains(raw, "*") {
+		value, err := unescapeFilterValue(raw)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return encode(identifier, octetString(tagOctetString, attribute), octetString(tagOctetString, value)), nil
+	}
+	if raw == "*" {
+		return octetString(filterPresent, attribute), nil
+	}
+
+	parts := strings.Split(raw, "*")
+	var substrings [][]byte
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, err := unescapeFilterValue(part)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		identifier := byte(substringAny)
+		switch i {
+		case 0:
+			identifier = substringInitial
+		case len(parts) - 1:
+			identifier = substringFinal
+		}
+		substrings = append(substrings, octetString(identifier, value))
+	}
+	if len(substrings) == 0 {
+		return nil, p.errorf("empty substring filter")
+	}
+	return encode(filterSubstrings, octetString(tagOctetString, attribute), sequence(substrings...)), nil
+}
+
+func isAttributeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == ';'
+}
+
+func unescapeFilterValue(raw string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			continue
+		}
+		if i+2 >= len(raw) || !isHex(raw[i+1]) || !isHex(raw[i+2]) {
+			return "", errors.New(`\ must start a two digit hex escape`)
+		}
+		b.WriteByte(unhex(raw[i+1])<<4 | unhex(raw[i+2]))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// escapeFilterValue escapes a value for a string filter, so that a
+// username cannot add wildcards or clauses to it.
+func escapeFilterValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&b, `\%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// escapeDNValue escapes a value for an attribute value of a distinguished
+// name, as RFC 4514 section 2.4 requires.
+func escapeDNValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == 0:
+			b.WriteString(`\00`)
+		case strings.IndexByte(`"+,;<>\`, c) >= 0,
+			i == 0 && (c == ' ' || c == '#'),
+			i == len(value)-1 && c == ' ':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+// LogMailer writes outgoing mail to the application log instead of
+// delivering it. Intended for local development only.
+type LogMailer struct {
+	log *logger.Logger
+}
+
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{log: log}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg port.MailMessage) error {
+	m.log.InfoCtx(ctx, "Mail sent",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.TextBody),
+	)
+	return nil
+}
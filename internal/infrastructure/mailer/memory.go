@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+// MemoryMailer keeps the most recent messages in memory instead of
+// delivering them. Once capacity is reached the oldest message is dropped.
+type MemoryMailer struct {
+	mu       sync.RWMutex
+	capacity int
+	messages []port.SentMail
+}
+
+func NewMemoryMailer(capacity int) *MemoryMailer {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &MemoryMailer{capacity: capacity}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg port.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.messages) >= m.capacity {
+		m.messages = m.messages[1:]
+	}
+
+	m.messages = append(m.messages, port.SentMail{
+		ID:          uuid.New().String(),
+		SentAt:      time.Now().UTC(),
+		MailMessage: msg,
+	})
+
+	return nil
+}
+
+// List returns captured messages, newest first.
+func (m *MemoryMailer) List() []port.SentMail {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]port.SentMail, len(m.messages))
+	for i, msg := range m.messages {
+		out[len(m.messages)-1-i] = msg
+	}
+	return out
+}
+
+func (m *MemoryMailer) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
@@ -0,0 +1,36 @@
+package otp
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// userCodeAlphabet has no vowels, so codes cannot spell words, and no
+// characters that are easily confused, following RFC 8628 section 6.1.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+const userCodeLength = 8
+
+// UserCodeGenerator creates the codes a user types to approve a device,
+// such as "WDJB-MJHT": eight letters, about 34 bits, shown in two groups.
+type UserCodeGenerator struct{}
+
+func NewUserCodeGenerator() *UserCodeGenerator {
+	return &UserCodeGenerator{}
+}
+
+func (g *UserCodeGenerator) Generate() (string, error) {
+	code := make([]byte, 0, userCodeLength+1)
+	size := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < userCodeLength; i++ {
+		if i == userCodeLength/2 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, userCodeAlphabet[n.Int64()])
+	}
+	return string(code), nil
+}
@@ -0,0 +1,213 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+// Dispatcher polls the outbox and hands due events to their subscriber.
+// Delivery is at-least-once: an event is marked delivered only after its
+// subscriber returns, and a lease that expires mid-delivery makes the event
+// claimable again. The outcome of a delivery whose lease was claimed again
+// is dropped; the newer claim records its own.
+type Dispatcher struct {
+	repo   repository.OutboxRepository
+	router *Router
+	log    *logger.Logger
+	config *config.OutboxConfig
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewDispatcher(repo repository.OutboxRepository, router *Router, log *logger.Logger, cfg *config.OutboxConfig) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		router: router,
+		log:    log,
+		config: cfg,
+		stopCh: make(chan struct{}),
+	}
+}
+
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go d.run()
+
+	d.log.Info("Outbox dispatcher started",
+		zap.Duration("poll_interval", d.config.PollInterval),
+		zap.Int("batch_size", d.config.BatchSize),
+		zap.Int("max_attempts", d.config.MaxAttempts),
+	)
+}
+
+// Stop waits for the batch in flight to finish. Events it did not reach
+// stay pending and are picked up on the next start.
+func (d *Dispatcher) Stop() {
+	d.once.Do(func() {
+		close(d.stopCh)
+	})
+	d.wg.Wait()
+	d.log.Info("Outbox dispatcher stopped")
+}
+
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Keep draining while batches come back full instead of
+			// waiting a full interval between them.
+			for {
+				n, err := d.DispatchBatch(context.Background())
+				if err != nil {
+					d.log.Error("Failed to dispatch outbox batch", zap.Error(err))
+				}
+				if err != nil || n < d.config.BatchSize || d.stopping() {
+					break
+				}
+			}
+		case <-d.stopCh:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) stopping() bool {
+	select {
+	case <-d.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// DispatchBatch claims and delivers one batch of due events and returns
+// how many were claimed.
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	events, err := d.repo.Claim(ctx, d.config.BatchSize, time.Now().UTC(), d.config.Lease, d.config.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		d.deliver(ctx, event)
+	}
+
+	return len(events), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *entity.OutboxEvent) {
+	err := d.handle(ctx, event)
+	now := time.Now().UTC()
+
+	lockedUntil := *event.LockedUntil
+
+	if err == nil {
+		held, err := d.repo.MarkDelivered(ctx, event.ID, lockedUntil, now)
+		if err != nil {
+			d.log.Error("Failed to mark outbox event delivered", zap.String("outbox_id", event.ID), zap.Error(err))
+		} else if !held {
+			d.leaseLost(event)
+		}
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("outbox_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.String("subscriber", event.Subscriber),
+		zap.Int("attempt", event.Attempts),
+		zap.Error(err),
+	}
+
+	if event.Attempts >= d.config.MaxAttempts {
+		d.log.Error("Outbox event exhausted retries", fields...)
+		held, err := d.repo.MarkDead(ctx, event.ID, lockedUntil, now, err.Error())
+		if err != nil {
+			d.log.Error("Failed to mark outbox event dead", zap.String("outbox_id", event.ID), zap.Error(err))
+		} else if !held {
+			d.leaseLost(event)
+		}
+		return
+	}
+
+	delay := withJitter(Backoff(event.Attempts, d.config.BackoffBase, d.config.BackoffMax))
+	d.log.Warn("Outbox delivery failed, retrying", append(fields, zap.Duration("retry_in", delay))...)
+	held, err := d.repo.MarkRetry(ctx, event.ID, lockedUntil, now.Add(delay), err.Error())
+	if err != nil {
+		d.log.Error("Failed to reschedule outbox event", zap.String("outbox_id", event.ID), zap.Error(err))
+	} else if !held {
+		d.leaseLost(event)
+	}
+}
+
+func (d *Dispatcher) leaseLost(event *entity.OutboxEvent) {
+	d.log.Warn("Outbox lease expired during delivery, leaving the event to its newer claim",
+		zap.String("outbox_id", event.ID),
+		zap.String("subscriber", event.Subscriber),
+		zap.Int("attempt", event.Attempts),
+	)
+}
+
+func (d *Dispatcher) handle(ctx context.Context, event *entity.OutboxEvent) (err error) {
+	subscriber, ok := d.router.Subscriber(event.Subscriber)
+	if !ok {
+		return fmt.Errorf("no outbox subscriber named %q", event.Subscriber)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.HandlerTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("outbox subscriber panicked: %v", r)
+		}
+	}()
+
+	return subscriber.Handle(ctx, port.OutboxDelivery{
+		ID:        event.ID,
+		EventType: event.EventType,
+		DedupKey:  event.DedupKey,
+		Payload:   event.Payload,
+		Attempt:   event.Attempts,
+	})
+}
+
+// Backoff returns the delay before retrying after the given attempt:
+// base doubled per previous attempt, capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return min(delay, max)
+}
+
+// withJitter spreads retries over [delay/2, delay) so events that failed
+// together do not all retry together.
+func withJitter(delay time.Duration) time.Duration {
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half)
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+var ErrDedupKeyRequired = errors.New("outbox message dedup key is required")
+
+// Publisher writes one outbox row per subscriber of the message's event
+// type, so each subscriber is retried on its own schedule.
+type Publisher struct {
+	repo          repository.OutboxRepository
+	router        *Router
+	uuidGenerator port.UUIDGenerator
+}
+
+func NewPublisher(repo repository.OutboxRepository, router *Router, uuidGenerator port.UUIDGenerator) *Publisher {
+	return &Publisher{
+		repo:          repo,
+		router:        router,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msg port.OutboxMessage) error {
+	if msg.DedupKey == "" {
+		return ErrDedupKeyRequired
+	}
+
+	subscribers := p.router.Subscribers(msg.EventType)
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, subscriber := range subscribers {
+		event := entity.NewOutboxEvent(
+			p.uuidGenerator.Generate(),
+			msg.EventType,
+			subscriber,
+			msg.DedupKey+":"+subscriber,
+			payload,
+		)
+		if err := p.repo.Create(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
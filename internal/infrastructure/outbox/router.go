@@ -0,0 +1,43 @@
+package outbox
+
+import (
+	"fmt"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+// Router maps event types to the subscribers that receive them. It is
+// populated at startup and read-only afterwards.
+type Router struct {
+	subscribers map[string]port.OutboxSubscriber
+	routes      map[string][]string
+}
+
+func NewRouter() *Router {
+	return &Router{
+		subscribers: make(map[string]port.OutboxSubscriber),
+		routes:      make(map[string][]string),
+	}
+}
+
+func (r *Router) Subscribe(subscriber port.OutboxSubscriber, eventTypes ...string) error {
+	name := subscriber.Name()
+	if _, exists := r.subscribers[name]; exists {
+		return fmt.Errorf("outbox subscriber %q already registered", name)
+	}
+
+	r.subscribers[name] = subscriber
+	for _, eventType := range eventTypes {
+		r.routes[eventType] = append(r.routes[eventType], name)
+	}
+	return nil
+}
+
+func (r *Router) Subscribers(eventType string) []string {
+	return r.routes[eventType]
+}
+
+func (r *Router) Subscriber(name string) (port.OutboxSubscriber, bool) {
+	subscriber, ok := r.subscribers[name]
+	return subscriber, ok
+}
@@ -0,0 +1,194 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	hashPrefixLength = 5
+	// maxLineLength comfortably fits "<40 hex chars>:<count>\r\n".
+	maxLineLength = 128
+)
+
+// BreachChecker looks passwords up in a local copy of the Have I Been
+// Pwned SHA-1 corpus, so no password or hash leaves the host. Like the
+// online range API it only reads entries sharing the hash's 5-character
+// prefix.
+//
+// Two layouts are supported:
+//   - a directory of range files named "<PREFIX>.txt" containing
+//     "<SUFFIX>:<COUNT>" lines, as produced by the HIBP downloader;
+//   - a single file of "<HASH>:<COUNT>" lines sorted by hash, which is
+//     binary searched for the prefix.
+type BreachChecker struct {
+	path     string
+	dir      bool
+	minCount int
+}
+
+func NewBreachChecker(path string, minCount int) (*BreachChecker, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BreachChecker{
+		path:     path,
+		dir:      info.IsDir(),
+		minCount: max(minCount, 1),
+	}, nil
+}
+
+func (c *BreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:hashPrefixLength], hash[hashPrefixLength:]
+
+	var (
+		count int
+		err   error
+	)
+	if c.dir {
+		count, err = c.lookupRangeFile(prefix, suffix)
+	} else {
+		count, err = c.lookupSortedFile(prefix, suffix)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return count >= c.minCount, nil
+}
+
+func (c *BreachChecker) lookupRangeFile(prefix, suffix string) (int, error) {
+	file, err := os.Open(filepath.Join(c.path, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if count, ok := matchEntry(scanner.Text(), suffix); ok {
+			return count, nil
+		}
+	}
+	return 0, scanner.Err()
+}
+
+func (c *BreachChecker) lookupSortedFile(prefix, suffix string) (int, error) {
+	file, err := os.Open(c.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// Find the first line whose prefix is not less than ours. lineAt is
+	// monotonic in the offset, so a binary search over offsets works
+	// without an index.
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		_, line, err := lineAt(file, mid)
+		if err != nil {
+			return 0, err
+		}
+		if line == "" || linePrefix(line) >= prefix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	start, _, err := lineAt(file, lo)
+	if err != nil {
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(io.NewSectionReader(file, start, info.Size()-start))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if linePrefix(line) != prefix {
+			break
+		}
+		if count, ok := matchEntry(line[hashPrefixLength:], suffix); ok {
+			return count, nil
+		}
+	}
+	return 0, scanner.Err()
+}
+
+// lineAt returns the first complete line starting at or after offset,
+// with its start offset. It returns an empty line at end of file.
+func lineAt(file *os.File, offset int64) (int64, string, error) {
+	start := offset
+	if offset > 0 {
+		// Skip the rest of the line offset points into, unless offset is
+		// already at the start of one.
+		buf := make([]byte, maxLineLength)
+		n, err := file.ReadAt(buf, offset-1)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, "", err
+		}
+		i := bytes.IndexByte(buf[:n], '\n')
+		if i < 0 {
+			return offset + int64(n), "", nil
+		}
+		start = offset - 1 + int64(i) + 1
+	}
+
+	buf := make([]byte, maxLineLength)
+	n, err := file.ReadAt(buf, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	return start, strings.TrimRight(string(line), "\r"), nil
+}
+
+func linePrefix(line string) string {
+	if len(line) < hashPrefixLength {
+		return line
+	}
+	return strings.ToUpper(line[:hashPrefixLength])
+}
+
+// matchEntry parses a "<SUFFIX>:<COUNT>" entry and reports its count when
+// the suffix matches.
+func matchEntry(entry, suffix string) (int, bool) {
+	hashPart, countPart, found := strings.Cut(strings.TrimSpace(entry), ":")
+	if !strings.EqualFold(hashPart, suffix) {
+		return 0, false
+	}
+	if !found {
+		return 1, true
+	}
+
+	count, err := strconv.Atoi(countPart)
+	if err != nil {
+		return 1, true
+	}
+	return count, true
+}
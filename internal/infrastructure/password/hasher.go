@@ -0,0 +1,59 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+)
+
+var (
+	ErrInvalidHash         = errors.New("password hash is not in a supported format")
+	ErrIncompatibleVersion = errors.New("password hash uses an incompatible argon2 version")
+)
+
+// Hasher hashes new passwords with the configured algorithm and verifies
+// existing hashes with whichever algorithm produced them.
+type Hasher struct {
+	primary port.PasswordHasher
+	argon2  *Argon2idHasher
+	bcrypt  *BcryptHasher
+}
+
+func NewHasher(cfg *config.PasswordConfig) *Hasher {
+	h := &Hasher{
+		argon2: NewArgon2idHasher(Argon2Params{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+			SaltLength:  cfg.Argon2SaltLength,
+			KeyLength:   cfg.Argon2KeyLength,
+		}),
+		bcrypt: NewBcryptHasher(cfg.BcryptCost),
+	}
+
+	switch cfg.Algorithm {
+	case config.PasswordAlgorithmBcrypt:
+		h.primary = h.bcrypt
+	default:
+		h.primary = h.argon2
+	}
+
+	return h
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+func (h *Hasher) Verify(password, encodedHash string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, argon2idPrefix):
+		return h.argon2.Verify(password, encodedHash)
+	case isBcryptHash(encodedHash):
+		return h.bcrypt.Verify(password, encodedHash)
+	default:
+		return false, ErrInvalidHash
+	}
+}
@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"database/sql/driver"
+
+	"github.com/lib/pq"
+)
+
+// textArray binds values to a NOT NULL TEXT[] column. pq sends a nil
+// slice as NULL, so it is replaced with an empty one.
+func textArray(values []string) driver.Valuer {
+	if values == nil {
+		values = []string{}
+	}
+	return pq.StringArray(values)
+}
@@ -20,9 +20,10 @@ func (r *AuditRepo) Create(ctx context.Context, log *entity.AuditLog) error {
 	query := `
 		INSERT INTO audit_logs (id, timestamp, user_id, action, details, ip_address, correlation_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
 		log.ID,
 		log.Timestamp,
 		log.UserID,
@@ -82,7 +83,7 @@ func (r *AuditRepo) FindByUserID(ctx context.Context, userID string, limit, offs
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +100,7 @@ func (r *AuditRepo) FindByCorrelationID(ctx context.Context, correlationID strin
 		ORDER BY timestamp DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, correlationID)
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, correlationID)
 	if err != nil {
 		return nil, err
 	}
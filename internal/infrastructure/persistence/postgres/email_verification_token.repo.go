@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type EmailVerificationTokenRepo struct {
+	db *DB
+}
+
+func NewEmailVerificationTokenRepo(db *DB) repository.EmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepo{db: db}
+}
+
+func (r *EmailVerificationTokenRepo) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *EmailVerificationTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM email_verification_tokens WHERE token_hash = $1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash)
+	return scanEmailVerificationToken(row)
+}
+
+func (r *EmailVerificationTokenRepo) FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM email_verification_tokens WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, userID)
+	return scanEmailVerificationToken(row)
+}
+
+func (r *EmailVerificationTokenRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *EmailVerificationTokenRepo) InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = $2
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userID, usedAt)
+	return err
+}
+
+func scanEmailVerificationToken(row *sql.Row) (*entity.EmailVerificationToken, error) {
+	var token entity.EmailVerificationToken
+	var usedAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return &token, nil
+}
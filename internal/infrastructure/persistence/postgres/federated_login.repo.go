@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type FederatedLoginRepo struct {
+	db *DB
+}
+
+func NewFederatedLoginRepo(db *DB) repository.FederatedLoginRepository {
+	return &FederatedLoginRepo{db: db}
+}
+
+func (r *FederatedLoginRepo) Create(ctx context.Context, login *entity.FederatedLogin) error {
+	query := `
+		INSERT INTO federated_logins (id, provider, state_hash, device_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		login.ID,
+		login.Provider,
+		login.StateHash,
+		login.DeviceHash,
+		login.Nonce,
+		login.CodeVerifier,
+		login.ExpiresAt,
+		login.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *FederatedLoginRepo) FindByStateHash(ctx context.Context, stateHash string) (*entity.FederatedLogin, error) {
+	query := `
+		SELECT id, provider, state_hash, device_hash, nonce, code_verifier, expires_at, used_at, created_at
+		FROM federated_logins WHERE state_hash = $1
+	`
+
+	var login entity.FederatedLogin
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, stateHash).Scan(
+		&login.ID,
+		&login.Provider,
+		&login.StateHash,
+		&login.DeviceHash,
+		&login.Nonce,
+		&login.CodeVerifier,
+		&login.ExpiresAt,
+		&usedAt,
+		&login.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		login.UsedAt = &usedAt.Time
+	}
+
+	return &login, nil
+}
+
+func (r *FederatedLoginRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE federated_logins
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type IdentityRepo struct {
+	db *DB
+}
+
+func NewIdentityRepo(db *DB) repository.IdentityRepository {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) Create(ctx context.Context, identity *entity.Identity) error {
+	query := `
+		INSERT INTO identities (id, user_id, provider, subject, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+		identity.LastLoginAt,
+	)
+
+	return err
+}
+
+func (r *IdentityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM identities WHERE provider = $1 AND subject = $2
+	`
+
+	var identity entity.Identity
+	var lastLoginAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&lastLoginAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if lastLoginAt.Valid {
+		identity.LastLoginAt = &lastLoginAt.Time
+	}
+
+	return &identity, nil
+}
+
+func (r *IdentityRepo) ExistsForUser(ctx context.Context, userID, provider string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM identities WHERE user_id = $1 AND provider = $2)`
+
+	var exists bool
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID, provider).Scan(&exists)
+	return exists, err
+}
+
+func (r *IdentityRepo) RecordLogin(ctx context.Context, id, email string, at time.Time) error {
+	query := `
+		UPDATE identities
+		SET email = $2, last_login_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, id, email, at)
+	return err
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type MFAChallengeRepo struct {
+	db *DB
+}
+
+func NewMFAChallengeRepo(db *DB) repository.MFAChallengeRepository {
+	return &MFAChallengeRepo{db: db}
+}
+
+func (r *MFAChallengeRepo) Create(ctx context.Context, challenge *entity.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		challenge.ID,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.Attempts,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *MFAChallengeRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, attempts, expires_at, used_at, created_at
+		FROM mfa_challenges WHERE token_hash = $1
+	`
+
+	var challenge entity.MFAChallenge
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&usedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+
+	return &challenge, nil
+}
+
+func (r *MFAChallengeRepo) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	query := `
+		UPDATE mfa_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+		RETURNING attempts
+	`
+
+	var attempts int
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, id).Scan(&attempts)
+	return attempts, err
+}
+
+func (r *MFAChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE mfa_challenges
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type MFARecoveryCodeRepo struct {
+	db *DB
+}
+
+func NewMFARecoveryCodeRepo(db *DB) repository.MFARecoveryCodeRepository {
+	return &MFARecoveryCodeRepo{db: db}
+}
+
+func (r *MFARecoveryCodeRepo) ReplaceForUser(ctx context.Context, userID string, codes []*entity.MFARecoveryCode) error {
+	if err := r.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	for _, code := range codes {
+		_, err := r.db.conn(ctx).ExecContext(ctx, query,
+			code.ID,
+			code.UserID,
+			code.CodeHash,
+			code.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MFARecoveryCodeRepo) MarkUsed(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, userID, codeHash, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *MFARecoveryCodeRepo) CountUnused(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *MFARecoveryCodeRepo) DeleteByUserID(ctx context.Context, userID string) error {
+	query := `DELETE FROM mfa_recovery_codes WHERE user_id = $1`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userID)
+	return err
+}
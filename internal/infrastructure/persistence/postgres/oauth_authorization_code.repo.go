@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type AuthorizationCodeRepo struct {
+	db *DB
+}
+
+func NewAuthorizationCodeRepo(db *DB) repository.AuthorizationCodeRepository {
+	return &AuthorizationCodeRepo{db: db}
+}
+
+func (r *AuthorizationCodeRepo) Create(ctx context.Context, code *entity.AuthorizationCode) error {
+	query := `
+		INSERT INTO oauth_authorization_codes (
+			id, code_hash, client_id, user_id, redirect_uri, scopes, nonce,
+			code_challenge, family_id, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		code.ID,
+		code.CodeHash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		textArray(code.Scopes),
+		code.Nonce,
+		code.CodeChallenge,
+		code.FamilyID,
+		code.ExpiresAt,
+		code.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *AuthorizationCodeRepo) FindByHash(ctx context.Context, codeHash string) (*entity.AuthorizationCode, error) {
+	query := `
+		SELECT id, code_hash, client_id, user_id, redirect_uri, scopes, nonce,
+			code_challenge, family_id, expires_at, used_at, created_at
+		FROM oauth_authorization_codes WHERE code_hash = $1
+	`
+
+	var code entity.AuthorizationCode
+	var scopes pq.StringArray
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, codeHash).Scan(
+		&code.ID,
+		&code.CodeHash,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		&scopes,
+		&code.Nonce,
+		&code.CodeChallenge,
+		&code.FamilyID,
+		&code.ExpiresAt,
+		&usedAt,
+		&code.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	code.Scopes = scopes
+	if usedAt.Valid {
+		code.UsedAt = &usedAt.Time
+	}
+
+	return &code, nil
+}
+
+func (r *AuthorizationCodeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE oauth_authorization_codes
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type ClientAssertionRepo struct {
+	db *DB
+}
+
+func NewClientAssertionRepo(db *DB) repository.ClientAssertionRepository {
+	return &ClientAssertionRepo{db: db}
+}
+
+// Use also drops the client's expired assertion IDs, which can no longer
+// be replayed, so the table stays small without a separate cleanup job.
+func (r *ClientAssertionRepo) Use(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error) {
+	purge := `DELETE FROM oauth_client_assertions WHERE client_id = $1 AND expires_at < $2`
+	if _, err := r.db.conn(ctx).ExecContext(ctx, purge, clientID, time.Now().UTC()); err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO oauth_client_assertions (client_id, jti, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (client_id, jti) DO NOTHING
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, clientID, jti, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
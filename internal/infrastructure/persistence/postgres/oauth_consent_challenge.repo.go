@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type ConsentChallengeRepo struct {
+	db *DB
+}
+
+func NewConsentChallengeRepo(db *DB) repository.ConsentChallengeRepository {
+	return &ConsentChallengeRepo{db: db}
+}
+
+// Create also drops expired challenges, as the device authorization
+// repository does, so abandoned consent pages do not pile up.
+func (r *ConsentChallengeRepo) Create(ctx context.Context, challenge *entity.ConsentChallenge) error {
+	purge := `DELETE FROM oauth_consent_challenges WHERE expires_at < $1`
+	if _, err := r.db.conn(ctx).ExecContext(ctx, purge, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oauth_consent_challenges (
+			id, token_hash, client_id, user_id, redirect_uri, scopes,
+			state, nonce, code_challenge, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		challenge.ID,
+		challenge.TokenHash,
+		challenge.ClientID,
+		challenge.UserID,
+		challenge.RedirectURI,
+		textArray(challenge.Scopes),
+		challenge.State,
+		challenge.Nonce,
+		challenge.CodeChallenge,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *ConsentChallengeRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ConsentChallenge, error) {
+	query := `
+		SELECT id, token_hash, client_id, user_id, redirect_uri, scopes,
+			state, nonce, code_challenge, expires_at, used_at, created_at
+		FROM oauth_consent_challenges WHERE token_hash = $1
+	`
+
+	var challenge entity.ConsentChallenge
+	var scopes pq.StringArray
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.TokenHash,
+		&challenge.ClientID,
+		&challenge.UserID,
+		&challenge.RedirectURI,
+		&scopes,
+		&challenge.State,
+		&challenge.Nonce,
+		&challenge.CodeChallenge,
+		&challenge.ExpiresAt,
+		&usedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	challenge.Scopes = scopes
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+
+	return &challenge, nil
+}
+
+func (r *ConsentChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE oauth_consent_challenges
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type DeviceAuthorizationRepo struct {
+	db *DB
+}
+
+func NewDeviceAuthorizationRepo(db *DB) repository.DeviceAuthorizationRepository {
+	return &DeviceAuthorizationRepo{db: db}
+}
+
+// Create also drops expired requests, whose codes can no longer be
+// polled or entered, so the table stays small without a separate cleanup
+// job. This frees their user codes for reuse too.
+func (r *DeviceAuthorizationRepo) Create(ctx context.Context, authorization *entity.DeviceAuthorization) error {
+	purge := `DELETE FROM oauth_device_authorizations WHERE expires_at < $1`
+	if _, err := r.db.conn(ctx).ExecContext(ctx, purge, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oauth_device_authorizations (
+			id, client_id, device_code_hash, user_code_hash, scopes, status,
+			family_id, interval_sec, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		authorization.ID,
+		authorization.ClientID,
+		authorization.DeviceCodeHash,
+		authorization.UserCodeHash,
+		textArray(authorization.Scopes),
+		string(authorization.Status),
+		authorization.FamilyID,
+		int(authorization.Interval/time.Second),
+		authorization.ExpiresAt,
+		authorization.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *DeviceAuthorizationRepo) FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*entity.DeviceAuthorization, error) {
+	query := `
+		SELECT id, client_id, device_code_hash, user_code_hash, scopes, status, user_id,
+			family_id, interval_sec, last_polled_at, expires_at, decided_at, used_at, created_at
+		FROM oauth_device_authorizations WHERE device_code_hash = $1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, deviceCodeHash)
+	return scanDeviceAuthorization(row)
+}
+
+func (r *DeviceAuthorizationRepo) FindByUserCodeHash(ctx context.Context, userCodeHash string) (*entity.DeviceAuthorization, error) {
+	query := `
+		SELECT id, client_id, device_code_hash, user_code_hash, scopes, status, user_id,
+			family_id, interval_sec, last_polled_at, expires_at, decided_at, used_at, created_at
+		FROM oauth_device_authorizations WHERE user_code_hash = $1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, userCodeHash)
+	return scanDeviceAuthorization(row)
+}
+
+func (r *DeviceAuthorizationRepo) RecordPoll(ctx context.Context, id string, polledAt time.Time, interval time.Duration) error {
+	query := `
+		UPDATE oauth_device_authorizations
+		SET last_polled_at = $2, interval_sec = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, id, polledAt, int(interval/time.Second))
+	return err
+}
+
+func (r *DeviceAuthorizationRepo) Decide(ctx context.Context, id, userID string, status entity.DeviceAuthorizationStatus, decidedAt time.Time) (bool, error) {
+	query := `
+		UPDATE oauth_device_authorizations
+		SET status = $3, user_id = $2, decided_at = $4
+		WHERE id = $1 AND status = 'pending' AND expires_at > $4
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, userID, string(status), decidedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *DeviceAuthorizationRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE oauth_device_authorizations
+		SET used_at = $2
+		WHERE id = $1 AND status = 'approved' AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func scanDeviceAuthorization(row *sql.Row) (*entity.DeviceAuthorization, error) {
+	var authorization entity.DeviceAuthorization
+	var scopes pq.StringArray
+	var status string
+	var userID sql.NullString
+	var intervalSec int
+	var lastPolledAt, decidedAt, usedAt sql.NullTime
+
+	err := row.Scan(
+		&authorization.ID,
+		&authorization.ClientID,
+		&authorization.DeviceCodeHash,
+		&authorization.UserCodeHash,
+		&scopes,
+		&status,
+		&userID,
+		&authorization.FamilyID,
+		&intervalSec,
+		&lastPolledAt,
+		&authorization.ExpiresAt,
+		&decidedAt,
+		&usedAt,
+		&authorization.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	authorization.Scopes = scopes
+	authorization.Status = entity.DeviceAuthorizationStatus(status)
+	authorization.UserID = userID.String
+	authorization.Interval = time.Duration(intervalSec) * time.Second
+	if lastPolledAt.Valid {
+		authorization.LastPolledAt = &lastPolledAt.Time
+	}
+	if decidedAt.Valid {
+		authorization.DecidedAt = &decidedAt.Time
+	}
+	if usedAt.Valid {
+		authorization.UsedAt = &usedAt.Time
+	}
+
+	return &authorization, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type OAuthSessionRepo struct {
+	db *DB
+}
+
+func NewOAuthSessionRepo(db *DB) repository.OAuthSessionRepository {
+	return &OAuthSessionRepo{db: db}
+}
+
+func (r *OAuthSessionRepo) Create(ctx context.Context, session *entity.OAuthSession) error {
+	query := `
+		INSERT INTO oauth_sessions (id, user_id, client_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.ClientID,
+		session.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *OAuthSessionRepo) ListActiveByUserID(ctx context.Context, userID string) ([]*entity.OAuthSession, error) {
+	query := `
+		SELECT id, user_id, client_id, created_at, ended_at
+		FROM oauth_sessions WHERE user_id = $1 AND ended_at IS NULL
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entity.OAuthSession
+	for rows.Next() {
+		var session entity.OAuthSession
+		if err := rows.Scan(&session.ID, &session.UserID, &session.ClientID, &session.CreatedAt, &session.EndedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *OAuthSessionRepo) EndByUserID(ctx context.Context, userID string, endedAt time.Time) error {
+	query := `
+		UPDATE oauth_sessions
+		SET ended_at = $2
+		WHERE user_id = $1 AND ended_at IS NULL
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userID, endedAt)
+	return err
+}
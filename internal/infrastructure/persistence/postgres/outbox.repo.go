@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type OutboxRepo struct {
+	db *DB
+}
+
+func NewOutboxRepo(db *DB) repository.OutboxRepository {
+	return &OutboxRepo{db: db}
+}
+
+func (r *OutboxRepo) Create(ctx context.Context, event *entity.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox (id, event_type, subscriber, dedup_key, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (dedup_key) DO NOTHING
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		event.ID,
+		event.EventType,
+		event.Subscriber,
+		event.DedupKey,
+		event.Payload,
+		event.Status,
+		event.Attempts,
+		event.NextAttemptAt,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, now time.Time, lease time.Duration, maxAttempts int) ([]*entity.OutboxEvent, error) {
+	// A lease that expired on the last attempt means the dispatcher died
+	// mid-delivery with no attempts left.
+	abandoned := `
+		UPDATE outbox
+		SET status = 'dead', processed_at = $1, locked_until = NULL,
+			last_error = 'lease expired during the last attempt'
+		WHERE status = 'processing' AND locked_until < $1 AND attempts >= $2
+	`
+
+	if _, err := r.db.conn(ctx).ExecContext(ctx, abandoned, now, maxAttempts); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE outbox
+		SET status = 'processing', locked_until = $2, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE (status = 'pending' AND next_attempt_at <= $1)
+			   OR (status = 'processing' AND locked_until < $1 AND attempts < $4)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, subscriber, dedup_key, payload, status, attempts,
+			next_attempt_at, locked_until, last_error, created_at, processed_at
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, now, now.Add(lease), limit, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entity.OutboxEvent
+
+	for rows.Next() {
+		var event entity.OutboxEvent
+		var lockedUntil, processedAt sql.NullTime
+		var lastError sql.NullString
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.Subscriber,
+			&event.DedupKey,
+			&event.Payload,
+			&event.Status,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&lockedUntil,
+			&lastError,
+			&event.CreatedAt,
+			&processedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if lockedUntil.Valid {
+			event.LockedUntil = &lockedUntil.Time
+		}
+		if processedAt.Valid {
+			event.ProcessedAt = &processedAt.Time
+		}
+		event.LastError = lastError.String
+
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *OutboxRepo) MarkDelivered(ctx context.Context, id string, lockedUntil, processedAt time.Time) (bool, error) {
+	query := `
+		UPDATE outbox
+		SET status = 'delivered', processed_at = $3, locked_until = NULL, last_error = NULL
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return r.release(ctx, query, id, lockedUntil, processedAt)
+}
+
+func (r *OutboxRepo) MarkRetry(ctx context.Context, id string, lockedUntil, nextAttemptAt time.Time, lastError string) (bool, error) {
+	query := `
+		UPDATE outbox
+		SET status = 'pending', next_attempt_at = $3, locked_until = NULL, last_error = $4
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return r.release(ctx, query, id, lockedUntil, nextAttemptAt, lastError)
+}
+
+func (r *OutboxRepo) MarkDead(ctx context.Context, id string, lockedUntil, processedAt time.Time, lastError string) (bool, error) {
+	query := `
+		UPDATE outbox
+		SET status = 'dead', processed_at = $3, locked_until = NULL, last_error = $4
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return r.release(ctx, query, id, lockedUntil, processedAt, lastError)
+}
+
+// release runs an update that ends a claim and reports whether the claim
+// was still held.
+func (r *OutboxRepo) release(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
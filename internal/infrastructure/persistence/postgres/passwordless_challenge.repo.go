@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type PasswordlessChallengeRepo struct {
+	db *DB
+}
+
+func NewPasswordlessChallengeRepo(db *DB) repository.PasswordlessChallengeRepository {
+	return &PasswordlessChallengeRepo{db: db}
+}
+
+func (r *PasswordlessChallengeRepo) Create(ctx context.Context, challenge *entity.PasswordlessChallenge) error {
+	query := `
+		INSERT INTO passwordless_challenges (id, user_id, method, device_hash, secret_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		challenge.ID,
+		challenge.UserID,
+		string(challenge.Method),
+		challenge.DeviceHash,
+		challenge.SecretHash,
+		challenge.Attempts,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PasswordlessChallengeRepo) FindByDeviceHash(ctx context.Context, deviceHash string) (*entity.PasswordlessChallenge, error) {
+	query := `
+		SELECT id, user_id, method, device_hash, secret_hash, attempts, expires_at, used_at, created_at
+		FROM passwordless_challenges WHERE device_hash = $1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, deviceHash)
+	return scanPasswordlessChallenge(row)
+}
+
+func (r *PasswordlessChallengeRepo) FindLatestByUserID(ctx context.Context, userID string) (*entity.PasswordlessChallenge, error) {
+	query := `
+		SELECT id, user_id, method, device_hash, secret_hash, attempts, expires_at, used_at, created_at
+		FROM passwordless_challenges WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, userID)
+	return scanPasswordlessChallenge(row)
+}
+
+func (r *PasswordlessChallengeRepo) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	query := `
+		UPDATE passwordless_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+		RETURNING attempts
+	`
+
+	var attempts int
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, id).Scan(&attempts)
+	return attempts, err
+}
+
+func (r *PasswordlessChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE passwordless_challenges
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *PasswordlessChallengeRepo) InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error {
+	query := `
+		UPDATE passwordless_challenges
+		SET used_at = $2
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userID, usedAt)
+	return err
+}
+
+func scanPasswordlessChallenge(row *sql.Row) (*entity.PasswordlessChallenge, error) {
+	var challenge entity.PasswordlessChallenge
+	var method string
+	var usedAt sql.NullTime
+
+	err := row.Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&method,
+		&challenge.DeviceHash,
+		&challenge.SecretHash,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&usedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	challenge.Method = entity.PasswordlessMethod(method)
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+
+	return &challenge, nil
+}
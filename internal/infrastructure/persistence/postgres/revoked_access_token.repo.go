@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type RevokedAccessTokenRepo struct {
+	db *DB
+}
+
+func NewRevokedAccessTokenRepo(db *DB) repository.RevokedAccessTokenRepository {
+	return &RevokedAccessTokenRepo{db: db}
+}
+
+func (r *RevokedAccessTokenRepo) Create(ctx context.Context, token *entity.RevokedAccessToken) error {
+	query := `
+		INSERT INTO revoked_access_tokens (jti, expires_at, revoked_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, token.ID, token.ExpiresAt, token.RevokedAt)
+	return err
+}
+
+func (r *RevokedAccessTokenRepo) ListActive(ctx context.Context, now time.Time) ([]*entity.RevokedAccessToken, error) {
+	query := `
+		SELECT jti, expires_at, revoked_at
+		FROM revoked_access_tokens
+		WHERE expires_at > $1
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*entity.RevokedAccessToken
+	for rows.Next() {
+		var token entity.RevokedAccessToken
+		if err := rows.Scan(&token.ID, &token.ExpiresAt, &token.RevokedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *RevokedAccessTokenRepo) CreateCutoff(ctx context.Context, cutoff *entity.AccessTokenCutoff) error {
+	query := `
+		INSERT INTO access_token_cutoffs (user_id, issued_before, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET issued_before = EXCLUDED.issued_before, expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, cutoff.UserID, cutoff.IssuedBefore, cutoff.ExpiresAt)
+	return err
+}
+
+func (r *RevokedAccessTokenRepo) ListActiveCutoffs(ctx context.Context, now time.Time) ([]*entity.AccessTokenCutoff, error) {
+	query := `
+		SELECT user_id, issued_before, expires_at
+		FROM access_token_cutoffs
+		WHERE expires_at > $1
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cutoffs []*entity.AccessTokenCutoff
+	for rows.Next() {
+		var cutoff entity.AccessTokenCutoff
+		if err := rows.Scan(&cutoff.UserID, &cutoff.IssuedBefore, &cutoff.ExpiresAt); err != nil {
+			return nil, err
+		}
+		cutoffs = append(cutoffs, &cutoff)
+	}
+
+	return cutoffs, rows.Err()
+}
+
+func (r *RevokedAccessTokenRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for _, query := range []string{
+		`DELETE FROM revoked_access_tokens WHERE expires_at <= $1`,
+		`DELETE FROM access_token_cutoffs WHERE expires_at <= $1`,
+	} {
+		result, err := r.db.conn(ctx).ExecContext(ctx, query, now)
+		if err != nil {
+			return deleted, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += affected
+	}
+	return deleted, nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type SAMLAssertionRepo struct {
+	db *DB
+}
+
+func NewSAMLAssertionRepo(db *DB) repository.SAMLAssertionRepository {
+	return &SAMLAssertionRepo{db: db}
+}
+
+// Remember prunes assertions that have expired, which are refused on
+// their own, before recording the new one.
+func (r *SAMLAssertionRepo) Remember(ctx context.Context, idp, assertionID string, expiresAt time.Time) (bool, error) {
+	if _, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM saml_assertions WHERE expires_at < $1`, time.Now().UTC()); err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO saml_assertions (idp, assertion_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (idp, assertion_id) DO NOTHING
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, idp, assertionID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type SAMLLoginRepo struct {
+	db *DB
+}
+
+func NewSAMLLoginRepo(db *DB) repository.SAMLLoginRepository {
+	return &SAMLLoginRepo{db: db}
+}
+
+func (r *SAMLLoginRepo) Create(ctx context.Context, login *entity.SAMLLogin) error {
+	query := `
+		INSERT INTO saml_logins (id, idp, user_id, code_hash, device_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		login.ID,
+		login.IdP,
+		login.UserID,
+		login.CodeHash,
+		login.DeviceHash,
+		login.ExpiresAt,
+		login.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *SAMLLoginRepo) FindByCodeHash(ctx context.Context, codeHash string) (*entity.SAMLLogin, error) {
+	query := `
+		SELECT id, idp, user_id, code_hash, device_hash, expires_at, used_at, created_at
+		FROM saml_logins WHERE code_hash = $1
+	`
+
+	var login entity.SAMLLogin
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, codeHash).Scan(
+		&login.ID,
+		&login.IdP,
+		&login.UserID,
+		&login.CodeHash,
+		&login.DeviceHash,
+		&login.ExpiresAt,
+		&usedAt,
+		&login.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		login.UsedAt = &usedAt.Time
+	}
+
+	return &login, nil
+}
+
+func (r *SAMLLoginRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE saml_logins
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
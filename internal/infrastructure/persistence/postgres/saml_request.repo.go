@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type SAMLRequestRepo struct {
+	db *DB
+}
+
+func NewSAMLRequestRepo(db *DB) repository.SAMLRequestRepository {
+	return &SAMLRequestRepo{db: db}
+}
+
+func (r *SAMLRequestRepo) Create(ctx context.Context, request *entity.SAMLRequest) error {
+	query := `
+		INSERT INTO saml_requests (id, idp, device_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		request.ID,
+		request.IdP,
+		request.DeviceHash,
+		request.ExpiresAt,
+		request.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *SAMLRequestRepo) FindByID(ctx context.Context, id string) (*entity.SAMLRequest, error) {
+	query := `
+		SELECT id, idp, device_hash, expires_at, used_at, created_at
+		FROM saml_requests WHERE id = $1
+	`
+
+	var request entity.SAMLRequest
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, id).Scan(
+		&request.ID,
+		&request.IdP,
+		&request.DeviceHash,
+		&request.ExpiresAt,
+		&usedAt,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		request.UsedAt = &usedAt.Time
+	}
+
+	return &request, nil
+}
+
+func (r *SAMLRequestRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE saml_requests
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
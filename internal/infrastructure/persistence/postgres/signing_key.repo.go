@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+// signingKeyLockID is the advisory lock that serializes key rotation.
+const signingKeyLockID = 0x7369676e
+
+type SigningKeyRepo struct {
+	db *DB
+}
+
+func NewSigningKeyRepo(db *DB) repository.SigningKeyRepository {
+	return &SigningKeyRepo{db: db}
+}
+
+func (r *SigningKeyRepo) Create(ctx context.Context, key *entity.SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (id, algorithm, private_key, public_key, activates_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		key.ID,
+		key.Algorithm,
+		key.PrivateKey,
+		key.PublicKey,
+		key.ActivatesAt,
+		key.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *SigningKeyRepo) List(ctx context.Context) ([]*entity.SigningKey, error) {
+	query := `
+		SELECT id, algorithm, private_key, public_key, activates_at, created_at
+		FROM signing_keys
+		ORDER BY activates_at
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*entity.SigningKey
+	for rows.Next() {
+		var key entity.SigningKey
+		err := rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&key.PrivateKey,
+			&key.PublicKey,
+			&key.ActivatesAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *SigningKeyRepo) Delete(ctx context.Context, ids []string) error {
+	query := `DELETE FROM signing_keys WHERE id = ANY($1)`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, pq.StringArray(ids))
+	return err
+}
+
+func (r *SigningKeyRepo) Lock(ctx context.Context) error {
+	_, err := r.db.conn(ctx).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, signingKeyLockID)
+	return err
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type TOTPCredentialRepo struct {
+	db *DB
+}
+
+func NewTOTPCredentialRepo(db *DB) repository.TOTPCredentialRepository {
+	return &TOTPCredentialRepo{db: db}
+}
+
+func (r *TOTPCredentialRepo) Save(ctx context.Context, credential *entity.TOTPCredential) error {
+	query := `
+		INSERT INTO totp_credentials (user_id, id, secret_ciphertext, confirmed_at, last_used_step, created_at)
+		VALUES ($1, $2, $3, NULL, 0, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET id = EXCLUDED.id, secret_ciphertext = EXCLUDED.secret_ciphertext,
+			last_used_step = 0, created_at = EXCLUDED.created_at
+		WHERE totp_credentials.confirmed_at IS NULL
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		credential.UserID,
+		credential.ID,
+		credential.SecretCiphertext,
+		credential.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *TOTPCredentialRepo) FindByUserID(ctx context.Context, userID string) (*entity.TOTPCredential, error) {
+	query := `
+		SELECT id, user_id, secret_ciphertext, confirmed_at, last_used_step, created_at
+		FROM totp_credentials WHERE user_id = $1
+	`
+
+	var credential entity.TOTPCredential
+	var confirmedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.SecretCiphertext,
+		&confirmedAt,
+		&credential.LastUsedStep,
+		&credential.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		credential.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return &credential, nil
+}
+
+func (r *TOTPCredentialRepo) Confirm(ctx context.Context, userID string, step int64, confirmedAt time.Time) (bool, error) {
+	query := `
+		UPDATE totp_credentials
+		SET confirmed_at = $3, last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	return r.execConditional(ctx, query, userID, step, confirmedAt)
+}
+
+func (r *TOTPCredentialRepo) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := `
+		UPDATE totp_credentials
+		SET last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2
+	`
+
+	return r.execConditional(ctx, query, userID, step)
+}
+
+func (r *TOTPCredentialRepo) Delete(ctx context.Context, userID string) error {
+	query := `DELETE FROM totp_credentials WHERE user_id = $1`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *TOTPCredentialRepo) execConditional(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
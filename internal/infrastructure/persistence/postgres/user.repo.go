@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
 	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
@@ -19,17 +22,18 @@ func NewPostgreUserRepo(db *DB) repository.UserRepository {
 
 func (r *PostgreUserRepo) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, username, email, password_hash, is_active, is_email_verified)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, username, email, password_hash, is_active, is_email_verified, roles)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
 		user.ID.String(),
 		user.Username.String(),
 		user.Email.String(),
-		"", // password_hash - will be added later
+		user.PasswordHash,
 		user.IsActive,
 		user.IsEmailVerified,
+		textArray(user.Roles),
 	)
 
 	return err
@@ -37,31 +41,34 @@ func (r *PostgreUserRepo) Create(ctx context.Context, user *entity.User) error {
 
 func (r *PostgreUserRepo) FindByID(ctx context.Context, id string) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, is_active, is_email_verified
+		SELECT id, username, email, password_hash, is_active, is_email_verified,
+		       failed_login_attempts, lockout_count, locked_until, roles
 		FROM users WHERE id = $1
 	`
 
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, id)
 	return scanUser(row)
 }
 
 func (r *PostgreUserRepo) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, is_active, is_email_verified
+		SELECT id, username, email, password_hash, is_active, is_email_verified,
+		       failed_login_attempts, lockout_count, locked_until, roles
 		FROM users WHERE username = $1
 	`
 
-	row := r.db.QueryRowContext(ctx, query, username)
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, username)
 	return scanUser(row)
 }
 
 func (r *PostgreUserRepo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, is_active, is_email_verified
+		SELECT id, username, email, password_hash, is_active, is_email_verified,
+		       failed_login_attempts, lockout_count, locked_until, roles
 		FROM users WHERE email = $1
 	`
 
-	row := r.db.QueryRowContext(ctx, query, email)
+	row := r.db.conn(ctx).QueryRowContext(ctx, query, email)
 	return scanUser(row)
 }
 
@@ -69,7 +76,7 @@ func (r *PostgreUserRepo) ExistsByUsername(ctx context.Context, username string)
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, username).Scan(&exists)
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, username).Scan(&exists)
 	return exists, err
 }
 
@@ -77,33 +84,105 @@ func (r *PostgreUserRepo) ExistsByEmail(ctx context.Context, email string) (bool
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, email).Scan(&exists)
 	return exists, err
 }
 
 func (r *PostgreUserRepo) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET username = $2, email = $3, is_active = $4, is_email_verified = $5
+		SET username = $2, email = $3, password_hash = $4, is_active = $5, is_email_verified = $6, roles = $7
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
 		user.ID.String(),
 		user.Username.String(),
 		user.Email.String(),
+		user.PasswordHash,
 		user.IsActive,
 		user.IsEmailVerified,
+		textArray(user.Roles),
 	)
 
 	return err
 }
 
+func (r *PostgreUserRepo) RecordFailedLogin(ctx context.Context, user *entity.User, now, windowStart time.Time) error {
+	// Failures older than the window no longer count towards a lockout,
+	// but an expired lock still leaves lockout_count in place so the next
+	// lock lasts longer.
+	query := `
+		UPDATE users
+		SET failed_login_attempts = CASE
+				WHEN last_failed_login_at IS NULL OR last_failed_login_at < $3 THEN 1
+				ELSE failed_login_attempts + 1
+			END,
+			last_failed_login_at = $2
+		WHERE id = $1
+		RETURNING failed_login_attempts, lockout_count, locked_until
+	`
+
+	var lockedUntil sql.NullTime
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, user.ID.String(), now, windowStart).
+		Scan(&user.FailedLoginAttempts, &user.LockoutCount, &lockedUntil)
+	if err != nil {
+		return err
+	}
+
+	user.LockedUntil = nullTimePtr(lockedUntil)
+	return nil
+}
+
+func (r *PostgreUserRepo) Lock(ctx context.Context, user *entity.User, until time.Time, threshold int) (bool, error) {
+	query := `
+		UPDATE users
+		SET locked_until = $2, lockout_count = lockout_count + 1, failed_login_attempts = 0
+		WHERE id = $1 AND failed_login_attempts >= $3
+		RETURNING lockout_count
+	`
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, user.ID.String(), until, threshold).
+		Scan(&user.LockoutCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = &until
+	return true, nil
+}
+
+func (r *PostgreUserRepo) ResetFailedLogins(ctx context.Context, user *entity.User) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, last_failed_login_at = NULL, lockout_count = 0, locked_until = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockoutCount = 0
+	user.LockedUntil = nil
+	return nil
+}
+
 func scanUser(row *sql.Row) (*entity.User, error) {
-	var id, username, email string
+	var id, username, email, passwordHash string
 	var isActive, isEmailVerified bool
+	var failedLoginAttempts, lockoutCount int
+	var lockedUntil sql.NullTime
+	var roles pq.StringArray
 
-	err := row.Scan(&id, &username, &email, &isActive, &isEmailVerified)
+	err := row.Scan(&id, &username, &email, &passwordHash, &isActive, &isEmailVerified,
+		&failedLoginAttempts, &lockoutCount, &lockedUntil, &roles)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -130,7 +209,20 @@ func scanUser(row *sql.Row) (*entity.User, error) {
 		ID:              userID,
 		Username:        *userUsername,
 		Email:           userEmail,
+		PasswordHash:    passwordHash,
 		IsActive:        isActive,
 		IsEmailVerified: isEmailVerified,
+		Roles:           roles,
+
+		FailedLoginAttempts: failedLoginAttempts,
+		LockoutCount:        lockoutCount,
+		LockedUntil:         nullTimePtr(lockedUntil),
 	}, nil
 }
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type UserConsentRepo struct {
+	db *DB
+}
+
+func NewUserConsentRepo(db *DB) repository.UserConsentRepository {
+	return &UserConsentRepo{db: db}
+}
+
+func (r *UserConsentRepo) Find(ctx context.Context, userID, clientID string) (*entity.UserConsent, error) {
+	query := `
+		SELECT user_id, client_id, scopes, created_at, updated_at
+		FROM user_consents WHERE user_id = $1 AND client_id = $2
+	`
+
+	consent, err := scanUserConsent(r.db.conn(ctx).QueryRowContext(ctx, query, userID, clientID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return consent, nil
+}
+
+func (r *UserConsentRepo) ListByUserID(ctx context.Context, userID string) ([]*entity.UserConsent, error) {
+	query := `
+		SELECT user_id, client_id, scopes, created_at, updated_at
+		FROM user_consents WHERE user_id = $1
+		ORDER BY created_at, client_id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []*entity.UserConsent
+	for rows.Next() {
+		consent, err := scanUserConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+
+	return consents, rows.Err()
+}
+
+func (r *UserConsentRepo) Save(ctx context.Context, consent *entity.UserConsent) error {
+	query := `
+		INSERT INTO user_consents (user_id, client_id, scopes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, client_id) DO UPDATE
+		SET scopes = EXCLUDED.scopes, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		consent.UserID,
+		consent.ClientID,
+		textArray(consent.Scopes),
+		consent.CreatedAt,
+		consent.UpdatedAt,
+	)
+
+	return err
+}
+
+// Delete treats a client ID that is not a UUID as unknown, since it
+// arrives unchecked from the request path.
+func (r *UserConsentRepo) Delete(ctx context.Context, userID, clientID string) (bool, error) {
+	if uuid.Validate(clientID) != nil {
+		return false, nil
+	}
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM user_consents WHERE user_id = $1 AND client_id = $2`, userID, clientID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func scanUserConsent(row rowScanner) (*entity.UserConsent, error) {
+	var consent entity.UserConsent
+	var scopes pq.StringArray
+
+	err := row.Scan(
+		&consent.UserID,
+		&consent.ClientID,
+		&scopes,
+		&consent.CreatedAt,
+		&consent.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	consent.Scopes = scopes
+	return &consent, nil
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type WebAuthnCredentialRepo struct {
+	db *DB
+}
+
+func NewWebAuthnCredentialRepo(db *DB) repository.WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepo{db: db}
+}
+
+const webAuthnCredentialColumns = `
+	id, user_id, name, credential_id, public_key, algorithm, sign_count, aaguid,
+	transports, attestation_format, backup_eligible, backup_state, created_at, last_used_at
+`
+
+func (r *WebAuthnCredentialRepo) Create(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	query := `
+		INSERT INTO webauthn_credentials (` + webAuthnCredentialColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		credential.ID,
+		credential.UserID,
+		credential.Name,
+		credential.CredentialID,
+		credential.PublicKey,
+		credential.Algorithm,
+		int64(credential.SignCount),
+		credential.AAGUID,
+		textArray(credential.Transports),
+		credential.AttestationFormat,
+		credential.BackupEligible,
+		credential.BackupState,
+		credential.CreatedAt,
+		credential.LastUsedAt,
+	)
+
+	return err
+}
+
+func (r *WebAuthnCredentialRepo) FindByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnCredentialColumns + ` FROM webauthn_credentials WHERE credential_id = $1`
+
+	credential, err := scanWebAuthnCredential(r.db.conn(ctx).QueryRowContext(ctx, query, credentialID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (r *WebAuthnCredentialRepo) ListByUserID(ctx context.Context, userID string) ([]*entity.WebAuthnCredential, error) {
+	query := `
+		SELECT ` + webAuthnCredentialColumns + `
+		FROM webauthn_credentials WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credentials []*entity.WebAuthnCredential
+	for rows.Next() {
+		credential, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, rows.Err()
+}
+
+func (r *WebAuthnCredentialRepo) UpdateUsage(ctx context.Context, id string, expectedSignCount, signCount uint32, backupState bool, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE webauthn_credentials
+		SET sign_count = $3, backup_state = $4, last_used_at = $5
+		WHERE id = $1 AND sign_count = $2
+	`
+
+	return r.execConditional(ctx, query, id, int64(expectedSignCount), int64(signCount), backupState, usedAt)
+}
+
+func (r *WebAuthnCredentialRepo) Delete(ctx context.Context, userID, id string) (bool, error) {
+	query := `DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2`
+
+	return r.execConditional(ctx, query, id, userID)
+}
+
+func (r *WebAuthnCredentialRepo) execConditional(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebAuthnCredential(row rowScanner) (*entity.WebAuthnCredential, error) {
+	var credential entity.WebAuthnCredential
+	var signCount int64
+	var transports pq.StringArray
+	var lastUsedAt sql.NullTime
+
+	err := row.Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.Name,
+		&credential.CredentialID,
+		&credential.PublicKey,
+		&credential.Algorithm,
+		&signCount,
+		&credential.AAGUID,
+		&transports,
+		&credential.AttestationFormat,
+		&credential.BackupEligible,
+		&credential.BackupState,
+		&credential.CreatedAt,
+		&lastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.SignCount = uint32(signCount)
+	credential.Transports = transports
+	if lastUsedAt.Valid {
+		credential.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &credential, nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+)
+
+type WebAuthnSessionRepo struct {
+	db *DB
+}
+
+func NewWebAuthnSessionRepo(db *DB) repository.WebAuthnSessionRepository {
+	return &WebAuthnSessionRepo{db: db}
+}
+
+func (r *WebAuthnSessionRepo) Create(ctx context.Context, session *entity.WebAuthnSession) error {
+	query := `
+		INSERT INTO webauthn_sessions (id, user_id, ceremony, challenge, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query,
+		session.ID,
+		sql.NullString{String: session.UserID, Valid: session.UserID != ""},
+		string(session.Ceremony),
+		session.Challenge,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *WebAuthnSessionRepo) FindByID(ctx context.Context, id string) (*entity.WebAuthnSession, error) {
+	query := `
+		SELECT id, user_id, ceremony, challenge, expires_at, used_at, created_at
+		FROM webauthn_sessions WHERE id = $1
+	`
+
+	var session entity.WebAuthnSession
+	var userID sql.NullString
+	var ceremony string
+	var usedAt sql.NullTime
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, id).Scan(
+		&session.ID,
+		&userID,
+		&ceremony,
+		&session.Challenge,
+		&session.ExpiresAt,
+		&usedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	session.UserID = userID.String
+	session.Ceremony = entity.WebAuthnCeremony(ceremony)
+	if usedAt.Valid {
+		session.UsedAt = &usedAt.Time
+	}
+
+	return &session, nil
+}
+
+func (r *WebAuthnSessionRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	query := `
+		UPDATE webauthn_sessions
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,19 @@
+package qrcode
+
+import (
+	qr "github.com/skip2/go-qrcode"
+)
+
+const defaultSize = 256
+
+type Encoder struct {
+	size int
+}
+
+func NewEncoder() *Encoder {
+	return &Encoder{size: defaultSize}
+}
+
+func (e *Encoder) EncodePNG(content string) ([]byte, error) {
+	return qr.Encode(content, qr.Medium, e.size)
+}
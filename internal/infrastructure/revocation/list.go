@@ -0,0 +1,202 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/repository"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+// List holds the IDs of revoked access tokens that have not expired yet,
+// and per-user cutoffs that revoke every token a user was issued before a
+// point in time. Revocations live in Postgres; every instance keeps the whole set in
+// memory and reloads it each refresh interval, so checking a token costs
+// no query. A token revoked here is refused at once, and on other
+// instances from their next reload. While running, the list also deletes
+// revocations whose tokens have expired.
+type List struct {
+	repo   repository.RevokedAccessTokenRepository
+	log    *logger.Logger
+	config *config.RevocationConfig
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+	cutoffs map[string]*entity.AccessTokenCutoff
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewList(repo repository.RevokedAccessTokenRepository, log *logger.Logger, cfg *config.RevocationConfig) *List {
+	return &List{
+		repo:    repo,
+		log:     log,
+		config:  cfg,
+		revoked: make(map[string]time.Time),
+		cutoffs: make(map[string]*entity.AccessTokenCutoff),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Revoke records the token until expiresAt. A token that has already
+// expired needs no entry.
+func (l *List) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	token := entity.NewRevokedAccessToken(jti, expiresAt)
+	if token.IsExpired(token.RevokedAt) {
+		return nil
+	}
+
+	if err := l.repo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.revoked[jti] = expiresAt
+	l.mu.Unlock()
+	return nil
+}
+
+// RevokeUser revokes every access token issued to the user up to now. The
+// cutoff is kept until the longest-lived of those tokens has expired.
+func (l *List) RevokeUser(ctx context.Context, userID string, now time.Time) error {
+	cutoff := entity.NewAccessTokenCutoff(userID, now, l.config.MaxAccessTokenTTL)
+	if err := l.repo.CreateCutoff(ctx, cutoff); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.addCutoff(cutoff)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *List) IsRevoked(claims *port.AccessTokenClaims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if _, ok := l.revoked[claims.ID]; ok {
+		return true
+	}
+	cutoff, ok := l.cutoffs[claims.UserID]
+	return ok && cutoff.Revokes(claims.IssuedAt)
+}
+
+// Size reports how many revoked tokens are held in memory, not counting
+// per-user cutoffs.
+func (l *List) Size() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.revoked)
+}
+
+// Reload adds the stored revocations to the in-memory set and drops those
+// of expired tokens. Revocations are never withdrawn, so entries added by
+// Revoke while the query ran are kept.
+func (l *List) Reload(ctx context.Context) error {
+	now := time.Now().UTC()
+	stored, err := l.repo.ListActive(ctx, now)
+	if err != nil {
+		return err
+	}
+	storedCutoffs, err := l.repo.ListActiveCutoffs(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	revoked := make(map[string]time.Time, len(stored))
+	for jti, expiresAt := range l.revoked {
+		if now.Before(expiresAt) {
+			revoked[jti] = expiresAt
+		}
+	}
+	for _, token := range stored {
+		revoked[token.ID] = token.ExpiresAt
+	}
+	l.revoked = revoked
+
+	cutoffs := l.cutoffs
+	l.cutoffs = make(map[string]*entity.AccessTokenCutoff, len(storedCutoffs))
+	for _, cutoff := range cutoffs {
+		if !cutoff.IsExpired(now) {
+			l.addCutoff(cutoff)
+		}
+	}
+	for _, cutoff := range storedCutoffs {
+		l.addCutoff(cutoff)
+	}
+	return nil
+}
+
+// addCutoff keeps the later of the user's cutoffs. The caller holds mu.
+func (l *List) addCutoff(cutoff *entity.AccessTokenCutoff) {
+	if current, ok := l.cutoffs[cutoff.UserID]; ok && !cutoff.IssuedBefore.After(current.IssuedBefore) {
+		return
+	}
+	l.cutoffs[cutoff.UserID] = cutoff
+}
+
+// Prune deletes the revocations of expired tokens.
+func (l *List) Prune(ctx context.Context) error {
+	deleted, err := l.repo.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		l.log.Info("Deleted expired access token revocations", zap.Int64("count", deleted))
+	}
+	return nil
+}
+
+func (l *List) Start() {
+	l.wg.Add(1)
+	go l.run()
+
+	l.log.Info("Access token revocation list started",
+		zap.Duration("refresh_interval", l.config.RefreshInterval),
+		zap.Duration("cleanup_interval", l.config.CleanupInterval),
+	)
+}
+
+func (l *List) Stop() {
+	l.once.Do(func() {
+		close(l.stopCh)
+	})
+	l.wg.Wait()
+	l.log.Info("Access token revocation list stopped")
+}
+
+func (l *List) run() {
+	defer l.wg.Done()
+
+	refresh := time.NewTicker(l.config.RefreshInterval)
+	defer refresh.Stop()
+	cleanup := time.NewTicker(l.config.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-refresh.C:
+			if err := l.Reload(context.Background()); err != nil {
+				l.log.Error("Failed to reload access token revocations", zap.Error(err))
+			}
+		case <-cleanup.C:
+			if err := l.Prune(context.Background()); err != nil {
+				l.log.Error("Failed to delete expired access token revocations", zap.Error(err))
+			}
+		case <-l.stopCh:
+			return
+		}
+	}
+}
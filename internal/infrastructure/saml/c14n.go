@@ -0,0 +1,131 @@
+package saml
+
+import (
+	"sort"
+	"strings"
+)
+
+// canonicalize serializes an element with Exclusive XML Canonicalization
+// 1.0, without comments. Namespaces are declared where they are first
+// visibly used, plus those in inclusivePrefixes ("#default" naming the
+// default namespace). The element skip, when given, is left out, which is
+// how the enveloped signature transform removes the signature.
+func canonicalize(el *element, inclusivePrefixes []string, skip *element) []byte {
+	var b strings.Builder
+	inclusive := make(map[string]bool, len(inclusivePrefixes))
+	for _, prefix := range inclusivePrefixes {
+		if prefix == "#default" {
+			prefix = ""
+		}
+		inclusive[prefix] = true
+	}
+	writeCanonical(&b, el, map[string]string{"": ""}, inclusive, skip)
+	return []byte(b.String())
+}
+
+func writeCanonical(b *strings.Builder, el *element, rendered map[string]string, inclusive map[string]bool, skip *element) {
+	used := map[string]bool{el.prefix: true}
+	for _, a := range el.attrs {
+		if a.prefix != "" {
+			used[a.prefix] = true
+		}
+	}
+	for prefix := range inclusive {
+		if _, ok := el.scope[prefix]; ok {
+			used[prefix] = true
+		}
+	}
+
+	var declared []string
+	for prefix := range used {
+		if prefix == "xml" {
+			continue
+		}
+		space := el.scope[prefix]
+		if current, ok := rendered[prefix]; ok && current == space {
+			continue
+		}
+		if prefix != "" && space == "" {
+			continue
+		}
+		declared = append(declared, prefix)
+	}
+	sort.Strings(declared)
+
+	childRendered := rendered
+	if len(declared) > 0 {
+		childRendered = make(map[string]string, len(rendered)+len(declared))
+		for k, v := range rendered {
+			childRendered[k] = v
+		}
+	}
+
+	b.WriteByte('<')
+	b.WriteString(qualifiedName(el.prefix, el.local))
+	for _, prefix := range declared {
+		space := el.scope[prefix]
+		childRendered[prefix] = space
+		if prefix == "" {
+			b.WriteString(` xmlns="`)
+		} else {
+			b.WriteString(` xmlns:` + prefix + `="`)
+		}
+		b.WriteString(escapeAttr(space))
+		b.WriteByte('"')
+	}
+
+	attrs := append([]attribute(nil), el.attrs...)
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].space != attrs[j].space {
+			return attrs[i].space < attrs[j].space
+		}
+		return attrs[i].local < attrs[j].local
+	})
+	for _, a := range attrs {
+		b.WriteByte(' ')
+		b.WriteString(qualifiedName(a.prefix, a.local))
+		b.WriteString(`="`)
+		b.WriteString(escapeAttr(a.value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('>')
+
+	for _, child := range el.children {
+		switch child := child.(type) {
+		case *element:
+			if child != skip {
+				writeCanonical(b, child, childRendered, inclusive, skip)
+			}
+		case charData:
+			b.WriteString(escapeText(string(child)))
+		case procInst:
+			b.WriteString("<?" + child.target)
+			if child.inst != "" {
+				b.WriteString(" " + child.inst)
+			}
+			b.WriteString("?>")
+		}
+	}
+
+	b.WriteString("</" + qualifiedName(el.prefix, el.local) + ">")
+}
+
+func qualifiedName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,246 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// element is a parsed XML element that keeps the prefixes and namespace
+// declarations encoding/xml resolves away, since canonicalization and
+// signature checks need them.
+type element struct {
+	prefix string
+	local  string
+	space  string
+	attrs  []attribute
+	// scope maps each prefix in scope to its namespace, "" being the
+	// default namespace.
+	scope    map[string]string
+	children []interface{}
+	parent   *element
+}
+
+type attribute struct {
+	prefix string
+	local  string
+	space  string
+	value  string
+}
+
+// charData is a text node.
+type charData string
+
+// procInst is a processing instruction node.
+type procInst struct {
+	target string
+	inst   string
+}
+
+// parseDocument parses an XML document into elements. Document type
+// declarations are refused, so entity expansion cannot be abused.
+func parseDocument(data []byte) (*element, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var root, current *element
+	for {
+		tok, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if root != nil && current == nil {
+				return nil, errors.New("content after the document element")
+			}
+			el, err := newElement(tok, current)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil {
+				root = el
+			} else {
+				current.children = append(current.children, el)
+			}
+			current = el
+		case xml.EndElement:
+			if current == nil || tok.Name.Space != current.prefix || tok.Name.Local != current.local {
+				return nil, fmt.Errorf("unexpected end element %s", tok.Name.Local)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, charData(tok))
+			} else if len(bytes.TrimSpace(tok)) > 0 {
+				return nil, errors.New("text outside the document element")
+			}
+		case xml.Directive:
+			return nil, errors.New("document type declarations are not allowed")
+		case xml.ProcInst:
+			// Processing instructions within an element are signed with
+			// it; those around the document element, the XML
+			// declaration among them, are no element's content.
+			if current != nil {
+				current.children = append(current.children, procInst{target: tok.Target, inst: string(tok.Inst)})
+			}
+		case xml.Comment:
+			// Comments are not signed by exclusive canonicalization
+			// without comments.
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, errors.New("incomplete document")
+	}
+	return root, nil
+}
+
+func newElement(start xml.StartElement, parent *element) (*element, error) {
+	el := &element{
+		prefix: start.Name.Space,
+		local:  start.Name.Local,
+		parent: parent,
+		scope:  map[string]string{"": ""},
+	}
+	if parent != nil {
+		el.scope = parent.scope
+	}
+
+	copied := false
+	for _, attr := range start.Attr {
+		var prefix string
+		switch {
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			prefix = ""
+		case attr.Name.Space == "xmlns":
+			if attr.Name.Local == "xml" || attr.Name.Local == "xmlns" || attr.Value == "" {
+				return nil, fmt.Errorf("invalid namespace declaration for %q", attr.Name.Local)
+			}
+			prefix = attr.Name.Local
+		default:
+			continue
+		}
+		if !copied {
+			scope := make(map[string]string, len(el.scope)+1)
+			for k, v := range el.scope {
+				scope[k] = v
+			}
+			el.scope = scope
+			copied = true
+		}
+		el.scope[prefix] = attr.Value
+	}
+
+	space, err := el.lookup(el.prefix)
+	if err != nil {
+		return nil, err
+	}
+	el.space = space
+
+	seen := make(map[string]bool)
+	for _, attr := range start.Attr {
+		if (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == "xmlns" {
+			continue
+		}
+		a := attribute{prefix: attr.Name.Space, local: attr.Name.Local, value: attr.Value}
+		if a.prefix != "" {
+			if a.space, err = el.lookup(a.prefix); err != nil {
+				return nil, err
+			}
+		}
+		key := a.space + " " + a.local
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate attribute %s", a.local)
+		}
+		seen[key] = true
+		el.attrs = append(el.attrs, a)
+	}
+
+	return el, nil
+}
+
+func (e *element) lookup(prefix string) (string, error) {
+	if prefix == "xml" {
+		return xmlNamespace, nil
+	}
+	space, ok := e.scope[prefix]
+	if !ok {
+		return "", fmt.Errorf("undeclared namespace prefix %q", prefix)
+	}
+	return space, nil
+}
+
+func (e *element) is(space, local string) bool {
+	return e.space == space && e.local == local
+}
+
+// attr returns the value of an attribute in no namespace.
+func (e *element) attr(local string) string {
+	for _, a := range e.attrs {
+		if a.space == "" && a.local == local {
+			return a.value
+		}
+	}
+	return ""
+}
+
+func (e *element) hasAttr(local string) bool {
+	for _, a := range e.attrs {
+		if a.space == "" && a.local == local {
+			return true
+		}
+	}
+	return false
+}
+
+// childElements returns the child elements with the given name.
+func (e *element) childElements(space, local string) []*element {
+	var found []*element
+	for _, child := range e.children {
+		if el, ok := child.(*element); ok && el.is(space, local) {
+			found = append(found, el)
+		}
+	}
+	return found
+}
+
+// child returns the only child element with the given name, or nil when
+// there is none or more than one.
+func (e *element) child(space, local string) *element {
+	found := e.childElements(space, local)
+	if len(found) != 1 {
+		return nil
+	}
+	return found[0]
+}
+
+// text returns the element's text content, ignoring child elements.
+func (e *element) text() string {
+	var b strings.Builder
+	for _, child := range e.children {
+		if text, ok := child.(charData); ok {
+			b.WriteString(string(text))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// walk calls fn for the element and each of its descendants.
+func (e *element) walk(fn func(*element)) {
+	fn(e)
+	for _, child := range e.children {
+		if el, ok := child.(*element); ok {
+			el.walk(fn)
+		}
+	}
+}
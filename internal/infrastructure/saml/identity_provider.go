@@ -0,0 +1,384 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	metadataNamespace  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	bindingHTTPPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess       = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confirmationBearer  = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	nameIDPersistent    = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	nameIDTransient     = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+	nameIDEmailAddress  = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	nameIDUnspecified   = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	samlVersion         = "2.0"
+	maxSAMLResponseSize = 1 << 20
+	maxIdentifierLength = 255
+)
+
+// ErrInvalidResponse is returned for responses that fail to decode, do
+// not report success, or whose assertion does not check out.
+var ErrInvalidResponse = errors.New("invalid SAML response")
+
+// IdentityProviderConfig describes a SAML identity provider users can
+// sign in with, and this service as the service provider it knows.
+type IdentityProviderConfig struct {
+	ID   string
+	Name string
+	// EntityID is the identity provider's, which must issue its responses
+	// and assertions.
+	EntityID string
+	SSOURL   string
+	// Certificates hold the keys the identity provider signs with; more
+	// than one lets it roll its key over.
+	Certificates []*x509.Certificate
+	// UsernameAttributes and EmailAttributes name the attributes the
+	// username and email are read from, first match winning.
+	UsernameAttributes []string
+	EmailAttributes    []string
+	// AllowIdPInitiated accepts responses the identity provider sends
+	// without a request of ours.
+	AllowIdPInitiated bool
+	LinkByEmail       bool
+	// SPEntityID and ACSURL identify this service to the identity
+	// provider; assertions must name them as audience and recipient.
+	SPEntityID string
+	ACSURL     string
+	// ClockSkew is how far the identity provider's clock may be off.
+	ClockSkew time.Duration
+}
+
+// IdentityProvider is the service provider side of Web Browser SSO
+// against one identity provider: requests are sent and responses received
+// with the HTTP-POST binding, and only signed bearer assertions are
+// accepted.
+type IdentityProvider struct {
+	cfg IdentityProviderConfig
+}
+
+func NewIdentityProvider(cfg IdentityProviderConfig) *IdentityProvider {
+	return &IdentityProvider{cfg: cfg}
+}
+
+func (p *IdentityProvider) ID() string {
+	return p.cfg.ID
+}
+
+func (p *IdentityProvider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *IdentityProvider) LinksByEmail() bool {
+	return p.cfg.LinkByEmail
+}
+
+func (p *IdentityProvider) AllowsIdPInitiated() bool {
+	return p.cfg.AllowIdPInitiated
+}
+
+func (p *IdentityProvider) AuthnRequest(id string, issuedAt time.Time) (*port.SAMLAuthnRequest, error) {
+	request := `<samlp:AuthnRequest xmlns:samlp="` + protocolNamespace + `" xmlns:saml="` + assertionNamespace + `"` +
+		` ID="` + escapeAttr(id) + `" Version="` + samlVersion + `" IssueInstant="` + formatTime(issuedAt) + `"` +
+		` Destination="` + escapeAttr(p.cfg.SSOURL) + `" AssertionConsumerServiceURL="` + escapeAttr(p.cfg.ACSURL) + `"` +
+		` ProtocolBinding="` + bindingHTTPPOST + `">` +
+		`<saml:Issuer>` + escapeText(p.cfg.SPEntityID) + `</saml:Issuer>` +
+		`<samlp:NameIDPolicy AllowCreate="true"></samlp:NameIDPolicy>` +
+		`</samlp:AuthnRequest>`
+
+	return &port.SAMLAuthnRequest{
+		URL:         p.cfg.SSOURL,
+		SAMLRequest: base64.StdEncoding.EncodeToString([]byte(request)),
+	}, nil
+}
+
+func (p *IdentityProvider) Metadata() []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<md:EntityDescriptor xmlns:md="` + metadataNamespace + `" entityID="` + escapeAttr(p.cfg.SPEntityID) + `">` +
+		`<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="` + protocolNamespace + `">` +
+		`<md:NameIDFormat>` + nameIDPersistent + `</md:NameIDFormat>` +
+		`<md:NameIDFormat>` + nameIDEmailAddress + `</md:NameIDFormat>` +
+		`<md:AssertionConsumerService Binding="` + bindingHTTPPOST + `" Location="` + escapeAttr(p.cfg.ACSURL) + `" index="0" isDefault="true"></md:AssertionConsumerService>` +
+		`</md:SPSSODescriptor>` +
+		`</md:EntityDescriptor>` + "\n")
+}
+
+// ParseResponse accepts a response carrying exactly one assertion, signed
+// itself or through the signed response around it, by a configured
+// certificate. Only the verified elements are read from, and IDs must be
+// unique in the document, so content wrapped around a signed element
+// cannot stand in for it.
+func (p *IdentityProvider) ParseResponse(samlResponse string, now time.Time) (*port.SAMLAssertion, error) {
+	if len(samlResponse) > maxSAMLResponseSize {
+		return nil, fmt.Errorf("%w: response too large", ErrInvalidResponse)
+	}
+	data, err := decodeBase64(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	response, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := uniqueIDs(response); err != nil {
+		return nil, err
+	}
+
+	if !response.is(protocolNamespace, "Response") || response.attr("Version") != samlVersion {
+		return nil, fmt.Errorf("%w: not a SAML 2.0 response", ErrInvalidResponse)
+	}
+	if destination := response.attr("Destination"); destination != "" && destination != p.cfg.ACSURL {
+		return nil, fmt.Errorf("%w: destination %q is not the assertion consumer service", ErrInvalidResponse, destination)
+	}
+	if issuer := response.child(assertionNamespace, "Issuer"); issuer != nil && issuer.text() != p.cfg.EntityID {
+		return nil, fmt.Errorf("%w: response issued by %q", ErrInvalidResponse, issuer.text())
+	}
+	if code := statusCode(response); code != statusSuccess {
+		return nil, fmt.Errorf("%w: status %q", ErrInvalidResponse, code)
+	}
+
+	if len(response.childElements(assertionNamespace, "EncryptedAssertion")) > 0 {
+		return nil, fmt.Errorf("%w: encrypted assertions are not supported", ErrInvalidResponse)
+	}
+	assertions := response.childElements(assertionNamespace, "Assertion")
+	if len(assertions) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one assertion, got %d", ErrInvalidResponse, len(assertions))
+	}
+	assertion := assertions[0]
+
+	responseSigned := signature(response) != nil
+	if responseSigned {
+		if err := verifySignature(response, p.cfg.Certificates); err != nil {
+			return nil, fmt.Errorf("%w: response: %v", ErrInvalidResponse, err)
+		}
+	}
+	if signature(assertion) != nil {
+		if err := verifySignature(assertion, p.cfg.Certificates); err != nil {
+			return nil, fmt.Errorf("%w: assertion: %v", ErrInvalidResponse, err)
+		}
+	} else if !responseSigned {
+		return nil, fmt.Errorf("%w: neither the response nor the assertion is signed", ErrInvalidResponse)
+	}
+
+	parsed, err := p.readAssertion(assertion, now)
+	if err != nil {
+		return nil, err
+	}
+	// Unsigned, the response's InResponseTo could be anything.
+	if inResponseTo := response.attr("InResponseTo"); responseSigned && inResponseTo != "" {
+		if parsed.InResponseTo != "" && parsed.InResponseTo != inResponseTo {
+			return nil, fmt.Errorf("%w: response and assertion answer different requests", ErrInvalidResponse)
+		}
+		parsed.InResponseTo = inResponseTo
+	}
+	return parsed, nil
+}
+
+func (p *IdentityProvider) readAssertion(assertion *element, now time.Time) (*port.SAMLAssertion, error) {
+	id := assertion.attr("ID")
+	if id == "" || len(id) > maxIdentifierLength || assertion.attr("Version") != samlVersion {
+		return nil, fmt.Errorf("%w: not a SAML 2.0 assertion", ErrInvalidResponse)
+	}
+	issuer := assertion.child(assertionNamespace, "Issuer")
+	if issuer == nil || issuer.text() != p.cfg.EntityID {
+		return nil, fmt.Errorf("%w: assertion not issued by the identity provider", ErrInvalidResponse)
+	}
+	issuedAt, err := parseTime(assertion.attr("IssueInstant"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: IssueInstant: %v", ErrInvalidResponse, err)
+	}
+	if issuedAt.After(now.Add(p.cfg.ClockSkew)) {
+		return nil, fmt.Errorf("%w: assertion issued in the future", ErrInvalidResponse)
+	}
+
+	conditionsEnd, err := p.checkConditions(assertion.child(assertionNamespace, "Conditions"), now)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := assertion.child(assertionNamespace, "Subject")
+	if subject == nil {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidResponse)
+	}
+	nameID := subject.child(assertionNamespace, "NameID")
+	if nameID == nil || nameID.text() == "" || len(nameID.text()) > maxIdentifierLength {
+		return nil, fmt.Errorf("%w: missing or oversized NameID", ErrInvalidResponse)
+	}
+	format := nameID.attr("Format")
+	if format == nameIDTransient {
+		return nil, fmt.Errorf("%w: transient NameIDs cannot identify returning users", ErrInvalidResponse)
+	}
+
+	inResponseTo, confirmedUntil, err := p.checkConfirmation(subject, now)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := confirmedUntil
+	if !conditionsEnd.IsZero() && conditionsEnd.Before(expiresAt) {
+		expiresAt = conditionsEnd
+	}
+
+	identity := port.ExternalIdentity{
+		Subject:           nameID.text(),
+		Email:             attributeValue(assertion, p.cfg.EmailAttributes),
+		PreferredUsername: attributeValue(assertion, p.cfg.UsernameAttributes),
+	}
+	if identity.Email == "" && (format == nameIDEmailAddress || format == nameIDUnspecified && strings.Contains(identity.Subject, "@")) {
+		identity.Email = identity.Subject
+	}
+	// The identity provider vouches for the directory it asserts from.
+	identity.EmailVerified = identity.Email != ""
+
+	return &port.SAMLAssertion{
+		ID:           id,
+		InResponseTo: inResponseTo,
+		ExpiresAt:    expiresAt.Add(p.cfg.ClockSkew),
+		Identity:     identity,
+	}, nil
+}
+
+// checkConditions checks the validity window and that every audience
+// restriction names this service, and returns when the conditions lapse,
+// zero when they set no end.
+func (p *IdentityProvider) checkConditions(conditions *element, now time.Time) (time.Time, error) {
+	if conditions == nil {
+		return time.Time{}, fmt.Errorf("%w: no conditions", ErrInvalidResponse)
+	}
+	notOnOrAfter, err := p.checkWindow(conditions, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	restrictions := conditions.childElements(assertionNamespace, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return time.Time{}, fmt.Errorf("%w: no audience restriction", ErrInvalidResponse)
+	}
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range restriction.childElements(assertionNamespace, "Audience") {
+			if audience.text() == p.cfg.SPEntityID {
+				found = true
+			}
+		}
+		if !found {
+			return time.Time{}, fmt.Errorf("%w: assertion is meant for another audience", ErrInvalidResponse)
+		}
+	}
+	return notOnOrAfter, nil
+}
+
+// checkConfirmation looks for a bearer confirmation addressed to the
+// assertion consumer service that is still valid, and returns the request
+// it answers and when it lapses.
+func (p *IdentityProvider) checkConfirmation(subject *element, now time.Time) (string, time.Time, error) {
+	for _, confirmation := range subject.childElements(assertionNamespace, "SubjectConfirmation") {
+		if confirmation.attr("Method") != confirmationBearer {
+			continue
+		}
+		data := confirmation.child(assertionNamespace, "SubjectConfirmationData")
+		if data == nil || data.attr("Recipient") != p.cfg.ACSURL {
+			continue
+		}
+		notOnOrAfter, err := p.checkWindow(data, now)
+		if err != nil || notOnOrAfter.IsZero() {
+			continue
+		}
+		return data.attr("InResponseTo"), notOnOrAfter, nil
+	}
+	return "", time.Time{}, fmt.Errorf("%w: no valid bearer confirmation for this service", ErrInvalidResponse)
+}
+
+// checkWindow checks now, give or take the clock skew, is within the
+// element's NotBefore and NotOnOrAfter, either of which may be absent,
+// and returns NotOnOrAfter.
+func (p *IdentityProvider) checkWindow(el *element, now time.Time) (time.Time, error) {
+	if el.hasAttr("NotBefore") {
+		notBefore, err := parseTime(el.attr("NotBefore"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: NotBefore: %v", ErrInvalidResponse, err)
+		}
+		if now.Add(p.cfg.ClockSkew).Before(notBefore) {
+			return time.Time{}, fmt.Errorf("%w: not valid yet", ErrInvalidResponse)
+		}
+	}
+	if !el.hasAttr("NotOnOrAfter") {
+		return time.Time{}, nil
+	}
+	notOnOrAfter, err := parseTime(el.attr("NotOnOrAfter"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: NotOnOrAfter: %v", ErrInvalidResponse, err)
+	}
+	if !now.Add(-p.cfg.ClockSkew).Before(notOnOrAfter) {
+		return time.Time{}, fmt.Errorf("%w: expired", ErrInvalidResponse)
+	}
+	return notOnOrAfter, nil
+}
+
+// attributeValue returns the first value of the first of the named
+// attributes the assertion carries, matching names or friendly names.
+func attributeValue(assertion *element, names []string) string {
+	for _, name := range names {
+		for _, statement := range assertion.childElements(assertionNamespace, "AttributeStatement") {
+			for _, attribute := range statement.childElements(assertionNamespace, "Attribute") {
+				if attribute.attr("Name") != name && attribute.attr("FriendlyName") != name {
+					continue
+				}
+				for _, value := range attribute.childElements(assertionNamespace, "AttributeValue") {
+					if text := value.text(); text != "" {
+						return text
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func statusCode(response *element) string {
+	status := response.child(protocolNamespace, "Status")
+	if status == nil {
+		return ""
+	}
+	code := status.child(protocolNamespace, "StatusCode")
+	if code == nil {
+		return ""
+	}
+	return code.attr("Value")
+}
+
+// uniqueIDs refuses documents where two elements share an ID, which would
+// make what a signature reference points at ambiguous.
+func uniqueIDs(root *element) error {
+	seen := make(map[string]bool)
+	var err error
+	root.walk(func(el *element) {
+		id := el.attr("ID")
+		if id == "" || err != nil {
+			return
+		}
+		if seen[id] {
+			err = fmt.Errorf("%w: duplicate ID %q", ErrInvalidResponse, id)
+		}
+		seen[id] = true
+	})
+	return err
+}
+
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,21 @@
+package saml
+
+import "github.com/thanhnamdk2710/auth-service/internal/application/port"
+
+// Registry holds the configured SAML identity providers.
+type Registry struct {
+	providers []*IdentityProvider
+}
+
+func NewRegistry(providers ...*IdentityProvider) *Registry {
+	return &Registry{providers: providers}
+}
+
+func (r *Registry) Find(id string) port.SAMLIdentityProvider {
+	for _, provider := range r.providers {
+		if provider.ID() == id {
+			return provider
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	dsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	algExcC14N             = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnvelopedSignature  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA256           = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algRSASHA512           = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	algECDSASHA256         = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	algSHA256              = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algSHA512              = "http://www.w3.org/2001/04/xmlenc#sha512"
+	inclusiveNamespacesTag = "InclusiveNamespaces"
+)
+
+// ErrInvalidSignature is returned when an element's signature is missing,
+// malformed, uses an algorithm that is not accepted, or does not verify.
+var ErrInvalidSignature = errors.New("invalid XML signature")
+
+var digestAlgorithms = map[string]crypto.Hash{
+	algSHA256: crypto.SHA256,
+	algSHA512: crypto.SHA512,
+}
+
+var signatureAlgorithms = map[string]crypto.Hash{
+	algRSASHA256:   crypto.SHA256,
+	algRSASHA512:   crypto.SHA512,
+	algECDSASHA256: crypto.SHA256,
+}
+
+// signature returns the element's enveloped signature, or nil when it is
+// not signed.
+func signature(el *element) *element {
+	return el.child(dsigNamespace, "Signature")
+}
+
+// verifySignature checks the enveloped signature of el against the
+// trusted certificates. The signature must reference el by its ID and
+// nothing else, with the enveloped signature and exclusive
+// canonicalization transforms only, so what it covers is exactly the
+// element the caller goes on to read. SHA-1 is not accepted, and key
+// material in the signature itself is ignored.
+func verifySignature(el *element, certificates []*x509.Certificate) error {
+	sig := signature(el)
+	if sig == nil {
+		return fmt.Errorf("%w: element is not signed", ErrInvalidSignature)
+	}
+	id := el.attr("ID")
+	if id == "" {
+		return fmt.Errorf("%w: signed element has no ID", ErrInvalidSignature)
+	}
+
+	signedInfo := sig.child(dsigNamespace, "SignedInfo")
+	if signedInfo == nil {
+		return fmt.Errorf("%w: no SignedInfo", ErrInvalidSignature)
+	}
+	c14nMethod := signedInfo.child(dsigNamespace, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != algExcC14N {
+		return fmt.Errorf("%w: unsupported canonicalization", ErrInvalidSignature)
+	}
+	sigMethod := signedInfo.child(dsigNamespace, "SignatureMethod")
+	if sigMethod == nil {
+		return fmt.Errorf("%w: no SignatureMethod", ErrInvalidSignature)
+	}
+	sigHash, ok := signatureAlgorithms[sigMethod.attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("%w: unsupported signature algorithm %q", ErrInvalidSignature, sigMethod.attr("Algorithm"))
+	}
+
+	reference := signedInfo.child(dsigNamespace, "Reference")
+	if reference == nil {
+		return fmt.Errorf("%w: expected exactly one Reference", ErrInvalidSignature)
+	}
+	if reference.attr("URI") != "#"+id {
+		return fmt.Errorf("%w: reference does not point at the signed element", ErrInvalidSignature)
+	}
+	prefixes, err := referenceTransforms(reference)
+	if err != nil {
+		return err
+	}
+
+	digestMethod := reference.child(dsigNamespace, "DigestMethod")
+	if digestMethod == nil {
+		return fmt.Errorf("%w: no DigestMethod", ErrInvalidSignature)
+	}
+	digestHash, ok := digestAlgorithms[digestMethod.attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("%w: unsupported digest algorithm %q", ErrInvalidSignature, digestMethod.attr("Algorithm"))
+	}
+	digestValue := reference.child(dsigNamespace, "DigestValue")
+	if digestValue == nil {
+		return fmt.Errorf("%w: no DigestValue", ErrInvalidSignature)
+	}
+	wantDigest, err := decodeBase64(digestValue.text())
+	if err != nil {
+		return fmt.Errorf("%w: malformed DigestValue", ErrInvalidSignature)
+	}
+
+	h := digestHash.New()
+	h.Write(canonicalize(el, prefixes, sig))
+	if subtle.ConstantTimeCompare(h.Sum(nil), wantDigest) != 1 {
+		return fmt.Errorf("%w: digest mismatch", ErrInvalidSignature)
+	}
+
+	signatureValue := sig.child(dsigNamespace, "SignatureValue")
+	if signatureValue == nil {
+		return fmt.Errorf("%w: no SignatureValue", ErrInvalidSignature)
+	}
+	sigBytes, err := decodeBase64(signatureValue.text())
+	if err != nil {
+		return fmt.Errorf("%w: malformed SignatureValue", ErrInvalidSignature)
+	}
+
+	h = sigHash.New()
+	h.Write(canonicalize(signedInfo, inclusivePrefixes(c14nMethod), nil))
+	hashed := h.Sum(nil)
+	for _, cert := range certificates {
+		if verifyWithKey(cert.PublicKey, sigHash, hashed, sigBytes) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature does not match a trusted certificate", ErrInvalidSignature)
+}
+
+// referenceTransforms checks the reference's transforms are the enveloped
+// signature transform followed by exclusive canonicalization, and returns
+// the canonicalization's inclusive namespace prefixes.
+func referenceTransforms(reference *element) ([]string, error) {
+	transforms := reference.child(dsigNamespace, "Transforms")
+	if transforms == nil {
+		return nil, fmt.Errorf("%w: no Transforms", ErrInvalidSignature)
+	}
+	list := transforms.childElements(dsigNamespace, "Transform")
+	if len(list) != 2 || list[0].attr("Algorithm") != algEnvelopedSignature || list[1].attr("Algorithm") != algExcC14N {
+		return nil, fmt.Errorf("%w: unsupported transforms", ErrInvalidSignature)
+	}
+	return inclusivePrefixes(list[1]), nil
+}
+
+func inclusivePrefixes(method *element) []string {
+	inclusive := method.child(algExcC14N, inclusiveNamespacesTag)
+	if inclusive == nil {
+		return nil
+	}
+	return strings.Fields(inclusive.attr("PrefixList"))
+}
+
+func verifyWithKey(key crypto.PublicKey, hash crypto.Hash, hashed, sig []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, hash, hashed, sig) == nil
+	case *ecdsa.PublicKey:
+		// XML signatures carry ECDSA signatures as r and s concatenated.
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(key, hashed, r, s)
+	}
+	return false
+}
+
+// decodeBase64 decodes base64 that may be wrapped over several lines.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,194 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// minClientRSAKeyBits is the smallest RSA key a client may register.
+const minClientRSAKeyBits = 2048
+
+var ErrInvalidKeySet = errors.New("invalid JWK set")
+
+// clientKey is a public key from a client's JWK Set.
+type clientKey struct {
+	id        string
+	algorithm string
+	public    crypto.PublicKey
+}
+
+// ClientAssertionVerifier verifies private_key_jwt client assertions
+// against the JWK Set a client registered.
+type ClientAssertionVerifier struct {
+	now func() time.Time
+}
+
+func NewClientAssertionVerifier() *ClientAssertionVerifier {
+	return &ClientAssertionVerifier{now: time.Now}
+}
+
+func (v *ClientAssertionVerifier) ValidateKeySet(jwks string) error {
+	_, err := parseClientKeys(jwks)
+	return err
+}
+
+func (v *ClientAssertionVerifier) Subject(assertion string) (string, error) {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, &claims); err != nil {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+func (v *ClientAssertionVerifier) Verify(assertion, jwks string) (*port.ClientAssertion, error) {
+	keys, err := parseClientKeys(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.RegisteredClaims
+	_, err = jwt.ParseWithClaims(assertion, &claims,
+		clientVerificationKeys(keys),
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &port.ClientAssertion{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		ID:        claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// clientVerificationKeys offers every key matching the token's kid, or
+// every key when it names none, that is usable with the token's alg.
+func clientVerificationKeys(keys []clientKey) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		var set jwt.VerificationKeySet
+		for _, key := range keys {
+			if (kid == "" || key.id == kid) && key.algorithm == t.Method.Alg() {
+				set.Keys = append(set.Keys, key.public)
+			}
+		}
+		if len(set.Keys) == 0 {
+			return nil, ErrInvalidToken
+		}
+		return set, nil
+	}
+}
+
+// parseClientKeys accepts RSA keys of at least 2048 bits, P-256 keys and
+// Ed25519 keys. A key's alg, when given, must be the one its type is used
+// with here, and its use, when given, must be sig.
+func parseClientKeys(jwks string) ([]clientKey, error) {
+	var set struct {
+		Keys []port.JSONWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(jwks), &set); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeySet, err)
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrInvalidKeySet)
+	}
+
+	keys := make([]clientKey, 0, len(set.Keys))
+	for i, jwk := range set.Keys {
+		key, err := parseClientKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %d: %v", ErrInvalidKeySet, i, err)
+		}
+		if jwk.Use != "" && jwk.Use != "sig" {
+			return nil, fmt.Errorf("%w: key %d: use must be sig", ErrInvalidKeySet, i)
+		}
+		if jwk.Algorithm != "" && jwk.Algorithm != key.algorithm {
+			return nil, fmt.Errorf("%w: key %d: alg %s does not suit a %s key", ErrInvalidKeySet, i, jwk.Algorithm, jwk.KeyType)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseClientKey(jwk port.JSONWebKey) (clientKey, error) {
+	key := clientKey{id: jwk.KeyID}
+
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return key, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return key, errors.New("invalid exponent")
+		}
+		if n.BitLen() < minClientRSAKeyBits {
+			return key, fmt.Errorf("RSA keys must have at least %d bits", minClientRSAKeyBits)
+		}
+		key.algorithm = entity.SigningAlgRS256
+		key.public = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return key, errors.New("EC keys must use the P-256 curve")
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return key, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return key, err
+		}
+		if x.BitLen() > 256 || y.BitLen() > 256 {
+			return key, errors.New("point is not on the curve")
+		}
+		point := append([]byte{4}, x.FillBytes(make([]byte, 32))...)
+		point = append(point, y.FillBytes(make([]byte, 32))...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return key, errors.New("point is not on the curve")
+		}
+		key.algorithm = entity.SigningAlgES256
+		key.public = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if jwk.Curve != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return key, errors.New("OKP keys must be Ed25519 keys")
+		}
+		key.algorithm = entity.SigningAlgEdDSA
+		key.public = ed25519.PublicKey(x)
+	default:
+		return key, fmt.Errorf("unsupported key type %q", jwk.KeyType)
+	}
+
+	return key, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,148 @@
+package token
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// backchannelLogoutEvent is the event a logout token carries, as
+// OpenID Connect Back-Channel Logout section 2.4 names it.
+const backchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// logoutTokenTTL is short: a logout token is sent right after it is
+// signed, and a fresh one is signed for every retry.
+const logoutTokenTTL = 2 * time.Minute
+
+type idTokenHintClaims struct {
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IDTokenSigner signs OpenID Connect ID tokens and logout tokens with the
+// current key from keys, and verifies the ID tokens it signed when clients
+// send them back.
+type IDTokenSigner struct {
+	keys   KeyProvider
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewIDTokenSigner(keys KeyProvider, issuer string, ttl time.Duration) *IDTokenSigner {
+	return &IDTokenSigner{
+		keys:   keys,
+		issuer: issuer,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (s *IDTokenSigner) SignIDToken(claims port.IDTokenClaims) (string, error) {
+	key, err := s.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+
+	mapClaims := jwt.MapClaims{}
+	for name, value := range claims.UserClaims {
+		mapClaims[name] = value
+	}
+	mapClaims["iss"] = s.issuer
+	mapClaims["sub"] = claims.Subject
+	mapClaims["aud"] = claims.Audience
+	mapClaims["iat"] = now.Unix()
+	mapClaims["exp"] = now.Add(s.ttl).Unix()
+	if !claims.AuthTime.IsZero() {
+		mapClaims["auth_time"] = claims.AuthTime.Unix()
+	}
+	if claims.Nonce != "" {
+		mapClaims["nonce"] = claims.Nonce
+	}
+	if claims.AccessToken != "" {
+		mapClaims["at_hash"] = accessTokenHash(key.Algorithm, claims.AccessToken)
+	}
+	if claims.SessionID != "" {
+		mapClaims["sid"] = claims.SessionID
+	}
+
+	token := jwt.NewWithClaims(key.Method, mapClaims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+// SignLogoutToken signs the logout token of OpenID Connect Back-Channel
+// Logout section 2.4. It never carries a nonce, which tells it apart from
+// an ID token.
+func (s *IDTokenSigner) SignLogoutToken(claims port.LogoutTokenClaims) (string, error) {
+	key, err := s.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+
+	mapClaims := jwt.MapClaims{
+		"iss":    s.issuer,
+		"aud":    claims.Audience,
+		"iat":    now.Unix(),
+		"exp":    now.Add(logoutTokenTTL).Unix(),
+		"jti":    uuid.NewString(),
+		"events": map[string]interface{}{backchannelLogoutEvent: map[string]interface{}{}},
+	}
+	if claims.Subject != "" {
+		mapClaims["sub"] = claims.Subject
+	}
+	if claims.SessionID != "" {
+		mapClaims["sid"] = claims.SessionID
+	}
+
+	token := jwt.NewWithClaims(key.Method, mapClaims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = "logout+jwt"
+
+	return token.SignedString(key.Private)
+}
+
+// VerifyIDTokenHint checks the signature and issuer of an ID token but
+// not its lifetime.
+func (s *IDTokenSigner) VerifyIDTokenHint(tokenString string) (*port.IDTokenHint, error) {
+	var claims idTokenHintClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, verificationKey(s.keys),
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil || claims.Issuer != s.issuer || claims.Subject == "" || len(claims.Audience) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	return &port.IDTokenHint{
+		Subject:   claims.Subject,
+		Audience:  claims.Audience[0],
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// accessTokenHash is the at_hash claim: the left half of the access token
+// hashed with the signature's hash function, SHA-512 for Ed25519.
+func accessTokenHash(algorithm, accessToken string) string {
+	var sum []byte
+	if algorithm == entity.SigningAlgEdDSA {
+		digest := sha512.Sum512([]byte(accessToken))
+		sum = digest[:]
+	} else {
+		digest := sha256.Sum256([]byte(accessToken))
+		sum = digest[:]
+	}
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
@@ -0,0 +1,176 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// accessTokenType is the typ header of RFC 9068 that tells access tokens
+// apart from the ID and logout tokens signed with the same keys.
+const accessTokenType = "at+jwt"
+
+type accessTokenClaims struct {
+	Username string       `json:"username,omitempty"`
+	Roles    []string     `json:"roles,omitempty"`
+	ClientID string       `json:"client_id,omitempty"`
+	Scope    string       `json:"scope,omitempty"`
+	Act      *actorClaims `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// actorClaims is the act claim of RFC 8693 section 4.1.
+type actorClaims struct {
+	Subject  string       `json:"sub"`
+	ClientID string       `json:"client_id,omitempty"`
+	Act      *actorClaims `json:"act,omitempty"`
+}
+
+// JWTService issues access tokens signed with the current key from keys.
+type JWTService struct {
+	keys     KeyProvider
+	issuer   string
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func NewJWTService(cfg *config.JWTConfig, keys KeyProvider) *JWTService {
+	return &JWTService{
+		keys:     keys,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		ttl:      cfg.AccessTokenTTL,
+		now:      time.Now,
+	}
+}
+
+// GenerateAccessToken uses the client ID as the subject of a token that
+// was not issued for a user.
+func (s *JWTService) GenerateAccessToken(claims port.AccessTokenClaims) (string, time.Time, error) {
+	now := s.now().UTC()
+	ttl := s.ttl
+	if claims.TTL > 0 {
+		ttl = claims.TTL
+	}
+	expiresAt := now.Add(ttl)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt
+	}
+
+	subject := claims.UserID
+	if subject == "" {
+		subject = claims.ClientID
+	}
+	audience := claims.Audience
+	if len(audience) == 0 {
+		audience = []string{s.audience}
+	}
+
+	signed, _, err := sign(s.keys, accessTokenType, accessTokenClaims{
+		Username: claims.Username,
+		Roles:    claims.Roles,
+		ClientID: claims.ClientID,
+		Scope:    strings.Join(claims.Scopes, " "),
+		Act:      toActorClaims(claims.Actor),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   subject,
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings(audience),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (s *JWTService) ParseAccessToken(tokenString string) (*port.AccessTokenClaims, error) {
+	return s.parse(tokenString, jwt.WithAudience(s.audience))
+}
+
+// InspectAccessToken verifies a token this service issued to a client for
+// any audience, such as one obtained by token exchange for another
+// service. Tokens from a first-party login carry no client_id and are only
+// meant for this service's own API.
+func (s *JWTService) InspectAccessToken(tokenString string) (*port.AccessTokenClaims, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ClientID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *JWTService) parse(tokenString string, opts ...jwt.ParserOption) (*port.AccessTokenClaims, error) {
+	var claims accessTokenClaims
+
+	opts = append(opts,
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	token, err := jwt.ParseWithClaims(tokenString, &claims, verificationKey(s.keys), opts...)
+	if err != nil || !isAccessTokenType(token.Header["typ"]) || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	userID := claims.Subject
+	if claims.ClientID != "" && userID == claims.ClientID {
+		userID = ""
+	}
+
+	parsed := &port.AccessTokenClaims{
+		UserID:    userID,
+		Username:  claims.Username,
+		Roles:     claims.Roles,
+		ClientID:  claims.ClientID,
+		Scopes:    strings.Fields(claims.Scope),
+		Audience:  claims.Audience,
+		Actor:     fromActorClaims(claims.Act),
+		ID:        claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		parsed.IssuedAt = claims.IssuedAt.Time
+	}
+	return parsed, nil
+}
+
+// isAccessTokenType accepts the typ header in either of the forms RFC 9068
+// allows.
+func isAccessTokenType(typ interface{}) bool {
+	s, _ := typ.(string)
+	return strings.TrimPrefix(strings.ToLower(s), "application/") == accessTokenType
+}
+
+func toActorClaims(actor *entity.TokenActor) *actorClaims {
+	if actor == nil {
+		return nil
+	}
+	return &actorClaims{Subject: actor.Subject, ClientID: actor.ClientID, Act: toActorClaims(actor.Actor)}
+}
+
+func fromActorClaims(act *actorClaims) *entity.TokenActor {
+	if act == nil {
+		return nil
+	}
+	return &entity.TokenActor{Subject: act.Subject, ClientID: act.ClientID, Actor: fromActorClaims(act.Act)}
+}
@@ -0,0 +1,47 @@
+package token
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+)
+
+// KeyProvider supplies the key tokens are signed with and looks up the
+// key a token names in its kid header.
+type KeyProvider interface {
+	SigningKey() (*keystore.Key, error)
+	VerificationKey(kid string) (*keystore.Key, bool)
+}
+
+var signingAlgorithms = []string{entity.SigningAlgRS256, entity.SigningAlgES256, entity.SigningAlgEdDSA}
+
+func sign(keys KeyProvider, typ string, claims jwt.Claims) (string, *keystore.Key, error) {
+	key, err := keys.SigningKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = typ
+
+	signed, err := token.SignedString(key.Private)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, key, nil
+}
+
+// verificationKey resolves the public key for a token from its kid,
+// refusing a token whose alg differs from the key's.
+func verificationKey(keys KeyProvider) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keys.VerificationKey(kid)
+		if !ok || t.Method.Alg() != key.Algorithm {
+			return nil, ErrInvalidToken
+		}
+		return key.Public, nil
+	}
+}
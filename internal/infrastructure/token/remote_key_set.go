@@ -0,0 +1,59 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+// RemoteKeySet holds the signing keys an upstream identity provider
+// publishes. Unlike the JWK Sets clients register, a provider's set may
+// hold keys for other uses or algorithms; those are skipped rather than
+// rejected.
+type RemoteKeySet struct {
+	keys []clientKey
+}
+
+func ParseRemoteKeySet(data []byte) (*RemoteKeySet, error) {
+	var set struct {
+		Keys []port.JSONWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeySet, err)
+	}
+
+	keys := make([]clientKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseClientKey(jwk)
+		if err != nil || (jwk.Algorithm != "" && jwk.Algorithm != key.algorithm) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no usable signing keys", ErrInvalidKeySet)
+	}
+	return &RemoteKeySet{keys: keys}, nil
+}
+
+// Has reports whether the set holds a key with the given key ID.
+func (s *RemoteKeySet) Has(kid string) bool {
+	for _, key := range s.keys {
+		if key.id == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// Keyfunc selects the keys a token may be verified with by its kid and
+// alg headers.
+func (s *RemoteKeySet) Keyfunc() jwt.Keyfunc {
+	return clientVerificationKeys(s.keys)
+}
@@ -8,6 +8,7 @@ func NewGenerator() *Generator {
 	return &Generator{}
 }
 
+// Generate returns a UUID v7, which vo.UserID requires.
 func (g *Generator) Generate() string {
-	return uuid.New().String()
+	return uuid.Must(uuid.NewV7()).String()
 }
@@ -0,0 +1,89 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagBackupEligible   = 0x08
+	flagBackupState      = 0x10
+	flagAttestedCredData = 0x40
+	flagExtensionData    = 0x80
+
+	rpIDHashLength  = 32
+	aaguidLength    = 16
+	minAuthDataSize = rpIDHashLength + 1 + 4
+	maxCredentialID = 1023
+)
+
+var errAuthenticatorData = errors.New("malformed authenticator data")
+
+type authenticatorData struct {
+	raw       []byte
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+
+	// Set only when the attested credential data flag is present.
+	aaguid       []byte
+	credentialID []byte
+	publicKey    *publicKey
+	publicKeyRaw []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < minAuthDataSize {
+		return nil, fmt.Errorf("%w: too short", errAuthenticatorData)
+	}
+
+	ad := &authenticatorData{
+		raw:       data,
+		rpIDHash:  data[:rpIDHashLength],
+		flags:     data[rpIDHashLength],
+		signCount: binary.BigEndian.Uint32(data[rpIDHashLength+1:]),
+	}
+	rest := data[minAuthDataSize:]
+
+	if ad.has(flagAttestedCredData) {
+		if len(rest) < aaguidLength+2 {
+			return nil, fmt.Errorf("%w: truncated attested credential data", errAuthenticatorData)
+		}
+		ad.aaguid = rest[:aaguidLength]
+		idLength := int(binary.BigEndian.Uint16(rest[aaguidLength:]))
+		rest = rest[aaguidLength+2:]
+		if idLength > maxCredentialID || idLength > len(rest) {
+			return nil, fmt.Errorf("%w: invalid credential ID length", errAuthenticatorData)
+		}
+		ad.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		key, after, err := parseCOSEKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		ad.publicKey = key
+		ad.publicKeyRaw = rest[:len(rest)-len(after)]
+		rest = after
+	}
+
+	if ad.has(flagExtensionData) {
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid extensions", errAuthenticatorData)
+		}
+		rest = after
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", errAuthenticatorData)
+	}
+	return ad, nil
+}
+
+func (ad *authenticatorData) has(flag byte) bool {
+	return ad.flags&flag != 0
+}
@@ -0,0 +1,131 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers accepted for credentials.
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// SupportedAlgorithms lists accepted algorithms in order of preference,
+// as advertised in pubKeyCredParams.
+var SupportedAlgorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+const (
+	coseKty = 1
+	coseAlg = 3
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+
+	minRSABits = 2048
+)
+
+var errUnsupportedKey = errors.New("unsupported credential public key")
+
+type publicKey struct {
+	alg int
+	key crypto.PublicKey
+}
+
+// parseCOSEKey decodes a COSE_Key and returns it with the bytes following it.
+func parseCOSEKey(data []byte) (*publicKey, []byte, error) {
+	v, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, ok := cborMap(v)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: not a map", errUnsupportedKey)
+	}
+
+	kty, _ := cborInt(m, int64(coseKty))
+	alg, ok := cborInt(m, int64(coseAlg))
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: missing alg", errUnsupportedKey)
+	}
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := cborInt(m, int64(-1))
+		x, okX := cborBytes(m, int64(-2))
+		y, okY := cborBytes(m, int64(-3))
+		if crv != coseCrvP256 || !okX || !okY || len(x) != 32 || len(y) != 32 {
+			return nil, nil, fmt.Errorf("%w: invalid P-256 key", errUnsupportedKey)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		// Round-trip through the uncompressed encoding, which rejects
+		// points that are not on the curve.
+		if _, err := key.ECDH(); err != nil {
+			return nil, nil, fmt.Errorf("%w: point not on curve", errUnsupportedKey)
+		}
+		return &publicKey{alg: AlgES256, key: key}, rest, nil
+
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := cborInt(m, int64(-1))
+		x, ok := cborBytes(m, int64(-2))
+		if crv != coseCrvEd25519 || !ok || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("%w: invalid Ed25519 key", errUnsupportedKey)
+		}
+		return &publicKey{alg: AlgEdDSA, key: ed25519.PublicKey(x)}, rest, nil
+
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, okN := cborBytes(m, int64(-1))
+		e, okE := cborBytes(m, int64(-2))
+		if !okN || !okE || len(e) == 0 || len(e) > 4 {
+			return nil, nil, fmt.Errorf("%w: invalid RSA key", errUnsupportedKey)
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSABits {
+			return nil, nil, fmt.Errorf("%w: RSA key too short", errUnsupportedKey)
+		}
+		return &publicKey{alg: AlgRS256, key: key}, rest, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: kty %d alg %d", errUnsupportedKey, kty, alg)
+}
+
+func (k *publicKey) verify(data, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+// x509SignatureAlgorithm maps a COSE algorithm to the equivalent for
+// checking signatures with an attestation certificate.
+func x509SignatureAlgorithm(alg int64) (x509.SignatureAlgorithm, bool) {
+	switch alg {
+	case AlgES256:
+		return x509.ECDSAWithSHA256, true
+	case AlgEdDSA:
+		return x509.PureEd25519, true
+	case AlgRS256:
+		return x509.SHA256WithRSA, true
+	}
+	return x509.UnknownSignatureAlgorithm, false
+}
@@ -0,0 +1,191 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	challengeBytes = 32
+)
+
+var (
+	ErrInvalidClientData = errors.New("invalid client data")
+	ErrRPIDMismatch      = errors.New("authenticator data is for another relying party")
+	ErrUserNotPresent    = errors.New("user presence flag not set")
+	ErrUserNotVerified   = errors.New("user verification flag not set")
+	ErrInvalidSignature  = errors.New("invalid assertion signature")
+)
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// RelyingParty verifies registration and assertion responses as described
+// in WebAuthn Level 2 sections 7.1 and 7.2.
+type RelyingParty struct {
+	id       string
+	name     string
+	origins  []string
+	rpIDHash [32]byte
+}
+
+func NewRelyingParty(id, name string, origins []string) *RelyingParty {
+	return &RelyingParty{
+		id:       id,
+		name:     name,
+		origins:  origins,
+		rpIDHash: sha256.Sum256([]byte(id)),
+	}
+}
+
+func (rp *RelyingParty) ID() string {
+	return rp.id
+}
+
+func (rp *RelyingParty) Name() string {
+	return rp.name
+}
+
+func (rp *RelyingParty) Algorithms() []int {
+	return SupportedAlgorithms
+}
+
+func (rp *RelyingParty) NewChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeBytes)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func (rp *RelyingParty) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (*port.WebAuthnRegistration, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	v, rest, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := cborMap(v)
+	if !ok || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: attestation object is not a map", errInvalidAttestation)
+	}
+	format, _ := obj["fmt"].(string)
+	stmt, okStmt := cborMap(obj["attStmt"])
+	rawAuthData, okAuthData := cborBytes(obj, "authData")
+	if !okStmt || !okAuthData {
+		return nil, fmt.Errorf("%w: missing attStmt or authData", errInvalidAttestation)
+	}
+
+	authData, err := rp.parseAuthData(rawAuthData, false)
+	if err != nil {
+		return nil, err
+	}
+	if !authData.has(flagAttestedCredData) {
+		return nil, fmt.Errorf("%w: no attested credential", errAuthenticatorData)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifyAttestation(format, stmt, authData, clientDataHash[:]); err != nil {
+		return nil, err
+	}
+
+	return &port.WebAuthnRegistration{
+		CredentialID:      bytes.Clone(authData.credentialID),
+		PublicKey:         bytes.Clone(authData.publicKeyRaw),
+		Algorithm:         authData.publicKey.alg,
+		SignCount:         authData.signCount,
+		AAGUID:            bytes.Clone(authData.aaguid),
+		AttestationFormat: format,
+		UserVerified:      authData.has(flagUserVerified),
+		BackupEligible:    authData.has(flagBackupEligible),
+		BackupState:       authData.has(flagBackupState),
+	}, nil
+}
+
+func (rp *RelyingParty) VerifyAssertion(challenge, clientDataJSON, rawAuthData, signature, publicKeyCOSE []byte, requireUserVerification bool) (*port.WebAuthnAssertion, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := rp.parseAuthData(rawAuthData, requireUserVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	key, rest, err := parseCOSEKey(publicKeyCOSE)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", errUnsupportedKey)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(bytes.Clone(rawAuthData), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	return &port.WebAuthnAssertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.has(flagUserVerified),
+		BackupState:  authData.has(flagBackupState),
+	}, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidClientData, err)
+	}
+	if cd.Type != ceremony {
+		return fmt.Errorf("%w: type %q", ErrInvalidClientData, cd.Type)
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(cd.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidClientData)
+	}
+	if !slices.Contains(rp.origins, cd.Origin) {
+		return fmt.Errorf("%w: origin %q not allowed", ErrInvalidClientData, cd.Origin)
+	}
+	if cd.CrossOrigin {
+		return fmt.Errorf("%w: cross-origin ceremonies are not allowed", ErrInvalidClientData)
+	}
+	return nil
+}
+
+func (rp *RelyingParty) parseAuthData(raw []byte, requireUserVerification bool) (*authenticatorData, error) {
+	authData, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rp.rpIDHash[:]) != 1 {
+		return nil, ErrRPIDMismatch
+	}
+	if !authData.has(flagUserPresent) {
+		return nil, ErrUserNotPresent
+	}
+	if requireUserVerification && !authData.has(flagUserVerified) {
+		return nil, ErrUserNotVerified
+	}
+	return authData, nil
+}
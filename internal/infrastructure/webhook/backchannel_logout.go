@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+// BackchannelLogoutNotifier posts logout tokens to the back-channel logout
+// URIs of clients, as OpenID Connect Back-Channel Logout section 2.5
+// describes. A client that does not answer with a 2xx is retried by the
+// outbox.
+type BackchannelLogoutNotifier struct {
+	signer port.LogoutTokenSigner
+	client *http.Client
+}
+
+func NewBackchannelLogoutNotifier(signer port.LogoutTokenSigner, timeout time.Duration) *BackchannelLogoutNotifier {
+	return &BackchannelLogoutNotifier{
+		signer: signer,
+		client: &http.Client{
+			Timeout: timeout,
+			// The registered URI is the only place the token may go.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (n *BackchannelLogoutNotifier) Name() string {
+	return "backchannel_logout"
+}
+
+func (n *BackchannelLogoutNotifier) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	var payload event.BackchannelLogoutEvent
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return err
+	}
+
+	logoutToken, err := n.signer.SignLogoutToken(port.LogoutTokenClaims{
+		Subject:   payload.UserID,
+		Audience:  payload.ClientID,
+		SessionID: payload.SessionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	body := url.Values{"logout_token": {logoutToken}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, payload.LogoutURI, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("back-channel logout %s responded with status %d", payload.LogoutURI, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -19,6 +20,7 @@ type Metrics struct {
 	UserRegistrations prometheus.Counter
 	LoginAttempts     *prometheus.CounterVec
 	PasswordResets    prometheus.Counter
+	AccountLockouts   prometheus.Counter
 }
 
 func New(reg prometheus.Registerer) *Metrics {
@@ -63,6 +65,12 @@ func New(reg prometheus.Registerer) *Metrics {
 				Help: "Total number of password reset requests",
 			},
 		),
+		AccountLockouts: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Name: "account_lockouts_total",
+				Help: "Total number of accounts locked after repeated failed logins",
+			},
+		),
 	}
 
 	return m
@@ -99,3 +107,43 @@ func (m *Metrics) RegisterDBStats(reg prometheus.Registerer, db *sql.DB) {
 		},
 	))
 }
+
+func (m *Metrics) RecordLoginAttempt(status string) {
+	m.LoginAttempts.WithLabelValues(status).Inc()
+}
+
+func (m *Metrics) RecordPasswordReset() {
+	m.PasswordResets.Inc()
+}
+
+func (m *Metrics) RecordAccountLockout() {
+	m.AccountLockouts.Inc()
+}
+
+// SigningKeyStats reports on the token signing keys.
+type SigningKeyStats interface {
+	ActiveKeyAge() time.Duration
+	PublishedKeys() int
+}
+
+func (m *Metrics) RegisterSigningKeyStats(reg prometheus.Registerer, keys SigningKeyStats) {
+	reg.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "signing_key_age_seconds",
+			Help: "Seconds since the current token signing key started signing",
+		},
+		func() float64 {
+			return keys.ActiveKeyAge().Seconds()
+		},
+	))
+
+	reg.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "signing_keys_published",
+			Help: "Number of signing keys published in the JWKS",
+		},
+		func() float64 {
+			return float64(keys.PublishedKeys())
+		},
+	))
+}
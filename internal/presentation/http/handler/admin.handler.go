@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+type AdminHandler struct {
+	unlockUC       port.UnlockAccountUseCase
+	createClientUC port.CreateOAuthClientUseCase
+	listClientsUC  port.ListOAuthClientsUseCase
+}
+
+func NewAdminHandler(
+	unlockUC port.UnlockAccountUseCase,
+	createClientUC port.CreateOAuthClientUseCase,
+	listClientsUC port.ListOAuthClientsUseCase,
+) *AdminHandler {
+	return &AdminHandler{
+		unlockUC:       unlockUC,
+		createClientUC: createClientUC,
+		listClientsUC:  listClientsUC,
+	}
+}
+
+func (h *AdminHandler) UnlockAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.unlockUC.Execute(ctx, input.UnlockAccountInput{
+		UserID:    c.Param("id"),
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    result.UserID,
+		"was_locked": result.WasLocked,
+		"message":    result.Message,
+	})
+}
+
+func (h *AdminHandler) CreateOAuthClient(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.CreateOAuthClientRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.createClientUC.Execute(ctx, input.CreateOAuthClientInput{
+		Name:                   req.Name,
+		RedirectURIs:           req.RedirectURIs,
+		Scopes:                 req.Scopes,
+		GrantTypes:             req.GrantTypes,
+		ExchangeAudiences:      req.ExchangeAudiences,
+		Confidential:           req.Confidential,
+		FirstParty:             req.FirstParty,
+		JWKS:                   string(req.JWKS),
+		AccessTokenTTL:         time.Duration(req.AccessTokenTTLSec) * time.Second,
+		PostLogoutRedirectURIs: req.PostLogoutRedirectURIs,
+		BackchannelLogoutURI:   req.BackchannelLogoutURI,
+		FrontchannelLogoutURI:  req.FrontchannelLogoutURI,
+		IPAddress:              c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInvalidClientMetadata):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	body := adminClient(result.OAuthClientOutput)
+	body["registration_access_token"] = result.RegistrationToken
+	if result.ClientSecret != "" {
+		body["client_secret"] = result.ClientSecret
+	}
+	c.JSON(http.StatusCreated, body)
+}
+
+func (h *AdminHandler) ListOAuthClients(c *gin.Context) {
+	clients, err := h.listClientsUC.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	body := make([]gin.H, 0, len(clients))
+	for _, client := range clients {
+		body = append(body, adminClient(client))
+	}
+	c.JSON(http.StatusOK, gin.H{"clients": body})
+}
+
+func adminClient(client output.OAuthClientOutput) gin.H {
+	body := gin.H{
+		"client_id":                  client.ClientID,
+		"name":                       client.Name,
+		"redirect_uris":              client.RedirectURIs,
+		"scopes":                     client.Scopes,
+		"grant_types":                client.GrantTypes,
+		"token_endpoint_auth_method": client.AuthMethod,
+		"first_party":                client.FirstParty,
+		"created_at":                 client.CreatedAt,
+	}
+	if len(client.ExchangeAudiences) > 0 {
+		body["exchange_audiences"] = client.ExchangeAudiences
+	}
+	if client.AccessTokenTTL > 0 {
+		body["access_token_ttl_sec"] = int64(client.AccessTokenTTL.Seconds())
+	}
+	if len(client.PostLogoutRedirectURIs) > 0 {
+		body["post_logout_redirect_uris"] = client.PostLogoutRedirectURIs
+	}
+	if client.BackchannelLogoutURI != "" {
+		body["backchannel_logout_uri"] = client.BackchannelLogoutURI
+		body["backchannel_logout_session_required"] = true
+	}
+	if client.FrontchannelLogoutURI != "" {
+		body["frontchannel_logout_uri"] = client.FrontchannelLogoutURI
+		body["frontchannel_logout_session_required"] = true
+	}
+	return body
+}
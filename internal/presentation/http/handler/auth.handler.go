@@ -1,24 +1,48 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
 	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
 	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
-	"github.com/thanhnamdk2710/auth-service/internal/validation"
 )
 
 type AuthHandler struct {
 	registerUC port.RegisterUseCase
+	loginUC    port.LoginUseCase
+	refreshUC  port.RefreshUseCase
+	forgotUC   port.ForgotPasswordUseCase
+	resetUC    port.ResetPasswordUseCase
+	verifyUC   port.VerifyEmailUseCase
+	resendUC   port.ResendVerificationUseCase
 	logger     port.Logger
 }
 
-func NewAuthHandler(registerUC port.RegisterUseCase, logger port.Logger) *AuthHandler {
+func NewAuthHandler(
+	registerUC port.RegisterUseCase,
+	loginUC port.LoginUseCase,
+	refreshUC port.RefreshUseCase,
+	forgotUC port.ForgotPasswordUseCase,
+	resetUC port.ResetPasswordUseCase,
+	verifyUC port.VerifyEmailUseCase,
+	resendUC port.ResendVerificationUseCase,
+	logger port.Logger,
+) *AuthHandler {
 	return &AuthHandler{
 		registerUC: registerUC,
+		loginUC:    loginUC,
+		refreshUC:  refreshUC,
+		forgotUC:   forgotUC,
+		resetUC:    resetUC,
+		verifyUC:   verifyUC,
+		resendUC:   resendUC,
 		logger:     logger,
 	}
 }
@@ -27,18 +51,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var req request.RegisterRequest
-	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
-		if errs := validation.TranslateAll(err); errs != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "validation failed",
-				"errors":  errs,
-			})
-			return
-		}
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -50,6 +63,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	})
 
 	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
@@ -63,13 +79,213 @@ func (h *AuthHandler) Register(c *gin.Context) {
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.LoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.loginUC.Execute(ctx, input.LoginInput{
+		Identifier: req.Identifier,
+		Password:   req.Password,
+		IPAddress:  c.ClientIP(),
+	})
+
+	if err != nil {
+		if errors.Is(err, exception.ErrIdentityProviderUnavailable) {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		writeLoginError(c, err)
+		return
+	}
+
+	writeLoginResult(c, result)
+}
+
+// writeLoginResult answers a first login step with either the tokens or
+// the MFA challenge to complete.
+func writeLoginResult(c *gin.Context, result *output.LoginOutput) {
+	if result.MFARequired {
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+			"mfa_methods":  result.MFAMethods,
+			"expires_in":   int64(time.Until(result.MFAExpiresAt).Seconds()),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login API",
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+		"token_type":    result.TokenType,
+		"expires_in":    int64(time.Until(result.ExpiresAt).Seconds()),
+	})
+}
+
+// writeLoginError answers a failed password, passkey or passwordless
+// login.
+func writeLoginError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidCredentials),
+		errors.Is(err, exception.ErrInvalidPasskey),
+		errors.Is(err, exception.ErrInvalidPasswordlessCode),
+		errors.Is(err, exception.ErrInvalidPasswordlessChallenge),
+		errors.Is(err, exception.ErrInvalidFederatedLogin),
+		errors.Is(err, exception.ErrFederatedLoginFailed),
+		errors.Is(err, exception.ErrInvalidSAMLLogin):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrUserInactive),
+		errors.Is(err, exception.ErrEmailNotVerified),
+		errors.Is(err, exception.ErrFederatedEmailRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.RefreshRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.refreshUC.Execute(ctx, input.RefreshInput{
+		RefreshToken: req.RefreshToken,
+		IPAddress:    c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInvalidRefreshToken),
+			errors.Is(err, exception.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+		"token_type":    result.TokenType,
+		"expires_in":    int64(time.Until(result.ExpiresAt).Seconds()),
 	})
 }
 
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.ForgotPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.forgotUC.Execute(ctx, input.ForgotPasswordInput{
+		Email:     req.Email,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Forgot Password API",
+		"message": result.Message,
+	})
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.ResetPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.resetUC.Execute(ctx, input.ResetPasswordInput{
+		Token:     req.Token,
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, exception.ErrInvalidResetToken),
+			errors.Is(err, exception.ErrPasswordRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": result.Message,
+	})
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.VerifyEmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.verifyUC.Execute(ctx, input.VerifyEmailInput{
+		Token:     req.Token,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInvalidVerificationToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, exception.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, exception.ErrUserAlreadyInactive):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": result.UserID,
+		"message": result.Message,
+	})
+}
+
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.ResendVerificationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.resendUC.Execute(ctx, input.ResendVerificationInput{
+		Email:     req.Email,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": result.Message,
 	})
 }
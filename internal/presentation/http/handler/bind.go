@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/validation"
+)
+
+// bindJSON binds the request body into req and writes a 400 response when
+// binding or validation fails.
+func bindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindBodyWithJSON(req); err != nil {
+		if errs := validation.TranslateAll(err); errs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "validation failed",
+				"errors":  errs,
+			})
+			return false
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writePasswordPolicyError writes a 400 response listing each password
+// policy violation when err is a policy error.
+func writePasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *exception.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	errs := make([]fieldError, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		errs = append(errs, fieldError{Field: v.Field, Code: v.Code, Message: v.Message})
+	}
+
+	body := gin.H{
+		"message": "validation failed",
+		"errors":  errs,
+	}
+	if len(policyErr.Suggestions) > 0 {
+		body["suggestions"] = policyErr.Suggestions
+	}
+
+	c.JSON(http.StatusBadRequest, body)
+	return true
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/middleware"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+// ConsentHandler serves the page where a user approves or denies a
+// third-party client's authorization request, and lets signed-in users
+// list and revoke the consents they have given.
+type ConsentHandler struct {
+	lookupUC port.LookupConsentUseCase
+	decideUC port.DecideConsentUseCase
+	listUC   port.ListConsentsUseCase
+	revokeUC port.RevokeConsentUseCase
+}
+
+func NewConsentHandler(
+	lookupUC port.LookupConsentUseCase,
+	decideUC port.DecideConsentUseCase,
+	listUC port.ListConsentsUseCase,
+	revokeUC port.RevokeConsentUseCase,
+) *ConsentHandler {
+	return &ConsentHandler{
+		lookupUC: lookupUC,
+		decideUC: decideUC,
+		listUC:   listUC,
+		revokeUC: revokeUC,
+	}
+}
+
+var consentPage = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Authorize {{.ClientName}}</title>
+</head>
+<body>
+<main>
+{{if .Error}}
+<h1>This request cannot be completed</h1>
+<p>{{.Error}}. Return to the application and sign in again.</p>
+{{else}}
+<h1>{{.ClientName}} wants to access your account</h1>
+<p>It is asking for:</p>
+<ul>
+{{range .Scopes}}<li>{{.Name}}{{if .Granted}} (already allowed){{end}}</li>
+{{end}}</ul>
+<form method="post" action="consent">
+<input type="hidden" name="consent_token" value="{{.ConsentToken}}">
+<button type="submit" name="action" value="approve">Allow</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+<p>You can revoke this access at any time.</p>
+{{end}}
+</main>
+</body>
+</html>
+`))
+
+type consentScope struct {
+	Name    string
+	Granted bool
+}
+
+type consentPageData struct {
+	ClientName   string
+	Scopes       []consentScope
+	ConsentToken string
+	Error        string
+}
+
+// Page renders the consent page for the pending request its consent
+// token names.
+func (h *ConsentHandler) Page(c *gin.Context) {
+	var req request.ConsentQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		renderConsentPage(c, http.StatusBadRequest, consentPageData{Error: exception.ErrInvalidConsentToken.Error()})
+		return
+	}
+
+	result, err := h.lookupUC.Execute(c.Request.Context(), input.LookupConsentInput{ConsentToken: req.ConsentToken})
+	if err != nil {
+		writeConsentPageError(c, err)
+		return
+	}
+
+	renderConsentPage(c, http.StatusOK, consentPageData{
+		ClientName:   result.ClientName,
+		Scopes:       consentScopes(result),
+		ConsentToken: req.ConsentToken,
+	})
+}
+
+// Decide takes the consent page's form and sends the browser back to the
+// client with either an authorization code or an access_denied error.
+func (h *ConsentHandler) Decide(c *gin.Context) {
+	var req request.ConsentDecisionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderConsentPage(c, http.StatusBadRequest, consentPageData{Error: exception.ErrInvalidConsentToken.Error()})
+		return
+	}
+
+	result, err := h.decideUC.Execute(c.Request.Context(), input.DecideConsentInput{
+		ConsentToken: req.ConsentToken,
+		Approve:      req.Action == "approve",
+		IPAddress:    c.ClientIP(),
+	})
+	if err != nil {
+		writeConsentPageError(c, err)
+		return
+	}
+
+	params := url.Values{"code": {result.Code}}
+	if result.Denied {
+		params = url.Values{
+			"error":             {exception.OAuthAccessDenied},
+			"error_description": {"The user denied the request"},
+		}
+	}
+	if result.State != "" {
+		params.Set("state", result.State)
+	}
+	c.Redirect(http.StatusFound, withQuery(result.RedirectURI, params))
+}
+
+func (h *ConsentHandler) List(c *gin.Context) {
+	result, err := h.listUC.Execute(c.Request.Context(), input.ListConsentsInput{UserID: middleware.UserID(c)})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	consents := make([]gin.H, 0, len(result.Consents))
+	for _, consent := range result.Consents {
+		consents = append(consents, gin.H{
+			"client_id":   consent.ClientID,
+			"client_name": consent.ClientName,
+			"scope":       entity.FormatScope(consent.Scopes),
+			"granted_at":  consent.GrantedAt,
+			"updated_at":  consent.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"consents": consents})
+}
+
+// Revoke withdraws the user's consent to a client, which also signs the
+// client out of the user's account.
+func (h *ConsentHandler) Revoke(c *gin.Context) {
+	err := h.revokeUC.Execute(c.Request.Context(), input.RevokeConsentInput{
+		UserID:    middleware.UserID(c),
+		ClientID:  c.Param("client_id"),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrConsentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func consentScopes(result *output.ConsentRequestOutput) []consentScope {
+	scopes := make([]consentScope, 0, len(result.Scopes))
+	for _, scope := range result.Scopes {
+		scopes = append(scopes, consentScope{Name: scope, Granted: slices.Contains(result.GrantedScopes, scope)})
+	}
+	return scopes
+}
+
+// renderConsentPage writes the page with headers that keep it out of
+// frames and caches, so the decision cannot be clickjacked or replayed.
+func renderConsentPage(c *gin.Context, status int, data consentPageData) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := consentPage.Execute(c.Writer, data); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// writeConsentPageError renders the page's error state. The user is told
+// to start over rather than sent back to a client that may have changed.
+func writeConsentPageError(c *gin.Context, err error) {
+	var oauthErr *exception.OAuthError
+	switch {
+	case errors.Is(err, exception.ErrInvalidConsentToken):
+		renderConsentPage(c, http.StatusBadRequest, consentPageData{Error: err.Error()})
+	case errors.As(err, &oauthErr):
+		renderConsentPage(c, http.StatusBadRequest, consentPageData{Error: oauthErr.Description})
+	case errors.Is(err, exception.ErrInvalidOAuthClient),
+		errors.Is(err, exception.ErrInvalidRedirectURI):
+		renderConsentPage(c, http.StatusBadRequest, consentPageData{Error: err.Error()})
+	case errors.Is(err, exception.ErrUserInactive),
+		errors.Is(err, exception.ErrUserNotFound):
+		renderConsentPage(c, http.StatusForbidden, consentPageData{Error: err.Error()})
+	default:
+		renderConsentPage(c, http.StatusInternalServerError, consentPageData{Error: "Something went wrong"})
+	}
+}
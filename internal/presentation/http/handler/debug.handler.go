@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+)
+
+type DebugHandler struct {
+	mailbox port.Mailbox
+}
+
+func NewDebugHandler(mailbox port.Mailbox) *DebugHandler {
+	return &DebugHandler{mailbox: mailbox}
+}
+
+func (h *DebugHandler) ListMailbox(c *gin.Context) {
+	messages := h.mailbox.List()
+
+	items := make([]gin.H, 0, len(messages))
+	for _, msg := range messages {
+		items = append(items, gin.H{
+			"id":        msg.ID,
+			"sent_at":   msg.SentAt,
+			"to":        msg.To,
+			"subject":   msg.Subject,
+			"text_body": msg.TextBody,
+			"html_body": msg.HTMLBody,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":    len(items),
+		"messages": items,
+	})
+}
+
+func (h *DebugHandler) ClearMailbox(c *gin.Context) {
+	h.mailbox.Clear()
+	c.Status(http.StatusNoContent)
+}
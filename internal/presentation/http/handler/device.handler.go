@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/middleware"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+// DeviceHandler serves the device verification page, where a user enters
+// the code a device displays, signs in and approves or denies it. The
+// same steps are offered as a JSON API to signed-in users.
+type DeviceHandler struct {
+	lookupUC    port.LookupDeviceAuthorizationUseCase
+	decideUC    port.DecideDeviceAuthorizationUseCase
+	loginUC     port.LoginUseCase
+	challengeUC port.VerifyMFAChallengeUseCase
+}
+
+func NewDeviceHandler(
+	lookupUC port.LookupDeviceAuthorizationUseCase,
+	decideUC port.DecideDeviceAuthorizationUseCase,
+	loginUC port.LoginUseCase,
+	challengeUC port.VerifyMFAChallengeUseCase,
+) *DeviceHandler {
+	return &DeviceHandler{
+		lookupUC:    lookupUC,
+		decideUC:    decideUC,
+		loginUC:     loginUC,
+		challengeUC: challengeUC,
+	}
+}
+
+// The page walks through entering the user code, signing in with the
+// decision, and the MFA code when the account has a second factor.
+// Passkeys cannot be used here, so the MFA step asks for an authenticator
+// or recovery code.
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Connect a device</title>
+</head>
+<body>
+<main>
+{{if .Result}}
+<h1>{{.Result}}</h1>
+<p>You can close this window and return to your device.</p>
+{{else}}
+{{if .Error}}<p role="alert">{{.Error}}</p>
+{{end}}{{if .MFAToken}}
+<h1>Confirm it's you</h1>
+<form method="post" action="device">
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<input type="hidden" name="mfa_token" value="{{.MFAToken}}">
+<input type="hidden" name="action" value="{{.Action}}">
+<label>Code from your authenticator app or a recovery code <input name="code" autocomplete="one-time-code" required></label>
+<button type="submit">Continue</button>
+</form>
+{{else if .ClientName}}
+<h1>{{.ClientName}} wants to access your account</h1>
+<p>It is asking for:</p>
+<ul>
+{{range .Scopes}}<li>{{.}}</li>
+{{end}}</ul>
+<p>Only allow this if you started signing in on a device showing the code {{.UserCode}}.</p>
+<form method="post" action="device">
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<label>Email or username <input name="identifier" autocomplete="username" required></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+<button type="submit" name="action" value="approve">Allow</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+{{else}}
+<h1>Connect a device</h1>
+<form method="get" action="device">
+<label>Enter the code shown on your device <input name="user_code" autocomplete="off" required></label>
+<button type="submit">Continue</button>
+</form>
+{{end}}{{end}}
+</main>
+</body>
+</html>
+`))
+
+type devicePageData struct {
+	UserCode   string
+	ClientName string
+	Scopes     []string
+	MFAToken   string
+	Action     string
+	Result     string
+	Error      string
+}
+
+// Page renders the code entry step, or the sign-in step for the pending
+// request whose user code is in the query.
+func (h *DeviceHandler) Page(c *gin.Context) {
+	var req request.DeviceVerificationQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		renderDevicePage(c, http.StatusBadRequest, devicePageData{Error: exception.ErrInvalidUserCode.Error()})
+		return
+	}
+	if req.UserCode == "" {
+		renderDevicePage(c, http.StatusOK, devicePageData{})
+		return
+	}
+
+	result, err := h.lookupUC.Execute(c.Request.Context(), input.LookupDeviceAuthorizationInput{UserCode: req.UserCode})
+	if err != nil {
+		writeDevicePageError(c, devicePageData{}, err)
+		return
+	}
+
+	renderDevicePage(c, http.StatusOK, deviceSignInStep(req.UserCode, result))
+}
+
+// Verify takes the page's form. It signs the user in without issuing a
+// session, asks for the MFA code when the login requires one, and then
+// approves or denies the device request as that user.
+func (h *DeviceHandler) Verify(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.DeviceVerificationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderDevicePage(c, http.StatusBadRequest, devicePageData{Error: "Enter the code shown on your device and sign in"})
+		return
+	}
+
+	// The request is looked up again so that an expired code is reported
+	// before any credentials are checked.
+	authorization, err := h.lookupUC.Execute(ctx, input.LookupDeviceAuthorizationInput{UserCode: req.UserCode})
+	if err != nil {
+		writeDevicePageError(c, devicePageData{}, err)
+		return
+	}
+	step := deviceSignInStep(req.UserCode, authorization)
+
+	var result *output.LoginOutput
+	if req.MFAToken != "" {
+		result, err = h.challengeUC.Execute(ctx, input.VerifyMFAChallengeInput{
+			MFAToken:   req.MFAToken,
+			Code:       req.Code,
+			IPAddress:  c.ClientIP(),
+			VerifyOnly: true,
+		})
+		if errors.Is(err, exception.ErrInvalidMFACode) {
+			step.MFAToken = req.MFAToken
+			step.Action = req.Action
+		}
+	} else {
+		result, err = h.loginUC.Execute(ctx, input.LoginInput{
+			Identifier: req.Identifier,
+			Password:   req.Password,
+			IPAddress:  c.ClientIP(),
+			VerifyOnly: true,
+		})
+	}
+	if err != nil {
+		writeDevicePageError(c, step, err)
+		return
+	}
+
+	if result.MFARequired {
+		step.MFAToken = result.MFAToken
+		step.Action = req.Action
+		renderDevicePage(c, http.StatusOK, step)
+		return
+	}
+
+	approve := req.Action == "approve"
+	err = h.decideUC.Execute(ctx, input.DecideDeviceAuthorizationInput{
+		UserID:    result.UserID,
+		UserCode:  req.UserCode,
+		Approve:   approve,
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		writeDevicePageError(c, step, err)
+		return
+	}
+
+	message := "Device request denied"
+	if approve {
+		message = "Device approved"
+	}
+	renderDevicePage(c, http.StatusOK, devicePageData{Result: message})
+}
+
+// Lookup describes the pending request for a user code, so the page can
+// show which client is asking before the user decides.
+func (h *DeviceHandler) Lookup(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.DeviceUserCodeQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.lookupUC.Execute(ctx, input.LookupDeviceAuthorizationInput{UserCode: req.UserCode})
+	if err != nil {
+		writeDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client_id":   result.ClientID,
+		"client_name": result.ClientName,
+		"scope":       entity.FormatScope(result.Scopes),
+		"expires_at":  result.ExpiresAt,
+	})
+}
+
+func (h *DeviceHandler) Decide(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.DecideDeviceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	approve := req.Action == "approve"
+	err := h.decideUC.Execute(ctx, input.DecideDeviceAuthorizationInput{
+		UserID:    middleware.UserID(c),
+		UserCode:  req.UserCode,
+		Approve:   approve,
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		writeDeviceError(c, err)
+		return
+	}
+
+	message := "Device request denied"
+	if approve {
+		message = "Device approved"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func writeDeviceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidUserCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrUserInactive),
+		errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+func deviceSignInStep(userCode string, result *output.DeviceAuthorizationOutput) devicePageData {
+	return devicePageData{
+		UserCode:   userCode,
+		ClientName: result.ClientName,
+		Scopes:     result.Scopes,
+	}
+}
+
+// renderDevicePage writes the page with headers that keep it out of
+// frames and caches, as for the consent page.
+func renderDevicePage(c *gin.Context, status int, data devicePageData) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := devicePage.Execute(c.Writer, data); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// writeDevicePageError shows the error on step, the page the user was on,
+// except that a code no longer pending sends them back to entering one.
+func writeDevicePageError(c *gin.Context, step devicePageData, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidUserCode):
+		renderDevicePage(c, http.StatusBadRequest, devicePageData{Error: err.Error()})
+	case errors.Is(err, exception.ErrInvalidCredentials),
+		errors.Is(err, exception.ErrInvalidMFACode),
+		errors.Is(err, exception.ErrInvalidMFAChallenge):
+		step.Error = err.Error()
+		renderDevicePage(c, http.StatusUnauthorized, step)
+	case errors.Is(err, exception.ErrUserInactive),
+		errors.Is(err, exception.ErrEmailNotVerified),
+		errors.Is(err, exception.ErrUserNotFound):
+		step.Error = err.Error()
+		renderDevicePage(c, http.StatusForbidden, step)
+	default:
+		renderDevicePage(c, http.StatusInternalServerError, devicePageData{Error: "Something went wrong"})
+	}
+}
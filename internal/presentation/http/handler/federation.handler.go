@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+const (
+	federatedDeviceCookie     = "federated_device"
+	federatedDeviceCookiePath = "/api/v1/auth/federated"
+)
+
+// FederationHandler signs users in through upstream identity providers.
+// The provider sends the browser back to the frontend's redirect page,
+// which posts the state and code it was given to Callback.
+type FederationHandler struct {
+	listUC     port.ListIdentityProvidersUseCase
+	startUC    port.StartFederatedLoginUseCase
+	completeUC port.CompleteFederatedLoginUseCase
+	// secureCookie marks the device cookie Secure, for HTTPS deployments.
+	secureCookie bool
+}
+
+func NewFederationHandler(
+	listUC port.ListIdentityProvidersUseCase,
+	startUC port.StartFederatedLoginUseCase,
+	completeUC port.CompleteFederatedLoginUseCase,
+	secureCookie bool,
+) *FederationHandler {
+	return &FederationHandler{
+		listUC:       listUC,
+		startUC:      startUC,
+		completeUC:   completeUC,
+		secureCookie: secureCookie,
+	}
+}
+
+func (h *FederationHandler) List(c *gin.Context) {
+	providers := h.listUC.Execute(c.Request.Context())
+
+	list := make([]gin.H, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, gin.H{"id": provider.ID, "name": provider.Name})
+	}
+	c.JSON(http.StatusOK, gin.H{"providers": list})
+}
+
+// Start returns the URL to send the browser to. As with passwordless
+// sign-in, the device token ties the callback to this client.
+func (h *FederationHandler) Start(c *gin.Context) {
+	result, err := h.startUC.Execute(c.Request.Context(), input.StartFederatedLoginInput{
+		Provider:  c.Param("provider"),
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUnknownIdentityProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, exception.ErrIdentityProviderUnavailable):
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(federatedDeviceCookie, result.DeviceToken, maxAge, federatedDeviceCookiePath, "", h.secureCookie, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"authorization_url": result.AuthorizationURL,
+		"device_token":      result.DeviceToken,
+		"expires_in":        int64(maxAge),
+	})
+}
+
+func (h *FederationHandler) Callback(c *gin.Context) {
+	var req request.FederatedCallbackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	deviceToken := req.DeviceToken
+	if deviceToken == "" {
+		deviceToken, _ = c.Cookie(federatedDeviceCookie)
+	}
+
+	result, err := h.completeUC.Execute(c.Request.Context(), input.CompleteFederatedLoginInput{
+		DeviceToken: deviceToken,
+		State:       req.State,
+		Code:        req.Code,
+		Error:       req.Error,
+		IPAddress:   c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrEmailAlreadyExists),
+			errors.Is(err, exception.ErrUsernameAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			writeLoginError(c, err)
+		}
+		return
+	}
+
+	// The sign-in is spent; drop the cookie.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(federatedDeviceCookie, "", -1, federatedDeviceCookiePath, "", h.secureCookie, true)
+
+	writeLoginResult(c, result)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+// LogoutHandler serves the end_session_endpoint of OpenID Connect
+// RP-Initiated Logout.
+type LogoutHandler struct {
+	endSessionUC port.EndSessionUseCase
+	issuer       string
+}
+
+func NewLogoutHandler(endSessionUC port.EndSessionUseCase, issuer string) *LogoutHandler {
+	return &LogoutHandler{
+		endSessionUC: endSessionUC,
+		issuer:       issuer,
+	}
+}
+
+// The page loads each client's front-channel logout URI in a hidden frame
+// and, once they have had a moment to clear their cookies, moves on to
+// the post-logout redirect URI.
+var logoutPage = template.Must(template.New("logout").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .RedirectURI}}<meta http-equiv="refresh" content="2; url={{.RedirectURI}}">
+{{end}}<title>Signed out</title>
+</head>
+<body>
+<main>
+{{if .Error}}
+<h1>You could not be signed out</h1>
+<p>{{.Error}}. Return to the application and try again.</p>
+{{else}}
+<h1>You have been signed out</h1>
+{{if .RedirectURI}}<p><a href="{{.RedirectURI}}">Continue</a></p>
+{{else}}<p>You can close this window.</p>
+{{end}}{{range .Frames}}<iframe src="{{.}}" hidden></iframe>
+{{end}}{{end}}
+</main>
+</body>
+</html>
+`))
+
+type logoutPageData struct {
+	RedirectURI string
+	Frames      []string
+	Error       string
+}
+
+// EndSession logs the user out everywhere. Without front-channel clients
+// to notify the browser goes straight back to the client; otherwise the
+// logout page loads their logout URIs first.
+func (h *LogoutHandler) EndSession(c *gin.Context) {
+	var req request.EndSessionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderLogoutPage(c, http.StatusBadRequest, logoutPageData{Error: "The logout request is malformed"})
+		return
+	}
+
+	result, err := h.endSessionUC.Execute(c.Request.Context(), input.EndSessionInput{
+		IDTokenHint:           req.IDTokenHint,
+		ClientID:              req.ClientID,
+		PostLogoutRedirectURI: req.PostLogoutRedirectURI,
+		State:                 req.State,
+		IPAddress:             c.ClientIP(),
+	})
+	if err != nil {
+		var oauthErr *exception.OAuthError
+		if errors.As(err, &oauthErr) {
+			renderLogoutPage(c, http.StatusBadRequest, logoutPageData{Error: oauthErr.Description})
+			return
+		}
+		renderLogoutPage(c, http.StatusInternalServerError, logoutPageData{Error: "Something went wrong"})
+		return
+	}
+
+	redirectURI := postLogoutRedirect(result)
+	if len(result.FrontchannelLogouts) == 0 && redirectURI != "" {
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, redirectURI)
+		return
+	}
+
+	renderLogoutPage(c, http.StatusOK, logoutPageData{
+		RedirectURI: redirectURI,
+		Frames:      h.frontchannelFrames(result.FrontchannelLogouts),
+	})
+}
+
+// frontchannelFrames adds the iss and sid query parameters of OpenID
+// Connect Front-Channel Logout section 2 to each client's logout URI.
+func (h *LogoutHandler) frontchannelFrames(logouts []output.FrontchannelLogout) []string {
+	frames := make([]string, 0, len(logouts))
+	for _, logout := range logouts {
+		frames = append(frames, withQuery(logout.URI, url.Values{
+			"iss": {h.issuer},
+			"sid": {logout.SessionID},
+		}))
+	}
+	return frames
+}
+
+func postLogoutRedirect(result *output.EndSessionOutput) string {
+	if result.PostLogoutRedirectURI == "" {
+		return ""
+	}
+	if result.State == "" {
+		return result.PostLogoutRedirectURI
+	}
+	return withQuery(result.PostLogoutRedirectURI, url.Values{"state": {result.State}})
+}
+
+// renderLogoutPage writes the page with a policy that lets it frame only
+// the clients it logs out and keeps it out of frames and caches itself.
+func renderLogoutPage(c *gin.Context, status int, data logoutPageData) {
+	policy := "default-src 'none'; frame-ancestors 'none'"
+	if origins := frameOrigins(data.Frames); len(origins) > 0 {
+		policy += "; frame-src " + strings.Join(origins, " ")
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", policy)
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := logoutPage.Execute(c.Writer, data); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func frameOrigins(frames []string) []string {
+	var origins []string
+	for _, frame := range frames {
+		u, err := url.Parse(frame)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if !slices.Contains(origins, origin) {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/middleware"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+type MFAHandler struct {
+	setupUC     port.SetupTOTPUseCase
+	confirmUC   port.ConfirmTOTPUseCase
+	disableUC   port.DisableTOTPUseCase
+	challengeUC port.VerifyMFAChallengeUseCase
+}
+
+func NewMFAHandler(
+	setupUC port.SetupTOTPUseCase,
+	confirmUC port.ConfirmTOTPUseCase,
+	disableUC port.DisableTOTPUseCase,
+	challengeUC port.VerifyMFAChallengeUseCase,
+) *MFAHandler {
+	return &MFAHandler{
+		setupUC:     setupUC,
+		confirmUC:   confirmUC,
+		disableUC:   disableUC,
+		challengeUC: challengeUC,
+	}
+}
+
+func (h *MFAHandler) SetupTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.setupUC.Execute(ctx, input.SetupTOTPInput{
+		UserID:    middleware.UserID(c),
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      result.Secret,
+		"otpauth_uri": result.ProvisioningURI,
+		"qr_code_png": base64.StdEncoding.EncodeToString(result.QRCodePNG),
+	})
+}
+
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.ConfirmTOTPRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.confirmUC.Execute(ctx, input.ConfirmTOTPInput{
+		UserID:    middleware.UserID(c),
+		Code:      req.Code,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recovery_codes": result.RecoveryCodes,
+		"message":        result.Message,
+	})
+}
+
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.DisableTOTPRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.disableUC.Execute(ctx, input.DisableTOTPInput{
+		UserID:    middleware.UserID(c),
+		Password:  req.Password,
+		Code:      req.Code,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": result.Message,
+	})
+}
+
+func (h *MFAHandler) Challenge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.MFAChallengeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	challenge := input.VerifyMFAChallengeInput{
+		MFAToken:  req.MFAToken,
+		Code:      req.Code,
+		IPAddress: c.ClientIP(),
+	}
+	if req.Passkey != nil {
+		assertion, err := decodePasskeyAssertion(req.PasskeySessionID, *req.Passkey)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		challenge.Passkey = assertion
+	}
+
+	result, err := h.challengeUC.Execute(ctx, challenge)
+
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+		"token_type":    result.TokenType,
+		"expires_in":    int64(time.Until(result.ExpiresAt).Seconds()),
+	})
+}
+
+func writeMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidMFACode),
+		errors.Is(err, exception.ErrInvalidMFAChallenge),
+		errors.Is(err, exception.ErrInvalidCredentials),
+		errors.Is(err, exception.ErrInvalidPasskey),
+		errors.Is(err, exception.ErrInvalidWebAuthnSession),
+		errors.Is(err, exception.ErrPasskeySignCount):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrMFANotEnabled),
+		errors.Is(err, exception.ErrMFASetupNotStarted),
+		errors.Is(err, exception.ErrPasskeyNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
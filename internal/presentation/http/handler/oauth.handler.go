@@ -0,0 +1,489 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+type OAuthHandler struct {
+	validateUC   port.ValidateAuthorizationRequestUseCase
+	authorizeUC  port.AuthorizeUseCase
+	tokenUC      port.OAuthTokenUseCase
+	introspectUC port.IntrospectTokenUseCase
+	revokeUC     port.RevokeTokenUseCase
+	deviceUC     port.StartDeviceAuthorizationUseCase
+	loginUC      port.LoginUseCase
+	challengeUC  port.VerifyMFAChallengeUseCase
+	loginURL     string
+	consentURL   string
+}
+
+func NewOAuthHandler(
+	validateUC port.ValidateAuthorizationRequestUseCase,
+	authorizeUC port.AuthorizeUseCase,
+	tokenUC port.OAuthTokenUseCase,
+	introspectUC port.IntrospectTokenUseCase,
+	revokeUC port.RevokeTokenUseCase,
+	deviceUC port.StartDeviceAuthorizationUseCase,
+	loginUC port.LoginUseCase,
+	challengeUC port.VerifyMFAChallengeUseCase,
+	loginURL string,
+	consentURL string,
+) *OAuthHandler {
+	return &OAuthHandler{
+		validateUC:   validateUC,
+		authorizeUC:  authorizeUC,
+		tokenUC:      tokenUC,
+		introspectUC: introspectUC,
+		revokeUC:     revokeUC,
+		deviceUC:     deviceUC,
+		loginUC:      loginUC,
+		challengeUC:  challengeUC,
+		loginURL:     loginURL,
+		consentURL:   consentURL,
+	}
+}
+
+// Authorize receives the browser from the client. A valid request is
+// passed on, parameters unchanged, to the login page; an invalid one is
+// reported back to the client's redirect URI when that URI can be trusted.
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.AuthorizationParams
+	if err := c.ShouldBindQuery(&req); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+		return
+	}
+
+	if _, err := h.validateUC.Execute(ctx, authorizationRequest(req)); err != nil {
+		if redirectTo, ok := authorizationErrorRedirect(req, err); ok {
+			c.Redirect(http.StatusFound, redirectTo)
+			return
+		}
+		writeAuthorizationError(c, req, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, h.loginURL+"?"+c.Request.URL.RawQuery)
+}
+
+// Login is called by the login page with the authorization request and
+// the user's credentials. It reuses the password and MFA logins, and on
+// success returns the client redirect carrying the authorization code, or
+// the consent page when the user has yet to approve the client. Only the
+// credentials are verified; no session is issued.
+func (h *OAuthHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.AuthorizeLoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	authRequest := authorizationRequest(req.AuthorizationParams)
+	if _, err := h.validateUC.Execute(ctx, authRequest); err != nil {
+		writeAuthorizationError(c, req.AuthorizationParams, err)
+		return
+	}
+
+	var result *output.LoginOutput
+	var err error
+	if req.MFAToken != "" {
+		challenge := input.VerifyMFAChallengeInput{
+			MFAToken:   req.MFAToken,
+			Code:       req.Code,
+			IPAddress:  c.ClientIP(),
+			VerifyOnly: true,
+		}
+		if req.Passkey != nil {
+			assertion, decodeErr := decodePasskeyAssertion(req.PasskeySessionID, *req.Passkey)
+			if decodeErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": decodeErr.Error()})
+				return
+			}
+			challenge.Passkey = assertion
+		}
+		result, err = h.challengeUC.Execute(ctx, challenge)
+		if err != nil {
+			writeMFAError(c, err)
+			return
+		}
+	} else {
+		result, err = h.loginUC.Execute(ctx, input.LoginInput{
+			Identifier: req.Identifier,
+			Password:   req.Password,
+			IPAddress:  c.ClientIP(),
+			VerifyOnly: true,
+		})
+		if err != nil {
+			writeLoginError(c, err)
+			return
+		}
+	}
+
+	if result.MFARequired {
+		writeLoginResult(c, result)
+		return
+	}
+
+	authorized, err := h.authorizeUC.Execute(ctx, input.AuthorizeInput{
+		Request:   authRequest,
+		UserID:    result.UserID,
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		writeAuthorizationError(c, req.AuthorizationParams, err)
+		return
+	}
+
+	if authorized.ConsentToken != "" {
+		params := url.Values{"consent_token": {authorized.ConsentToken}}
+		c.JSON(http.StatusOK, gin.H{"redirect_to": withQuery(h.consentURL, params)})
+		return
+	}
+
+	params := url.Values{"code": {authorized.Code}}
+	if authorized.State != "" {
+		params.Set("state", authorized.State)
+	}
+	c.JSON(http.StatusOK, gin.H{"redirect_to": withQuery(authorized.RedirectURI, params)})
+}
+
+// Token redeems an authorization code, refresh token or approved device
+// code, or issues a token to the client itself for the client_credentials
+// grant. Responses follow
+// RFC 6749 section 5 rather than this API's usual error shape.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req request.OAuthTokenRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+		return
+	}
+
+	client, usedBasic, ok := clientCredentials(c, req.OAuthClientAuth)
+	if !ok {
+		return
+	}
+
+	result, err := h.tokenUC.Execute(ctx, input.OAuthTokenInput{
+		GrantType:           req.GrantType,
+		ClientID:            client.ClientID,
+		ClientSecret:        client.ClientSecret,
+		ClientAssertionType: client.AssertionType,
+		ClientAssertion:     client.Assertion,
+		Code:                req.Code,
+		RedirectURI:         req.RedirectURI,
+		CodeVerifier:        req.CodeVerifier,
+		RefreshToken:        req.RefreshToken,
+		DeviceCode:          req.DeviceCode,
+		SubjectToken:        req.SubjectToken,
+		SubjectTokenType:    req.SubjectTokenType,
+		ActorToken:          req.ActorToken,
+		ActorTokenType:      req.ActorTokenType,
+		RequestedTokenType:  req.RequestedTokenType,
+		Audiences:           append(req.Audience, req.Resource...),
+		Scope:               req.Scope,
+		IPAddress:           c.ClientIP(),
+	})
+	if err != nil {
+		writeClientError(c, err, usedBasic)
+		return
+	}
+
+	body := gin.H{
+		"access_token": result.AccessToken,
+		"token_type":   result.TokenType,
+		"expires_in":   int64(time.Until(result.ExpiresAt).Seconds()),
+		"scope":        entity.FormatScope(result.Scopes),
+	}
+	if result.RefreshToken != "" {
+		body["refresh_token"] = result.RefreshToken
+	}
+	if result.IDToken != "" {
+		body["id_token"] = result.IDToken
+	}
+	if result.IssuedTokenType != "" {
+		body["issued_token_type"] = result.IssuedTokenType
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// DeviceAuthorization starts the device authorization grant (RFC 8628):
+// the device shows the user code and verification URI, then polls the
+// token endpoint with the device code.
+func (h *OAuthHandler) DeviceAuthorization(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	c.Header("Cache-Control", "no-store")
+
+	var req request.OAuthDeviceAuthorizationRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+		return
+	}
+
+	client, usedBasic, ok := clientCredentials(c, req.OAuthClientAuth)
+	if !ok {
+		return
+	}
+
+	result, err := h.deviceUC.Execute(ctx, input.StartDeviceAuthorizationInput{
+		Client:    client,
+		Scope:     req.Scope,
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		writeClientError(c, err, usedBasic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"device_code":               result.DeviceCode,
+		"user_code":                 result.UserCode,
+		"verification_uri":          result.VerificationURI,
+		"verification_uri_complete": result.VerificationURIComplete,
+		"expires_in":                int64(time.Until(result.ExpiresAt).Seconds()),
+		"interval":                  int64(result.Interval.Seconds()),
+	})
+}
+
+// Introspect tells a confidential client whether a token is active and,
+// if so, what it grants (RFC 7662).
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	c.Header("Cache-Control", "no-store")
+
+	var req request.OAuthTokenActionRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+		return
+	}
+
+	client, usedBasic, ok := clientCredentials(c, req.OAuthClientAuth)
+	if !ok {
+		return
+	}
+
+	result, err := h.introspectUC.Execute(ctx, input.IntrospectTokenInput{
+		Client:        client,
+		Token:         req.Token,
+		TokenTypeHint: req.TokenTypeHint,
+	})
+	if err != nil {
+		writeClientError(c, err, usedBasic)
+		return
+	}
+
+	if !result.Active {
+		c.JSON(http.StatusOK, gin.H{"active": false})
+		return
+	}
+
+	body := gin.H{
+		"active":    true,
+		"scope":     entity.FormatScope(result.Scopes),
+		"client_id": result.ClientID,
+		"exp":       result.ExpiresAt.Unix(),
+		"iat":       result.IssuedAt.Unix(),
+	}
+	if result.UserID != "" {
+		body["sub"] = result.UserID
+	}
+	if result.Username != "" {
+		body["username"] = result.Username
+	}
+	if result.TokenType != "" {
+		body["token_type"] = result.TokenType
+	}
+	if result.TokenID != "" {
+		body["jti"] = result.TokenID
+	}
+	if len(result.Audience) > 0 {
+		body["aud"] = result.Audience
+	}
+	if result.Actor != nil {
+		body["act"] = actorClaim(result.Actor)
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// actorClaim renders a delegation chain as the nested act claim of
+// RFC 8693 section 4.1.
+func actorClaim(actor *entity.TokenActor) gin.H {
+	claim := gin.H{"sub": actor.Subject}
+	if actor.ClientID != "" {
+		claim["client_id"] = actor.ClientID
+	}
+	if actor.Actor != nil {
+		claim["act"] = actorClaim(actor.Actor)
+	}
+	return claim
+}
+
+// Revoke revokes one of the client's tokens (RFC 7009). It answers 200
+// whether or not there was anything to revoke.
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.OAuthTokenActionRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+		return
+	}
+
+	client, usedBasic, ok := clientCredentials(c, req.OAuthClientAuth)
+	if !ok {
+		return
+	}
+
+	if err := h.revokeUC.Execute(ctx, input.RevokeTokenInput{
+		Client:        client,
+		Token:         req.Token,
+		TokenTypeHint: req.TokenTypeHint,
+		IPAddress:     c.ClientIP(),
+	}); err != nil {
+		writeClientError(c, err, usedBasic)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// clientCredentials reads the client's credentials from the form or from
+// HTTP Basic authentication, reporting whether Basic was used. Malformed
+// or repeated credentials are answered here.
+func clientCredentials(c *gin.Context, form request.OAuthClientAuth) (input.ClientCredentials, bool, bool) {
+	credentials := input.ClientCredentials{
+		ClientID:      form.ClientID,
+		ClientSecret:  form.ClientSecret,
+		AssertionType: form.ClientAssertionType,
+		Assertion:     form.ClientAssertion,
+	}
+
+	basicID, basicSecret, usedBasic := c.Request.BasicAuth()
+	if !usedBasic {
+		return credentials, false, true
+	}
+
+	// Basic credentials are form-encoded before being joined, per
+	// RFC 6749 section 2.3.1.
+	id, idErr := url.QueryUnescape(basicID)
+	secret, secretErr := url.QueryUnescape(basicSecret)
+	if idErr != nil || secretErr != nil || form.ClientSecret != "" || (form.ClientID != "" && form.ClientID != id) {
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, "client credentials are malformed or sent more than once")
+		return credentials, true, false
+	}
+	credentials.ClientID, credentials.ClientSecret = id, secret
+	return credentials, true, true
+}
+
+// writeClientError answers a failed request to an endpoint clients
+// authenticate to, as RFC 6749 section 5.2 describes.
+func writeClientError(c *gin.Context, err error, usedBasic bool) {
+	var oauthErr *exception.OAuthError
+	switch {
+	case errors.As(err, &oauthErr) && oauthErr.Code == exception.OAuthInvalidClient:
+		if usedBasic {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		writeOAuthError(c, http.StatusUnauthorized, oauthErr.Code, oauthErr.Description)
+	case errors.As(err, &oauthErr):
+		writeOAuthError(c, http.StatusBadRequest, oauthErr.Code, oauthErr.Description)
+	default:
+		writeOAuthError(c, http.StatusInternalServerError, "server_error", "Internal server error")
+	}
+}
+
+func authorizationRequest(req request.AuthorizationParams) input.AuthorizationRequest {
+	return input.AuthorizationRequest{
+		ResponseType:        req.ResponseType,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		State:               req.State,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Prompt:              req.Prompt,
+		RequestObject:       req.RequestObject,
+		RequestURI:          req.RequestURI,
+	}
+}
+
+// authorizationErrorRedirect builds the client redirect for a rejected
+// request. Only OAuthErrors qualify: they are raised after the client and
+// its redirect URI have been verified.
+func authorizationErrorRedirect(req request.AuthorizationParams, err error) (string, bool) {
+	var oauthErr *exception.OAuthError
+	if !errors.As(err, &oauthErr) {
+		return "", false
+	}
+
+	params := url.Values{
+		"error":             {oauthErr.Code},
+		"error_description": {oauthErr.Description},
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return withQuery(req.RedirectURI, params), true
+}
+
+// writeAuthorizationError answers a rejected authorization request. Errors
+// the client should hear about come with the redirect that reports them.
+func writeAuthorizationError(c *gin.Context, req request.AuthorizationParams, err error) {
+	var oauthErr *exception.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		redirectTo, _ := authorizationErrorRedirect(req, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             oauthErr.Code,
+			"error_description": oauthErr.Description,
+			"redirect_to":       redirectTo,
+		})
+	case errors.Is(err, exception.ErrInvalidOAuthClient),
+		errors.Is(err, exception.ErrInvalidRedirectURI):
+		writeOAuthError(c, http.StatusBadRequest, exception.OAuthInvalidRequest, err.Error())
+	case errors.Is(err, exception.ErrUserInactive),
+		errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+func writeOAuthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+// withQuery adds params to uri, keeping any query it already has.
+func withQuery(uri string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(uri, "?") {
+		separator = "&"
+	}
+	return uri + separator + params.Encode()
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/middleware"
+)
+
+type OIDCHandler struct {
+	userInfoUC port.UserInfoUseCase
+	keys       port.KeySet
+	issuer     string
+	scopes     []string
+	// registration reports whether dynamic client registration is open.
+	registration bool
+	// deviceAuthorization reports whether the device authorization grant
+	// is on.
+	deviceAuthorization bool
+}
+
+func NewOIDCHandler(
+	userInfoUC port.UserInfoUseCase,
+	keys port.KeySet,
+	issuer string,
+	scopes []string,
+	registration bool,
+	deviceAuthorization bool,
+) *OIDCHandler {
+	return &OIDCHandler{
+		userInfoUC:          userInfoUC,
+		keys:                keys,
+		issuer:              issuer,
+		scopes:              scopes,
+		registration:        registration,
+		deviceAuthorization: deviceAuthorization,
+	}
+}
+
+// Discovery serves the OpenID Provider Metadata described in OpenID
+// Connect Discovery section 3.
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	grantTypes := []string{entity.OAuthGrantAuthorizationCode, entity.OAuthGrantRefreshToken, entity.OAuthGrantClientCredentials, entity.OAuthGrantTokenExchange}
+	if h.deviceAuthorization {
+		grantTypes = append(grantTypes, entity.OAuthGrantDeviceCode)
+	}
+
+	metadata := gin.H{
+		"issuer":                                           h.issuer,
+		"authorization_endpoint":                           h.issuer + "/oauth/authorize",
+		"token_endpoint":                                   h.issuer + "/oauth/token",
+		"userinfo_endpoint":                                h.issuer + "/oauth/userinfo",
+		"introspection_endpoint":                           h.issuer + "/oauth/introspect",
+		"revocation_endpoint":                              h.issuer + "/oauth/revoke",
+		"end_session_endpoint":                             h.issuer + "/oauth/logout",
+		"jwks_uri":                                         h.issuer + "/.well-known/jwks.json",
+		"scopes_supported":                                 h.scopes,
+		"response_types_supported":                         []string{"code"},
+		"response_modes_supported":                         []string{"query"},
+		"grant_types_supported":                            grantTypes,
+		"subject_types_supported":                          []string{"public"},
+		"id_token_signing_alg_values_supported":            h.signingAlgorithms(),
+		"token_endpoint_auth_methods_supported":            []string{"client_secret_basic", "client_secret_post", entity.OAuthAuthMethodPrivateKeyJWT, "none"},
+		"token_endpoint_auth_signing_alg_values_supported": []string{entity.SigningAlgRS256, entity.SigningAlgES256, entity.SigningAlgEdDSA},
+		"introspection_endpoint_auth_methods_supported":    []string{"client_secret_basic", "client_secret_post", entity.OAuthAuthMethodPrivateKeyJWT},
+		"revocation_endpoint_auth_methods_supported":       []string{"client_secret_basic", "client_secret_post", entity.OAuthAuthMethodPrivateKeyJWT, "none"},
+		"code_challenge_methods_supported":                 []string{entity.PKCEMethodS256},
+		"claims_supported": []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "at_hash",
+			"preferred_username", "email", "email_verified", "sid",
+		},
+		// Without a single sign-on session prompt=none cannot succeed.
+		"prompt_values_supported":               []string{"login", "consent"},
+		"request_parameter_supported":           false,
+		"request_uri_parameter_supported":       false,
+		"backchannel_logout_supported":          true,
+		"backchannel_logout_session_supported":  true,
+		"frontchannel_logout_supported":         true,
+		"frontchannel_logout_session_supported": true,
+	}
+	if h.registration {
+		metadata["registration_endpoint"] = h.issuer + "/oauth/register"
+	}
+	if h.deviceAuthorization {
+		metadata["device_authorization_endpoint"] = h.issuer + "/oauth/device_authorization"
+	}
+	c.JSON(http.StatusOK, metadata)
+}
+
+// signingAlgorithms lists the algorithms of the published keys, which
+// differ only while keys move to a newly configured algorithm.
+func (h *OIDCHandler) signingAlgorithms() []string {
+	var algorithms []string
+	for _, key := range h.keys.PublicKeys() {
+		if !slices.Contains(algorithms, key.Algorithm) {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	return algorithms
+}
+
+// JWKS publishes the keys tokens are signed with, including keys about
+// to start signing and retired keys whose tokens have not yet expired.
+func (h *OIDCHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": h.keys.PublicKeys()})
+}
+
+// UserInfo returns the claims about the user the client's access token
+// was granted. Errors follow RFC 6750 section 3.
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	result, err := h.userInfoUC.Execute(c.Request.Context(), input.UserInfoInput{
+		UserID: middleware.UserID(c),
+		Scopes: middleware.Scopes(c),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInsufficientScope):
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient_scope"})
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result.Claims)
+}
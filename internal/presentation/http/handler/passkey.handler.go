@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/middleware"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+const (
+	userVerificationRequired  = "required"
+	userVerificationPreferred = "preferred"
+)
+
+var errInvalidPasskeyEncoding = errors.New("Passkey fields must be base64url encoded")
+
+type PasskeyHandler struct {
+	beginRegistrationUC  port.BeginPasskeyRegistrationUseCase
+	finishRegistrationUC port.FinishPasskeyRegistrationUseCase
+	listUC               port.ListPasskeysUseCase
+	deleteUC             port.DeletePasskeyUseCase
+	beginLoginUC         port.BeginPasskeyLoginUseCase
+	finishLoginUC        port.FinishPasskeyLoginUseCase
+	beginMFAUC           port.BeginMFAPasskeyUseCase
+}
+
+func NewPasskeyHandler(
+	beginRegistrationUC port.BeginPasskeyRegistrationUseCase,
+	finishRegistrationUC port.FinishPasskeyRegistrationUseCase,
+	listUC port.ListPasskeysUseCase,
+	deleteUC port.DeletePasskeyUseCase,
+	beginLoginUC port.BeginPasskeyLoginUseCase,
+	finishLoginUC port.FinishPasskeyLoginUseCase,
+	beginMFAUC port.BeginMFAPasskeyUseCase,
+) *PasskeyHandler {
+	return &PasskeyHandler{
+		beginRegistrationUC:  beginRegistrationUC,
+		finishRegistrationUC: finishRegistrationUC,
+		listUC:               listUC,
+		deleteUC:             deleteUC,
+		beginLoginUC:         beginLoginUC,
+		finishLoginUC:        finishLoginUC,
+		beginMFAUC:           beginMFAUC,
+	}
+}
+
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.beginRegistrationUC.Execute(ctx, input.BeginPasskeyRegistrationInput{
+		UserID: middleware.UserID(c),
+	})
+
+	if err != nil {
+		writePasskeyError(c, err)
+		return
+	}
+
+	excluded := credentialDescriptors(result.CredentialIDs)
+	params := make([]gin.H, 0, len(result.Algorithms))
+	for _, alg := range result.Algorithms {
+		params = append(params, gin.H{"type": "public-key", "alg": alg})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": result.SessionID,
+		"expires_in": int64(time.Until(result.ExpiresAt).Seconds()),
+		"public_key": gin.H{
+			"challenge": encodeBase64URL(result.Challenge),
+			"rp": gin.H{
+				"id":   result.RPID,
+				"name": result.RPName,
+			},
+			"user": gin.H{
+				"id":          encodeBase64URL(result.UserHandle),
+				"name":        result.UserName,
+				"displayName": result.UserDisplayName,
+			},
+			"pubKeyCredParams":   params,
+			"timeout":            time.Until(result.ExpiresAt).Milliseconds(),
+			"excludeCredentials": excluded,
+			"authenticatorSelection": gin.H{
+				"residentKey":      "preferred",
+				"userVerification": userVerificationPreferred,
+			},
+			"attestation": "none",
+		},
+	})
+}
+
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.FinishPasskeyRegistrationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	clientDataJSON, err1 := decodeBase64URL(req.Credential.Response.ClientDataJSON)
+	attestationObject, err2 := decodeBase64URL(req.Credential.Response.AttestationObject)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidPasskeyEncoding.Error()})
+		return
+	}
+
+	result, err := h.finishRegistrationUC.Execute(ctx, input.FinishPasskeyRegistrationInput{
+		UserID:            middleware.UserID(c),
+		SessionID:         req.SessionID,
+		Name:              strings.TrimSpace(req.Name),
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+		Transports:        req.Credential.Response.Transports,
+		IPAddress:         c.ClientIP(),
+	})
+
+	if err != nil {
+		writePasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"passkey":        passkeyJSON(result.Passkey),
+		"recovery_codes": result.RecoveryCodes,
+		"message":        result.Message,
+	})
+}
+
+func (h *PasskeyHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.listUC.Execute(ctx, input.ListPasskeysInput{
+		UserID: middleware.UserID(c),
+	})
+
+	if err != nil {
+		writePasskeyError(c, err)
+		return
+	}
+
+	passkeys := make([]gin.H, 0, len(result.Passkeys))
+	for _, passkey := range result.Passkeys {
+		passkeys = append(passkeys, passkeyJSON(passkey))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"passkeys": passkeys,
+	})
+}
+
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.DeletePasskeyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		writePasskeyError(c, exception.ErrPasskeyNotFound)
+		return
+	}
+
+	result, err := h.deleteUC.Execute(ctx, input.DeletePasskeyInput{
+		UserID:    middleware.UserID(c),
+		PasskeyID: c.Param("id"),
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		writePasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": result.Message,
+	})
+}
+
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.BeginPasskeyLoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.beginLoginUC.Execute(ctx, input.BeginPasskeyLoginInput{
+		Identifier: req.Identifier,
+	})
+
+	if err != nil {
+		writePasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assertionOptionsJSON(result, userVerificationRequired))
+}
+
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.FinishPasskeyLoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	assertion, err := decodePasskeyAssertion(req.SessionID, req.Credential)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.finishLoginUC.Execute(ctx, input.FinishPasskeyLoginInput{
+		Assertion: *assertion,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		writeLoginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+		"token_type":    result.TokenType,
+		"expires_in":    int64(time.Until(result.ExpiresAt).Seconds()),
+	})
+}
+
+// MFAOptions starts the passkey assertion for a login that is waiting on
+// its second factor.
+func (h *PasskeyHandler) MFAOptions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.MFAPasskeyOptionsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.beginMFAUC.Execute(ctx, input.BeginMFAPasskeyInput{
+		MFAToken: req.MFAToken,
+	})
+
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assertionOptionsJSON(result, userVerificationPreferred))
+}
+
+func assertionOptionsJSON(result *output.PasskeyOptionsOutput, userVerification string) gin.H {
+	return gin.H{
+		"session_id": result.SessionID,
+		"expires_in": int64(time.Until(result.ExpiresAt).Seconds()),
+		"public_key": gin.H{
+			"challenge":        encodeBase64URL(result.Challenge),
+			"rpId":             result.RPID,
+			"timeout":          time.Until(result.ExpiresAt).Milliseconds(),
+			"allowCredentials": credentialDescriptors(result.CredentialIDs),
+			"userVerification": userVerification,
+		},
+	}
+}
+
+func credentialDescriptors(ids [][]byte) []gin.H {
+	descriptors := make([]gin.H, 0, len(ids))
+	for _, id := range ids {
+		descriptors = append(descriptors, gin.H{"type": "public-key", "id": encodeBase64URL(id)})
+	}
+	return descriptors
+}
+
+func passkeyJSON(passkey output.PasskeyOutput) gin.H {
+	return gin.H{
+		"id":              passkey.ID,
+		"name":            passkey.Name,
+		"created_at":      passkey.CreatedAt,
+		"last_used_at":    passkey.LastUsedAt,
+		"backup_eligible": passkey.BackupEligible,
+		"backed_up":       passkey.BackupState,
+		"transports":      passkey.Transports,
+	}
+}
+
+func decodePasskeyAssertion(sessionID string, credential request.PasskeyAssertionCredential) (*input.PasskeyAssertion, error) {
+	credentialID, err1 := decodeBase64URL(credential.RawID)
+	clientDataJSON, err2 := decodeBase64URL(credential.Response.ClientDataJSON)
+	authenticatorData, err3 := decodeBase64URL(credential.Response.AuthenticatorData)
+	signature, err4 := decodeBase64URL(credential.Response.Signature)
+	userHandle, err5 := decodeBase64URL(credential.Response.UserHandle)
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, errInvalidPasskeyEncoding
+	}
+
+	return &input.PasskeyAssertion{
+		SessionID:         sessionID,
+		CredentialID:      credentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+		UserHandle:        userHandle,
+	}, nil
+}
+
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeBase64URL accepts padded and unpadded input; browsers differ.
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+func writePasskeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidPasskey),
+		errors.Is(err, exception.ErrInvalidWebAuthnSession):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrPasskeyAlreadyRegistered):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrPasskeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, exception.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+const (
+	passwordlessDeviceCookie     = "passwordless_device"
+	passwordlessDeviceCookiePath = "/api/v1/auth/passwordless"
+)
+
+type PasswordlessHandler struct {
+	startUC  port.StartPasswordlessUseCase
+	verifyUC port.VerifyPasswordlessUseCase
+	// secureCookie marks the device cookie Secure, for HTTPS deployments.
+	secureCookie bool
+}
+
+func NewPasswordlessHandler(
+	startUC port.StartPasswordlessUseCase,
+	verifyUC port.VerifyPasswordlessUseCase,
+	secureCookie bool,
+) *PasswordlessHandler {
+	return &PasswordlessHandler{
+		startUC:      startUC,
+		verifyUC:     verifyUC,
+		secureCookie: secureCookie,
+	}
+}
+
+// Start emails a magic link or code. The device token binds the sign-in
+// to this client: browsers keep it as a cookie, other clients send it
+// back in the verify request.
+func (h *PasswordlessHandler) Start(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.StartPasswordlessRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.startUC.Execute(ctx, input.StartPasswordlessInput{
+		Email:     req.Email,
+		Method:    req.Method,
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrInvalidPasswordlessMethod):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(passwordlessDeviceCookie, result.DeviceToken, maxAge, passwordlessDeviceCookiePath, "", h.secureCookie, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"device_token": result.DeviceToken,
+		"method":       result.Method,
+		"expires_in":   int64(maxAge),
+		"message":      result.Message,
+	})
+}
+
+func (h *PasswordlessHandler) Verify(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req request.VerifyPasswordlessRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	deviceToken := req.DeviceToken
+	if deviceToken == "" {
+		deviceToken, _ = c.Cookie(passwordlessDeviceCookie)
+	}
+
+	result, err := h.verifyUC.Execute(ctx, input.VerifyPasswordlessInput{
+		DeviceToken: deviceToken,
+		Token:       req.Token,
+		Code:        req.Code,
+		IPAddress:   c.ClientIP(),
+	})
+
+	if err != nil {
+		writeLoginError(c, err)
+		return
+	}
+
+	// The sign-in is spent; drop the cookie.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(passwordlessDeviceCookie, "", -1, passwordlessDeviceCookiePath, "", h.secureCookie, true)
+
+	writeLoginResult(c, result)
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+// RegistrationHandler serves dynamic client registration (RFC 7591) and
+// lets each registered client read, update and delete its registration
+// with its registration access token (RFC 7592).
+type RegistrationHandler struct {
+	createUC port.CreateOAuthClientUseCase
+	readUC   port.ReadClientRegistrationUseCase
+	updateUC port.UpdateClientRegistrationUseCase
+	deleteUC port.DeleteClientRegistrationUseCase
+	issuer   string
+}
+
+func NewRegistrationHandler(
+	createUC port.CreateOAuthClientUseCase,
+	readUC port.ReadClientRegistrationUseCase,
+	updateUC port.UpdateClientRegistrationUseCase,
+	deleteUC port.DeleteClientRegistrationUseCase,
+	issuer string,
+) *RegistrationHandler {
+	return &RegistrationHandler{
+		createUC: createUC,
+		readUC:   readUC,
+		updateUC: updateUC,
+		deleteUC: deleteUC,
+		issuer:   issuer,
+	}
+}
+
+func (h *RegistrationHandler) Register(c *gin.Context) {
+	_, metadata, ok := h.bindMetadata(c)
+	if !ok {
+		return
+	}
+	metadata.IPAddress = c.ClientIP()
+
+	result, err := h.createUC.Execute(c.Request.Context(), metadata)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	body := h.clientMetadata(result.OAuthClientOutput)
+	body["registration_access_token"] = result.RegistrationToken
+	if result.ClientSecret != "" {
+		body["client_secret"] = result.ClientSecret
+		body["client_secret_expires_at"] = 0
+	}
+	c.JSON(http.StatusCreated, body)
+}
+
+func (h *RegistrationHandler) Read(c *gin.Context) {
+	result, err := h.readUC.Execute(c.Request.Context(), input.ClientRegistrationInput{
+		ClientID:          c.Param("client_id"),
+		RegistrationToken: bearerToken(c),
+		IPAddress:         c.ClientIP(),
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.clientMetadata(*result))
+}
+
+// Update replaces the client's metadata with the request body. Its
+// client_id, when sent, must name the client being updated.
+func (h *RegistrationHandler) Update(c *gin.Context) {
+	req, metadata, ok := h.bindMetadata(c)
+	if !ok {
+		return
+	}
+	metadata.IPAddress = c.ClientIP()
+
+	if req.ClientID != "" && req.ClientID != c.Param("client_id") {
+		writeOAuthError(c, http.StatusBadRequest, "invalid_client_metadata", "client_id does not match the registration being updated")
+		return
+	}
+
+	result, err := h.updateUC.Execute(c.Request.Context(), input.UpdateClientRegistrationInput{
+		ClientID:          c.Param("client_id"),
+		RegistrationToken: bearerToken(c),
+		Client:            metadata,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.clientMetadata(*result))
+}
+
+func (h *RegistrationHandler) Delete(c *gin.Context) {
+	err := h.deleteUC.Execute(c.Request.Context(), input.ClientRegistrationInput{
+		ClientID:          c.Param("client_id"),
+		RegistrationToken: bearerToken(c),
+		IPAddress:         c.ClientIP(),
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindMetadata reads RFC 7591 client metadata into the input the admin
+// API registers clients with, writing invalid_client_metadata for
+// anything it cannot take.
+func (h *RegistrationHandler) bindMetadata(c *gin.Context) (request.ClientRegistrationRequest, input.CreateOAuthClientInput, bool) {
+	var req request.ClientRegistrationRequest
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		writeOAuthError(c, http.StatusBadRequest, "invalid_client_metadata", "Client metadata is malformed")
+		return req, input.CreateOAuthClientInput{}, false
+	}
+	if req.JWKSURI != "" {
+		writeOAuthError(c, http.StatusBadRequest, "invalid_client_metadata", "jwks_uri is not supported, send the keys as jwks")
+		return req, input.CreateOAuthClientInput{}, false
+	}
+	for _, responseType := range req.ResponseTypes {
+		if responseType != "code" {
+			writeOAuthError(c, http.StatusBadRequest, "invalid_client_metadata", "Only the code response type is supported")
+			return req, input.CreateOAuthClientInput{}, false
+		}
+	}
+
+	var jwks string
+	if len(req.JWKS) > 0 && string(req.JWKS) != "null" {
+		jwks = string(req.JWKS)
+	}
+	// RFC 7591 section 2 makes client_secret_basic the default method.
+	authMethod := req.TokenEndpointAuthMethod
+	if authMethod == "" && jwks == "" {
+		authMethod = entity.OAuthAuthMethodClientSecretBasic
+	}
+
+	return req, input.CreateOAuthClientInput{
+		Name:                   req.ClientName,
+		RedirectURIs:           req.RedirectURIs,
+		Scopes:                 entity.ParseScope(req.Scope),
+		GrantTypes:             req.GrantTypes,
+		ExchangeAudiences:      req.ExchangeAudiences,
+		AuthMethod:             authMethod,
+		JWKS:                   jwks,
+		AccessTokenTTL:         time.Duration(req.AccessTokenTTLSec) * time.Second,
+		PostLogoutRedirectURIs: req.PostLogoutRedirectURIs,
+		BackchannelLogoutURI:   req.BackchannelLogoutURI,
+		FrontchannelLogoutURI:  req.FrontchannelLogoutURI,
+	}, true
+}
+
+// clientMetadata describes a registered client as RFC 7591 section 3.2.1
+// does, with the URI it manages its registration at.
+func (h *RegistrationHandler) clientMetadata(client output.OAuthClientOutput) gin.H {
+	body := gin.H{
+		"client_id":                  client.ClientID,
+		"client_id_issued_at":        client.CreatedAt.Unix(),
+		"client_name":                client.Name,
+		"grant_types":                client.GrantTypes,
+		"token_endpoint_auth_method": client.AuthMethod,
+		"scope":                      entity.FormatScope(client.Scopes),
+		"registration_client_uri":    h.issuer + "/oauth/register/" + client.ClientID,
+	}
+	if slices.Contains(client.GrantTypes, entity.OAuthGrantAuthorizationCode) {
+		body["redirect_uris"] = client.RedirectURIs
+		body["response_types"] = []string{"code"}
+	}
+	if len(client.ExchangeAudiences) > 0 {
+		body["exchange_audiences"] = client.ExchangeAudiences
+	}
+	if client.AccessTokenTTL > 0 {
+		body["access_token_ttl_sec"] = int64(client.AccessTokenTTL.Seconds())
+	}
+	if len(client.PostLogoutRedirectURIs) > 0 {
+		body["post_logout_redirect_uris"] = client.PostLogoutRedirectURIs
+	}
+	if client.BackchannelLogoutURI != "" {
+		body["backchannel_logout_uri"] = client.BackchannelLogoutURI
+		body["backchannel_logout_session_required"] = true
+	}
+	if client.FrontchannelLogoutURI != "" {
+		body["frontchannel_logout_uri"] = client.FrontchannelLogoutURI
+		body["frontchannel_logout_session_required"] = true
+	}
+	return body
+}
+
+func (h *RegistrationHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrInvalidRegistrationToken):
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+	case errors.Is(err, exception.ErrInvalidClientRedirectURI):
+		writeOAuthError(c, http.StatusBadRequest, "invalid_redirect_uri", err.Error())
+	case errors.Is(err, exception.ErrInvalidClientMetadata):
+		writeOAuthError(c, http.StatusBadRequest, "invalid_client_metadata", err.Error())
+	default:
+		writeOAuthError(c, http.StatusInternalServerError, "server_error", "Internal server error")
+	}
+}
+
+// bearerToken returns the bearer token of the Authorization header, or an
+// empty string when there is none.
+func bearerToken(c *gin.Context) string {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
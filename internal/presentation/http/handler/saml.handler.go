@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/request"
+)
+
+const (
+	samlDeviceCookie     = "saml_device"
+	samlDeviceCookiePath = "/api/v1/auth/saml"
+)
+
+// SAMLHandler signs users in through SAML identity providers. The
+// identity provider posts its response to ACS, which sends the browser on
+// to the frontend's redirect page with a code; the page posts the code to
+// Redeem.
+type SAMLHandler struct {
+	metadataUC port.GetSAMLMetadataUseCase
+	startUC    port.StartSAMLLoginUseCase
+	consumeUC  port.ConsumeSAMLResponseUseCase
+	redeemUC   port.RedeemSAMLLoginUseCase
+	// redirectURL is the frontend page ACS sends the browser to.
+	redirectURL string
+	// secureCookie marks the device cookie Secure, for HTTPS deployments.
+	secureCookie bool
+}
+
+func NewSAMLHandler(
+	metadataUC port.GetSAMLMetadataUseCase,
+	startUC port.StartSAMLLoginUseCase,
+	consumeUC port.ConsumeSAMLResponseUseCase,
+	redeemUC port.RedeemSAMLLoginUseCase,
+	redirectURL string,
+	secureCookie bool,
+) *SAMLHandler {
+	return &SAMLHandler{
+		metadataUC:   metadataUC,
+		startUC:      startUC,
+		consumeUC:    consumeUC,
+		redeemUC:     redeemUC,
+		redirectURL:  redirectURL,
+		secureCookie: secureCookie,
+	}
+}
+
+func (h *SAMLHandler) Metadata(c *gin.Context) {
+	metadata, err := h.metadataUC.Execute(c.Request.Context(), c.Param("idp"))
+	if err != nil {
+		if errors.Is(err, exception.ErrUnknownIdentityProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// Start returns the authentication request for the browser to post to the
+// identity provider's SSO URL, as the SAMLRequest form field. The device
+// token ties the sign-in to this client.
+func (h *SAMLHandler) Start(c *gin.Context) {
+	result, err := h.startUC.Execute(c.Request.Context(), input.StartSAMLLoginInput{
+		IdP:       c.Param("idp"),
+		IPAddress: c.ClientIP(),
+	})
+
+	if err != nil {
+		if errors.Is(err, exception.ErrUnknownIdentityProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(samlDeviceCookie, result.DeviceToken, maxAge, samlDeviceCookiePath, "", h.secureCookie, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"sso_url":      result.SSOURL,
+		"saml_request": result.SAMLRequest,
+		"device_token": result.DeviceToken,
+		"expires_in":   int64(maxAge),
+	})
+}
+
+// ACS is the assertion consumer service. The browser arrives here with a
+// cross-site form post, so it is sent on to the redirect page either way,
+// with a code or with an error the page can show.
+func (h *SAMLHandler) ACS(c *gin.Context) {
+	result, err := h.consumeUC.Execute(c.Request.Context(), input.ConsumeSAMLResponseInput{
+		IdP:          c.Param("idp"),
+		SAMLResponse: c.PostForm("SAMLResponse"),
+		IPAddress:    c.ClientIP(),
+	})
+
+	query := url.Values{}
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUnknownIdentityProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, exception.ErrInvalidSAMLResponse):
+			query.Set("error", "invalid_response")
+		case errors.Is(err, exception.ErrEmailAlreadyExists),
+			errors.Is(err, exception.ErrUsernameAlreadyExists):
+			query.Set("error", "account_exists")
+		case errors.Is(err, exception.ErrFederatedEmailRequired):
+			query.Set("error", "email_required")
+		default:
+			query.Set("error", "server_error")
+		}
+	} else {
+		query.Set("code", result.Code)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusSeeOther, h.redirectURL+"?"+query.Encode())
+}
+
+func (h *SAMLHandler) Redeem(c *gin.Context) {
+	var req request.SAMLRedeemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	deviceToken := req.DeviceToken
+	if deviceToken == "" {
+		deviceToken, _ = c.Cookie(samlDeviceCookie)
+	}
+
+	result, err := h.redeemUC.Execute(c.Request.Context(), input.RedeemSAMLLoginInput{
+		Code:        req.Code,
+		DeviceToken: deviceToken,
+		IPAddress:   c.ClientIP(),
+	})
+
+	if err != nil {
+		writeLoginError(c, err)
+		return
+	}
+
+	// The sign-in is spent; drop the cookie.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(samlDeviceCookie, "", -1, samlDeviceCookiePath, "", h.secureCookie, true)
+
+	writeLoginResult(c, result)
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminAuth only lets requests through that present the shared admin API
+// key in the X-Admin-Key header.
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	expected := []byte(apiKey)
+
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader(AdminKeyHeader))
+		if len(expected) == 0 || subtle.ConstantTimeCompare(provided, expected) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationAuth only lets requests through that present the initial
+// access token as a bearer token, as RFC 7591 section 3 allows a server to
+// require before it registers a client.
+func RegistrationAuth(initialAccessToken string) gin.HandlerFunc {
+	expected := []byte(initialAccessToken)
+
+	return func(c *gin.Context) {
+		scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", `Bearer`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+
+		provided := []byte(strings.TrimSpace(token))
+		if len(expected) == 0 || subtle.ConstantTimeCompare(provided, expected) != 1 {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+		c.Next()
+	}
+}
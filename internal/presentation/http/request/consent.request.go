@@ -0,0 +1,14 @@
+package request
+
+// ConsentQuery names the pending authorization request the consent page
+// shows.
+type ConsentQuery struct {
+	ConsentToken string `form:"consent_token" binding:"required,lte=128"`
+}
+
+// ConsentDecisionRequest is the consent page's form, approving or denying
+// the request ConsentToken names.
+type ConsentDecisionRequest struct {
+	ConsentToken string `form:"consent_token" binding:"required,lte=128"`
+	Action       string `form:"action" binding:"required,oneof=approve deny"`
+}
@@ -0,0 +1,31 @@
+package request
+
+// DeviceUserCodeQuery is the user code typed on the device verification
+// page.
+type DeviceUserCodeQuery struct {
+	UserCode string `form:"user_code" binding:"required,lte=32"`
+}
+
+// DecideDeviceRequest approves or denies the device request with UserCode.
+type DecideDeviceRequest struct {
+	UserCode string `json:"user_code" binding:"required,lte=32"`
+	Action   string `json:"action" binding:"required,oneof=approve deny"`
+}
+
+// DeviceVerificationQuery is the verification page's query. The user code
+// is present when the device showed verification_uri_complete.
+type DeviceVerificationQuery struct {
+	UserCode string `form:"user_code" binding:"lte=32"`
+}
+
+// DeviceVerificationRequest is the verification page's form, approving or
+// denying the request with UserCode. The user signs in with a password
+// or, when that login required MFA, the challenge token and a code.
+type DeviceVerificationRequest struct {
+	UserCode   string `form:"user_code" binding:"required,lte=32"`
+	Action     string `form:"action" binding:"required,oneof=approve deny"`
+	Identifier string `form:"identifier" binding:"required_without=MFAToken,omitempty,gte=3,lte=255"`
+	Password   string `form:"password" binding:"required_without=MFAToken,lte=50"`
+	MFAToken   string `form:"mfa_token" binding:"lte=128"`
+	Code       string `form:"code" binding:"lte=32"`
+}
@@ -0,0 +1,9 @@
+package request
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email,gte=5,lte=255"`
+}
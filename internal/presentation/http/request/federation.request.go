@@ -0,0 +1,11 @@
+package request
+
+// FederatedCallbackRequest carries what the identity provider sent back to
+// the redirect page. It may omit the device token when the browser sends
+// the cookie set by the start endpoint.
+type FederatedCallbackRequest struct {
+	DeviceToken string `json:"device_token" binding:"lte=64"`
+	State       string `json:"state" binding:"required,lte=64"`
+	Code        string `json:"code" binding:"required_without=Error,lte=2048"`
+	Error       string `json:"error" binding:"lte=256"`
+}
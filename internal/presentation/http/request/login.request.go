@@ -0,0 +1,6 @@
+package request
+
+type LoginRequest struct {
+	Identifier string `json:"identifier" binding:"required,gte=3,lte=255"`
+	Password   string `json:"password" binding:"required,lte=50"`
+}
@@ -0,0 +1,19 @@
+package request
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,lte=32"`
+}
+
+// MFAChallengeRequest answers the challenge with either a code or a
+// passkey assertion started through the passkey options endpoint.
+type MFAChallengeRequest struct {
+	MFAToken         string                      `json:"mfa_token" binding:"required"`
+	Code             string                      `json:"code" binding:"required_without=Passkey,lte=32"`
+	PasskeySessionID string                      `json:"passkey_session_id" binding:"required_with=Passkey,omitempty,uuid"`
+	Passkey          *PasskeyAssertionCredential `json:"passkey"`
+}
@@ -0,0 +1,131 @@
+package request
+
+import "encoding/json"
+
+// AuthorizationParams are the parameters of an OAuth authorization
+// request, read from the query string of the client's redirect and echoed
+// back by the login page in its JSON body.
+type AuthorizationParams struct {
+	ResponseType        string `form:"response_type" json:"response_type"`
+	ClientID            string `form:"client_id" json:"client_id"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	Nonce               string `form:"nonce" json:"nonce"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+	Prompt              string `form:"prompt" json:"prompt"`
+	RequestObject       string `form:"request" json:"request"`
+	RequestURI          string `form:"request_uri" json:"request_uri"`
+}
+
+// AuthorizeLoginRequest signs the user in for an authorization request,
+// with a password or, when that login required MFA, the challenge token
+// and a code or passkey assertion.
+type AuthorizeLoginRequest struct {
+	AuthorizationParams
+	Identifier       string                      `json:"identifier" binding:"required_without=MFAToken,omitempty,gte=3,lte=255"`
+	Password         string                      `json:"password" binding:"required_without=MFAToken,lte=50"`
+	MFAToken         string                      `json:"mfa_token"`
+	Code             string                      `json:"code" binding:"lte=32"`
+	PasskeySessionID string                      `json:"passkey_session_id" binding:"required_with=Passkey,omitempty,uuid"`
+	Passkey          *PasskeyAssertionCredential `json:"passkey"`
+}
+
+// OAuthClientAuth are the client credentials an OAuth endpoint reads from
+// its form. They may instead arrive with HTTP Basic authentication, or as
+// a signed client assertion.
+type OAuthClientAuth struct {
+	ClientID            string `form:"client_id"`
+	ClientSecret        string `form:"client_secret"`
+	ClientAssertionType string `form:"client_assertion_type"`
+	ClientAssertion     string `form:"client_assertion"`
+}
+
+// OAuthTokenRequest is a form-encoded token endpoint request. Audience and
+// Resource may be repeated in a token exchange (RFC 8693).
+type OAuthTokenRequest struct {
+	OAuthClientAuth
+	GrantType          string   `form:"grant_type"`
+	Code               string   `form:"code"`
+	RedirectURI        string   `form:"redirect_uri"`
+	CodeVerifier       string   `form:"code_verifier"`
+	RefreshToken       string   `form:"refresh_token"`
+	DeviceCode         string   `form:"device_code"`
+	SubjectToken       string   `form:"subject_token"`
+	SubjectTokenType   string   `form:"subject_token_type"`
+	ActorToken         string   `form:"actor_token"`
+	ActorTokenType     string   `form:"actor_token_type"`
+	RequestedTokenType string   `form:"requested_token_type"`
+	Audience           []string `form:"audience"`
+	Resource           []string `form:"resource"`
+	Scope              string   `form:"scope"`
+}
+
+// OAuthDeviceAuthorizationRequest starts a device authorization grant
+// (RFC 8628).
+type OAuthDeviceAuthorizationRequest struct {
+	OAuthClientAuth
+	Scope string `form:"scope"`
+}
+
+// OAuthTokenActionRequest names a token to introspect (RFC 7662) or
+// revoke (RFC 7009).
+type OAuthTokenActionRequest struct {
+	OAuthClientAuth
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
+// CreateOAuthClientRequest registers a client. Redirect URIs are only
+// needed for the authorization code grant; JWKS is the JWK Set a
+// private_key_jwt client signs its assertions with. ExchangeAudiences are
+// the audiences a token exchange client may obtain tokens for. FirstParty
+// marks one of our own apps, which users are not asked to consent to. The
+// logout URIs are those of OpenID Connect RP-Initiated, Back-Channel and
+// Front-Channel Logout.
+type CreateOAuthClientRequest struct {
+	Name                   string          `json:"name" binding:"required,lte=100"`
+	RedirectURIs           []string        `json:"redirect_uris" binding:"omitempty,dive,required,lte=2000"`
+	Scopes                 []string        `json:"scopes" binding:"required,min=1"`
+	GrantTypes             []string        `json:"grant_types"`
+	ExchangeAudiences      []string        `json:"exchange_audiences" binding:"omitempty,dive,required,lte=255"`
+	Confidential           bool            `json:"confidential"`
+	FirstParty             bool            `json:"first_party"`
+	JWKS                   json.RawMessage `json:"jwks"`
+	AccessTokenTTLSec      int             `json:"access_token_ttl_sec" binding:"gte=0"`
+	PostLogoutRedirectURIs []string        `json:"post_logout_redirect_uris" binding:"omitempty,dive,required,lte=2000"`
+	BackchannelLogoutURI   string          `json:"backchannel_logout_uri" binding:"lte=2000"`
+	FrontchannelLogoutURI  string          `json:"frontchannel_logout_uri" binding:"lte=2000"`
+}
+
+// ClientRegistrationRequest is client metadata as RFC 7591 section 2
+// names it, sent to register a client or, in full, to update one (RFC
+// 7592). Scope is space-separated. Only the code response type is
+// supported, and keys must be sent inline as jwks rather than by
+// jwks_uri.
+type ClientRegistrationRequest struct {
+	ClientID                string          `json:"client_id"`
+	ClientName              string          `json:"client_name" binding:"lte=100"`
+	RedirectURIs            []string        `json:"redirect_uris" binding:"omitempty,dive,required,lte=2000"`
+	GrantTypes              []string        `json:"grant_types"`
+	ResponseTypes           []string        `json:"response_types"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method"`
+	Scope                   string          `json:"scope" binding:"lte=1000"`
+	JWKS                    json.RawMessage `json:"jwks"`
+	JWKSURI                 string          `json:"jwks_uri"`
+	ExchangeAudiences       []string        `json:"exchange_audiences" binding:"omitempty,dive,required,lte=255"`
+	AccessTokenTTLSec       int             `json:"access_token_ttl_sec" binding:"gte=0"`
+	PostLogoutRedirectURIs  []string        `json:"post_logout_redirect_uris" binding:"omitempty,dive,required,lte=2000"`
+	BackchannelLogoutURI    string          `json:"backchannel_logout_uri" binding:"lte=2000"`
+	FrontchannelLogoutURI   string          `json:"frontchannel_logout_uri" binding:"lte=2000"`
+}
+
+// EndSessionRequest is an OpenID Connect RP-Initiated Logout request,
+// sent as a query string or a form.
+type EndSessionRequest struct {
+	IDTokenHint           string `form:"id_token_hint"`
+	ClientID              string `form:"client_id"`
+	PostLogoutRedirectURI string `form:"post_logout_redirect_uri"`
+	State                 string `form:"state"`
+}
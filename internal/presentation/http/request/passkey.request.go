@@ -0,0 +1,52 @@
+package request
+
+// Binary WebAuthn fields are base64url encoded, as produced by
+// PublicKeyCredential.toJSON().
+
+type PasskeyAttestationCredential struct {
+	RawID    string                     `json:"rawId" binding:"required"`
+	Type     string                     `json:"type" binding:"required,eq=public-key"`
+	Response PasskeyAttestationResponse `json:"response"`
+}
+
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject" binding:"required"`
+	Transports        []string `json:"transports" binding:"lte=8,dive,lte=32"`
+}
+
+type PasskeyAssertionCredential struct {
+	RawID    string                   `json:"rawId" binding:"required"`
+	Type     string                   `json:"type" binding:"required,eq=public-key"`
+	Response PasskeyAssertionResponse `json:"response"`
+}
+
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"userHandle"`
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	SessionID  string                       `json:"session_id" binding:"required,uuid"`
+	Name       string                       `json:"name" binding:"lte=100"`
+	Credential PasskeyAttestationCredential `json:"credential"`
+}
+
+type DeletePasskeyRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type BeginPasskeyLoginRequest struct {
+	Identifier string `json:"identifier" binding:"lte=255"`
+}
+
+type FinishPasskeyLoginRequest struct {
+	SessionID  string                     `json:"session_id" binding:"required,uuid"`
+	Credential PasskeyAssertionCredential `json:"credential"`
+}
+
+type MFAPasskeyOptionsRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,gte=5,lte=255"`
+}
+
+type ResetPasswordRequest struct {
+	Token                string `json:"token" binding:"required"`
+	Password             string `json:"password" binding:"required"`
+	PasswordConfirmation string `json:"password_confirmation" binding:"required,eqfield=Password"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type StartPasswordlessRequest struct {
+	Email  string `json:"email" binding:"required,email,gte=5,lte=255"`
+	Method string `json:"method" binding:"required,oneof=link code"`
+}
+
+// VerifyPasswordlessRequest may omit the device token when the browser
+// sends the cookie set by the start endpoint.
+type VerifyPasswordlessRequest struct {
+	DeviceToken string `json:"device_token" binding:"lte=64"`
+	Token       string `json:"token" binding:"required_without=Code,lte=256"`
+	Code        string `json:"code" binding:"required_without=Token,omitempty,len=6,numeric"`
+}
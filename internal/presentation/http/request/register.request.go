@@ -1,8 +1,10 @@
 package request
 
+// RegisterRequest leaves the password out for accounts that only sign in
+// by email or passkey.
 type RegisterRequest struct {
 	Username             string `json:"username" binding:"required,gte=3,lte=30"`
 	Email                string `json:"email" binding:"required,email,gte=5,lte=255"`
-	Password             string `json:"password" binding:"required,gte=8,lte=50"`
-	PasswordConfirmation string `json:"password_confirmation" binding:"required,gte=8,lte=50,eqfield=Password"`
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"password_confirmation" binding:"eqfield=Password"`
 }
@@ -0,0 +1,9 @@
+package request
+
+// SAMLRedeemRequest carries the code the assertion consumer service sent
+// the browser to the redirect page with. It may omit the device token
+// when the browser sends the cookie set by the start endpoint.
+type SAMLRedeemRequest struct {
+	Code        string `json:"code" binding:"required,lte=64"`
+	DeviceToken string `json:"device_token" binding:"lte=64"`
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
 	"github.com/thanhnamdk2710/auth-service/internal/pkg/metrics"
 	"github.com/thanhnamdk2710/auth-service/internal/presentation/http/handler"
@@ -14,9 +15,31 @@ import (
 )
 
 type RouterDeps struct {
-	Logger      *logger.Logger
-	Metrics     *metrics.Metrics
-	AuthHandler *handler.AuthHandler
+	Logger              *logger.Logger
+	Metrics             *metrics.Metrics
+	AuthHandler         *handler.AuthHandler
+	DebugHandler        *handler.DebugHandler
+	AdminHandler        *handler.AdminHandler
+	AdminAPIKey         string
+	MFAHandler          *handler.MFAHandler
+	PasskeyHandler      *handler.PasskeyHandler
+	PasswordlessHandler *handler.PasswordlessHandler
+	FederationHandler   *handler.FederationHandler
+	SAMLHandler         *handler.SAMLHandler
+	OAuthHandler        *handler.OAuthHandler
+	DeviceHandler       *handler.DeviceHandler
+	ConsentHandler      *handler.ConsentHandler
+	LogoutHandler       *handler.LogoutHandler
+	OIDCHandler         *handler.OIDCHandler
+	RegistrationHandler *handler.RegistrationHandler
+	// RegistrationToken is the initial access token that registering a
+	// client requires; registration is closed when it is empty.
+	RegistrationToken string
+	// DeviceAuthorization reports whether the device authorization grant
+	// is on.
+	DeviceAuthorization bool
+	TokenService        port.TokenService
+	Revocations         port.AccessTokenRevocations
 }
 
 func New(deps RouterDeps) *gin.Engine {
@@ -35,6 +58,62 @@ func New(deps RouterDeps) *gin.Engine {
 
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	if deps.DebugHandler != nil {
+		debug := r.Group("/debug")
+		{
+			debug.GET("/mailbox", deps.DebugHandler.ListMailbox)
+			debug.DELETE("/mailbox", deps.DebugHandler.ClearMailbox)
+		}
+	}
+
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/openid-configuration", deps.OIDCHandler.Discovery)
+		wellKnown.GET("/jwks.json", deps.OIDCHandler.JWKS)
+	}
+
+	oauth := r.Group("/oauth")
+	oauth.Use(middleware.RateLimitDefault())
+	{
+		oauth.GET("/authorize", deps.OAuthHandler.Authorize)
+		oauth.POST("/authorize", deps.OAuthHandler.Login)
+		oauth.POST("/token", deps.OAuthHandler.Token)
+		oauth.POST("/introspect", deps.OAuthHandler.Introspect)
+		oauth.POST("/revoke", deps.OAuthHandler.Revoke)
+		if deps.DeviceAuthorization {
+			oauth.POST("/device_authorization", deps.OAuthHandler.DeviceAuthorization)
+			oauth.GET("/device", deps.DeviceHandler.Page)
+			oauth.POST("/device", deps.DeviceHandler.Verify)
+		}
+		oauth.GET("/consent", deps.ConsentHandler.Page)
+		oauth.POST("/consent", deps.ConsentHandler.Decide)
+		oauth.GET("/logout", deps.LogoutHandler.EndSession)
+		oauth.POST("/logout", deps.LogoutHandler.EndSession)
+
+		// Registered clients keep managing their registration with
+		// their own token even while registration is closed.
+		if deps.RegistrationToken != "" {
+			oauth.POST("/register", middleware.RegistrationAuth(deps.RegistrationToken), deps.RegistrationHandler.Register)
+		}
+		oauth.GET("/register/:client_id", deps.RegistrationHandler.Read)
+		oauth.PUT("/register/:client_id", deps.RegistrationHandler.Update)
+		oauth.DELETE("/register/:client_id", deps.RegistrationHandler.Delete)
+
+		userInfo := oauth.Group("/userinfo")
+		userInfo.Use(middleware.AuthenticateClient(deps.TokenService, deps.Revocations))
+		{
+			userInfo.GET("", deps.OIDCHandler.UserInfo)
+			userInfo.POST("", deps.OIDCHandler.UserInfo)
+		}
+	}
+
+	samlGroup := r.Group("/saml")
+	samlGroup.Use(middleware.RateLimitDefault())
+	{
+		samlGroup.GET("/:idp/metadata", deps.SAMLHandler.Metadata)
+		samlGroup.POST("/:idp/acs", deps.SAMLHandler.ACS)
+	}
+
 	api := r.Group("/api/v1")
 	api.Use(middleware.RateLimitDefault())
 	{
@@ -42,7 +121,65 @@ func New(deps RouterDeps) *gin.Engine {
 		{
 			auth.POST("/register", deps.AuthHandler.Register)
 			auth.POST("/login", deps.AuthHandler.Login)
+			auth.POST("/refresh", deps.AuthHandler.Refresh)
 			auth.POST("/forgot-password", deps.AuthHandler.ForgotPassword)
+			auth.POST("/reset-password", deps.AuthHandler.ResetPassword)
+			auth.POST("/verify-email", deps.AuthHandler.VerifyEmail)
+			auth.POST("/resend-verification", deps.AuthHandler.ResendVerification)
+			auth.POST("/mfa/challenge", deps.MFAHandler.Challenge)
+			auth.POST("/mfa/passkey/options", deps.PasskeyHandler.MFAOptions)
+			auth.POST("/passkey/login/begin", deps.PasskeyHandler.BeginLogin)
+			auth.POST("/passkey/login/finish", deps.PasskeyHandler.FinishLogin)
+			auth.POST("/passwordless/start", deps.PasswordlessHandler.Start)
+			auth.POST("/passwordless/verify", deps.PasswordlessHandler.Verify)
+			auth.GET("/federated", deps.FederationHandler.List)
+			auth.POST("/federated/:provider/start", deps.FederationHandler.Start)
+			auth.POST("/federated/callback", deps.FederationHandler.Callback)
+			auth.POST("/saml/:idp/start", deps.SAMLHandler.Start)
+			auth.POST("/saml/redeem", deps.SAMLHandler.Redeem)
+
+			totp := auth.Group("/mfa/totp")
+			totp.Use(middleware.Authenticate(deps.TokenService, deps.Revocations))
+			{
+				totp.POST("/setup", deps.MFAHandler.SetupTOTP)
+				totp.POST("/confirm", deps.MFAHandler.ConfirmTOTP)
+				totp.POST("/disable", deps.MFAHandler.DisableTOTP)
+			}
+
+			passkeys := auth.Group("/passkeys")
+			passkeys.Use(middleware.Authenticate(deps.TokenService, deps.Revocations))
+			{
+				passkeys.GET("", deps.PasskeyHandler.List)
+				passkeys.POST("/register/begin", deps.PasskeyHandler.BeginRegistration)
+				passkeys.POST("/register/finish", deps.PasskeyHandler.FinishRegistration)
+				passkeys.DELETE("/:id", deps.PasskeyHandler.Delete)
+			}
+
+			if deps.DeviceAuthorization {
+				device := auth.Group("/device")
+				device.Use(middleware.Authenticate(deps.TokenService, deps.Revocations))
+				{
+					device.GET("", deps.DeviceHandler.Lookup)
+					device.POST("", deps.DeviceHandler.Decide)
+				}
+			}
+		}
+
+		me := api.Group("/me")
+		me.Use(middleware.Authenticate(deps.TokenService, deps.Revocations))
+		{
+			me.GET("/consents", deps.ConsentHandler.List)
+			me.DELETE("/consents/:client_id", deps.ConsentHandler.Revoke)
+		}
+
+		if deps.AdminHandler != nil {
+			admin := api.Group("/admin")
+			admin.Use(middleware.AdminAuth(deps.AdminAPIKey))
+			{
+				admin.POST("/users/:id/unlock", deps.AdminHandler.UnlockAccount)
+				admin.GET("/oauth/clients", deps.AdminHandler.ListOAuthClients)
+				admin.POST("/oauth/clients", deps.AdminHandler.CreateOAuthClient)
+			}
 		}
 	}
 
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN failed_login_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_failed_login_at TIMESTAMPTZ,
+    ADD COLUMN lockout_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMPTZ;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS passwordless_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    device_hash VARCHAR(64) NOT NULL UNIQUE,
+    secret_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_passwordless_challenges_user_id_created_at ON passwordless_challenges(user_id, created_at DESC);
+CREATE INDEX idx_passwordless_challenges_expires_at ON passwordless_challenges(expires_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_client_id;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS scopes,
+    DROP COLUMN IF EXISTS client_id;
+
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
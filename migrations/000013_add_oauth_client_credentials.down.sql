@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS oauth_client_assertions;
+
+ALTER TABLE oauth_clients
+    DROP COLUMN IF EXISTS access_token_ttl_sec,
+    DROP COLUMN IF EXISTS jwks;
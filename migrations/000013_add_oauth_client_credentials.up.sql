@@ -0,0 +1,12 @@
+ALTER TABLE oauth_clients
+    ADD COLUMN jwks TEXT NOT NULL DEFAULT '',
+    ADD COLUMN access_token_ttl_sec INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS oauth_client_assertions (
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    jti VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (client_id, jti)
+);
+
+CREATE INDEX idx_oauth_client_assertions_expires_at ON oauth_client_assertions(expires_at);
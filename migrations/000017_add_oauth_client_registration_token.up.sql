@@ -0,0 +1,2 @@
+ALTER TABLE oauth_clients
+    ADD COLUMN registration_token_hash VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS oauth_consent_challenges;
+DROP TABLE IF EXISTS user_consents;
+
+ALTER TABLE oauth_clients
+    DROP COLUMN IF EXISTS first_party;
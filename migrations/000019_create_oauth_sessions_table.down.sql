@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS oauth_sessions;
+
+ALTER TABLE oauth_clients
+    DROP COLUMN IF EXISTS frontchannel_logout_uri,
+    DROP COLUMN IF EXISTS backchannel_logout_uri,
+    DROP COLUMN IF EXISTS post_logout_redirect_uris;
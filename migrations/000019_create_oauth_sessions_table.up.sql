@@ -0,0 +1,14 @@
+ALTER TABLE oauth_clients
+    ADD COLUMN post_logout_redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN backchannel_logout_uri TEXT NOT NULL DEFAULT '',
+    ADD COLUMN frontchannel_logout_uri TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS oauth_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_oauth_sessions_user_id ON oauth_sessions(user_id) WHERE ended_at IS NULL;
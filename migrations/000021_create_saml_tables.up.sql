@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS saml_requests (
+    id VARCHAR(64) PRIMARY KEY,
+    idp VARCHAR(50) NOT NULL,
+    device_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_saml_requests_expires_at ON saml_requests(expires_at);
+
+CREATE TABLE IF NOT EXISTS saml_logins (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    idp VARCHAR(50) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    device_hash VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_saml_logins_expires_at ON saml_logins(expires_at);
+
+CREATE TABLE IF NOT EXISTS saml_assertions (
+    idp VARCHAR(50) NOT NULL,
+    assertion_id VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (idp, assertion_id)
+);
+
+CREATE INDEX idx_saml_assertions_expires_at ON saml_assertions(expires_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS access_token_cutoffs (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    issued_before TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_access_token_cutoffs_expires_at ON access_token_cutoffs(expires_at);
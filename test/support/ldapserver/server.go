@@ -0,0 +1,582 @@
+// Package ldapserver is an in-process LDAP server for tests. It answers
+// simple binds, subtree searches and StartTLS over its own BER codec, so
+// directory client code can be exercised offline and without the encoding
+// under test.
+package ldapserver
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	opBindRequest      = 0x60
+	opBindResponse     = 0x61
+	opUnbindRequest    = 0x42
+	opSearchRequest    = 0x63
+	opSearchEntry      = 0x64
+	opSearchDone       = 0x65
+	opExtendedRequest  = 0x77
+	opExtendedResponse = 0x78
+
+	oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+	// Result codes the server answers with.
+	ResultSuccess                  = 0
+	ResultProtocolError            = 2
+	ResultSizeLimitExceeded        = 4
+	ResultConfidentialityRequired  = 13
+	ResultInvalidCredentials       = 49
+	ResultInsufficientAccessRights = 50
+	ResultUnwillingToPerform       = 53
+)
+
+// Entry is a directory entry. Entries with a Password can be bound as.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server is an LDAP server listening on the loopback interface.
+type Server struct {
+	// RequireTLS refuses binds over connections that are not encrypted.
+	RequireTLS bool
+
+	listener    net.Listener
+	ldaps       bool
+	tlsConfig   *tls.Config
+	certificate *x509.Certificate
+
+	mu      sync.Mutex
+	entries []Entry
+	binds   []string
+	conns   map[net.Conn]struct{}
+	wg      sync.WaitGroup
+}
+
+// New starts an ldap:// server, which also offers StartTLS. The server
+// stops when the test ends.
+func New(tb testing.TB) *Server {
+	return start(tb, false)
+}
+
+// NewTLS starts an ldaps:// server.
+func NewTLS(tb testing.TB) *Server {
+	return start(tb, true)
+}
+
+func start(tb testing.TB, ldaps bool) *Server {
+	tb.Helper()
+
+	s := &Server{ldaps: ldaps, conns: make(map[net.Conn]struct{})}
+	s.tlsConfig = s.generateCertificate(tb)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("ldapserver: listen: %v", err)
+	}
+	if ldaps {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go s.serve()
+	tb.Cleanup(s.close)
+	return s
+}
+
+func (s *Server) generateCertificate(tb testing.TB) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatalf("ldapserver: generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ldapserver"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:     []string{"localhost"},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatalf("ldapserver: create certificate: %v", err)
+	}
+	s.certificate, err = x509.ParseCertificate(der)
+	if err != nil {
+		tb.Fatalf("ldapserver: parse certificate: %v", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// URL is the server's ldap:// or ldaps:// URL.
+func (s *Server) URL() string {
+	if s.ldaps {
+		return "ldaps://" + s.listener.Addr().String()
+	}
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *Server) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// CertificatePEM returns the server's self-signed certificate PEM
+// encoded, as it would be configured.
+func (s *Server) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.certificate.Raw}))
+}
+
+// Add adds an entry, or replaces the one with the same DN.
+func (s *Server) Add(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.entries {
+		if normalizeDN(s.entries[i].DN) == normalizeDN(entry.DN) {
+			s.entries[i] = entry
+			return
+		}
+	}
+	s.entries = append(s.entries, entry)
+}
+
+// Binds returns the DNs of the bind requests received so far, whether
+// they succeeded or not.
+func (s *Server) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *Server) close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, c)
+				s.mu.Unlock()
+			}()
+			(&session{server: s, conn: c, encrypted: s.ldaps}).run()
+		}()
+	}
+}
+
+// session is one client connection.
+type session struct {
+	server    *Server
+	conn      net.Conn
+	reader    *bufio.Reader
+	encrypted bool
+	bound     bool
+}
+
+func (ss *session) run() {
+	defer func() { ss.conn.Close() }()
+	ss.reader = bufio.NewReader(ss.conn)
+
+	for {
+		message, err := readElement(ss.reader)
+		if err != nil || len(message.children) < 2 {
+			return
+		}
+		id := message.children[0].content
+		op := message.children[1]
+
+		switch op.tag {
+		case opBindRequest:
+			ss.bind(id, op)
+		case opSearchRequest:
+			ss.search(id, op)
+		case opExtendedRequest:
+			if !ss.extended(id, op) {
+				return
+			}
+		case opUnbindRequest:
+			return
+		default:
+			// Nothing else is implemented; drop the connection.
+			return
+		}
+	}
+}
+
+func (ss *session) bind(id []byte, op element) {
+	ss.bound = false
+	if len(op.children) < 3 || op.children[2].tag != 0x80 {
+		ss.reply(id, result(opBindResponse, ResultProtocolError, "only simple binds are supported"))
+		return
+	}
+	dn, password := string(op.children[1].content), string(op.children[2].content)
+
+	ss.server.mu.Lock()
+	ss.server.binds = append(ss.server.binds, dn)
+	ss.server.mu.Unlock()
+
+	switch {
+	case ss.server.RequireTLS && !ss.encrypted:
+		ss.reply(id, result(opBindResponse, ResultConfidentialityRequired, "TLS is required"))
+	case dn == "" && password == "":
+		ss.reply(id, result(opBindResponse, ResultSuccess, ""))
+	case password == "":
+		ss.reply(id, result(opBindResponse, ResultUnwillingToPerform, "unauthenticated binds are not allowed"))
+	default:
+		entry, ok := ss.server.find(dn)
+		if !ok || entry.Password == "" || entry.Password != password {
+			ss.reply(id, result(opBindResponse, ResultInvalidCredentials, "invalid credentials"))
+			return
+		}
+		ss.bound = true
+		ss.reply(id, result(opBindResponse, ResultSuccess, ""))
+	}
+}
+
+func (ss *session) search(id []byte, op element) {
+	if !ss.bound {
+		ss.reply(id, result(opSearchDone, ResultInsufficientAccessRights, "bind first"))
+		return
+	}
+	if len(op.children) < 8 {
+		ss.reply(id, result(opSearchDone, ResultProtocolError, "malformed search"))
+		return
+	}
+	base := normalizeDN(string(op.children[0].content))
+	sizeLimit := toInt(op.children[3].content)
+	filter := op.children[6]
+	var requested []string
+	for _, attribute := range op.children[7].children {
+		requested = append(requested, string(attribute.content))
+	}
+
+	ss.server.mu.Lock()
+	entries := append([]Entry(nil), ss.server.entries...)
+	ss.server.mu.Unlock()
+
+	sent := 0
+	for _, entry := range entries {
+		dn := normalizeDN(entry.DN)
+		if dn != base && !strings.HasSuffix(dn, ","+base) {
+			continue
+		}
+		if !matches(filter, entry) {
+			continue
+		}
+		if sizeLimit > 0 && sent == sizeLimit {
+			ss.reply(id, result(opSearchDone, ResultSizeLimitExceeded, ""))
+			return
+		}
+		ss.reply(id, searchEntry(entry, requested))
+		sent++
+	}
+	ss.reply(id, result(opSearchDone, ResultSuccess, ""))
+}
+
+// extended answers StartTLS, the only extended operation supported, and
+// reports whether the session goes on.
+func (ss *session) extended(id []byte, op element) bool {
+	if len(op.children) == 0 || string(op.children[0].content) != oidStartTLS || ss.encrypted {
+		ss.reply(id, result(opExtendedResponse, ResultProtocolError, "unsupported extended operation"))
+		return true
+	}
+	ss.reply(id, result(opExtendedResponse, ResultSuccess, ""))
+
+	tlsConn := tls.Server(ss.conn, ss.server.tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return false
+	}
+	ss.conn = tlsConn
+	ss.reader = bufio.NewReader(tlsConn)
+	ss.encrypted = true
+	return true
+}
+
+func (ss *session) reply(id []byte, op []byte) {
+	_, _ = ss.conn.Write(encode(0x30, encode(0x02, id), op))
+}
+
+func (s *Server) find(dn string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.entries {
+		if normalizeDN(entry.DN) == normalizeDN(dn) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+func result(op byte, code int, message string) []byte {
+	return encode(op, encode(0x0a, []byte{byte(code)}), encode(0x04), encode(0x04, []byte(message)))
+}
+
+func searchEntry(entry Entry, requested []string) []byte {
+	var attributes [][]byte
+	for name, values := range entry.Attributes {
+		if len(requested) > 0 && !containsFold(requested, name) {
+			continue
+		}
+		var encoded [][]byte
+		for _, value := range values {
+			encoded = append(encoded, encode(0x04, []byte(value)))
+		}
+		attributes = append(attributes, encode(0x30, encode(0x04, []byte(name)), encode(0x31, encoded...)))
+	}
+	return encode(opSearchEntry, encode(0x04, []byte(entry.DN)), encode(0x30, attributes...))
+}
+
+// matches evaluates a search filter against an entry. Values compare
+// case-insensitively, and equal DNs match however they are spaced;
+// objectClass is taken to be present on every entry.
+func matches(filter element, entry Entry) bool {
+	switch filter.tag {
+	case 0xa0:
+		for _, child := range filter.children {
+			if !matches(child, entry) {
+				return false
+			}
+		}
+		return true
+	case 0xa1:
+		for _, child := range filter.children {
+			if matches(child, entry) {
+				return true
+			}
+		}
+		return false
+	case 0xa2:
+		return len(filter.children) == 1 && !matches(filter.children[0], entry)
+	case 0x87:
+		name := string(filter.content)
+		return strings.EqualFold(name, "objectClass") || len(values(entry, name)) > 0
+	case 0xa3, 0xa8:
+		if len(filter.children) != 2 {
+			return false
+		}
+		want := string(filter.children[1].content)
+		for _, value := range values(entry, string(filter.children[0].content)) {
+			if normalizeDN(value) == normalizeDN(want) {
+				return true
+			}
+		}
+		return false
+	case 0xa4:
+		if len(filter.children) != 2 {
+			return false
+		}
+		for _, value := range values(entry, string(filter.children[0].content)) {
+			if matchesSubstrings(strings.ToLower(value), filter.children[1].children) {
+				return true
+			}
+		}
+		return false
+	case 0xa5, 0xa6:
+		if len(filter.children) != 2 {
+			return false
+		}
+		want := strings.ToLower(string(filter.children[1].content))
+		for _, value := range values(entry, string(filter.children[0].content)) {
+			value = strings.ToLower(value)
+			if filter.tag == 0xa5 && value >= want || filter.tag == 0xa6 && value <= want {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func matchesSubstrings(value string, parts []element) bool {
+	for _, part := range parts {
+		s := strings.ToLower(string(part.content))
+		switch part.tag {
+		case 0x80:
+			if !strings.HasPrefix(value, s) {
+				return false
+			}
+			value = value[len(s):]
+		case 0x81:
+			i := strings.Index(value, s)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(s):]
+		case 0x82:
+			if !strings.HasSuffix(value, s) {
+				return false
+			}
+			value = ""
+		}
+	}
+	return true
+}
+
+func values(entry Entry, name string) []string {
+	for attribute, values := range entry.Attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDN(dn string) string {
+	rdns := strings.Split(strings.ToLower(dn), ",")
+	for i, rdn := range rdns {
+		attribute, value, _ := strings.Cut(rdn, "=")
+		rdns[i] = strings.TrimSpace(attribute) + "=" + strings.TrimSpace(value)
+	}
+	return strings.Join(rdns, ",")
+}
+
+// element is a decoded BER element.
+type element struct {
+	tag      byte
+	content  []byte
+	children []element
+}
+
+func readElement(r *bufio.Reader) (element, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 3 {
+			return element{}, errors.New("ldapserver: unsupported length")
+		}
+		length = 0
+		for range n {
+			b, err := r.ReadByte()
+			if err != nil {
+				return element{}, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return element{}, err
+	}
+	return parse(tag, content)
+}
+
+func parse(tag byte, content []byte) (element, error) {
+	e := element{tag: tag, content: content}
+	if tag&0x20 == 0 {
+		return e, nil
+	}
+	for len(content) > 0 {
+		if len(content) < 2 {
+			return element{}, errors.New("ldapserver: truncated element")
+		}
+		childTag, length, header := content[0], int(content[1]), 2
+		if content[1]&0x80 != 0 {
+			n := int(content[1] & 0x7f)
+			if n == 0 || n > 3 || len(content) < 2+n {
+				return element{}, errors.New("ldapserver: unsupported length")
+			}
+			length = 0
+			for _, b := range content[2 : 2+n] {
+				length = length<<8 | int(b)
+			}
+			header += n
+		}
+		if len(content) < header+length {
+			return element{}, errors.New("ldapserver: truncated element")
+		}
+		child, err := parse(childTag, content[header:header+length])
+		if err != nil {
+			return element{}, err
+		}
+		e.children = append(e.children, child)
+		content = content[header+length:]
+	}
+	return e, nil
+}
+
+func encode(tag byte, content ...[]byte) []byte {
+	var body []byte
+	for _, c := range content {
+		body = append(body, c...)
+	}
+	out := []byte{tag}
+	switch n := len(body); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, body...)
+}
+
+func toInt(content []byte) int {
+	n := 0
+	for _, b := range content {
+		n = n<<8 | int(b)
+	}
+	return n
+}
@@ -0,0 +1,308 @@
+// Package samlidp is a SAML 2.0 identity provider for tests. It signs
+// responses with an RSA key and self-signed certificate generated when it
+// starts. Responses are written directly in exclusive canonical form, so
+// their signatures are computed without the canonicalization code under
+// test.
+package samlidp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	dsigNamespace      = "http://www.w3.org/2000/09/xmldsig#"
+
+	// NameIDPersistent and NameIDTransient are NameID formats.
+	NameIDPersistent = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDTransient  = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+	// StatusSuccess and StatusRequester are status codes.
+	StatusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	StatusRequester = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+)
+
+// Attribute is an attribute the identity provider asserts.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// User is who the identity provider signs in.
+type User struct {
+	NameID       string
+	NameIDFormat string
+	Attributes   []Attribute
+}
+
+// IdP is an identity provider with its own signing key.
+type IdP struct {
+	EntityID string
+	// User is signed in by every response.
+	User User
+
+	key         *rsa.PrivateKey
+	certificate *x509.Certificate
+}
+
+// New generates an identity provider's key pair and certificate.
+func New(tb testing.TB) *IdP {
+	tb.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tb.Fatalf("samlidp: generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "samlidp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatalf("samlidp: create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		tb.Fatalf("samlidp: parse certificate: %v", err)
+	}
+
+	return &IdP{
+		EntityID: "https://idp.example.com/saml",
+		User: User{
+			NameID:       "00u1a2b3c4d5e6f7",
+			NameIDFormat: NameIDPersistent,
+			Attributes: []Attribute{
+				{Name: "email", Value: "jane.doe@example.com"},
+				{Name: "username", Value: "jane.doe"},
+			},
+		},
+		key:         key,
+		certificate: certificate,
+	}
+}
+
+func (p *IdP) Certificate() *x509.Certificate {
+	return p.certificate
+}
+
+// CertificatePEM returns the certificate PEM encoded, as it would be
+// configured.
+func (p *IdP) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.certificate.Raw}))
+}
+
+// Response describes a response to issue.
+type Response struct {
+	// ACSURL is the response's destination and the recipient of the
+	// assertion's bearer confirmation.
+	ACSURL string
+	// Audience is the service provider entity ID the assertion is
+	// restricted to.
+	Audience     string
+	InResponseTo string
+	// Status defaults to StatusSuccess.
+	Status string
+	// IssuedAt defaults to now.
+	IssuedAt time.Time
+	// Lifetime is how long after IssuedAt the assertion is valid, five
+	// minutes by default.
+	Lifetime time.Duration
+	// SignResponse signs the response instead of the assertion.
+	SignResponse bool
+	// AssertionHook, when set, may rewrite the assertion before it is
+	// signed. What it returns must still be in canonical form.
+	AssertionHook func(assertion string) string
+	// Hook, when set, may rewrite the signed document before it is
+	// encoded.
+	Hook func(document string) string
+}
+
+// Respond returns a base64 encoded response, as posted to the assertion
+// consumer service.
+func (p *IdP) Respond(r Response) string {
+	if r.Status == "" {
+		r.Status = StatusSuccess
+	}
+	if r.IssuedAt.IsZero() {
+		r.IssuedAt = time.Now()
+	}
+	if r.Lifetime == 0 {
+		r.Lifetime = 5 * time.Minute
+	}
+
+	assertion := p.assertion(r)
+	if r.AssertionHook != nil {
+		assertion = r.AssertionHook(assertion)
+	}
+	if !r.SignResponse {
+		assertion = p.sign(assertion)
+	}
+
+	document := `<samlp:Response xmlns:samlp="` + protocolNamespace + `"` +
+		` Destination="` + escapeAttr(r.ACSURL) + `" ID="` + newID() + `"` + inResponseTo(r.InResponseTo) +
+		` IssueInstant="` + formatTime(r.IssuedAt) + `" Version="2.0">` +
+		`<saml:Issuer xmlns:saml="` + assertionNamespace + `">` + escapeText(p.EntityID) + `</saml:Issuer>` +
+		`<samlp:Status><samlp:StatusCode Value="` + r.Status + `"></samlp:StatusCode></samlp:Status>` +
+		assertion +
+		`</samlp:Response>`
+	if r.SignResponse {
+		document = p.sign(document)
+	}
+
+	if r.Hook != nil {
+		document = r.Hook(document)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(document))
+}
+
+func (p *IdP) assertion(r Response) string {
+	notOnOrAfter := formatTime(r.IssuedAt.Add(r.Lifetime))
+
+	var attributes strings.Builder
+	for _, attribute := range p.User.Attributes {
+		attributes.WriteString(`<saml:Attribute Name="` + escapeAttr(attribute.Name) + `">` +
+			`<saml:AttributeValue>` + escapeText(attribute.Value) + `</saml:AttributeValue></saml:Attribute>`)
+	}
+	statement := ""
+	if attributes.Len() > 0 {
+		statement = `<saml:AttributeStatement>` + attributes.String() + `</saml:AttributeStatement>`
+	}
+
+	return `<saml:Assertion xmlns:saml="` + assertionNamespace + `" ID="` + newID() + `"` +
+		` IssueInstant="` + formatTime(r.IssuedAt) + `" Version="2.0">` +
+		`<saml:Issuer>` + escapeText(p.EntityID) + `</saml:Issuer>` +
+		`<saml:Subject>` +
+		`<saml:NameID Format="` + escapeAttr(p.User.NameIDFormat) + `">` + escapeText(p.User.NameID) + `</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">` +
+		`<saml:SubjectConfirmationData` + inResponseTo(r.InResponseTo) + ` NotOnOrAfter="` + notOnOrAfter + `"` +
+		` Recipient="` + escapeAttr(r.ACSURL) + `"></saml:SubjectConfirmationData>` +
+		`</saml:SubjectConfirmation>` +
+		`</saml:Subject>` +
+		`<saml:Conditions NotBefore="` + formatTime(r.IssuedAt) + `" NotOnOrAfter="` + notOnOrAfter + `">` +
+		`<saml:AudienceRestriction><saml:Audience>` + escapeText(r.Audience) + `</saml:Audience></saml:AudienceRestriction>` +
+		`</saml:Conditions>` +
+		`<saml:AuthnStatement AuthnInstant="` + formatTime(r.IssuedAt) + `">` +
+		`<saml:AuthnContext><saml:AuthnContextClassRef>urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport</saml:AuthnContextClassRef></saml:AuthnContext>` +
+		`</saml:AuthnStatement>` +
+		statement +
+		`</saml:Assertion>`
+}
+
+// sign adds an enveloped signature to element, which must be in
+// canonical form, right after its issuer as the schema places it.
+func (p *IdP) sign(element string) string {
+	id := element[strings.Index(element, ` ID="`)+5:]
+	id = id[:strings.Index(id, `"`)]
+
+	digest := sha256.Sum256([]byte(element))
+	signedInfo := func(declaration string) string {
+		return `<ds:SignedInfo` + declaration + `>` +
+			`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:CanonicalizationMethod>` +
+			`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></ds:SignatureMethod>` +
+			`<ds:Reference URI="#` + id + `">` +
+			`<ds:Transforms>` +
+			`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"></ds:Transform>` +
+			`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:Transform>` +
+			`</ds:Transforms>` +
+			`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></ds:DigestMethod>` +
+			`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue>` +
+			`</ds:Reference>` +
+			`</ds:SignedInfo>`
+	}
+
+	// Canonicalized on its own, SignedInfo declares the namespace it
+	// inherits from Signature in the document.
+	hashed := sha256.Sum256([]byte(signedInfo(` xmlns:ds="` + dsigNamespace + `"`)))
+	value, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		panic("samlidp: sign: " + err.Error())
+	}
+
+	signature := `<ds:Signature xmlns:ds="` + dsigNamespace + `">` +
+		signedInfo("") +
+		`<ds:SignatureValue>` + base64.StdEncoding.EncodeToString(value) + `</ds:SignatureValue>` +
+		`<ds:KeyInfo><ds:X509Data><ds:X509Certificate>` + base64.StdEncoding.EncodeToString(p.certificate.Raw) + `</ds:X509Certificate></ds:X509Data></ds:KeyInfo>` +
+		`</ds:Signature>`
+
+	at := strings.Index(element, `</saml:Issuer>`) + len(`</saml:Issuer>`)
+	return element[:at] + signature + element[at:]
+}
+
+// AuthnRequest is what the identity provider reads from a request.
+type AuthnRequest struct {
+	ID                          string `xml:"ID,attr"`
+	Destination                 string `xml:"Destination,attr"`
+	AssertionConsumerServiceURL string `xml:"AssertionConsumerServiceURL,attr"`
+	ProtocolBinding             string `xml:"ProtocolBinding,attr"`
+	Issuer                      string `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+}
+
+// ParseRequest decodes a base64 encoded request, as posted to the single
+// sign-on service.
+func ParseRequest(tb testing.TB, samlRequest string) AuthnRequest {
+	tb.Helper()
+
+	data, err := base64.StdEncoding.DecodeString(samlRequest)
+	if err != nil {
+		tb.Fatalf("samlidp: decode request: %v", err)
+	}
+	var request struct {
+		XMLName xml.Name
+		AuthnRequest
+	}
+	if err := xml.Unmarshal(data, &request); err != nil {
+		tb.Fatalf("samlidp: parse request: %v", err)
+	}
+	if request.XMLName.Space != protocolNamespace || request.XMLName.Local != "AuthnRequest" {
+		tb.Fatalf("samlidp: not an AuthnRequest: %v", request.XMLName)
+	}
+	return request.AuthnRequest
+}
+
+func inResponseTo(id string) string {
+	if id == "" {
+		return ""
+	}
+	return ` InResponseTo="` + escapeAttr(id) + `"`
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("samlidp: " + err.Error())
+	}
+	return "_" + hex.EncodeToString(b)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Text and attribute values are escaped as canonicalization escapes them.
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,266 @@
+// Package softauthn is an in-memory WebAuthn authenticator for tests. It
+// produces registration and assertion responses the way a browser and a
+// platform authenticator would, so ceremonies can be exercised offline.
+package softauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+)
+
+const (
+	FormatNone = "none"
+	// FormatPacked is self attestation, signed with the credential key.
+	FormatPacked = "packed"
+	// FormatPackedX5C is packed attestation with a generated attestation
+	// certificate.
+	FormatPackedX5C = "packed-x5c"
+
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagBackupEligible   = 0x08
+	flagBackupState      = 0x10
+	flagAttestedCredData = 0x40
+
+	coseAlgES256 = -7
+)
+
+var ErrUnknownCredential = errors.New("softauthn: unknown credential")
+
+// AAGUID identifies the software authenticator model.
+var AAGUID = []byte("softauthn-test!!")
+
+type credential struct {
+	id         []byte
+	key        *ecdsa.PrivateKey
+	userHandle []byte
+	signCount  uint32
+}
+
+// Authenticator holds ES256 credentials for one relying party.
+type Authenticator struct {
+	RPID   string
+	Origin string
+	// UserVerified controls the UV flag; user presence is always set.
+	UserVerified bool
+	// Counting makes the signature counter grow with every assertion.
+	// Passkey providers that sync credentials leave it at zero.
+	Counting bool
+
+	credentials map[string]*credential
+}
+
+func New(rpID, origin string) *Authenticator {
+	return &Authenticator{
+		RPID:         rpID,
+		Origin:       origin,
+		UserVerified: true,
+		Counting:     true,
+		credentials:  make(map[string]*credential),
+	}
+}
+
+// Registration is what navigator.credentials.create() resolves to.
+type Registration struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AttestationObject []byte
+}
+
+// Assertion is what navigator.credentials.get() resolves to.
+type Assertion struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+// Register creates a credential for userHandle and attests it in format.
+func (a *Authenticator) Register(challenge, userHandle []byte, format string) (*Registration, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	cred := &credential{id: id, key: key, userHandle: append([]byte(nil), userHandle...)}
+	if a.Counting {
+		cred.signCount = 1
+	}
+	a.credentials[string(id)] = cred
+
+	attested := append([]byte(nil), AAGUID...)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(id)))
+	attested = append(attested, id...)
+	attested = append(attested, COSEKey(&key.PublicKey)...)
+
+	authData := a.authenticatorData(flagAttestedCredData|flagBackupEligible, cred.signCount)
+	authData = append(authData, attested...)
+
+	clientDataJSON := a.clientData("webauthn.create", challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	stmt := Map{}
+	fmtName := format
+	switch format {
+	case FormatNone:
+	case FormatPacked:
+		sig, err := sign(key, signed)
+		if err != nil {
+			return nil, err
+		}
+		stmt = Map{{"alg", coseAlgES256}, {"sig", sig}}
+	case FormatPackedX5C:
+		attestationKey, cert, err := attestationCertificate()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := sign(attestationKey, signed)
+		if err != nil {
+			return nil, err
+		}
+		stmt = Map{{"alg", coseAlgES256}, {"sig", sig}, {"x5c", []any{cert}}}
+		fmtName = FormatPacked
+	default:
+		return nil, errors.New("softauthn: unknown attestation format " + format)
+	}
+
+	attestationObject := Encode(Map{
+		{"fmt", fmtName},
+		{"attStmt", stmt},
+		{"authData", authData},
+	})
+
+	return &Registration{
+		CredentialID:      id,
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	}, nil
+}
+
+// Assert signs challenge with the credential, advancing its counter when
+// the authenticator counts.
+func (a *Authenticator) Assert(credentialID, challenge []byte) (*Assertion, error) {
+	cred, ok := a.credentials[string(credentialID)]
+	if !ok {
+		return nil, ErrUnknownCredential
+	}
+	if a.Counting {
+		cred.signCount++
+	}
+
+	authData := a.authenticatorData(flagBackupEligible|flagBackupState, cred.signCount)
+	clientDataJSON := a.clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	sig, err := sign(cred.key, append(append([]byte(nil), authData...), clientDataHash[:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Assertion{
+		CredentialID:      cred.id,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         sig,
+		UserHandle:        cred.userHandle,
+	}, nil
+}
+
+// SetSignCount overwrites a credential's counter, e.g. to imitate a clone.
+func (a *Authenticator) SetSignCount(credentialID []byte, count uint32) {
+	if cred, ok := a.credentials[string(credentialID)]; ok {
+		cred.signCount = count
+	}
+}
+
+func (a *Authenticator) authenticatorData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags |= flagUserPresent
+	if a.UserVerified {
+		flags |= flagUserVerified
+	}
+
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+func (a *Authenticator) clientData(ceremony string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+// COSEKey encodes an ES256 public key as a COSE_Key.
+func COSEKey(key *ecdsa.PublicKey) []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	return Encode(Map{
+		{1, 2},
+		{3, coseAlgES256},
+		{-1, 1},
+		{-2, x},
+		{-3, y},
+	})
+}
+
+func sign(key *ecdsa.PrivateKey, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	return ecdsa.SignASN1(rand.Reader, key, digest[:])
+}
+
+func attestationCertificate() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	aaguid, err := asn1.Marshal(AAGUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"softauthn"},
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+			CommonName:         "softauthn attestation",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}, Value: aaguid},
+		},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, der, nil
+}
@@ -0,0 +1,74 @@
+package softauthn
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Entry is one key/value pair of a CBOR map.
+type Entry struct {
+	Key   any
+	Value any
+}
+
+// Map is a CBOR map that keeps its keys in the order given.
+type Map []Entry
+
+// Encode writes v as CBOR. It supports int, int64, uint32, string,
+// []byte, []any and Map, which is all WebAuthn responses need.
+func Encode(v any) []byte {
+	return appendItem(nil, v)
+}
+
+func appendItem(out []byte, v any) []byte {
+	switch v := v.(type) {
+	case int:
+		return appendInt(out, int64(v))
+	case int64:
+		return appendInt(out, v)
+	case uint32:
+		return appendHead(out, 0, uint64(v))
+	case []byte:
+		return append(appendHead(out, 2, uint64(len(v))), v...)
+	case string:
+		return append(appendHead(out, 3, uint64(len(v))), v...)
+	case []any:
+		out = appendHead(out, 4, uint64(len(v)))
+		for _, item := range v {
+			out = appendItem(out, item)
+		}
+		return out
+	case Map:
+		out = appendHead(out, 5, uint64(len(v)))
+		for _, entry := range v {
+			out = appendItem(out, entry.Key)
+			out = appendItem(out, entry.Value)
+		}
+		return out
+	default:
+		panic(fmt.Sprintf("softauthn: cannot encode %T", v))
+	}
+}
+
+func appendInt(out []byte, v int64) []byte {
+	if v < 0 {
+		return appendHead(out, 1, uint64(-1-v))
+	}
+	return appendHead(out, 0, uint64(v))
+}
+
+func appendHead(out []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(out, major|byte(arg))
+	case arg <= 0xff:
+		return append(out, major|24, byte(arg))
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(out, major|25), uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(out, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(out, major|27), arg)
+	}
+}
@@ -0,0 +1,289 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+)
+
+const oauthServiceClientID = "0190a5b0-7e1c-7b3d-8f4e-c11e00000003"
+
+// addServiceClient registers a client_credentials client that
+// authenticates with private_key_jwt and returns its signing key.
+func (f *oauthFixture) addServiceClient(t *testing.T) *keystore.Key {
+	t.Helper()
+	key, err := keystore.GenerateKey(entity.SigningAlgES256)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []interface{}{key.JWK()}})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+
+	client := entity.NewOAuthClient(oauthServiceClientID, "Billing", "", nil, []string{"profile", "email"}, []string{entity.OAuthGrantClientCredentials})
+	client.JWKS = string(jwks)
+	client.AccessTokenTTL = 5 * time.Minute
+	f.clientRepo.clients[client.ID] = client
+	return key
+}
+
+func clientAssertion(t *testing.T, key *keystore.Key, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(key.Method, claims)
+	tok.Header["kid"] = key.ID
+	signed, err := tok.SignedString(key.Private)
+	if err != nil {
+		t.Fatalf("SignedString() unexpected error: %v", err)
+	}
+	return signed
+}
+
+func serviceAssertionClaims(jti string) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Issuer:    oauthServiceClientID,
+		Subject:   oauthServiceClientID,
+		Audience:  jwt.ClaimStrings{oauthIssuer + "/oauth/token"},
+		ID:        jti,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func clientCredentialsRequest(assertion string) input.OAuthTokenInput {
+	return input.OAuthTokenInput{
+		GrantType:           entity.OAuthGrantClientCredentials,
+		ClientAssertionType: "urn:ietf:params:oauth:client-assertion-type:jwt-bearer",
+		ClientAssertion:     assertion,
+	}
+}
+
+func TestClientCredentials_SecretClient(t *testing.T) {
+	f := newOAuthFixture(t)
+	client := f.clientRepo.clients[oauthConfidentialClientID]
+	client.GrantTypes = append(client.GrantTypes, entity.OAuthGrantClientCredentials)
+
+	out, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantClientCredentials,
+		ClientID:     oauthConfidentialClientID,
+		ClientSecret: oauthClientSecret,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if out.RefreshToken != "" || out.IDToken != "" {
+		t.Error("the client_credentials grant should issue an access token only")
+	}
+	if entity.FormatScope(out.Scopes) != "profile email" {
+		t.Errorf("Scopes = %v, want the client's scopes without openid", out.Scopes)
+	}
+	if len(f.tokens.issued) != 1 || f.tokens.issued[0].UserID != "" || f.tokens.issued[0].ClientID != oauthConfidentialClientID {
+		t.Errorf("issued claims = %+v, want a token for the client alone", f.tokens.issued)
+	}
+
+	if len(f.audit.logs) != 1 {
+		t.Fatalf("audit logs = %v, want one entry", f.audit.actions())
+	}
+	logged := f.audit.logs[0]
+	details := f.audit.details(t, 0)
+	if logged.Action != entity.AuditActionOAuthTokenIssued || logged.UserID != nil || details["client_id"] != oauthConfidentialClientID {
+		t.Errorf("audit log = %+v, want a token issued to the client without a user", logged)
+	}
+}
+
+func TestClientCredentials_Rejections(t *testing.T) {
+	f := newOAuthFixture(t)
+	for _, id := range []string{oauthPublicClientID, oauthConfidentialClientID} {
+		client := f.clientRepo.clients[id]
+		client.GrantTypes = append(client.GrantTypes, entity.OAuthGrantClientCredentials)
+	}
+
+	tests := []struct {
+		name  string
+		input input.OAuthTokenInput
+		code  string
+	}{
+		{"public client", input.OAuthTokenInput{ClientID: oauthPublicClientID}, exception.OAuthUnauthorizedClient},
+		{"openid scope", input.OAuthTokenInput{ClientID: oauthConfidentialClientID, ClientSecret: oauthClientSecret, Scope: "openid"}, exception.OAuthInvalidScope},
+		{"unregistered scope", input.OAuthTokenInput{ClientID: oauthConfidentialClientID, ClientSecret: oauthClientSecret, Scope: "admin"}, exception.OAuthInvalidScope},
+		{"wrong secret", input.OAuthTokenInput{ClientID: oauthConfidentialClientID, ClientSecret: "nope"}, exception.OAuthInvalidClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.GrantType = entity.OAuthGrantClientCredentials
+			_, err := f.tokenUC.Execute(context.Background(), tt.input)
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+
+	t.Run("grant not registered", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		_, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+			GrantType:    entity.OAuthGrantClientCredentials,
+			ClientID:     oauthConfidentialClientID,
+			ClientSecret: oauthClientSecret,
+		})
+		assertOAuthError(t, err, exception.OAuthUnauthorizedClient)
+	})
+}
+
+func TestClientCredentials_PrivateKeyJWT(t *testing.T) {
+	f := newOAuthFixture(t)
+	key := f.addServiceClient(t)
+
+	req := clientCredentialsRequest(clientAssertion(t, key, serviceAssertionClaims("jti-1")))
+	req.Scope = "email"
+	out, err := f.tokenUC.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if entity.FormatScope(out.Scopes) != "email" {
+		t.Errorf("Scopes = %v, want [email]", out.Scopes)
+	}
+	if until := time.Until(out.ExpiresAt); until > 5*time.Minute || until < 4*time.Minute {
+		t.Errorf("token expires in %v, want the client's TTL of 5m", until)
+	}
+	if f.tokens.issued[0].TTL != 5*time.Minute {
+		t.Errorf("issued TTL = %v, want 5m", f.tokens.issued[0].TTL)
+	}
+
+	_, err = f.tokenUC.Execute(context.Background(), req)
+	assertOAuthError(t, err, exception.OAuthInvalidClient)
+}
+
+func TestClientCredentials_PrivateKeyJWTRejections(t *testing.T) {
+	f := newOAuthFixture(t)
+	key := f.addServiceClient(t)
+	other, err := keystore.GenerateKey(entity.SigningAlgES256)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+
+	claims := func(jti string, mutate func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := serviceAssertionClaims(jti)
+		mutate(&c)
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		input input.OAuthTokenInput
+	}{
+		{"unregistered key", clientCredentialsRequest(clientAssertion(t, other, serviceAssertionClaims("a")))},
+		{"other audience", clientCredentialsRequest(clientAssertion(t, key, claims("b", func(c *jwt.RegisteredClaims) {
+			c.Audience = jwt.ClaimStrings{"https://elsewhere.example.com"}
+		})))},
+		{"other issuer", clientCredentialsRequest(clientAssertion(t, key, claims("c", func(c *jwt.RegisteredClaims) {
+			c.Issuer = oauthConfidentialClientID
+		})))},
+		{"no jti", clientCredentialsRequest(clientAssertion(t, key, claims("", func(c *jwt.RegisteredClaims) {})))},
+		{"long lived", clientCredentialsRequest(clientAssertion(t, key, claims("d", func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(24 * time.Hour))
+		})))},
+		{"wrong assertion type", func() input.OAuthTokenInput {
+			req := clientCredentialsRequest(clientAssertion(t, key, serviceAssertionClaims("e")))
+			req.ClientAssertionType = "urn:example:other"
+			return req
+		}()},
+		{"secret instead of assertion", input.OAuthTokenInput{
+			GrantType:    entity.OAuthGrantClientCredentials,
+			ClientID:     oauthServiceClientID,
+			ClientSecret: "guess",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.tokenUC.Execute(context.Background(), tt.input)
+			assertOAuthError(t, err, exception.OAuthInvalidClient)
+		})
+	}
+
+	t.Run("secret client cannot present an assertion", func(t *testing.T) {
+		c := serviceAssertionClaims("f")
+		c.Issuer, c.Subject = oauthConfidentialClientID, oauthConfidentialClientID
+		_, err := f.tokenUC.Execute(context.Background(), clientCredentialsRequest(clientAssertion(t, key, c)))
+		assertOAuthError(t, err, exception.OAuthInvalidClient)
+	})
+}
+
+func TestCreateOAuthClient_ClientCredentials(t *testing.T) {
+	key, err := keystore.GenerateKey(entity.SigningAlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []interface{}{key.JWK()}})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	grants := []string{entity.OAuthGrantClientCredentials}
+
+	t.Run("secret client without redirect URIs", func(t *testing.T) {
+		uc, repo := newCreateOAuthClientUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:           "Billing",
+			Scopes:         []string{"profile"},
+			GrantTypes:     grants,
+			Confidential:   true,
+			AccessTokenTTL: 10 * time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.ClientSecret == "" || out.AuthMethod != entity.OAuthAuthMethodClientSecretBasic {
+			t.Errorf("Execute() = %+v, want a client authenticating with a secret", out)
+		}
+		if repo.clients[out.ClientID].AccessTokenTTL != 10*time.Minute {
+			t.Error("the client's access token TTL should be stored")
+		}
+	})
+
+	t.Run("private_key_jwt client", func(t *testing.T) {
+		uc, repo := newCreateOAuthClientUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:       "Billing",
+			Scopes:     []string{"profile"},
+			GrantTypes: grants,
+			JWKS:       string(jwks),
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.ClientSecret != "" || out.AuthMethod != entity.OAuthAuthMethodPrivateKeyJWT {
+			t.Errorf("Execute() = %+v, want a private_key_jwt client without a secret", out)
+		}
+		stored := repo.clients[out.ClientID]
+		if !stored.IsConfidential() || stored.SecretHash != "" || stored.JWKS != string(jwks) {
+			t.Error("the client's JWK Set should be stored in place of a secret")
+		}
+	})
+
+	rejected := []struct {
+		name  string
+		input input.CreateOAuthClientInput
+	}{
+		{"public client", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: grants}},
+		{"redirect URIs", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, GrantTypes: grants, Confidential: true}},
+		{"refresh token", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: append(grants, entity.OAuthGrantRefreshToken), Confidential: true}},
+		{"invalid JWKS", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: grants, JWKS: `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`}},
+		{"TTL above maximum", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: grants, Confidential: true, AccessTokenTTL: 2 * time.Hour}},
+		{"negative TTL", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: grants, Confidential: true, AccessTokenTTL: -time.Minute}},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newCreateOAuthClientUC()
+			_, err := uc.Execute(context.Background(), tt.input)
+			if !errors.Is(err, exception.ErrInvalidClientMetadata) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidClientMetadata, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+type registrationFixture struct {
+	createUC port.CreateOAuthClientUseCase
+	readUC   port.ReadClientRegistrationUseCase
+	updateUC port.UpdateClientRegistrationUseCase
+	deleteUC port.DeleteClientRegistrationUseCase
+	listUC   port.ListOAuthClientsUseCase
+	repo     *fakeOAuthClientRepo
+	audit    *fakeAuditLogger
+}
+
+func newRegistrationFixture() *registrationFixture {
+	repo := newFakeOAuthClientRepo()
+	audit := &fakeAuditLogger{}
+	opaque := token.NewOpaqueGenerator()
+	assertions := token.NewClientAssertionVerifier()
+
+	return &registrationFixture{
+		createUC: usecase.NewCreateOAuthClientUsecase(repo, audit, noopLogger{}, opaque, &sequentialUUIDGenerator{}, assertions, oauthScopes, time.Hour),
+		readUC:   usecase.NewReadClientRegistrationUsecase(repo, opaque, noopLogger{}),
+		updateUC: usecase.NewUpdateClientRegistrationUsecase(repo, audit, noopLogger{}, opaque, assertions, oauthScopes, time.Hour),
+		deleteUC: usecase.NewDeleteClientRegistrationUsecase(repo, audit, noopLogger{}, opaque),
+		listUC:   usecase.NewListOAuthClientsUsecase(repo, noopLogger{}),
+		repo:     repo,
+		audit:    audit,
+	}
+}
+
+// registerWeb registers a confidential web client the way a client
+// registering itself would, naming only its auth method.
+func (f *registrationFixture) registerWeb(t *testing.T) *output.CreateOAuthClientOutput {
+	t.Helper()
+	out, err := f.createUC.Execute(context.Background(), input.CreateOAuthClientInput{
+		Name:         "Web",
+		RedirectURIs: []string{oauthRedirectURI},
+		Scopes:       []string{"openid", "profile"},
+		AuthMethod:   entity.OAuthAuthMethodClientSecretPost,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestClientRegistration_Register(t *testing.T) {
+	f := newRegistrationFixture()
+	out := f.registerWeb(t)
+
+	if out.ClientSecret == "" || out.RegistrationToken == "" || out.AuthMethod != entity.OAuthAuthMethodClientSecretBasic {
+		t.Errorf("Execute() = %+v, want a secret and a registration access token", out)
+	}
+	stored := f.repo.clients[out.ClientID]
+	if stored.RegistrationTokenHash != token.NewOpaqueGenerator().Hash(out.RegistrationToken) {
+		t.Error("only the hash of the registration access token should be stored")
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthClientCreated)
+}
+
+func TestClientRegistration_Read(t *testing.T) {
+	f := newRegistrationFixture()
+	registered := f.registerWeb(t)
+
+	out, err := f.readUC.Execute(context.Background(), input.ClientRegistrationInput{
+		ClientID:          registered.ClientID,
+		RegistrationToken: registered.RegistrationToken,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.ClientID != registered.ClientID || out.Name != "Web" || entity.FormatScope(out.Scopes) != "openid profile" {
+		t.Errorf("Execute() = %+v, want the registered metadata", out)
+	}
+}
+
+func TestClientRegistration_Update(t *testing.T) {
+	f := newRegistrationFixture()
+	registered := f.registerWeb(t)
+	before := *f.repo.clients[registered.ClientID]
+
+	out, err := f.updateUC.Execute(context.Background(), input.UpdateClientRegistrationInput{
+		ClientID:          registered.ClientID,
+		RegistrationToken: registered.RegistrationToken,
+		Client: input.CreateOAuthClientInput{
+			Name:         "Web app",
+			RedirectURIs: []string{"https://app.example.com/new-callback"},
+			Scopes:       []string{"profile"},
+			AuthMethod:   entity.OAuthAuthMethodClientSecretBasic,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.Name != "Web app" || !slices.Equal(out.RedirectURIs, []string{"https://app.example.com/new-callback"}) {
+		t.Errorf("Execute() = %+v, want the new metadata", out)
+	}
+
+	stored := f.repo.clients[registered.ClientID]
+	if stored.SecretHash != before.SecretHash || stored.RegistrationTokenHash != before.RegistrationTokenHash || !stored.CreatedAt.Equal(before.CreatedAt) {
+		t.Error("an update should keep the client's credentials and creation time")
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthClientCreated, entity.AuditActionOAuthClientUpdated)
+}
+
+func TestClientRegistration_UpdateRejections(t *testing.T) {
+	f := newRegistrationFixture()
+	registered := f.registerWeb(t)
+
+	tests := []struct {
+		name   string
+		client input.CreateOAuthClientInput
+		want   error
+	}{
+		{
+			name:   "auth method change",
+			client: input.CreateOAuthClientInput{Name: "Web", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, AuthMethod: entity.OAuthAuthMethodNone},
+			want:   exception.ErrInvalidClientMetadata,
+		},
+		{
+			name:   "http redirect URI",
+			client: input.CreateOAuthClientInput{Name: "Web", RedirectURIs: []string{"http://app.example.com/cb"}, Scopes: []string{"profile"}, AuthMethod: entity.OAuthAuthMethodClientSecretBasic},
+			want:   exception.ErrInvalidClientRedirectURI,
+		},
+		{
+			name:   "missing redirect URI",
+			client: input.CreateOAuthClientInput{Name: "Web", Scopes: []string{"profile"}, AuthMethod: entity.OAuthAuthMethodClientSecretBasic},
+			want:   exception.ErrInvalidClientRedirectURI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.updateUC.Execute(context.Background(), input.UpdateClientRegistrationInput{
+				ClientID:          registered.ClientID,
+				RegistrationToken: registered.RegistrationToken,
+				Client:            tt.client,
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Execute() expected error %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if stored := f.repo.clients[registered.ClientID]; stored.Name != "Web" {
+		t.Error("a rejected update should leave the client unchanged")
+	}
+}
+
+func TestClientRegistration_LogoutURIs(t *testing.T) {
+	f := newRegistrationFixture()
+	web := input.CreateOAuthClientInput{
+		Name:                   "Web",
+		RedirectURIs:           []string{oauthRedirectURI},
+		Scopes:                 []string{"openid"},
+		PostLogoutRedirectURIs: []string{"https://app.example.com/signed-out"},
+		BackchannelLogoutURI:   "https://app.example.com/backchannel-logout",
+		FrontchannelLogoutURI:  "http://127.0.0.1:8080/frontchannel-logout",
+	}
+	out, err := f.createUC.Execute(context.Background(), web)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if stored := f.repo.clients[out.ClientID]; stored.BackchannelLogoutURI != web.BackchannelLogoutURI ||
+		stored.FrontchannelLogoutURI != web.FrontchannelLogoutURI || !stored.HasPostLogoutRedirectURI("https://app.example.com/signed-out") {
+		t.Errorf("stored %+v, want the logout URIs", stored)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*input.CreateOAuthClientInput)
+	}{
+		{"http back-channel URI", func(in *input.CreateOAuthClientInput) { in.BackchannelLogoutURI = "http://app.example.com/logout" }},
+		{"front-channel URI with fragment", func(in *input.CreateOAuthClientInput) { in.FrontchannelLogoutURI = "https://app.example.com/logout#x" }},
+		{"relative post-logout URI", func(in *input.CreateOAuthClientInput) { in.PostLogoutRedirectURIs = []string{"/signed-out"} }},
+		{"client without sign-in", func(in *input.CreateOAuthClientInput) {
+			in.RedirectURIs = nil
+			in.GrantTypes = []string{entity.OAuthGrantClientCredentials}
+			in.AuthMethod = entity.OAuthAuthMethodClientSecretBasic
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := web
+			tt.change(&client)
+			if _, err := f.createUC.Execute(context.Background(), client); !errors.Is(err, exception.ErrInvalidClientMetadata) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidClientMetadata, err)
+			}
+		})
+	}
+}
+
+func TestClientRegistration_Delete(t *testing.T) {
+	f := newRegistrationFixture()
+	registered := f.registerWeb(t)
+
+	err := f.deleteUC.Execute(context.Background(), input.ClientRegistrationInput{
+		ClientID:          registered.ClientID,
+		RegistrationToken: registered.RegistrationToken,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if _, ok := f.repo.clients[registered.ClientID]; ok {
+		t.Error("the client should be deleted")
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthClientCreated, entity.AuditActionOAuthClientDeleted)
+
+	_, err = f.readUC.Execute(context.Background(), input.ClientRegistrationInput{
+		ClientID:          registered.ClientID,
+		RegistrationToken: registered.RegistrationToken,
+	})
+	if !errors.Is(err, exception.ErrInvalidRegistrationToken) {
+		t.Errorf("Execute() expected error %v after deletion, got %v", exception.ErrInvalidRegistrationToken, err)
+	}
+}
+
+func TestClientRegistration_InvalidToken(t *testing.T) {
+	f := newRegistrationFixture()
+	registered := f.registerWeb(t)
+	other := f.registerWeb(t)
+
+	// Clients added before registration tokens existed have none.
+	legacy := entity.NewOAuthClient("legacy", "Legacy", "", []string{oauthRedirectURI}, []string{"profile"}, []string{entity.OAuthGrantAuthorizationCode})
+	f.repo.clients[legacy.ID] = legacy
+
+	tests := []struct {
+		name  string
+		input input.ClientRegistrationInput
+	}{
+		{"wrong token", input.ClientRegistrationInput{ClientID: registered.ClientID, RegistrationToken: "nope"}},
+		{"another client's token", input.ClientRegistrationInput{ClientID: registered.ClientID, RegistrationToken: other.RegistrationToken}},
+		{"no token", input.ClientRegistrationInput{ClientID: registered.ClientID}},
+		{"unknown client", input.ClientRegistrationInput{ClientID: "unknown", RegistrationToken: registered.RegistrationToken}},
+		{"client without a token", input.ClientRegistrationInput{ClientID: legacy.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.readUC.Execute(context.Background(), tt.input); !errors.Is(err, exception.ErrInvalidRegistrationToken) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRegistrationToken, err)
+			}
+			if err := f.deleteUC.Execute(context.Background(), tt.input); !errors.Is(err, exception.ErrInvalidRegistrationToken) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRegistrationToken, err)
+			}
+		})
+	}
+	if len(f.repo.clients) != 3 {
+		t.Errorf("%d clients left, want all 3", len(f.repo.clients))
+	}
+}
+
+func TestListOAuthClients(t *testing.T) {
+	f := newRegistrationFixture()
+	first := f.registerWeb(t)
+	second := f.registerWeb(t)
+
+	out, err := f.listUC.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].ClientID != first.ClientID || out[1].ClientID != second.ClientID {
+		t.Errorf("Execute() = %+v, want both clients", out)
+	}
+}
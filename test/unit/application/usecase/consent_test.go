@@ -0,0 +1,247 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+// newConsentFixture is the OAuth fixture with the public client turned
+// into a third-party app, which users must consent to.
+func newConsentFixture(t *testing.T) *oauthFixture {
+	t.Helper()
+	f := newOAuthFixture(t)
+	f.clientRepo.clients[oauthPublicClientID].FirstParty = false
+	return f
+}
+
+func (f *oauthFixture) decideConsent(t *testing.T, consentToken string, approve bool) *output.AuthorizeOutput {
+	t.Helper()
+	out, err := f.decideConsentUC.Execute(context.Background(), input.DecideConsentInput{
+		ConsentToken: consentToken,
+		Approve:      approve,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+// consentChallenge returns the stored challenge, so tests can move its
+// clock.
+func (f *oauthFixture) consentChallenge(t *testing.T) *entity.ConsentChallenge {
+	t.Helper()
+	if len(f.challenges.challenges) != 1 {
+		t.Fatalf("stored %d consent challenges, want 1", len(f.challenges.challenges))
+	}
+	for _, c := range f.challenges.challenges {
+		return c
+	}
+	return nil
+}
+
+func TestConsent_Flow(t *testing.T) {
+	f := newConsentFixture(t)
+
+	asked := f.authorize(t, oauthPublicClientID)
+	if asked.Code != "" || asked.ConsentToken == "" {
+		t.Fatalf("Authorize() = %+v, want the consent page rather than a code", asked)
+	}
+	if f.consentChallenge(t).TokenHash == asked.ConsentToken {
+		t.Error("only a hash of the consent token should be stored")
+	}
+
+	looked, err := f.lookupConsentUC.Execute(context.Background(), input.LookupConsentInput{ConsentToken: asked.ConsentToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if looked.ClientName != "Mobile" || entity.FormatScope(looked.Scopes) != "profile email" || len(looked.GrantedScopes) != 0 {
+		t.Errorf("Execute() = %+v, want the client and the requested scopes", looked)
+	}
+
+	approved := f.decideConsent(t, asked.ConsentToken, true)
+	if approved.Code == "" || approved.State != "xyz" || approved.RedirectURI != oauthRedirectURI {
+		t.Fatalf("Execute() = %+v, want a code for the original redirect URI and state", approved)
+	}
+	if _, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, approved.Code)); err != nil {
+		t.Fatalf("the code issued after consent should be redeemable: %v", err)
+	}
+
+	consent, _ := f.consentRepo.Find(context.Background(), f.user.ID.String(), oauthPublicClientID)
+	if consent == nil || entity.FormatScope(consent.Scopes) != "profile email" {
+		t.Fatalf("consent = %+v, want the approved scopes recorded", consent)
+	}
+	assertActions(t, f.audit.actions(),
+		entity.AuditActionOAuthConsentGranted, entity.AuditActionOAuthAuthorized, entity.AuditActionOAuthTokenIssued)
+
+	again := f.authorize(t, oauthPublicClientID)
+	if again.Code == "" || again.ConsentToken != "" {
+		t.Errorf("Authorize() = %+v, want a code once the scopes are granted", again)
+	}
+}
+
+func TestConsent_AskedForNewScopes(t *testing.T) {
+	f := newConsentFixture(t)
+	userID := f.user.ID.String()
+	if err := f.consentRepo.Save(context.Background(), entity.NewUserConsent(userID, oauthPublicClientID, []string{"profile"})); err != nil {
+		t.Fatal(err)
+	}
+
+	asked := f.authorize(t, oauthPublicClientID)
+	if asked.ConsentToken == "" {
+		t.Fatalf("Authorize() = %+v, want consent for the email scope", asked)
+	}
+	looked, err := f.lookupConsentUC.Execute(context.Background(), input.LookupConsentInput{ConsentToken: asked.ConsentToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if entity.FormatScope(looked.GrantedScopes) != "profile" {
+		t.Errorf("GrantedScopes = %v, want the scope already granted", looked.GrantedScopes)
+	}
+
+	f.decideConsent(t, asked.ConsentToken, true)
+	consent, _ := f.consentRepo.Find(context.Background(), userID, oauthPublicClientID)
+	if consent == nil || !consent.Covers([]string{"profile", "email"}) {
+		t.Errorf("consent = %+v, want the new scope added", consent)
+	}
+}
+
+func TestConsent_PromptConsent(t *testing.T) {
+	f := newConsentFixture(t)
+	userID := f.user.ID.String()
+	if err := f.consentRepo.Save(context.Background(), entity.NewUserConsent(userID, oauthPublicClientID, oauthScopes)); err != nil {
+		t.Fatal(err)
+	}
+
+	req := authorizationRequest(oauthPublicClientID)
+	req.Prompt = "login consent"
+	out, err := f.authorizeUC.Execute(context.Background(), input.AuthorizeInput{Request: req, UserID: userID})
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	if out.Code != "" || out.ConsentToken == "" {
+		t.Errorf("Authorize() = %+v, want prompt=consent to ask again", out)
+	}
+}
+
+func TestConsent_FirstPartySkipsConsent(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	req := authorizationRequest(oauthPublicClientID)
+	req.Prompt = "consent"
+	out, err := f.authorizeUC.Execute(context.Background(), input.AuthorizeInput{Request: req, UserID: f.user.ID.String()})
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	if out.Code == "" || len(f.challenges.challenges) != 0 {
+		t.Errorf("Authorize() = %+v, want a code for a first-party client", out)
+	}
+}
+
+func TestConsent_Denied(t *testing.T) {
+	f := newConsentFixture(t)
+	asked := f.authorize(t, oauthPublicClientID)
+
+	denied := f.decideConsent(t, asked.ConsentToken, false)
+	if !denied.Denied || denied.Code != "" || denied.State != "xyz" || denied.RedirectURI != oauthRedirectURI {
+		t.Errorf("Execute() = %+v, want a denial for the client's redirect URI", denied)
+	}
+	if len(f.codeRepo.codes) != 0 || len(f.consentRepo.consents) != 0 {
+		t.Error("a denied request should neither issue a code nor record consent")
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthConsentDenied)
+
+	_, err := f.decideConsentUC.Execute(context.Background(), input.DecideConsentInput{ConsentToken: asked.ConsentToken, Approve: true})
+	if !errors.Is(err, exception.ErrInvalidConsentToken) {
+		t.Errorf("Execute() expected error %v after a decision, got %v", exception.ErrInvalidConsentToken, err)
+	}
+}
+
+func TestConsent_InvalidToken(t *testing.T) {
+	f := newConsentFixture(t)
+	asked := f.authorize(t, oauthPublicClientID)
+	f.consentChallenge(t).ExpiresAt = time.Now().Add(-time.Second)
+
+	for _, token := range []string{asked.ConsentToken, "not-a-consent-token", ""} {
+		_, err := f.lookupConsentUC.Execute(context.Background(), input.LookupConsentInput{ConsentToken: token})
+		if !errors.Is(err, exception.ErrInvalidConsentToken) {
+			t.Errorf("lookup of %q: expected error %v, got %v", token, exception.ErrInvalidConsentToken, err)
+		}
+		_, err = f.decideConsentUC.Execute(context.Background(), input.DecideConsentInput{ConsentToken: token, Approve: true})
+		if !errors.Is(err, exception.ErrInvalidConsentToken) {
+			t.Errorf("decision on %q: expected error %v, got %v", token, exception.ErrInvalidConsentToken, err)
+		}
+	}
+}
+
+func TestConsent_ClientChangedWhilePending(t *testing.T) {
+	f := newConsentFixture(t)
+	asked := f.authorize(t, oauthPublicClientID)
+	f.clientRepo.clients[oauthPublicClientID].Scopes = []string{"openid"}
+
+	_, err := f.decideConsentUC.Execute(context.Background(), input.DecideConsentInput{ConsentToken: asked.ConsentToken, Approve: true})
+	assertOAuthError(t, err, exception.OAuthInvalidScope)
+	if len(f.codeRepo.codes) != 0 || len(f.consentRepo.consents) != 0 {
+		t.Error("no code or consent should be issued for scopes the client lost")
+	}
+}
+
+func TestConsent_ListAndRevoke(t *testing.T) {
+	f := newConsentFixture(t)
+	userID := f.user.ID.String()
+
+	approved := f.decideConsent(t, f.authorize(t, oauthPublicClientID).ConsentToken, true)
+	if _, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, approved.Code)); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	_, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantAuthorizationCode,
+		ClientID:     oauthConfidentialClientID,
+		ClientSecret: oauthClientSecret,
+		Code:         f.authorize(t, oauthConfidentialClientID).Code,
+		RedirectURI:  oauthRedirectURI,
+		CodeVerifier: oauthCodeVerifier,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	listed, err := f.listConsentsUC.Execute(context.Background(), input.ListConsentsInput{UserID: userID})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(listed.Consents) != 1 || listed.Consents[0].ClientID != oauthPublicClientID || listed.Consents[0].ClientName != "Mobile" {
+		t.Fatalf("Execute() = %+v, want the consent to the mobile client", listed.Consents)
+	}
+
+	err = f.revokeConsentUC.Execute(context.Background(), input.RevokeConsentInput{UserID: userID, ClientID: oauthPublicClientID})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if f.refreshRepo.activeCount() != 1 {
+		t.Errorf("active refresh tokens = %d, want only the other client's", f.refreshRepo.activeCount())
+	}
+	if !slices.Contains(f.audit.actions(), entity.AuditActionOAuthConsentRevoked) {
+		t.Errorf("audit = %v, want the revocation recorded", f.audit.actions())
+	}
+
+	listed, _ = f.listConsentsUC.Execute(context.Background(), input.ListConsentsInput{UserID: userID})
+	if len(listed.Consents) != 0 {
+		t.Errorf("Execute() = %+v, want no consents after revoking", listed.Consents)
+	}
+	if out := f.authorize(t, oauthPublicClientID); out.ConsentToken == "" {
+		t.Error("the client should need consent again after it is revoked")
+	}
+
+	err = f.revokeConsentUC.Execute(context.Background(), input.RevokeConsentInput{UserID: userID, ClientID: oauthPublicClientID})
+	if !errors.Is(err, exception.ErrConsentNotFound) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrConsentNotFound, err)
+	}
+}
@@ -0,0 +1,228 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+var publicClient = input.ClientCredentials{ClientID: oauthPublicClientID}
+
+// startDevice registers the device grant for the public client and starts
+// a device authorization for scope.
+func (f *oauthFixture) startDevice(t *testing.T, scope string) *output.StartDeviceAuthorizationOutput {
+	t.Helper()
+	client := f.clientRepo.clients[oauthPublicClientID]
+	if !client.AllowsGrantType(entity.OAuthGrantDeviceCode) {
+		client.GrantTypes = append(client.GrantTypes, entity.OAuthGrantDeviceCode)
+	}
+
+	out, err := f.deviceUC.Execute(context.Background(), input.StartDeviceAuthorizationInput{Client: publicClient, Scope: scope})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+func (f *oauthFixture) pollDevice(clientID, deviceCode string) (*output.OAuthTokenOutput, error) {
+	return f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:  entity.OAuthGrantDeviceCode,
+		ClientID:   clientID,
+		DeviceCode: deviceCode,
+	})
+}
+
+func (f *oauthFixture) decideDevice(t *testing.T, userCode string, approve bool) {
+	t.Helper()
+	err := f.decideUC.Execute(context.Background(), input.DecideDeviceAuthorizationInput{
+		UserID:   f.user.ID.String(),
+		UserCode: userCode,
+		Approve:  approve,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+}
+
+// deviceAuthorization returns the stored authorization, so tests can move
+// its clock.
+func (f *oauthFixture) deviceAuthorization(t *testing.T) *entity.DeviceAuthorization {
+	t.Helper()
+	if len(f.deviceRepo.authorizations) != 1 {
+		t.Fatalf("stored %d device authorizations, want 1", len(f.deviceRepo.authorizations))
+	}
+	for _, a := range f.deviceRepo.authorizations {
+		return a
+	}
+	return nil
+}
+
+func TestDeviceAuthorization_Flow(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "openid profile")
+
+	if started.VerificationURI != oauthDeviceVerificationURL || !strings.HasPrefix(started.VerificationURIComplete, oauthDeviceVerificationURL+"?user_code=") {
+		t.Errorf("Execute() = %+v, want the verification URIs", started)
+	}
+	if started.Interval != 5*time.Second || started.DeviceCode == "" || len(started.UserCode) != 9 {
+		t.Errorf("Execute() = %+v, want codes and the polling interval", started)
+	}
+	stored := f.deviceAuthorization(t)
+	if stored.DeviceCodeHash == started.DeviceCode || strings.Contains(stored.UserCodeHash, started.UserCode) {
+		t.Error("only hashes of the codes should be stored")
+	}
+
+	_, err := f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthAuthorizationPending)
+
+	looked, err := f.lookupUC.Execute(context.Background(), input.LookupDeviceAuthorizationInput{
+		UserCode: strings.ToLower(strings.ReplaceAll(started.UserCode, "-", " ")),
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if looked.ClientID != oauthPublicClientID || looked.ClientName != "Mobile" || entity.FormatScope(looked.Scopes) != "openid profile" {
+		t.Errorf("Execute() = %+v, want the client and scopes", looked)
+	}
+
+	f.decideDevice(t, started.UserCode, true)
+	polled := time.Now().Add(-time.Minute)
+	stored.LastPolledAt = &polled
+
+	out, err := f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" || out.RefreshToken == "" || out.IDToken == "" {
+		t.Errorf("Execute() = %+v, want access, refresh and ID tokens", out)
+	}
+	refresh, _ := f.refreshRepo.FindByHash(context.Background(), token.NewOpaqueGenerator().Hash(out.RefreshToken))
+	if refresh == nil || refresh.ClientID != oauthPublicClientID || refresh.UserID != f.user.ID.String() || refresh.FamilyID != stored.FamilyID {
+		t.Errorf("refresh token should be bound to the user, the client and the grant's family, got %+v", refresh)
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthAuthorized, entity.AuditActionOAuthTokenIssued)
+
+	stored.LastPolledAt = &polled
+	_, err = f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+}
+
+func TestDeviceAuthorization_SlowDown(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "profile")
+
+	_, err := f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthAuthorizationPending)
+	_, err = f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthSlowDown)
+
+	stored := f.deviceAuthorization(t)
+	if stored.Interval != 10*time.Second {
+		t.Errorf("Interval = %v, want it raised by %v", stored.Interval, entity.DeviceSlowDownStep)
+	}
+
+	polled := stored.LastPolledAt.Add(-10 * time.Second)
+	stored.LastPolledAt = &polled
+	_, err = f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthAuthorizationPending)
+}
+
+func TestDeviceAuthorization_Denied(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "profile")
+
+	f.decideDevice(t, started.UserCode, false)
+
+	_, err := f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthAccessDenied)
+	if f.refreshRepo.activeCount() != 0 {
+		t.Error("a denied device should not get tokens")
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthDeviceDenied)
+
+	err = f.decideUC.Execute(context.Background(), input.DecideDeviceAuthorizationInput{
+		UserID:   f.user.ID.String(),
+		UserCode: started.UserCode,
+		Approve:  true,
+	})
+	if !errors.Is(err, exception.ErrInvalidUserCode) {
+		t.Errorf("Execute() expected error %v after a decision, got %v", exception.ErrInvalidUserCode, err)
+	}
+}
+
+func TestDeviceAuthorization_Expired(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "profile")
+	f.deviceAuthorization(t).ExpiresAt = time.Now().Add(-time.Second)
+
+	_, err := f.pollDevice(oauthPublicClientID, started.DeviceCode)
+	assertOAuthError(t, err, exception.OAuthExpiredToken)
+
+	_, err = f.lookupUC.Execute(context.Background(), input.LookupDeviceAuthorizationInput{UserCode: started.UserCode})
+	if !errors.Is(err, exception.ErrInvalidUserCode) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidUserCode, err)
+	}
+}
+
+func TestDeviceAuthorization_PollRejections(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "profile")
+	confidential := f.clientRepo.clients[oauthConfidentialClientID]
+	confidential.GrantTypes = append(confidential.GrantTypes, entity.OAuthGrantDeviceCode)
+
+	_, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantDeviceCode,
+		ClientID:     oauthConfidentialClientID,
+		ClientSecret: oauthClientSecret,
+		DeviceCode:   started.DeviceCode,
+	})
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+
+	_, err = f.pollDevice(oauthPublicClientID, "not-a-device-code")
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+
+	_, err = f.pollDevice(oauthPublicClientID, "")
+	assertOAuthError(t, err, exception.OAuthInvalidRequest)
+}
+
+func TestDeviceAuthorization_GrantOff(t *testing.T) {
+	f := newOAuthFixture(t)
+	started := f.startDevice(t, "profile")
+	f.decideDevice(t, started.UserCode, true)
+
+	_, err := f.newTokenUC(false).Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:  entity.OAuthGrantDeviceCode,
+		ClientID:   oauthPublicClientID,
+		DeviceCode: started.DeviceCode,
+	})
+	assertOAuthError(t, err, exception.OAuthUnsupportedGrantType)
+	if len(f.tokens.issued) != 0 || len(f.refreshRepo.tokens) != 0 {
+		t.Error("no tokens should be issued while the device grant is off")
+	}
+}
+
+func TestDeviceAuthorization_StartRejections(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	_, err := f.deviceUC.Execute(context.Background(), input.StartDeviceAuthorizationInput{Client: publicClient, Scope: "profile"})
+	assertOAuthError(t, err, exception.OAuthUnauthorizedClient)
+
+	f.startDevice(t, "profile")
+	for _, scope := range []string{"", "admin"} {
+		_, err := f.deviceUC.Execute(context.Background(), input.StartDeviceAuthorizationInput{Client: publicClient, Scope: scope})
+		assertOAuthError(t, err, exception.OAuthInvalidScope)
+	}
+
+	_, err = f.lookupUC.Execute(context.Background(), input.LookupDeviceAuthorizationInput{UserCode: "BCDF-GHJK"})
+	if !errors.Is(err, exception.ErrInvalidUserCode) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidUserCode, err)
+	}
+}
@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/subscriber"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+type emailVerificationFixture struct {
+	register  port.RegisterUseCase
+	verify    port.VerifyEmailUseCase
+	resend    port.ResendVerificationUseCase
+	userRepo  *fakeUserRepo
+	tokenRepo *fakeEmailVerificationTokenRepo
+	mailer    *fakeMailer
+	outbox    *fakeOutbox
+	mail      *subscriber.VerificationMailSubscriber
+}
+
+func newEmailVerificationFixture(resendInterval time.Duration) *emailVerificationFixture {
+	f := &emailVerificationFixture{
+		userRepo:  newFakeUserRepo(),
+		tokenRepo: newFakeEmailVerificationTokenRepo(),
+		mailer:    &fakeMailer{},
+		outbox:    &fakeOutbox{},
+	}
+
+	opaque := token.NewOpaqueGenerator()
+	uuidGenerator := &sequentialUUIDGenerator{}
+	sender := service.NewEmailVerificationService(
+		f.tokenRepo,
+		opaque,
+		uuidGenerator,
+		f.mailer,
+		24*time.Hour,
+		"http://localhost/verify-email",
+	)
+
+	f.register = usecase.NewRegisterUsecase(f.userRepo, &fakeTxManager{}, f.outbox, noopLogger{}, uuidGenerator, &fakeHasher{}, newPasswordValidator(nil))
+	f.verify = usecase.NewVerifyEmailUsecase(f.userRepo, f.tokenRepo, &fakeTxManager{}, f.outbox, noopLogger{}, opaque)
+	f.mail = subscriber.NewVerificationMailSubscriber(f.userRepo, sender)
+	f.resend = usecase.NewResendVerificationUsecase(f.userRepo, f.tokenRepo, noopLogger{}, sender, resendInterval)
+	return f
+}
+
+func (f *emailVerificationFixture) registerUser(t *testing.T) *entity.User {
+	t.Helper()
+
+	out, err := f.register.Execute(context.Background(), input.RegisterInput{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "secret123",
+	})
+	if err != nil {
+		t.Fatalf("Register Execute() unexpected error: %v", err)
+	}
+	f.outbox.deliver(t, f.mail, subscriber.VerificationMailEvents...)
+
+	user, _ := f.userRepo.FindByID(context.Background(), out.UserID)
+	return user
+}
+
+func TestVerifyEmail_RegistrationSendsLink(t *testing.T) {
+	f := newEmailVerificationFixture(time.Minute)
+	user := f.registerUser(t)
+
+	if len(f.mailer.sent) != 1 || f.mailer.sent[0].To != "test@example.com" {
+		t.Fatalf("expected one verification mail to test@example.com, got %+v", f.mailer.sent)
+	}
+
+	out, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: f.mailer.lastToken()})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if out.UserID != user.ID.String() {
+		t.Errorf("Execute() UserID = %q, want %q", out.UserID, user.ID.String())
+	}
+	if !user.IsEmailVerified {
+		t.Error("Execute() should mark the user's email as verified")
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserRegistered, event.UserEmailVerified)
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		f := newEmailVerificationFixture(time.Minute)
+		f.registerUser(t)
+
+		_, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: "unknown"})
+		if err != exception.ErrInvalidVerificationToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidVerificationToken, err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newEmailVerificationFixture(time.Minute)
+		f.registerUser(t)
+		for _, stored := range f.tokenRepo.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		_, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: f.mailer.lastToken()})
+		if err != exception.ErrInvalidVerificationToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidVerificationToken, err)
+		}
+	})
+
+	t.Run("token reused", func(t *testing.T) {
+		f := newEmailVerificationFixture(time.Minute)
+		f.registerUser(t)
+		rawToken := f.mailer.lastToken()
+
+		if _, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: rawToken}); err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+
+		_, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: rawToken})
+		if err != exception.ErrInvalidVerificationToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidVerificationToken, err)
+		}
+	})
+}
+
+func TestResendVerification_Throttled(t *testing.T) {
+	f := newEmailVerificationFixture(time.Hour)
+	f.registerUser(t)
+
+	out, err := f.resend.Execute(context.Background(), input.ResendVerificationInput{Email: "test@example.com"})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if len(f.mailer.sent) != 1 {
+		t.Errorf("expected resend to be throttled, got %d mails", len(f.mailer.sent))
+	}
+
+	unknown, err := f.resend.Execute(context.Background(), input.ResendVerificationInput{Email: "nobody@example.com"})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if *out != *unknown {
+		t.Errorf("Execute() responses differ: %+v vs %+v", out, unknown)
+	}
+}
+
+func TestResendVerification_InvalidatesPreviousLink(t *testing.T) {
+	f := newEmailVerificationFixture(0)
+	f.registerUser(t)
+	first := f.mailer.lastToken()
+
+	if _, err := f.resend.Execute(context.Background(), input.ResendVerificationInput{Email: "test@example.com"}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(f.mailer.sent) != 2 {
+		t.Fatalf("expected a second mail, got %d", len(f.mailer.sent))
+	}
+
+	if _, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: first}); err != exception.ErrInvalidVerificationToken {
+		t.Errorf("Execute() with old token expected error %v, got %v", exception.ErrInvalidVerificationToken, err)
+	}
+	if _, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: f.mailer.lastToken()}); err != nil {
+		t.Errorf("Execute() with new token unexpected error: %v", err)
+	}
+}
+
+func TestVerificationMail_SkipsVerifiedUser(t *testing.T) {
+	f := newEmailVerificationFixture(time.Minute)
+	f.registerUser(t)
+
+	if _, err := f.verify.Execute(context.Background(), input.VerifyEmailInput{Token: f.mailer.lastToken()}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	// A late redelivery of the registration event must not mail a user who
+	// has already verified.
+	f.outbox.deliver(t, f.mail, subscriber.VerificationMailEvents...)
+
+	if len(f.mailer.sent) != 1 {
+		t.Errorf("expected no mail on redelivery, got %d mails", len(f.mailer.sent))
+	}
+}
+
+func TestRegister_FailsWhenEventCannotBeRecorded(t *testing.T) {
+	f := newEmailVerificationFixture(time.Minute)
+	f.outbox.err = errors.New("outbox unavailable")
+
+	_, err := f.register.Execute(context.Background(), input.RegisterInput{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "secret123",
+	})
+	if err == nil {
+		t.Error("Execute() expected error when the outbox write fails")
+	}
+}
@@ -0,0 +1,199 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type fakeIdentityRepo struct {
+	mu         sync.Mutex
+	identities map[string]*entity.Identity
+}
+
+func newFakeIdentityRepo() *fakeIdentityRepo {
+	return &fakeIdentityRepo{identities: make(map[string]*entity.Identity)}
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *entity.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return errors.New("duplicate identity")
+		}
+	}
+	copied := *identity
+	r.identities[identity.ID] = &copied
+	return nil
+}
+
+func (r *fakeIdentityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeIdentityRepo) ExistsForUser(ctx context.Context, userID, provider string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeIdentityRepo) RecordLogin(ctx context.Context, id, email string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.identities[id].Email = email
+	r.identities[id].LastLoginAt = &at
+	return nil
+}
+
+type fakeFederatedLoginRepo struct {
+	mu     sync.Mutex
+	logins map[string]*entity.FederatedLogin
+}
+
+func newFakeFederatedLoginRepo() *fakeFederatedLoginRepo {
+	return &fakeFederatedLoginRepo{logins: make(map[string]*entity.FederatedLogin)}
+}
+
+func (r *fakeFederatedLoginRepo) Create(ctx context.Context, login *entity.FederatedLogin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logins[login.ID] = login
+	return nil
+}
+
+func (r *fakeFederatedLoginRepo) FindByStateHash(ctx context.Context, stateHash string) (*entity.FederatedLogin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, login := range r.logins {
+		if login.StateHash == stateHash {
+			copied := *login
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFederatedLoginRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	login, ok := r.logins[id]
+	if !ok || login.UsedAt != nil {
+		return false, nil
+	}
+	login.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeSAMLRequestRepo struct {
+	mu       sync.Mutex
+	requests map[string]*entity.SAMLRequest
+}
+
+func newFakeSAMLRequestRepo() *fakeSAMLRequestRepo {
+	return &fakeSAMLRequestRepo{requests: make(map[string]*entity.SAMLRequest)}
+}
+
+func (r *fakeSAMLRequestRepo) Create(ctx context.Context, request *entity.SAMLRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[request.ID] = request
+	return nil
+}
+
+func (r *fakeSAMLRequestRepo) FindByID(ctx context.Context, id string) (*entity.SAMLRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *request
+	return &copied, nil
+}
+
+func (r *fakeSAMLRequestRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request, ok := r.requests[id]
+	if !ok || request.UsedAt != nil {
+		return false, nil
+	}
+	request.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeSAMLLoginRepo struct {
+	mu     sync.Mutex
+	logins map[string]*entity.SAMLLogin
+}
+
+func newFakeSAMLLoginRepo() *fakeSAMLLoginRepo {
+	return &fakeSAMLLoginRepo{logins: make(map[string]*entity.SAMLLogin)}
+}
+
+func (r *fakeSAMLLoginRepo) Create(ctx context.Context, login *entity.SAMLLogin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logins[login.ID] = login
+	return nil
+}
+
+func (r *fakeSAMLLoginRepo) FindByCodeHash(ctx context.Context, codeHash string) (*entity.SAMLLogin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, login := range r.logins {
+		if login.CodeHash == codeHash {
+			copied := *login
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSAMLLoginRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	login, ok := r.logins[id]
+	if !ok || login.UsedAt != nil {
+		return false, nil
+	}
+	login.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeSAMLAssertionRepo struct {
+	mu         sync.Mutex
+	assertions map[string]time.Time
+}
+
+func newFakeSAMLAssertionRepo() *fakeSAMLAssertionRepo {
+	return &fakeSAMLAssertionRepo{assertions: make(map[string]time.Time)}
+}
+
+func (r *fakeSAMLAssertionRepo) Remember(ctx context.Context, idp, assertionID string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := idp + " " + assertionID
+	if _, ok := r.assertions[key]; ok {
+		return false, nil
+	}
+	r.assertions[key] = expiresAt
+	return true, nil
+}
@@ -0,0 +1,355 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type fakeTOTPCredentialRepo struct {
+	mu          sync.Mutex
+	credentials map[string]*entity.TOTPCredential
+}
+
+func newFakeTOTPCredentialRepo() *fakeTOTPCredentialRepo {
+	return &fakeTOTPCredentialRepo{credentials: make(map[string]*entity.TOTPCredential)}
+}
+
+func (r *fakeTOTPCredentialRepo) Save(ctx context.Context, credential *entity.TOTPCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.credentials[credential.UserID]; ok && existing.IsConfirmed() {
+		return nil
+	}
+	c := *credential
+	r.credentials[credential.UserID] = &c
+	return nil
+}
+
+func (r *fakeTOTPCredentialRepo) FindByUserID(ctx context.Context, userID string) (*entity.TOTPCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (r *fakeTOTPCredentialRepo) Confirm(ctx context.Context, userID string, step int64, confirmedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.credentials[userID]
+	if !ok || c.IsConfirmed() {
+		return false, nil
+	}
+	c.ConfirmedAt = &confirmedAt
+	c.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeTOTPCredentialRepo) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.credentials[userID]
+	if !ok || !c.IsConfirmed() || c.LastUsedStep >= step {
+		return false, nil
+	}
+	c.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeTOTPCredentialRepo) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.credentials, userID)
+	return nil
+}
+
+type fakeMFARecoveryCodeRepo struct {
+	mu    sync.Mutex
+	codes []*entity.MFARecoveryCode
+}
+
+func (r *fakeMFARecoveryCodeRepo) ReplaceForUser(ctx context.Context, userID string, codes []*entity.MFARecoveryCode) error {
+	_ = r.DeleteByUserID(ctx, userID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = append(r.codes, codes...)
+	return nil
+}
+
+func (r *fakeMFARecoveryCodeRepo) MarkUsed(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.codes {
+		if c.UserID == userID && c.CodeHash == codeHash && !c.IsUsed() {
+			c.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeMFARecoveryCodeRepo) CountUnused(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.codes {
+		if c.UserID == userID && !c.IsUsed() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeMFARecoveryCodeRepo) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = slices.DeleteFunc(r.codes, func(c *entity.MFARecoveryCode) bool {
+		return c.UserID == userID
+	})
+	return nil
+}
+
+type fakeMFAChallengeRepo struct {
+	mu         sync.Mutex
+	challenges map[string]*entity.MFAChallenge
+}
+
+func newFakeMFAChallengeRepo() *fakeMFAChallengeRepo {
+	return &fakeMFAChallengeRepo{challenges: make(map[string]*entity.MFAChallenge)}
+}
+
+func (r *fakeMFAChallengeRepo) Create(ctx context.Context, challenge *entity.MFAChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (r *fakeMFAChallengeRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.challenges {
+		if c.TokenHash == tokenHash {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeMFAChallengeRepo) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[id].Attempts++
+	return r.challenges[id].Attempts, nil
+}
+
+func (r *fakeMFAChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.challenges[id]
+	if c.IsUsed() {
+		return false, nil
+	}
+	c.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeQRCodeEncoder struct{}
+
+func (fakeQRCodeEncoder) EncodePNG(content string) ([]byte, error) {
+	return []byte("png:" + content), nil
+}
+
+// noMFA is an MFAChallenger for users without a second factor.
+type noMFA struct{}
+
+func (noMFA) BeginChallenge(ctx context.Context, user *entity.User) (*port.MFAChallengeTicket, error) {
+	return nil, nil
+}
+
+type fakeWebAuthnCredentialRepo struct {
+	mu          sync.Mutex
+	credentials map[string]*entity.WebAuthnCredential
+}
+
+func newFakeWebAuthnCredentialRepo() *fakeWebAuthnCredentialRepo {
+	return &fakeWebAuthnCredentialRepo{credentials: make(map[string]*entity.WebAuthnCredential)}
+}
+
+func (r *fakeWebAuthnCredentialRepo) Create(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *credential
+	r.credentials[credential.ID] = &copied
+	return nil
+}
+
+func (r *fakeWebAuthnCredentialRepo) FindByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.credentials {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWebAuthnCredentialRepo) ListByUserID(ctx context.Context, userID string) ([]*entity.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var credentials []*entity.WebAuthnCredential
+	for _, c := range r.credentials {
+		if c.UserID == userID {
+			copied := *c
+			credentials = append(credentials, &copied)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *fakeWebAuthnCredentialRepo) UpdateUsage(ctx context.Context, id string, expectedSignCount, signCount uint32, backupState bool, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.credentials[id]
+	if !ok || c.SignCount != expectedSignCount {
+		return false, nil
+	}
+	c.SignCount = signCount
+	c.BackupState = backupState
+	c.LastUsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakeWebAuthnCredentialRepo) Delete(ctx context.Context, userID, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.credentials[id]
+	if !ok || c.UserID != userID {
+		return false, nil
+	}
+	delete(r.credentials, id)
+	return true, nil
+}
+
+type fakeWebAuthnSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.WebAuthnSession
+}
+
+func newFakeWebAuthnSessionRepo() *fakeWebAuthnSessionRepo {
+	return &fakeWebAuthnSessionRepo{sessions: make(map[string]*entity.WebAuthnSession)}
+}
+
+func (r *fakeWebAuthnSessionRepo) Create(ctx context.Context, session *entity.WebAuthnSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *fakeWebAuthnSessionRepo) FindByID(ctx context.Context, id string) (*entity.WebAuthnSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *fakeWebAuthnSessionRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.sessions[id]
+	if s.IsUsed() {
+		return false, nil
+	}
+	s.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakePasswordlessChallengeRepo struct {
+	mu         sync.Mutex
+	challenges map[string]*entity.PasswordlessChallenge
+}
+
+func newFakePasswordlessChallengeRepo() *fakePasswordlessChallengeRepo {
+	return &fakePasswordlessChallengeRepo{challenges: make(map[string]*entity.PasswordlessChallenge)}
+}
+
+func (r *fakePasswordlessChallengeRepo) Create(ctx context.Context, challenge *entity.PasswordlessChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (r *fakePasswordlessChallengeRepo) FindByDeviceHash(ctx context.Context, deviceHash string) (*entity.PasswordlessChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.challenges {
+		if c.DeviceHash == deviceHash {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePasswordlessChallengeRepo) FindLatestByUserID(ctx context.Context, userID string) (*entity.PasswordlessChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *entity.PasswordlessChallenge
+	for _, c := range r.challenges {
+		if c.UserID == userID && (latest == nil || c.CreatedAt.After(latest.CreatedAt)) {
+			latest = c
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (r *fakePasswordlessChallengeRepo) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[id].Attempts++
+	return r.challenges[id].Attempts, nil
+}
+
+func (r *fakePasswordlessChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.challenges[id]
+	if !ok || c.UsedAt != nil {
+		return false, nil
+	}
+	c.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakePasswordlessChallengeRepo) InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.challenges {
+		if c.UserID == userID && c.UsedAt == nil {
+			c.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
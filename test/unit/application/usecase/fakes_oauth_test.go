@@ -0,0 +1,452 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+type fakeOAuthClientRepo struct {
+	mu      sync.Mutex
+	clients map[string]*entity.OAuthClient
+}
+
+func newFakeOAuthClientRepo(clients ...*entity.OAuthClient) *fakeOAuthClientRepo {
+	r := &fakeOAuthClientRepo{clients: make(map[string]*entity.OAuthClient)}
+	for _, c := range clients {
+		r.clients[c.ID] = c
+	}
+	return r
+}
+
+func (r *fakeOAuthClientRepo) Create(ctx context.Context, client *entity.OAuthClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *fakeOAuthClientRepo) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.clients[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (r *fakeOAuthClientRepo) List(ctx context.Context) ([]*entity.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clients := make([]*entity.OAuthClient, 0, len(r.clients))
+	for _, c := range r.clients {
+		copied := *c
+		clients = append(clients, &copied)
+	}
+	slices.SortFunc(clients, func(a, b *entity.OAuthClient) int { return strings.Compare(a.ID, b.ID) })
+	return clients, nil
+}
+
+func (r *fakeOAuthClientRepo) Update(ctx context.Context, client *entity.OAuthClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *fakeOAuthClientRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, id)
+	return nil
+}
+
+type fakeAuthorizationCodeRepo struct {
+	mu    sync.Mutex
+	codes map[string]*entity.AuthorizationCode
+}
+
+func newFakeAuthorizationCodeRepo() *fakeAuthorizationCodeRepo {
+	return &fakeAuthorizationCodeRepo{codes: make(map[string]*entity.AuthorizationCode)}
+}
+
+func (r *fakeAuthorizationCodeRepo) Create(ctx context.Context, code *entity.AuthorizationCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes[code.ID] = code
+	return nil
+}
+
+func (r *fakeAuthorizationCodeRepo) FindByHash(ctx context.Context, codeHash string) (*entity.AuthorizationCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.codes {
+		if c.CodeHash == codeHash {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAuthorizationCodeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.codes[id]
+	if !ok || c.UsedAt != nil {
+		return false, nil
+	}
+	c.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeDeviceAuthorizationRepo struct {
+	mu             sync.Mutex
+	authorizations map[string]*entity.DeviceAuthorization
+}
+
+func newFakeDeviceAuthorizationRepo() *fakeDeviceAuthorizationRepo {
+	return &fakeDeviceAuthorizationRepo{authorizations: make(map[string]*entity.DeviceAuthorization)}
+}
+
+func (r *fakeDeviceAuthorizationRepo) Create(ctx context.Context, authorization *entity.DeviceAuthorization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.authorizations[authorization.ID] = authorization
+	return nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) find(match func(*entity.DeviceAuthorization) bool) *entity.DeviceAuthorization {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.authorizations {
+		if match(a) {
+			copied := *a
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*entity.DeviceAuthorization, error) {
+	return r.find(func(a *entity.DeviceAuthorization) bool { return a.DeviceCodeHash == deviceCodeHash }), nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) FindByUserCodeHash(ctx context.Context, userCodeHash string) (*entity.DeviceAuthorization, error) {
+	return r.find(func(a *entity.DeviceAuthorization) bool { return a.UserCodeHash == userCodeHash }), nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) RecordPoll(ctx context.Context, id string, polledAt time.Time, interval time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a, ok := r.authorizations[id]; ok {
+		a.LastPolledAt = &polledAt
+		a.Interval = interval
+	}
+	return nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) Decide(ctx context.Context, id, userID string, status entity.DeviceAuthorizationStatus, decidedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.authorizations[id]
+	if !ok || !a.IsPending() || a.IsExpired(decidedAt) {
+		return false, nil
+	}
+	a.Status = status
+	a.UserID = userID
+	a.DecidedAt = &decidedAt
+	return true, nil
+}
+
+func (r *fakeDeviceAuthorizationRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.authorizations[id]
+	if !ok || a.Status != entity.DeviceAuthorizationApproved || a.UsedAt != nil {
+		return false, nil
+	}
+	a.UsedAt = &usedAt
+	return true, nil
+}
+
+type fakeUserConsentRepo struct {
+	mu       sync.Mutex
+	consents map[string]*entity.UserConsent
+}
+
+func newFakeUserConsentRepo() *fakeUserConsentRepo {
+	return &fakeUserConsentRepo{consents: make(map[string]*entity.UserConsent)}
+}
+
+func (r *fakeUserConsentRepo) Find(ctx context.Context, userID, clientID string) (*entity.UserConsent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.consents[userID+"/"+clientID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	copied.Scopes = slices.Clone(c.Scopes)
+	return &copied, nil
+}
+
+func (r *fakeUserConsentRepo) ListByUserID(ctx context.Context, userID string) ([]*entity.UserConsent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var consents []*entity.UserConsent
+	for _, c := range r.consents {
+		if c.UserID == userID {
+			consents = append(consents, c)
+		}
+	}
+	slices.SortFunc(consents, func(a, b *entity.UserConsent) int { return strings.Compare(a.ClientID, b.ClientID) })
+	return consents, nil
+}
+
+func (r *fakeUserConsentRepo) Save(ctx context.Context, consent *entity.UserConsent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consents[consent.UserID+"/"+consent.ClientID] = consent
+	return nil
+}
+
+func (r *fakeUserConsentRepo) Delete(ctx context.Context, userID, clientID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.consents[userID+"/"+clientID]; !ok {
+		return false, nil
+	}
+	delete(r.consents, userID+"/"+clientID)
+	return true, nil
+}
+
+type fakeConsentChallengeRepo struct {
+	mu         sync.Mutex
+	challenges map[string]*entity.ConsentChallenge
+}
+
+func newFakeConsentChallengeRepo() *fakeConsentChallengeRepo {
+	return &fakeConsentChallengeRepo{challenges: make(map[string]*entity.ConsentChallenge)}
+}
+
+func (r *fakeConsentChallengeRepo) Create(ctx context.Context, challenge *entity.ConsentChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (r *fakeConsentChallengeRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ConsentChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.challenges {
+		if c.TokenHash == tokenHash {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeConsentChallengeRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.challenges[id]
+	if !ok || c.UsedAt != nil {
+		return false, nil
+	}
+	c.UsedAt = &usedAt
+	return true, nil
+}
+
+// fakeIDTokenSigner records the claims of the ID tokens it is asked for.
+type fakeIDTokenSigner struct {
+	mu     sync.Mutex
+	signed []port.IDTokenClaims
+}
+
+func (s *fakeIDTokenSigner) SignIDToken(claims port.IDTokenClaims) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signed = append(s.signed, claims)
+	return fmt.Sprintf("id-token-%d", len(s.signed)), nil
+}
+
+// VerifyIDTokenHint accepts the tokens SignIDToken issued, telling what
+// they were signed for.
+func (s *fakeIDTokenSigner) VerifyIDTokenHint(idToken string) (*port.IDTokenHint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int
+	if _, err := fmt.Sscanf(idToken, "id-token-%d", &n); err != nil || n < 1 || n > len(s.signed) {
+		return nil, errors.New("unknown ID token")
+	}
+	claims := s.signed[n-1]
+	return &port.IDTokenHint{Subject: claims.Subject, Audience: claims.Audience, SessionID: claims.SessionID}, nil
+}
+
+type fakeOAuthSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.OAuthSession
+}
+
+func newFakeOAuthSessionRepo() *fakeOAuthSessionRepo {
+	return &fakeOAuthSessionRepo{sessions: make(map[string]*entity.OAuthSession)}
+}
+
+func (r *fakeOAuthSessionRepo) Create(ctx context.Context, session *entity.OAuthSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sessions[session.ID]; !ok {
+		r.sessions[session.ID] = session
+	}
+	return nil
+}
+
+func (r *fakeOAuthSessionRepo) ListActiveByUserID(ctx context.Context, userID string) ([]*entity.OAuthSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*entity.OAuthSession
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.EndedAt == nil {
+			copied := *s
+			result = append(result, &copied)
+		}
+	}
+	slices.SortFunc(result, func(a, b *entity.OAuthSession) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return result, nil
+}
+
+func (r *fakeOAuthSessionRepo) EndByUserID(ctx context.Context, userID string, endedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.EndedAt == nil {
+			s.EndedAt = &endedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeOAuthSessionRepo) activeCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, s := range r.sessions {
+		if s.EndedAt == nil {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeClientAssertionRepo struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func newFakeClientAssertionRepo() *fakeClientAssertionRepo {
+	return &fakeClientAssertionRepo{used: make(map[string]bool)}
+}
+
+func (r *fakeClientAssertionRepo) Use(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := clientID + "/" + jti
+	if r.used[key] {
+		return false, nil
+	}
+	r.used[key] = true
+	return true, nil
+}
+
+// recordingTokenService issues opaque stand-ins for access tokens, keeps
+// the claims it was asked to sign and parses the tokens it issued. Like
+// the real service, it only parses tokens for another audience when
+// inspecting them.
+type recordingTokenService struct {
+	mu     sync.Mutex
+	issued []port.AccessTokenClaims
+}
+
+func (s *recordingTokenService) GenerateAccessToken(claims port.AccessTokenClaims) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ttl := 15 * time.Minute
+	if claims.TTL > 0 {
+		ttl = claims.TTL
+	}
+	claims.ID = fmt.Sprintf("jti-%d", len(s.issued)+1)
+	claims.IssuedAt = time.Now().Truncate(time.Second)
+	if expiresAt := claims.IssuedAt.Add(ttl); claims.ExpiresAt.IsZero() || expiresAt.Before(claims.ExpiresAt) {
+		claims.ExpiresAt = expiresAt
+	}
+	s.issued = append(s.issued, claims)
+	return "access-token-" + claims.ID, claims.ExpiresAt, nil
+}
+
+func (s *recordingTokenService) ParseAccessToken(token string) (*port.AccessTokenClaims, error) {
+	claims, err := s.InspectAccessToken(token)
+	if err != nil || len(claims.Audience) > 0 {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (s *recordingTokenService) InspectAccessToken(token string) (*port.AccessTokenClaims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, claims := range s.issued {
+		if token == "access-token-"+claims.ID && time.Now().Before(claims.ExpiresAt) {
+			parsed := claims
+			return &parsed, nil
+		}
+	}
+	return nil, errors.New("invalid token")
+}
+
+type fakeRevocationList struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+	cutoffs map[string]time.Time
+}
+
+func newFakeRevocationList() *fakeRevocationList {
+	return &fakeRevocationList{revoked: make(map[string]time.Time), cutoffs: make(map[string]time.Time)}
+}
+
+func (l *fakeRevocationList) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revoked[jti] = expiresAt
+	return nil
+}
+
+func (l *fakeRevocationList) RevokeUser(ctx context.Context, userID string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cutoffs[userID] = now
+	return nil
+}
+
+func (l *fakeRevocationList) IsRevoked(claims *port.AccessTokenClaims) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.revoked[claims.ID]; ok {
+		return true
+	}
+	cutoff, ok := l.cutoffs[claims.UserID]
+	return ok && !claims.IssuedAt.After(cutoff)
+}
@@ -0,0 +1,557 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+)
+
+type fakeUserRepo struct {
+	mu           sync.Mutex
+	users        map[string]*entity.User
+	lastFailedAt map[string]time.Time
+}
+
+func newFakeUserRepo(users ...*entity.User) *fakeUserRepo {
+	r := &fakeUserRepo{
+		users:        make(map[string]*entity.User),
+		lastFailedAt: make(map[string]time.Time),
+	}
+	for _, u := range users {
+		r.users[u.ID.String()] = u
+	}
+	return r
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID.String()] = user
+	return nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[id], nil
+}
+
+func (r *fakeUserRepo) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username.String() == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email.String() == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	u, _ := r.FindByUsername(ctx, username)
+	return u != nil, nil
+}
+
+func (r *fakeUserRepo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	u, _ := r.FindByEmail(ctx, email)
+	return u != nil, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID.String()] = user
+	return nil
+}
+
+func (r *fakeUserRepo) RecordFailedLogin(ctx context.Context, user *entity.User, now, windowStart time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.users[user.ID.String()]
+	if last, ok := r.lastFailedAt[user.ID.String()]; !ok || last.Before(windowStart) {
+		stored.FailedLoginAttempts = 1
+	} else {
+		stored.FailedLoginAttempts++
+	}
+	r.lastFailedAt[user.ID.String()] = now
+	user.FailedLoginAttempts = stored.FailedLoginAttempts
+	user.LockoutCount = stored.LockoutCount
+	user.LockedUntil = stored.LockedUntil
+	return nil
+}
+
+func (r *fakeUserRepo) Lock(ctx context.Context, user *entity.User, until time.Time, threshold int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.users[user.ID.String()]
+	if stored.FailedLoginAttempts < threshold {
+		return false, nil
+	}
+	stored.LockedUntil = &until
+	stored.LockoutCount++
+	stored.FailedLoginAttempts = 0
+	user.LockedUntil = stored.LockedUntil
+	user.LockoutCount = stored.LockoutCount
+	user.FailedLoginAttempts = 0
+	return true, nil
+}
+
+func (r *fakeUserRepo) ResetFailedLogins(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range []*entity.User{user, r.users[user.ID.String()]} {
+		u.FailedLoginAttempts = 0
+		u.LockoutCount = 0
+		u.LockedUntil = nil
+	}
+	delete(r.lastFailedAt, user.ID.String())
+	return nil
+}
+
+type fakeAuditLogger struct {
+	mu   sync.Mutex
+	logs []*entity.AuditLog
+}
+
+func (a *fakeAuditLogger) Log(ctx context.Context, log *entity.AuditLog) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.logs = append(a.logs, log)
+}
+
+func (a *fakeAuditLogger) Start() {}
+func (a *fakeAuditLogger) Stop()  {}
+
+func (a *fakeAuditLogger) actions() []entity.AuditAction {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]entity.AuditAction, 0, len(a.logs))
+	for _, l := range a.logs {
+		out = append(out, l.Action)
+	}
+	return out
+}
+
+// details decodes the details of the i-th audit entry.
+func (a *fakeAuditLogger) details(t *testing.T, i int) map[string]interface{} {
+	t.Helper()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var details map[string]interface{}
+	if err := json.Unmarshal(a.logs[i].Details, &details); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+	}
+	return details
+}
+
+type noopLogger struct{}
+
+func (noopLogger) InfoCtx(ctx context.Context, msg string, fields ...any)  {}
+func (noopLogger) ErrorCtx(ctx context.Context, msg string, fields ...any) {}
+func (noopLogger) WarnCtx(ctx context.Context, msg string, fields ...any)  {}
+func (noopLogger) DebugCtx(ctx context.Context, msg string, fields ...any) {}
+
+// fakeHasher stores passwords as "hashed:<password>" and counts Verify calls.
+type fakeHasher struct {
+	mu          sync.Mutex
+	verifyCalls int
+}
+
+func (h *fakeHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (h *fakeHasher) Verify(password, encodedHash string) (bool, error) {
+	h.mu.Lock()
+	h.verifyCalls++
+	h.mu.Unlock()
+	return strings.TrimPrefix(encodedHash, "hashed:") == password, nil
+}
+
+type fakeTokenService struct{}
+
+func (fakeTokenService) GenerateAccessToken(claims port.AccessTokenClaims) (string, time.Time, error) {
+	return "token-for-" + claims.UserID, time.Now().Add(15 * time.Minute), nil
+}
+
+func (fakeTokenService) ParseAccessToken(token string) (*port.AccessTokenClaims, error) {
+	return &port.AccessTokenClaims{UserID: strings.TrimPrefix(token, "token-for-")}, nil
+}
+
+func (s fakeTokenService) InspectAccessToken(token string) (*port.AccessTokenClaims, error) {
+	return s.ParseAccessToken(token)
+}
+
+type fakeMetrics struct {
+	mu              sync.Mutex
+	loginAttempts   map[string]int
+	passwordResets  int
+	accountLockouts int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{loginAttempts: make(map[string]int)}
+}
+
+func (m *fakeMetrics) RecordLoginAttempt(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loginAttempts[status]++
+}
+
+func (m *fakeMetrics) RecordPasswordReset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.passwordResets++
+}
+
+func (m *fakeMetrics) RecordAccountLockout() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accountLockouts++
+}
+
+type fakeRefreshTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.RefreshToken
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{tokens: make(map[string]*entity.RefreshToken)}
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRefreshTokenRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeByUserAndClient(ctx context.Context, userID, clientID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.ClientID == clientID && t.RevokedAt == nil {
+			t.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) activeCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, t := range r.tokens {
+		if t.RevokedAt == nil && t.UsedAt == nil {
+			n++
+		}
+	}
+	return n
+}
+
+type sequentialUUIDGenerator struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (g *sequentialUUIDGenerator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("0190a5b0-7e1c-7b3d-8f4e-%012d", g.n)
+}
+
+type fakePasswordResetTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.PasswordResetToken
+}
+
+func newFakePasswordResetTokenRepo() *fakePasswordResetTokenRepo {
+	return &fakePasswordResetTokenRepo{tokens: make(map[string]*entity.PasswordResetToken)}
+}
+
+func (r *fakePasswordResetTokenRepo) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakePasswordResetTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePasswordResetTokenRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakePasswordResetTokenRepo) InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []port.MailMessage
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg port.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// lastToken extracts the token query parameter from the most recent mail.
+func (m *fakeMailer) lastToken() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return ""
+	}
+	body := m.sent[len(m.sent)-1].TextBody
+	idx := strings.Index(body, "token=")
+	if idx < 0 {
+		return ""
+	}
+	return strings.Fields(body[idx+len("token="):])[0]
+}
+
+type fakeEmailVerificationTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.EmailVerificationToken
+}
+
+func newFakeEmailVerificationTokenRepo() *fakeEmailVerificationTokenRepo {
+	return &fakeEmailVerificationTokenRepo{tokens: make(map[string]*entity.EmailVerificationToken)}
+}
+
+func (r *fakeEmailVerificationTokenRepo) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeEmailVerificationTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeEmailVerificationTokenRepo) FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *entity.EmailVerificationToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && (latest == nil || t.CreatedAt.After(latest.CreatedAt)) {
+			latest = t
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakeEmailVerificationTokenRepo) MarkUsed(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakeEmailVerificationTokenRepo) InvalidateByUserID(ctx context.Context, userID string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+// fakeTxManager runs fn directly; it only records how many transactions
+// were opened.
+type fakeTxManager struct {
+	calls int
+}
+
+func (m *fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+type fakeOutbox struct {
+	mu       sync.Mutex
+	messages []port.OutboxMessage
+	err      error
+}
+
+func (o *fakeOutbox) Publish(ctx context.Context, msg port.OutboxMessage) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+func (o *fakeOutbox) eventTypes() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make([]string, 0, len(o.messages))
+	for _, msg := range o.messages {
+		out = append(out, msg.EventType)
+	}
+	return out
+}
+
+// deliver hands every recorded message of a subscribed event type to sub,
+// the way the outbox dispatcher would.
+func (o *fakeOutbox) deliver(t *testing.T, sub port.OutboxSubscriber, eventTypes ...string) {
+	t.Helper()
+	o.mu.Lock()
+	messages := append([]port.OutboxMessage(nil), o.messages...)
+	o.mu.Unlock()
+
+	for i, msg := range messages {
+		if !slices.Contains(eventTypes, msg.EventType) {
+			continue
+		}
+		payload, err := json.Marshal(msg.Payload)
+		if err != nil {
+			t.Fatalf("failed to marshal outbox payload: %v", err)
+		}
+		err = sub.Handle(context.Background(), port.OutboxDelivery{
+			ID:        fmt.Sprintf("outbox-%d", i),
+			EventType: msg.EventType,
+			DedupKey:  msg.DedupKey,
+			Payload:   payload,
+			Attempt:   1,
+		})
+		if err != nil {
+			t.Fatalf("%s.Handle() unexpected error: %v", sub.Name(), err)
+		}
+	}
+}
+
+func assertEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Errorf("published events = %v, want %v", got, want)
+	}
+}
+
+// newPasswordValidator skips the strength score so fixtures can use short,
+// readable passwords.
+func newPasswordValidator(breaches port.BreachedPasswordChecker) port.PasswordValidator {
+	policy := vo.DefaultPasswordPolicy()
+	policy.MinStrengthScore = 0
+	return service.NewPasswordPolicyService(policy, breaches, noopLogger{})
+}
+
+type fakeBreachChecker struct {
+	breached map[string]bool
+}
+
+func (c *fakeBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	return c.breached[password], nil
+}
@@ -0,0 +1,425 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/federation"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+	"github.com/thanhnamdk2710/auth-service/test/support/mockoidc"
+)
+
+const federatedProviderID = "acme"
+
+type federationFixture struct {
+	idp          *mockoidc.Provider
+	list         port.ListIdentityProvidersUseCase
+	start        port.StartFederatedLoginUseCase
+	complete     port.CompleteFederatedLoginUseCase
+	userRepo     *fakeUserRepo
+	identityRepo *fakeIdentityRepo
+	loginRepo    *fakeFederatedLoginRepo
+	refreshRepo  *fakeRefreshTokenRepo
+	outbox       *fakeOutbox
+	audit        *fakeAuditLogger
+	metrics      *fakeMetrics
+}
+
+// newFederationFixture signs users in through an in-process provider
+// with the real OpenID Connect relying party.
+func newFederationFixture(t *testing.T, linkByEmail bool, mfa port.MFAChallenger, users ...*entity.User) *federationFixture {
+	t.Helper()
+
+	f := &federationFixture{
+		idp:          mockoidc.New(t, "auth-service", "client-secret"),
+		userRepo:     newFakeUserRepo(users...),
+		identityRepo: newFakeIdentityRepo(),
+		loginRepo:    newFakeFederatedLoginRepo(),
+		refreshRepo:  newFakeRefreshTokenRepo(),
+		outbox:       &fakeOutbox{},
+		audit:        &fakeAuditLogger{},
+		metrics:      newFakeMetrics(),
+	}
+
+	providers := federation.NewRegistry(federation.NewOIDCProvider(federation.ProviderConfig{
+		ID:           federatedProviderID,
+		Name:         "Acme",
+		Issuer:       f.idp.Issuer(),
+		ClientID:     f.idp.ClientID,
+		ClientSecret: f.idp.ClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+		LinkByEmail:  linkByEmail,
+		RedirectURL:  "http://localhost/login/callback",
+		CacheTTL:     time.Hour,
+	}, f.idp.Client()))
+	opaque := token.NewOpaqueGenerator()
+	uuidGenerator := &sequentialUUIDGenerator{}
+
+	f.list = usecase.NewListIdentityProvidersUsecase(providers)
+	f.start = usecase.NewStartFederatedLoginUsecase(providers, f.loginRepo, noopLogger{}, opaque, uuidGenerator, 10*time.Minute)
+	f.complete = usecase.NewCompleteFederatedLoginUsecase(
+		providers,
+		f.loginRepo,
+		service.NewExternalAccountService(f.identityRepo, f.userRepo, &fakeTxManager{}, f.outbox, f.audit, noopLogger{}, uuidGenerator),
+		newLoginService(newSessionService(f.refreshRepo), mfa, f.audit, f.metrics),
+		f.metrics,
+		noopLogger{},
+		opaque,
+	)
+	return f
+}
+
+// callback starts a sign-in and returns the callback the provider's
+// redirect page would post once the user has signed in there.
+func (f *federationFixture) callback(t *testing.T) input.CompleteFederatedLoginInput {
+	t.Helper()
+	started, err := f.start.Execute(context.Background(), input.StartFederatedLoginInput{Provider: federatedProviderID})
+	if err != nil {
+		t.Fatalf("StartFederatedLogin.Execute() unexpected error: %v", err)
+	}
+	code, state, err := f.idp.Authorize(started.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	return input.CompleteFederatedLoginInput{DeviceToken: started.DeviceToken, State: state, Code: code}
+}
+
+func (f *federationFixture) signIn(t *testing.T) (*output.LoginOutput, error) {
+	t.Helper()
+	return f.complete.Execute(context.Background(), f.callback(t))
+}
+
+func (f *federationFixture) userByEmail(t *testing.T, email string) *entity.User {
+	t.Helper()
+	user, _ := f.userRepo.FindByEmail(context.Background(), email)
+	if user == nil {
+		t.Fatalf("no user with email %s", email)
+	}
+	return user
+}
+
+func TestFederatedLogin_ListsProviders(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+
+	providers := f.list.Execute(context.Background())
+	if len(providers) != 1 || providers[0] != (output.IdentityProviderOutput{ID: federatedProviderID, Name: "Acme"}) {
+		t.Errorf("Execute() = %+v, want the configured provider", providers)
+	}
+}
+
+func TestFederatedLogin_ProvisionsUser(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+
+	out, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" || out.RefreshToken == "" || out.MFARequired {
+		t.Fatalf("Execute() = %+v, want a session", out)
+	}
+
+	user := f.userByEmail(t, "jane.doe@example.com")
+	if user.ID.String() != out.UserID || user.Username.String() != "jane.doe" || !user.IsEmailVerified || user.HasPassword() {
+		t.Errorf("provisioned %+v, want jane.doe with a verified email and no password", user)
+	}
+	identity, _ := f.identityRepo.FindByProviderSubject(context.Background(), federatedProviderID, f.idp.User.Subject)
+	if identity == nil || identity.UserID != user.ID.String() || identity.LastLoginAt == nil {
+		t.Errorf("identity = %+v, want the provider's subject linked to the user", identity)
+	}
+
+	assertEvents(t, f.outbox.eventTypes(), event.UserRegistered)
+	payload := f.outbox.messages[0].Payload.(event.UserEvent)
+	if payload.Details["has_password"] != false || payload.Details["provider"] != federatedProviderID {
+		t.Errorf("UserRegistered details = %v, want a passwordless user from the provider", payload.Details)
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLogin)
+	if f.metrics.loginAttempts[port.LoginStatusSuccess] != 1 {
+		t.Errorf("login attempts = %v, want one success", f.metrics.loginAttempts)
+	}
+}
+
+func TestFederatedLogin_ReturningUser(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+	first, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	f.idp.User.Email = "jane@new.example.com"
+	second, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if second.UserID != first.UserID || len(f.userRepo.users) != 1 {
+		t.Errorf("signed in as %s, want the account provisioned the first time", second.UserID)
+	}
+	identity, _ := f.identityRepo.FindByProviderSubject(context.Background(), federatedProviderID, f.idp.User.Subject)
+	if identity.Email != "jane@new.example.com" {
+		t.Errorf("identity email = %q, want the one last reported", identity.Email)
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserRegistered)
+}
+
+func TestFederatedLogin_UsernameTaken(t *testing.T) {
+	taken := createUser(t, "secret123")
+	username, _ := vo.NewUsername("jane.doe")
+	taken.Username = *username
+	f := newFederationFixture(t, false, noMFA{}, taken)
+
+	out, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	user := f.userByEmail(t, "jane.doe@example.com")
+	id := out.UserID
+	if want := "jane.doe-" + id[len(id)-8:]; user.Username.String() != want {
+		t.Errorf("username = %q, want %q", user.Username.String(), want)
+	}
+}
+
+func TestFederatedUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity mockoidc.User
+		want     string
+	}{
+		{name: "email local part", identity: mockoidc.User{Email: "j.smith+work@example.com"}, want: "j.smith+work"},
+		{name: "unsupported characters", identity: mockoidc.User{PreferredUsername: "Jürgen Müller"}, want: "JrgenMller"},
+		{name: "too short", identity: mockoidc.User{PreferredUsername: "李"}, want: "user"},
+		{name: "too long", identity: mockoidc.User{PreferredUsername: "abcdefghijklmnopqrstuvwxyz0123456789"}, want: "abcdefghijklmnopqrstuvwxyz0123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFederationFixture(t, false, noMFA{})
+			f.idp.User = tt.identity
+			f.idp.User.Subject = "subject"
+			f.idp.User.EmailVerified = true
+			if f.idp.User.Email == "" {
+				f.idp.User.Email = "someone@example.com"
+			}
+
+			if _, err := f.signIn(t); err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if got := f.userByEmail(t, f.idp.User.Email).Username.String(); got != tt.want {
+				t.Errorf("username = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// janeDoe is a local account holding the email the test identity
+// providers report.
+func janeDoe(t *testing.T, verified bool) *entity.User {
+	t.Helper()
+	user := createUser(t, "secret123")
+	email, _ := vo.NewEmail("jane.doe@example.com")
+	user.Email = email
+	user.IsEmailVerified = verified
+	return user
+}
+
+func TestFederatedLogin_ExistingEmail(t *testing.T) {
+	refused := []struct {
+		name        string
+		linkByEmail bool
+		verified    bool
+	}{
+		{name: "not linked", linkByEmail: false, verified: true},
+		{name: "unverified account", linkByEmail: true, verified: false},
+	}
+	for _, tt := range refused {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFederationFixture(t, tt.linkByEmail, noMFA{}, janeDoe(t, tt.verified))
+
+			_, err := f.signIn(t)
+			if !errors.Is(err, exception.ErrEmailAlreadyExists) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrEmailAlreadyExists, err)
+			}
+			if len(f.identityRepo.identities) != 0 || len(f.userRepo.users) != 1 {
+				t.Error("the existing account should be left alone")
+			}
+		})
+	}
+
+	t.Run("linked by email", func(t *testing.T) {
+		existing := janeDoe(t, true)
+		f := newFederationFixture(t, true, noMFA{}, existing)
+
+		out, err := f.signIn(t)
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.UserID != existing.ID.String() || len(f.outbox.messages) != 0 {
+			t.Errorf("signed in as %s, want the existing account", out.UserID)
+		}
+		assertActions(t, f.audit.actions(), entity.AuditActionIdentityLinked, entity.AuditActionUserLogin)
+	})
+}
+
+func TestFederatedLogin_RequiresVerifiedEmail(t *testing.T) {
+	for _, user := range []mockoidc.User{
+		{Subject: "1", Email: "jane.doe@example.com"},
+		{Subject: "2", EmailVerified: true},
+	} {
+		f := newFederationFixture(t, true, noMFA{})
+		f.idp.User = user
+
+		_, err := f.signIn(t)
+		if !errors.Is(err, exception.ErrFederatedEmailRequired) {
+			t.Errorf("Execute() for %+v expected error %v, got %v", user, exception.ErrFederatedEmailRequired, err)
+		}
+		if len(f.userRepo.users) != 0 {
+			t.Error("no account should be provisioned without a verified email")
+		}
+	}
+}
+
+func TestFederatedLogin_RequiresSecondFactor(t *testing.T) {
+	f := newFederationFixture(t, false, stubMFA{})
+
+	out, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.MFARequired || out.AccessToken != "" || f.refreshRepo.activeCount() != 0 {
+		t.Errorf("Execute() = %+v, want an MFA challenge instead of a session", out)
+	}
+}
+
+func TestFederatedLogin_InactiveUser(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+	first, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if err := f.userRepo.users[first.UserID].Deactivate(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.signIn(t)
+	if !errors.Is(err, exception.ErrUserInactive) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUserInactive, err)
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLogin, entity.AuditActionUserLoginFailed)
+}
+
+func TestFederatedLogin_LockedUser(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+	first, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	until := time.Now().Add(time.Hour)
+	f.userRepo.users[first.UserID].LockedUntil = &until
+
+	_, err = f.signIn(t)
+	if !errors.Is(err, exception.ErrInvalidCredentials) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLogin, entity.AuditActionUserLoginFailed)
+	if reason := f.audit.details(t, 1)["reason"]; reason != "account_locked" {
+		t.Errorf("audit reason = %v, want %q", reason, "account_locked")
+	}
+}
+
+func TestFederatedLogin_InvalidCallback(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+
+	replayed := f.callback(t)
+	if _, err := f.complete.Execute(context.Background(), replayed); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	otherDevice := f.callback(t)
+	otherDevice.DeviceToken = "someone-elses-device"
+
+	expired := f.callback(t)
+	for _, login := range f.loginRepo.logins {
+		if login.UsedAt == nil {
+			login.ExpiresAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		callback input.CompleteFederatedLoginInput
+	}{
+		{name: "replayed", callback: replayed},
+		{name: "other device", callback: otherDevice},
+		{name: "expired", callback: expired},
+		{name: "unknown state", callback: input.CompleteFederatedLoginInput{DeviceToken: replayed.DeviceToken, State: "unknown", Code: replayed.Code}},
+		{name: "no device token", callback: input.CompleteFederatedLoginInput{State: replayed.State, Code: replayed.Code}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.complete.Execute(context.Background(), tt.callback)
+			if !errors.Is(err, exception.ErrInvalidFederatedLogin) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidFederatedLogin, err)
+			}
+		})
+	}
+	if len(f.userRepo.users) != 1 {
+		t.Errorf("users = %d, want only the one from the valid callback", len(f.userRepo.users))
+	}
+}
+
+func TestFederatedLogin_ProviderFailures(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+
+	denied := f.callback(t)
+	denied.Code, denied.Error = "", "access_denied"
+	if _, err := f.complete.Execute(context.Background(), denied); !errors.Is(err, exception.ErrFederatedLoginFailed) {
+		t.Errorf("Execute() for a denied sign-in expected error %v, got %v", exception.ErrFederatedLoginFailed, err)
+	}
+
+	badCode := f.callback(t)
+	badCode.Code = "not-the-code"
+	if _, err := f.complete.Execute(context.Background(), badCode); !errors.Is(err, exception.ErrFederatedLoginFailed) {
+		t.Errorf("Execute() for a bad code expected error %v, got %v", exception.ErrFederatedLoginFailed, err)
+	}
+
+	badCode.Code = ""
+	if _, err := f.complete.Execute(context.Background(), badCode); !errors.Is(err, exception.ErrInvalidFederatedLogin) {
+		t.Errorf("a failed sign-in should be spent, got %v", err)
+	}
+	if len(f.userRepo.users) != 0 || f.metrics.loginAttempts[port.LoginStatusInvalidCredentials] != 2 {
+		t.Errorf("login attempts = %v, want two failures and no users", f.metrics.loginAttempts)
+	}
+}
+
+func TestFederatedLogin_StartErrors(t *testing.T) {
+	f := newFederationFixture(t, false, noMFA{})
+
+	_, err := f.start.Execute(context.Background(), input.StartFederatedLoginInput{Provider: "unknown"})
+	if !errors.Is(err, exception.ErrUnknownIdentityProvider) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUnknownIdentityProvider, err)
+	}
+
+	unreachable := federation.NewRegistry(federation.NewOIDCProvider(federation.ProviderConfig{
+		ID:       federatedProviderID,
+		Issuer:   "http://127.0.0.1:1",
+		CacheTTL: time.Hour,
+	}, f.idp.Client()))
+	start := usecase.NewStartFederatedLoginUsecase(unreachable, f.loginRepo, noopLogger{}, token.NewOpaqueGenerator(), &sequentialUUIDGenerator{}, time.Minute)
+	_, err = start.Execute(context.Background(), input.StartFederatedLoginInput{Provider: federatedProviderID})
+	if !errors.Is(err, exception.ErrIdentityProviderUnavailable) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrIdentityProviderUnavailable, err)
+	}
+	if len(f.loginRepo.logins) != 0 {
+		t.Error("no sign-in should be recorded when the provider cannot be reached")
+	}
+}
@@ -0,0 +1,317 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/ldap"
+	"github.com/thanhnamdk2710/auth-service/test/support/ldapserver"
+)
+
+const (
+	ldapServiceDN = "cn=auth-service,ou=services,dc=example,dc=com"
+	ldapJaneDN    = "uid=jane.doe,ou=people,dc=example,dc=com"
+	ldapJanePass  = "directory-password"
+	ldapJaneUUID  = "5b0b6a0c-9d3e-4d8e-a7a6-1f0c2e7e4b11"
+	ldapStaffDN   = "cn=staff,ou=groups,dc=example,dc=com"
+	ldapAdminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+type ldapLoginFixture struct {
+	uc           port.LoginUseCase
+	server       *ldapserver.Server
+	userRepo     *fakeUserRepo
+	identityRepo *fakeIdentityRepo
+	refreshRepo  *fakeRefreshTokenRepo
+	outbox       *fakeOutbox
+	audit        *fakeAuditLogger
+	metrics      *fakeMetrics
+	linkByEmail  bool
+}
+
+// newLDAPLoginFixture checks passwords against an in-process directory
+// with the real LDAP client, alongside the given local users.
+func newLDAPLoginFixture(t *testing.T, users ...*entity.User) *ldapLoginFixture {
+	t.Helper()
+
+	f := &ldapLoginFixture{
+		server:       ldapserver.New(t),
+		userRepo:     newFakeUserRepo(users...),
+		identityRepo: newFakeIdentityRepo(),
+		refreshRepo:  newFakeRefreshTokenRepo(),
+		outbox:       &fakeOutbox{},
+		audit:        &fakeAuditLogger{},
+		metrics:      newFakeMetrics(),
+	}
+	f.server.Add(ldapserver.Entry{DN: ldapServiceDN, Password: "service-secret"})
+	f.addJane("jane.doe@example.com", ldapStaffDN)
+
+	f.uc = f.newUsecase(f.server.URL())
+	return f
+}
+
+func (f *ldapLoginFixture) newUsecase(url string) port.LoginUseCase {
+	directory := ldap.NewDirectory(ldap.Config{
+		URL:               url,
+		BindDN:            ldapServiceDN,
+		BindPassword:      "service-secret",
+		BaseDN:            "ou=people,dc=example,dc=com",
+		UserFilter:        "(uid={username})",
+		IDAttribute:       "entryUUID",
+		UsernameAttribute: "uid",
+		EmailAttribute:    "mail",
+		GroupAttribute:    "memberOf",
+		GroupRoles: map[string][]string{
+			ldapStaffDN:  {"staff"},
+			ldapAdminsDN: {"admin"},
+		},
+		Timeout:     5 * time.Second,
+		LinkByEmail: f.linkByEmail,
+	})
+	accounts := service.NewExternalAccountService(f.identityRepo, f.userRepo, &fakeTxManager{}, f.outbox, f.audit, noopLogger{}, &sequentialUUIDGenerator{})
+
+	return usecase.NewLoginUsecase(
+		f.userRepo,
+		f.audit,
+		noopLogger{},
+		&fakeHasher{},
+		directory,
+		accounts,
+		newLoginService(newSessionService(f.refreshRepo), noMFA{}, f.audit, f.metrics),
+		f.metrics,
+		cache.NewLoginFailures(time.Minute),
+		false,
+		entity.LockoutPolicy{},
+	)
+}
+
+func (f *ldapLoginFixture) addJane(email string, groups ...string) {
+	f.server.Add(ldapserver.Entry{
+		DN:       ldapJaneDN,
+		Password: ldapJanePass,
+		Attributes: map[string][]string{
+			"entryUUID": {ldapJaneUUID},
+			"uid":       {"jane.doe"},
+			"mail":      {email},
+			"memberOf":  groups,
+		},
+	})
+}
+
+func (f *ldapLoginFixture) login(identifier, password string) error {
+	_, err := f.uc.Execute(context.Background(), input.LoginInput{Identifier: identifier, Password: password})
+	return err
+}
+
+// shadow returns the local user linked to jane.doe's directory account.
+func (f *ldapLoginFixture) shadow(t *testing.T) *entity.User {
+	t.Helper()
+	identity, _ := f.identityRepo.FindByProviderSubject(context.Background(), ldap.ProviderID, ldapJaneUUID)
+	if identity == nil {
+		t.Fatal("no identity links a user to the directory account")
+	}
+	user, _ := f.userRepo.FindByID(context.Background(), identity.UserID)
+	if user == nil {
+		t.Fatal("the identity's user does not exist")
+	}
+	return user
+}
+
+func TestLDAPLogin_ProvisionsShadowUser(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+
+	out, err := f.uc.Execute(context.Background(), input.LoginInput{Identifier: "jane.doe", Password: ldapJanePass})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	user := f.shadow(t)
+	if out.UserID != user.ID.String() || out.AccessToken == "" {
+		t.Errorf("Execute() = %+v, want a session for the shadow user", out)
+	}
+	if user.Username.String() != "jane.doe" || user.Email.String() != "jane.doe@example.com" || !user.IsEmailVerified {
+		t.Errorf("shadow user = %s <%s> verified %v, want the directory's username and verified email", user.Username, user.Email, user.IsEmailVerified)
+	}
+	if user.HasPassword() {
+		t.Error("shadow user should not have a local password")
+	}
+	if !slices.Equal(user.Roles, []string{"staff"}) {
+		t.Errorf("Roles = %v, want [staff]", user.Roles)
+	}
+	if f.metrics.loginAttempts[port.LoginStatusSuccess] != 1 {
+		t.Errorf("expected one successful login attempt metric, got %v", f.metrics.loginAttempts)
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserRegistered)
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLogin)
+}
+
+func TestLDAPLogin_SynchronizesShadowUser(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	f.addJane("jane@corp.example.com", ldapStaffDN, ldapAdminsDN)
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Fatalf("Execute() after the directory changed unexpected error: %v", err)
+	}
+
+	user := f.shadow(t)
+	if user.Email.String() != "jane@corp.example.com" {
+		t.Errorf("Email = %s, want the directory's new address", user.Email)
+	}
+	if !slices.Equal(user.Roles, []string{"admin", "staff"}) {
+		t.Errorf("Roles = %v, want [admin staff]", user.Roles)
+	}
+	if len(f.userRepo.users) != 1 {
+		t.Errorf("expected one user, got %d", len(f.userRepo.users))
+	}
+
+	f.addJane("jane@corp.example.com")
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Fatalf("Execute() after leaving every group unexpected error: %v", err)
+	}
+	if roles := f.shadow(t).Roles; len(roles) != 0 {
+		t.Errorf("Roles = %v, want none once the groups are gone", roles)
+	}
+}
+
+func TestLDAPLogin_ExistingEmail(t *testing.T) {
+	refused := []struct {
+		name        string
+		linkByEmail bool
+		verified    bool
+	}{
+		{name: "not linked", linkByEmail: false, verified: true},
+		{name: "unverified account", linkByEmail: true, verified: false},
+	}
+	for _, tt := range refused {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLDAPLoginFixture(t, janeDoe(t, tt.verified))
+			f.linkByEmail = tt.linkByEmail
+			f.uc = f.newUsecase(f.server.URL())
+
+			// Refused like a wrong password, so the answer does not
+			// reveal the existing account.
+			if err := f.login("jane.doe", ldapJanePass); !errors.Is(err, exception.ErrInvalidCredentials) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+			}
+			if len(f.identityRepo.identities) != 0 || len(f.userRepo.users) != 1 {
+				t.Error("the existing account should be left alone")
+			}
+			if reason := f.audit.details(t, len(f.audit.logs)-1)["reason"]; reason != "account_conflict" {
+				t.Errorf("audit reason = %v, want %q", reason, "account_conflict")
+			}
+		})
+	}
+
+	t.Run("linked by email", func(t *testing.T) {
+		existing := janeDoe(t, true)
+		f := newLDAPLoginFixture(t, existing)
+		f.linkByEmail = true
+		f.uc = f.newUsecase(f.server.URL())
+
+		if err := f.login("jane.doe", ldapJanePass); err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if f.shadow(t).ID != existing.ID || len(f.userRepo.users) != 1 {
+			t.Error("the directory account should sign in to the existing account")
+		}
+	})
+}
+
+func TestLDAPLogin_WrongPassword(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+
+	if err := f.login("jane.doe", "wrong"); !errors.Is(err, exception.ErrInvalidCredentials) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if len(f.userRepo.users) != 0 {
+		t.Errorf("expected no user to be provisioned, got %d", len(f.userRepo.users))
+	}
+	if f.metrics.loginAttempts[port.LoginStatusInvalidCredentials] != 1 {
+		t.Errorf("expected one invalid credentials metric, got %v", f.metrics.loginAttempts)
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLoginFailed)
+}
+
+// TestLDAPLogin_LocalUsersStayLocal checks that the password of an account
+// with its own is never sent to the directory.
+func TestLDAPLogin_LocalUsersStayLocal(t *testing.T) {
+	f := newLDAPLoginFixture(t, createUser(t, "secret123"))
+
+	if err := f.login("testuser", "secret123"); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if err := f.login("testuser", "wrong"); !errors.Is(err, exception.ErrInvalidCredentials) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if binds := f.server.Binds(); len(binds) != 0 {
+		t.Errorf("binds = %v, want the directory left alone", binds)
+	}
+}
+
+// TestLDAPLogin_IgnoresShadowUserPasswords checks that a shadow user who
+// has set a local password, such as through a password reset, still signs
+// in with their directory password only.
+func TestLDAPLogin_IgnoresShadowUserPasswords(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if err := f.shadow(t).SetPasswordHash("hashed:local-password"); err != nil {
+		t.Fatalf("failed to set password hash: %v", err)
+	}
+
+	if err := f.login("jane.doe", "local-password"); !errors.Is(err, exception.ErrInvalidCredentials) {
+		t.Errorf("Execute() with the local password expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Errorf("Execute() with the directory password unexpected error: %v", err)
+	}
+}
+
+func TestLDAPLogin_DirectoryUnavailable(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	listener.Close()
+	f.uc = f.newUsecase("ldap://" + listener.Addr().String())
+
+	if err := f.login("jane.doe", ldapJanePass); !errors.Is(err, exception.ErrIdentityProviderUnavailable) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrIdentityProviderUnavailable, err)
+	}
+	if f.metrics.loginAttempts[port.LoginStatusError] != 1 {
+		t.Errorf("expected one error metric, got %v", f.metrics.loginAttempts)
+	}
+}
+
+func TestLDAPLogin_LockedShadowUser(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	if err := f.login("jane.doe", ldapJanePass); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	until := time.Now().Add(time.Hour)
+	f.shadow(t).LockedUntil = &until
+
+	if err := f.login("jane.doe", ldapJanePass); !errors.Is(err, exception.ErrInvalidCredentials) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if f.refreshRepo.activeCount() != 1 {
+		t.Errorf("expected only the first login's session, got %d", f.refreshRepo.activeCount())
+	}
+}
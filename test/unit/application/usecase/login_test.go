@@ -0,0 +1,438 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+func createUser(t *testing.T, password string) *entity.User {
+	t.Helper()
+
+	userID, err := vo.NewUserID("0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f")
+	if err != nil {
+		t.Fatalf("failed to create UserID: %v", err)
+	}
+
+	username, err := vo.NewUsername("testuser")
+	if err != nil {
+		t.Fatalf("failed to create Username: %v", err)
+	}
+
+	email, err := vo.NewEmail("test@example.com")
+	if err != nil {
+		t.Fatalf("failed to create Email: %v", err)
+	}
+
+	user := entity.NewUser(userID, *username, email)
+	if err := user.SetPasswordHash("hashed:" + password); err != nil {
+		t.Fatalf("failed to set password hash: %v", err)
+	}
+
+	return user
+}
+
+func newSessionService(refreshRepo *fakeRefreshTokenRepo) *service.SessionService {
+	return service.NewSessionService(
+		fakeTokenService{},
+		refreshRepo,
+		token.NewOpaqueGenerator(),
+		&sequentialUUIDGenerator{},
+		time.Hour,
+	)
+}
+
+func newLoginService(sessions port.SessionIssuer, mfa port.MFAChallenger, audit *fakeAuditLogger, metrics *fakeMetrics) *service.LoginService {
+	return service.NewLoginService(sessions, mfa, audit, metrics, noopLogger{})
+}
+
+type loginFixture struct {
+	uc          port.LoginUseCase
+	userRepo    *fakeUserRepo
+	audit       *fakeAuditLogger
+	hasher      *fakeHasher
+	metrics     *fakeMetrics
+	refreshRepo *fakeRefreshTokenRepo
+}
+
+// loginOptions configures newLoginFixture. The zero value signs in local
+// accounts with no second factor, lockout or email verification.
+type loginOptions struct {
+	requireVerifiedEmail bool
+	lockout              entity.LockoutPolicy
+}
+
+func newLoginFixture(opts loginOptions, users ...*entity.User) *loginFixture {
+	f := &loginFixture{
+		userRepo:    newFakeUserRepo(users...),
+		audit:       &fakeAuditLogger{},
+		hasher:      &fakeHasher{},
+		metrics:     newFakeMetrics(),
+		refreshRepo: newFakeRefreshTokenRepo(),
+	}
+	f.uc = usecase.NewLoginUsecase(
+		f.userRepo,
+		f.audit,
+		noopLogger{},
+		f.hasher,
+		nil,
+		nil,
+		newLoginService(newSessionService(f.refreshRepo), noMFA{}, f.audit, f.metrics),
+		f.metrics,
+		cache.NewLoginFailures(15*time.Minute),
+		opts.requireVerifiedEmail,
+		opts.lockout,
+	)
+	return f
+}
+
+func TestLogin_Success(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+	}{
+		{name: "by username", identifier: "testuser"},
+		{name: "by email", identifier: "test@example.com"},
+		{name: "by email with different case", identifier: "Test@Example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := createUser(t, "secret123")
+			f := newLoginFixture(loginOptions{}, user)
+
+			out, err := f.uc.Execute(context.Background(), input.LoginInput{
+				Identifier: tt.identifier,
+				Password:   "secret123",
+			})
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+
+			if out.AccessToken != "token-for-"+user.ID.String() {
+				t.Errorf("Execute() AccessToken = %q, want token for user", out.AccessToken)
+			}
+			if out.RefreshToken == "" {
+				t.Error("Execute() should return a refresh token")
+			}
+			if f.refreshRepo.activeCount() != 1 {
+				t.Errorf("expected one stored refresh token, got %d", f.refreshRepo.activeCount())
+			}
+			if out.TokenType != "Bearer" {
+				t.Errorf("Execute() TokenType = %q, want %q", out.TokenType, "Bearer")
+			}
+			if f.metrics.loginAttempts[port.LoginStatusSuccess] != 1 {
+				t.Errorf("expected one successful login attempt metric, got %v", f.metrics.loginAttempts)
+			}
+			assertActions(t, f.audit.actions(), entity.AuditActionUserLogin)
+		})
+	}
+}
+
+func TestLogin_Rejected(t *testing.T) {
+	inactive := func(user *entity.User) { _ = user.Deactivate() }
+	locked := func(user *entity.User) {
+		until := time.Now().Add(time.Hour)
+		user.LockedUntil = &until
+	}
+
+	tests := []struct {
+		name       string
+		opts       loginOptions
+		setup      func(*entity.User)
+		identifier string
+		password   string
+		wantErr    error
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "wrong password",
+			identifier: "testuser",
+			password:   "wrong",
+			wantErr:    exception.ErrInvalidCredentials,
+			wantStatus: port.LoginStatusInvalidCredentials,
+			wantReason: "invalid_password",
+		},
+		{
+			name:       "unknown user",
+			identifier: "nobody",
+			password:   "secret123",
+			wantErr:    exception.ErrInvalidCredentials,
+			wantStatus: port.LoginStatusInvalidCredentials,
+			wantReason: "user_not_found",
+		},
+		{
+			name:       "inactive user",
+			setup:      inactive,
+			identifier: "testuser",
+			password:   "secret123",
+			wantErr:    exception.ErrUserInactive,
+			wantStatus: port.LoginStatusInactive,
+			wantReason: "user_inactive",
+		},
+		{
+			name:       "inactive user with wrong password",
+			setup:      inactive,
+			identifier: "testuser",
+			password:   "wrong",
+			wantErr:    exception.ErrInvalidCredentials,
+			wantStatus: port.LoginStatusInvalidCredentials,
+			wantReason: "invalid_password",
+		},
+		{
+			name:       "locked user with correct password",
+			setup:      locked,
+			identifier: "testuser",
+			password:   "secret123",
+			wantErr:    exception.ErrInvalidCredentials,
+			wantStatus: port.LoginStatusLocked,
+			wantReason: "account_locked",
+		},
+		{
+			name:       "unverified email",
+			opts:       loginOptions{requireVerifiedEmail: true},
+			identifier: "testuser",
+			password:   "secret123",
+			wantErr:    exception.ErrEmailNotVerified,
+			wantStatus: port.LoginStatusEmailUnverified,
+			wantReason: "email_unverified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := createUser(t, "secret123")
+			if tt.setup != nil {
+				tt.setup(user)
+			}
+			f := newLoginFixture(tt.opts, user)
+
+			_, err := f.uc.Execute(context.Background(), input.LoginInput{Identifier: tt.identifier, Password: tt.password})
+			if err != tt.wantErr {
+				t.Fatalf("Execute() expected error %v, got %v", tt.wantErr, err)
+			}
+
+			// Every answer takes one password check, so timing does not
+			// tell the cases apart.
+			if f.hasher.verifyCalls != 1 {
+				t.Errorf("hasher.Verify ran %d times, want once", f.hasher.verifyCalls)
+			}
+			if f.metrics.loginAttempts[tt.wantStatus] != 1 {
+				t.Errorf("expected one %s metric, got %v", tt.wantStatus, f.metrics.loginAttempts)
+			}
+			if f.refreshRepo.activeCount() != 0 {
+				t.Error("no session should be issued")
+			}
+			assertActions(t, f.audit.actions(), entity.AuditActionUserLoginFailed)
+			if reason := f.audit.details(t, 0)["reason"]; reason != tt.wantReason {
+				t.Errorf("audit reason = %v, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+
+	t.Run("verified email accepted", func(t *testing.T) {
+		user := createUser(t, "secret123")
+		_ = user.VerifyEmail()
+		f := newLoginFixture(loginOptions{requireVerifiedEmail: true}, user)
+
+		if _, err := f.uc.Execute(context.Background(), input.LoginInput{Identifier: "testuser", Password: "secret123"}); err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+	})
+}
+
+func assertActions(t *testing.T, got []entity.AuditAction, want ...entity.AuditAction) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("audit actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("audit actions = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func testLockoutPolicy() entity.LockoutPolicy {
+	return entity.LockoutPolicy{
+		MaxAttempts:   3,
+		AttemptWindow: 15 * time.Minute,
+		BaseDuration:  5 * time.Minute,
+		MaxDuration:   time.Hour,
+	}
+}
+
+func TestLogin_LocksAccountAfterMaxAttempts(t *testing.T) {
+	user := createUser(t, "secret123")
+	f := newLoginFixture(loginOptions{lockout: testLockoutPolicy()}, user)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "wrong"})
+		if err != exception.ErrInvalidCredentials {
+			t.Fatalf("attempt %d: Execute() expected error %v, got %v", i+1, exception.ErrInvalidCredentials, err)
+		}
+	}
+
+	// The lock is not announced, or the answer would show that the
+	// account exists.
+	before := time.Now()
+	_, err := f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "wrong"})
+	if err != exception.ErrInvalidCredentials {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if user.LockedUntil == nil || user.LockedUntil.Before(before.Add(5*time.Minute)) || user.LockedUntil.After(time.Now().Add(5*time.Minute)) {
+		t.Errorf("locked until %v, want about 5 minutes from now", user.LockedUntil)
+	}
+
+	if f.metrics.accountLockouts != 1 {
+		t.Errorf("expected one account lockout metric, got %d", f.metrics.accountLockouts)
+	}
+	assertActions(t, f.audit.actions(),
+		entity.AuditActionUserLoginFailed,
+		entity.AuditActionUserLoginFailed,
+		entity.AuditActionUserLoginFailed,
+		entity.AuditActionAccountLocked,
+	)
+
+	// The correct password does not help while the lock lasts.
+	_, err = f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "secret123"})
+	if err != exception.ErrInvalidCredentials {
+		t.Fatalf("Execute() while locked expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+	if f.metrics.loginAttempts[port.LoginStatusLocked] != 1 {
+		t.Errorf("expected one locked login metric, got %v", f.metrics.loginAttempts)
+	}
+	if f.refreshRepo.activeCount() != 0 {
+		t.Error("no session should be issued for a locked account")
+	}
+}
+
+func TestLogin_LockDurationGrowsWithRepeatedLockouts(t *testing.T) {
+	user := createUser(t, "secret123")
+	expired := time.Now().Add(-time.Minute)
+	user.LockedUntil = &expired
+	user.LockoutCount = 2
+	f := newLoginFixture(loginOptions{lockout: testLockoutPolicy()}, user)
+
+	for i := 0; i < 3; i++ {
+		_, _ = f.uc.Execute(context.Background(), input.LoginInput{Identifier: "testuser", Password: "wrong"})
+	}
+
+	if !user.IsLocked(time.Now()) {
+		t.Fatal("account should be locked")
+	}
+	if remaining := time.Until(*user.LockedUntil); remaining < 19*time.Minute || remaining > 20*time.Minute {
+		t.Errorf("third lockout lasts %v, want 20 minutes", remaining)
+	}
+	if user.LockoutCount != 3 {
+		t.Errorf("LockoutCount = %d, want 3", user.LockoutCount)
+	}
+}
+
+func TestLogin_SuccessResetsFailedAttempts(t *testing.T) {
+	user := createUser(t, "secret123")
+	f := newLoginFixture(loginOptions{lockout: testLockoutPolicy()}, user)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "wrong"})
+	}
+	if user.FailedLoginAttempts != 2 {
+		t.Fatalf("FailedLoginAttempts = %d, want 2", user.FailedLoginAttempts)
+	}
+
+	if _, err := f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "secret123"}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if user.FailedLoginAttempts != 0 {
+		t.Errorf("FailedLoginAttempts = %d after success, want 0", user.FailedLoginAttempts)
+	}
+
+	// Two more failures are not enough to lock once the count was reset.
+	for i := 0; i < 2; i++ {
+		_, err := f.uc.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "wrong"})
+		if err != exception.ErrInvalidCredentials {
+			t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+		}
+	}
+}
+
+// TestLogin_ProgressiveDelay checks that failures are slowed down per
+// identifier, so an identifier without an account waits just as long.
+func TestLogin_ProgressiveDelay(t *testing.T) {
+	for _, identifier := range []string{"testuser", "nobody"} {
+		t.Run(identifier, func(t *testing.T) {
+			policy := testLockoutPolicy()
+			policy.MaxAttempts = 0
+			policy.DelayAfter = 2
+			policy.DelayBase = time.Hour
+			policy.DelayMax = time.Hour
+			f := newLoginFixture(loginOptions{lockout: policy}, createUser(t, "secret123"))
+
+			start := time.Now()
+			_, err := f.uc.Execute(context.Background(), input.LoginInput{Identifier: identifier, Password: "wrong"})
+			if err != exception.ErrInvalidCredentials {
+				t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("first failure took %v, want no delay", elapsed)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			start = time.Now()
+			_, err = f.uc.Execute(ctx, input.LoginInput{Identifier: identifier, Password: "wrong"})
+			if err != exception.ErrInvalidCredentials {
+				t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+			}
+			if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+				t.Errorf("Execute() took %v, want it to wait until the context is done", elapsed)
+			}
+		})
+	}
+}
+
+func TestUnlockAccount(t *testing.T) {
+	user := createUser(t, "secret123")
+	until := time.Now().Add(time.Hour)
+	user.LockedUntil = &until
+	user.LockoutCount = 2
+	repo := newFakeUserRepo(user)
+	audit := &fakeAuditLogger{}
+	uc := usecase.NewUnlockAccountUsecase(repo, audit, noopLogger{})
+
+	out, err := uc.Execute(context.Background(), input.UnlockAccountInput{UserID: user.ID.String()})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.WasLocked {
+		t.Error("Execute() should report the account was locked")
+	}
+	if user.LockedUntil != nil || user.LockoutCount != 0 {
+		t.Errorf("lockout state not cleared: locked_until=%v lockout_count=%d", user.LockedUntil, user.LockoutCount)
+	}
+	assertActions(t, audit.actions(), entity.AuditActionAccountUnlocked)
+
+	_, err = uc.Execute(context.Background(), input.UnlockAccountInput{UserID: "0190a5b0-7e1c-7b3d-8f4e-000000000000"})
+	if err != exception.ErrUserNotFound {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUserNotFound, err)
+	}
+	_, err = uc.Execute(context.Background(), input.UnlockAccountInput{UserID: "not-a-uuid"})
+	if err != exception.ErrUserNotFound {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUserNotFound, err)
+	}
+}
@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+const oauthPostLogoutURI = "https://app.example.com/signed-out"
+
+// signIn runs the authorization code flow for clientID with the openid
+// scope and returns the tokens it ends with.
+func (f *oauthFixture) signIn(t *testing.T, clientID string) *output.OAuthTokenOutput {
+	t.Helper()
+	req := authorizationRequest(clientID)
+	req.Scope = "openid profile"
+	authorized, err := f.authorizeUC.Execute(context.Background(), input.AuthorizeInput{Request: req, UserID: f.user.ID.String()})
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+
+	exchange := codeExchange(clientID, authorized.Code)
+	if clientID == oauthConfidentialClientID {
+		exchange.ClientSecret = oauthClientSecret
+	}
+	out, err := f.tokenUC.Execute(context.Background(), exchange)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestLogout_IDTokenNamesSession(t *testing.T) {
+	f := newOAuthFixture(t)
+	out := f.signIn(t, oauthPublicClientID)
+
+	refresh, _ := f.refreshRepo.FindByHash(context.Background(), token.NewOpaqueGenerator().Hash(out.RefreshToken))
+	claims := f.idTokens.signed[0]
+	if refresh == nil || claims.SessionID != refresh.FamilyID {
+		t.Fatalf("sid = %q, want the refresh token family", claims.SessionID)
+	}
+	if f.sessionRepo.activeCount() != 1 || f.sessionRepo.sessions[claims.SessionID] == nil {
+		t.Errorf("the session named by sid should be recorded, got %d sessions", f.sessionRepo.activeCount())
+	}
+}
+
+func TestLogout_EndsEverySession(t *testing.T) {
+	f := newOAuthFixture(t)
+	mobile := f.clientRepo.clients[oauthPublicClientID]
+	mobile.PostLogoutRedirectURIs = []string{oauthPostLogoutURI}
+	mobile.FrontchannelLogoutURI = "https://app.example.com/frontchannel-logout"
+	f.clientRepo.clients[oauthConfidentialClientID].BackchannelLogoutURI = "https://web.example.com/backchannel-logout"
+
+	signedIn := f.signIn(t, oauthPublicClientID)
+	f.signIn(t, oauthConfidentialClientID)
+	mobileSession := f.idTokens.signed[0].SessionID
+	webSession := f.idTokens.signed[1].SessionID
+
+	out, err := f.endSessionUC.Execute(context.Background(), input.EndSessionInput{
+		IDTokenHint:           signedIn.IDToken,
+		ClientID:              oauthPublicClientID,
+		PostLogoutRedirectURI: oauthPostLogoutURI,
+		State:                 "bye",
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.PostLogoutRedirectURI != oauthPostLogoutURI || out.State != "bye" {
+		t.Errorf("Execute() = %+v, want the post-logout redirect and state", out)
+	}
+	if len(out.FrontchannelLogouts) != 1 || out.FrontchannelLogouts[0].SessionID != mobileSession {
+		t.Errorf("FrontchannelLogouts = %+v, want the mobile client's session", out.FrontchannelLogouts)
+	}
+
+	if f.sessionRepo.activeCount() != 0 || f.refreshRepo.activeCount() != 0 {
+		t.Error("every session should end and every refresh token be revoked")
+	}
+	if len(f.outbox.messages) != 1 {
+		t.Fatalf("published %v, want one back-channel logout", f.outbox.eventTypes())
+	}
+	msg := f.outbox.messages[0]
+	payload, ok := msg.Payload.(event.BackchannelLogoutEvent)
+	if msg.EventType != event.OIDCBackchannelLogout || !ok ||
+		payload.ClientID != oauthConfidentialClientID || payload.SessionID != webSession || payload.LogoutURI != "https://web.example.com/backchannel-logout" {
+		t.Errorf("published %+v, want a back-channel logout of the web client's session", msg)
+	}
+	assertActions(t, f.audit.actions(),
+		entity.AuditActionOAuthAuthorized, entity.AuditActionOAuthTokenIssued,
+		entity.AuditActionOAuthAuthorized, entity.AuditActionOAuthTokenIssued,
+		entity.AuditActionOIDCLogout)
+}
+
+func TestLogout_WithoutRedirect(t *testing.T) {
+	f := newOAuthFixture(t)
+	signedIn := f.signIn(t, oauthPublicClientID)
+
+	out, err := f.endSessionUC.Execute(context.Background(), input.EndSessionInput{IDTokenHint: signedIn.IDToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.PostLogoutRedirectURI != "" || len(out.FrontchannelLogouts) != 0 || len(f.outbox.messages) != 0 {
+		t.Errorf("Execute() = %+v, want nothing to redirect to or notify", out)
+	}
+	if f.refreshRepo.activeCount() != 0 {
+		t.Error("the user's refresh tokens should be revoked")
+	}
+}
+
+func TestLogout_Rejections(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.clientRepo.clients[oauthPublicClientID].PostLogoutRedirectURIs = []string{oauthPostLogoutURI}
+	signedIn := f.signIn(t, oauthPublicClientID)
+
+	tests := []struct {
+		name string
+		req  input.EndSessionInput
+	}{
+		{name: "missing hint", req: input.EndSessionInput{PostLogoutRedirectURI: oauthPostLogoutURI}},
+		{name: "invalid hint", req: input.EndSessionInput{IDTokenHint: "not-an-id-token"}},
+		{name: "other client", req: input.EndSessionInput{IDTokenHint: signedIn.IDToken, ClientID: oauthConfidentialClientID}},
+		{name: "unregistered redirect", req: input.EndSessionInput{IDTokenHint: signedIn.IDToken, PostLogoutRedirectURI: "https://evil.example.com/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.endSessionUC.Execute(context.Background(), tt.req)
+			assertOAuthError(t, err, exception.OAuthInvalidRequest)
+		})
+	}
+	if f.sessionRepo.activeCount() != 1 || f.refreshRepo.activeCount() != 1 {
+		t.Error("a rejected logout should leave the session alone")
+	}
+}
@@ -0,0 +1,370 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/encryption"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webauthn"
+)
+
+const mfaMaxAttempts = 3
+
+type mfaFixture struct {
+	user          *entity.User
+	totpRepo      *fakeTOTPCredentialRepo
+	recoveryRepo  *fakeMFARecoveryCodeRepo
+	challengeRepo *fakeMFAChallengeRepo
+	passkeyRepo   *fakeWebAuthnCredentialRepo
+	audit         *fakeAuditLogger
+	outbox        *fakeOutbox
+	refreshRepo   *fakeRefreshTokenRepo
+	// enrollCode is the code enrollment was confirmed with.
+	enrollCode string
+
+	login     port.LoginUseCase
+	setup     port.SetupTOTPUseCase
+	confirm   port.ConfirmTOTPUseCase
+	disable   port.DisableTOTPUseCase
+	challenge port.VerifyMFAChallengeUseCase
+
+	beginRegistration  port.BeginPasskeyRegistrationUseCase
+	finishRegistration port.FinishPasskeyRegistrationUseCase
+	listPasskeys       port.ListPasskeysUseCase
+	deletePasskey      port.DeletePasskeyUseCase
+	beginPasskeyLogin  port.BeginPasskeyLoginUseCase
+	finishPasskeyLogin port.FinishPasskeyLoginUseCase
+	beginMFAPasskey    port.BeginMFAPasskeyUseCase
+}
+
+func newMFAFixture(t *testing.T) *mfaFixture {
+	t.Helper()
+
+	secrets, err := encryption.NewAESGCM(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewAESGCM() unexpected error: %v", err)
+	}
+
+	f := &mfaFixture{
+		user:          createUser(t, "secret123"),
+		totpRepo:      newFakeTOTPCredentialRepo(),
+		recoveryRepo:  &fakeMFARecoveryCodeRepo{},
+		challengeRepo: newFakeMFAChallengeRepo(),
+		passkeyRepo:   newFakeWebAuthnCredentialRepo(),
+		audit:         &fakeAuditLogger{},
+		outbox:        &fakeOutbox{},
+		refreshRepo:   newFakeRefreshTokenRepo(),
+	}
+
+	userRepo := newFakeUserRepo(f.user)
+	uuids := &sequentialUUIDGenerator{}
+	opaqueTokens := token.NewOpaqueGenerator()
+	totp := otp.NewTOTP("auth-service", 1)
+	sessions := newSessionService(f.refreshRepo)
+	passkeys := service.NewPasskeyService(
+		f.passkeyRepo,
+		newFakeWebAuthnSessionRepo(),
+		webauthn.NewRelyingParty(passkeyRPID, "Auth Service", []string{passkeyOrigin}),
+		uuids,
+		5*time.Minute,
+	)
+	mfa := service.NewMFAService(
+		f.totpRepo,
+		f.passkeyRepo,
+		f.recoveryRepo,
+		f.challengeRepo,
+		totp,
+		secrets,
+		otp.NewRecoveryCodeGenerator(),
+		opaqueTokens,
+		uuids,
+		5*time.Minute,
+		4,
+	)
+
+	f.login = usecase.NewLoginUsecase(userRepo, f.audit, noopLogger{}, &fakeHasher{}, nil, nil, newLoginService(sessions, mfa, f.audit, newFakeMetrics()), newFakeMetrics(), cache.NewLoginFailures(time.Minute), false, entity.LockoutPolicy{})
+	f.setup = usecase.NewSetupTOTPUsecase(userRepo, f.totpRepo, totp, secrets, fakeQRCodeEncoder{}, uuids, noopLogger{})
+	f.confirm = usecase.NewConfirmTOTPUsecase(f.totpRepo, totp, secrets, mfa, &fakeTxManager{}, f.outbox, noopLogger{})
+	f.disable = usecase.NewDisableTOTPUsecase(userRepo, f.totpRepo, f.recoveryRepo, &fakeHasher{}, mfa, mfa, &fakeTxManager{}, f.outbox, noopLogger{})
+	f.challenge = usecase.NewVerifyMFAChallengeUsecase(
+		f.challengeRepo,
+		userRepo,
+		mfa,
+		passkeys,
+		sessions,
+		&fakeTxManager{},
+		f.audit,
+		newFakeMetrics(),
+		noopLogger{},
+		opaqueTokens,
+		mfaMaxAttempts,
+	)
+
+	f.beginRegistration = usecase.NewBeginPasskeyRegistrationUsecase(userRepo, passkeys, noopLogger{})
+	f.finishRegistration = usecase.NewFinishPasskeyRegistrationUsecase(passkeys, mfa, mfa, &fakeTxManager{}, f.outbox, noopLogger{})
+	f.listPasskeys = usecase.NewListPasskeysUsecase(f.passkeyRepo, noopLogger{})
+	f.deletePasskey = usecase.NewDeletePasskeyUsecase(userRepo, f.passkeyRepo, f.recoveryRepo, &fakeHasher{}, mfa, &fakeTxManager{}, f.outbox, noopLogger{})
+	f.beginPasskeyLogin = usecase.NewBeginPasskeyLoginUsecase(userRepo, passkeys, noopLogger{})
+	f.finishPasskeyLogin = usecase.NewFinishPasskeyLoginUsecase(userRepo, passkeys, newLoginService(sessions, mfa, f.audit, newFakeMetrics()), f.audit, newFakeMetrics(), noopLogger{}, false)
+	f.beginMFAPasskey = usecase.NewBeginMFAPasskeyUsecase(f.challengeRepo, passkeys, noopLogger{}, opaqueTokens, mfaMaxAttempts)
+	return f
+}
+
+// totpCode returns the code for the period offset steps from now.
+func totpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("failed to decode TOTP secret: %v", err)
+	}
+	return otp.Code(key, time.Now().Unix()/30+offset)
+}
+
+// enroll sets up and confirms TOTP, returning the secret and recovery codes.
+func (f *mfaFixture) enroll(t *testing.T) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	setup, err := f.setup.Execute(ctx, input.SetupTOTPInput{UserID: f.user.ID.String()})
+	if err != nil {
+		t.Fatalf("SetupTOTP.Execute() unexpected error: %v", err)
+	}
+
+	f.enrollCode = totpCode(t, setup.Secret, 0)
+	confirmed, err := f.confirm.Execute(ctx, input.ConfirmTOTPInput{
+		UserID: f.user.ID.String(),
+		Code:   f.enrollCode,
+	})
+	if err != nil {
+		t.Fatalf("ConfirmTOTP.Execute() unexpected error: %v", err)
+	}
+	return setup.Secret, confirmed.RecoveryCodes
+}
+
+func (f *mfaFixture) passwordStep(t *testing.T) *output.LoginOutput {
+	t.Helper()
+	out, err := f.login.Execute(context.Background(), input.LoginInput{Identifier: "testuser", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login.Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestSetupTOTP(t *testing.T) {
+	f := newMFAFixture(t)
+
+	out, err := f.setup.Execute(context.Background(), input.SetupTOTPInput{UserID: f.user.ID.String()})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(out.ProvisioningURI, "otpauth://totp/") || !strings.Contains(out.ProvisioningURI, "secret="+out.Secret) {
+		t.Errorf("ProvisioningURI = %q, want otpauth URI with the secret", out.ProvisioningURI)
+	}
+	if string(out.QRCodePNG) != "png:"+out.ProvisioningURI {
+		t.Errorf("QRCodePNG should encode the provisioning URI")
+	}
+
+	stored, _ := f.totpRepo.FindByUserID(context.Background(), f.user.ID.String())
+	if stored == nil || stored.IsConfirmed() {
+		t.Fatalf("expected a pending credential, got %+v", stored)
+	}
+	if strings.Contains(string(stored.SecretCiphertext), out.Secret) {
+		t.Error("TOTP secret must not be stored in plaintext")
+	}
+
+	// Login is unaffected until setup is confirmed.
+	if login := f.passwordStep(t); login.MFARequired {
+		t.Error("unconfirmed TOTP should not require MFA at login")
+	}
+}
+
+func TestConfirmTOTP(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+
+	_, err := f.confirm.Execute(ctx, input.ConfirmTOTPInput{UserID: f.user.ID.String(), Code: "123456"})
+	if err != exception.ErrMFASetupNotStarted {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrMFASetupNotStarted, err)
+	}
+
+	setup, _ := f.setup.Execute(ctx, input.SetupTOTPInput{UserID: f.user.ID.String()})
+	wrong := totpCode(t, setup.Secret, 5)
+	_, err = f.confirm.Execute(ctx, input.ConfirmTOTPInput{UserID: f.user.ID.String(), Code: wrong})
+	if err != exception.ErrInvalidMFACode {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidMFACode, err)
+	}
+
+	out, err := f.confirm.Execute(ctx, input.ConfirmTOTPInput{UserID: f.user.ID.String(), Code: totpCode(t, setup.Secret, 0)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(out.RecoveryCodes) != 4 {
+		t.Errorf("expected 4 recovery codes, got %d", len(out.RecoveryCodes))
+	}
+	for _, c := range f.recoveryRepo.codes {
+		for _, code := range out.RecoveryCodes {
+			if c.CodeHash == code {
+				t.Error("recovery codes must be stored hashed")
+			}
+		}
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserMFAEnabled)
+
+	_, err = f.setup.Execute(ctx, input.SetupTOTPInput{UserID: f.user.ID.String()})
+	if err != exception.ErrMFAAlreadyEnabled {
+		t.Errorf("Setup after enrollment expected error %v, got %v", exception.ErrMFAAlreadyEnabled, err)
+	}
+}
+
+func TestLogin_RequiresMFAChallenge(t *testing.T) {
+	f := newMFAFixture(t)
+	secret, _ := f.enroll(t)
+	ctx := context.Background()
+
+	login := f.passwordStep(t)
+	if !login.MFARequired || login.MFAToken == "" || login.AccessToken != "" {
+		t.Fatalf("password step = %+v, want an MFA challenge and no tokens", login)
+	}
+	if f.refreshRepo.activeCount() != 0 {
+		t.Error("no session should be issued before the second factor")
+	}
+
+	// The code used to confirm enrollment cannot be replayed.
+	_, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: f.enrollCode})
+	if err != exception.ErrInvalidMFACode {
+		t.Fatalf("Execute() with replayed code expected error %v, got %v", exception.ErrInvalidMFACode, err)
+	}
+
+	out, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: totpCode(t, secret, 1)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" || out.RefreshToken == "" {
+		t.Error("Execute() should return a session")
+	}
+
+	_, err = f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: totpCode(t, secret, 1)})
+	if err != exception.ErrInvalidMFAChallenge {
+		t.Errorf("reusing a challenge expected error %v, got %v", exception.ErrInvalidMFAChallenge, err)
+	}
+
+	assertActions(t, f.audit.actions(),
+		entity.AuditActionMFAChallengeFailed,
+		entity.AuditActionMFAChallengeSucceeded,
+		entity.AuditActionUserLogin,
+	)
+}
+
+// TestLogin_VerifyOnly checks the sign-in that authorizes an OAuth client:
+// both factors are checked, but no session is issued or logged as a login.
+func TestLogin_VerifyOnly(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+
+	out, err := f.login.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "secret123", VerifyOnly: true})
+	if err != nil {
+		t.Fatalf("Login.Execute() unexpected error: %v", err)
+	}
+	if out.UserID != f.user.ID.String() || out.AccessToken != "" || out.RefreshToken != "" {
+		t.Errorf("password step = %+v, want the user and no tokens", out)
+	}
+
+	secret, _ := f.enroll(t)
+	login, err := f.login.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: "secret123", VerifyOnly: true})
+	if err != nil || !login.MFARequired {
+		t.Fatalf("Login.Execute() = %+v, %v, want an MFA challenge", login, err)
+	}
+	out, err = f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: totpCode(t, secret, 1), VerifyOnly: true})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.UserID != f.user.ID.String() || out.AccessToken != "" || out.RefreshToken != "" {
+		t.Errorf("Execute() = %+v, want the user and no tokens", out)
+	}
+
+	if f.refreshRepo.activeCount() != 0 {
+		t.Errorf("expected no session, got %d", f.refreshRepo.activeCount())
+	}
+	assertActions(t, f.audit.actions(), entity.AuditActionMFAChallengeSucceeded)
+}
+
+func TestMFAChallenge_RecoveryCodeIsSingleUse(t *testing.T) {
+	f := newMFAFixture(t)
+	_, codes := f.enroll(t)
+	ctx := context.Background()
+
+	login := f.passwordStep(t)
+	// Codes are accepted regardless of case and dashes.
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	if _, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: typed}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	login = f.passwordStep(t)
+	_, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: codes[0]})
+	if err != exception.ErrInvalidMFACode {
+		t.Fatalf("reusing a recovery code expected error %v, got %v", exception.ErrInvalidMFACode, err)
+	}
+
+	if n, _ := f.recoveryRepo.CountUnused(ctx, f.user.ID.String()); n != len(codes)-1 {
+		t.Errorf("unused recovery codes = %d, want %d", n, len(codes)-1)
+	}
+}
+
+func TestMFAChallenge_LimitsAttempts(t *testing.T) {
+	f := newMFAFixture(t)
+	secret, _ := f.enroll(t)
+	ctx := context.Background()
+
+	login := f.passwordStep(t)
+	for i := 0; i < mfaMaxAttempts; i++ {
+		_, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: "000000"})
+		if err != exception.ErrInvalidMFACode {
+			t.Fatalf("attempt %d: Execute() expected error %v, got %v", i+1, exception.ErrInvalidMFACode, err)
+		}
+	}
+
+	_, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{MFAToken: login.MFAToken, Code: totpCode(t, secret, 1)})
+	if err != exception.ErrInvalidMFAChallenge {
+		t.Errorf("Execute() after too many attempts expected error %v, got %v", exception.ErrInvalidMFAChallenge, err)
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	f := newMFAFixture(t)
+	secret, _ := f.enroll(t)
+	ctx := context.Background()
+
+	_, err := f.disable.Execute(ctx, input.DisableTOTPInput{UserID: f.user.ID.String(), Password: "wrong", Code: totpCode(t, secret, 1)})
+	if err != exception.ErrInvalidCredentials {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+
+	_, err = f.disable.Execute(ctx, input.DisableTOTPInput{UserID: f.user.ID.String(), Password: "secret123", Code: totpCode(t, secret, 1)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if login := f.passwordStep(t); login.MFARequired {
+		t.Error("login should not require MFA after disabling it")
+	}
+	if n, _ := f.recoveryRepo.CountUnused(ctx, f.user.ID.String()); n != 0 {
+		t.Errorf("recovery codes should be deleted, %d left", n)
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserMFAEnabled, event.UserMFADisabled)
+}
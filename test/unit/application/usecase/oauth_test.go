@@ -0,0 +1,574 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+const (
+	oauthPublicClientID        = "0190a5b0-7e1c-7b3d-8f4e-c11e00000001"
+	oauthConfidentialClientID  = "0190a5b0-7e1c-7b3d-8f4e-c11e00000002"
+	oauthRedirectURI           = "https://app.example.com/callback"
+	oauthClientSecret          = "confidential-client-secret"
+	oauthCodeVerifier          = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	oauthIssuer                = "https://auth.example.com"
+	oauthDeviceVerificationURL = "https://app.example.com/device"
+)
+
+var oauthScopes = []string{"openid", "profile", "email"}
+
+type oauthFixture struct {
+	validateUC      port.ValidateAuthorizationRequestUseCase
+	authorizeUC     port.AuthorizeUseCase
+	tokenUC         port.OAuthTokenUseCase
+	introspectUC    port.IntrospectTokenUseCase
+	revokeUC        port.RevokeTokenUseCase
+	deviceUC        port.StartDeviceAuthorizationUseCase
+	lookupUC        port.LookupDeviceAuthorizationUseCase
+	decideUC        port.DecideDeviceAuthorizationUseCase
+	refreshUC       port.RefreshUseCase
+	endSessionUC    port.EndSessionUseCase
+	lookupConsentUC port.LookupConsentUseCase
+	decideConsentUC port.DecideConsentUseCase
+	listConsentsUC  port.ListConsentsUseCase
+	revokeConsentUC port.RevokeConsentUseCase
+	codeRepo        *fakeAuthorizationCodeRepo
+	deviceRepo      *fakeDeviceAuthorizationRepo
+	refreshRepo     *fakeRefreshTokenRepo
+	sessionRepo     *fakeOAuthSessionRepo
+	clientRepo      *fakeOAuthClientRepo
+	consentRepo     *fakeUserConsentRepo
+	challenges      *fakeConsentChallengeRepo
+	outbox          *fakeOutbox
+	audit           *fakeAuditLogger
+	idTokens        *fakeIDTokenSigner
+	userRepo        *fakeUserRepo
+	tokens          *recordingTokenService
+	revocations     *fakeRevocationList
+	user            *entity.User
+
+	// newTokenUC builds another token use case over the same fakes, with
+	// the device grant on or off.
+	newTokenUC func(deviceGrant bool) port.OAuthTokenUseCase
+}
+
+func newOAuthFixture(t *testing.T) *oauthFixture {
+	t.Helper()
+
+	opaque := token.NewOpaqueGenerator()
+	grants := []string{entity.OAuthGrantAuthorizationCode, entity.OAuthGrantRefreshToken}
+
+	f := &oauthFixture{
+		codeRepo:    newFakeAuthorizationCodeRepo(),
+		deviceRepo:  newFakeDeviceAuthorizationRepo(),
+		refreshRepo: newFakeRefreshTokenRepo(),
+		sessionRepo: newFakeOAuthSessionRepo(),
+		clientRepo: newFakeOAuthClientRepo(
+			entity.NewOAuthClient(oauthPublicClientID, "Mobile", "", []string{oauthRedirectURI}, oauthScopes, grants),
+			entity.NewOAuthClient(oauthConfidentialClientID, "Web", opaque.Hash(oauthClientSecret), []string{oauthRedirectURI}, oauthScopes, grants),
+		),
+		consentRepo: newFakeUserConsentRepo(),
+		challenges:  newFakeConsentChallengeRepo(),
+		outbox:      &fakeOutbox{},
+		audit:       &fakeAuditLogger{},
+		idTokens:    &fakeIDTokenSigner{},
+		tokens:      &recordingTokenService{},
+		revocations: newFakeRevocationList(),
+		user:        createUser(t, "secret123"),
+	}
+
+	// Both clients are our own apps unless a test says otherwise, so
+	// authorizing them issues a code without the consent page.
+	for _, client := range f.clientRepo.clients {
+		client.FirstParty = true
+	}
+
+	userRepo := newFakeUserRepo(f.user)
+	f.userRepo = userRepo
+	sessions := newSessionService(f.refreshRepo)
+	uuids := &sequentialUUIDGenerator{}
+
+	f.validateUC = usecase.NewValidateAuthorizationRequestUsecase(f.clientRepo, noopLogger{}, oauthScopes)
+	f.authorizeUC = usecase.NewAuthorizeUsecase(
+		f.clientRepo,
+		f.codeRepo,
+		userRepo,
+		f.consentRepo,
+		f.challenges,
+		f.audit,
+		noopLogger{},
+		opaque,
+		uuids,
+		oauthScopes,
+		time.Minute,
+		10*time.Minute,
+	)
+	f.refreshUC = usecase.NewRefreshUsecase(userRepo, f.refreshRepo, f.audit, noopLogger{}, opaque, sessions)
+	clients := service.NewClientAuthenticator(f.clientRepo, newFakeClientAssertionRepo(), opaque, token.NewClientAssertionVerifier(), noopLogger{}, oauthIssuer)
+	f.newTokenUC = func(deviceGrant bool) port.OAuthTokenUseCase {
+		return usecase.NewOAuthTokenUsecase(
+			clients,
+			f.codeRepo,
+			f.deviceRepo,
+			userRepo,
+			f.refreshRepo,
+			f.sessionRepo,
+			sessions,
+			f.refreshUC,
+			f.audit,
+			noopLogger{},
+			opaque,
+			f.idTokens,
+			f.tokens,
+			f.revocations,
+			deviceGrant,
+		)
+	}
+	f.tokenUC = f.newTokenUC(true)
+	f.introspectUC = usecase.NewIntrospectTokenUsecase(clients, f.refreshRepo, f.tokens, f.revocations, opaque, noopLogger{})
+	f.revokeUC = usecase.NewRevokeTokenUsecase(clients, f.refreshRepo, f.tokens, f.revocations, opaque, f.audit, noopLogger{})
+	f.deviceUC = usecase.NewStartDeviceAuthorizationUsecase(
+		clients,
+		f.deviceRepo,
+		noopLogger{},
+		opaque,
+		otp.NewUserCodeGenerator(),
+		uuids,
+		oauthDeviceVerificationURL,
+		10*time.Minute,
+		5*time.Second,
+	)
+	f.lookupUC = usecase.NewLookupDeviceAuthorizationUsecase(f.deviceRepo, f.clientRepo, noopLogger{}, opaque)
+	f.decideUC = usecase.NewDecideDeviceAuthorizationUsecase(f.deviceRepo, f.clientRepo, userRepo, f.audit, noopLogger{}, opaque)
+	f.lookupConsentUC = usecase.NewLookupConsentUsecase(f.clientRepo, f.consentRepo, f.challenges, noopLogger{}, opaque)
+	f.decideConsentUC = usecase.NewDecideConsentUsecase(
+		f.clientRepo,
+		f.codeRepo,
+		userRepo,
+		f.consentRepo,
+		f.challenges,
+		f.audit,
+		noopLogger{},
+		opaque,
+		uuids,
+		oauthScopes,
+		time.Minute,
+	)
+	f.listConsentsUC = usecase.NewListConsentsUsecase(f.consentRepo, f.clientRepo, noopLogger{})
+	f.revokeConsentUC = usecase.NewRevokeConsentUsecase(f.consentRepo, f.refreshRepo, &fakeTxManager{}, f.audit, noopLogger{})
+	f.endSessionUC = usecase.NewEndSessionUsecase(f.clientRepo, f.sessionRepo, f.refreshRepo, f.idTokens, &fakeTxManager{}, f.outbox, f.audit, noopLogger{})
+	return f
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func authorizationRequest(clientID string) input.AuthorizationRequest {
+	return input.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            clientID,
+		RedirectURI:         oauthRedirectURI,
+		Scope:               "profile email",
+		State:               "xyz",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       pkceChallenge(oauthCodeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func (f *oauthFixture) authorize(t *testing.T, clientID string) *output.AuthorizeOutput {
+	t.Helper()
+	out, err := f.authorizeUC.Execute(context.Background(), input.AuthorizeInput{
+		Request: authorizationRequest(clientID),
+		UserID:  f.user.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	return out
+}
+
+func codeExchange(clientID, code string) input.OAuthTokenInput {
+	return input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantAuthorizationCode,
+		ClientID:     clientID,
+		Code:         code,
+		RedirectURI:  oauthRedirectURI,
+		CodeVerifier: oauthCodeVerifier,
+	}
+}
+
+func assertOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *exception.OAuthError
+	if !errors.As(err, &oauthErr) {
+		t.Fatalf("expected OAuth error %s, got %v", code, err)
+	}
+	if oauthErr.Code != code {
+		t.Errorf("OAuth error code = %s, want %s (%s)", oauthErr.Code, code, oauthErr.Description)
+	}
+}
+
+func TestOAuth_AuthorizationCodeFlow(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	authorized := f.authorize(t, oauthPublicClientID)
+	if authorized.Code == "" || authorized.State != "xyz" || authorized.RedirectURI != oauthRedirectURI {
+		t.Fatalf("Authorize() = %+v, want a code for the registered redirect URI and the original state", authorized)
+	}
+
+	out, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if out.AccessToken == "" || out.RefreshToken == "" || out.TokenType != "Bearer" {
+		t.Errorf("Execute() = %+v, want an access and a refresh token", out)
+	}
+	if entity.FormatScope(out.Scopes) != "profile email" {
+		t.Errorf("Scopes = %v, want [profile email]", out.Scopes)
+	}
+
+	stored, _ := f.refreshRepo.FindByHash(context.Background(), token.NewOpaqueGenerator().Hash(out.RefreshToken))
+	if stored == nil || stored.ClientID != oauthPublicClientID {
+		t.Errorf("refresh token should be bound to the client, got %+v", stored)
+	}
+
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthAuthorized, entity.AuditActionOAuthTokenIssued)
+}
+
+func TestOAuth_ValidateRejectsRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*input.AuthorizationRequest)
+		wantErr error
+		code    string
+	}{
+		{"unknown client", func(r *input.AuthorizationRequest) { r.ClientID = "0190a5b0-7e1c-7b3d-8f4e-c11e000000ff" }, exception.ErrInvalidOAuthClient, ""},
+		{"missing client", func(r *input.AuthorizationRequest) { r.ClientID = "" }, exception.ErrInvalidOAuthClient, ""},
+		{"redirect with trailing slash", func(r *input.AuthorizationRequest) { r.RedirectURI += "/" }, exception.ErrInvalidRedirectURI, ""},
+		{"redirect with extra query", func(r *input.AuthorizationRequest) { r.RedirectURI += "?next=/" }, exception.ErrInvalidRedirectURI, ""},
+		{"implicit grant", func(r *input.AuthorizationRequest) { r.ResponseType = "token" }, nil, exception.OAuthUnsupportedResponseType},
+		{"missing challenge", func(r *input.AuthorizationRequest) { r.CodeChallenge = "" }, nil, exception.OAuthInvalidRequest},
+		{"plain challenge", func(r *input.AuthorizationRequest) { r.CodeChallengeMethod = "plain" }, nil, exception.OAuthInvalidRequest},
+		{"missing method", func(r *input.AuthorizationRequest) { r.CodeChallengeMethod = "" }, nil, exception.OAuthInvalidRequest},
+		{"malformed challenge", func(r *input.AuthorizationRequest) { r.CodeChallenge = "short" }, nil, exception.OAuthInvalidRequest},
+		{"missing scope", func(r *input.AuthorizationRequest) { r.Scope = "" }, nil, exception.OAuthInvalidScope},
+		{"unknown scope", func(r *input.AuthorizationRequest) { r.Scope = "profile admin" }, nil, exception.OAuthInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			req := authorizationRequest(oauthPublicClientID)
+			tt.modify(&req)
+
+			_, err := f.validateUC.Execute(context.Background(), req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestOAuth_ValidateRejectsScopeNotGrantedToClient(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.clientRepo.clients[oauthPublicClientID].Scopes = []string{"profile"}
+
+	_, err := f.validateUC.Execute(context.Background(), authorizationRequest(oauthPublicClientID))
+	assertOAuthError(t, err, exception.OAuthInvalidScope)
+}
+
+func TestOAuth_CodeIsSingleUse(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorize(t, oauthPublicClientID)
+
+	if _, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code)); err != nil {
+		t.Fatalf("first Execute() unexpected error: %v", err)
+	}
+	if f.refreshRepo.activeCount() != 1 {
+		t.Fatalf("expected one refresh token after the exchange, got %d", f.refreshRepo.activeCount())
+	}
+
+	_, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+
+	if f.refreshRepo.activeCount() != 0 {
+		t.Errorf("replaying the code should revoke the tokens it issued, %d still active", f.refreshRepo.activeCount())
+	}
+	assertActions(t, f.audit.actions(),
+		entity.AuditActionOAuthAuthorized,
+		entity.AuditActionOAuthTokenIssued,
+		entity.AuditActionOAuthCodeReused,
+	)
+}
+
+func TestOAuth_ExchangeRejections(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*input.OAuthTokenInput)
+		code   string
+	}{
+		{"wrong verifier", func(in *input.OAuthTokenInput) { in.CodeVerifier = strings.Repeat("a", 43) }, exception.OAuthInvalidGrant},
+		{"missing verifier", func(in *input.OAuthTokenInput) { in.CodeVerifier = "" }, exception.OAuthInvalidRequest},
+		{"different redirect", func(in *input.OAuthTokenInput) { in.RedirectURI = "https://app.example.com/other" }, exception.OAuthInvalidGrant},
+		{"unknown code", func(in *input.OAuthTokenInput) { in.Code = "not-a-code" }, exception.OAuthInvalidGrant},
+		{"other client", func(in *input.OAuthTokenInput) {
+			in.ClientID = oauthConfidentialClientID
+			in.ClientSecret = oauthClientSecret
+		}, exception.OAuthInvalidGrant},
+		{"unsupported grant", func(in *input.OAuthTokenInput) { in.GrantType = "password" }, exception.OAuthUnsupportedGrantType},
+		{"unknown client", func(in *input.OAuthTokenInput) { in.ClientID = "0190a5b0-7e1c-7b3d-8f4e-c11e000000ff" }, exception.OAuthInvalidClient},
+		{"secret for public client", func(in *input.OAuthTokenInput) { in.ClientSecret = "guess" }, exception.OAuthInvalidClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			authorized := f.authorize(t, oauthPublicClientID)
+
+			req := codeExchange(oauthPublicClientID, authorized.Code)
+			tt.modify(&req)
+			_, err := f.tokenUC.Execute(context.Background(), req)
+			assertOAuthError(t, err, tt.code)
+
+			// A rejected exchange does not consume the code.
+			if _, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code)); err != nil {
+				t.Errorf("Execute() after rejection unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOAuth_ExpiredCode(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorize(t, oauthPublicClientID)
+	for _, c := range f.codeRepo.codes {
+		c.ExpiresAt = time.Now().Add(-time.Second)
+	}
+
+	_, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+}
+
+func TestOAuth_ConfidentialClientAuthentication(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"correct secret", oauthClientSecret, false},
+		{"wrong secret", "not-the-secret", true},
+		{"missing secret", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			authorized := f.authorize(t, oauthConfidentialClientID)
+
+			req := codeExchange(oauthConfidentialClientID, authorized.Code)
+			req.ClientSecret = tt.secret
+			_, err := f.tokenUC.Execute(context.Background(), req)
+			if tt.wantErr {
+				assertOAuthError(t, err, exception.OAuthInvalidClient)
+				return
+			}
+			if err != nil {
+				t.Errorf("Execute() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOAuth_NoRefreshTokenWithoutRefreshGrant(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.clientRepo.clients[oauthPublicClientID].GrantTypes = []string{entity.OAuthGrantAuthorizationCode}
+	authorized := f.authorize(t, oauthPublicClientID)
+
+	out, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.RefreshToken != "" || f.refreshRepo.activeCount() != 0 {
+		t.Error("a client without the refresh_token grant should not receive a refresh token")
+	}
+}
+
+func TestOAuth_RefreshTokenGrant(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorize(t, oauthPublicClientID)
+	issued, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	refresh := func(clientID, secret, refreshToken, scope string) (*output.OAuthTokenOutput, error) {
+		return f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+			GrantType:    entity.OAuthGrantRefreshToken,
+			ClientID:     clientID,
+			ClientSecret: secret,
+			RefreshToken: refreshToken,
+			Scope:        scope,
+		})
+	}
+
+	_, err = refresh(oauthConfidentialClientID, oauthClientSecret, issued.RefreshToken, "")
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+
+	_, err = refresh(oauthPublicClientID, "", issued.RefreshToken, "profile email openid")
+	assertOAuthError(t, err, exception.OAuthInvalidScope)
+
+	_, err = f.refreshUC.Execute(context.Background(), input.RefreshInput{RefreshToken: issued.RefreshToken})
+	if !errors.Is(err, exception.ErrInvalidRefreshToken) {
+		t.Errorf("first-party refresh of a client token expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+	}
+
+	rotated, err := refresh(oauthPublicClientID, "", issued.RefreshToken, "profile")
+	if err != nil {
+		t.Fatalf("refresh unexpected error: %v", err)
+	}
+	if rotated.RefreshToken == "" || rotated.RefreshToken == issued.RefreshToken {
+		t.Error("refresh should rotate the refresh token")
+	}
+	if entity.FormatScope(rotated.Scopes) != "profile" {
+		t.Errorf("Scopes = %v, want the requested scope", rotated.Scopes)
+	}
+}
+
+func newCreateOAuthClientUC() (port.CreateOAuthClientUseCase, *fakeOAuthClientRepo) {
+	repo := newFakeOAuthClientRepo()
+	return usecase.NewCreateOAuthClientUsecase(
+		repo,
+		&fakeAuditLogger{},
+		noopLogger{},
+		token.NewOpaqueGenerator(),
+		&sequentialUUIDGenerator{},
+		token.NewClientAssertionVerifier(),
+		oauthScopes,
+		time.Hour,
+	), repo
+}
+
+func TestCreateOAuthClient(t *testing.T) {
+	newUC := newCreateOAuthClientUC
+
+	t.Run("confidential client gets a secret", func(t *testing.T) {
+		uc, repo := newUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:         "Web",
+			RedirectURIs: []string{oauthRedirectURI},
+			Scopes:       []string{"profile"},
+			Confidential: true,
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.ClientSecret == "" {
+			t.Fatal("Execute() should return the client secret")
+		}
+		stored := repo.clients[out.ClientID]
+		if stored.SecretHash != token.NewOpaqueGenerator().Hash(out.ClientSecret) {
+			t.Error("only the hash of the client secret should be stored")
+		}
+		if len(stored.GrantTypes) != 2 {
+			t.Errorf("GrantTypes = %v, want the default grants", stored.GrantTypes)
+		}
+	})
+
+	t.Run("public client has no secret", func(t *testing.T) {
+		uc, _ := newUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:         "Mobile",
+			RedirectURIs: []string{"com.example.app:/oauth/callback", "http://127.0.0.1:8123/callback"},
+			Scopes:       []string{"profile"},
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.ClientSecret != "" {
+			t.Error("a public client should not get a secret")
+		}
+	})
+
+	t.Run("device client needs no redirect URI", func(t *testing.T) {
+		uc, repo := newUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:       "CLI",
+			Scopes:     []string{"profile"},
+			GrantTypes: []string{entity.OAuthGrantDeviceCode, entity.OAuthGrantRefreshToken},
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if stored := repo.clients[out.ClientID]; len(stored.RedirectURIs) != 0 || !stored.AllowsGrantType(entity.OAuthGrantDeviceCode) {
+			t.Errorf("stored client = %+v, want the device grant without redirect URIs", stored)
+		}
+	})
+
+	t.Run("token exchange client lists its audiences", func(t *testing.T) {
+		uc, repo := newUC()
+		out, err := uc.Execute(context.Background(), input.CreateOAuthClientInput{
+			Name:              "Gateway",
+			Scopes:            []string{"profile"},
+			GrantTypes:        []string{entity.OAuthGrantTokenExchange},
+			ExchangeAudiences: []string{"orders-api"},
+			Confidential:      true,
+		})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if stored := repo.clients[out.ClientID]; !stored.MayExchangeFor("orders-api") || stored.MayExchangeFor("billing-api") {
+			t.Errorf("stored client = %+v, want exchange for orders-api only", stored)
+		}
+	})
+
+	rejected := []struct {
+		name  string
+		input input.CreateOAuthClientInput
+	}{
+		{"http redirect", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{"http://app.example.com/cb"}, Scopes: []string{"profile"}}},
+		{"fragment", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{"https://app.example.com/cb#x"}, Scopes: []string{"profile"}}},
+		{"relative", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{"/cb"}, Scopes: []string{"profile"}}},
+		{"script", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{"javascript:alert(1)"}, Scopes: []string{"profile"}}},
+		{"unknown scope", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"admin"}}},
+		{"public token exchange", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: []string{entity.OAuthGrantTokenExchange}, ExchangeAudiences: []string{"api"}}},
+		{"token exchange without audiences", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: []string{entity.OAuthGrantTokenExchange}, Confidential: true}},
+		{"audiences without token exchange", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, ExchangeAudiences: []string{"api"}}},
+		{"audience with spaces", input.CreateOAuthClientInput{Name: "x", Scopes: []string{"profile"}, GrantTypes: []string{entity.OAuthGrantTokenExchange}, ExchangeAudiences: []string{"orders api"}, Confidential: true}},
+		{"refresh only", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, GrantTypes: []string{"refresh_token"}}},
+		{"confidential without auth", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, Confidential: true, AuthMethod: entity.OAuthAuthMethodNone}},
+		{"private_key_jwt without jwks", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, AuthMethod: entity.OAuthAuthMethodPrivateKeyJWT}},
+		{"unknown auth method", input.CreateOAuthClientInput{Name: "x", RedirectURIs: []string{oauthRedirectURI}, Scopes: []string{"profile"}, AuthMethod: "tls_client_auth"}},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newUC()
+			_, err := uc.Execute(context.Background(), tt.input)
+			if !errors.Is(err, exception.ErrInvalidClientMetadata) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidClientMetadata, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+func (f *oauthFixture) authorizeScope(t *testing.T, scope string) *output.AuthorizeOutput {
+	t.Helper()
+	req := authorizationRequest(oauthPublicClientID)
+	req.Scope = scope
+	out, err := f.authorizeUC.Execute(context.Background(), input.AuthorizeInput{
+		Request: req,
+		UserID:  f.user.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestOIDC_IDTokenIssuedForOpenIDScope(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorizeScope(t, "openid profile email")
+
+	out, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.IDToken == "" {
+		t.Fatal("Execute() should return an ID token for the openid scope")
+	}
+
+	if len(f.idTokens.signed) != 1 {
+		t.Fatalf("signed %d ID tokens, want 1", len(f.idTokens.signed))
+	}
+	claims := f.idTokens.signed[0]
+	if claims.Subject != f.user.ID.String() || claims.Audience != oauthPublicClientID {
+		t.Errorf("sub/aud = %s/%s, want the user and the client", claims.Subject, claims.Audience)
+	}
+	if claims.Nonce != "n-0S6_WzA2Mj" {
+		t.Errorf("Nonce = %q, want the nonce from the authorization request", claims.Nonce)
+	}
+	if claims.AccessToken != out.AccessToken {
+		t.Error("ID token should be bound to the issued access token")
+	}
+	if claims.AuthTime.IsZero() {
+		t.Error("AuthTime should be set")
+	}
+	if claims.UserClaims["preferred_username"] != f.user.Username.String() ||
+		claims.UserClaims["email"] != f.user.Email.String() ||
+		claims.UserClaims["email_verified"] != f.user.IsEmailVerified {
+		t.Errorf("UserClaims = %v, want the profile and email claims", claims.UserClaims)
+	}
+}
+
+func TestOIDC_NoIDTokenWithoutOpenIDScope(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorizeScope(t, "profile")
+
+	out, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.IDToken != "" || len(f.idTokens.signed) != 0 {
+		t.Error("no ID token should be issued without the openid scope")
+	}
+}
+
+func TestOIDC_IDTokenReleasesOnlyGrantedClaims(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorizeScope(t, "openid")
+
+	if _, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code)); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if claims := f.idTokens.signed[0].UserClaims; len(claims) != 0 {
+		t.Errorf("UserClaims = %v, want none for the openid scope alone", claims)
+	}
+}
+
+func TestOIDC_ValidateRejectsUnsupportedParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*input.AuthorizationRequest)
+		code   string
+	}{
+		{"prompt none", func(r *input.AuthorizationRequest) { r.Prompt = "none" }, exception.OIDCLoginRequired},
+		{"prompt none with login", func(r *input.AuthorizationRequest) { r.Prompt = "none login" }, exception.OAuthInvalidRequest},
+		{"request object", func(r *input.AuthorizationRequest) { r.RequestObject = "eyJhbGciOiJub25lIn0.e30." }, exception.OIDCRequestNotSupported},
+		{"request uri", func(r *input.AuthorizationRequest) { r.RequestURI = "https://app.example.com/request.jwt" }, exception.OIDCRequestURINotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			req := authorizationRequest(oauthPublicClientID)
+			tt.modify(&req)
+
+			_, err := f.validateUC.Execute(context.Background(), req)
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestOIDC_ValidateAcceptsPromptLogin(t *testing.T) {
+	f := newOAuthFixture(t)
+	req := authorizationRequest(oauthPublicClientID)
+	req.Prompt = "login consent"
+
+	if _, err := f.validateUC.Execute(context.Background(), req); err != nil {
+		t.Errorf("Execute() unexpected error: %v", err)
+	}
+}
+
+func TestUserInfo(t *testing.T) {
+	f := newOAuthFixture(t)
+	uc := usecase.NewUserInfoUsecase(f.userRepo, noopLogger{})
+	userID := f.user.ID.String()
+
+	t.Run("claims follow the granted scopes", func(t *testing.T) {
+		out, err := uc.Execute(context.Background(), input.UserInfoInput{UserID: userID, Scopes: []string{"openid", "email"}})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.Claims["sub"] != userID || out.Claims["email"] != f.user.Email.String() {
+			t.Errorf("Claims = %v, want sub and email", out.Claims)
+		}
+		if _, ok := out.Claims["preferred_username"]; ok {
+			t.Error("preferred_username requires the profile scope")
+		}
+	})
+
+	t.Run("openid scope is required", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), input.UserInfoInput{UserID: userID, Scopes: []string{"profile"}})
+		if !errors.Is(err, exception.ErrInsufficientScope) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInsufficientScope, err)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		f.user.IsActive = false
+		defer func() { f.user.IsActive = true }()
+
+		_, err := uc.Execute(context.Background(), input.UserInfoInput{UserID: userID, Scopes: []string{"openid"}})
+		if !errors.Is(err, exception.ErrUserNotFound) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrUserNotFound, err)
+		}
+	})
+}
+
+func TestOIDC_RefreshIssuesNoIDToken(t *testing.T) {
+	f := newOAuthFixture(t)
+	authorized := f.authorizeScope(t, "openid profile")
+
+	first, err := f.tokenUC.Execute(context.Background(), codeExchange(oauthPublicClientID, authorized.Code))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	out, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantRefreshToken,
+		ClientID:     oauthPublicClientID,
+		RefreshToken: first.RefreshToken,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.IDToken != "" {
+		t.Error("the refresh grant should not issue an ID token")
+	}
+}
@@ -0,0 +1,403 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/test/support/softauthn"
+)
+
+const (
+	passkeyRPID   = "localhost"
+	passkeyOrigin = "http://localhost:8000"
+)
+
+// registerPasskey runs a full registration ceremony with authenticator.
+func (f *mfaFixture) registerPasskey(t *testing.T, authenticator *softauthn.Authenticator) *output.FinishPasskeyRegistrationOutput {
+	t.Helper()
+	ctx := context.Background()
+
+	options, err := f.beginRegistration.Execute(ctx, input.BeginPasskeyRegistrationInput{UserID: f.user.ID.String()})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration.Execute() unexpected error: %v", err)
+	}
+
+	reg, err := authenticator.Register(options.Challenge, options.UserHandle, softauthn.FormatNone)
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+
+	out, err := f.finishRegistration.Execute(ctx, input.FinishPasskeyRegistrationInput{
+		UserID:            f.user.ID.String(),
+		SessionID:         options.SessionID,
+		Name:              "Laptop",
+		ClientDataJSON:    reg.ClientDataJSON,
+		AttestationObject: reg.AttestationObject,
+		Transports:        []string{"internal"},
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration.Execute() unexpected error: %v", err)
+	}
+	return out
+}
+
+func passkeyAssertion(t *testing.T, authenticator *softauthn.Authenticator, options *output.PasskeyOptionsOutput, credentialID []byte) *input.PasskeyAssertion {
+	t.Helper()
+	assertion, err := authenticator.Assert(credentialID, options.Challenge)
+	if err != nil {
+		t.Fatalf("Assert() unexpected error: %v", err)
+	}
+	return &input.PasskeyAssertion{
+		SessionID:         options.SessionID,
+		CredentialID:      assertion.CredentialID,
+		ClientDataJSON:    assertion.ClientDataJSON,
+		AuthenticatorData: assertion.AuthenticatorData,
+		Signature:         assertion.Signature,
+		UserHandle:        assertion.UserHandle,
+	}
+}
+
+func (f *mfaFixture) storedCredentialID(t *testing.T) []byte {
+	t.Helper()
+	credentials, _ := f.passkeyRepo.ListByUserID(context.Background(), f.user.ID.String())
+	if len(credentials) != 1 {
+		t.Fatalf("expected one stored passkey, got %d", len(credentials))
+	}
+	return credentials[0].CredentialID
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+
+	out := f.registerPasskey(t, authenticator)
+	if out.Passkey.Name != "Laptop" || !out.Passkey.BackupEligible {
+		t.Errorf("Passkey = %+v, want name Laptop and backup eligible", out.Passkey)
+	}
+	// The first second factor comes with recovery codes.
+	if len(out.RecoveryCodes) != 4 {
+		t.Errorf("expected 4 recovery codes, got %d", len(out.RecoveryCodes))
+	}
+
+	// The ceremony cannot be finished twice.
+	options, _ := f.beginRegistration.Execute(ctx, input.BeginPasskeyRegistrationInput{UserID: f.user.ID.String()})
+	if len(options.CredentialIDs) != 1 {
+		t.Errorf("registration options should exclude the existing passkey, got %d", len(options.CredentialIDs))
+	}
+	reg, _ := authenticator.Register(options.Challenge, options.UserHandle, softauthn.FormatPacked)
+	finish := input.FinishPasskeyRegistrationInput{
+		UserID:            f.user.ID.String(),
+		SessionID:         options.SessionID,
+		ClientDataJSON:    reg.ClientDataJSON,
+		AttestationObject: reg.AttestationObject,
+	}
+	second, err := f.finishRegistration.Execute(ctx, finish)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if second.Passkey.Name != "Passkey" || len(second.RecoveryCodes) != 0 {
+		t.Errorf("second passkey = %+v, want default name and no new recovery codes", second)
+	}
+	if _, err := f.finishRegistration.Execute(ctx, finish); !errors.Is(err, exception.ErrInvalidWebAuthnSession) {
+		t.Errorf("finishing twice expected error %v, got %v", exception.ErrInvalidWebAuthnSession, err)
+	}
+
+	list, _ := f.listPasskeys.Execute(ctx, input.ListPasskeysInput{UserID: f.user.ID.String()})
+	if len(list.Passkeys) != 2 {
+		t.Errorf("expected 2 passkeys, got %d", len(list.Passkeys))
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserPasskeyAdded, event.UserPasskeyAdded)
+}
+
+func TestPasskeyRegistration_RejectsWrongOrigin(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, "https://phishing.example")
+
+	options, _ := f.beginRegistration.Execute(ctx, input.BeginPasskeyRegistrationInput{UserID: f.user.ID.String()})
+	reg, _ := authenticator.Register(options.Challenge, options.UserHandle, softauthn.FormatNone)
+
+	_, err := f.finishRegistration.Execute(ctx, input.FinishPasskeyRegistrationInput{
+		UserID:            f.user.ID.String(),
+		SessionID:         options.SessionID,
+		ClientDataJSON:    reg.ClientDataJSON,
+		AttestationObject: reg.AttestationObject,
+	})
+	if !errors.Is(err, exception.ErrInvalidPasskey) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasskey, err)
+	}
+	if len(f.outbox.eventTypes()) != 0 {
+		t.Error("no event should be published for a rejected registration")
+	}
+}
+
+func TestPasskeyLogin_Passwordless(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+	f.registerPasskey(t, authenticator)
+	credentialID := f.storedCredentialID(t)
+
+	// Without an identifier the browser picks a discoverable passkey.
+	options, err := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin.Execute() unexpected error: %v", err)
+	}
+	if len(options.CredentialIDs) != 0 {
+		t.Errorf("discoverable login should not list credentials, got %d", len(options.CredentialIDs))
+	}
+
+	out, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+		Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin.Execute() unexpected error: %v", err)
+	}
+	if out.UserID != f.user.ID.String() || out.AccessToken == "" || out.MFARequired {
+		t.Errorf("Execute() = %+v, want a session for the user", out)
+	}
+
+	// With an identifier only that user's passkeys are offered.
+	options, _ = f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{Identifier: "testuser"})
+	if len(options.CredentialIDs) != 1 {
+		t.Errorf("expected one allowed credential, got %d", len(options.CredentialIDs))
+	}
+	if _, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+		Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+	}); err != nil {
+		t.Fatalf("FinishPasskeyLogin.Execute() unexpected error: %v", err)
+	}
+
+	stored, _ := f.passkeyRepo.FindByCredentialID(ctx, credentialID)
+	if stored.SignCount != 3 || stored.LastUsedAt == nil {
+		t.Errorf("stored passkey = %+v, want sign count 3 and a last use", stored)
+	}
+	if !slices.Contains(f.audit.actions(), entity.AuditActionUserLogin) {
+		t.Error("expected a login audit entry")
+	}
+}
+
+func TestPasskeyLogin_Rejects(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+	f.registerPasskey(t, authenticator)
+	credentialID := f.storedCredentialID(t)
+
+	t.Run("user not verified", func(t *testing.T) {
+		authenticator.UserVerified = false
+		defer func() { authenticator.UserVerified = true }()
+
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+			Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+		})
+		if !errors.Is(err, exception.ErrInvalidPasskey) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasskey, err)
+		}
+	})
+
+	t.Run("replayed assertion", func(t *testing.T) {
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		assertion := passkeyAssertion(t, authenticator, options, credentialID)
+		if _, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{Assertion: *assertion}); err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{Assertion: *assertion})
+		if !errors.Is(err, exception.ErrInvalidPasskey) {
+			t.Errorf("replay expected error %v, got %v", exception.ErrInvalidPasskey, err)
+		}
+	})
+
+	t.Run("sign count went backwards", func(t *testing.T) {
+		// A cloned authenticator keeps signing from an older counter.
+		authenticator.SetSignCount(credentialID, 0)
+
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+			Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+		})
+		if !errors.Is(err, exception.ErrInvalidPasskey) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasskey, err)
+		}
+	})
+
+	t.Run("user handle of another user", func(t *testing.T) {
+		authenticator.SetSignCount(credentialID, 100)
+
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		assertion := passkeyAssertion(t, authenticator, options, credentialID)
+		assertion.UserHandle = []byte("someone-else")
+
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{Assertion: *assertion})
+		if !errors.Is(err, exception.ErrInvalidPasskey) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasskey, err)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		f.user.IsActive = false
+		defer func() { f.user.IsActive = true }()
+
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+			Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+		})
+		if err != exception.ErrUserInactive {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrUserInactive, err)
+		}
+	})
+
+	t.Run("locked user", func(t *testing.T) {
+		until := time.Now().Add(time.Hour)
+		f.user.LockedUntil = &until
+		defer func() { f.user.LockedUntil = nil }()
+
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		_, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+			Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+		})
+		if err != exception.ErrInvalidCredentials {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+		}
+	})
+}
+
+func TestPasskeyLogin_NonCountingAuthenticator(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+	authenticator.Counting = false
+	f.registerPasskey(t, authenticator)
+	credentialID := f.storedCredentialID(t)
+
+	// Synced passkeys always report zero, which must keep working.
+	for i := 0; i < 2; i++ {
+		options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+		if _, err := f.finishPasskeyLogin.Execute(ctx, input.FinishPasskeyLoginInput{
+			Assertion: *passkeyAssertion(t, authenticator, options, credentialID),
+		}); err != nil {
+			t.Fatalf("login %d: Execute() unexpected error: %v", i+1, err)
+		}
+	}
+}
+
+func TestPasskeyAsSecondFactor(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+	f.registerPasskey(t, authenticator)
+	credentialID := f.storedCredentialID(t)
+
+	login := f.passwordStep(t)
+	if !login.MFARequired {
+		t.Fatal("a registered passkey should require a second factor after the password")
+	}
+	if !slices.Equal(login.MFAMethods, []string{port.MFAMethodPasskey, port.MFAMethodRecoveryCode}) {
+		t.Errorf("MFAMethods = %v, want passkey and recovery_code", login.MFAMethods)
+	}
+
+	options, err := f.beginMFAPasskey.Execute(ctx, input.BeginMFAPasskeyInput{MFAToken: login.MFAToken})
+	if err != nil {
+		t.Fatalf("BeginMFAPasskey.Execute() unexpected error: %v", err)
+	}
+
+	// Presence is enough for a second factor.
+	authenticator.UserVerified = false
+	out, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{
+		MFAToken: login.MFAToken,
+		Passkey:  passkeyAssertion(t, authenticator, options, credentialID),
+	})
+	if err != nil {
+		t.Fatalf("VerifyMFAChallenge.Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" {
+		t.Error("Execute() should return a session")
+	}
+
+	if _, err := f.beginMFAPasskey.Execute(ctx, input.BeginMFAPasskeyInput{MFAToken: "unknown"}); err != exception.ErrInvalidMFAChallenge {
+		t.Errorf("unknown MFA token expected error %v, got %v", exception.ErrInvalidMFAChallenge, err)
+	}
+}
+
+func TestPasskeyAsSecondFactor_RejectsOtherUsersPasskey(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	authenticator := softauthn.New(passkeyRPID, passkeyOrigin)
+	f.registerPasskey(t, authenticator)
+	credentialID := f.storedCredentialID(t)
+
+	login := f.passwordStep(t)
+	// A passwordless ceremony is not bound to this challenge's user.
+	options, _ := f.beginPasskeyLogin.Execute(ctx, input.BeginPasskeyLoginInput{})
+	_, err := f.challenge.Execute(ctx, input.VerifyMFAChallengeInput{
+		MFAToken: login.MFAToken,
+		Passkey:  passkeyAssertion(t, authenticator, options, credentialID),
+	})
+	if !errors.Is(err, exception.ErrInvalidWebAuthnSession) {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidWebAuthnSession, err)
+	}
+
+	for _, challenge := range f.challengeRepo.challenges {
+		if challenge.Attempts != 1 {
+			t.Errorf("Attempts = %d, want 1", challenge.Attempts)
+		}
+	}
+}
+
+func TestDeletePasskey(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	out := f.registerPasskey(t, softauthn.New(passkeyRPID, passkeyOrigin))
+
+	_, err := f.deletePasskey.Execute(ctx, input.DeletePasskeyInput{UserID: f.user.ID.String(), PasskeyID: out.Passkey.ID, Password: "wrong"})
+	if err != exception.ErrInvalidCredentials {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+
+	_, err = f.deletePasskey.Execute(ctx, input.DeletePasskeyInput{UserID: f.user.ID.String(), PasskeyID: "missing", Password: "secret123"})
+	if err != exception.ErrPasskeyNotFound {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrPasskeyNotFound, err)
+	}
+
+	if _, err := f.deletePasskey.Execute(ctx, input.DeletePasskeyInput{UserID: f.user.ID.String(), PasskeyID: out.Passkey.ID, Password: "secret123"}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if login := f.passwordStep(t); login.MFARequired {
+		t.Error("login should not require MFA once the last passkey is removed")
+	}
+	if n, _ := f.recoveryRepo.CountUnused(ctx, f.user.ID.String()); n != 0 {
+		t.Errorf("recovery codes should be deleted with the last factor, %d left", n)
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserPasskeyAdded, event.UserPasskeyRemoved)
+}
+
+func TestDisableTOTP_KeepsRecoveryCodesForPasskeys(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	secret, _ := f.enroll(t)
+	f.registerPasskey(t, softauthn.New(passkeyRPID, passkeyOrigin))
+
+	_, err := f.disable.Execute(ctx, input.DisableTOTPInput{UserID: f.user.ID.String(), Password: "secret123", Code: totpCode(t, secret, 1)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if n, _ := f.recoveryRepo.CountUnused(ctx, f.user.ID.String()); n == 0 {
+		t.Error("recovery codes should remain while a passkey is registered")
+	}
+	if login := f.passwordStep(t); !login.MFARequired {
+		t.Error("the passkey should still be required after disabling TOTP")
+	}
+}
@@ -0,0 +1,240 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/subscriber"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+type passwordResetFixture struct {
+	forgot      port.ForgotPasswordUseCase
+	reset       port.ResetPasswordUseCase
+	user        *entity.User
+	userRepo    *fakeUserRepo
+	resetRepo   *fakePasswordResetTokenRepo
+	refreshRepo *fakeRefreshTokenRepo
+	revocations *fakeRevocationList
+	mailer      *fakeMailer
+	outbox      *fakeOutbox
+	// requests records the reset requests forgot publishes, apart from
+	// the events of resets.
+	requests *fakeOutbox
+	mail     *subscriber.PasswordResetMailSubscriber
+	metrics  *fakeMetrics
+}
+
+func newPasswordResetFixture(t *testing.T) *passwordResetFixture {
+	t.Helper()
+
+	f := &passwordResetFixture{
+		user:        createUser(t, "old-password"),
+		resetRepo:   newFakePasswordResetTokenRepo(),
+		refreshRepo: newFakeRefreshTokenRepo(),
+		revocations: newFakeRevocationList(),
+		mailer:      &fakeMailer{},
+		outbox:      &fakeOutbox{},
+		requests:    &fakeOutbox{},
+		metrics:     newFakeMetrics(),
+	}
+	f.userRepo = newFakeUserRepo(f.user)
+
+	opaque := token.NewOpaqueGenerator()
+	uuidGenerator := &sequentialUUIDGenerator{}
+	f.forgot = usecase.NewForgotPasswordUsecase(f.userRepo, f.requests, noopLogger{}, uuidGenerator, f.metrics)
+	f.mail = subscriber.NewPasswordResetMailSubscriber(
+		f.userRepo,
+		service.NewPasswordResetService(f.resetRepo, opaque, uuidGenerator, f.mailer, 30*time.Minute, "http://localhost/reset-password"),
+	)
+	f.reset = usecase.NewResetPasswordUsecase(
+		f.userRepo,
+		f.resetRepo,
+		f.refreshRepo,
+		f.revocations,
+		&fakeTxManager{},
+		f.outbox,
+		noopLogger{},
+		opaque,
+		&fakeHasher{},
+		newPasswordValidator(&fakeBreachChecker{breached: map[string]bool{"breached-password": true}}),
+	)
+	return f
+}
+
+// forgotPassword requests a reset link and mails it, the way the outbox
+// dispatcher would.
+func (f *passwordResetFixture) forgotPassword(t *testing.T, email string) *output.ForgotPasswordOutput {
+	t.Helper()
+	out, err := f.forgot.Execute(context.Background(), input.ForgotPasswordInput{Email: email})
+	if err != nil {
+		t.Fatalf("ForgotPassword.Execute() unexpected error: %v", err)
+	}
+	f.requests.deliver(t, f.mail, subscriber.PasswordResetMailEvents...)
+	f.requests.messages = nil
+	return out
+}
+
+func TestForgotPassword_SameResponseForUnknownEmail(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	known := f.forgotPassword(t, "test@example.com")
+	unknown := f.forgotPassword(t, "nobody@example.com")
+
+	if *known != *unknown {
+		t.Errorf("Execute() responses differ: %+v vs %+v", known, unknown)
+	}
+	if len(f.mailer.sent) != 1 {
+		t.Errorf("expected exactly one mail, got %d", len(f.mailer.sent))
+	}
+	if f.metrics.passwordResets != 2 {
+		t.Errorf("expected 2 password reset metrics, got %d", f.metrics.passwordResets)
+	}
+}
+
+// TestForgotPassword_MailsThroughOutbox checks that the request only
+// publishes an event, so answering it takes as long for a known account as
+// for an unknown one.
+func TestForgotPassword_MailsThroughOutbox(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	for _, email := range []string{"test@example.com", "nobody@example.com"} {
+		if _, err := f.forgot.Execute(context.Background(), input.ForgotPasswordInput{Email: email}); err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+	}
+	if len(f.mailer.sent) != 0 {
+		t.Errorf("expected no mail sent during the request, got %d", len(f.mailer.sent))
+	}
+	assertEvents(t, f.requests.eventTypes(), event.UserPasswordResetRequested)
+
+	// An account deactivated before delivery gets no link.
+	f.user.IsActive = false
+	f.requests.deliver(t, f.mail, subscriber.PasswordResetMailEvents...)
+	if len(f.mailer.sent) != 0 || len(f.resetRepo.tokens) != 0 {
+		t.Errorf("expected no link for a deactivated account, got %d mails", len(f.mailer.sent))
+	}
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	if _, err := newSessionService(f.refreshRepo).Issue(context.Background(), f.user, ""); err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+
+	accessToken := &port.AccessTokenClaims{ID: "jti-1", UserID: f.user.ID.String(), IssuedAt: time.Now().UTC()}
+
+	f.forgotPassword(t, "test@example.com")
+
+	_, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{
+		Token:    f.mailer.lastToken(),
+		Password: "new-password",
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if f.user.PasswordHash != "hashed:new-password" {
+		t.Errorf("User.PasswordHash = %q, want new password hash", f.user.PasswordHash)
+	}
+	if f.refreshRepo.activeCount() != 0 {
+		t.Errorf("expected all sessions revoked, %d still active", f.refreshRepo.activeCount())
+	}
+	if !f.revocations.IsRevoked(accessToken) {
+		t.Error("expected access tokens issued before the reset to be revoked")
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserPasswordReset)
+}
+
+func TestResetPassword_TokenIsSingleUse(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	f.forgotPassword(t, "test@example.com")
+	rawToken := f.mailer.lastToken()
+
+	if _, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{Token: rawToken, Password: "new-password"}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	_, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{Token: rawToken, Password: "another-password"})
+	if err != exception.ErrInvalidResetToken {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidResetToken, err)
+	}
+}
+
+func TestResetPassword_NewRequestInvalidatesOldToken(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	f.forgotPassword(t, "test@example.com")
+	first := f.mailer.lastToken()
+	f.forgotPassword(t, "test@example.com")
+
+	_, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{Token: first, Password: "new-password"})
+	if err != exception.ErrInvalidResetToken {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidResetToken, err)
+	}
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	f := newPasswordResetFixture(t)
+
+	f.forgotPassword(t, "test@example.com")
+	for _, stored := range f.resetRepo.tokens {
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	_, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{Token: f.mailer.lastToken(), Password: "new-password"})
+	if err != exception.ErrInvalidResetToken {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidResetToken, err)
+	}
+}
+
+func TestResetPassword_RejectsPolicyViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantCode string
+	}{
+		{name: "too short", password: "short", wantCode: vo.PasswordViolationTooShort},
+		{name: "contains username", password: "my-testuser-pass", wantCode: vo.PasswordViolationContainsPersonalInfo},
+		{name: "breached", password: "breached-password", wantCode: vo.PasswordViolationBreached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordResetFixture(t)
+			f.forgotPassword(t, "test@example.com")
+
+			_, err := f.reset.Execute(context.Background(), input.ResetPasswordInput{
+				Token:    f.mailer.lastToken(),
+				Password: tt.password,
+			})
+
+			var policyErr *exception.PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Execute() expected policy error, got %v", err)
+			}
+			if policyErr.Violations[0].Code != tt.wantCode {
+				t.Errorf("Execute() violation = %q, want %q", policyErr.Violations[0].Code, tt.wantCode)
+			}
+			if f.user.PasswordHash != "hashed:old-password" {
+				t.Error("Execute() should not change the password on a policy violation")
+			}
+			if len(f.outbox.messages) != 0 {
+				t.Error("Execute() should not publish an event on a policy violation")
+			}
+		})
+	}
+}
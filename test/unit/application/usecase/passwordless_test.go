@@ -0,0 +1,420 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/subscriber"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+const passwordlessMaxAttempts = 3
+
+type passwordlessFixture struct {
+	start         port.StartPasswordlessUseCase
+	verify        port.VerifyPasswordlessUseCase
+	register      port.RegisterUseCase
+	login         port.LoginUseCase
+	userRepo      *fakeUserRepo
+	challengeRepo *fakePasswordlessChallengeRepo
+	mailer        *fakeMailer
+	requests      *fakeOutbox
+	mail          *subscriber.PasswordlessMailSubscriber
+	outbox        *fakeOutbox
+	audit         *fakeAuditLogger
+}
+
+func newPasswordlessFixture(t *testing.T, resendInterval time.Duration, mfa port.MFAChallenger, users ...*entity.User) *passwordlessFixture {
+	t.Helper()
+
+	f := &passwordlessFixture{
+		userRepo:      newFakeUserRepo(users...),
+		challengeRepo: newFakePasswordlessChallengeRepo(),
+		mailer:        &fakeMailer{},
+		requests:      &fakeOutbox{},
+		outbox:        &fakeOutbox{},
+		audit:         &fakeAuditLogger{},
+	}
+
+	opaque := token.NewOpaqueGenerator()
+	metrics := newFakeMetrics()
+	signer := token.NewHMACSigner([]byte("passwordless-test-secret-0123456789"))
+	uuidGenerator := &sequentialUUIDGenerator{}
+
+	f.start = usecase.NewStartPasswordlessUsecase(
+		f.userRepo,
+		f.requests,
+		f.audit,
+		noopLogger{},
+		opaque,
+		uuidGenerator,
+		15*time.Minute,
+		10*time.Minute,
+	)
+	f.mail = subscriber.NewPasswordlessMailSubscriber(f.userRepo, service.NewPasswordlessService(
+		f.challengeRepo,
+		opaque,
+		otp.NewEmailCodeGenerator(),
+		signer,
+		uuidGenerator,
+		f.mailer,
+		15*time.Minute,
+		10*time.Minute,
+		resendInterval,
+		"http://localhost/passwordless/verify",
+	))
+	f.verify = usecase.NewVerifyPasswordlessUsecase(
+		f.challengeRepo,
+		f.userRepo,
+		newLoginService(newSessionService(newFakeRefreshTokenRepo()), mfa, f.audit, metrics),
+		&fakeTxManager{},
+		f.outbox,
+		f.audit,
+		metrics,
+		noopLogger{},
+		opaque,
+		signer,
+		passwordlessMaxAttempts,
+	)
+	f.register = usecase.NewRegisterUsecase(f.userRepo, &fakeTxManager{}, f.outbox, noopLogger{}, uuidGenerator, &fakeHasher{}, newPasswordValidator(nil))
+	f.login = usecase.NewLoginUsecase(
+		f.userRepo,
+		f.audit,
+		noopLogger{},
+		&fakeHasher{},
+		nil,
+		nil,
+		newLoginService(newSessionService(newFakeRefreshTokenRepo()), mfa, f.audit, newFakeMetrics()),
+		newFakeMetrics(),
+		cache.NewLoginFailures(time.Minute),
+		false,
+		entity.LockoutPolicy{},
+	)
+	return f
+}
+
+func (f *passwordlessFixture) startSignIn(t *testing.T, method entity.PasswordlessMethod) *output.StartPasswordlessOutput {
+	t.Helper()
+	out, err := f.start.Execute(context.Background(), input.StartPasswordlessInput{
+		Email:  "test@example.com",
+		Method: string(method),
+	})
+	if err != nil {
+		t.Fatalf("StartPasswordless.Execute() unexpected error: %v", err)
+	}
+	f.requests.deliver(t, f.mail, subscriber.PasswordlessMailEvents...)
+	f.requests.messages = nil
+	return out
+}
+
+var signInCodePattern = regexp.MustCompile(`sign-in code is (\d{6})`)
+
+func (f *passwordlessFixture) lastCode(t *testing.T) string {
+	t.Helper()
+	if len(f.mailer.sent) == 0 {
+		t.Fatal("expected a sign-in email")
+	}
+	match := signInCodePattern.FindStringSubmatch(f.mailer.sent[len(f.mailer.sent)-1].TextBody)
+	if match == nil {
+		t.Fatal("sign-in email does not contain a code")
+	}
+	return match[1]
+}
+
+func TestPasswordless_MagicLink(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+	ctx := context.Background()
+
+	started := f.startSignIn(t, entity.PasswordlessMethodLink)
+	if started.DeviceToken == "" || started.Message == "" {
+		t.Fatalf("Execute() = %+v, want a device token and a message", started)
+	}
+	link := f.mailer.lastToken()
+	if link == "" {
+		t.Fatal("expected a magic link")
+	}
+
+	out, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Token: link})
+	if err != nil {
+		t.Fatalf("VerifyPasswordless.Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" || out.MFARequired {
+		t.Errorf("Execute() = %+v, want a session", out)
+	}
+
+	// The email proved the address.
+	user, _ := f.userRepo.FindByID(ctx, out.UserID)
+	if !user.IsEmailVerified {
+		t.Error("signing in by email should verify the address")
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserEmailVerified)
+	assertActions(t, f.audit.actions(), entity.AuditActionPasswordlessRequested, entity.AuditActionUserLogin)
+
+	_, err = f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Token: link})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("reused link expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+}
+
+func TestPasswordless_MagicLinkIsBoundToDevice(t *testing.T) {
+	f := newPasswordlessFixture(t, 0, noMFA{}, createUser(t, "secret123"))
+	ctx := context.Background()
+
+	started := f.startSignIn(t, entity.PasswordlessMethodLink)
+	link := f.mailer.lastToken()
+
+	// Another browser has no device token, or one of its own.
+	_, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{Token: link})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("Execute() without device token expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+	_, err = f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: "other-device", Token: link})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("Execute() from another device expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+
+	// An altered link is rejected and counts as a failed attempt.
+	_, err = f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Token: link + "x"})
+	if err != exception.ErrInvalidPasswordlessCode {
+		t.Errorf("Execute() with altered link expected error %v, got %v", exception.ErrInvalidPasswordlessCode, err)
+	}
+
+	if _, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Token: link}); err != nil {
+		t.Fatalf("Execute() on the starting device unexpected error: %v", err)
+	}
+}
+
+func TestPasswordless_Code(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	code := f.lastCode(t)
+
+	out, err := f.verify.Execute(context.Background(), input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: code})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" {
+		t.Error("Execute() should return a session")
+	}
+}
+
+// TestPasswordless_LockedAccount checks that a locked account is refused
+// like a wrong password, and keeps its challenge for after the lock.
+func TestPasswordless_LockedAccount(t *testing.T) {
+	user := createUser(t, "secret123")
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, user)
+	ctx := context.Background()
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	code := f.lastCode(t)
+
+	until := time.Now().Add(time.Hour)
+	user.LockedUntil = &until
+	_, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: code})
+	if err != exception.ErrInvalidCredentials {
+		t.Fatalf("Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+
+	user.LockedUntil = nil
+	if _, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: code}); err != nil {
+		t.Fatalf("Execute() after the lock unexpected error: %v", err)
+	}
+}
+
+func TestPasswordless_CodeLimitsAttempts(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+	ctx := context.Background()
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	code := f.lastCode(t)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	for i := 0; i < passwordlessMaxAttempts; i++ {
+		_, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: wrong})
+		if err != exception.ErrInvalidPasswordlessCode {
+			t.Fatalf("attempt %d: Execute() expected error %v, got %v", i+1, exception.ErrInvalidPasswordlessCode, err)
+		}
+	}
+
+	_, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: code})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("Execute() after too many attempts expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+}
+
+func TestPasswordless_Expired(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	code := f.lastCode(t)
+	for _, c := range f.challengeRepo.challenges {
+		c.ExpiresAt = time.Now().UTC().Add(-time.Second)
+	}
+
+	_, err := f.verify.Execute(context.Background(), input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: code})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+}
+
+func TestPasswordless_NewRequestReplacesOlder(t *testing.T) {
+	f := newPasswordlessFixture(t, 0, noMFA{}, createUser(t, "secret123"))
+	ctx := context.Background()
+
+	first := f.startSignIn(t, entity.PasswordlessMethodCode)
+	firstCode := f.lastCode(t)
+	second := f.startSignIn(t, entity.PasswordlessMethodCode)
+
+	_, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: first.DeviceToken, Code: firstCode})
+	if err != exception.ErrInvalidPasswordlessChallenge {
+		t.Errorf("Execute() with replaced code expected error %v, got %v", exception.ErrInvalidPasswordlessChallenge, err)
+	}
+	if _, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: second.DeviceToken, Code: f.lastCode(t)}); err != nil {
+		t.Errorf("Execute() with latest code unexpected error: %v", err)
+	}
+}
+
+func TestPasswordless_StartDoesNotRevealAccounts(t *testing.T) {
+	inactive := createUser(t, "secret123")
+	inactive.IsActive = false
+
+	tests := []struct {
+		name  string
+		users []*entity.User
+	}{
+		{"unknown email", nil},
+		{"inactive user", []*entity.User{inactive}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordlessFixture(t, time.Minute, noMFA{}, tt.users...)
+
+			out := f.startSignIn(t, entity.PasswordlessMethodLink)
+			if out.DeviceToken == "" || out.Message == "" {
+				t.Errorf("Execute() = %+v, want the usual response", out)
+			}
+			if len(f.mailer.sent) != 0 {
+				t.Errorf("expected no email, got %d", len(f.mailer.sent))
+			}
+		})
+	}
+}
+
+func TestPasswordless_Throttled(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+
+	f.startSignIn(t, entity.PasswordlessMethodCode)
+	out := f.startSignIn(t, entity.PasswordlessMethodCode)
+
+	if out.DeviceToken == "" {
+		t.Error("a throttled request should still get the usual response")
+	}
+	if len(f.mailer.sent) != 1 {
+		t.Errorf("expected 1 email within the resend interval, got %d", len(f.mailer.sent))
+	}
+}
+
+func TestPasswordless_MailsThroughOutbox(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+	ctx := context.Background()
+
+	started, err := f.start.Execute(ctx, input.StartPasswordlessInput{Email: "test@example.com", Method: string(entity.PasswordlessMethodCode)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(f.mailer.sent) != 0 || len(f.challengeRepo.challenges) != 0 {
+		t.Fatal("the request itself should neither mail nor store a challenge")
+	}
+	assertEvents(t, f.requests.eventTypes(), event.UserPasswordlessRequested)
+
+	// A redelivery of a request that was already mailed sends nothing.
+	f.requests.deliver(t, f.mail, subscriber.PasswordlessMailEvents...)
+	f.requests.deliver(t, f.mail, subscriber.PasswordlessMailEvents...)
+	if len(f.mailer.sent) != 1 {
+		t.Fatalf("expected 1 email after redelivery, got %d", len(f.mailer.sent))
+	}
+
+	if _, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: f.lastCode(t)}); err != nil {
+		t.Errorf("VerifyPasswordless.Execute() unexpected error: %v", err)
+	}
+}
+
+func TestPasswordless_InvalidMethod(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{}, createUser(t, "secret123"))
+
+	_, err := f.start.Execute(context.Background(), input.StartPasswordlessInput{Email: "test@example.com", Method: "sms"})
+	if !errors.Is(err, exception.ErrInvalidPasswordlessMethod) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidPasswordlessMethod, err)
+	}
+}
+
+// stubMFA requires a second factor from every user.
+type stubMFA struct{}
+
+func (stubMFA) BeginChallenge(ctx context.Context, user *entity.User) (*port.MFAChallengeTicket, error) {
+	return &port.MFAChallengeTicket{
+		Token:     "mfa-token",
+		ExpiresAt: time.Now().Add(time.Minute),
+		Methods:   []string{port.MFAMethodTOTP, port.MFAMethodRecoveryCode},
+	}, nil
+}
+
+func TestPasswordless_RequiresSecondFactor(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, stubMFA{}, createUser(t, "secret123"))
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	out, err := f.verify.Execute(context.Background(), input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: f.lastCode(t)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.MFARequired || out.AccessToken != "" {
+		t.Errorf("Execute() = %+v, want an MFA challenge instead of tokens", out)
+	}
+}
+
+func TestPasswordless_AccountWithoutPassword(t *testing.T) {
+	f := newPasswordlessFixture(t, time.Minute, noMFA{})
+	ctx := context.Background()
+
+	registered, err := f.register.Execute(ctx, input.RegisterInput{Username: "testuser", Email: "test@example.com"})
+	if err != nil {
+		t.Fatalf("Register Execute() unexpected error: %v", err)
+	}
+	user, _ := f.userRepo.FindByID(ctx, registered.UserID)
+	if user.HasPassword() {
+		t.Fatal("user registered without a password should have none")
+	}
+
+	// No password, not even an empty one, signs in to the account.
+	_, err = f.login.Execute(ctx, input.LoginInput{Identifier: "testuser", Password: ""})
+	if err != exception.ErrInvalidCredentials {
+		t.Errorf("Login Execute() expected error %v, got %v", exception.ErrInvalidCredentials, err)
+	}
+
+	started := f.startSignIn(t, entity.PasswordlessMethodCode)
+	out, err := f.verify.Execute(ctx, input.VerifyPasswordlessInput{DeviceToken: started.DeviceToken, Code: f.lastCode(t)})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.UserID != registered.UserID {
+		t.Errorf("UserID = %s, want %s", out.UserID, registered.UserID)
+	}
+}
@@ -0,0 +1,209 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+type refreshFixture struct {
+	uc          port.RefreshUseCase
+	sessions    port.SessionIssuer
+	tokens      *recordingTokenService
+	audit       *fakeAuditLogger
+	refreshRepo *fakeRefreshTokenRepo
+	user        *entity.User
+}
+
+func newRefreshFixture(t *testing.T) *refreshFixture {
+	t.Helper()
+
+	f := &refreshFixture{
+		tokens:      &recordingTokenService{},
+		audit:       &fakeAuditLogger{},
+		refreshRepo: newFakeRefreshTokenRepo(),
+		user:        createUser(t, "secret123"),
+	}
+	opaque := token.NewOpaqueGenerator()
+	f.sessions = service.NewSessionService(f.tokens, f.refreshRepo, opaque, &sequentialUUIDGenerator{}, time.Hour)
+	f.uc = usecase.NewRefreshUsecase(
+		newFakeUserRepo(f.user),
+		f.refreshRepo,
+		f.audit,
+		noopLogger{},
+		opaque,
+		f.sessions,
+	)
+	return f
+}
+
+func (f *refreshFixture) login(t *testing.T) *port.Session {
+	t.Helper()
+	session, err := f.sessions.Issue(context.Background(), f.user, "")
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	return session
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	f := newRefreshFixture(t)
+	session := f.login(t)
+
+	out, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	if out.RefreshToken == "" || out.RefreshToken == session.RefreshToken {
+		t.Error("Execute() should return a new refresh token")
+	}
+	if out.AccessToken == "" {
+		t.Error("Execute() should return an access token")
+	}
+	if f.refreshRepo.activeCount() != 1 {
+		t.Errorf("expected exactly one usable refresh token after rotation, got %d", f.refreshRepo.activeCount())
+	}
+
+	// The rotated token continues the chain.
+	if _, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: out.RefreshToken}); err != nil {
+		t.Fatalf("Execute() with rotated token unexpected error: %v", err)
+	}
+}
+
+func TestRefresh_NarrowerScope(t *testing.T) {
+	f := newRefreshFixture(t)
+	session, err := f.sessions.IssueForClient(context.Background(), f.user, port.ClientGrant{
+		ClientID:    "client-1",
+		Scopes:      []string{"profile", "email"},
+		Refreshable: true,
+	})
+	if err != nil {
+		t.Fatalf("IssueForClient() unexpected error: %v", err)
+	}
+
+	out, err := f.uc.Execute(context.Background(), input.RefreshInput{
+		RefreshToken: session.RefreshToken,
+		ClientID:     "client-1",
+		Scopes:       []string{"profile"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if entity.FormatScope(out.Scopes) != "profile" {
+		t.Errorf("Scopes = %v, want the requested scope", out.Scopes)
+	}
+	if claims := f.tokens.issued[len(f.tokens.issued)-1]; entity.FormatScope(claims.Scopes) != "profile" {
+		t.Errorf("access token scopes = %v, want the requested scope", claims.Scopes)
+	}
+
+	// The rotated refresh token keeps the original grant.
+	out, err = f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: out.RefreshToken, ClientID: "client-1"})
+	if err != nil {
+		t.Fatalf("Execute() with rotated token unexpected error: %v", err)
+	}
+	if entity.FormatScope(out.Scopes) != "profile email" {
+		t.Errorf("Scopes = %v, want the original grant", out.Scopes)
+	}
+	if claims := f.tokens.issued[len(f.tokens.issued)-1]; entity.FormatScope(claims.Scopes) != "profile email" {
+		t.Errorf("access token scopes = %v, want the original grant", claims.Scopes)
+	}
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	f := newRefreshFixture(t)
+	session := f.login(t)
+
+	out, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	_, err = f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+	if err != exception.ErrRefreshTokenReused {
+		t.Fatalf("Execute() replay expected error %v, got %v", exception.ErrRefreshTokenReused, err)
+	}
+
+	_, err = f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: out.RefreshToken})
+	if err != exception.ErrInvalidRefreshToken {
+		t.Errorf("Execute() after reuse expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+	}
+
+	assertActions(t, f.audit.actions(), entity.AuditActionRefreshTokenReused)
+}
+
+func TestRefresh_ReuseDoesNotAffectOtherFamilies(t *testing.T) {
+	f := newRefreshFixture(t)
+	first := f.login(t)
+	second := f.login(t)
+
+	if _, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: first.RefreshToken}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	_, _ = f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: first.RefreshToken})
+
+	if _, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: second.RefreshToken}); err != nil {
+		t.Errorf("Execute() for other family unexpected error: %v", err)
+	}
+}
+
+func TestRefresh_InvalidTokens(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		f := newRefreshFixture(t)
+
+		_, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: "unknown"})
+		if err != exception.ErrInvalidRefreshToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		session := f.login(t)
+
+		for _, stored := range f.refreshRepo.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		_, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+		if err != exception.ErrInvalidRefreshToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		session := f.login(t)
+		_ = f.user.Deactivate()
+
+		_, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+		if err != exception.ErrInvalidRefreshToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+		}
+	})
+	t.Run("locked user", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		session := f.login(t)
+		until := time.Now().Add(time.Hour)
+		f.user.LockedUntil = &until
+
+		_, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken})
+		if err != exception.ErrInvalidRefreshToken {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidRefreshToken, err)
+		}
+
+		// The token survives the lock and works once it is lifted.
+		f.user.LockedUntil = nil
+		if _, err := f.uc.Execute(context.Background(), input.RefreshInput{RefreshToken: session.RefreshToken}); err != nil {
+			t.Errorf("Execute() after the lock unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,354 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/output"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/application/service"
+	"github.com/thanhnamdk2710/auth-service/internal/application/usecase"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/saml"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+	"github.com/thanhnamdk2710/auth-service/test/support/samlidp"
+)
+
+const (
+	samlIdPID      = "acme"
+	samlACSURL     = "https://auth.example.com/saml/acme/acs"
+	samlSPEntityID = "https://auth.example.com/saml/acme/metadata"
+)
+
+type samlFixture struct {
+	idp           *samlidp.IdP
+	metadata      port.GetSAMLMetadataUseCase
+	start         port.StartSAMLLoginUseCase
+	consume       port.ConsumeSAMLResponseUseCase
+	redeem        port.RedeemSAMLLoginUseCase
+	userRepo      *fakeUserRepo
+	identityRepo  *fakeIdentityRepo
+	requestRepo   *fakeSAMLRequestRepo
+	loginRepo     *fakeSAMLLoginRepo
+	assertionRepo *fakeSAMLAssertionRepo
+	refreshRepo   *fakeRefreshTokenRepo
+	outbox        *fakeOutbox
+	audit         *fakeAuditLogger
+	metrics       *fakeMetrics
+}
+
+type samlOptions struct {
+	allowIdPInitiated bool
+	linkByEmail       bool
+	mfa               port.MFAChallenger
+}
+
+// newSAMLFixture signs users in through an in-process identity provider
+// with the real SAML service provider.
+func newSAMLFixture(t *testing.T, opts samlOptions, users ...*entity.User) *samlFixture {
+	t.Helper()
+
+	mfa := opts.mfa
+	if mfa == nil {
+		mfa = noMFA{}
+	}
+
+	f := &samlFixture{
+		idp:           samlidp.New(t),
+		userRepo:      newFakeUserRepo(users...),
+		identityRepo:  newFakeIdentityRepo(),
+		requestRepo:   newFakeSAMLRequestRepo(),
+		loginRepo:     newFakeSAMLLoginRepo(),
+		assertionRepo: newFakeSAMLAssertionRepo(),
+		refreshRepo:   newFakeRefreshTokenRepo(),
+		outbox:        &fakeOutbox{},
+		audit:         &fakeAuditLogger{},
+		metrics:       newFakeMetrics(),
+	}
+
+	idps := saml.NewRegistry(saml.NewIdentityProvider(saml.IdentityProviderConfig{
+		ID:                 samlIdPID,
+		Name:               "Acme",
+		EntityID:           f.idp.EntityID,
+		SSOURL:             "https://idp.example.com/sso",
+		Certificates:       []*x509.Certificate{f.idp.Certificate()},
+		UsernameAttributes: []string{"username"},
+		EmailAttributes:    []string{"email"},
+		AllowIdPInitiated:  opts.allowIdPInitiated,
+		LinkByEmail:        opts.linkByEmail,
+		SPEntityID:         samlSPEntityID,
+		ACSURL:             samlACSURL,
+		ClockSkew:          time.Minute,
+	}))
+	opaque := token.NewOpaqueGenerator()
+	uuidGenerator := &sequentialUUIDGenerator{}
+	accounts := service.NewExternalAccountService(f.identityRepo, f.userRepo, &fakeTxManager{}, f.outbox, f.audit, noopLogger{}, uuidGenerator)
+
+	f.metadata = usecase.NewGetSAMLMetadataUsecase(idps)
+	f.start = usecase.NewStartSAMLLoginUsecase(idps, f.requestRepo, noopLogger{}, opaque, 10*time.Minute)
+	f.consume = usecase.NewConsumeSAMLResponseUsecase(idps, f.requestRepo, f.assertionRepo, f.loginRepo, accounts, f.metrics, noopLogger{}, opaque, uuidGenerator, time.Minute)
+	f.redeem = usecase.NewRedeemSAMLLoginUsecase(f.loginRepo, f.userRepo, newLoginService(newSessionService(f.refreshRepo), mfa, f.audit, f.metrics), f.metrics, noopLogger{}, opaque)
+	return f
+}
+
+// respond starts a sign-in and returns the device token along with the
+// response the identity provider posts back once the user has signed in
+// there.
+func (f *samlFixture) respond(t *testing.T) (deviceToken, samlResponse string) {
+	t.Helper()
+	started, err := f.start.Execute(context.Background(), input.StartSAMLLoginInput{IdP: samlIdPID})
+	if err != nil {
+		t.Fatalf("StartSAMLLogin.Execute() unexpected error: %v", err)
+	}
+	request := samlidp.ParseRequest(t, started.SAMLRequest)
+	return started.DeviceToken, f.idp.Respond(samlidp.Response{ACSURL: request.AssertionConsumerServiceURL, Audience: samlSPEntityID, InResponseTo: request.ID})
+}
+
+func (f *samlFixture) signIn(t *testing.T) (*output.LoginOutput, error) {
+	t.Helper()
+	deviceToken, samlResponse := f.respond(t)
+	consumed, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: samlResponse})
+	if err != nil {
+		t.Fatalf("ConsumeSAMLResponse.Execute() unexpected error: %v", err)
+	}
+	return f.redeem.Execute(context.Background(), input.RedeemSAMLLoginInput{Code: consumed.Code, DeviceToken: deviceToken})
+}
+
+func TestSAMLLogin_Metadata(t *testing.T) {
+	f := newSAMLFixture(t, samlOptions{})
+
+	metadata, err := f.metadata.Execute(context.Background(), samlIdPID)
+	if err != nil || len(metadata) == 0 {
+		t.Errorf("Execute() = %d bytes, %v, want the service provider metadata", len(metadata), err)
+	}
+	if _, err := f.metadata.Execute(context.Background(), "unknown"); !errors.Is(err, exception.ErrUnknownIdentityProvider) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUnknownIdentityProvider, err)
+	}
+}
+
+func TestSAMLLogin_ProvisionsUser(t *testing.T) {
+	f := newSAMLFixture(t, samlOptions{})
+
+	out, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.AccessToken == "" || out.RefreshToken == "" || out.MFARequired {
+		t.Fatalf("Execute() = %+v, want a session", out)
+	}
+
+	user, _ := f.userRepo.FindByID(context.Background(), out.UserID)
+	if user == nil || user.Username.String() != "jane.doe" || user.Email.String() != "jane.doe@example.com" || user.HasPassword() {
+		t.Errorf("provisioned %+v, want jane.doe from the assertion attributes with no password", user)
+	}
+	identity, _ := f.identityRepo.FindByProviderSubject(context.Background(), samlIdPID, f.idp.User.NameID)
+	if identity == nil || identity.UserID != out.UserID {
+		t.Errorf("identity = %+v, want the NameID linked to the user", identity)
+	}
+	assertEvents(t, f.outbox.eventTypes(), event.UserRegistered)
+	assertActions(t, f.audit.actions(), entity.AuditActionUserLogin)
+	details := f.audit.details(t, 0)
+	if details["method"] != "saml" || details["provider"] != samlIdPID {
+		t.Errorf("audit details = %v, want a SAML sign-in through %s", details, samlIdPID)
+	}
+
+	second, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if second.UserID != out.UserID || len(f.userRepo.users) != 1 {
+		t.Errorf("signed in as %s, want the account provisioned the first time", second.UserID)
+	}
+}
+
+func TestSAMLLogin_RequiresSecondFactor(t *testing.T) {
+	f := newSAMLFixture(t, samlOptions{mfa: stubMFA{}})
+
+	out, err := f.signIn(t)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.MFARequired || out.AccessToken != "" || f.refreshRepo.activeCount() != 0 {
+		t.Errorf("Execute() = %+v, want an MFA challenge instead of a session", out)
+	}
+}
+
+func TestSAMLLogin_ExistingEmail(t *testing.T) {
+	refused := []struct {
+		name        string
+		linkByEmail bool
+		verified    bool
+	}{
+		{name: "not linked", linkByEmail: false, verified: true},
+		{name: "unverified account", linkByEmail: true, verified: false},
+	}
+	for _, tt := range refused {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSAMLFixture(t, samlOptions{linkByEmail: tt.linkByEmail}, janeDoe(t, tt.verified))
+
+			_, samlResponse := f.respond(t)
+			_, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: samlResponse})
+			if !errors.Is(err, exception.ErrEmailAlreadyExists) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrEmailAlreadyExists, err)
+			}
+			if len(f.identityRepo.identities) != 0 || len(f.userRepo.users) != 1 {
+				t.Error("the existing account should be left alone")
+			}
+		})
+	}
+
+	t.Run("linked by email", func(t *testing.T) {
+		existing := janeDoe(t, true)
+		f := newSAMLFixture(t, samlOptions{linkByEmail: true}, existing)
+
+		out, err := f.signIn(t)
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if out.UserID != existing.ID.String() || len(f.outbox.messages) != 0 {
+			t.Errorf("signed in as %s, want the existing account", out.UserID)
+		}
+		assertActions(t, f.audit.actions(), entity.AuditActionIdentityLinked, entity.AuditActionUserLogin)
+	})
+}
+
+func TestSAMLLogin_IdPInitiated(t *testing.T) {
+	unsolicited := func(f *samlFixture) string {
+		return f.idp.Respond(samlidp.Response{ACSURL: samlACSURL, Audience: samlSPEntityID})
+	}
+
+	t.Run("not allowed", func(t *testing.T) {
+		f := newSAMLFixture(t, samlOptions{})
+
+		_, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: unsolicited(f)})
+		if !errors.Is(err, exception.ErrInvalidSAMLResponse) {
+			t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidSAMLResponse, err)
+		}
+		if len(f.userRepo.users) != 0 {
+			t.Error("no account should be provisioned for an unsolicited response")
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		f := newSAMLFixture(t, samlOptions{allowIdPInitiated: true})
+		samlResponse := unsolicited(f)
+
+		consumed, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: samlResponse})
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		out, err := f.redeem.Execute(context.Background(), input.RedeemSAMLLoginInput{Code: consumed.Code})
+		if err != nil || out.AccessToken == "" {
+			t.Fatalf("Redeem() = %+v, %v, want a session without a device token", out, err)
+		}
+
+		_, err = f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: samlResponse})
+		if !errors.Is(err, exception.ErrInvalidSAMLResponse) {
+			t.Errorf("replayed Execute() expected error %v, got %v", exception.ErrInvalidSAMLResponse, err)
+		}
+	})
+}
+
+func TestSAMLLogin_InvalidResponses(t *testing.T) {
+	f := newSAMLFixture(t, samlOptions{allowIdPInitiated: true})
+
+	_, replayed := f.respond(t)
+	if _, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: replayed}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	_, expired := f.respond(t)
+	for _, request := range f.requestRepo.requests {
+		if request.UsedAt == nil {
+			request.ExpiresAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		samlResponse string
+	}{
+		{name: "replayed", samlResponse: replayed},
+		{name: "expired request", samlResponse: expired},
+		{name: "unknown request", samlResponse: f.idp.Respond(samlidp.Response{ACSURL: samlACSURL, Audience: samlSPEntityID, InResponseTo: "_unknown"})},
+		{name: "other identity provider", samlResponse: samlidp.New(t).Respond(samlidp.Response{ACSURL: samlACSURL, Audience: samlSPEntityID})},
+		{name: "garbage", samlResponse: "not a SAML response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: tt.samlResponse})
+			if !errors.Is(err, exception.ErrInvalidSAMLResponse) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidSAMLResponse, err)
+			}
+		})
+	}
+	if f.metrics.loginAttempts[port.LoginStatusInvalidCredentials] != len(tests) {
+		t.Errorf("login attempts = %v, want %d failures", f.metrics.loginAttempts, len(tests))
+	}
+
+	_, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: "unknown", SAMLResponse: replayed})
+	if !errors.Is(err, exception.ErrUnknownIdentityProvider) {
+		t.Errorf("Execute() expected error %v, got %v", exception.ErrUnknownIdentityProvider, err)
+	}
+}
+
+func TestSAMLLogin_InvalidRedeem(t *testing.T) {
+	f := newSAMLFixture(t, samlOptions{})
+
+	consume := func() input.RedeemSAMLLoginInput {
+		deviceToken, samlResponse := f.respond(t)
+		consumed, err := f.consume.Execute(context.Background(), input.ConsumeSAMLResponseInput{IdP: samlIdPID, SAMLResponse: samlResponse})
+		if err != nil {
+			t.Fatalf("ConsumeSAMLResponse.Execute() unexpected error: %v", err)
+		}
+		return input.RedeemSAMLLoginInput{Code: consumed.Code, DeviceToken: deviceToken}
+	}
+
+	redeemed := consume()
+	if _, err := f.redeem.Execute(context.Background(), redeemed); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	otherDevice := consume()
+	otherDevice.DeviceToken = "someone-elses-device"
+
+	noDevice := consume()
+	noDevice.DeviceToken = ""
+
+	expired := consume()
+	for _, login := range f.loginRepo.logins {
+		if login.UsedAt == nil {
+			login.ExpiresAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		redeem input.RedeemSAMLLoginInput
+	}{
+		{name: "redeemed", redeem: redeemed},
+		{name: "other device", redeem: otherDevice},
+		{name: "no device token", redeem: noDevice},
+		{name: "expired", redeem: expired},
+		{name: "unknown code", redeem: input.RedeemSAMLLoginInput{Code: "unknown", DeviceToken: redeemed.DeviceToken}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.redeem.Execute(context.Background(), tt.redeem)
+			if !errors.Is(err, exception.ErrInvalidSAMLLogin) {
+				t.Errorf("Execute() expected error %v, got %v", exception.ErrInvalidSAMLLogin, err)
+			}
+		})
+	}
+	if f.refreshRepo.activeCount() != 1 {
+		t.Errorf("sessions = %d, want only the one from the valid redeem", f.refreshRepo.activeCount())
+	}
+}
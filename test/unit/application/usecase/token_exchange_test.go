@@ -0,0 +1,254 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+const (
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	ordersAudience       = "orders-api"
+)
+
+// allowTokenExchange lets the confidential client, standing in for an API
+// gateway, exchange tokens for the orders and billing APIs.
+func (f *oauthFixture) allowTokenExchange() {
+	client := f.clientRepo.clients[oauthConfidentialClientID]
+	client.GrantTypes = append(client.GrantTypes, entity.OAuthGrantTokenExchange)
+	client.ExchangeAudiences = []string{ordersAudience, "billing-api"}
+}
+
+// userAccessToken issues the user an access token for the public client,
+// as the gateway would receive it.
+func (f *oauthFixture) userAccessToken(t *testing.T, ttl time.Duration) string {
+	t.Helper()
+	token, _, err := f.tokens.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:   f.user.ID.String(),
+		Username: f.user.Username.String(),
+		ClientID: oauthPublicClientID,
+		Scopes:   []string{"openid", "profile", "email"},
+		TTL:      ttl,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+	return token
+}
+
+func tokenExchange(subjectToken string, audiences ...string) input.OAuthTokenInput {
+	return input.OAuthTokenInput{
+		GrantType:        entity.OAuthGrantTokenExchange,
+		ClientID:         oauthConfidentialClientID,
+		ClientSecret:     oauthClientSecret,
+		SubjectToken:     subjectToken,
+		SubjectTokenType: tokenTypeAccessToken,
+		Audiences:        audiences,
+	}
+}
+
+// lastIssued returns the claims of the token issued last.
+func (f *oauthFixture) lastIssued() port.AccessTokenClaims {
+	return f.tokens.issued[len(f.tokens.issued)-1]
+}
+
+func TestTokenExchange_Impersonation(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	subject := f.userAccessToken(t, time.Hour)
+
+	req := tokenExchange(subject, ordersAudience)
+	req.Scope = "profile"
+	out, err := f.tokenUC.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.IssuedTokenType != tokenTypeAccessToken || out.TokenType != "Bearer" || out.RefreshToken != "" {
+		t.Errorf("Execute() = %+v, want a bearer access token without a refresh token", out)
+	}
+
+	claims := f.lastIssued()
+	if claims.UserID != f.user.ID.String() || claims.ClientID != oauthConfidentialClientID || claims.Actor != nil {
+		t.Errorf("issued claims = %+v, want the user's token for the gateway with no actor", claims)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != ordersAudience || entity.FormatScope(claims.Scopes) != "profile" {
+		t.Errorf("issued claims = %+v, want the orders audience and the profile scope", claims)
+	}
+	if _, err := f.tokens.ParseAccessToken(out.AccessToken); err == nil {
+		t.Error("a token for another audience should not be accepted by this service's API")
+	}
+
+	assertActions(t, f.audit.actions(), entity.AuditActionOAuthTokenExchanged)
+	logged := f.audit.logs[0]
+	details := f.audit.details(t, 0)
+	if logged.UserID == nil || *logged.UserID != f.user.ID.String() ||
+		details["client_id"] != oauthConfidentialClientID || details["subject_client_id"] != oauthPublicClientID || details["subject_jti"] != "jti-1" {
+		t.Errorf("audit log = %+v (%v), want the exchange for the user", logged, details)
+	}
+	if _, ok := details["act"]; ok {
+		t.Errorf("audit details = %v, want no actor for impersonation", details)
+	}
+}
+
+func TestTokenExchange_DefaultsToSubjectScopes(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	f.clientRepo.clients[oauthConfidentialClientID].Scopes = []string{"profile", "email"}
+
+	out, err := f.tokenUC.Execute(context.Background(), tokenExchange(f.userAccessToken(t, time.Hour), ordersAudience, "billing-api", ordersAudience))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if entity.FormatScope(out.Scopes) != "profile email" {
+		t.Errorf("Scopes = %v, want the subject's scopes the gateway may hold", out.Scopes)
+	}
+	if claims := f.lastIssued(); strings.Join(claims.Audience, " ") != ordersAudience+" billing-api" {
+		t.Errorf("Audience = %v, want each requested audience once", claims.Audience)
+	}
+}
+
+func TestTokenExchange_NeverOutlivesSubject(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	subject := f.userAccessToken(t, time.Minute)
+	subjectExpiry := f.lastIssued().ExpiresAt
+
+	out, err := f.tokenUC.Execute(context.Background(), tokenExchange(subject, ordersAudience))
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.ExpiresAt.After(subjectExpiry) {
+		t.Errorf("ExpiresAt = %v, want no later than the subject token's %v", out.ExpiresAt, subjectExpiry)
+	}
+}
+
+func TestTokenExchange_DelegationChain(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	subject := f.userAccessToken(t, time.Hour)
+	actorToken := f.serviceToken(t)
+
+	req := tokenExchange(subject, ordersAudience)
+	req.ActorToken = actorToken
+	req.ActorTokenType = "urn:ietf:params:oauth:token-type:jwt"
+	first, err := f.tokenUC.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	actor := f.lastIssued().Actor
+	if actor == nil || actor.Subject != oauthConfidentialClientID || actor.ClientID != oauthConfidentialClientID || actor.Actor != nil {
+		t.Fatalf("Actor = %+v, want the gateway", actor)
+	}
+
+	// The orders API passes the token it received on to billing.
+	req = tokenExchange(first.AccessToken, "billing-api")
+	req.ActorToken = actorToken
+	req.ActorTokenType = tokenTypeAccessToken
+	if _, err := f.tokenUC.Execute(context.Background(), req); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	chained := f.lastIssued()
+	if chained.Actor.Depth() != 2 || chained.UserID != f.user.ID.String() {
+		t.Errorf("issued claims = %+v, want the user with two actors", chained)
+	}
+
+	out, err := f.introspectUC.Execute(context.Background(), input.IntrospectTokenInput{Client: confidentialClient, Token: "access-token-" + chained.ID})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.Active || out.Actor.Depth() != 2 || len(out.Audience) != 1 || out.Audience[0] != "billing-api" {
+		t.Errorf("Execute() = %+v, want the active token with its audience and actors", out)
+	}
+
+	details := f.audit.details(t, len(f.audit.logs)-1)
+	if act, ok := details["act"].([]interface{}); !ok || len(act) != 2 {
+		t.Errorf("audit details = %v, want both actors", details)
+	}
+}
+
+func TestTokenExchange_LimitsChainDepth(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	token := f.userAccessToken(t, time.Hour)
+	actorToken := f.serviceToken(t)
+
+	for i := 0; i < 6; i++ {
+		req := tokenExchange(token, ordersAudience)
+		req.ActorToken = actorToken
+		req.ActorTokenType = tokenTypeAccessToken
+		out, err := f.tokenUC.Execute(context.Background(), req)
+		if i == 5 {
+			assertOAuthError(t, err, exception.OAuthInvalidGrant)
+			return
+		}
+		if err != nil {
+			t.Fatalf("Execute() unexpected error at depth %d: %v", i+1, err)
+		}
+		token = out.AccessToken
+	}
+}
+
+func TestTokenExchange_Rejections(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	public := f.clientRepo.clients[oauthPublicClientID]
+	public.GrantTypes = append(public.GrantTypes, entity.OAuthGrantTokenExchange)
+	public.ExchangeAudiences = []string{ordersAudience}
+
+	subject := f.userAccessToken(t, time.Hour)
+	revoked := f.userAccessToken(t, time.Hour)
+	if err := f.revocations.Revoke(context.Background(), f.lastIssued().ID, f.lastIssued().ExpiresAt); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	serviceToken := f.serviceToken(t)
+	othersToken, _, _ := f.tokens.GenerateAccessToken(port.AccessTokenClaims{ClientID: oauthPublicClientID})
+
+	tests := []struct {
+		name   string
+		modify func(*input.OAuthTokenInput)
+		code   string
+	}{
+		{"public client", func(r *input.OAuthTokenInput) { r.ClientID, r.ClientSecret = oauthPublicClientID, "" }, exception.OAuthUnauthorizedClient},
+		{"no subject token", func(r *input.OAuthTokenInput) { r.SubjectToken = "" }, exception.OAuthInvalidRequest},
+		{"refresh token subject type", func(r *input.OAuthTokenInput) { r.SubjectTokenType = "urn:ietf:params:oauth:token-type:refresh_token" }, exception.OAuthInvalidRequest},
+		{"ID token requested", func(r *input.OAuthTokenInput) { r.RequestedTokenType = "urn:ietf:params:oauth:token-type:id_token" }, exception.OAuthInvalidRequest},
+		{"actor type without actor", func(r *input.OAuthTokenInput) { r.ActorTokenType = tokenTypeAccessToken }, exception.OAuthInvalidRequest},
+		{"no audience", func(r *input.OAuthTokenInput) { r.Audiences = nil }, exception.OAuthInvalidTarget},
+		{"audience not allowed", func(r *input.OAuthTokenInput) { r.Audiences = []string{ordersAudience, "admin-api"} }, exception.OAuthInvalidTarget},
+		{"unknown subject", func(r *input.OAuthTokenInput) { r.SubjectToken = "not-a-token" }, exception.OAuthInvalidGrant},
+		{"revoked subject", func(r *input.OAuthTokenInput) { r.SubjectToken = revoked }, exception.OAuthInvalidGrant},
+		{"subject without a user", func(r *input.OAuthTokenInput) { r.SubjectToken = serviceToken }, exception.OAuthInvalidGrant},
+		{"another client's actor", func(r *input.OAuthTokenInput) {
+			r.ActorToken, r.ActorTokenType = othersToken, tokenTypeAccessToken
+		}, exception.OAuthInvalidGrant},
+		{"scope beyond the subject", func(r *input.OAuthTokenInput) { r.Scope = "profile admin" }, exception.OAuthInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tokenExchange(subject, ordersAudience)
+			tt.modify(&req)
+			_, err := f.tokenUC.Execute(context.Background(), req)
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+
+	if actions := f.audit.actions(); len(actions) != 1 || actions[0] != entity.AuditActionOAuthTokenIssued {
+		t.Errorf("audit actions = %v, want only the service token", actions)
+	}
+}
+
+func TestTokenExchange_InactiveUser(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.allowTokenExchange()
+	subject := f.userAccessToken(t, time.Hour)
+	f.user.IsActive = false
+
+	_, err := f.tokenUC.Execute(context.Background(), tokenExchange(subject, ordersAudience))
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+}
@@ -0,0 +1,214 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/input"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+)
+
+var confidentialClient = input.ClientCredentials{ClientID: oauthConfidentialClientID, ClientSecret: oauthClientSecret}
+
+// serviceToken issues an access token to the confidential client through
+// the client_credentials grant.
+func (f *oauthFixture) serviceToken(t *testing.T) string {
+	t.Helper()
+	client := f.clientRepo.clients[oauthConfidentialClientID]
+	client.GrantTypes = append(client.GrantTypes, entity.OAuthGrantClientCredentials)
+
+	out, err := f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantClientCredentials,
+		ClientID:     oauthConfidentialClientID,
+		ClientSecret: oauthClientSecret,
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out.AccessToken
+}
+
+// userRefreshToken runs the authorization code flow for clientID and
+// returns the refresh token it issues.
+func (f *oauthFixture) userRefreshToken(t *testing.T, clientID, secret string) string {
+	t.Helper()
+	req := codeExchange(clientID, f.authorize(t, clientID).Code)
+	req.ClientSecret = secret
+	out, err := f.tokenUC.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	return out.RefreshToken
+}
+
+func TestIntrospectToken_AccessToken(t *testing.T) {
+	f := newOAuthFixture(t)
+	accessToken := f.serviceToken(t)
+
+	out, err := f.introspectUC.Execute(context.Background(), input.IntrospectTokenInput{Client: confidentialClient, Token: accessToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !out.Active || out.TokenType != "Bearer" || out.ClientID != oauthConfidentialClientID || out.TokenID != "jti-1" {
+		t.Errorf("Execute() = %+v, want the active client token", out)
+	}
+	if out.UserID != "" || entity.FormatScope(out.Scopes) != "profile email" || out.ExpiresAt.IsZero() {
+		t.Errorf("Execute() = %+v, want the token's claims", out)
+	}
+}
+
+func TestIntrospectToken_RefreshToken(t *testing.T) {
+	f := newOAuthFixture(t)
+	refreshToken := f.userRefreshToken(t, oauthConfidentialClientID, oauthClientSecret)
+
+	for _, hint := range []string{"", "refresh_token", "access_token"} {
+		t.Run("hint "+hint, func(t *testing.T) {
+			out, err := f.introspectUC.Execute(context.Background(), input.IntrospectTokenInput{
+				Client:        confidentialClient,
+				Token:         refreshToken,
+				TokenTypeHint: hint,
+			})
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if !out.Active || out.TokenType != "" || out.UserID != f.user.ID.String() || out.ClientID != oauthConfidentialClientID {
+				t.Errorf("Execute() = %+v, want the active refresh token", out)
+			}
+		})
+	}
+}
+
+func TestIntrospectToken_Inactive(t *testing.T) {
+	f := newOAuthFixture(t)
+	revoked := f.serviceToken(t)
+	if err := f.revocations.Revoke(context.Background(), "jti-1", f.tokens.issued[0].ExpiresAt); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	othersRefresh := f.userRefreshToken(t, oauthPublicClientID, "")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown token", "not-a-token"},
+		{"revoked access token", revoked},
+		{"another client's refresh token", othersRefresh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := f.introspectUC.Execute(context.Background(), input.IntrospectTokenInput{Client: confidentialClient, Token: tt.token})
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if out.Active || out.ClientID != "" {
+				t.Errorf("Execute() = %+v, want an inactive token and nothing more", out)
+			}
+		})
+	}
+}
+
+func TestIntrospectToken_Rejections(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	tests := []struct {
+		name  string
+		input input.IntrospectTokenInput
+		code  string
+	}{
+		{"public client", input.IntrospectTokenInput{Client: input.ClientCredentials{ClientID: oauthPublicClientID}, Token: "t"}, exception.OAuthUnauthorizedClient},
+		{"wrong secret", input.IntrospectTokenInput{Client: input.ClientCredentials{ClientID: oauthConfidentialClientID, ClientSecret: "nope"}, Token: "t"}, exception.OAuthInvalidClient},
+		{"no token", input.IntrospectTokenInput{Client: confidentialClient}, exception.OAuthInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.introspectUC.Execute(context.Background(), tt.input)
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestRevokeToken_AccessToken(t *testing.T) {
+	f := newOAuthFixture(t)
+	accessToken := f.serviceToken(t)
+
+	if err := f.revokeUC.Execute(context.Background(), input.RevokeTokenInput{Client: confidentialClient, Token: accessToken}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !f.revocations.IsRevoked(&port.AccessTokenClaims{ID: "jti-1"}) {
+		t.Error("the access token should be revoked")
+	}
+
+	out, err := f.introspectUC.Execute(context.Background(), input.IntrospectTokenInput{Client: confidentialClient, Token: accessToken})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.Active {
+		t.Error("a revoked access token should introspect as inactive")
+	}
+
+	last := f.audit.logs[len(f.audit.logs)-1]
+	if last.Action != entity.AuditActionOAuthTokenRevoked || last.UserID != nil {
+		t.Errorf("audit log = %+v, want the revocation without a user", last)
+	}
+}
+
+func TestRevokeToken_RefreshToken(t *testing.T) {
+	f := newOAuthFixture(t)
+	refreshToken := f.userRefreshToken(t, oauthPublicClientID, "")
+
+	err := f.revokeUC.Execute(context.Background(), input.RevokeTokenInput{
+		Client:        input.ClientCredentials{ClientID: oauthPublicClientID},
+		Token:         refreshToken,
+		TokenTypeHint: "refresh_token",
+	})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if f.refreshRepo.activeCount() != 0 {
+		t.Errorf("the refresh token family should be revoked, %d still active", f.refreshRepo.activeCount())
+	}
+
+	_, err = f.tokenUC.Execute(context.Background(), input.OAuthTokenInput{
+		GrantType:    entity.OAuthGrantRefreshToken,
+		ClientID:     oauthPublicClientID,
+		RefreshToken: refreshToken,
+	})
+	assertOAuthError(t, err, exception.OAuthInvalidGrant)
+
+	last := f.audit.logs[len(f.audit.logs)-1]
+	if last.Action != entity.AuditActionOAuthTokenRevoked || last.UserID == nil || *last.UserID != f.user.ID.String() {
+		t.Errorf("audit log = %+v, want the revocation for the user", last)
+	}
+}
+
+func TestRevokeToken_OtherTokensAreIgnored(t *testing.T) {
+	f := newOAuthFixture(t)
+	accessToken := f.serviceToken(t)
+	refreshToken := f.userRefreshToken(t, oauthConfidentialClientID, oauthClientSecret)
+	public := input.ClientCredentials{ClientID: oauthPublicClientID}
+
+	for _, token := range []string{"not-a-token", accessToken, refreshToken} {
+		if err := f.revokeUC.Execute(context.Background(), input.RevokeTokenInput{Client: public, Token: token}); err != nil {
+			t.Errorf("Execute() unexpected error: %v", err)
+		}
+	}
+
+	if f.revocations.IsRevoked(&port.AccessTokenClaims{ID: "jti-1"}) || f.refreshRepo.activeCount() != 1 {
+		t.Error("a client should not revoke another client's tokens")
+	}
+}
+
+func TestRevokeToken_Rejections(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	err := f.revokeUC.Execute(context.Background(), input.RevokeTokenInput{
+		Client: input.ClientCredentials{ClientID: oauthConfidentialClientID, ClientSecret: "nope"},
+		Token:  "t",
+	})
+	assertOAuthError(t, err, exception.OAuthInvalidClient)
+
+	err = f.revokeUC.Execute(context.Background(), input.RevokeTokenInput{Client: confidentialClient})
+	assertOAuthError(t, err, exception.OAuthInvalidRequest)
+}
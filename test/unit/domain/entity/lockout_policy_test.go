@@ -0,0 +1,80 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+func TestLockoutPolicy_LockDuration(t *testing.T) {
+	policy := entity.LockoutPolicy{
+		MaxAttempts:  5,
+		BaseDuration: 5 * time.Minute,
+		MaxDuration:  30 * time.Minute,
+	}
+
+	tests := []struct {
+		lockout int
+		want    time.Duration
+	}{
+		{lockout: 1, want: 5 * time.Minute},
+		{lockout: 2, want: 10 * time.Minute},
+		{lockout: 3, want: 20 * time.Minute},
+		{lockout: 4, want: 30 * time.Minute},
+		{lockout: 50, want: 30 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := policy.LockDuration(tt.lockout); got != tt.want {
+			t.Errorf("LockDuration(%d) = %v, want %v", tt.lockout, got, tt.want)
+		}
+	}
+}
+
+func TestLockoutPolicy_Delay(t *testing.T) {
+	policy := entity.LockoutPolicy{
+		DelayAfter: 3,
+		DelayBase:  500 * time.Millisecond,
+		DelayMax:   time.Second,
+	}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: 500 * time.Millisecond},
+		{failures: 4, want: time.Second},
+		{failures: 10, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Delay(tt.failures); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	if got := (entity.LockoutPolicy{}).Delay(10); got != 0 {
+		t.Errorf("Delay() with delays disabled = %v, want 0", got)
+	}
+}
+
+func TestUser_IsLocked(t *testing.T) {
+	user := createValidUser(t)
+	now := time.Now()
+
+	if user.IsLocked(now) {
+		t.Error("new user should not be locked")
+	}
+
+	until := now.Add(time.Minute)
+	user.LockedUntil = &until
+	if !user.IsLocked(now) {
+		t.Error("user should be locked before locked_until")
+	}
+	if user.IsLocked(until) {
+		t.Error("user should not be locked once locked_until has passed")
+	}
+}
@@ -0,0 +1,61 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+// The verifier and challenge from RFC 7636 appendix B.
+const (
+	rfcCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestAuthorizationCode_VerifyCodeVerifier(t *testing.T) {
+	code := entity.NewAuthorizationCode("id", "hash", "client", "user", "https://app.example.com/cb", nil, "", rfcCodeChallenge, "family", time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name     string
+		verifier string
+		want     bool
+	}{
+		{"matching verifier", rfcCodeVerifier, true},
+		{"different verifier", strings.Repeat("a", 43), false},
+		{"challenge as verifier", rfcCodeChallenge, false},
+		{"too short", rfcCodeVerifier[:42], false},
+		{"too long", strings.Repeat("a", 129), false},
+		{"invalid character", rfcCodeVerifier[:42] + "+", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := code.VerifyCodeVerifier(tt.verifier); got != tt.want {
+				t.Errorf("VerifyCodeVerifier(%q) = %v, want %v", tt.verifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidCodeChallenge(t *testing.T) {
+	if !entity.IsValidCodeChallenge(rfcCodeChallenge) {
+		t.Error("IsValidCodeChallenge() should accept an S256 challenge")
+	}
+	for _, challenge := range []string{"", rfcCodeChallenge + "=", rfcCodeChallenge[:42], strings.Repeat("/", 43)} {
+		if entity.IsValidCodeChallenge(challenge) {
+			t.Errorf("IsValidCodeChallenge(%q) = true, want false", challenge)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	got := entity.ParseScope("  email profile  email ")
+	if entity.FormatScope(got) != "email profile" {
+		t.Errorf("ParseScope() = %v, want [email profile]", got)
+	}
+	if entity.ParseScope("") != nil {
+		t.Error("ParseScope(\"\") should be empty")
+	}
+}
@@ -0,0 +1,34 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+func TestNormalizeUserCode(t *testing.T) {
+	for _, typed := range []string{"WDJB-MJHT", "wdjb-mjht", "WDJB MJHT", " wdjbmjht "} {
+		if got := entity.NormalizeUserCode(typed); got != "WDJBMJHT" {
+			t.Errorf("NormalizeUserCode(%q) = %q, want %q", typed, got, "WDJBMJHT")
+		}
+	}
+}
+
+func TestDeviceAuthorization_PolledTooSoon(t *testing.T) {
+	now := time.Now()
+	authorization := entity.NewDeviceAuthorization("id", "client", "device", "user", nil, "family", 5*time.Second, now.Add(time.Minute))
+
+	if authorization.PolledTooSoon(now) {
+		t.Error("the first poll should never be too soon")
+	}
+
+	polledAt := now.Add(-3 * time.Second)
+	authorization.LastPolledAt = &polledAt
+	if !authorization.PolledTooSoon(now) {
+		t.Error("a poll within the interval should be too soon")
+	}
+	if authorization.PolledTooSoon(now.Add(2 * time.Second)) {
+		t.Error("a poll once the interval has passed should be allowed")
+	}
+}
@@ -0,0 +1,67 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+)
+
+func TestSigningKeyRing_Lifecycle(t *testing.T) {
+	now := time.Now().UTC()
+	overlap := time.Hour
+
+	expired := entity.NewSigningKey("expired", entity.SigningAlgRS256, nil, nil, now.Add(-72*time.Hour))
+	retired := entity.NewSigningKey("retired", entity.SigningAlgRS256, nil, nil, now.Add(-48*time.Hour))
+	active := entity.NewSigningKey("active", entity.SigningAlgRS256, nil, nil, now.Add(-30*time.Minute))
+	pending := entity.NewSigningKey("pending", entity.SigningAlgRS256, nil, nil, now.Add(time.Minute))
+
+	ring := entity.NewSigningKeyRing([]*entity.SigningKey{pending, active, expired, retired})
+
+	if got := ring.Active(now); got != active {
+		t.Errorf("Active() = %v, want the latest activated key", got.ID)
+	}
+	if got := ring.Latest(); got != pending {
+		t.Errorf("Latest() = %v, want the pending key", got.ID)
+	}
+
+	want := map[*entity.SigningKey]entity.SigningKeyStatus{
+		expired: entity.SigningKeyExpired,
+		// Its successor activated 30 minutes ago, within the overlap.
+		retired: entity.SigningKeyRetired,
+		active:  entity.SigningKeyActive,
+		pending: entity.SigningKeyPending,
+	}
+	for key, status := range want {
+		if got := ring.Status(key, now, overlap); got != status {
+			t.Errorf("Status(%s) = %s, want %s", key.ID, got, status)
+		}
+	}
+
+	if published := ring.Published(now, overlap); len(published) != 3 || published[0] != retired {
+		t.Errorf("Published() should hold the retired, active and pending keys, got %d keys", len(published))
+	}
+	if expiredKeys := ring.Expired(now, overlap); len(expiredKeys) != 1 || expiredKeys[0] != expired {
+		t.Errorf("Expired() = %v, want only the expired key", expiredKeys)
+	}
+}
+
+func TestSigningKeyRing_Empty(t *testing.T) {
+	ring := entity.NewSigningKeyRing(nil)
+	if ring.Active(time.Now()) != nil || ring.Latest() != nil {
+		t.Error("an empty ring has no active or latest key")
+	}
+}
+
+func TestSigningKeyRing_PendingKeyOnly(t *testing.T) {
+	now := time.Now().UTC()
+	pending := entity.NewSigningKey("pending", entity.SigningAlgES256, nil, nil, now.Add(time.Minute))
+	ring := entity.NewSigningKeyRing([]*entity.SigningKey{pending})
+
+	if ring.Active(now) != nil {
+		t.Error("a key must not sign before it activates")
+	}
+	if ring.Active(now.Add(2*time.Minute)) != pending {
+		t.Error("the key should sign once activated")
+	}
+}
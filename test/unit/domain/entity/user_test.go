@@ -60,6 +60,30 @@ func TestNewUser(t *testing.T) {
 	}
 }
 
+func TestUser_SetPasswordHash(t *testing.T) {
+	t.Run("set valid hash", func(t *testing.T) {
+		user := createValidUser(t)
+
+		err := user.SetPasswordHash("$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA")
+		if err != nil {
+			t.Errorf("SetPasswordHash() unexpected error: %v", err)
+		}
+
+		if user.PasswordHash != "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA" {
+			t.Errorf("User.PasswordHash = %q, want the provided hash", user.PasswordHash)
+		}
+	})
+
+	t.Run("set empty hash", func(t *testing.T) {
+		user := createValidUser(t)
+
+		err := user.SetPasswordHash("")
+		if err != exception.ErrPasswordHashRequired {
+			t.Errorf("SetPasswordHash() expected error %v, got %v", exception.ErrPasswordHashRequired, err)
+		}
+	})
+}
+
 func TestUser_Activate(t *testing.T) {
 	t.Run("activate inactive user", func(t *testing.T) {
 		user := createValidUser(t)
@@ -0,0 +1,182 @@
+package valueobject_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/exception"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/vo"
+)
+
+func violationCodes(t *testing.T, err error) []string {
+	t.Helper()
+
+	var policyErr *exception.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected *exception.PasswordPolicyError, got %v", err)
+	}
+	if !errors.Is(err, exception.ErrPasswordPolicyViolation) {
+		t.Errorf("policy error should match ErrPasswordPolicyViolation")
+	}
+
+	codes := make([]string, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		if v.Field != "password" {
+			t.Errorf("violation field = %q, want %q", v.Field, "password")
+		}
+		codes = append(codes, v.Code)
+	}
+	return codes
+}
+
+func TestNewPassword(t *testing.T) {
+	strict := vo.PasswordPolicy{
+		MinLength:          10,
+		MaxLength:          20,
+		RequireUppercase:   true,
+		RequireLowercase:   true,
+		RequireDigit:       true,
+		RequireSymbol:      true,
+		RejectPersonalInfo: true,
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		policy    vo.PasswordPolicy
+		wantCodes []string
+	}{
+		{
+			name:     "meets every rule",
+			password: "Blue-Kettle-42",
+			policy:   strict,
+		},
+		{
+			name:      "too short",
+			password:  "Ab1-",
+			policy:    strict,
+			wantCodes: []string{vo.PasswordViolationTooShort},
+		},
+		{
+			name:      "too long",
+			password:  "Aa1-" + strings.Repeat("x", 20),
+			policy:    strict,
+			wantCodes: []string{vo.PasswordViolationTooLong},
+		},
+		{
+			name:     "missing every character class",
+			password: "          ",
+			policy:   strict,
+			wantCodes: []string{
+				vo.PasswordViolationMissingUppercase,
+				vo.PasswordViolationMissingLowercase,
+				vo.PasswordViolationMissingDigit,
+			},
+		},
+		{
+			name:      "contains username",
+			password:  "Xx-JohnDoe-99",
+			policy:    strict,
+			wantCodes: []string{vo.PasswordViolationContainsPersonalInfo},
+		},
+		{
+			name:      "contains email local part",
+			password:  "Xx-jdoe.work-9",
+			policy:    strict,
+			wantCodes: []string{vo.PasswordViolationContainsPersonalInfo},
+		},
+		{
+			name:      "common password is too weak",
+			password:  "password123",
+			policy:    vo.DefaultPasswordPolicy(),
+			wantCodes: []string{vo.PasswordViolationTooWeak},
+		},
+		{
+			name:      "keyboard walk is too weak",
+			password:  "qwertyuiop",
+			policy:    vo.DefaultPasswordPolicy(),
+			wantCodes: []string{vo.PasswordViolationTooWeak},
+		},
+		{
+			name:     "long passphrase passes default policy",
+			password: "violet tractor sings quietly",
+			policy:   vo.DefaultPasswordPolicy(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := vo.NewPassword(tt.password, tt.policy, "johndoe", "jdoe.work@example.com")
+
+			if len(tt.wantCodes) == 0 {
+				if err != nil {
+					t.Fatalf("NewPassword() unexpected error: %v", err)
+				}
+				if password.Plaintext() != tt.password {
+					t.Errorf("NewPassword().Plaintext() = %q, want %q", password.Plaintext(), tt.password)
+				}
+				return
+			}
+
+			if got := violationCodes(t, err); !slices.Equal(got, tt.wantCodes) {
+				t.Errorf("NewPassword() violations = %v, want %v", got, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestNewPassword_Empty(t *testing.T) {
+	_, err := vo.NewPassword("", vo.DefaultPasswordPolicy())
+	if err != exception.ErrPasswordRequired {
+		t.Errorf("NewPassword() expected error %v, got %v", exception.ErrPasswordRequired, err)
+	}
+}
+
+func TestPassword_StringIsRedacted(t *testing.T) {
+	password, err := vo.NewPassword("violet tractor sings quietly", vo.DefaultPasswordPolicy())
+	if err != nil {
+		t.Fatalf("NewPassword() unexpected error: %v", err)
+	}
+
+	if strings.Contains(password.String(), "violet") {
+		t.Errorf("Password.String() = %q, should not reveal the password", password.String())
+	}
+}
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		minScore    int
+		maxScore    int
+		wantWarning string
+	}{
+		{name: "top common password", password: "password", maxScore: 0, wantWarning: "This is a top-10 common password"},
+		{name: "l33t common password", password: "p@ssw0rd", maxScore: 0},
+		{name: "reversed common password", password: "drowssap", maxScore: 0},
+		{name: "repeated character", password: "aaaaaaaaaa", maxScore: 0, wantWarning: `Repeats like "aaa" are easy to guess`},
+		{name: "sequence", password: "abcdefghij", maxScore: 0, wantWarning: "Sequences like abc or 6543 are easy to guess"},
+		{name: "keyboard row", password: "zxcvbnm,./", maxScore: 1, wantWarning: "Straight rows of keys are easy to guess"},
+		{name: "username and year", password: "johndoe1990", maxScore: 1, wantWarning: "Avoid using your username or email address"},
+		{name: "random characters", password: "xK9#mQ2pLz", minScore: 4, maxScore: 4},
+		{name: "passphrase", password: "violet tractor sings quietly", minScore: 4, maxScore: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := vo.EstimatePasswordStrength(tt.password, "johndoe")
+
+			if got.Score < tt.minScore || got.Score > tt.maxScore {
+				t.Errorf("EstimatePasswordStrength() score = %d, want between %d and %d", got.Score, tt.minScore, tt.maxScore)
+			}
+			if tt.wantWarning != "" && got.Warning != tt.wantWarning {
+				t.Errorf("EstimatePasswordStrength() warning = %q, want %q", got.Warning, tt.wantWarning)
+			}
+			if got.Score <= 2 && len(got.Suggestions) == 0 {
+				t.Error("EstimatePasswordStrength() should give suggestions for weak passwords")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+)
+
+func TestLoginFailures_CountsWithinWindow(t *testing.T) {
+	failures := cache.NewLoginFailures(time.Minute)
+	now := time.Now()
+
+	if n := failures.Add("Jane.Doe", now); n != 1 {
+		t.Fatalf("Add() = %d, want 1", n)
+	}
+	if n := failures.Add("jane.doe", now.Add(30*time.Second)); n != 2 {
+		t.Fatalf("Add() with different case = %d, want 2", n)
+	}
+	if n := failures.Add("john.roe", now); n != 1 {
+		t.Errorf("Add() for another identifier = %d, want 1", n)
+	}
+
+	// A quiet spell longer than the window starts the count over.
+	if n := failures.Add("jane.doe", now.Add(2*time.Minute)); n != 1 {
+		t.Errorf("Add() after the window = %d, want 1", n)
+	}
+}
+
+func TestLoginFailures_Reset(t *testing.T) {
+	failures := cache.NewLoginFailures(time.Minute)
+	now := time.Now()
+
+	failures.Add("jane.doe", now)
+	failures.Add("jane.doe", now)
+	failures.Reset("JANE.DOE")
+
+	if n := failures.Add("jane.doe", now); n != 1 {
+		t.Errorf("Add() after Reset() = %d, want 1", n)
+	}
+}
@@ -0,0 +1,120 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/cache"
+)
+
+type countingClientRepo struct {
+	clients map[string]*entity.OAuthClient
+	finds   int
+}
+
+func (r *countingClientRepo) Create(ctx context.Context, client *entity.OAuthClient) error {
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *countingClientRepo) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	r.finds++
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *client
+	return &copied, nil
+}
+
+func (r *countingClientRepo) List(ctx context.Context) ([]*entity.OAuthClient, error) {
+	var clients []*entity.OAuthClient
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+func (r *countingClientRepo) Update(ctx context.Context, client *entity.OAuthClient) error {
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *countingClientRepo) Delete(ctx context.Context, id string) error {
+	delete(r.clients, id)
+	return nil
+}
+
+func newCountingRepo() *countingClientRepo {
+	client := entity.NewOAuthClient("client", "Web", "", []string{"https://app.example.com/callback"}, []string{"openid"}, []string{entity.OAuthGrantAuthorizationCode})
+	return &countingClientRepo{clients: map[string]*entity.OAuthClient{client.ID: client}}
+}
+
+func TestOAuthClientRepo_CachesHits(t *testing.T) {
+	backing := newCountingRepo()
+	repo := cache.NewOAuthClientRepo(backing, time.Minute)
+	ctx := context.Background()
+
+	first, err := repo.FindByID(ctx, "client")
+	if err != nil || first == nil {
+		t.Fatalf("FindByID() = %v, %v; want the client", first, err)
+	}
+	first.Name = "Changed"
+
+	second, err := repo.FindByID(ctx, "client")
+	if err != nil || second == nil {
+		t.Fatalf("FindByID() = %v, %v; want the client", second, err)
+	}
+	if backing.finds != 1 {
+		t.Errorf("repository queried %d times, want 1", backing.finds)
+	}
+	if second.Name != "Web" {
+		t.Error("changing a returned client should not change the cached one")
+	}
+}
+
+func TestOAuthClientRepo_DoesNotCacheMisses(t *testing.T) {
+	backing := newCountingRepo()
+	repo := cache.NewOAuthClientRepo(backing, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if client, err := repo.FindByID(ctx, "unknown"); err != nil || client != nil {
+			t.Fatalf("FindByID() = %v, %v; want nil, nil", client, err)
+		}
+	}
+	if backing.finds != 2 {
+		t.Errorf("repository queried %d times, want every miss to reach it", backing.finds)
+	}
+}
+
+func TestOAuthClientRepo_ForgetsChangedClients(t *testing.T) {
+	backing := newCountingRepo()
+	repo := cache.NewOAuthClientRepo(backing, time.Minute)
+	ctx := context.Background()
+
+	client, _ := repo.FindByID(ctx, "client")
+	client.Name = "Renamed"
+	if err := repo.Update(ctx, client); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if updated, _ := repo.FindByID(ctx, "client"); updated == nil || updated.Name != "Renamed" {
+		t.Errorf("FindByID() = %+v, want the updated client", updated)
+	}
+
+	if err := repo.Delete(ctx, "client"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if deleted, _ := repo.FindByID(ctx, "client"); deleted != nil {
+		t.Errorf("FindByID() = %+v, want nil after Delete", deleted)
+	}
+}
+
+func TestOAuthClientRepo_Disabled(t *testing.T) {
+	backing := newCountingRepo()
+	if repo := cache.NewOAuthClientRepo(backing, 0); repo != backing {
+		t.Error("a zero TTL should return the repository uncached")
+	}
+}
@@ -0,0 +1,75 @@
+package encryption_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/encryption"
+)
+
+func newBox(t *testing.T, keyByte byte) *encryption.AESGCM {
+	t.Helper()
+	box, err := encryption.NewAESGCM(bytes.Repeat([]byte{keyByte}, 32))
+	if err != nil {
+		t.Fatalf("NewAESGCM() unexpected error: %v", err)
+	}
+	return box
+}
+
+func TestAESGCM_RoundTrip(t *testing.T) {
+	box := newBox(t, 1)
+	plaintext := []byte("JBSWY3DPEHPK3PXP")
+
+	first, err := box.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+	second, _ := box.Seal(plaintext)
+	if bytes.Equal(first, second) {
+		t.Error("Seal() should use a fresh nonce each time")
+	}
+	if bytes.Contains(first, plaintext) {
+		t.Error("ciphertext contains the plaintext")
+	}
+
+	got, err := box.Open(first)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Open() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestAESGCM_OpenRejectsTamperingAndWrongKey(t *testing.T) {
+	box := newBox(t, 1)
+	sealed, _ := box.Seal([]byte("secret"))
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name       string
+		box        *encryption.AESGCM
+		ciphertext []byte
+	}{
+		{name: "tampered", box: box, ciphertext: tampered},
+		{name: "wrong key", box: newBox(t, 2), ciphertext: sealed},
+		{name: "truncated", box: box, ciphertext: sealed[:10]},
+		{name: "unknown version", box: box, ciphertext: append([]byte{9}, sealed[1:]...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.box.Open(tt.ciphertext); err != encryption.ErrInvalidCiphertext {
+				t.Errorf("Open() expected error %v, got %v", encryption.ErrInvalidCiphertext, err)
+			}
+		})
+	}
+}
+
+func TestNewAESGCM_RequiresAES256Key(t *testing.T) {
+	if _, err := encryption.NewAESGCM(make([]byte, 16)); err == nil {
+		t.Error("NewAESGCM() with a 16 byte key should fail")
+	}
+}
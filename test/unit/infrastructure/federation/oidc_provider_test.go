@@ -0,0 +1,190 @@
+package federation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/federation"
+	"github.com/thanhnamdk2710/auth-service/test/support/mockoidc"
+)
+
+const (
+	testClientID     = "auth-service"
+	testClientSecret = "s3cret:with/odd&chars"
+	testRedirectURL  = "https://app.example.com/login/callback"
+	testNonce        = "n-0S6_WzA2Mj"
+	testVerifier     = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+func newProvider(idp *mockoidc.Provider) *federation.OIDCProvider {
+	return federation.NewOIDCProvider(federation.ProviderConfig{
+		ID:           "acme",
+		Name:         "Acme",
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+		RedirectURL:  testRedirectURL,
+		CacheTTL:     time.Hour,
+	}, idp.Client())
+}
+
+func codeChallenge(verifier string) string {
+	login := entity.FederatedLogin{CodeVerifier: verifier}
+	return login.CodeChallenge()
+}
+
+// signIn runs the flow at the mock provider and redeems the code it
+// issues with verifier and nonce.
+func signIn(t *testing.T, idp *mockoidc.Provider, provider *federation.OIDCProvider, verifier, nonce string) (*port.ExternalIdentity, error) {
+	t.Helper()
+	authURL, err := provider.AuthorizationURL(context.Background(), "xyz", testNonce, codeChallenge(testVerifier))
+	if err != nil {
+		t.Fatalf("AuthorizationURL() unexpected error: %v", err)
+	}
+	code, state, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() unexpected error: %v", err)
+	}
+	if state != "xyz" {
+		t.Fatalf("state = %q, want it passed back", state)
+	}
+	return provider.Exchange(context.Background(), code, verifier, nonce)
+}
+
+func TestOIDCProvider_SignIn(t *testing.T) {
+	for _, secret := range []string{testClientSecret, ""} {
+		idp := mockoidc.New(t, testClientID, secret)
+		provider := newProvider(idp)
+
+		identity, err := signIn(t, idp, provider, testVerifier, testNonce)
+		if err != nil {
+			t.Fatalf("Exchange() with secret %q unexpected error: %v", secret, err)
+		}
+		want := port.ExternalIdentity{Subject: "248289761001", Email: "jane.doe@example.com", EmailVerified: true, PreferredUsername: "jane.doe"}
+		if *identity != want {
+			t.Errorf("Exchange() = %+v, want %+v", *identity, want)
+		}
+	}
+}
+
+func TestOIDCProvider_EmailVerifiedAsString(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+	idp.IDTokenHook = func(claims jwt.MapClaims) { claims["email_verified"] = "true" }
+
+	identity, err := signIn(t, idp, newProvider(idp), testVerifier, testNonce)
+	if err != nil {
+		t.Fatalf("Exchange() unexpected error: %v", err)
+	}
+	if !identity.EmailVerified {
+		t.Error("email_verified \"true\" should count as verified")
+	}
+}
+
+func TestOIDCProvider_RejectsIDTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		nonce string
+		hook  func(jwt.MapClaims)
+	}{
+		{name: "wrong nonce", nonce: "another-nonce"},
+		{name: "wrong audience", hook: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "wrong issuer", hook: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", hook: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-5 * time.Minute).Unix() }},
+		{name: "no expiry", hook: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "no subject", hook: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "other authorized party", hook: func(c jwt.MapClaims) { c["azp"] = "someone-else" }},
+		{name: "several audiences without azp", hook: func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "someone-else"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := mockoidc.New(t, testClientID, testClientSecret)
+			idp.IDTokenHook = tt.hook
+			nonce := testNonce
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			_, err := signIn(t, idp, newProvider(idp), testVerifier, nonce)
+			if !errors.Is(err, federation.ErrInvalidIDToken) {
+				t.Errorf("Exchange() expected error %v, got %v", federation.ErrInvalidIDToken, err)
+			}
+		})
+	}
+}
+
+func TestOIDCProvider_WrongCodeVerifier(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+
+	_, err := signIn(t, idp, newProvider(idp), "wrong-verifier-wrong-verifier-wrong-verifier", testNonce)
+	if err == nil {
+		t.Error("Exchange() expected an error for a code verifier that does not match the challenge")
+	}
+}
+
+func TestOIDCProvider_WrongClientSecret(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+	provider := newProvider(idp)
+	idp.ClientSecret = "rotated"
+
+	if _, err := signIn(t, idp, provider, testVerifier, testNonce); err == nil {
+		t.Error("Exchange() expected an error when the provider rejects the client")
+	}
+}
+
+func TestOIDCProvider_CachesKeysUntilRotation(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+	provider := newProvider(idp)
+
+	for range 2 {
+		if _, err := signIn(t, idp, provider, testVerifier, testNonce); err != nil {
+			t.Fatalf("Exchange() unexpected error: %v", err)
+		}
+	}
+	if idp.JWKSRequests() != 1 {
+		t.Errorf("JWKS fetched %d times, want the keys cached", idp.JWKSRequests())
+	}
+
+	idp.RotateKey()
+	if _, err := signIn(t, idp, provider, testVerifier, testNonce); err != nil {
+		t.Fatalf("Exchange() after key rotation unexpected error: %v", err)
+	}
+	if idp.JWKSRequests() != 2 {
+		t.Errorf("JWKS fetched %d times, want one refetch for the new key", idp.JWKSRequests())
+	}
+}
+
+func TestOIDCProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+	provider := federation.NewOIDCProvider(federation.ProviderConfig{
+		ID:       "acme",
+		Issuer:   idp.Issuer() + "/",
+		ClientID: testClientID,
+		CacheTTL: time.Hour,
+	}, idp.Client())
+
+	if _, err := provider.AuthorizationURL(context.Background(), "xyz", testNonce, codeChallenge(testVerifier)); err == nil {
+		t.Error("AuthorizationURL() expected an error when discovery names another issuer")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	idp := mockoidc.New(t, testClientID, testClientSecret)
+	first := newProvider(idp)
+	second := federation.NewOIDCProvider(federation.ProviderConfig{ID: "other", Name: "Other"}, idp.Client())
+	registry := federation.NewRegistry(first, second)
+
+	if registry.Find("other") != second || registry.Find("missing") != nil {
+		t.Error("Find() should look providers up by ID")
+	}
+	if list := registry.List(); len(list) != 2 || list[0].ID() != "acme" || list[1].ID() != "other" {
+		t.Errorf("List() = %v, want the providers in configured order", list)
+	}
+}
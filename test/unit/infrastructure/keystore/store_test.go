@@ -0,0 +1,256 @@
+package keystore_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/encryption"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+type memorySigningKeyRepo struct {
+	mu   sync.Mutex
+	keys []*entity.SigningKey
+}
+
+func (r *memorySigningKeyRepo) Create(ctx context.Context, key *entity.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *key
+	r.keys = append(r.keys, &copied)
+	return nil
+}
+
+func (r *memorySigningKeyRepo) List(ctx context.Context) ([]*entity.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]*entity.SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+	return keys, nil
+}
+
+func (r *memorySigningKeyRepo) Delete(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = slices.DeleteFunc(r.keys, func(key *entity.SigningKey) bool {
+		return slices.Contains(ids, key.ID)
+	})
+	return nil
+}
+
+func (r *memorySigningKeyRepo) Lock(ctx context.Context) error {
+	return nil
+}
+
+// shift moves every key's activation back by d, as if d had passed.
+func (r *memorySigningKeyRepo) shift(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		key.ActivatesAt = key.ActivatesAt.Add(-d)
+	}
+}
+
+type inlineTx struct{}
+
+func (inlineTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestStore(t *testing.T, algorithm string) (*keystore.Store, *memorySigningKeyRepo) {
+	t.Helper()
+
+	kek, err := encryption.NewAESGCM(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &memorySigningKeyRepo{}
+	store := keystore.NewStore(repo, inlineTx{}, kek, &logger.Logger{Logger: zap.NewNop()}, &config.SigningKeyConfig{
+		Algorithm:        algorithm,
+		RotationInterval: 24 * time.Hour,
+		RefreshInterval:  time.Minute,
+		Overlap:          time.Hour,
+	})
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("Init() unexpected error: %v", err)
+	}
+	return store, repo
+}
+
+func signingKeyID(t *testing.T, store *keystore.Store) string {
+	t.Helper()
+	key, err := store.SigningKey()
+	if err != nil {
+		t.Fatalf("SigningKey() unexpected error: %v", err)
+	}
+	return key.ID
+}
+
+func TestStore_InitCreatesActiveKey(t *testing.T) {
+	store, repo := newTestStore(t, entity.SigningAlgRS256)
+
+	if len(repo.keys) != 1 {
+		t.Fatalf("Init() stored %d keys, want 1", len(repo.keys))
+	}
+	if signingKeyID(t, store) != repo.keys[0].ID {
+		t.Error("the first key should sign immediately")
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(repo.keys[0].PrivateKey); err == nil {
+		t.Error("the private key must be stored encrypted")
+	}
+
+	// A second instance starting against the same table reuses the key.
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("Init() unexpected error: %v", err)
+	}
+	if len(repo.keys) != 1 {
+		t.Errorf("a second Init() stored %d keys, want 1", len(repo.keys))
+	}
+}
+
+func TestStore_RotationOverlap(t *testing.T) {
+	ctx := context.Background()
+	store, repo := newTestStore(t, entity.SigningAlgES256)
+	oldID := signingKeyID(t, store)
+
+	created, err := store.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate() unexpected error: %v", err)
+	}
+	if err := store.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+
+	// The new key is published before it signs.
+	if signingKeyID(t, store) != oldID {
+		t.Error("a rotated key should not sign before the refresh interval has passed")
+	}
+	if store.PublishedKeys() != 2 {
+		t.Errorf("PublishedKeys() = %d, want the old and the pending key", store.PublishedKeys())
+	}
+
+	repo.shift(2 * time.Minute)
+	if err := store.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if signingKeyID(t, store) != created.ID {
+		t.Error("the rotated key should sign once activated")
+	}
+	if _, ok := store.VerificationKey(oldID); !ok {
+		t.Error("the retired key should verify tokens until the overlap ends")
+	}
+
+	// Past the overlap the old key is no longer published and is deleted.
+	repo.shift(time.Hour)
+	if err := store.Prune(ctx); err != nil {
+		t.Fatalf("Prune() unexpected error: %v", err)
+	}
+	if err := store.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if _, ok := store.VerificationKey(oldID); ok {
+		t.Error("the retired key should not verify after the overlap")
+	}
+	if len(repo.keys) != 1 || repo.keys[0].ID != created.ID {
+		t.Errorf("Prune() should delete only the expired key, %d keys left", len(repo.keys))
+	}
+}
+
+func TestStore_RotateIfDue(t *testing.T) {
+	ctx := context.Background()
+	store, repo := newTestStore(t, entity.SigningAlgEdDSA)
+
+	rotated, err := store.RotateIfDue(ctx)
+	if err != nil || rotated {
+		t.Fatalf("RotateIfDue() = %v, %v; a fresh key is not due", rotated, err)
+	}
+
+	repo.shift(25 * time.Hour)
+	rotated, err = store.RotateIfDue(ctx)
+	if err != nil || !rotated {
+		t.Fatalf("RotateIfDue() = %v, %v; want a rotation after the interval", rotated, err)
+	}
+
+	// The pending key counts as the latest, so it is not rotated again.
+	rotated, err = store.RotateIfDue(ctx)
+	if err != nil || rotated {
+		t.Errorf("RotateIfDue() = %v, %v; want no second rotation", rotated, err)
+	}
+	if len(repo.keys) != 2 {
+		t.Errorf("stored %d keys, want 2", len(repo.keys))
+	}
+}
+
+func TestStore_ReloadRejectsWrongEncryptionKey(t *testing.T) {
+	_, repo := newTestStore(t, entity.SigningAlgES256)
+
+	otherKEK, _ := encryption.NewAESGCM([]byte("0123456789abcdef0123456789abcdef"))
+	store := keystore.NewStore(repo, inlineTx{}, otherKEK, &logger.Logger{Logger: zap.NewNop()}, &config.SigningKeyConfig{
+		Algorithm:       entity.SigningAlgES256,
+		RefreshInterval: time.Minute,
+		Overlap:         time.Hour,
+	})
+	if err := store.Reload(context.Background()); err == nil {
+		t.Error("Reload() should fail when keys cannot be decrypted")
+	}
+}
+
+func TestKey_JWK(t *testing.T) {
+	decode := func(t *testing.T, s string) []byte {
+		t.Helper()
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("%q is not base64url: %v", s, err)
+		}
+		return b
+	}
+
+	for _, algorithm := range []string{entity.SigningAlgRS256, entity.SigningAlgES256, entity.SigningAlgEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := keystore.GenerateKey(algorithm)
+			if err != nil {
+				t.Fatalf("GenerateKey() unexpected error: %v", err)
+			}
+
+			jwk := key.JWK()
+			if jwk.KeyID != key.ID || jwk.Algorithm != algorithm || jwk.Use != "sig" {
+				t.Errorf("JWK() = %+v, want kid, alg and use set", jwk)
+			}
+
+			// A relying party rebuilds the same public key from the JWK.
+			var public any
+			switch jwk.KeyType {
+			case "RSA":
+				public = &rsa.PublicKey{N: new(big.Int).SetBytes(decode(t, jwk.N)), E: int(new(big.Int).SetBytes(decode(t, jwk.E)).Int64())}
+			case "EC":
+				public = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(decode(t, jwk.X)), Y: new(big.Int).SetBytes(decode(t, jwk.Y))}
+			case "OKP":
+				public = ed25519.PublicKey(decode(t, jwk.X))
+			}
+			equal, ok := key.Public.(interface{ Equal(crypto.PublicKey) bool })
+			if !ok || !equal.Equal(public) {
+				t.Errorf("JWK() does not describe the key: %+v", jwk)
+			}
+		})
+	}
+}
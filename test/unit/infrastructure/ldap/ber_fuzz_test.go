@@ -0,0 +1,174 @@
+package ldap_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/ldap"
+)
+
+// The BER of RFC 4511 messages, enough to build the seed responses.
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+	berSet         = 0x31
+
+	berBindResponse    = 0x61
+	berSearchEntry     = 0x64
+	berSearchDone      = 0x65
+	berSearchResultRef = 0x73
+)
+
+func ber(tag byte, content ...[]byte) []byte {
+	size := 0
+	for _, c := range content {
+		size += len(c)
+	}
+	out := []byte{tag}
+	if size < 0x80 {
+		out = append(out, byte(size))
+	} else {
+		var length []byte
+		for n := size; n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		out = append(out, 0x80|byte(len(length)))
+		out = append(out, length...)
+	}
+	for _, c := range content {
+		out = append(out, c...)
+	}
+	return out
+}
+
+func berString(tag byte, s string) []byte {
+	return ber(tag, []byte(s))
+}
+
+func berMessage(id byte, op []byte) []byte {
+	return ber(berSequence, ber(berInteger, []byte{id}), op)
+}
+
+func berResult(op byte, code byte) []byte {
+	return ber(op, ber(berEnumerated, []byte{code}), berString(berOctetString, ""), berString(berOctetString, ""))
+}
+
+func berEntry(dn string, attributes map[string]string) []byte {
+	var list [][]byte
+	for name, value := range attributes {
+		list = append(list, ber(berSequence, berString(berOctetString, name), ber(berSet, berString(berOctetString, value))))
+	}
+	return ber(berSearchEntry, berString(berOctetString, dn), ber(berSequence, list...))
+}
+
+// replay accepts one connection and answers whatever the client sends
+// with response. It then ends its side, so a client waiting for more
+// reads EOF rather than its timeout, and waits for the client to hang up.
+func replay(tb testing.TB, response []byte) string {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("Listen() unexpected error: %v", err)
+	}
+	tb.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, _ = conn.Write(response)
+		_ = conn.(*net.TCPConn).CloseWrite()
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+	return "ldap://" + listener.Addr().String()
+}
+
+// FuzzDirectory_Responses feeds arbitrary server responses to the
+// client's BER decoder. Verify must fail or return a usable account; it
+// must never panic or hang.
+func FuzzDirectory_Responses(f *testing.F) {
+	bound := berMessage(1, berResult(berBindResponse, 0))
+	jane := berMessage(2, berEntry(testJaneDN, map[string]string{"entryUUID": "5b0b6a0c", "uid": "jane.doe"}))
+	done := berMessage(2, berResult(berSearchDone, 0))
+	userBound := berMessage(3, berResult(berBindResponse, 0))
+
+	seeds := [][]byte{
+		concat(bound, jane, done, userBound),
+		concat(bound, jane, done, berMessage(3, berResult(berBindResponse, 49))),
+		concat(bound, berMessage(2, ber(berSearchResultRef, berString(berOctetString, "ldap://other"))), done),
+		concat(bound, done),
+		concat(bound, jane, jane, done),
+		berMessage(1, berResult(berBindResponse, 49)),
+		// Unsolicited notice of disconnection.
+		berMessage(0, ber(0x78, berResult(0x0a, 52))),
+		// Truncated, oversized and indefinite lengths.
+		bound[:len(bound)-1],
+		{berSequence, 0x84, 0x7f, 0xff, 0xff, 0xff},
+		{berSequence, 0x85, 0x01, 0x00, 0x00, 0x00, 0x00},
+		{berSequence, 0x80, 0x00, 0x00},
+		// A length running past the end of its parent.
+		concat(bound, []byte{berSequence, 0x05, berInteger, 0x01, 0x02, berSearchEntry, 0x7f}),
+		// High tag numbers and deep nesting.
+		{0x1f, 0x81, 0x01, 0x00},
+		nested(200),
+		// Empty and oversized integers.
+		ber(berSequence, ber(berInteger), berResult(berBindResponse, 0)),
+		ber(berSequence, ber(berInteger, make([]byte, 9)), berResult(berBindResponse, 0)),
+		// A bind response without its result fields.
+		berMessage(1, ber(berBindResponse, ber(berEnumerated, []byte{0}))),
+		// An entry whose attribute is missing its values.
+		concat(bound, berMessage(2, ber(berSearchEntry, berString(berOctetString, testJaneDN),
+			ber(berSequence, ber(berSequence, berString(berOctetString, "uid"))))), done),
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, response []byte) {
+		server := replay(t, response)
+		cfg := ldap.Config{
+			URL:               server,
+			BindDN:            testServiceDN,
+			BindPassword:      testServicePass,
+			BaseDN:            testBaseDN,
+			UserFilter:        "(uid={username})",
+			IDAttribute:       "entryUUID",
+			UsernameAttribute: "uid",
+			Timeout:           2 * time.Second,
+		}
+
+		start := time.Now()
+		account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Fatalf("Verify() took %v, want it bounded by the timeout", elapsed)
+		}
+		if err == nil && account != nil && (account.Subject == "" || len(account.Subject) > 255) {
+			t.Errorf("Verify() = %+v, want an account with a usable subject", account)
+		}
+	})
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+// nested wraps an empty sequence depth times.
+func nested(depth int) []byte {
+	out := ber(berSequence)
+	for i := 0; i < depth; i++ {
+		out = ber(berSequence, out)
+	}
+	return out
+}
@@ -0,0 +1,273 @@
+package ldap_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/ldap"
+	"github.com/thanhnamdk2710/auth-service/test/support/ldapserver"
+)
+
+const (
+	testBaseDN      = "ou=people,dc=example,dc=com"
+	testServiceDN   = "cn=auth-service,ou=services,dc=example,dc=com"
+	testServicePass = "service-secret"
+	testJaneDN      = "uid=jane.doe,ou=people,dc=example,dc=com"
+	testJanePass    = "correct horse battery staple"
+	testAdminsDN    = "cn=Admins,ou=groups,dc=example,dc=com"
+	testSupportDN   = "cn=Support,ou=groups,dc=example,dc=com"
+)
+
+func newServer(tb testing.TB, server *ldapserver.Server) *ldapserver.Server {
+	server.Add(ldapserver.Entry{DN: testServiceDN, Password: testServicePass})
+	server.Add(ldapserver.Entry{
+		DN:       testJaneDN,
+		Password: testJanePass,
+		Attributes: map[string][]string{
+			"objectClass": {"inetOrgPerson"},
+			"entryUUID":   {"5b0b6a0c-9d3e-4d8e-a7a6-1f0c2e7e4b11"},
+			"uid":         {"jane.doe"},
+			"mail":        {"jane.doe@example.com"},
+			"memberOf":    {"CN=admins, OU=groups, DC=example, DC=com", testSupportDN, "cn=Other,ou=groups,dc=example,dc=com"},
+		},
+	})
+	server.Add(ldapserver.Entry{
+		DN:       "uid=john.roe,ou=people,dc=example,dc=com",
+		Password: "john's password",
+		Attributes: map[string][]string{
+			"entryUUID": {"0f6c9d2a-3b1e-4f7a-9c55-8e2d4a6b7c90"},
+			"uid":       {"john.roe"},
+		},
+	})
+	return server
+}
+
+func config(server *ldapserver.Server) ldap.Config {
+	return ldap.Config{
+		URL:               server.URL(),
+		BindDN:            testServiceDN,
+		BindPassword:      testServicePass,
+		BaseDN:            testBaseDN,
+		UserFilter:        "(&(objectClass=*)(uid={username}))",
+		IDAttribute:       "entryUUID",
+		UsernameAttribute: "uid",
+		EmailAttribute:    "mail",
+		GroupAttribute:    "memberOf",
+		GroupRoles: map[string][]string{
+			testAdminsDN:  {"admin"},
+			testSupportDN: {"support", "admin"},
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func trusting(server *ldapserver.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return pool
+}
+
+func TestDirectory_SearchThenBind(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+
+	account, err := ldap.NewDirectory(config(server)).Verify(context.Background(), "jane.doe", testJanePass)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	want := &port.DirectoryAccount{
+		Subject:  "5b0b6a0c-9d3e-4d8e-a7a6-1f0c2e7e4b11",
+		Username: "jane.doe",
+		Email:    "jane.doe@example.com",
+		Roles:    []string{"admin", "support"},
+	}
+	if !reflect.DeepEqual(account, want) {
+		t.Errorf("Verify() = %+v, want %+v", account, want)
+	}
+	if binds := server.Binds(); !slices.Equal(binds, []string{testServiceDN, testJaneDN}) {
+		t.Errorf("binds = %v, want the service account and then the user", binds)
+	}
+}
+
+func TestDirectory_BindAsUser(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	cfg := config(server)
+	cfg.BindDN, cfg.BindPassword = "", ""
+	cfg.UserDNTemplate = "uid={username},ou=people,dc=example,dc=com"
+
+	account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if account == nil || account.Subject != "5b0b6a0c-9d3e-4d8e-a7a6-1f0c2e7e4b11" {
+		t.Errorf("Verify() = %+v, want jane.doe's account", account)
+	}
+	if binds := server.Binds(); !slices.Equal(binds, []string{testJaneDN}) {
+		t.Errorf("binds = %v, want only the user", binds)
+	}
+
+	if account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", "wrong"); err != nil || account != nil {
+		t.Errorf("Verify() with a wrong password = %+v, %v, want a rejection", account, err)
+	}
+}
+
+func TestDirectory_RejectsBadCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{"wrong password", "jane.doe", "wrong"},
+		{"unknown user", "nobody", testJanePass},
+		{"another user's password", "john.roe", testJanePass},
+		{"wildcard username", "*", testJanePass},
+		{"filter injection", "jane.doe)(uid=*", testJanePass},
+		{"control characters", "jane.doe\x00", testJanePass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t, ldapserver.New(t))
+
+			account, err := ldap.NewDirectory(config(server)).Verify(context.Background(), tt.username, tt.password)
+			if err != nil || account != nil {
+				t.Errorf("Verify() = %+v, %v, want a rejection", account, err)
+			}
+		})
+	}
+}
+
+// TestDirectory_RejectsEmptyPasswords checks that an empty password never
+// reaches the server, which would take it for an unauthenticated bind.
+func TestDirectory_RejectsEmptyPasswords(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+
+	account, err := ldap.NewDirectory(config(server)).Verify(context.Background(), "jane.doe", "")
+	if err != nil || account != nil {
+		t.Errorf("Verify() = %+v, %v, want a rejection", account, err)
+	}
+	if binds := server.Binds(); len(binds) != 0 {
+		t.Errorf("binds = %v, want none", binds)
+	}
+}
+
+func TestDirectory_FilterRestrictsAccounts(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	cfg := config(server)
+	cfg.UserFilter = "(&(uid={username})(memberOf=" + testAdminsDN + "))"
+	directory := ldap.NewDirectory(cfg)
+
+	if account, err := directory.Verify(context.Background(), "john.roe", "john's password"); err != nil || account != nil {
+		t.Errorf("Verify() for a user outside the group = %+v, %v, want a rejection", account, err)
+	}
+	if account, err := directory.Verify(context.Background(), "jane.doe", testJanePass); err != nil || account == nil {
+		t.Errorf("Verify() for a member = %+v, %v, want the account", account, err)
+	}
+}
+
+func TestDirectory_StartTLS(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	server.RequireTLS = true
+	cfg := config(server)
+
+	if _, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass); err == nil {
+		t.Error("Verify() without StartTLS against a server requiring TLS expected error")
+	}
+
+	cfg.StartTLS = true
+	if _, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass); err == nil {
+		t.Error("Verify() with an untrusted server certificate expected error")
+	}
+
+	cfg.RootCAs = trusting(server)
+	account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+	if err != nil || account == nil {
+		t.Errorf("Verify() with StartTLS = %+v, %v, want the account", account, err)
+	}
+}
+
+func TestDirectory_LDAPS(t *testing.T) {
+	server := newServer(t, ldapserver.NewTLS(t))
+	server.RequireTLS = true
+	cfg := config(server)
+	cfg.RootCAs = trusting(server)
+
+	account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+	if err != nil || account == nil {
+		t.Errorf("Verify() over ldaps = %+v, %v, want the account", account, err)
+	}
+}
+
+func TestDirectory_ServiceAccountFailure(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	cfg := config(server)
+	cfg.BindPassword = "wrong"
+
+	_, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+	var result *ldap.ResultError
+	if !errors.As(err, &result) || result.Code != ldapserver.ResultInvalidCredentials {
+		t.Errorf("Verify() error = %v, want the service account's bind to fail rather than the user to be rejected", err)
+	}
+}
+
+func TestDirectory_AmbiguousMatch(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	cfg := config(server)
+	cfg.UserFilter = "(|(uid={username})(uid=john.roe))"
+
+	if _, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass); err == nil {
+		t.Error("Verify() with a filter matching two entries expected error")
+	}
+}
+
+func TestDirectory_MissingIdentifier(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	cfg := config(server)
+	cfg.IDAttribute = "objectGUID"
+
+	if _, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass); err == nil {
+		t.Error("Verify() for an entry without the ID attribute expected error")
+	}
+}
+
+func TestDirectory_BinaryIdentifier(t *testing.T) {
+	server := newServer(t, ldapserver.New(t))
+	server.Add(ldapserver.Entry{
+		DN:       testJaneDN,
+		Password: testJanePass,
+		Attributes: map[string][]string{
+			"objectGUID": {"\x0c\x6a\x0b\x5b\x3e\x9d\x8e\x4d\xa7\xa6\x1f\x0c\x2e\x7e\x4b\x11"},
+			"uid":        {"jane.doe"},
+		},
+	})
+	cfg := config(server)
+	cfg.IDAttribute = "objectGUID"
+
+	account, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if want := "0c6a0b5b3e9d8e4da7a61f0c2e7e4b11"; account.Subject != want {
+		t.Errorf("Subject = %q, want the hex encoded GUID %q", account.Subject, want)
+	}
+}
+
+func TestDirectory_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	cfg := config(ldapserver.New(t))
+	cfg.URL = "ldap://" + address
+	if _, err := ldap.NewDirectory(cfg).Verify(context.Background(), "jane.doe", testJanePass); err == nil {
+		t.Error("Verify() against an unreachable server expected error")
+	}
+}
@@ -0,0 +1,132 @@
+package mailer_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/mailer"
+)
+
+func TestMemoryMailer_ListNewestFirst(t *testing.T) {
+	m := mailer.NewMemoryMailer(10)
+
+	for i := 1; i <= 3; i++ {
+		if err := m.Send(context.Background(), port.MailMessage{
+			To:      "test@example.com",
+			Subject: fmt.Sprintf("message %d", i),
+		}); err != nil {
+			t.Fatalf("Send() unexpected error: %v", err)
+		}
+	}
+
+	msgs := m.List()
+	if len(msgs) != 3 {
+		t.Fatalf("List() returned %d messages, want 3", len(msgs))
+	}
+	if msgs[0].Subject != "message 3" || msgs[2].Subject != "message 1" {
+		t.Errorf("List() order = %q, %q, %q; want newest first", msgs[0].Subject, msgs[1].Subject, msgs[2].Subject)
+	}
+	if msgs[0].ID == "" || msgs[0].SentAt.IsZero() {
+		t.Error("List() messages should have an ID and SentAt")
+	}
+}
+
+func TestMemoryMailer_DropsOldestAtCapacity(t *testing.T) {
+	m := mailer.NewMemoryMailer(2)
+
+	for i := 1; i <= 3; i++ {
+		_ = m.Send(context.Background(), port.MailMessage{Subject: fmt.Sprintf("message %d", i)})
+	}
+
+	msgs := m.List()
+	if len(msgs) != 2 {
+		t.Fatalf("List() returned %d messages, want 2", len(msgs))
+	}
+	if msgs[1].Subject != "message 2" {
+		t.Errorf("oldest remaining message = %q, want %q", msgs[1].Subject, "message 2")
+	}
+}
+
+func TestMemoryMailer_Clear(t *testing.T) {
+	m := mailer.NewMemoryMailer(10)
+	_ = m.Send(context.Background(), port.MailMessage{Subject: "hello"})
+
+	m.Clear()
+
+	if len(m.List()) != 0 {
+		t.Error("Clear() should remove all messages")
+	}
+}
+
+func TestFileMailer_WritesEML(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	m, err := mailer.NewFileMailer(dir, "no-reply@example.com")
+	if err != nil {
+		t.Fatalf("NewFileMailer() unexpected error: %v", err)
+	}
+
+	err = m.Send(context.Background(), port.MailMessage{
+		To:       "test@example.com",
+		Subject:  "Verify your email address",
+		TextBody: "Click http://localhost/verify-email?token=abc",
+		HTMLBody: "<a href=\"http://localhost/verify-email?token=abc\">Verify</a>",
+	})
+	if err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %v (err %v)", files, err)
+	}
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read .eml: %v", err)
+	}
+
+	for _, want := range []string{
+		"From: no-reply@example.com\r\n",
+		"To: test@example.com\r\n",
+		"Subject: Verify your email address\r\n",
+		"MIME-Version: 1.0\r\n",
+		"multipart/alternative",
+		"text/plain",
+		"text/html",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf(".eml content missing %q", want)
+		}
+	}
+}
+
+func TestFileMailer_StripsHeaderInjection(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := mailer.NewFileMailer(dir, "no-reply@example.com")
+	if err != nil {
+		t.Fatalf("NewFileMailer() unexpected error: %v", err)
+	}
+
+	_ = m.Send(context.Background(), port.MailMessage{
+		To:       "test@example.com\r\nBcc: attacker@example.com",
+		Subject:  "hello",
+		TextBody: "body",
+	})
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %v", files)
+	}
+	content, _ := os.ReadFile(files[0])
+
+	if strings.Contains(string(content), "\r\nBcc:") {
+		t.Error("header values must not be able to inject new headers")
+	}
+}
@@ -0,0 +1,29 @@
+package otp_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+)
+
+func TestEmailCodeGenerator_Format(t *testing.T) {
+	gen := otp.NewEmailCodeGenerator()
+	sixDigits := regexp.MustCompile(`^\d{6}$`)
+
+	seen := make(map[string]bool)
+	for range 50 {
+		code, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() unexpected error: %v", err)
+		}
+		if !sixDigits.MatchString(code) {
+			t.Fatalf("Generate() = %q, want six digits", code)
+		}
+		seen[code] = true
+	}
+
+	if len(seen) < 45 {
+		t.Errorf("Generate() returned only %d distinct codes out of 50", len(seen))
+	}
+}
@@ -0,0 +1,117 @@
+package otp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+)
+
+// rfc6238Secret is the SHA-1 key from RFC 6238 appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTP_ValidateRFC6238Vectors(t *testing.T) {
+	totp := otp.NewTOTP("auth-service", 0)
+
+	// The RFC lists eight digit codes; six digit codes are their last six.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tt := range tests {
+		step, ok := totp.Validate(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok {
+			t.Errorf("Validate(%q) at %d = false, want true", tt.code, tt.unix)
+		}
+		if step != tt.unix/30 {
+			t.Errorf("Validate(%q) step = %d, want %d", tt.code, step, tt.unix/30)
+		}
+	}
+}
+
+func TestTOTP_ValidateSkew(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	previous := time.Unix(1111111111-30, 0)
+
+	code := otp.Code([]byte("12345678901234567890"), previous.Unix()/30)
+
+	if _, ok := otp.NewTOTP("auth-service", 0).Validate(rfc6238Secret, code, at); ok {
+		t.Error("Validate() without skew should reject the previous period's code")
+	}
+	if _, ok := otp.NewTOTP("auth-service", 1).Validate(rfc6238Secret, code, at); !ok {
+		t.Error("Validate() with skew 1 should accept the previous period's code")
+	}
+}
+
+func TestTOTP_ValidateRejectsMalformedCodes(t *testing.T) {
+	totp := otp.NewTOTP("auth-service", 1)
+	at := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870822", "abcdef"} {
+		if _, ok := totp.Validate(rfc6238Secret, code, at); ok {
+			t.Errorf("Validate(%q) = true, want false", code)
+		}
+	}
+	if _, ok := totp.Validate("not base32!", "287082", at); ok {
+		t.Error("Validate() with an invalid secret = true, want false")
+	}
+}
+
+func TestTOTP_ProvisioningURI(t *testing.T) {
+	totp := otp.NewTOTP("Acme Auth", 1)
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() unexpected error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("GenerateSecret() length = %d, want 32", len(secret))
+	}
+
+	u, err := url.Parse(totp.ProvisioningURI(secret, "test@example.com"))
+	if err != nil {
+		t.Fatalf("ProvisioningURI() is not a URL: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("ProvisioningURI() = %q, want otpauth://totp/...", u)
+	}
+	if u.Path != "/Acme Auth:test@example.com" {
+		t.Errorf("ProvisioningURI() label = %q", u.Path)
+	}
+	q := u.Query()
+	if q.Get("secret") != secret || q.Get("issuer") != "Acme Auth" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Errorf("ProvisioningURI() query = %v", q)
+	}
+}
+
+func TestRecoveryCodeGenerator(t *testing.T) {
+	codes, err := otp.NewRecoveryCodeGenerator().Generate(10)
+	if err != nil {
+		t.Fatalf("Generate() unexpected error: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("Generate() returned %d codes, want 10", len(codes))
+	}
+
+	format := regexp.MustCompile(`^[a-z2-9]{4}-[a-z2-9]{4}-[a-z2-9]{4}$`)
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match xxxx-xxxx-xxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
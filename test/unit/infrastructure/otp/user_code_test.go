@@ -0,0 +1,29 @@
+package otp_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/otp"
+)
+
+func TestUserCodeGenerator_Format(t *testing.T) {
+	gen := otp.NewUserCodeGenerator()
+	format := regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`)
+
+	seen := make(map[string]bool)
+	for range 50 {
+		code, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() unexpected error: %v", err)
+		}
+		if !format.MatchString(code) {
+			t.Fatalf("Generate() = %q, want two groups of four consonants", code)
+		}
+		seen[code] = true
+	}
+
+	if len(seen) < 50 {
+		t.Errorf("Generate() returned only %d distinct codes out of 50", len(seen))
+	}
+}
@@ -0,0 +1,374 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/outbox"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+type memoryOutboxRepo struct {
+	mu     sync.Mutex
+	events []*entity.OutboxEvent
+}
+
+func (r *memoryOutboxRepo) Create(ctx context.Context, event *entity.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.events {
+		if existing.DedupKey == event.DedupKey {
+			return nil
+		}
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryOutboxRepo) Claim(ctx context.Context, limit int, now time.Time, lease time.Duration, maxAttempts int) ([]*entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*entity.OutboxEvent
+	for _, event := range r.events {
+		expired := event.Status == entity.OutboxStatusProcessing && event.LockedUntil.Before(now)
+		if expired && event.Attempts >= maxAttempts {
+			event.Status = entity.OutboxStatusDead
+			event.LockedUntil = nil
+			continue
+		}
+		if len(claimed) == limit {
+			continue
+		}
+		due := event.Status == entity.OutboxStatusPending && !event.NextAttemptAt.After(now)
+		if !due && !expired {
+			continue
+		}
+		lockedUntil := now.Add(lease)
+		event.Status = entity.OutboxStatusProcessing
+		event.LockedUntil = &lockedUntil
+		event.Attempts++
+		// Hand out a copy, as a query would, so a later claim does not
+		// change what an earlier one holds.
+		copied := *event
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memoryOutboxRepo) find(id string) *entity.OutboxEvent {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+// held returns the event if it is still claimed under the lease ending at
+// lockedUntil.
+func (r *memoryOutboxRepo) held(id string, lockedUntil time.Time) *entity.OutboxEvent {
+	event := r.find(id)
+	if event == nil || event.Status != entity.OutboxStatusProcessing || !event.LockedUntil.Equal(lockedUntil) {
+		return nil
+	}
+	return event
+}
+
+func (r *memoryOutboxRepo) MarkDelivered(ctx context.Context, id string, lockedUntil, processedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.held(id, lockedUntil)
+	if event == nil {
+		return false, nil
+	}
+	event.Status = entity.OutboxStatusDelivered
+	event.LockedUntil = nil
+	event.ProcessedAt = &processedAt
+	return true, nil
+}
+
+func (r *memoryOutboxRepo) MarkRetry(ctx context.Context, id string, lockedUntil, nextAttemptAt time.Time, lastError string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.held(id, lockedUntil)
+	if event == nil {
+		return false, nil
+	}
+	event.Status = entity.OutboxStatusPending
+	event.LockedUntil = nil
+	event.NextAttemptAt = nextAttemptAt
+	event.LastError = lastError
+	return true, nil
+}
+
+func (r *memoryOutboxRepo) MarkDead(ctx context.Context, id string, lockedUntil, processedAt time.Time, lastError string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.held(id, lockedUntil)
+	if event == nil {
+		return false, nil
+	}
+	event.Status = entity.OutboxStatusDead
+	event.LockedUntil = nil
+	event.ProcessedAt = &processedAt
+	event.LastError = lastError
+	return true, nil
+}
+
+// makeDue moves every pending retry into the past so the next batch
+// claims it without waiting for the backoff.
+func (r *memoryOutboxRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range r.events {
+		event.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+type sequentialIDs struct {
+	n int
+}
+
+func (g *sequentialIDs) Generate() string {
+	g.n++
+	return fmt.Sprintf("id-%d", g.n)
+}
+
+type recordingSubscriber struct {
+	name       string
+	failures   int
+	deliveries []port.OutboxDelivery
+	// during runs inside Handle, before it returns.
+	during func()
+}
+
+func (s *recordingSubscriber) Name() string {
+	return s.name
+}
+
+func (s *recordingSubscriber) Handle(ctx context.Context, delivery port.OutboxDelivery) error {
+	s.deliveries = append(s.deliveries, delivery)
+	if s.during != nil {
+		s.during()
+	}
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("temporary failure")
+	}
+	return nil
+}
+
+func newTestConfig(maxAttempts int) *config.OutboxConfig {
+	return &config.OutboxConfig{
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		MaxAttempts:    maxAttempts,
+		BackoffBase:    time.Second,
+		BackoffMax:     time.Minute,
+		Lease:          time.Minute,
+		HandlerTimeout: time.Second,
+	}
+}
+
+func newTestOutbox(t *testing.T, maxAttempts int, subscribers ...*recordingSubscriber) (*memoryOutboxRepo, *outbox.Publisher, *outbox.Dispatcher) {
+	t.Helper()
+
+	repo := &memoryOutboxRepo{}
+	router := outbox.NewRouter()
+	for _, sub := range subscribers {
+		if err := router.Subscribe(sub, "user.registered"); err != nil {
+			t.Fatalf("Subscribe() unexpected error: %v", err)
+		}
+	}
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	return repo, outbox.NewPublisher(repo, router, &sequentialIDs{}), outbox.NewDispatcher(repo, router, log, newTestConfig(maxAttempts))
+}
+
+func publish(t *testing.T, publisher *outbox.Publisher, eventType, dedupKey string) {
+	t.Helper()
+	err := publisher.Publish(context.Background(), port.OutboxMessage{
+		EventType: eventType,
+		DedupKey:  dedupKey,
+		Payload:   map[string]string{"user_id": "u1"},
+	})
+	if err != nil {
+		t.Fatalf("Publish() unexpected error: %v", err)
+	}
+}
+
+func TestPublisher_FansOutAndDeduplicates(t *testing.T) {
+	audit := &recordingSubscriber{name: "audit"}
+	mail := &recordingSubscriber{name: "mail"}
+	repo, publisher, _ := newTestOutbox(t, 3, audit, mail)
+
+	publish(t, publisher, "user.registered", "user.registered:u1")
+	publish(t, publisher, "user.registered", "user.registered:u1")
+	publish(t, publisher, "user.unrouted", "user.unrouted:u1")
+
+	if len(repo.events) != 2 {
+		t.Fatalf("expected one row per subscriber, got %d", len(repo.events))
+	}
+	if repo.events[0].DedupKey != "user.registered:u1:audit" || repo.events[1].DedupKey != "user.registered:u1:mail" {
+		t.Errorf("unexpected dedup keys %q, %q", repo.events[0].DedupKey, repo.events[1].DedupKey)
+	}
+}
+
+func TestPublisher_RequiresDedupKey(t *testing.T) {
+	_, publisher, _ := newTestOutbox(t, 3, &recordingSubscriber{name: "audit"})
+
+	err := publisher.Publish(context.Background(), port.OutboxMessage{EventType: "user.registered"})
+	if err != outbox.ErrDedupKeyRequired {
+		t.Errorf("Publish() expected error %v, got %v", outbox.ErrDedupKeyRequired, err)
+	}
+}
+
+func TestDispatcher_DeliversAndRetries(t *testing.T) {
+	healthy := &recordingSubscriber{name: "audit"}
+	flaky := &recordingSubscriber{name: "webhook", failures: 1}
+	repo, publisher, dispatcher := newTestOutbox(t, 3, healthy, flaky)
+
+	publish(t, publisher, "user.registered", "user.registered:u1")
+
+	if _, err := dispatcher.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("DispatchBatch() unexpected error: %v", err)
+	}
+	if repo.events[0].Status != entity.OutboxStatusDelivered {
+		t.Errorf("healthy subscriber status = %s, want %s", repo.events[0].Status, entity.OutboxStatusDelivered)
+	}
+	if repo.events[1].Status != entity.OutboxStatusPending || repo.events[1].LastError == "" {
+		t.Errorf("failed delivery should be rescheduled with its error, got %+v", repo.events[1])
+	}
+
+	n, err := dispatcher.DispatchBatch(context.Background())
+	if err != nil || n != 0 {
+		t.Fatalf("DispatchBatch() before backoff elapsed = %d, %v; want 0, nil", n, err)
+	}
+
+	repo.makeDue()
+	if _, err := dispatcher.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("DispatchBatch() unexpected error: %v", err)
+	}
+	if repo.events[1].Status != entity.OutboxStatusDelivered {
+		t.Errorf("retried subscriber status = %s, want %s", repo.events[1].Status, entity.OutboxStatusDelivered)
+	}
+	if len(healthy.deliveries) != 1 {
+		t.Errorf("healthy subscriber should not be redelivered, got %d deliveries", len(healthy.deliveries))
+	}
+	if len(flaky.deliveries) != 2 || flaky.deliveries[1].Attempt != 2 {
+		t.Errorf("expected a second attempt, got %+v", flaky.deliveries)
+	}
+	if flaky.deliveries[0].DedupKey != flaky.deliveries[1].DedupKey {
+		t.Error("redelivery should keep the same dedup key")
+	}
+}
+
+func TestDispatcher_MarksDeadAfterMaxAttempts(t *testing.T) {
+	broken := &recordingSubscriber{name: "webhook", failures: 10}
+	repo, publisher, dispatcher := newTestOutbox(t, 2, broken)
+
+	publish(t, publisher, "user.registered", "user.registered:u1")
+
+	for i := 0; i < 3; i++ {
+		repo.makeDue()
+		if _, err := dispatcher.DispatchBatch(context.Background()); err != nil {
+			t.Fatalf("DispatchBatch() unexpected error: %v", err)
+		}
+	}
+
+	if repo.events[0].Status != entity.OutboxStatusDead {
+		t.Errorf("status = %s, want %s", repo.events[0].Status, entity.OutboxStatusDead)
+	}
+	if len(broken.deliveries) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(broken.deliveries))
+	}
+}
+
+func TestDispatcher_IgnoresOutcomeAfterLeaseLost(t *testing.T) {
+	slow := &recordingSubscriber{name: "webhook"}
+	repo, publisher, dispatcher := newTestOutbox(t, 3, slow)
+
+	publish(t, publisher, "user.registered", "user.registered:u1")
+
+	// The lease runs out while the subscriber is still busy, and another
+	// dispatcher claims the event again.
+	var reclaimed []*entity.OutboxEvent
+	slow.during = func() {
+		slow.during = nil
+		var err error
+		reclaimed, err = repo.Claim(context.Background(), 10, time.Now().Add(2*time.Minute), time.Minute, 3)
+		if err != nil {
+			t.Fatalf("Claim() unexpected error: %v", err)
+		}
+	}
+
+	if _, err := dispatcher.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("DispatchBatch() unexpected error: %v", err)
+	}
+	if len(reclaimed) != 1 {
+		t.Fatalf("expected the expired lease to be claimed again, got %d events", len(reclaimed))
+	}
+	event := repo.events[0]
+	if event.Status != entity.OutboxStatusProcessing || !event.LockedUntil.Equal(*reclaimed[0].LockedUntil) {
+		t.Errorf("the first delivery should not settle an event claimed again, got %+v", event)
+	}
+
+	held, err := repo.MarkDelivered(context.Background(), event.ID, *reclaimed[0].LockedUntil, time.Now())
+	if err != nil || !held {
+		t.Fatalf("MarkDelivered() under the newer lease = %v, %v; want true, nil", held, err)
+	}
+}
+
+func TestDispatcher_ExpiredLeaseOnLastAttemptIsDead(t *testing.T) {
+	sub := &recordingSubscriber{name: "webhook"}
+	repo, publisher, dispatcher := newTestOutbox(t, 2, sub)
+
+	publish(t, publisher, "user.registered", "user.registered:u1")
+
+	// The dispatcher holding the last attempt died mid-delivery.
+	expired := time.Now().Add(-time.Minute)
+	repo.events[0].Status = entity.OutboxStatusProcessing
+	repo.events[0].Attempts = 2
+	repo.events[0].LockedUntil = &expired
+
+	if _, err := dispatcher.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("DispatchBatch() unexpected error: %v", err)
+	}
+	if len(sub.deliveries) != 0 {
+		t.Errorf("an event with no attempts left should not be delivered, got %d deliveries", len(sub.deliveries))
+	}
+	if repo.events[0].Status != entity.OutboxStatusDead || repo.events[0].Attempts != 2 {
+		t.Errorf("event = %+v, want dead after 2 attempts", repo.events[0])
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 7, want: 30 * time.Second},
+		{attempt: 100, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			got := outbox.Backoff(tt.attempt, time.Second, 30*time.Second)
+			if got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
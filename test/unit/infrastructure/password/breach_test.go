@@ -0,0 +1,124 @@
+package password_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/password"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// breachedCorpus returns the hashes of a few breached passwords plus
+// filler entries so the binary search has to skip over other prefixes.
+func breachedCorpus() map[string]int {
+	corpus := map[string]int{
+		sha1Hex("password"):     9545824,
+		sha1Hex("hunter2"):      17043,
+		sha1Hex("rarely-seen"):  1,
+		sha1Hex("correcthorse"): 3,
+	}
+	for i := 0; i < 500; i++ {
+		corpus[sha1Hex(fmt.Sprintf("filler-%d", i))] = i + 1
+	}
+	return corpus
+}
+
+func writeSortedFile(t *testing.T, corpus map[string]int) string {
+	t.Helper()
+
+	hashes := make([]string, 0, len(corpus))
+	for hash := range corpus {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	var b strings.Builder
+	for _, hash := range hashes {
+		fmt.Fprintf(&b, "%s:%d\r\n", hash, corpus[hash])
+	}
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("failed to write corpus: %v", err)
+	}
+	return path
+}
+
+func writeRangeDir(t *testing.T, corpus map[string]int) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	ranges := make(map[string]*strings.Builder)
+	for hash, count := range corpus {
+		prefix := hash[:5]
+		if ranges[prefix] == nil {
+			ranges[prefix] = &strings.Builder{}
+		}
+		fmt.Fprintf(ranges[prefix], "%s:%d\n", hash[5:], count)
+	}
+	for prefix, b := range ranges {
+		if err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(b.String()), 0o600); err != nil {
+			t.Fatalf("failed to write range file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestBreachChecker(t *testing.T) {
+	layouts := map[string]func(*testing.T, map[string]int) string{
+		"sorted file":     writeSortedFile,
+		"range directory": writeRangeDir,
+	}
+
+	tests := []struct {
+		password string
+		minCount int
+		want     bool
+	}{
+		{password: "password", minCount: 1, want: true},
+		{password: "hunter2", minCount: 1, want: true},
+		{password: "filler-0", minCount: 1, want: true},
+		{password: "filler-499", minCount: 1, want: true},
+		{password: "rarely-seen", minCount: 1, want: true},
+		{password: "rarely-seen", minCount: 2, want: false},
+		{password: "not in the corpus", minCount: 1, want: false},
+	}
+
+	for layout, write := range layouts {
+		path := write(t, breachedCorpus())
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/%s/min %d", layout, tt.password, tt.minCount), func(t *testing.T) {
+				checker, err := password.NewBreachChecker(path, tt.minCount)
+				if err != nil {
+					t.Fatalf("NewBreachChecker() unexpected error: %v", err)
+				}
+
+				got, err := checker.IsBreached(context.Background(), tt.password)
+				if err != nil {
+					t.Fatalf("IsBreached() unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("IsBreached(%q) = %v, want %v", tt.password, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestNewBreachChecker_MissingPath(t *testing.T) {
+	if _, err := password.NewBreachChecker(filepath.Join(t.TempDir(), "missing.txt"), 1); err == nil {
+		t.Error("NewBreachChecker() expected error for a missing corpus")
+	}
+}
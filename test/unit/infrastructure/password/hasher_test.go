@@ -0,0 +1,145 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/password"
+)
+
+func testConfig(algorithm string) *config.PasswordConfig {
+	return &config.PasswordConfig{
+		Algorithm:         algorithm,
+		Argon2Memory:      8 * 1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+		Argon2SaltLength:  16,
+		Argon2KeyLength:   32,
+		BcryptCost:        4,
+	}
+}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		prefix    string
+	}{
+		{name: "argon2id", algorithm: config.PasswordAlgorithmArgon2id, prefix: "$argon2id$v=19$m=8192,t=1,p=1$"},
+		{name: "bcrypt", algorithm: config.PasswordAlgorithmBcrypt, prefix: "$2a$04$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := password.NewHasher(testConfig(tt.algorithm))
+
+			hash, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash() unexpected error: %v", err)
+			}
+
+			if !strings.HasPrefix(hash, tt.prefix) {
+				t.Errorf("Hash() = %q, want prefix %q", hash, tt.prefix)
+			}
+
+			ok, err := h.Verify("correct horse battery staple", hash)
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if !ok {
+				t.Error("Verify() should accept the correct password")
+			}
+
+			ok, err = h.Verify("wrong password", hash)
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("Verify() should reject a wrong password")
+			}
+		})
+	}
+}
+
+func TestHasher_HashUsesRandomSalt(t *testing.T) {
+	h := password.NewHasher(testConfig(config.PasswordAlgorithmArgon2id))
+
+	first, err := h.Hash("secret123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	second, err := h.Hash("secret123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	if first == second {
+		t.Error("Hash() should produce different hashes for the same password")
+	}
+}
+
+func TestHasher_VerifyAcrossAlgorithms(t *testing.T) {
+	bcryptHash, err := password.NewHasher(testConfig(config.PasswordAlgorithmBcrypt)).Hash("secret123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	h := password.NewHasher(testConfig(config.PasswordAlgorithmArgon2id))
+
+	ok, err := h.Verify("secret123", bcryptHash)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("Verify() should accept bcrypt hashes when argon2id is the primary algorithm")
+	}
+}
+
+func TestHasher_VerifyWithTunedParams(t *testing.T) {
+	oldCfg := testConfig(config.PasswordAlgorithmArgon2id)
+	hash, err := password.NewHasher(oldCfg).Hash("secret123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	newCfg := testConfig(config.PasswordAlgorithmArgon2id)
+	newCfg.Argon2Iterations = 2
+	newCfg.Argon2Memory = 16 * 1024
+
+	ok, err := password.NewHasher(newCfg).Verify("secret123", hash)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("Verify() should use the parameters encoded in the hash")
+	}
+}
+
+func TestHasher_VerifyInvalidHash(t *testing.T) {
+	h := password.NewHasher(testConfig(config.PasswordAlgorithmArgon2id))
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "empty", hash: ""},
+		{name: "plain text", hash: "secret123"},
+		{name: "unknown algorithm", hash: "$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA"},
+		{name: "truncated argon2id", hash: "$argon2id$v=19$m=8192,t=1,p=1$c2FsdA"},
+		{name: "bad argon2id params", hash: "$argon2id$v=19$m=x,t=1,p=1$c2FsdA$aGFzaA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := h.Verify("secret123", tt.hash)
+			if err == nil {
+				t.Errorf("Verify(%q) expected error, got nil", tt.hash)
+			}
+			if ok {
+				t.Errorf("Verify(%q) should not succeed", tt.hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package revocation_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/revocation"
+	"github.com/thanhnamdk2710/auth-service/internal/pkg/logger"
+)
+
+type memoryRevokedAccessTokenRepo struct {
+	mu      sync.Mutex
+	tokens  map[string]*entity.RevokedAccessToken
+	cutoffs map[string]*entity.AccessTokenCutoff
+}
+
+func newMemoryRepo() *memoryRevokedAccessTokenRepo {
+	return &memoryRevokedAccessTokenRepo{
+		tokens:  make(map[string]*entity.RevokedAccessToken),
+		cutoffs: make(map[string]*entity.AccessTokenCutoff),
+	}
+}
+
+func (r *memoryRevokedAccessTokenRepo) Create(ctx context.Context, token *entity.RevokedAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[token.ID]; !ok {
+		copied := *token
+		r.tokens[token.ID] = &copied
+	}
+	return nil
+}
+
+func (r *memoryRevokedAccessTokenRepo) ListActive(ctx context.Context, now time.Time) ([]*entity.RevokedAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tokens []*entity.RevokedAccessToken
+	for _, token := range r.tokens {
+		if !token.IsExpired(now) {
+			copied := *token
+			tokens = append(tokens, &copied)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryRevokedAccessTokenRepo) CreateCutoff(ctx context.Context, cutoff *entity.AccessTokenCutoff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *cutoff
+	r.cutoffs[cutoff.UserID] = &copied
+	return nil
+}
+
+func (r *memoryRevokedAccessTokenRepo) ListActiveCutoffs(ctx context.Context, now time.Time) ([]*entity.AccessTokenCutoff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var cutoffs []*entity.AccessTokenCutoff
+	for _, cutoff := range r.cutoffs {
+		if !cutoff.IsExpired(now) {
+			copied := *cutoff
+			cutoffs = append(cutoffs, &copied)
+		}
+	}
+	return cutoffs, nil
+}
+
+func (r *memoryRevokedAccessTokenRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, token := range r.tokens {
+		if token.IsExpired(now) {
+			delete(r.tokens, id)
+			deleted++
+		}
+	}
+	for userID, cutoff := range r.cutoffs {
+		if cutoff.IsExpired(now) {
+			delete(r.cutoffs, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newList(repo *memoryRevokedAccessTokenRepo) *revocation.List {
+	return revocation.NewList(repo, &logger.Logger{Logger: zap.NewNop()}, &config.RevocationConfig{
+		RefreshInterval:   time.Hour,
+		CleanupInterval:   time.Hour,
+		MaxAccessTokenTTL: 15 * time.Minute,
+	})
+}
+
+func token(jti string) *port.AccessTokenClaims {
+	return &port.AccessTokenClaims{ID: jti, UserID: "user-1", IssuedAt: time.Now()}
+}
+
+func TestList_Revoke(t *testing.T) {
+	repo := newMemoryRepo()
+	list := newList(repo)
+	ctx := context.Background()
+
+	if err := list.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	if err := list.Revoke(ctx, "jti-2", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+
+	if !list.IsRevoked(token("jti-1")) {
+		t.Error("IsRevoked() should report a token revoked on this instance at once")
+	}
+	if list.IsRevoked(token("jti-2")) || len(repo.tokens) != 1 {
+		t.Error("Revoke() should not record a token that has already expired")
+	}
+	if list.IsRevoked(token("jti-3")) {
+		t.Error("IsRevoked() should not report a token that was never revoked")
+	}
+}
+
+func TestList_RevokeUser(t *testing.T) {
+	repo := newMemoryRepo()
+	this, other := newList(repo), newList(repo)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	before := &port.AccessTokenClaims{ID: "jti-1", UserID: "user-1", IssuedAt: now.Add(-time.Minute).Truncate(time.Second)}
+	sameSecond := &port.AccessTokenClaims{ID: "jti-2", UserID: "user-1", IssuedAt: now.Truncate(time.Second)}
+	after := &port.AccessTokenClaims{ID: "jti-3", UserID: "user-1", IssuedAt: now.Add(time.Second).Truncate(time.Second)}
+	otherUser := &port.AccessTokenClaims{ID: "jti-4", UserID: "user-2", IssuedAt: before.IssuedAt}
+
+	if err := this.RevokeUser(ctx, "user-1", now); err != nil {
+		t.Fatalf("RevokeUser() unexpected error: %v", err)
+	}
+
+	if !this.IsRevoked(before) || !this.IsRevoked(sameSecond) {
+		t.Error("RevokeUser() should revoke the tokens the user was issued up to the cutoff")
+	}
+	if this.IsRevoked(after) {
+		t.Error("RevokeUser() should not revoke tokens issued after the cutoff")
+	}
+	if this.IsRevoked(otherUser) {
+		t.Error("RevokeUser() should not revoke other users' tokens")
+	}
+	if cutoff := repo.cutoffs["user-1"]; cutoff == nil || !cutoff.ExpiresAt.Equal(now.Add(15*time.Minute)) {
+		t.Errorf("stored cutoff = %+v, want one kept for the longest access token lifetime", cutoff)
+	}
+
+	if other.IsRevoked(before) {
+		t.Fatal("another instance's cutoff should not be seen before a reload")
+	}
+	if err := other.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if !other.IsRevoked(before) || other.IsRevoked(after) {
+		t.Error("Reload() should load cutoffs made by other instances")
+	}
+}
+
+func TestList_ReloadSharesRevocations(t *testing.T) {
+	repo := newMemoryRepo()
+	this, other := newList(repo), newList(repo)
+	ctx := context.Background()
+
+	if err := other.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	if this.IsRevoked(token("jti-1")) {
+		t.Fatal("another instance's revocation should not be seen before a reload")
+	}
+
+	if err := this.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if !this.IsRevoked(token("jti-1")) {
+		t.Error("Reload() should load revocations made by other instances")
+	}
+}
+
+func TestList_ReloadKeepsLocalRevocations(t *testing.T) {
+	repo := newMemoryRepo()
+	list := newList(repo)
+	ctx := context.Background()
+
+	if err := list.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+	// A reload whose query ran before the revocation was stored must not
+	// drop it.
+	repo.tokens = make(map[string]*entity.RevokedAccessToken)
+	if err := list.Reload(ctx); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if !list.IsRevoked(token("jti-1")) || list.Size() != 1 {
+		t.Error("Reload() should keep unexpired revocations held in memory")
+	}
+}
+
+func TestList_Prune(t *testing.T) {
+	repo := newMemoryRepo()
+	list := newList(repo)
+	ctx := context.Background()
+
+	expired := entity.NewRevokedAccessToken("jti-old", time.Now().Add(-time.Minute))
+	if err := repo.Create(ctx, expired); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	expiredCutoff := entity.NewAccessTokenCutoff("user-old", time.Now().Add(-time.Hour), 15*time.Minute)
+	if err := repo.CreateCutoff(ctx, expiredCutoff); err != nil {
+		t.Fatalf("CreateCutoff() unexpected error: %v", err)
+	}
+	if err := list.Revoke(ctx, "jti-new", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke() unexpected error: %v", err)
+	}
+
+	if err := list.Prune(ctx); err != nil {
+		t.Fatalf("Prune() unexpected error: %v", err)
+	}
+	if _, ok := repo.tokens["jti-old"]; ok {
+		t.Error("Prune() should delete the revocations of expired tokens")
+	}
+	if _, ok := repo.tokens["jti-new"]; !ok {
+		t.Error("Prune() should keep the revocations of live tokens")
+	}
+	if _, ok := repo.cutoffs["user-old"]; ok {
+		t.Error("Prune() should delete expired cutoffs")
+	}
+}
+
+func TestList_StartStop(t *testing.T) {
+	list := newList(newMemoryRepo())
+	list.Start()
+	list.Stop()
+	list.Stop()
+}
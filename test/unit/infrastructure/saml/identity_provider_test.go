@@ -0,0 +1,501 @@
+package saml_test
+
+import (
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/saml"
+	"github.com/thanhnamdk2710/auth-service/test/support/samlidp"
+)
+
+const (
+	testSPEntityID = "https://auth.example.com/saml/acme/metadata"
+	testACSURL     = "https://auth.example.com/saml/acme/acs"
+	testSSOURL     = "https://idp.example.com/sso"
+	testRequestID  = "_4fee3b046395c4e751011e97f8900b5273d56685"
+)
+
+func newIdentityProvider(idp *samlidp.IdP, certificates ...*x509.Certificate) *saml.IdentityProvider {
+	if len(certificates) == 0 {
+		certificates = []*x509.Certificate{idp.Certificate()}
+	}
+	return saml.NewIdentityProvider(saml.IdentityProviderConfig{
+		ID:                 "acme",
+		Name:               "Acme",
+		EntityID:           idp.EntityID,
+		SSOURL:             testSSOURL,
+		Certificates:       certificates,
+		UsernameAttributes: []string{"username"},
+		EmailAttributes:    []string{"email"},
+		SPEntityID:         testSPEntityID,
+		ACSURL:             testACSURL,
+		ClockSkew:          2 * time.Minute,
+	})
+}
+
+func response() samlidp.Response {
+	return samlidp.Response{ACSURL: testACSURL, Audience: testSPEntityID, InResponseTo: testRequestID}
+}
+
+var signatureRegex = regexp.MustCompile(`<ds:Signature .*?</ds:Signature>`)
+
+func TestIdentityProvider_AcceptsSignedResponses(t *testing.T) {
+	for _, signResponse := range []bool{false, true} {
+		idp := samlidp.New(t)
+		r := response()
+		r.SignResponse = signResponse
+		issuedAt := time.Now()
+		r.IssuedAt = issuedAt
+
+		assertion, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+		if err != nil {
+			t.Fatalf("ParseResponse() signing the response %v unexpected error: %v", signResponse, err)
+		}
+		want := port.ExternalIdentity{Subject: "00u1a2b3c4d5e6f7", Email: "jane.doe@example.com", EmailVerified: true, PreferredUsername: "jane.doe"}
+		if assertion.Identity != want {
+			t.Errorf("Identity = %+v, want %+v", assertion.Identity, want)
+		}
+		if assertion.InResponseTo != testRequestID || assertion.ID == "" {
+			t.Errorf("assertion = %+v, want its ID and the request it answers", assertion)
+		}
+		if want := issuedAt.Add(7 * time.Minute).Truncate(time.Second); !assertion.ExpiresAt.Equal(want) {
+			t.Errorf("ExpiresAt = %v, want the end of the validity window plus clock skew, %v", assertion.ExpiresAt, want)
+		}
+	}
+}
+
+// TestIdentityProvider_CanonicalizesBeforeVerifying serializes signed
+// responses the way other XML writers might; none of it is signed
+// content, so the signatures still hold.
+func TestIdentityProvider_CanonicalizesBeforeVerifying(t *testing.T) {
+	reserialize := func(document string) string {
+		const declaration = ` xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"`
+		document = strings.ReplaceAll(document, declaration, "")
+		document = strings.Replace(document, `<samlp:Response `, `<samlp:Response`+declaration+` `, 1)
+		document = regexp.MustCompile(`(<saml:Assertion) (ID="[^"]*") (IssueInstant="[^"]*") (Version="2.0")`).
+			ReplaceAllString(document, `$1 $4 $3 $2`)
+		document = regexp.MustCompile(`(<ds:Transform Algorithm="[^"]*")></ds:Transform>`).ReplaceAllString(document, `$1/>`)
+		document = strings.Replace(document, `Version="2.0">`, `Version='2.0'><!-- signed by samlidp -->`, 1)
+		return `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + document + "\n"
+	}
+
+	for _, signResponse := range []bool{false, true} {
+		idp := samlidp.New(t)
+		r := response()
+		r.SignResponse = signResponse
+		r.Hook = reserialize
+
+		if _, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now()); err != nil {
+			t.Errorf("ParseResponse() signing the response %v unexpected error: %v", signResponse, err)
+		}
+	}
+}
+
+// TestIdentityProvider_Comments splits signed text with comments, which
+// the signature does not cover. Values are read whole rather than up to
+// the comment, so an account at the identity provider named
+// jane.doe@example.com.evil.test cannot pass for jane.doe@example.com.
+func TestIdentityProvider_Comments(t *testing.T) {
+	for _, signResponse := range []bool{false, true} {
+		idp := samlidp.New(t)
+		idp.User.Attributes[0].Value = "jane.doe@example.com.evil.test"
+		r := response()
+		r.SignResponse = signResponse
+		r.Hook = func(d string) string {
+			d = strings.Replace(d, "00u1a2b3c4d5e6f7", "00u1a2b3<!---->c4d5e6f7", 1)
+			d = strings.Replace(d, "jane.doe@example.com.evil.test", "jane.doe@example.com<!--.evil.test-->.evil.test", 1)
+			d = strings.Replace(d, "<saml:Subject>", "<!-- subject --><saml:Subject><!-- name -->", 1)
+			return strings.Replace(d, "<ds:DigestValue>", "<ds:DigestValue><!-- digest -->", 1)
+		}
+
+		assertion, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+		if err != nil {
+			t.Fatalf("ParseResponse() signing the response %v unexpected error: %v", signResponse, err)
+		}
+		if assertion.Identity.Subject != "00u1a2b3c4d5e6f7" || assertion.Identity.Email != "jane.doe@example.com.evil.test" {
+			t.Errorf("Identity = %+v, want the subject and email read across the comments", assertion.Identity)
+		}
+	}
+}
+
+// TestIdentityProvider_ProcessingInstructions signs a processing
+// instruction, which exclusive canonicalization keeps, so one added after
+// signing is rejected with the other tampering.
+func TestIdentityProvider_ProcessingInstructions(t *testing.T) {
+	idp := samlidp.New(t)
+	r := response()
+	r.AssertionHook = func(a string) string {
+		return strings.Replace(a, "<saml:Subject>", "<saml:Subject><?idp note ?><?idp?>", 1)
+	}
+
+	if _, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now()); err != nil {
+		t.Errorf("ParseResponse() unexpected error: %v", err)
+	}
+}
+
+// TestIdentityProvider_Namespaces declares namespaces inside the signed
+// assertion. Declarations that change nothing are left out of the signed
+// form; a prefix bound to another namespace is signed with it and read
+// with it.
+func TestIdentityProvider_Namespaces(t *testing.T) {
+	const (
+		xsNamespace  = "http://www.w3.org/2001/XMLSchema"
+		xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	)
+
+	t.Run("redundant declarations", func(t *testing.T) {
+		idp := samlidp.New(t)
+		r := response()
+		r.Hook = func(d string) string {
+			d = strings.Replace(d, "<saml:Subject>", `<saml:Subject xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" xmlns="urn:unused">`, 1)
+			return strings.Replace(d, "<saml:NameID ", `<saml:NameID xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" `, 1)
+		}
+
+		if _, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now()); err != nil {
+			t.Errorf("ParseResponse() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("typed attribute values", func(t *testing.T) {
+		idp := samlidp.New(t)
+		r := response()
+		// The xs prefix appears only in an attribute value, so the
+		// signed form leaves its declaration out.
+		r.AssertionHook = func(a string) string {
+			return strings.ReplaceAll(a, "<saml:AttributeValue>", `<saml:AttributeValue xmlns:xsi="`+xsiNamespace+`" xsi:type="xs:string">`)
+		}
+		r.Hook = func(d string) string {
+			return strings.Replace(d, "<samlp:Response ", `<samlp:Response xmlns:xs="`+xsNamespace+`" xmlns:xsi="`+xsiNamespace+`" `, 1)
+		}
+
+		assertion, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+		if err != nil {
+			t.Fatalf("ParseResponse() unexpected error: %v", err)
+		}
+		if assertion.Identity.Email != "jane.doe@example.com" {
+			t.Errorf("Email = %q, want the typed attribute value", assertion.Identity.Email)
+		}
+	})
+
+	t.Run("prefix rebound by the identity provider", func(t *testing.T) {
+		idp := samlidp.New(t)
+		r := response()
+		r.AssertionHook = func(a string) string {
+			return strings.Replace(a, "<saml:AttributeStatement>", `<saml:AttributeStatement xmlns:saml="urn:example:other">`, 1)
+		}
+
+		assertion, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+		if err != nil {
+			t.Fatalf("ParseResponse() unexpected error: %v", err)
+		}
+		if assertion.Identity.Email != "" || assertion.Identity.PreferredUsername != "" {
+			t.Errorf("Identity = %+v, want no attributes from another namespace", assertion.Identity)
+		}
+	})
+
+	tampered := []struct {
+		name string
+		hook func(d string) string
+	}{
+		{name: "prefix rebound", hook: func(d string) string {
+			return strings.Replace(d, "<saml:AttributeStatement>", `<saml:AttributeStatement xmlns:saml="urn:example:other">`, 1)
+		}},
+		{name: "other prefix for the same namespace", hook: func(d string) string {
+			d = strings.Replace(d, "<saml:NameID ", `<evil:NameID xmlns:evil="urn:oasis:names:tc:SAML:2.0:assertion" `, 1)
+			return strings.Replace(d, "</saml:NameID>", "</evil:NameID>", 1)
+		}},
+		{name: "default namespace put to use", hook: func(d string) string {
+			d = strings.Replace(d, "<saml:Subject>", `<Subject xmlns="urn:oasis:names:tc:SAML:2.0:assertion">`, 1)
+			return strings.Replace(d, "</saml:Subject>", "</Subject>", 1)
+		}},
+	}
+	for _, tt := range tampered {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, signResponse := range []bool{false, true} {
+				idp := samlidp.New(t)
+				r := response()
+				r.SignResponse = signResponse
+				r.Hook = tt.hook
+
+				_, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+				if !errors.Is(err, saml.ErrInvalidResponse) || !strings.Contains(err.Error(), "digest mismatch") {
+					t.Errorf("ParseResponse() signing the response %v expected a digest mismatch, got %v", signResponse, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIdentityProvider_RejectsResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(idp *samlidp.IdP, r *samlidp.Response)
+	}{
+		{name: "tampered subject", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Hook = func(d string) string { return strings.Replace(d, "00u1a2b3c4d5e6f7", "00uADMIN", 1) }
+		}},
+		{name: "tampered signed response", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.SignResponse = true
+			r.Hook = func(d string) string { return strings.Replace(d, "jane.doe@example.com", "admin@example.com", 1) }
+		}},
+		{name: "unsigned", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Hook = func(d string) string { return signatureRegex.ReplaceAllString(d, "") }
+		}},
+		{name: "SHA-1 signature", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Hook = func(d string) string {
+				return strings.Replace(d, "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256", "http://www.w3.org/2000/09/xmldsig#rsa-sha1", 1)
+			}
+		}},
+		{name: "other issuer", modify: func(idp *samlidp.IdP, _ *samlidp.Response) {
+			idp.EntityID = "https://evil.example.com/saml"
+		}},
+		{name: "other audience", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Audience = "https://other-sp.example.com"
+		}},
+		{name: "other recipient", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.ACSURL = "https://other-sp.example.com/acs"
+			// The destination is not signed; only the recipient is wrong.
+			r.Hook = func(d string) string {
+				return strings.Replace(d, `Destination="`+r.ACSURL, `Destination="`+testACSURL, 1)
+			}
+		}},
+		{name: "expired", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.IssuedAt = time.Now().Add(-10 * time.Minute)
+		}},
+		{name: "issued in the future", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.IssuedAt = time.Now().Add(5 * time.Minute)
+		}},
+		{name: "failed status", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Status = samlidp.StatusRequester
+		}},
+		{name: "transient NameID", modify: func(idp *samlidp.IdP, _ *samlidp.Response) {
+			idp.User.NameIDFormat = samlidp.NameIDTransient
+		}},
+		{name: "processing instruction in the signed assertion", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Hook = func(d string) string {
+				return strings.Replace(d, "<saml:Subject>", "<saml:Subject><?idp ignore-conditions?>", 1)
+			}
+		}},
+		{name: "processing instruction in the signed response", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.SignResponse = true
+			r.Hook = func(d string) string {
+				return strings.Replace(d, "<samlp:Status>", "<?idp ignore-conditions?><samlp:Status>", 1)
+			}
+		}},
+		{name: "document type declaration", modify: func(_ *samlidp.IdP, r *samlidp.Response) {
+			r.Hook = func(d string) string { return `<!DOCTYPE Response [<!ENTITY x "x">]>` + d }
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := samlidp.New(t)
+			provider := newIdentityProvider(idp)
+			r := response()
+			tt.modify(idp, &r)
+
+			_, err := provider.ParseResponse(idp.Respond(r), time.Now())
+			if !errors.Is(err, saml.ErrInvalidResponse) {
+				t.Errorf("ParseResponse() expected error %v, got %v", saml.ErrInvalidResponse, err)
+			}
+		})
+	}
+}
+
+func TestIdentityProvider_RejectsOtherKeys(t *testing.T) {
+	idp := samlidp.New(t)
+	impostor := samlidp.New(t)
+
+	_, err := newIdentityProvider(idp).ParseResponse(impostor.Respond(response()), time.Now())
+	if !errors.Is(err, saml.ErrInvalidResponse) {
+		t.Errorf("ParseResponse() expected error %v for a key that is not configured, got %v", saml.ErrInvalidResponse, err)
+	}
+
+	rolledOver := newIdentityProvider(idp, impostor.Certificate(), idp.Certificate())
+	if _, err := rolledOver.ParseResponse(idp.Respond(response()), time.Now()); err != nil {
+		t.Errorf("ParseResponse() with any configured certificate unexpected error: %v", err)
+	}
+}
+
+// TestIdentityProvider_RejectsSignatureWrapping moves a genuine signed
+// assertion out of the way of a forged one, as signature wrapping attacks
+// do.
+func TestIdentityProvider_RejectsSignatureWrapping(t *testing.T) {
+	assertionRegex := regexp.MustCompile(`<saml:Assertion .*</saml:Assertion>`)
+	forge := func(signed string) string {
+		forged := signatureRegex.ReplaceAllString(signed, "")
+		return strings.Replace(forged, "00u1a2b3c4d5e6f7", "00uADMIN", 1)
+	}
+
+	// another is a second assertion the identity provider signed, as for
+	// another sign-in.
+	idp := samlidp.New(t)
+	var another string
+	earlier := response()
+	earlier.Hook = func(document string) string {
+		another = assertionRegex.FindString(document)
+		return document
+	}
+	idp.Respond(earlier)
+
+	tests := []struct {
+		name string
+		wrap func(document, signed string) string
+	}{
+		{name: "second assertion before", wrap: func(document, signed string) string {
+			return strings.Replace(document, signed, forge(signed)+signed, 1)
+		}},
+		{name: "second assertion after", wrap: func(document, signed string) string {
+			return strings.Replace(document, signed, signed+forge(signed), 1)
+		}},
+		{name: "second signed assertion", wrap: func(document, signed string) string {
+			return strings.Replace(document, signed, signed+another, 1)
+		}},
+		{name: "genuine assertion in the advice of a forged one", wrap: func(document, signed string) string {
+			forged := forge(signed)
+			forged = strings.Replace(forged, "</saml:Assertion>", "<saml:Advice>"+signed+"</saml:Advice></saml:Assertion>", 1)
+			return strings.Replace(document, signed, forged, 1)
+		}},
+		{name: "genuine assertion in extensions", wrap: func(document, signed string) string {
+			extensions := `<samlp:Extensions>` + signed + `</samlp:Extensions>`
+			return strings.Replace(document, `<samlp:Status>`, extensions+`<samlp:Status>`, 1)
+		}},
+		{name: "forged assertion carrying the signature", wrap: func(document, signed string) string {
+			forged := strings.Replace(signed, "00u1a2b3c4d5e6f7", "00uADMIN", 1)
+			extensions := `<samlp:Extensions>` + signed + `</samlp:Extensions>`
+			document = strings.Replace(document, signed, forged, 1)
+			return strings.Replace(document, `<samlp:Status>`, extensions+`<samlp:Status>`, 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := response()
+			r.Hook = func(document string) string {
+				signed := assertionRegex.FindString(document)
+				if tt.name == "genuine assertion in extensions" {
+					document = strings.Replace(document, signed, forge(signed), 1)
+				}
+				return tt.wrap(document, signed)
+			}
+
+			_, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now())
+			if !errors.Is(err, saml.ErrInvalidResponse) {
+				t.Errorf("ParseResponse() expected error %v, got %v", saml.ErrInvalidResponse, err)
+			}
+		})
+	}
+}
+
+func TestIdentityProvider_ClockSkew(t *testing.T) {
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+	}{
+		{name: "IdP clock ahead", issuedAt: time.Now().Add(time.Minute)},
+		{name: "expired within the skew", issuedAt: time.Now().Add(-6 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := samlidp.New(t)
+			r := response()
+			r.IssuedAt = tt.issuedAt
+
+			if _, err := newIdentityProvider(idp).ParseResponse(idp.Respond(r), time.Now()); err != nil {
+				t.Errorf("ParseResponse() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestIdentityProvider_AttributeMapping(t *testing.T) {
+	idp := samlidp.New(t)
+	idp.User = samlidp.User{
+		NameID:       "jdoe@corp.example.com",
+		NameIDFormat: "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		Attributes: []samlidp.Attribute{
+			{Name: "urn:oid:0.9.2342.19200300.100.1.1", Value: "jdoe"},
+		},
+	}
+	provider := saml.NewIdentityProvider(saml.IdentityProviderConfig{
+		EntityID:           idp.EntityID,
+		Certificates:       []*x509.Certificate{idp.Certificate()},
+		UsernameAttributes: []string{"username", "urn:oid:0.9.2342.19200300.100.1.1"},
+		EmailAttributes:    []string{"email"},
+		SPEntityID:         testSPEntityID,
+		ACSURL:             testACSURL,
+	})
+
+	assertion, err := provider.ParseResponse(idp.Respond(response()), time.Now())
+	if err != nil {
+		t.Fatalf("ParseResponse() unexpected error: %v", err)
+	}
+	want := port.ExternalIdentity{Subject: "jdoe@corp.example.com", Email: "jdoe@corp.example.com", EmailVerified: true, PreferredUsername: "jdoe"}
+	if assertion.Identity != want {
+		t.Errorf("Identity = %+v, want the username from the fallback attribute and the email from the NameID, %+v", assertion.Identity, want)
+	}
+}
+
+func TestIdentityProvider_AuthnRequest(t *testing.T) {
+	provider := newIdentityProvider(samlidp.New(t))
+
+	request, err := provider.AuthnRequest(testRequestID, time.Now())
+	if err != nil {
+		t.Fatalf("AuthnRequest() unexpected error: %v", err)
+	}
+	if request.URL != testSSOURL {
+		t.Errorf("URL = %q, want %q", request.URL, testSSOURL)
+	}
+
+	got := samlidp.ParseRequest(t, request.SAMLRequest)
+	want := samlidp.AuthnRequest{
+		ID:                          testRequestID,
+		Destination:                 testSSOURL,
+		AssertionConsumerServiceURL: testACSURL,
+		ProtocolBinding:             "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST",
+		Issuer:                      testSPEntityID,
+	}
+	if got != want {
+		t.Errorf("AuthnRequest = %+v, want %+v", got, want)
+	}
+}
+
+func TestIdentityProvider_Metadata(t *testing.T) {
+	var metadata struct {
+		EntityID   string `xml:"entityID,attr"`
+		Descriptor struct {
+			WantAssertionsSigned bool `xml:"WantAssertionsSigned,attr"`
+			ACS                  []struct {
+				Binding  string `xml:"Binding,attr"`
+				Location string `xml:"Location,attr"`
+			} `xml:"AssertionConsumerService"`
+		} `xml:"SPSSODescriptor"`
+	}
+	if err := xml.Unmarshal(newIdentityProvider(samlidp.New(t)).Metadata(), &metadata); err != nil {
+		t.Fatalf("Metadata() is not XML: %v", err)
+	}
+
+	if metadata.EntityID != testSPEntityID || !metadata.Descriptor.WantAssertionsSigned {
+		t.Errorf("metadata = %+v, want the service provider entity ID wanting signed assertions", metadata)
+	}
+	acs := metadata.Descriptor.ACS
+	if len(acs) != 1 || acs[0].Location != testACSURL || acs[0].Binding != "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" {
+		t.Errorf("AssertionConsumerService = %+v, want the HTTP-POST endpoint %s", acs, testACSURL)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	provider := newIdentityProvider(samlidp.New(t))
+	registry := saml.NewRegistry(provider)
+
+	if registry.Find("acme") != provider || registry.Find("missing") != nil {
+		t.Error("Find() should look identity providers up by ID")
+	}
+}
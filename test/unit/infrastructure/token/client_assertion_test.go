@@ -0,0 +1,156 @@
+package token_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+func keySet(t *testing.T, keys ...port.JSONWebKey) string {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	return string(raw)
+}
+
+func signAssertion(t *testing.T, key *keystore.Key, kid string, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(key.Method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	signed, err := tok.SignedString(key.Private)
+	if err != nil {
+		t.Fatalf("SignedString() unexpected error: %v", err)
+	}
+	return signed
+}
+
+func assertionClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Issuer:    "client",
+		Subject:   "client",
+		Audience:  jwt.ClaimStrings{"https://auth.example.com/oauth/token"},
+		ID:        "jti-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestClientAssertionVerifier_Verify(t *testing.T) {
+	verifier := token.NewClientAssertionVerifier()
+
+	for _, alg := range []string{entity.SigningAlgRS256, entity.SigningAlgES256, entity.SigningAlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			key := generateKey(t, alg)
+			jwks := keySet(t, key.JWK())
+
+			assertion, err := verifier.Verify(signAssertion(t, key, key.ID, assertionClaims()), jwks)
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if assertion.Subject != "client" || assertion.ID != "jti-1" || len(assertion.Audience) != 1 {
+				t.Errorf("Verify() = %+v, want the assertion's claims", assertion)
+			}
+		})
+	}
+
+	t.Run("without kid", func(t *testing.T) {
+		other, key := generateKey(t, entity.SigningAlgES256), generateKey(t, entity.SigningAlgES256)
+		jwks := keySet(t, other.JWK(), key.JWK())
+
+		if _, err := verifier.Verify(signAssertion(t, key, "", assertionClaims()), jwks); err != nil {
+			t.Errorf("Verify() should try every key when the assertion names none: %v", err)
+		}
+	})
+}
+
+func TestClientAssertionVerifier_VerifyRejects(t *testing.T) {
+	verifier := token.NewClientAssertionVerifier()
+	key := generateKey(t, entity.SigningAlgES256)
+	jwks := keySet(t, key.JWK())
+
+	expired := assertionClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noExpiry := assertionClaims()
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name      string
+		assertion string
+	}{
+		{"unregistered key", signAssertion(t, generateKey(t, entity.SigningAlgES256), "", assertionClaims())},
+		{"unknown kid", signAssertion(t, key, "other", assertionClaims())},
+		{"expired", signAssertion(t, key, key.ID, expired)},
+		{"no expiry", signAssertion(t, key, key.ID, noExpiry)},
+		{"malformed", "not-a-jwt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(tt.assertion, jwks); err == nil {
+				t.Error("Verify() should reject the assertion")
+			}
+		})
+	}
+}
+
+func TestClientAssertionVerifier_ValidateKeySet(t *testing.T) {
+	verifier := token.NewClientAssertionVerifier()
+
+	if err := verifier.ValidateKeySet(keySet(t, generateKey(t, entity.SigningAlgRS256).JWK())); err != nil {
+		t.Errorf("ValidateKeySet() unexpected error: %v", err)
+	}
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() unexpected error: %v", err)
+	}
+	encryption := generateKey(t, entity.SigningAlgES256).JWK()
+	encryption.Use = "enc"
+	wrongAlg := generateKey(t, entity.SigningAlgES256).JWK()
+	wrongAlg.Algorithm = entity.SigningAlgRS256
+	offCurve := generateKey(t, entity.SigningAlgES256).JWK()
+	offCurve.Y = offCurve.X
+
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{"not JSON", "{"},
+		{"no keys", `{"keys":[]}`},
+		{"small RSA key", keySet(t, rsaJWK(&small.PublicKey))},
+		{"P-384 key", `{"keys":[{"kty":"EC","crv":"P-384","x":"AQ","y":"AQ"}]}`},
+		{"encryption key", keySet(t, encryption)},
+		{"mismatched alg", keySet(t, wrongAlg)},
+		{"point off the curve", keySet(t, offCurve)},
+		{"symmetric key", `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifier.ValidateKeySet(tt.jwks); !errors.Is(err, token.ErrInvalidKeySet) {
+				t.Errorf("ValidateKeySet() expected error %v, got %v", token.ErrInvalidKeySet, err)
+			}
+		})
+	}
+}
+
+func rsaJWK(public *rsa.PublicKey) port.JSONWebKey {
+	return port.JSONWebKey{
+		KeyType: "RSA",
+		N:       base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}
+}
@@ -0,0 +1,141 @@
+package token_test
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+const testIssuer = "https://auth.example.com"
+
+func TestIDTokenSigner_Sign(t *testing.T) {
+	sha256Half := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return base64.RawURLEncoding.EncodeToString(sum[:16])
+	}
+	sha512Half := func(s string) string {
+		sum := sha512.Sum512([]byte(s))
+		return base64.RawURLEncoding.EncodeToString(sum[:32])
+	}
+
+	tests := []struct {
+		algorithm string
+		atHash    string
+	}{
+		{entity.SigningAlgRS256, sha256Half("access-token")},
+		{entity.SigningAlgES256, sha256Half("access-token")},
+		{entity.SigningAlgEdDSA, sha512Half("access-token")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			key := generateKey(t, tt.algorithm)
+			signer := token.NewIDTokenSigner(staticKeys{key}, testIssuer, time.Hour)
+
+			authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+			signed, err := signer.SignIDToken(port.IDTokenClaims{
+				Subject:     "user-1",
+				Audience:    "client-1",
+				Nonce:       "n-0S6_WzA2Mj",
+				AuthTime:    authTime,
+				AccessToken: "access-token",
+				UserClaims:  map[string]interface{}{"email": "user@example.com", "sub": "ignored"},
+			})
+			if err != nil {
+				t.Fatalf("SignIDToken() unexpected error: %v", err)
+			}
+
+			parsed, err := jwt.Parse(signed, func(tok *jwt.Token) (interface{}, error) {
+				if tok.Header["kid"] != key.ID {
+					t.Errorf("kid = %v, want %s", tok.Header["kid"], key.ID)
+				}
+				return key.Public, nil
+			}, jwt.WithValidMethods([]string{tt.algorithm}), jwt.WithIssuer(testIssuer), jwt.WithAudience("client-1"))
+			if err != nil {
+				t.Fatalf("ID token does not verify: %v", err)
+			}
+
+			claims := parsed.Claims.(jwt.MapClaims)
+			if claims["sub"] != "user-1" {
+				t.Errorf("sub = %v, user claims must not override it", claims["sub"])
+			}
+			if claims["nonce"] != "n-0S6_WzA2Mj" || claims["email"] != "user@example.com" {
+				t.Errorf("claims = %v, want the nonce and user claims", claims)
+			}
+			if claims["auth_time"] != float64(authTime.Unix()) {
+				t.Errorf("auth_time = %v, want %d", claims["auth_time"], authTime.Unix())
+			}
+			if claims["at_hash"] != tt.atHash {
+				t.Errorf("at_hash = %v, want %s", claims["at_hash"], tt.atHash)
+			}
+		})
+	}
+}
+
+func TestIDTokenSigner_VerifyIDTokenHint(t *testing.T) {
+	key := generateKey(t, entity.SigningAlgES256)
+	signer := token.NewIDTokenSigner(staticKeys{key}, testIssuer, -time.Hour)
+
+	expired, err := signer.SignIDToken(port.IDTokenClaims{Subject: "user-1", Audience: "client-1", SessionID: "session-1"})
+	if err != nil {
+		t.Fatalf("SignIDToken() unexpected error: %v", err)
+	}
+
+	hint, err := signer.VerifyIDTokenHint(expired)
+	if err != nil {
+		t.Fatalf("VerifyIDTokenHint() should accept an expired ID token: %v", err)
+	}
+	if hint.Subject != "user-1" || hint.Audience != "client-1" || hint.SessionID != "session-1" {
+		t.Errorf("VerifyIDTokenHint() = %+v, want the subject, client and sid", hint)
+	}
+
+	otherIssuer, _ := token.NewIDTokenSigner(staticKeys{key}, "https://elsewhere.example.com", time.Hour).
+		SignIDToken(port.IDTokenClaims{Subject: "user-1", Audience: "client-1"})
+	unknownKey, _ := token.NewIDTokenSigner(staticKeys{generateKey(t, entity.SigningAlgES256)}, testIssuer, time.Hour).
+		SignIDToken(port.IDTokenClaims{Subject: "user-1", Audience: "client-1"})
+
+	for name, tok := range map[string]string{"garbage": "not-a-jwt", "other issuer": otherIssuer, "unknown key": unknownKey} {
+		if _, err := signer.VerifyIDTokenHint(tok); err != token.ErrInvalidToken {
+			t.Errorf("%s: VerifyIDTokenHint() expected error %v, got %v", name, token.ErrInvalidToken, err)
+		}
+	}
+}
+
+func TestIDTokenSigner_SignLogoutToken(t *testing.T) {
+	key := generateKey(t, entity.SigningAlgRS256)
+	signer := token.NewIDTokenSigner(staticKeys{key}, testIssuer, time.Hour)
+
+	signed, err := signer.SignLogoutToken(port.LogoutTokenClaims{Subject: "user-1", Audience: "client-1", SessionID: "session-1"})
+	if err != nil {
+		t.Fatalf("SignLogoutToken() unexpected error: %v", err)
+	}
+
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return key.Public, nil },
+		jwt.WithValidMethods([]string{entity.SigningAlgRS256}), jwt.WithIssuer(testIssuer), jwt.WithAudience("client-1"))
+	if err != nil {
+		t.Fatalf("logout token does not verify: %v", err)
+	}
+	if parsed.Header["typ"] != "logout+jwt" {
+		t.Errorf("typ = %v, want logout+jwt", parsed.Header["typ"])
+	}
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	if claims["sub"] != "user-1" || claims["sid"] != "session-1" || claims["jti"] == nil {
+		t.Errorf("claims = %v, want sub, sid and jti", claims)
+	}
+	if _, ok := claims["nonce"]; ok {
+		t.Error("a logout token must not carry a nonce")
+	}
+	events, _ := claims["events"].(map[string]interface{})
+	if _, ok := events["http://schemas.openid.net/event/backchannel-logout"]; !ok {
+		t.Errorf("events = %v, want the back-channel logout event", claims["events"])
+	}
+}
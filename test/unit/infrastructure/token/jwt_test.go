@@ -0,0 +1,302 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/config"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+func testConfig() *config.JWTConfig {
+	return &config.JWTConfig{
+		Issuer:         "test-issuer",
+		Audience:       "test-audience",
+		AccessTokenTTL: 15 * time.Minute,
+	}
+}
+
+func TestJWTService_GenerateAndParse(t *testing.T) {
+	svc := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)})
+
+	before := time.Now()
+	signed, expiresAt, err := svc.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:   "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f",
+		Username: "testuser",
+		Roles:    []string{"admin", "support"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	if expiresAt.Before(before.Add(14*time.Minute)) || expiresAt.After(before.Add(16*time.Minute)) {
+		t.Errorf("GenerateAccessToken() expiresAt = %v, want about 15 minutes from now", expiresAt)
+	}
+
+	claims, err := svc.ParseAccessToken(signed)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() unexpected error: %v", err)
+	}
+
+	if claims.UserID != "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f" {
+		t.Errorf("claims.UserID = %q, want %q", claims.UserID, "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f")
+	}
+	if claims.Username != "testuser" {
+		t.Errorf("claims.Username = %q, want %q", claims.Username, "testuser")
+	}
+	if len(claims.Roles) != 2 || claims.Roles[0] != "admin" || claims.Roles[1] != "support" {
+		t.Errorf("claims.Roles = %v, want [admin support]", claims.Roles)
+	}
+	if claims.ID == "" || !claims.ExpiresAt.Equal(expiresAt.Truncate(time.Second)) || claims.IssuedAt.IsZero() {
+		t.Errorf("claims = %+v, want the token's jti, iat and exp", claims)
+	}
+}
+
+func TestJWTService_ClientClaims(t *testing.T) {
+	svc := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)})
+
+	signed, _, err := svc.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:   "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f",
+		ClientID: "client",
+		Scopes:   []string{"profile", "email"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	claims, err := svc.ParseAccessToken(signed)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() unexpected error: %v", err)
+	}
+
+	if claims.ClientID != "client" {
+		t.Errorf("claims.ClientID = %q, want %q", claims.ClientID, "client")
+	}
+	if len(claims.Scopes) != 2 || claims.Scopes[0] != "profile" || claims.Scopes[1] != "email" {
+		t.Errorf("claims.Scopes = %v, want [profile email]", claims.Scopes)
+	}
+}
+
+func TestJWTService_ClientOwnToken(t *testing.T) {
+	svc := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)})
+
+	before := time.Now()
+	signed, expiresAt, err := svc.GenerateAccessToken(port.AccessTokenClaims{
+		ClientID: "client",
+		Scopes:   []string{"profile"},
+		TTL:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+	if expiresAt.Before(before.Add(59*time.Minute)) || expiresAt.After(before.Add(61*time.Minute)) {
+		t.Errorf("GenerateAccessToken() expiresAt = %v, want the client's TTL of an hour", expiresAt)
+	}
+
+	var raw jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(signed, &raw); err != nil {
+		t.Fatalf("ParseUnverified() unexpected error: %v", err)
+	}
+	if raw.Subject != "client" {
+		t.Errorf("sub = %q, want the client ID", raw.Subject)
+	}
+
+	claims, err := svc.ParseAccessToken(signed)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() unexpected error: %v", err)
+	}
+	if claims.UserID != "" || claims.ClientID != "client" {
+		t.Errorf("claims = %+v, want a client token without a user", claims)
+	}
+}
+
+func TestJWTService_ExchangedToken(t *testing.T) {
+	svc := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)})
+
+	notAfter := time.Now().Add(time.Minute).Truncate(time.Second)
+	actor := &entity.TokenActor{Subject: "gateway", ClientID: "gateway", Actor: &entity.TokenActor{Subject: "edge"}}
+	signed, expiresAt, err := svc.GenerateAccessToken(port.AccessTokenClaims{
+		UserID:    "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f",
+		ClientID:  "gateway",
+		Audience:  []string{"orders-api"},
+		Actor:     actor,
+		ExpiresAt: notAfter,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+	if !expiresAt.Equal(notAfter) {
+		t.Errorf("GenerateAccessToken() expiresAt = %v, want it capped at %v", expiresAt, notAfter)
+	}
+
+	if _, err := svc.ParseAccessToken(signed); err != token.ErrInvalidToken {
+		t.Errorf("ParseAccessToken() expected error %v for another audience, got %v", token.ErrInvalidToken, err)
+	}
+
+	claims, err := svc.InspectAccessToken(signed)
+	if err != nil {
+		t.Fatalf("InspectAccessToken() unexpected error: %v", err)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "orders-api" {
+		t.Errorf("claims.Audience = %v, want [orders-api]", claims.Audience)
+	}
+	if claims.Actor == nil || claims.Actor.Subject != "gateway" || claims.Actor.ClientID != "gateway" ||
+		claims.Actor.Actor == nil || claims.Actor.Actor.Subject != "edge" || claims.Actor.Actor.Actor != nil {
+		t.Errorf("claims.Actor = %+v, want the gateway acting for the edge", claims.Actor)
+	}
+
+	var raw struct {
+		Act map[string]interface{} `json:"act"`
+		jwt.RegisteredClaims
+	}
+	if _, _, err := jwt.NewParser().ParseUnverified(signed, &raw); err != nil {
+		t.Fatalf("ParseUnverified() unexpected error: %v", err)
+	}
+	if raw.Act["sub"] != "gateway" || raw.Act["act"] == nil {
+		t.Errorf("act = %v, want the nested actor claim", raw.Act)
+	}
+}
+
+func TestJWTService_ParseRejectsInvalidTokens(t *testing.T) {
+	keys := staticKeys{generateKey(t, entity.SigningAlgES256)}
+	svc := token.NewJWTService(testConfig(), keys)
+
+	unknownKey, _, _ := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)}).
+		GenerateAccessToken(port.AccessTokenClaims{UserID: "u"})
+
+	otherCfg := testConfig()
+	otherCfg.Audience = "someone-else"
+	wrongAudience, _, _ := token.NewJWTService(otherCfg, keys).GenerateAccessToken(port.AccessTokenClaims{UserID: "u"})
+
+	otherCfg = testConfig()
+	otherCfg.AccessTokenTTL = -time.Minute
+	expired, _, _ := token.NewJWTService(otherCfg, keys).GenerateAccessToken(port.AccessTokenClaims{UserID: "u"})
+
+	// A token signed with HS256 using the public key as the secret must
+	// not pass as one signed with that key.
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "u", "iss": "test-issuer", "aud": "test-audience", "exp": time.Now().Add(time.Minute).Unix()})
+	hmac.Header["kid"] = keys[0].ID
+	confused, _ := hmac.SignedString([]byte(keys[0].ID))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "garbage", token: "not-a-jwt"},
+		{name: "unknown key", token: unknownKey},
+		{name: "algorithm mismatch", token: confused},
+		{name: "wrong audience", token: wrongAudience},
+		{name: "expired", token: expired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.ParseAccessToken(tt.token); err != token.ErrInvalidToken {
+				t.Errorf("ParseAccessToken() expected error %v, got %v", token.ErrInvalidToken, err)
+			}
+		})
+	}
+}
+
+// Every token signed with the service's keys verifies against them, so the
+// ID and logout tokens clients receive must not pass as access tokens.
+func TestJWTService_RejectsOtherTokenTypes(t *testing.T) {
+	key := generateKey(t, entity.SigningAlgES256)
+	cfg := testConfig()
+	svc := token.NewJWTService(cfg, staticKeys{key})
+	signer := token.NewIDTokenSigner(staticKeys{key}, cfg.Issuer, time.Hour)
+
+	idToken, err := signer.SignIDToken(port.IDTokenClaims{Subject: "u", Audience: cfg.Audience, AccessToken: "access-token"})
+	if err != nil {
+		t.Fatalf("SignIDToken() unexpected error: %v", err)
+	}
+	logoutToken, err := signer.SignLogoutToken(port.LogoutTokenClaims{Subject: "u", Audience: cfg.Audience})
+	if err != nil {
+		t.Fatalf("SignLogoutToken() unexpected error: %v", err)
+	}
+
+	handMade := func(typ, jti string) string {
+		tok := jwt.NewWithClaims(key.Method, jwt.MapClaims{
+			"sub": "u", "client_id": "client", "jti": jti,
+			"iss": cfg.Issuer, "aud": cfg.Audience, "exp": time.Now().Add(time.Minute).Unix(),
+		})
+		tok.Header["kid"] = key.ID
+		if typ != "" {
+			tok.Header["typ"] = typ
+		}
+		signed, err := tok.SignedString(key.Private)
+		if err != nil {
+			t.Fatalf("SignedString() unexpected error: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "id token", token: idToken},
+		{name: "logout token", token: logoutToken},
+		{name: "no typ", token: handMade("", "jti-1")},
+		{name: "plain jwt", token: handMade("JWT", "jti-1")},
+		{name: "no jti", token: handMade("at+jwt", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.ParseAccessToken(tt.token); err != token.ErrInvalidToken {
+				t.Errorf("ParseAccessToken() expected error %v, got %v", token.ErrInvalidToken, err)
+			}
+			if _, err := svc.InspectAccessToken(tt.token); err != token.ErrInvalidToken {
+				t.Errorf("InspectAccessToken() expected error %v, got %v", token.ErrInvalidToken, err)
+			}
+		})
+	}
+
+	if _, err := svc.InspectAccessToken(handMade("application/AT+JWT", "jti-1")); err != nil {
+		t.Errorf("InspectAccessToken() should accept the media type form of the typ header: %v", err)
+	}
+}
+
+func TestJWTService_InspectRequiresClient(t *testing.T) {
+	svc := token.NewJWTService(testConfig(), staticKeys{generateKey(t, entity.SigningAlgES256)})
+
+	signed, _, err := svc.GenerateAccessToken(port.AccessTokenClaims{UserID: "u", Username: "testuser"})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(signed, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() unexpected error: %v", err)
+	}
+	if parsed.Header["typ"] != "at+jwt" {
+		t.Errorf("typ = %v, want at+jwt", parsed.Header["typ"])
+	}
+
+	if _, err := svc.ParseAccessToken(signed); err != nil {
+		t.Errorf("ParseAccessToken() unexpected error for a first-party token: %v", err)
+	}
+	if _, err := svc.InspectAccessToken(signed); err != token.ErrInvalidToken {
+		t.Errorf("InspectAccessToken() expected error %v for a token without a client, got %v", token.ErrInvalidToken, err)
+	}
+}
+
+func TestJWTService_VerifiesWithRetiredKey(t *testing.T) {
+	oldKey := generateKey(t, entity.SigningAlgRS256)
+	newKey := generateKey(t, entity.SigningAlgEdDSA)
+
+	signed, _, err := token.NewJWTService(testConfig(), staticKeys{oldKey}).GenerateAccessToken(port.AccessTokenClaims{UserID: "u"})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	rotated := token.NewJWTService(testConfig(), staticKeys{newKey, oldKey})
+	if _, err := rotated.ParseAccessToken(signed); err != nil {
+		t.Errorf("ParseAccessToken() should accept a token signed with a still published key: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/keystore"
+)
+
+// staticKeys signs with the first key and verifies with any of them.
+type staticKeys []*keystore.Key
+
+func (k staticKeys) SigningKey() (*keystore.Key, error) {
+	return k[0], nil
+}
+
+func (k staticKeys) VerificationKey(kid string) (*keystore.Key, bool) {
+	for _, key := range k {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func generateKey(t *testing.T, algorithm string) *keystore.Key {
+	t.Helper()
+	key, err := keystore.GenerateKey(algorithm)
+	if err != nil {
+		t.Fatalf("GenerateKey(%s) unexpected error: %v", algorithm, err)
+	}
+	return key
+}
@@ -0,0 +1,40 @@
+package token_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/domain/entity"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+func TestRemoteKeySet_SkipsUnusableKeys(t *testing.T) {
+	key := generateKey(t, entity.SigningAlgES256)
+	encryption := generateKey(t, entity.SigningAlgRS256).JWK()
+	encryption.Use = "enc"
+	unknown := port.JSONWebKey{KeyType: "oct", KeyID: "hmac"}
+
+	set, err := token.ParseRemoteKeySet([]byte(keySet(t, encryption, unknown, key.JWK())))
+	if err != nil {
+		t.Fatalf("ParseRemoteKeySet() unexpected error: %v", err)
+	}
+	if !set.Has(key.ID) || set.Has(encryption.KeyID) || set.Has("hmac") {
+		t.Error("the set should hold only the signing key")
+	}
+
+	var claims jwt.RegisteredClaims
+	if _, err := jwt.ParseWithClaims(signAssertion(t, key, key.ID, assertionClaims()), &claims, set.Keyfunc()); err != nil {
+		t.Errorf("a token signed with the key should verify: %v", err)
+	}
+}
+
+func TestRemoteKeySet_NoUsableKeys(t *testing.T) {
+	for _, jwks := range []string{`{"keys":[]}`, `{"keys":[{"kty":"oct"}]}`, `not json`} {
+		if _, err := token.ParseRemoteKeySet([]byte(jwks)); !errors.Is(err, token.ErrInvalidKeySet) {
+			t.Errorf("ParseRemoteKeySet(%s) expected error %v, got %v", jwks, token.ErrInvalidKeySet, err)
+		}
+	}
+}
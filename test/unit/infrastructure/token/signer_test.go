@@ -0,0 +1,54 @@
+package token_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/token"
+)
+
+func TestHMACSigner_SignAndVerify(t *testing.T) {
+	signer := token.NewHMACSigner([]byte("test-secret-that-is-at-least-32-bytes"))
+
+	signed := signer.Sign("0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f")
+	payload, err := signer.Verify(signed)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if payload != "0190a5b0-7e1c-7b3d-8f4e-9a1b2c3d4e5f" {
+		t.Errorf("Verify() = %q, want the signed payload", payload)
+	}
+}
+
+func TestHMACSigner_Rejects(t *testing.T) {
+	signer := token.NewHMACSigner([]byte("test-secret-that-is-at-least-32-bytes"))
+	signed := signer.Sign("payload")
+
+	// Flip a character inside the tag; the last one carries padding bits.
+	tampered := []byte(signed)
+	i := len(tampered) - 10
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"unsigned", "payload"},
+		{"altered payload", "pay1oad" + signed[len("payload"):]},
+		{"altered tag", string(tampered)},
+		{"other key", token.NewHMACSigner([]byte("another-secret-that-is-32-bytes-long")).Sign("payload")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Verify(tt.token); !errors.Is(err, token.ErrInvalidToken) {
+				t.Errorf("Verify() expected error %v, got %v", token.ErrInvalidToken, err)
+			}
+		})
+	}
+}
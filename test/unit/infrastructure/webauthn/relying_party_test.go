@@ -0,0 +1,234 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webauthn"
+	"github.com/thanhnamdk2710/auth-service/test/support/softauthn"
+)
+
+const (
+	rpID   = "example.com"
+	origin = "https://login.example.com"
+)
+
+func newRelyingParty() *webauthn.RelyingParty {
+	return webauthn.NewRelyingParty(rpID, "Example", []string{origin})
+}
+
+func mustChallenge(t *testing.T, rp *webauthn.RelyingParty) []byte {
+	t.Helper()
+	challenge, err := rp.NewChallenge()
+	if err != nil {
+		t.Fatalf("NewChallenge() unexpected error: %v", err)
+	}
+	return challenge
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{"none attestation", softauthn.FormatNone},
+		{"packed self attestation", softauthn.FormatPacked},
+		{"packed attestation with certificate", softauthn.FormatPackedX5C},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newRelyingParty()
+			authenticator := softauthn.New(rpID, origin)
+			challenge := mustChallenge(t, rp)
+
+			reg, err := authenticator.Register(challenge, []byte("user-1"), tt.format)
+			if err != nil {
+				t.Fatalf("Register() unexpected error: %v", err)
+			}
+
+			got, err := rp.VerifyRegistration(challenge, reg.ClientDataJSON, reg.AttestationObject)
+			if err != nil {
+				t.Fatalf("VerifyRegistration() unexpected error: %v", err)
+			}
+			if !bytes.Equal(got.CredentialID, reg.CredentialID) {
+				t.Errorf("CredentialID = %x, want %x", got.CredentialID, reg.CredentialID)
+			}
+			if got.Algorithm != webauthn.AlgES256 {
+				t.Errorf("Algorithm = %d, want %d", got.Algorithm, webauthn.AlgES256)
+			}
+			if !bytes.Equal(got.AAGUID, softauthn.AAGUID) {
+				t.Errorf("AAGUID = %x, want %x", got.AAGUID, softauthn.AAGUID)
+			}
+			if !got.UserVerified || !got.BackupEligible {
+				t.Errorf("expected UV and BE flags, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistration_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(a *softauthn.Authenticator)
+		challenge func(issued []byte) []byte
+		wantErr   error
+	}{
+		{
+			name:      "other origin",
+			configure: func(a *softauthn.Authenticator) { a.Origin = "https://evil.example.net" },
+			wantErr:   webauthn.ErrInvalidClientData,
+		},
+		{
+			name:      "other relying party",
+			configure: func(a *softauthn.Authenticator) { a.RPID = "evil.example.net" },
+			wantErr:   webauthn.ErrRPIDMismatch,
+		},
+		{
+			name:      "other challenge",
+			challenge: func(issued []byte) []byte { return append([]byte{0}, issued[1:]...) },
+			wantErr:   webauthn.ErrInvalidClientData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newRelyingParty()
+			authenticator := softauthn.New(rpID, origin)
+			if tt.configure != nil {
+				tt.configure(authenticator)
+			}
+			issued := mustChallenge(t, rp)
+			answered := issued
+			if tt.challenge != nil {
+				answered = tt.challenge(issued)
+			}
+
+			reg, err := authenticator.Register(answered, []byte("user-1"), softauthn.FormatNone)
+			if err != nil {
+				t.Fatalf("Register() unexpected error: %v", err)
+			}
+
+			_, err = rp.VerifyRegistration(issued, reg.ClientDataJSON, reg.AttestationObject)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyRegistration() expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistration_TamperedAttestation(t *testing.T) {
+	rp := newRelyingParty()
+	authenticator := softauthn.New(rpID, origin)
+	challenge := mustChallenge(t, rp)
+
+	reg, err := authenticator.Register(challenge, []byte("user-1"), softauthn.FormatPacked)
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+
+	// Corrupt the DER signature inside the attestation statement.
+	tampered := bytes.Clone(reg.AttestationObject)
+	idx := bytes.Index(tampered, []byte("sig")) + 6
+	tampered[idx] ^= 0x01
+
+	if _, err := rp.VerifyRegistration(challenge, reg.ClientDataJSON, tampered); err == nil {
+		t.Error("VerifyRegistration() expected an error for a tampered attestation signature")
+	}
+}
+
+func registered(t *testing.T, rp *webauthn.RelyingParty, authenticator *softauthn.Authenticator) ([]byte, []byte) {
+	t.Helper()
+	challenge := mustChallenge(t, rp)
+	reg, err := authenticator.Register(challenge, []byte("user-1"), softauthn.FormatNone)
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+	got, err := rp.VerifyRegistration(challenge, reg.ClientDataJSON, reg.AttestationObject)
+	if err != nil {
+		t.Fatalf("VerifyRegistration() unexpected error: %v", err)
+	}
+	return got.CredentialID, got.PublicKey
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := newRelyingParty()
+	authenticator := softauthn.New(rpID, origin)
+	credentialID, publicKey := registered(t, rp, authenticator)
+
+	challenge := mustChallenge(t, rp)
+	assertion, err := authenticator.Assert(credentialID, challenge)
+	if err != nil {
+		t.Fatalf("Assert() unexpected error: %v", err)
+	}
+
+	got, err := rp.VerifyAssertion(challenge, assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, publicKey, true)
+	if err != nil {
+		t.Fatalf("VerifyAssertion() unexpected error: %v", err)
+	}
+	if got.SignCount != 2 {
+		t.Errorf("SignCount = %d, want 2", got.SignCount)
+	}
+	if !got.UserVerified {
+		t.Error("expected the UV flag")
+	}
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	rp := newRelyingParty()
+	authenticator := softauthn.New(rpID, origin)
+	credentialID, publicKey := registered(t, rp, authenticator)
+
+	t.Run("bad signature", func(t *testing.T) {
+		challenge := mustChallenge(t, rp)
+		assertion, _ := authenticator.Assert(credentialID, challenge)
+		assertion.AuthenticatorData[len(assertion.AuthenticatorData)-1] ^= 0x01
+
+		_, err := rp.VerifyAssertion(challenge, assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, publicKey, false)
+		if !errors.Is(err, webauthn.ErrInvalidSignature) {
+			t.Errorf("VerifyAssertion() expected error %v, got %v", webauthn.ErrInvalidSignature, err)
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, otherKey := registered(t, rp, softauthn.New(rpID, origin))
+		challenge := mustChallenge(t, rp)
+		assertion, _ := authenticator.Assert(credentialID, challenge)
+
+		_, err := rp.VerifyAssertion(challenge, assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, otherKey, false)
+		if !errors.Is(err, webauthn.ErrInvalidSignature) {
+			t.Errorf("VerifyAssertion() expected error %v, got %v", webauthn.ErrInvalidSignature, err)
+		}
+	})
+
+	t.Run("registration response replayed", func(t *testing.T) {
+		challenge := mustChallenge(t, rp)
+		reg, _ := softauthn.New(rpID, origin).Register(challenge, []byte("user-1"), softauthn.FormatNone)
+
+		_, err := rp.VerifyAssertion(challenge, reg.ClientDataJSON, nil, nil, publicKey, false)
+		if !errors.Is(err, webauthn.ErrInvalidClientData) {
+			t.Errorf("VerifyAssertion() expected error %v, got %v", webauthn.ErrInvalidClientData, err)
+		}
+	})
+
+	t.Run("user verification required", func(t *testing.T) {
+		authenticator.UserVerified = false
+		defer func() { authenticator.UserVerified = true }()
+
+		challenge := mustChallenge(t, rp)
+		assertion, _ := authenticator.Assert(credentialID, challenge)
+
+		_, err := rp.VerifyAssertion(challenge, assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, publicKey, true)
+		if !errors.Is(err, webauthn.ErrUserNotVerified) {
+			t.Errorf("VerifyAssertion() expected error %v, got %v", webauthn.ErrUserNotVerified, err)
+		}
+
+		// Presence alone is enough when verification is not required.
+		challenge = mustChallenge(t, rp)
+		assertion, _ = authenticator.Assert(credentialID, challenge)
+		if _, err := rp.VerifyAssertion(challenge, assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, publicKey, false); err != nil {
+			t.Errorf("VerifyAssertion() unexpected error: %v", err)
+		}
+	})
+}
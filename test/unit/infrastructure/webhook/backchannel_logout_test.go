@@ -0,0 +1,73 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/event"
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webhook"
+)
+
+// recordingLogoutSigner stands in for the ID token signer, encoding the
+// claims it is asked for into the token.
+type recordingLogoutSigner struct{}
+
+func (recordingLogoutSigner) SignLogoutToken(claims port.LogoutTokenClaims) (string, error) {
+	return claims.Audience + "/" + claims.Subject + "/" + claims.SessionID, nil
+}
+
+func backchannelDelivery(t *testing.T, uri string) port.OutboxDelivery {
+	t.Helper()
+	payload, err := json.Marshal(event.BackchannelLogoutEvent{
+		ClientID:   "client",
+		UserID:     "u1",
+		SessionID:  "s1",
+		LogoutURI:  uri,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return port.OutboxDelivery{EventType: event.OIDCBackchannelLogout, DedupKey: "s1", Payload: payload, Attempt: 1}
+}
+
+func TestBackchannelLogoutNotifier_PostsLogoutToken(t *testing.T) {
+	var logoutToken, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		logoutToken = r.PostFormValue("logout_token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewBackchannelLogoutNotifier(recordingLogoutSigner{}, time.Second)
+	if err := notifier.Handle(context.Background(), backchannelDelivery(t, server.URL)); err != nil {
+		t.Fatalf("Handle() unexpected error: %v", err)
+	}
+	if contentType != "application/x-www-form-urlencoded" || logoutToken != "client/u1/s1" {
+		t.Errorf("posted %q as %q, want the logout token for the session as a form", logoutToken, contentType)
+	}
+}
+
+func TestBackchannelLogoutNotifier_FailuresAreRetried(t *testing.T) {
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://elsewhere.example.com/", http.StatusFound)
+	}))
+	defer redirect.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	notifier := webhook.NewBackchannelLogoutNotifier(recordingLogoutSigner{}, time.Second)
+	for _, uri := range []string{redirect.URL, failing.URL} {
+		if err := notifier.Handle(context.Background(), backchannelDelivery(t, uri)); err == nil {
+			t.Errorf("Handle() expected error for %s", uri)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thanhnamdk2710/auth-service/internal/application/port"
+	"github.com/thanhnamdk2710/auth-service/internal/infrastructure/webhook"
+)
+
+func TestNotifier_SignsRequest(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier(server.URL, "secret", time.Second)
+	err := notifier.Handle(context.Background(), port.OutboxDelivery{
+		ID:        "outbox-1",
+		EventType: "user.registered",
+		DedupKey:  "user.registered:u1:" + notifier.Name(),
+		Payload:   json.RawMessage(`{"user_id":"u1"}`),
+		Attempt:   1,
+	})
+	if err != nil {
+		t.Fatalf("Handle() unexpected error: %v", err)
+	}
+
+	want := webhook.Sign([]byte("secret"), headers.Get(webhook.HeaderTimestamp), body)
+	if got := headers.Get(webhook.HeaderSignature); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := headers.Get(webhook.HeaderIdempotencyKey); got != "user.registered:u1:"+notifier.Name() {
+		t.Errorf("idempotency key = %q", got)
+	}
+
+	var envelope struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if envelope.Type != "user.registered" || string(envelope.Data) != `{"user_id":"u1"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+func TestNotifier_NonSuccessStatusFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier(server.URL, "secret", time.Second)
+	err := notifier.Handle(context.Background(), port.OutboxDelivery{
+		EventType: "user.registered",
+		DedupKey:  "user.registered:u1",
+		Payload:   json.RawMessage(`{}`),
+	})
+	if err == nil {
+		t.Error("Handle() expected error for 503 response")
+	}
+}